@@ -91,6 +91,14 @@ func generateMetrics(ymlDir string, thisDir string, md metadata) error {
 					}
 					return false
 				},
+				"histogramsPresent": func(metrics map[metricName]metric) bool {
+					for _, m := range metrics {
+						if m.Histogram != nil {
+							return true
+						}
+					}
+					return false
+				},
 			}).ParseFiles(filepath.Join(thisDir, tmplFile)))
 	buf := bytes.Buffer{}
 
@@ -46,6 +46,17 @@ metrics:
       value_type: double
     attributes: [host, cpu_type]
 `
+
+	histogramMetadata = `
+name: metricreceiver
+metrics:
+  system.cpu.latency:
+    enabled: true
+    description: Distribution of the CPU scheduling latency.
+    unit: s
+    histogram:
+      aggregation: cumulative
+`
 )
 
 func Test_runContents(t *testing.T) {
@@ -65,6 +76,12 @@ func Test_runContents(t *testing.T) {
 			expectedDocumentation: "testdata/documentation.md",
 			want:                  "",
 		},
+		{
+			name:                  "histogram metadata",
+			args:                  args{histogramMetadata},
+			expectedDocumentation: "testdata/histogram_documentation.md",
+			want:                  "",
+		},
 		{
 			name:    "invalid yaml",
 			args:    args{"invalid"},
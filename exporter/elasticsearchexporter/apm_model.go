@@ -0,0 +1,199 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/objmodel"
+)
+
+// Values of the processor.event field of the Elastic APM documents.
+const (
+	apmTransactionEvent = "transaction"
+	apmSpanEvent        = "span"
+)
+
+// apmModel encodes the spans as Elastic APM transaction and span documents, so that the Kibana
+// APM app works with the traces exported directly by the collector. The root spans and the server
+// and consumer spans are encoded as transactions, the other spans as spans of their parent.
+//
+// The logs are encoded by the encodeModel.
+type apmModel struct {
+	encodeModel
+}
+
+func (m *apmModel) encodeSpan(resource pcommon.Resource, span ptrace.Span) ([]byte, error) {
+	var document objmodel.Document
+	document.AddTimestamp("@timestamp", span.StartTimestamp())
+	document.AddInt("timestamp.us", int64(span.StartTimestamp())/1000)
+	document.AddString("agent.name", "opentelemetry")
+	document.AddTraceID("trace.id", span.TraceID())
+	document.AddSpanID("parent.id", span.ParentSpanID())
+	document.AddString("event.outcome", apmOutcome(span.Status().Code()))
+	addAPMResource(&document, resource)
+
+	durationUs := int64(span.EndTimestamp()-span.StartTimestamp()) / 1000
+	attributes := span.Attributes()
+	if isAPMTransaction(span) {
+		document.AddString("processor.event", apmTransactionEvent)
+		document.AddString("processor.name", apmTransactionEvent)
+		document.AddSpanID("transaction.id", span.SpanID())
+		document.AddString("transaction.name", span.Name())
+		document.AddString("transaction.type", apmTransactionType(span))
+		document.AddString("transaction.result", apmTransactionResult(span))
+		document.AddInt("transaction.duration.us", durationUs)
+		document.Add("transaction.sampled", objmodel.BoolValue(true))
+	} else {
+		spanType, spanSubtype := apmSpanType(attributes)
+		document.AddString("processor.event", apmSpanEvent)
+		document.AddString("processor.name", apmTransactionEvent)
+		document.AddSpanID("span.id", span.SpanID())
+		document.AddString("span.name", span.Name())
+		document.AddString("span.type", spanType)
+		document.AddString("span.subtype", spanSubtype)
+		document.AddString("span.kind", strings.ToUpper(span.Kind().String()))
+		document.AddInt("span.duration.us", durationUs)
+		if statement, ok := attributes.Get(conventions.AttributeDBStatement); ok {
+			document.AddString("span.db.statement", statement.AsString())
+		}
+	}
+	addAPMLabels(&document, attributes)
+
+	document.Dedup()
+	if m.redactor != nil {
+		document.Redact(m.redactor)
+	}
+
+	var buf bytes.Buffer
+	err := document.Serialize(&buf, true)
+	return buf.Bytes(), err
+}
+
+// isAPMTransaction reports whether the span is the entry point of a service.
+func isAPMTransaction(span ptrace.Span) bool {
+	switch span.Kind() {
+	case ptrace.SpanKindServer, ptrace.SpanKindConsumer:
+		return true
+	default:
+		return span.ParentSpanID().IsEmpty()
+	}
+}
+
+func apmOutcome(code ptrace.StatusCode) string {
+	switch code {
+	case ptrace.StatusCodeOk:
+		return "success"
+	case ptrace.StatusCodeError:
+		return "failure"
+	default:
+		return "unknown"
+	}
+}
+
+func apmTransactionType(span ptrace.Span) string {
+	attributes := span.Attributes()
+	if _, ok := attributes.Get(conventions.AttributeMessagingSystem); ok || span.Kind() == ptrace.SpanKindConsumer {
+		return "messaging"
+	}
+	if _, ok := attributes.Get(conventions.AttributeHTTPMethod); ok {
+		return "request"
+	}
+	if _, ok := attributes.Get(conventions.AttributeRPCSystem); ok {
+		return "request"
+	}
+	return "unknown"
+}
+
+// apmTransactionResult returns the class of the http status code of the transaction, if any,
+// or the result of its status otherwise.
+func apmTransactionResult(span ptrace.Span) string {
+	if status, ok := span.Attributes().Get(conventions.AttributeHTTPStatusCode); ok && status.Type() == pcommon.ValueTypeInt {
+		return fmt.Sprintf("HTTP %dxx", status.Int()/100)
+	}
+	switch span.Status().Code() {
+	case ptrace.StatusCodeOk:
+		return "Success"
+	case ptrace.StatusCodeError:
+		return "Error"
+	default:
+		return ""
+	}
+}
+
+// apmSpanType returns the type and subtype of a span from the semantic conventions it follows.
+func apmSpanType(attributes pcommon.Map) (string, string) {
+	if system, ok := attributes.Get(conventions.AttributeDBSystem); ok {
+		return "db", system.AsString()
+	}
+	if system, ok := attributes.Get(conventions.AttributeMessagingSystem); ok {
+		return "messaging", system.AsString()
+	}
+	if _, ok := attributes.Get(conventions.AttributeHTTPMethod); ok {
+		return "external", "http"
+	}
+	if system, ok := attributes.Get(conventions.AttributeRPCSystem); ok {
+		return "external", system.AsString()
+	}
+	return "app", ""
+}
+
+// addAPMResource adds the service, host and the other resource attributes, as labels, to the
+// document.
+func addAPMResource(document *objmodel.Document, resource pcommon.Resource) {
+	labels := pcommon.NewMap()
+	resource.Attributes().Range(func(k string, v pcommon.Value) bool {
+		switch k {
+		case conventions.AttributeServiceName:
+			document.AddString("service.name", v.AsString())
+		case conventions.AttributeServiceVersion:
+			document.AddString("service.version", v.AsString())
+		case conventions.AttributeDeploymentEnvironment:
+			document.AddString("service.environment", v.AsString())
+		case conventions.AttributeTelemetrySDKLanguage:
+			document.AddString("service.language.name", v.AsString())
+		case conventions.AttributeHostName:
+			document.AddString("host.hostname", v.AsString())
+		default:
+			v.CopyTo(labels.PutEmpty(k))
+		}
+		return true
+	})
+	addAPMLabels(document, labels)
+}
+
+// addAPMLabels adds the attributes as labels, the dots of their keys being replaced by
+// underscores as Elastic APM does. The numbers are added to the numeric labels.
+func addAPMLabels(document *objmodel.Document, attributes pcommon.Map) {
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		key := strings.ReplaceAll(k, ".", "_")
+		switch v.Type() {
+		case pcommon.ValueTypeEmpty:
+		case pcommon.ValueTypeInt, pcommon.ValueTypeDouble:
+			document.AddAttribute("numeric_labels."+key, v)
+		case pcommon.ValueTypeBool, pcommon.ValueTypeStr:
+			document.AddAttribute("labels."+key, v)
+		default:
+			document.AddString("labels."+key, v.AsString())
+		}
+		return true
+	})
+}
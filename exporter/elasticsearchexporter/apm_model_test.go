@@ -0,0 +1,120 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+func encodeAPMSpan(t *testing.T, span ptrace.Span) map[string]interface{} {
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "checkout")
+	resource.Attributes().PutStr("deployment.environment", "production")
+	resource.Attributes().PutStr("k8s.pod.name", "checkout-1")
+
+	model := &apmModel{encodeModel: encodeModel{dedup: true}}
+	encoded, err := model.encodeSpan(resource, span)
+	require.NoError(t, err)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &doc))
+	return doc
+}
+
+func newAPMSpan(kind ptrace.SpanKind, parentID pcommon.SpanID) ptrace.Span {
+	start := time.Unix(1000, 0)
+	span := ptrace.NewSpan()
+	span.SetTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	span.SetSpanID([8]byte{1, 1, 1, 1, 1, 1, 1, 1})
+	span.SetParentSpanID(parentID)
+	span.SetKind(kind)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(250 * time.Millisecond)))
+	return span
+}
+
+func TestAPMModelTransaction(t *testing.T) {
+	span := newAPMSpan(ptrace.SpanKindServer, [8]byte{2, 2, 2, 2, 2, 2, 2, 2})
+	span.SetName("GET /cart")
+	span.Attributes().PutStr("http.method", "GET")
+	span.Attributes().PutInt("http.status_code", 404)
+
+	doc := encodeAPMSpan(t, span)
+	assert.Equal(t, map[string]interface{}{"event": "transaction", "name": "transaction"}, doc["processor"])
+	assert.Equal(t, map[string]interface{}{
+		"id":       "0101010101010101",
+		"name":     "GET /cart",
+		"type":     "request",
+		"result":   "HTTP 4xx",
+		"duration": map[string]interface{}{"us": float64(250000)},
+		"sampled":  true,
+	}, doc["transaction"])
+	assert.Equal(t, map[string]interface{}{"id": "0102030405060708090a0b0c0d0e0f10"}, doc["trace"])
+	assert.Equal(t, map[string]interface{}{"id": "0202020202020202"}, doc["parent"])
+	assert.Equal(t, map[string]interface{}{"us": float64(1000000000)}, doc["timestamp"])
+	assert.Equal(t, map[string]interface{}{"name": "checkout", "environment": "production"}, doc["service"])
+	assert.Equal(t, map[string]interface{}{"outcome": "unknown"}, doc["event"])
+	assert.Equal(t, map[string]interface{}{"http_method": "GET", "k8s_pod_name": "checkout-1"}, doc["labels"])
+	assert.Equal(t, map[string]interface{}{"http_status_code": float64(404)}, doc["numeric_labels"])
+	assert.NotContains(t, doc, "span")
+}
+
+func TestAPMModelSpan(t *testing.T) {
+	span := newAPMSpan(ptrace.SpanKindClient, [8]byte{2, 2, 2, 2, 2, 2, 2, 2})
+	span.SetName("SELECT carts")
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Attributes().PutStr("db.system", "postgresql")
+	span.Attributes().PutStr("db.statement", "SELECT * FROM carts")
+
+	doc := encodeAPMSpan(t, span)
+	assert.Equal(t, map[string]interface{}{"event": "span", "name": "transaction"}, doc["processor"])
+	assert.Equal(t, map[string]interface{}{
+		"id":       "0101010101010101",
+		"name":     "SELECT carts",
+		"type":     "db",
+		"subtype":  "postgresql",
+		"kind":     "CLIENT",
+		"duration": map[string]interface{}{"us": float64(250000)},
+		"db":       map[string]interface{}{"statement": "SELECT * FROM carts"},
+	}, doc["span"])
+	assert.Equal(t, map[string]interface{}{"id": "0202020202020202"}, doc["parent"])
+	assert.Equal(t, map[string]interface{}{"outcome": "failure"}, doc["event"])
+	assert.NotContains(t, doc, "transaction")
+}
+
+func TestAPMModelRootSpanIsTransaction(t *testing.T) {
+	doc := encodeAPMSpan(t, newAPMSpan(ptrace.SpanKindInternal, pcommon.NewSpanIDEmpty()))
+	assert.Equal(t, "transaction", doc["processor"].(map[string]interface{})["event"])
+	assert.Equal(t, "unknown", doc["transaction"].(map[string]interface{})["type"])
+	assert.NotContains(t, doc, "parent")
+}
+
+func TestAPMMappingMode(t *testing.T) {
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.Endpoints = []string{"http://localhost:9200"}
+		cfg.Mapping.Mode = "apm"
+	})
+	require.NoError(t, cfg.Validate())
+	exporter, err := newTracesExporter(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	assert.IsType(t, &apmModel{}, exporter.model)
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -69,6 +70,8 @@ type Config struct {
 	Retry              RetrySettings     `mapstructure:"retry"`
 	Flush              FlushSettings     `mapstructure:"flush"`
 	Mapping            MappingsSettings  `mapstructure:"mapping"`
+	Rollover           RolloverSettings  `mapstructure:"rollover"`
+	Redaction          RedactionSettings `mapstructure:"redaction"`
 }
 
 type HTTPClientSettings struct {
@@ -150,6 +153,45 @@ type RetrySettings struct {
 	MaxInterval time.Duration `mapstructure:"max_interval"`
 }
 
+// RolloverSettings defines settings to bootstrap the write alias of the logs and traces
+// indices and to trigger their rollover, for clusters without index lifecycle management.
+//
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-rollover-index.html
+type RolloverSettings struct {
+	// Enabled creates the write alias named after the index if it doesn't exist, and
+	// rolls it over to a new index when one of the conditions is met.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Interval configures how often the rollover conditions are checked.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// MaxAge rolls the alias over once its write index is older than this duration.
+	MaxAge time.Duration `mapstructure:"max_age"`
+
+	// MaxPrimaryShardSize rolls the alias over once the largest primary shard of its
+	// write index reaches this size, e.g. 50gb.
+	MaxPrimaryShardSize string `mapstructure:"max_primary_shard_size"`
+
+	// MaxDocs rolls the alias over once its write index holds this number of documents.
+	MaxDocs int64 `mapstructure:"max_docs"`
+}
+
+// RedactionSettings defines the sensitive values scrubbed from the documents when they are
+// encoded, so that they don't reach Elasticsearch even if the processors earlier in the
+// pipeline missed them.
+type RedactionSettings struct {
+	// Keys are the attribute keys whose values are redacted. A key matches the flattened
+	// key of a field, e.g. `user.email`, or its last segments.
+	Keys []string `mapstructure:"keys"`
+
+	// Patterns are regular expressions, the parts of the string values matching them are redacted.
+	Patterns []string `mapstructure:"patterns"`
+
+	// Method configures how the values are redacted: `mask` replaces them with `****`, `hash`
+	// with their SHA-256 hash so that they can still be correlated.
+	Method string `mapstructure:"method"`
+}
+
 type MappingsSettings struct {
 	// Mode configures the field mappings.
 	Mode string `mapstructure:"mode"`
@@ -172,11 +214,19 @@ type MappingMode int
 const (
 	MappingNone MappingMode = iota
 	MappingECS
+	MappingAPM
 )
 
 var (
 	errConfigNoEndpoint    = errors.New("endpoints or cloudid must be specified")
 	errConfigEmptyEndpoint = errors.New("endpoints must not include empty entries")
+	errConfigNoConditions  = errors.New("rollover requires at least one of max_age, max_primary_shard_size or max_docs")
+)
+
+// Enum values for the redaction method.
+const (
+	redactionMask = "mask"
+	redactionHash = "hash"
 )
 
 func (m MappingMode) String() string {
@@ -185,6 +235,8 @@ func (m MappingMode) String() string {
 		return ""
 	case MappingECS:
 		return "ecs"
+	case MappingAPM:
+		return "apm"
 	default:
 		return ""
 	}
@@ -195,6 +247,7 @@ var mappingModes = func() map[string]MappingMode {
 	for _, m := range []MappingMode{
 		MappingNone,
 		MappingECS,
+		MappingAPM,
 	} {
 		table[strings.ToLower(m.String())] = m
 	}
@@ -226,5 +279,25 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("unknown mapping mode %v", cfg.Mapping.Mode)
 	}
 
+	switch cfg.Redaction.Method {
+	case redactionMask, redactionHash:
+	default:
+		return fmt.Errorf("unknown redaction method %q", cfg.Redaction.Method)
+	}
+	for _, pattern := range cfg.Redaction.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+	}
+
+	if cfg.Rollover.Enabled {
+		if cfg.Rollover.Interval <= 0 {
+			return errors.New("rollover interval must be positive")
+		}
+		if len(cfg.Rollover.conditions()) == 0 {
+			return errConfigNoConditions
+		}
+	}
+
 	return nil
 }
@@ -72,6 +72,12 @@ func TestLoad_DeprecatedIndexConfigOption(t *testing.T) {
 			Dedup: true,
 			Dedot: true,
 		},
+		Rollover: RolloverSettings{
+			Interval: 5 * time.Minute,
+		},
+		Redaction: RedactionSettings{
+			Method: "mask",
+		},
 	})
 }
 
@@ -130,6 +136,12 @@ func TestLoadConfig(t *testing.T) {
 					Dedup: true,
 					Dedot: true,
 				},
+				Rollover: RolloverSettings{
+					Interval: 5 * time.Minute,
+				},
+				Redaction: RedactionSettings{
+					Method: "mask",
+				},
 			},
 		},
 		{
@@ -170,6 +182,17 @@ func TestLoadConfig(t *testing.T) {
 					Dedup: true,
 					Dedot: true,
 				},
+				Rollover: RolloverSettings{
+					Enabled:             true,
+					Interval:            time.Minute,
+					MaxAge:              24 * time.Hour,
+					MaxPrimaryShardSize: "50gb",
+				},
+				Redaction: RedactionSettings{
+					Keys:     []string{"user.email", "password"},
+					Patterns: []string{`\d{4}-\d{4}-\d{4}-\d{4}`},
+					Method:   "hash",
+				},
 			},
 		},
 	}
@@ -169,7 +169,7 @@ func newTransport(config *Config, tlsCfg *tls.Config) *http.Transport {
 	return transport
 }
 
-func newBulkIndexer(logger *zap.Logger, client *elasticsearch.Client, config *Config) (esBulkIndexerCurrent, error) {
+func newBulkIndexer(logger *zap.Logger, client *elasticsearch.Client, config *Config, metrics *bulkMetrics) (esBulkIndexerCurrent, error) {
 	// TODO: add debug logger
 	return esutil.NewBulkIndexer(esutil.BulkIndexerConfig{
 		NumWorkers:    config.NumWorkers,
@@ -182,6 +182,8 @@ func newBulkIndexer(logger *zap.Logger, client *elasticsearch.Client, config *Co
 		OnError: func(_ context.Context, err error) {
 			logger.Error(fmt.Sprintf("Bulk indexer error: %v", err))
 		},
+		OnFlushStart: metrics.onFlushStart,
+		OnFlushEnd:   metrics.onFlushEnd,
 	})
 }
 
@@ -217,13 +219,21 @@ func shouldRetryEvent(status int) bool {
 	return false
 }
 
-func pushDocuments(ctx context.Context, logger *zap.Logger, index string, document []byte, bulkIndexer esBulkIndexerCurrent, maxAttempts int) error {
+func pushDocuments(ctx context.Context, logger *zap.Logger, index string, document []byte, bulkIndexer esBulkIndexerCurrent, maxAttempts int, metrics *bulkMetrics) error {
 	attempts := 1
 	body := bytes.NewReader(document)
 	item := esBulkIndexerItem{Action: createAction, Index: index, Body: body}
+	item.OnSuccess = func(ctx context.Context, item esBulkIndexerItem, resp esBulkIndexerResponseItem) {
+		metrics.docSent(ctx, documentIndex(item, resp), len(document))
+		metrics.docIndexed(ctx, documentIndex(item, resp))
+	}
 	// Setup error handler. The handler handles the per item response status based on the
 	// selective ACKing in the bulk response.
 	item.OnFailure = func(ctx context.Context, item esBulkIndexerItem, resp esBulkIndexerResponseItem, err error) {
+		if resp.Status != 0 {
+			metrics.docSent(ctx, documentIndex(item, resp), len(document))
+		}
+
 		switch {
 		case attempts < maxAttempts && shouldRetryEvent(resp.Status):
 			logger.Debug("Retrying to index",
@@ -234,7 +244,10 @@ func pushDocuments(ctx context.Context, logger *zap.Logger, index string, docume
 
 			attempts++
 			_, _ = body.Seek(0, io.SeekStart)
-			_ = bulkIndexer.Add(ctx, item)
+			if err := bulkIndexer.Add(ctx, item); err != nil {
+				metrics.docFailed(ctx, documentIndex(item, resp), failureErrorType(resp))
+			}
+			return
 
 		case resp.Status == 0 && err != nil:
 			// Encoding error. We didn't even attempt to send the event
@@ -253,7 +266,13 @@ func pushDocuments(ctx context.Context, logger *zap.Logger, index string, docume
 				zap.Int("attempt", attempts),
 				zap.Int("status", resp.Status))
 		}
+		metrics.docFailed(ctx, documentIndex(item, resp), failureErrorType(resp))
 	}
 
-	return bulkIndexer.Add(ctx, item)
+	metrics.docAdded(ctx)
+	if err := bulkIndexer.Add(ctx, item); err != nil {
+		metrics.docFailed(ctx, index, "request_failed")
+		return err
+	}
+	return nil
 }
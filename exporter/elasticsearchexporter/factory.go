@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -63,6 +64,12 @@ func createDefaultConfig() component.ExporterConfig {
 			Dedup: true,
 			Dedot: true,
 		},
+		Rollover: RolloverSettings{
+			Interval: 5 * time.Minute,
+		},
+		Redaction: RedactionSettings{
+			Method: redactionMask,
+		},
 	}
 }
 
@@ -77,6 +84,9 @@ func createLogsExporter(
 	if cfg.(*Config).Index != "" {
 		set.Logger.Warn("index option are deprecated and replaced with logs_index and traces_index.")
 	}
+	if err := registerMetricViews(); err != nil {
+		return nil, err
+	}
 
 	exporter, err := newLogsExporter(set.Logger, cfg.(*Config))
 	if err != nil {
@@ -88,6 +98,7 @@ func createLogsExporter(
 		set,
 		cfg,
 		exporter.pushLogsData,
+		exporterhelper.WithStart(exporter.Start),
 		exporterhelper.WithShutdown(exporter.Shutdown),
 	)
 }
@@ -96,10 +107,23 @@ func createTracesExporter(ctx context.Context,
 	set component.ExporterCreateSettings,
 	cfg component.ExporterConfig) (component.TracesExporter, error) {
 
+	if err := registerMetricViews(); err != nil {
+		return nil, err
+	}
 	exporter, err := newTracesExporter(set.Logger, cfg.(*Config))
 	if err != nil {
 		return nil, fmt.Errorf("cannot configure Elasticsearch traces exporter: %w", err)
 	}
 	return exporterhelper.NewTracesExporter(ctx, set, cfg, exporter.pushTraceData,
+		exporterhelper.WithStart(exporter.Start),
 		exporterhelper.WithShutdown(exporter.Shutdown))
 }
+
+// registerMetricViews registers the bulk indexer views, registering them again
+// is a no-op.
+func registerMetricViews() error {
+	if err := view.Register(MetricViews()...); err != nil {
+		return fmt.Errorf("cannot register Elasticsearch exporter metric views: %w", err)
+	}
+	return nil
+}
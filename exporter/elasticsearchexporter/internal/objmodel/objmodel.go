@@ -47,6 +47,7 @@ import (
 	"io"
 	"math"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -237,6 +238,68 @@ func (doc *Document) Dedup() {
 	}
 }
 
+// Redactor scrubs the sensitive values of a document.
+type Redactor interface {
+	// SensitiveKey reports whether the whole value of the field with the flattened key is sensitive.
+	SensitiveKey(key string) bool
+	// Redact returns the redacted form of a sensitive value.
+	Redact(s string) string
+	// RedactString returns the string with its sensitive parts redacted.
+	RedactString(s string) string
+}
+
+// Redact scrubs the sensitive values of the document: the values of the sensitive keys
+// are replaced by their redacted form as strings, and the sensitive parts of all the
+// other strings are redacted.
+func (doc *Document) Redact(r Redactor) {
+	for i := range doc.fields {
+		fld := &doc.fields[i]
+		fld.value.redact(r, r.SensitiveKey(fld.key))
+	}
+}
+
+func (v *Value) redact(r Redactor, sensitive bool) {
+	switch v.kind {
+	case KindString:
+		if sensitive {
+			v.str = r.Redact(v.str)
+		} else {
+			v.str = r.RedactString(v.str)
+		}
+	case KindBool, KindInt, KindDouble, KindTimestamp:
+		if sensitive {
+			*v = StringValue(r.Redact(v.String()))
+		}
+	case KindObject:
+		for i := range v.doc.fields {
+			fld := &v.doc.fields[i]
+			fld.value.redact(r, sensitive || r.SensitiveKey(fld.key))
+		}
+	case KindArr:
+		for i := range v.arr {
+			v.arr[i].redact(r, sensitive)
+		}
+	}
+}
+
+// String returns the primitive value formatted as a string, empty for the arrays and objects.
+func (v *Value) String() string {
+	switch v.kind {
+	case KindBool:
+		return strconv.FormatBool(v.primitive == 1)
+	case KindInt:
+		return strconv.FormatInt(int64(v.primitive), 10)
+	case KindDouble:
+		return strconv.FormatFloat(v.dbl, 'g', -1, 64)
+	case KindString:
+		return v.str
+	case KindTimestamp:
+		return v.ts.UTC().Format(tsLayout)
+	default:
+		return ""
+	}
+}
+
 // Serialize writes the document to the given writer. The serializer will create nested objects if dedot is true.
 //
 // NOTE: The documented MUST be sorted if dedot is true.
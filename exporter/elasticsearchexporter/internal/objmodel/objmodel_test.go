@@ -339,6 +339,39 @@ func TestDocument_Serialize_Flat(t *testing.T) {
 	}
 }
 
+// upperRedactor uppercases the sensitive values and the "secret" parts of the strings.
+type upperRedactor struct{}
+
+func (upperRedactor) SensitiveKey(key string) bool { return strings.HasSuffix(key, "password") }
+func (upperRedactor) Redact(s string) string       { return strings.ToUpper(s) }
+func (upperRedactor) RedactString(s string) string {
+	return strings.ReplaceAll(s, "secret", "SECRET")
+}
+
+func TestDocument_Redact(t *testing.T) {
+	m := pcommon.NewMap()
+	m.FromRaw(map[string]interface{}{
+		"password": "pass",
+		"pin":      1234,
+		"note":     "my secret",
+		"user": map[string]interface{}{
+			"password": true,
+			"name":     "jane",
+		},
+		"list": []interface{}{
+			"secret",
+			map[string]interface{}{"password": 1.5, "other": "no secret"},
+		},
+	})
+	doc := DocumentFromAttributes(m)
+	doc.Dedup()
+	doc.Redact(upperRedactor{})
+
+	var buf strings.Builder
+	require.NoError(t, doc.Serialize(&buf, false))
+	assert.Equal(t, `{"list":["SECRET",{"other":"no SECRET","password":"1.5"}],"note":"my SECRET","password":"PASS","pin":1234,"user.name":"jane","user.password":"TRUE"}`, buf.String())
+}
+
 func TestDocument_Serialize_Dedot(t *testing.T) {
 	tests := map[string]struct {
 		attrs map[string]interface{}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/multierr"
@@ -34,7 +35,9 @@ type elasticsearchLogsExporter struct {
 
 	client      *esClientCurrent
 	bulkIndexer esBulkIndexerCurrent
+	metrics     *bulkMetrics
 	model       mappingModel
+	rollover    *indexRollover
 }
 
 var retryOnStatus = []int{500, 502, 503, 504, 429}
@@ -51,7 +54,13 @@ func newLogsExporter(logger *zap.Logger, cfg *Config) (*elasticsearchLogsExporte
 		return nil, err
 	}
 
-	bulkIndexer, err := newBulkIndexer(logger, client, cfg)
+	indexStr := cfg.LogsIndex
+	if cfg.Index != "" {
+		indexStr = cfg.Index
+	}
+
+	metrics := newBulkMetrics(indexStr)
+	bulkIndexer, err := newBulkIndexer(logger, client, cfg, metrics)
 	if err != nil {
 		return nil, err
 	}
@@ -62,24 +71,34 @@ func newLogsExporter(logger *zap.Logger, cfg *Config) (*elasticsearchLogsExporte
 	}
 
 	// TODO: Apply encoding and field mapping settings.
-	model := &encodeModel{dedup: true, dedot: false}
+	model := &encodeModel{dedup: true, dedot: false, redactor: newRedactor(cfg.Redaction)}
 
-	indexStr := cfg.LogsIndex
-	if cfg.Index != "" {
-		indexStr = cfg.Index
-	}
 	esLogsExp := &elasticsearchLogsExporter{
 		logger:      logger,
 		client:      client,
 		bulkIndexer: bulkIndexer,
+		metrics:     metrics,
 		index:       indexStr,
 		maxAttempts: maxAttempts,
 		model:       model,
 	}
+	if cfg.Rollover.Enabled {
+		esLogsExp.rollover = newIndexRollover(logger, client, indexStr, cfg.Rollover)
+	}
 	return esLogsExp, nil
 }
 
+func (e *elasticsearchLogsExporter) Start(ctx context.Context, _ component.Host) error {
+	if e.rollover != nil {
+		return e.rollover.start(ctx)
+	}
+	return nil
+}
+
 func (e *elasticsearchLogsExporter) Shutdown(ctx context.Context) error {
+	if e.rollover != nil {
+		e.rollover.shutdown()
+	}
 	return e.bulkIndexer.Close(ctx)
 }
 
@@ -113,5 +132,5 @@ func (e *elasticsearchLogsExporter) pushLogRecord(ctx context.Context, resource
 	if err != nil {
 		return fmt.Errorf("Failed to encode log event: %w", err)
 	}
-	return pushDocuments(ctx, e.logger, e.index, document, e.bulkIndexer, e.maxAttempts)
+	return pushDocuments(ctx, e.logger, e.index, document, e.bulkIndexer, e.maxAttempts, e.metrics)
 }
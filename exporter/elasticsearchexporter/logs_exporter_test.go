@@ -119,6 +119,13 @@ func TestExporter_New(t *testing.T) {
 			}),
 			want: success,
 		},
+		"fail if rollover is enabled without conditions": {
+			config: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoints = []string{"test:9200"}
+				cfg.Rollover.Enabled = true
+			}),
+			want: failWith(errConfigNoConditions),
+		},
 	}
 
 	for name, test := range tests {
@@ -348,6 +355,6 @@ func withTestExporterConfig(fns ...func(*Config)) func(string) *Config {
 }
 
 func mustSend(t *testing.T, exporter *elasticsearchLogsExporter, contents string) {
-	err := pushDocuments(context.TODO(), zap.L(), exporter.index, []byte(contents), exporter.bulkIndexer, exporter.maxAttempts)
+	err := pushDocuments(context.TODO(), zap.L(), exporter.index, []byte(contents), exporter.bulkIndexer, exporter.maxAttempts, exporter.metrics)
 	require.NoError(t, err)
 }
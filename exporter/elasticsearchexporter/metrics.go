@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/atomic"
+)
+
+var (
+	mBulkLatency  = stats.Int64("elasticsearch_bulk_latency", "Latency in ms of the bulk requests flushed to Elasticsearch", stats.UnitMilliseconds)
+	mDocsIndexed  = stats.Int64("elasticsearch_docs_indexed", "Number of documents successfully indexed", stats.UnitDimensionless)
+	mDocsFailed   = stats.Int64("elasticsearch_docs_failed", "Number of documents dropped because they could not be indexed", stats.UnitDimensionless)
+	mQueueDepth   = stats.Int64("elasticsearch_bulk_queue_depth", "Current number of documents waiting for a bulk response", stats.UnitDimensionless)
+	mBytesFlushed = stats.Int64("elasticsearch_bytes_flushed", "Number of document bytes sent in bulk requests", stats.UnitBytes)
+
+	indexTagKey     = tag.MustNewKey("index")
+	errorTypeTagKey = tag.MustNewKey("error_type")
+)
+
+// MetricViews return the metrics views for the bulk indexer.
+func MetricViews() []*view.View {
+	return metricViews
+}
+
+// metricViews are built once, the distribution and last value aggregations can't be
+// compared and a view can only be registered again when it is the very same view.
+var metricViews = []*view.View{
+	{
+		Name:        mBulkLatency.Name(),
+		Measure:     mBulkLatency,
+		Description: mBulkLatency.Description(),
+		TagKeys:     []tag.Key{indexTagKey},
+		Aggregation: view.Distribution(0, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000, 30000),
+	},
+	{
+		Name:        mDocsIndexed.Name(),
+		Measure:     mDocsIndexed,
+		Description: mDocsIndexed.Description(),
+		TagKeys:     []tag.Key{indexTagKey},
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        mDocsFailed.Name(),
+		Measure:     mDocsFailed,
+		Description: mDocsFailed.Description(),
+		TagKeys:     []tag.Key{indexTagKey, errorTypeTagKey},
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        mQueueDepth.Name(),
+		Measure:     mQueueDepth,
+		Description: mQueueDepth.Description(),
+		TagKeys:     []tag.Key{indexTagKey},
+		Aggregation: view.LastValue(),
+	},
+	{
+		Name:        mBytesFlushed.Name(),
+		Measure:     mBytesFlushed,
+		Description: mBytesFlushed.Description(),
+		TagKeys:     []tag.Key{indexTagKey},
+		Aggregation: view.Sum(),
+	},
+}
+
+type flushStartKey struct{}
+
+// bulkMetrics records the self-observability metrics of a single bulk indexer.
+// The flush latency and the queue depth are tagged with the index the indexer
+// writes to, the documents metrics with the index the document was written to,
+// which differs from the former behind an alias.
+type bulkMetrics struct {
+	mutators []tag.Mutator
+	pending  *atomic.Int64
+}
+
+func newBulkMetrics(index string) *bulkMetrics {
+	return &bulkMetrics{
+		mutators: []tag.Mutator{tag.Upsert(indexTagKey, index)},
+		pending:  atomic.NewInt64(0),
+	}
+}
+
+func (m *bulkMetrics) onFlushStart(ctx context.Context) context.Context {
+	return context.WithValue(ctx, flushStartKey{}, time.Now())
+}
+
+func (m *bulkMetrics) onFlushEnd(ctx context.Context) {
+	start, ok := ctx.Value(flushStartKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	_ = stats.RecordWithTags(ctx, m.mutators, mBulkLatency.M(time.Since(start).Milliseconds()))
+}
+
+// docAdded records a new document entering the bulk indexer buffer.
+func (m *bulkMetrics) docAdded(ctx context.Context) {
+	_ = stats.RecordWithTags(ctx, m.mutators, mQueueDepth.M(m.pending.Inc()))
+}
+
+// docSent records a document written to index that has been part of a bulk request.
+func (m *bulkMetrics) docSent(ctx context.Context, index string, size int) {
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(indexTagKey, index)}, mBytesFlushed.M(int64(size)))
+}
+
+// docIndexed records a document that has been accepted by Elasticsearch in index.
+func (m *bulkMetrics) docIndexed(ctx context.Context, index string) {
+	_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(indexTagKey, index)}, mDocsIndexed.M(1))
+	_ = stats.RecordWithTags(ctx, m.mutators, mQueueDepth.M(m.pending.Dec()))
+}
+
+// docFailed records a document to be written to index that has been dropped.
+func (m *bulkMetrics) docFailed(ctx context.Context, index string, errorType string) {
+	mutators := []tag.Mutator{tag.Upsert(indexTagKey, index), tag.Upsert(errorTypeTagKey, errorType)}
+	_ = stats.RecordWithTags(ctx, mutators, mDocsFailed.M(1))
+	_ = stats.RecordWithTags(ctx, m.mutators, mQueueDepth.M(m.pending.Dec()))
+}
+
+// documentIndex returns the index a document has been written to, as reported by
+// Elasticsearch, or the index of the request when it wasn't reported.
+func documentIndex(item esBulkIndexerItem, resp esBulkIndexerResponseItem) string {
+	if resp.Index != "" {
+		return resp.Index
+	}
+	return item.Index
+}
+
+// failureErrorType classifies a failed bulk item for the error_type tag.
+func failureErrorType(resp esBulkIndexerResponseItem) string {
+	switch {
+	case resp.Error.Type != "":
+		return resp.Error.Type
+	case resp.Status != 0:
+		return "status_" + strconv.Itoa(resp.Status)
+	default:
+		return "request_failed"
+	}
+}
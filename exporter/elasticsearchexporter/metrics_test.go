@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/esutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+)
+
+func TestExporterMetrics(t *testing.T) {
+	expectedViewNames := []string{
+		"elasticsearch_bulk_latency",
+		"elasticsearch_docs_indexed",
+		"elasticsearch_docs_failed",
+		"elasticsearch_bulk_queue_depth",
+		"elasticsearch_bytes_flushed",
+	}
+
+	views := MetricViews()
+	for i, viewName := range expectedViewNames {
+		assert.Equal(t, viewName, views[i].Name)
+	}
+}
+
+// mockBulkIndexer answers every added item right away with the response of respond.
+type mockBulkIndexer struct {
+	respond func(item esBulkIndexerItem) esBulkIndexerResponseItem
+}
+
+func (m *mockBulkIndexer) Add(ctx context.Context, item esBulkIndexerItem) error {
+	resp := m.respond(item)
+	if resp.Status < 300 {
+		item.OnSuccess(ctx, item, resp)
+	} else {
+		item.OnFailure(ctx, item, resp, nil)
+	}
+	return nil
+}
+
+func (m *mockBulkIndexer) Close(context.Context) error {
+	return nil
+}
+
+func (m *mockBulkIndexer) Stats() esutil.BulkIndexerStats {
+	return esutil.BulkIndexerStats{}
+}
+
+func TestBulkMetricsRecorded(t *testing.T) {
+	views := MetricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	// the documents are written behind the metrics-test alias
+	bulkIndexer := &mockBulkIndexer{respond: func(item esBulkIndexerItem) esBulkIndexerResponseItem {
+		resp := esBulkIndexerResponseItem{Index: "metrics-test-000001", Status: 201}
+		if item.Index == "metrics-test-rejected" {
+			resp.Index = ""
+			resp.Status = 400
+			resp.Error.Type = "mapper_parsing_exception"
+		}
+		return resp
+	}}
+	metrics := newBulkMetrics("metrics-test")
+	document := []byte(`{"message":"hello"}`)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, pushDocuments(context.Background(), zap.NewNop(), "metrics-test", document, bulkIndexer, 1, metrics))
+	}
+	require.NoError(t, pushDocuments(context.Background(), zap.NewNop(), "metrics-test-rejected", document, bulkIndexer, 1, metrics))
+
+	indexed := retrieveRows(t, mDocsIndexed.Name(), "metrics-test-000001")
+	require.Len(t, indexed, 1)
+	assert.Equal(t, float64(2), indexed[0].Data.(*view.SumData).Value)
+
+	flushed := retrieveRows(t, mBytesFlushed.Name(), "metrics-test-000001")
+	require.Len(t, flushed, 1)
+	assert.Equal(t, float64(2*len(document)), flushed[0].Data.(*view.SumData).Value)
+
+	failed := retrieveRows(t, mDocsFailed.Name(), "metrics-test-rejected")
+	require.Len(t, failed, 1)
+	assert.Contains(t, failed[0].Tags, tag.Tag{Key: errorTypeTagKey, Value: "mapper_parsing_exception"})
+	assert.Equal(t, float64(1), failed[0].Data.(*view.SumData).Value)
+
+	depth := retrieveRows(t, mQueueDepth.Name(), "metrics-test")
+	require.Len(t, depth, 1)
+	assert.Equal(t, float64(0), depth[0].Data.(*view.LastValueData).Value)
+}
+
+// retrieveRows returns the rows of the view tagged with index.
+func retrieveRows(t *testing.T, viewName string, index string) []*view.Row {
+	rows, err := view.RetrieveData(viewName)
+	require.NoError(t, err)
+	var indexRows []*view.Row
+	for _, row := range rows {
+		for _, tg := range row.Tags {
+			if tg.Key == indexTagKey && tg.Value == index {
+				indexRows = append(indexRows, row)
+			}
+		}
+	}
+	return indexRows
+}
+
+func TestFailureErrorType(t *testing.T) {
+	tests := map[string]struct {
+		resp esBulkIndexerResponseItem
+		want string
+	}{
+		"elasticsearch error type": {
+			resp: func() esBulkIndexerResponseItem {
+				var resp esBulkIndexerResponseItem
+				resp.Status = 400
+				resp.Error.Type = "mapper_parsing_exception"
+				return resp
+			}(),
+			want: "mapper_parsing_exception",
+		},
+		"status only": {
+			resp: esBulkIndexerResponseItem{Status: 429},
+			want: "status_429",
+		},
+		"request failed": {
+			resp: esBulkIndexerResponseItem{},
+			want: "request_failed",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.want, failureErrorType(test.resp))
+		})
+	}
+}
@@ -40,6 +40,8 @@ type mappingModel interface {
 type encodeModel struct {
 	dedup bool
 	dedot bool
+	// redactor scrubs the sensitive values of the documents, if any.
+	redactor objmodel.Redactor
 }
 
 const (
@@ -65,6 +67,9 @@ func (m *encodeModel) encodeLog(resource pcommon.Resource, record plog.LogRecord
 	} else if m.dedot {
 		document.Sort()
 	}
+	if m.redactor != nil {
+		document.Redact(m.redactor)
+	}
 
 	var buf bytes.Buffer
 	err := document.Serialize(&buf, m.dedot)
@@ -90,6 +95,9 @@ func (m *encodeModel) encodeSpan(resource pcommon.Resource, span ptrace.Span) ([
 	} else if m.dedot {
 		document.Sort()
 	}
+	if m.redactor != nil {
+		document.Redact(m.redactor)
+	}
 
 	var buf bytes.Buffer
 	err := document.Serialize(&buf, m.dedot)
@@ -0,0 +1,76 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/objmodel"
+)
+
+const redactedMask = "****"
+
+// redactor redacts the values of the configured keys, and the parts of the strings
+// matching the configured patterns.
+type redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+	hash     bool
+}
+
+var _ objmodel.Redactor = (*redactor)(nil)
+
+// newRedactor returns the redactor of the settings, nil when nothing is redacted.
+// The patterns must have been validated.
+func newRedactor(cfg RedactionSettings) objmodel.Redactor {
+	if len(cfg.Keys) == 0 && len(cfg.Patterns) == 0 {
+		return nil
+	}
+	r := &redactor{
+		keys: cfg.Keys,
+		hash: cfg.Method == redactionHash,
+	}
+	for _, pattern := range cfg.Patterns {
+		r.patterns = append(r.patterns, regexp.MustCompile(pattern))
+	}
+	return r
+}
+
+func (r *redactor) SensitiveKey(key string) bool {
+	for _, k := range r.keys {
+		if key == k || strings.HasSuffix(key, "."+k) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) Redact(s string) string {
+	if !r.hash {
+		return redactedMask
+	}
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *redactor) RedactString(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllStringFunc(s, r.Redact)
+	}
+	return s
+}
@@ -0,0 +1,90 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func encodeRedactedLog(t *testing.T, redaction RedactionSettings) string {
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "checkout")
+
+	record := plog.NewLogRecord()
+	record.Body().SetStr("charged card 4111-1111-1111-1111")
+	record.Attributes().PutStr("user.email", "jane@example.com")
+	record.Attributes().PutInt("password", 1234)
+	user := record.Attributes().PutEmptyMap("user")
+	user.PutStr("name", "jane")
+	cards := record.Attributes().PutEmptySlice("cards")
+	cards.AppendEmpty().SetStr("4111-1111-1111-1111")
+
+	model := &encodeModel{dedup: true, redactor: newRedactor(redaction)}
+	doc, err := model.encodeLog(resource, record)
+	require.NoError(t, err)
+	return string(doc)
+}
+
+func TestRedactionMask(t *testing.T) {
+	doc := encodeRedactedLog(t, RedactionSettings{
+		Keys:     []string{"user.email", "password"},
+		Patterns: []string{`\d{4}-\d{4}-\d{4}-\d{4}`},
+		Method:   redactionMask,
+	})
+	assert.Contains(t, doc, `"Body":"charged card ****"`)
+	assert.Contains(t, doc, `"Attributes.user.email":"****"`)
+	assert.Contains(t, doc, `"Attributes.password":"****"`)
+	assert.Contains(t, doc, `"Attributes.user.name":"jane"`)
+	assert.Contains(t, doc, `"Attributes.cards":["****"]`)
+	assert.Contains(t, doc, `"Resource.service.name":"checkout"`)
+	assert.NotContains(t, doc, "4111")
+	assert.NotContains(t, doc, "jane@example.com")
+}
+
+func TestRedactionHash(t *testing.T) {
+	doc := encodeRedactedLog(t, RedactionSettings{
+		Keys:   []string{"email"},
+		Method: redactionHash,
+	})
+	// sha256 of jane@example.com, the values can still be correlated
+	assert.Contains(t, doc, `"Attributes.user.email":"`+(&redactor{hash: true}).Redact("jane@example.com")+`"`)
+	assert.NotContains(t, doc, "jane@example.com")
+	assert.Contains(t, doc, `"Attributes.password":1234`)
+	assert.Contains(t, doc, `"Body":"charged card 4111-1111-1111-1111"`)
+}
+
+func TestRedactionDisabled(t *testing.T) {
+	assert.Nil(t, newRedactor(RedactionSettings{Method: redactionMask}))
+	doc := encodeRedactedLog(t, RedactionSettings{Method: redactionMask})
+	assert.Contains(t, doc, `"Attributes.user.email":"jane@example.com"`)
+}
+
+func TestRedactionValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoints = []string{"http://localhost:9200"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Redaction.Method = "encrypt"
+	assert.EqualError(t, cfg.Validate(), `unknown redaction method "encrypt"`)
+
+	cfg.Redaction.Method = redactionHash
+	cfg.Redaction.Patterns = []string{"("}
+	assert.EqualError(t, cfg.Validate(), "invalid redaction pattern \"(\": error parsing regexp: missing closing ): `(`")
+}
@@ -0,0 +1,215 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"go.uber.org/zap"
+)
+
+// bootstrapAttempts is the number of attempts to bootstrap the write alias when starting.
+const bootstrapAttempts = 3
+
+// indexRollover bootstraps the write alias of an index, and periodically triggers
+// its rollover when one of the configured conditions is met. It allows rotating
+// the indices of self-managed clusters without ILM automation.
+type indexRollover struct {
+	logger   *zap.Logger
+	client   *esClientCurrent
+	alias    string
+	settings RolloverSettings
+
+	// bootstrapBackoff is the time waited between two bootstrap attempts.
+	bootstrapBackoff time.Duration
+	stop             chan struct{}
+	wg               sync.WaitGroup
+}
+
+func newIndexRollover(logger *zap.Logger, client *esClientCurrent, alias string, settings RolloverSettings) *indexRollover {
+	return &indexRollover{
+		logger:           logger,
+		client:           client,
+		alias:            alias,
+		settings:         settings,
+		bootstrapBackoff: time.Second,
+		stop:             make(chan struct{}),
+	}
+}
+
+// start bootstraps the write alias, then checks the rollover conditions every interval.
+// The alias is bootstrapped before returning, since a document written to the alias
+// before it exists would create a concrete index with its name, which can't become an
+// alias anymore. Rollover errors are logged and retried on the next interval.
+func (r *indexRollover) start(ctx context.Context) error {
+	var err error
+	for attempt := 1; attempt <= bootstrapAttempts; attempt++ {
+		if err = r.bootstrap(ctx); err == nil {
+			break
+		}
+		r.logger.Warn("Failed to bootstrap the write alias", zap.String("alias", r.alias), zap.Int("attempt", attempt), zap.Error(err))
+		if attempt == bootstrapAttempts {
+			return fmt.Errorf("failed to bootstrap the write alias %s: %w", r.alias, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to bootstrap the write alias %s: %w", r.alias, ctx.Err())
+		case <-time.After(r.bootstrapBackoff):
+		}
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.settings.Interval)
+		defer ticker.Stop()
+
+		r.run()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.run()
+			}
+		}
+	}()
+	return nil
+}
+
+func (r *indexRollover) shutdown() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+func (r *indexRollover) run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := r.rollover(ctx); err != nil {
+		r.logger.Error("Failed to roll over the write alias", zap.String("alias", r.alias), zap.Error(err))
+	}
+}
+
+// bootstrap creates the first index of the alias, with the alias as its write alias,
+// unless the alias already exists.
+func (r *indexRollover) bootstrap(ctx context.Context) error {
+	res, err := r.client.Indices.ExistsAlias([]string{r.alias}, r.client.Indices.ExistsAlias.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+	default:
+		return fmt.Errorf("unexpected status checking the alias: %s", res.Status())
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"aliases": map[string]interface{}{
+			r.alias: map[string]interface{}{"is_write_index": true},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	index := r.alias + "-000001"
+	res, err = r.client.Indices.Create(index,
+		r.client.Indices.Create.WithBody(bytes.NewReader(body)),
+		r.client.Indices.Create.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if err = responseError(res); err != nil {
+		return fmt.Errorf("failed to create index %s: %w", index, err)
+	}
+	r.logger.Info("Bootstrapped the write alias", zap.String("alias", r.alias), zap.String("index", index))
+	return nil
+}
+
+// rollover rolls the alias over to a new index when one of the conditions is met.
+func (r *indexRollover) rollover(ctx context.Context) error {
+	body, err := json.Marshal(map[string]interface{}{"conditions": r.settings.conditions()})
+	if err != nil {
+		return err
+	}
+	res, err := r.client.Indices.Rollover(r.alias,
+		r.client.Indices.Rollover.WithBody(bytes.NewReader(body)),
+		r.client.Indices.Rollover.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if res.IsError() {
+		return responseError(res)
+	}
+	defer res.Body.Close()
+
+	var result struct {
+		RolledOver bool   `json:"rolled_over"`
+		NewIndex   string `json:"new_index"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode the rollover response: %w", err)
+	}
+	if result.RolledOver {
+		r.logger.Info("Rolled over the write alias", zap.String("alias", r.alias), zap.String("index", result.NewIndex))
+	}
+	return nil
+}
+
+// conditions returns the rollover conditions in the format of the Elasticsearch API.
+func (s RolloverSettings) conditions() map[string]interface{} {
+	conditions := map[string]interface{}{}
+	if s.MaxAge > 0 {
+		conditions["max_age"] = fmt.Sprintf("%ds", int64(s.MaxAge/time.Second))
+	}
+	if s.MaxPrimaryShardSize != "" {
+		conditions["max_primary_shard_size"] = s.MaxPrimaryShardSize
+	}
+	if s.MaxDocs > 0 {
+		conditions["max_docs"] = s.MaxDocs
+	}
+	return conditions
+}
+
+// responseError closes the response body and returns an error holding it when the
+// response status is an error.
+func responseError(res *esapi.Response) error {
+	defer res.Body.Close()
+	if !res.IsError() {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+	return fmt.Errorf("%s: %s", res.Status(), bytes.TrimSpace(msg))
+}
@@ -0,0 +1,248 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// rolloverRecorder emulates the alias, index creation, rollover and bulk APIs of a cluster.
+type rolloverRecorder struct {
+	mu          sync.Mutex
+	aliasExists bool
+	// aliasFailures is the number of alias checks failing before the cluster is available.
+	aliasFailures int
+	// concreteIndex is set when a document is written to the alias name before the alias
+	// exists, creating an index with its name.
+	concreteIndex bool
+	created       []string
+	conditions    []map[string]interface{}
+	requests      []string
+}
+
+func newRolloverTestServer(t *testing.T, rec *rolloverRecorder) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("X-Elastic-Product", "Elasticsearch")
+		_, _ = fmt.Fprintf(w, `{"version": {"number": %q}}`, currentESVersion)
+	})
+	mux.HandleFunc("/_alias/logs-alias", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("X-Elastic-Product", "Elasticsearch")
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.requests = append(rec.requests, "alias")
+		switch {
+		case rec.aliasFailures > 0:
+			rec.aliasFailures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case !rec.aliasExists:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc("/logs-alias-000001", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("X-Elastic-Product", "Elasticsearch")
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{
+			"logs-alias": map[string]interface{}{"is_write_index": true},
+		}, body["aliases"])
+
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.requests = append(rec.requests, "create")
+		if rec.concreteIndex {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": {"type": "invalid_alias_name_exception"}, "status": 400}`))
+			return
+		}
+		rec.created = append(rec.created, "logs-alias-000001")
+		rec.aliasExists = true
+		_, _ = w.Write([]byte(`{"acknowledged": true}`))
+	})
+	mux.HandleFunc("/logs-alias/_rollover", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("X-Elastic-Product", "Elasticsearch")
+		var body struct {
+			Conditions map[string]interface{} `json:"conditions"`
+		}
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.requests = append(rec.requests, "rollover")
+		rec.conditions = append(rec.conditions, body.Conditions)
+		_, _ = w.Write([]byte(`{"acknowledged": true, "rolled_over": true, "new_index": "logs-alias-000002"}`))
+	})
+	mux.HandleFunc("/_bulk", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("X-Elastic-Product", "Elasticsearch")
+		var items []string
+		dec := json.NewDecoder(req.Body)
+		for dec.More() {
+			var action map[string]struct {
+				Index string `json:"_index"`
+			}
+			var doc json.RawMessage
+			if !assert.NoError(t, dec.Decode(&action)) || !assert.NoError(t, dec.Decode(&doc)) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			rec.mu.Lock()
+			rec.requests = append(rec.requests, "bulk")
+			if !rec.aliasExists && action[createAction].Index == "logs-alias" {
+				rec.concreteIndex = true
+			}
+			rec.mu.Unlock()
+			items = append(items, `{"create": {"status": 201}}`)
+		}
+		_, _ = fmt.Fprintf(w, `{"took": 1, "errors": false, "items": [%s]}`, strings.Join(items, ","))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestIndexRollover(t *testing.T, server *httptest.Server, settings RolloverSettings) *indexRollover {
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.Endpoints = []string{server.URL}
+		cfg.Retry.Enabled = false
+	})
+	client, err := newElasticsearchClient(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	rollover := newIndexRollover(zap.NewNop(), client, "logs-alias", settings)
+	rollover.bootstrapBackoff = time.Millisecond
+	return rollover
+}
+
+func TestIndexRollover(t *testing.T) {
+	rec := &rolloverRecorder{}
+	server := newRolloverTestServer(t, rec)
+	rollover := newTestIndexRollover(t, server, RolloverSettings{
+		Enabled:             true,
+		Interval:            10 * time.Millisecond,
+		MaxAge:              24 * time.Hour,
+		MaxPrimaryShardSize: "50gb",
+	})
+
+	require.NoError(t, rollover.start(context.Background()))
+	assert.Eventually(t, func() bool {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		return len(rec.conditions) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	rollover.shutdown()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.Equal(t, []string{"logs-alias-000001"}, rec.created, "the alias is only bootstrapped once")
+	assert.Equal(t, map[string]interface{}{
+		"max_age":                "86400s",
+		"max_primary_shard_size": "50gb",
+	}, rec.conditions[0])
+}
+
+func TestIndexRolloverExistingAlias(t *testing.T) {
+	rec := &rolloverRecorder{aliasExists: true}
+	server := newRolloverTestServer(t, rec)
+	rollover := newTestIndexRollover(t, server, RolloverSettings{
+		Enabled:  true,
+		Interval: time.Hour,
+		MaxDocs:  1000,
+	})
+
+	rollover.run()
+
+	assert.Empty(t, rec.created)
+	require.Len(t, rec.conditions, 1)
+	assert.Equal(t, map[string]interface{}{"max_docs": float64(1000)}, rec.conditions[0])
+}
+
+func TestIndexRolloverRetriesBootstrap(t *testing.T) {
+	rec := &rolloverRecorder{aliasFailures: bootstrapAttempts - 1}
+	server := newRolloverTestServer(t, rec)
+	rollover := newTestIndexRollover(t, server, RolloverSettings{
+		Enabled:  true,
+		Interval: time.Hour,
+		MaxDocs:  1000,
+	})
+
+	require.NoError(t, rollover.start(context.Background()))
+	rollover.shutdown()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.Equal(t, []string{"logs-alias-000001"}, rec.created)
+}
+
+func TestIndexRolloverBootstrapFailure(t *testing.T) {
+	rec := &rolloverRecorder{aliasFailures: bootstrapAttempts}
+	server := newRolloverTestServer(t, rec)
+	rollover := newTestIndexRollover(t, server, RolloverSettings{
+		Enabled:  true,
+		Interval: time.Hour,
+		MaxDocs:  1000,
+	})
+
+	err := rollover.start(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to bootstrap the write alias logs-alias")
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.Empty(t, rec.created)
+	assert.Equal(t, 0, rec.aliasFailures)
+}
+
+func TestLogsExporterBootstrapsAliasBeforePush(t *testing.T) {
+	rec := &rolloverRecorder{}
+	server := newRolloverTestServer(t, rec)
+	exporter, err := newLogsExporter(zaptest.NewLogger(t), withTestExporterConfig(func(cfg *Config) {
+		cfg.LogsIndex = "logs-alias"
+		cfg.Retry.Enabled = false
+		cfg.Rollover = RolloverSettings{
+			Enabled:  true,
+			Interval: time.Hour,
+			MaxDocs:  1000,
+		}
+	})(server.URL))
+	require.NoError(t, err)
+
+	require.NoError(t, exporter.Start(context.Background(), componenttest.NewNopHost()))
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("test")
+	require.NoError(t, exporter.pushLogsData(context.Background(), logs))
+	require.NoError(t, exporter.Shutdown(context.Background()))
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.False(t, rec.concreteIndex, "a document was written before the alias existed")
+	assert.Equal(t, []string{"logs-alias-000001"}, rec.created)
+	require.Contains(t, rec.requests, "bulk")
+	assert.Equal(t, []string{"alias", "create"}, rec.requests[:2])
+}
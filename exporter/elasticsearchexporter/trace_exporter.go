@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/multierr"
@@ -34,7 +35,9 @@ type elasticsearchTracesExporter struct {
 
 	client      *esClientCurrent
 	bulkIndexer esBulkIndexerCurrent
+	metrics     *bulkMetrics
 	model       mappingModel
+	rollover    *indexRollover
 }
 
 func newTracesExporter(logger *zap.Logger, cfg *Config) (*elasticsearchTracesExporter, error) {
@@ -47,7 +50,8 @@ func newTracesExporter(logger *zap.Logger, cfg *Config) (*elasticsearchTracesExp
 		return nil, err
 	}
 
-	bulkIndexer, err := newBulkIndexer(logger, client, cfg)
+	metrics := newBulkMetrics(cfg.TracesIndex)
+	bulkIndexer, err := newBulkIndexer(logger, client, cfg, metrics)
 	if err != nil {
 		return nil, err
 	}
@@ -58,20 +62,38 @@ func newTracesExporter(logger *zap.Logger, cfg *Config) (*elasticsearchTracesExp
 	}
 
 	// TODO: Apply encoding and field mapping settings.
-	model := &encodeModel{dedup: true, dedot: false}
+	var model mappingModel = &encodeModel{dedup: true, dedot: false, redactor: newRedactor(cfg.Redaction)}
+	if mappingModes[cfg.Mapping.Mode] == MappingAPM {
+		model = &apmModel{encodeModel: encodeModel{dedup: true, redactor: newRedactor(cfg.Redaction)}}
+	}
 
-	return &elasticsearchTracesExporter{
+	esTracesExp := &elasticsearchTracesExporter{
 		logger:      logger,
 		client:      client,
 		bulkIndexer: bulkIndexer,
+		metrics:     metrics,
 
 		index:       cfg.TracesIndex,
 		maxAttempts: maxAttempts,
 		model:       model,
-	}, nil
+	}
+	if cfg.Rollover.Enabled {
+		esTracesExp.rollover = newIndexRollover(logger, client, cfg.TracesIndex, cfg.Rollover)
+	}
+	return esTracesExp, nil
+}
+
+func (e *elasticsearchTracesExporter) Start(ctx context.Context, _ component.Host) error {
+	if e.rollover != nil {
+		return e.rollover.start(ctx)
+	}
+	return nil
 }
 
 func (e *elasticsearchTracesExporter) Shutdown(ctx context.Context) error {
+	if e.rollover != nil {
+		e.rollover.shutdown()
+	}
 	return e.bulkIndexer.Close(ctx)
 }
 
@@ -106,5 +128,5 @@ func (e *elasticsearchTracesExporter) pushTraceRecord(ctx context.Context, resou
 	if err != nil {
 		return fmt.Errorf("Failed to encode trace record: %w", err)
 	}
-	return pushDocuments(ctx, e.logger, e.index, document, e.bulkIndexer, e.maxAttempts)
+	return pushDocuments(ctx, e.logger, e.index, document, e.bulkIndexer, e.maxAttempts, e.metrics)
 }
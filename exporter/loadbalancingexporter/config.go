@@ -26,14 +26,24 @@ type routingKey int
 const (
 	traceIDRouting routingKey = iota
 	svcRouting
+	// attrRouting routes by a resource attribute
+	attrRouting
 )
 
 // Config defines configuration for the exporter.
 type Config struct {
 	config.ExporterSettings `mapstructure:",squash"`
-	Protocol                Protocol         `mapstructure:"protocol"`
-	Resolver                ResolverSettings `mapstructure:"resolver"`
-	RoutingKey              string           `mapstructure:"routing_key"`
+	Protocol                Protocol          `mapstructure:"protocol"`
+	Resolver                ResolverSettings  `mapstructure:"resolver"`
+	RoutingKey              string            `mapstructure:"routing_key"`
+	RoutingKeys             RoutingKeys       `mapstructure:"routing_keys"`
+	Locality                *LocalitySettings `mapstructure:"locality"`
+	LazyExporters           *LazyExporters    `mapstructure:"lazy_exporters"`
+	Stickiness              *Stickiness       `mapstructure:"stickiness"`
+	HealthCheck             *HealthCheck      `mapstructure:"health_check"`
+	// SharedRing is the name of the ring shared with the other exporters configured with the same name,
+	// so that the data of their pipelines with the same routing key is sent to the same backend.
+	SharedRing string `mapstructure:"shared_ring"`
 }
 
 // Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
@@ -41,10 +51,22 @@ type Protocol struct {
 	OTLP otlpexporter.Config `mapstructure:"otlp"`
 }
 
+// RoutingKeys defines the routing key of each signal: traceID, service, or resource.<attribute> to route by the
+// value of a resource attribute
+type RoutingKeys struct {
+	// Traces is the routing key of the traces, RoutingKey being used when not set.
+	Traces string `mapstructure:"traces"`
+	// Logs is the routing key of the logs, traceID by default.
+	Logs string `mapstructure:"logs"`
+	// Metrics is the routing key of the metrics, service by default.
+	Metrics string `mapstructure:"metrics"`
+}
+
 // ResolverSettings defines the configurations for the backend resolver
 type ResolverSettings struct {
-	Static *StaticResolver `mapstructure:"static"`
-	DNS    *DNSResolver    `mapstructure:"dns"`
+	Static      *StaticResolver      `mapstructure:"static"`
+	DNS         *DNSResolver         `mapstructure:"dns"`
+	AWSCloudMap *AWSCloudMapResolver `mapstructure:"aws_cloud_map"`
 }
 
 // StaticResolver defines the configuration for the resolver providing a fixed list of backends
@@ -52,6 +74,46 @@ type StaticResolver struct {
 	Hostnames []string `mapstructure:"hostnames"`
 }
 
+// LocalitySettings defines the preference for the backends in the same zone as the exporter
+type LocalitySettings struct {
+	// Zone is the zone of the exporter, the backends in this zone are preferred.
+	Zone string `mapstructure:"zone"`
+	// EndpointZones maps the backend endpoints to their zone.
+	EndpointZones map[string]string `mapstructure:"endpoint_zones"`
+	// UnhealthyDuration is the duration a backend failing to export is excluded from the preferred backends.
+	UnhealthyDuration time.Duration `mapstructure:"unhealthy_duration"`
+}
+
+// LazyExporters defines the creation of the backend exporters on first use, instead of when the backends are resolved
+type LazyExporters struct {
+	// IdleTimeout is the duration after which an unused exporter is shut down, 0 keeps the exporters until their backend is removed.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+}
+
+// Stickiness defines the routing of the late spans of a trace to the backend its first spans were routed to, even if the backends changed since
+type Stickiness struct {
+	// TTL is the duration a trace stays routed to its backend after its last spans.
+	TTL time.Duration `mapstructure:"ttl"`
+	// MaxTraces is the maximum number of traces remembered, the least recently seen traces are forgotten first.
+	MaxTraces int `mapstructure:"max_traces"`
+}
+
+// HealthCheck defines the active health checking of the backends, the unhealthy backends being removed from the ring until they pass a check again
+type HealthCheck struct {
+	// Protocol is the protocol of the checks: tcp, http or grpc.
+	Protocol string `mapstructure:"protocol"`
+	// Port is the port the backends are checked on, the port of their endpoint by default, or 13133 for the http checks.
+	Port string `mapstructure:"port"`
+	// Path is the path of the http checks.
+	Path string `mapstructure:"path"`
+	// Service is the service whose health is checked with the gRPC health checking protocol, the whole server by default.
+	Service string `mapstructure:"service"`
+	// Interval is the interval between the checks of each backend.
+	Interval time.Duration `mapstructure:"interval"`
+	// Timeout is the timeout of each check.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
 // DNSResolver defines the configuration for the DNS resolver
 type DNSResolver struct {
 	Hostname string        `mapstructure:"hostname"`
@@ -59,3 +121,23 @@ type DNSResolver struct {
 	Interval time.Duration `mapstructure:"interval"`
 	Timeout  time.Duration `mapstructure:"timeout"`
 }
+
+// AWSCloudMapResolver defines the configuration for the resolver discovering the backends registered in AWS Cloud Map
+type AWSCloudMapResolver struct {
+	// NamespaceName is the name of the namespace of the service.
+	NamespaceName string `mapstructure:"namespace"`
+	// ServiceName is the name of the service the backends are registered with.
+	ServiceName string `mapstructure:"service_name"`
+	// HealthStatus filters the instances by their health: HEALTHY (default), UNHEALTHY, ALL or HEALTHY_OR_ELSE_ALL.
+	HealthStatus string `mapstructure:"health_status"`
+	// QueryParameters filters the instances by the values of their custom attributes.
+	QueryParameters map[string]string `mapstructure:"query_parameters"`
+	// MaxInstances is the maximum number of instances discovered, 100 by default.
+	MaxInstances int `mapstructure:"max_instances"`
+	// Port is the port of the backends, the port the instances were registered with by default.
+	Port string `mapstructure:"port"`
+	// Region is the AWS region of the namespace, the region of the environment by default.
+	Region   string        `mapstructure:"region"`
+	Interval time.Duration `mapstructure:"interval"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
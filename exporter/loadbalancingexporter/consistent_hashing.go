@@ -36,13 +36,20 @@ type ringItem struct {
 type hashRing struct {
 	// ringItems holds all the positions, used for the lookup the position for the closest next ring item
 	items []ringItem
+	// endpoints holds the endpoints of the ring
+	endpoints map[string]bool
 }
 
 // newHashRing builds a new immutable consistent hash ring based on the given endpoints.
 func newHashRing(endpoints []string) *hashRing {
 	items := positionsForEndpoints(endpoints, defaultWeight)
+	set := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		set[endpoint] = true
+	}
 	return &hashRing{
-		items: items,
+		items:     items,
+		endpoints: set,
 	}
 }
 
@@ -60,6 +67,11 @@ func (h *hashRing) endpointFor(identifier []byte) string {
 	return h.findEndpoint(position(pos))
 }
 
+// contains returns whether the endpoint is part of the ring
+func (h *hashRing) contains(endpoint string) bool {
+	return h != nil && h.endpoints[endpoint]
+}
+
 // findEndpoint returns the "next" endpoint starting from the given position, or an empty string in case no endpoints are available
 func (h *hashRing) findEndpoint(pos position) string {
 	ringSize := len(h.items)
@@ -0,0 +1,323 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Protocols of the health checks.
+const (
+	tcpHealthCheck  = "tcp"
+	httpHealthCheck = "http"
+	grpcHealthCheck = "grpc"
+)
+
+const (
+	defaultHealthCheckInterval = 5 * time.Second
+	defaultHealthCheckTimeout  = time.Second
+	defaultHTTPHealthCheckPort = "13133"
+	defaultHTTPHealthCheckPath = "/"
+)
+
+var _ resolver = (*healthCheckedResolver)(nil)
+
+// healthChecker checks the health of the backend with the given endpoint, returning an error when it's unhealthy.
+type healthChecker func(ctx context.Context, endpoint string) error
+
+// healthCheckedResolver actively checks the health of the backends of the resolver, the unhealthy
+// backends being removed from the resolved backends until they pass a check again. All the backends
+// are kept when none is healthy, so that the data is still exported.
+type healthCheckedResolver struct {
+	logger   *zap.Logger
+	resolver resolver
+	check    healthChecker
+	interval time.Duration
+	timeout  time.Duration
+
+	mu        sync.Mutex
+	resolved  []string
+	unhealthy map[string]bool
+	callbacks []func([]string)
+
+	stopCh     chan struct{}
+	shutdownWg sync.WaitGroup
+}
+
+func newHealthCheckedResolver(logger *zap.Logger, res resolver, cfg *HealthCheck, tlsCfg *tls.Config) (*healthCheckedResolver, error) {
+	var check healthChecker
+	switch cfg.Protocol {
+	case tcpHealthCheck, "":
+		check = tcpCheck(cfg.Port)
+	case httpHealthCheck:
+		port, path := cfg.Port, cfg.Path
+		if port == "" {
+			port = defaultHTTPHealthCheckPort
+		}
+		if path == "" {
+			path = defaultHTTPHealthCheckPath
+		}
+		check = httpCheck(port, path, tlsCfg)
+	case grpcHealthCheck:
+		check = grpcCheck(cfg.Port, cfg.Service, tlsCfg)
+	default:
+		return nil, fmt.Errorf("unsupported health check protocol %q, must be one of %q, %q or %q", cfg.Protocol, tcpHealthCheck, httpHealthCheck, grpcHealthCheck)
+	}
+	if cfg.Interval < 0 || cfg.Timeout < 0 {
+		return nil, errors.New("the interval and the timeout of the health checks must not be negative")
+	}
+
+	interval, timeout := cfg.Interval, cfg.Timeout
+	if interval == 0 {
+		interval = defaultHealthCheckInterval
+	}
+	if timeout == 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	return &healthCheckedResolver{
+		logger:    logger,
+		resolver:  res,
+		check:     check,
+		interval:  interval,
+		timeout:   timeout,
+		unhealthy: map[string]bool{},
+	}, nil
+}
+
+func (r *healthCheckedResolver) resolve(ctx context.Context) ([]string, error) {
+	if _, err := r.resolver.resolve(ctx); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.healthy(), nil
+}
+
+func (r *healthCheckedResolver) start(ctx context.Context) error {
+	r.resolver.onChange(r.onResolved)
+	if err := r.resolver.start(ctx); err != nil {
+		return err
+	}
+
+	r.stopCh = make(chan struct{})
+	r.shutdownWg.Add(1)
+	go r.checkPeriodically()
+	return nil
+}
+
+func (r *healthCheckedResolver) shutdown(ctx context.Context) error {
+	if r.stopCh != nil {
+		close(r.stopCh)
+		r.shutdownWg.Wait()
+	}
+
+	r.mu.Lock()
+	r.callbacks = nil
+	r.mu.Unlock()
+	return r.resolver.shutdown(ctx)
+}
+
+func (r *healthCheckedResolver) onChange(f func([]string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.callbacks = append(r.callbacks, f)
+}
+
+// onResolved keeps the backends resolved by the resolver, forgetting the health of the removed ones.
+func (r *healthCheckedResolver) onResolved(endpoints []string) {
+	r.mu.Lock()
+	r.resolved = endpoints
+	resolved := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		resolved[endpoint] = true
+	}
+	for endpoint := range r.unhealthy {
+		if !resolved[endpoint] {
+			delete(r.unhealthy, endpoint)
+		}
+	}
+	r.mu.Unlock()
+
+	r.notify()
+}
+
+func (r *healthCheckedResolver) checkPeriodically() {
+	defer r.shutdownWg.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.checkAll(context.Background())
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+// checkAll checks the health of all the resolved backends concurrently, notifying the callbacks
+// when a backend became unhealthy or healthy again.
+func (r *healthCheckedResolver) checkAll(ctx context.Context) {
+	r.mu.Lock()
+	endpoints := r.resolved
+	r.mu.Unlock()
+
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, r.timeout)
+			defer cancel()
+			errs[i] = r.check(checkCtx, endpointWithPort(endpoint))
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	changed := false
+	r.mu.Lock()
+	for i, endpoint := range endpoints {
+		unhealthy := errs[i] != nil
+		if unhealthy == r.unhealthy[endpoint] {
+			continue
+		}
+		changed = true
+		if unhealthy {
+			r.logger.Warn("backend failed its health check, removing it from the ring", zap.String("endpoint", endpoint), zap.Error(errs[i]))
+			r.unhealthy[endpoint] = true
+		} else {
+			r.logger.Info("backend is healthy again, adding it back to the ring", zap.String("endpoint", endpoint))
+			delete(r.unhealthy, endpoint)
+		}
+	}
+	r.mu.Unlock()
+
+	if changed {
+		r.notify()
+	}
+}
+
+// healthy returns the resolved backends that aren't unhealthy, or all of them when none is healthy.
+// The caller must hold the lock.
+func (r *healthCheckedResolver) healthy() []string {
+	healthy := make([]string, 0, len(r.resolved))
+	for _, endpoint := range r.resolved {
+		if !r.unhealthy[endpoint] {
+			healthy = append(healthy, endpoint)
+		}
+	}
+	if len(healthy) == 0 {
+		return r.resolved
+	}
+	return healthy
+}
+
+func (r *healthCheckedResolver) notify() {
+	r.mu.Lock()
+	healthy := r.healthy()
+	callbacks := make([]func([]string), len(r.callbacks))
+	copy(callbacks, r.callbacks)
+	r.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(healthy)
+	}
+}
+
+// checkedAddress returns the address of the endpoint with the port of the checks, if any.
+func checkedAddress(endpoint, port string) string {
+	if port == "" {
+		return endpoint
+	}
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		host = endpoint
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// tcpCheck checks that a connection to the backend can be opened.
+func tcpCheck(port string) healthChecker {
+	return func(ctx context.Context, endpoint string) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", checkedAddress(endpoint, port))
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// httpCheck checks that the path of the backend responds with a 2xx status code.
+func httpCheck(port, path string, tlsCfg *tls.Config) healthChecker {
+	scheme := "http"
+	if tlsCfg != nil {
+		scheme = "https"
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+	return func(ctx context.Context, endpoint string) error {
+		url := fmt.Sprintf("%s://%s%s", scheme, checkedAddress(endpoint, port), path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// grpcCheck checks that the backend reports the service as serving with the gRPC health checking protocol.
+func grpcCheck(port, service string, tlsCfg *tls.Config) healthChecker {
+	creds := insecure.NewCredentials()
+	if tlsCfg != nil {
+		creds = credentials.NewTLS(tlsCfg)
+	}
+	return func(ctx context.Context, endpoint string) error {
+		conn, err := grpc.DialContext(ctx, checkedAddress(endpoint, port), grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return err
+		}
+		if resp.Status != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// fakeHealth holds the unhealthy endpoints of the tests.
+type fakeHealth struct {
+	mu        sync.Mutex
+	unhealthy map[string]bool
+}
+
+func (h *fakeHealth) set(endpoint string, unhealthy bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.unhealthy[endpoint] = unhealthy
+}
+
+func (h *fakeHealth) check(_ context.Context, endpoint string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.unhealthy[endpoint] {
+		return errors.New("unhealthy")
+	}
+	return nil
+}
+
+func newTestHealthCheckedResolver(t *testing.T, endpoints ...string) (*healthCheckedResolver, *fakeHealth, *[]string) {
+	static, err := newStaticResolver(endpoints)
+	require.NoError(t, err)
+	res, err := newHealthCheckedResolver(zap.NewNop(), static, &HealthCheck{}, nil)
+	require.NoError(t, err)
+	h := &fakeHealth{unhealthy: map[string]bool{}}
+	res.check = h.check
+
+	var mu sync.Mutex
+	var notified []string
+	res.onChange(func(endpoints []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		notified = endpoints
+	})
+	require.NoError(t, res.start(context.Background()))
+	t.Cleanup(func() { require.NoError(t, res.shutdown(context.Background())) })
+	return res, h, &notified
+}
+
+func TestHealthCheckedResolverRemovesUnhealthyBackends(t *testing.T) {
+	res, h, notified := newTestHealthCheckedResolver(t, "endpoint-1", "endpoint-2:4318")
+	assert.Equal(t, []string{"endpoint-1", "endpoint-2:4318"}, *notified)
+
+	// the checks use the endpoints with their port
+	h.set("endpoint-1:4317", true)
+	res.checkAll(context.Background())
+	assert.Equal(t, []string{"endpoint-2:4318"}, *notified)
+	resolved, err := res.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"endpoint-2:4318"}, resolved)
+
+	// the backend is added back once it passes a check again
+	h.set("endpoint-1:4317", false)
+	res.checkAll(context.Background())
+	assert.Equal(t, []string{"endpoint-1", "endpoint-2:4318"}, *notified)
+}
+
+func TestHealthCheckedResolverKeepsAllBackendsWhenNoneIsHealthy(t *testing.T) {
+	res, h, notified := newTestHealthCheckedResolver(t, "endpoint-1", "endpoint-2")
+
+	h.set("endpoint-1:4317", true)
+	res.checkAll(context.Background())
+	assert.Equal(t, []string{"endpoint-2"}, *notified)
+
+	h.set("endpoint-2:4317", true)
+	res.checkAll(context.Background())
+	assert.Equal(t, []string{"endpoint-1", "endpoint-2"}, *notified)
+}
+
+func TestHealthCheckedResolverForgetsRemovedBackends(t *testing.T) {
+	res, h, notified := newTestHealthCheckedResolver(t, "endpoint-1", "endpoint-2")
+
+	h.set("endpoint-1:4317", true)
+	res.checkAll(context.Background())
+	res.onResolved([]string{"endpoint-2", "endpoint-3"})
+	assert.Equal(t, []string{"endpoint-2", "endpoint-3"}, *notified)
+	assert.Empty(t, res.unhealthy)
+}
+
+func TestNewHealthCheckedResolverInvalidConfig(t *testing.T) {
+	static, err := newStaticResolver([]string{"endpoint-1"})
+	require.NoError(t, err)
+
+	_, err = newHealthCheckedResolver(zap.NewNop(), static, &HealthCheck{Protocol: "udp"}, nil)
+	assert.EqualError(t, err, `unsupported health check protocol "udp", must be one of "tcp", "http" or "grpc"`)
+
+	_, err = newHealthCheckedResolver(zap.NewNop(), static, &HealthCheck{Interval: -1}, nil)
+	assert.Error(t, err)
+
+	cfg := simpleConfig()
+	cfg.HealthCheck = &HealthCheck{Protocol: "udp"}
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, nil)
+	assert.Nil(t, p)
+	assert.Error(t, err)
+}
+
+func TestTCPCheck(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+
+	assert.NoError(t, tcpCheck("")(context.Background(), ln.Addr().String()))
+	// the port of the checks overrides the port of the endpoint
+	assert.NoError(t, tcpCheck(port)(context.Background(), "127.0.0.1:4317"))
+
+	require.NoError(t, ln.Close())
+	assert.Error(t, tcpCheck("")(context.Background(), ln.Addr().String()))
+}
+
+func TestHTTPCheck(t *testing.T) {
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" || !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	check := httpCheck(port, "/health", nil)
+	assert.NoError(t, check(context.Background(), "127.0.0.1:4317"))
+	healthy = false
+	assert.EqualError(t, check(context.Background(), "127.0.0.1:4317"), "unexpected status code 503")
+}
+
+func TestGRPCCheck(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Stop()
+
+	assert.NoError(t, grpcCheck("", "", nil)(context.Background(), ln.Addr().String()))
+
+	healthServer.SetServingStatus("otlp", healthpb.HealthCheckResponse_NOT_SERVING)
+	assert.EqualError(t, grpcCheck("", "otlp", nil)(context.Background(), ln.Addr().String()), "unexpected status NOT_SERVING")
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
@@ -32,6 +33,7 @@ const (
 var (
 	errNoResolver                = errors.New("no resolvers specified for the exporter")
 	errMultipleResolversProvided = errors.New("only one resolver should be specified")
+	errNegativeIdleTimeout       = errors.New("the idle timeout of the lazy exporters must not be negative")
 )
 
 var _ loadBalancer = (*loadBalancerImp)(nil)
@@ -42,18 +44,34 @@ type loadBalancer interface {
 	component.Component
 	Endpoint(identifier []byte) string
 	Exporter(endpoint string) (component.Exporter, error)
+	// IsResolved returns whether the endpoint is one of the currently resolved backends.
+	IsResolved(endpoint string) bool
+	// ReportFailure signals that the data couldn't be exported to the endpoint.
+	ReportFailure(endpoint string)
 }
 
 type loadBalancerImp struct {
 	logger *zap.Logger
 	host   component.Host
 
-	res  resolver
-	ring *hashRing
+	res      resolver
+	ring     *hashRing
+	locality *localityPreference
 
 	componentFactory componentFactory
 	exporters        map[string]component.Exporter
 
+	// lazy is set when the exporters are only created when first routed to,
+	// idleTimeout is then the duration after which an unused exporter is shut down.
+	lazy        bool
+	idleTimeout time.Duration
+	usageLock   sync.Mutex
+	lastUsed    map[string]time.Time
+	stopIdle    chan struct{}
+	idleWg      sync.WaitGroup
+	// now returns the current time, it is replaced by the tests.
+	now func() time.Time
+
 	stopped    bool
 	updateLock sync.RWMutex
 }
@@ -62,9 +80,18 @@ type loadBalancerImp struct {
 func newLoadBalancer(params component.ExporterCreateSettings, cfg component.ExporterConfig, factory componentFactory) (*loadBalancerImp, error) {
 	oCfg := cfg.(*Config)
 
-	if oCfg.Resolver.DNS != nil && oCfg.Resolver.Static != nil {
+	resolvers := 0
+	for _, configured := range []bool{oCfg.Resolver.Static != nil, oCfg.Resolver.DNS != nil, oCfg.Resolver.AWSCloudMap != nil} {
+		if configured {
+			resolvers++
+		}
+	}
+	if resolvers > 1 {
 		return nil, errMultipleResolversProvided
 	}
+	if oCfg.LazyExporters != nil && oCfg.LazyExporters.IdleTimeout < 0 {
+		return nil, errNegativeIdleTimeout
+	}
 
 	var res resolver
 	if oCfg.Resolver.Static != nil {
@@ -84,21 +111,63 @@ func newLoadBalancer(params component.ExporterCreateSettings, cfg component.Expo
 		}
 	}
 
+	if oCfg.Resolver.AWSCloudMap != nil {
+		cloudMapLogger := params.Logger.With(zap.String("resolver", "aws_cloud_map"))
+
+		var err error
+		res, err = newCloudMapResolver(cloudMapLogger, oCfg.Resolver.AWSCloudMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if res == nil {
 		return nil, errNoResolver
 	}
 
-	return &loadBalancerImp{
+	if oCfg.HealthCheck != nil {
+		tlsCfg, err := oCfg.Protocol.OTLP.TLSSetting.LoadTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		healthLogger := params.Logger.With(zap.String("health_check", oCfg.HealthCheck.Protocol))
+		if res, err = newHealthCheckedResolver(healthLogger, res, oCfg.HealthCheck, tlsCfg); err != nil {
+			return nil, err
+		}
+	}
+
+	lb := &loadBalancerImp{
 		logger:           params.Logger,
 		res:              res,
 		componentFactory: factory,
 		exporters:        map[string]component.Exporter{},
-	}, nil
+		lastUsed:         map[string]time.Time{},
+		now:              time.Now,
+	}
+	if oCfg.Locality != nil {
+		lb.locality = newLocalityPreference(oCfg.Locality)
+	}
+	if oCfg.SharedRing != "" {
+		var err error
+		if lb.res, lb.locality, err = joinSharedRing(oCfg, res, lb.locality); err != nil {
+			return nil, err
+		}
+	}
+	if oCfg.LazyExporters != nil {
+		lb.lazy = true
+		lb.idleTimeout = oCfg.LazyExporters.IdleTimeout
+	}
+	return lb, nil
 }
 
 func (lb *loadBalancerImp) Start(ctx context.Context, host component.Host) error {
 	lb.res.onChange(lb.onBackendChanges)
 	lb.host = host
+	if lb.idleTimeout > 0 {
+		lb.stopIdle = make(chan struct{})
+		lb.idleWg.Add(1)
+		go lb.removeIdleExportersPeriodically()
+	}
 	return lb.res.start(ctx)
 }
 
@@ -110,12 +179,17 @@ func (lb *loadBalancerImp) onBackendChanges(resolved []string) {
 		defer lb.updateLock.Unlock()
 
 		lb.ring = newRing
+		if lb.locality != nil {
+			lb.locality.setEndpoints(resolved)
+		}
 
 		// TODO: set a timeout?
 		ctx := context.Background()
 
-		// add the missing exporters first
-		lb.addMissingExporters(ctx, resolved)
+		// add the missing exporters first, the lazy exporters are created when first routed to
+		if !lb.lazy {
+			lb.addMissingExporters(ctx, resolved)
+		}
 		lb.removeExtraExporters(ctx, resolved)
 	}
 }
@@ -125,21 +199,27 @@ func (lb *loadBalancerImp) addMissingExporters(ctx context.Context, endpoints []
 		endpoint = endpointWithPort(endpoint)
 
 		if _, exists := lb.exporters[endpoint]; !exists {
-			exp, err := lb.componentFactory(ctx, endpoint)
-			if err != nil {
-				lb.logger.Error("failed to create new exporter for endpoint", zap.String("endpoint", endpoint), zap.Error(err))
-				continue
-			}
-
-			if err = exp.Start(ctx, lb.host); err != nil {
-				lb.logger.Error("failed to start new exporter for endpoint", zap.String("endpoint", endpoint), zap.Error(err))
-				continue
+			if _, err := lb.addExporter(ctx, endpoint); err != nil {
+				lb.logger.Error("failed to add new exporter for endpoint", zap.String("endpoint", endpoint), zap.Error(err))
 			}
-			lb.exporters[endpoint] = exp
 		}
 	}
 }
 
+// addExporter creates and starts the exporter of the endpoint, the caller must hold the update lock.
+func (lb *loadBalancerImp) addExporter(ctx context.Context, endpoint string) (component.Exporter, error) {
+	exp, err := lb.componentFactory(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the exporter: %w", err)
+	}
+
+	if err = exp.Start(ctx, lb.host); err != nil {
+		return nil, fmt.Errorf("failed to start the exporter: %w", err)
+	}
+	lb.exporters[endpoint] = exp
+	return exp, nil
+}
+
 func endpointWithPort(endpoint string) string {
 	if !strings.Contains(endpoint, ":") {
 		endpoint = fmt.Sprintf("%s:%s", endpoint, defaultPort)
@@ -154,12 +234,56 @@ func (lb *loadBalancerImp) removeExtraExporters(ctx context.Context, endpoints [
 	}
 	for existing := range lb.exporters {
 		if !endpointFound(existing, endpointsWithPort) {
-			_ = lb.exporters[existing].Shutdown(ctx)
-			delete(lb.exporters, existing)
+			lb.removeExporter(ctx, existing)
 		}
 	}
 }
 
+// removeExporter shuts down the exporter of the endpoint, the caller must hold the update lock.
+func (lb *loadBalancerImp) removeExporter(ctx context.Context, endpoint string) {
+	_ = lb.exporters[endpoint].Shutdown(ctx)
+	delete(lb.exporters, endpoint)
+
+	lb.usageLock.Lock()
+	delete(lb.lastUsed, endpoint)
+	lb.usageLock.Unlock()
+}
+
+func (lb *loadBalancerImp) removeIdleExportersPeriodically() {
+	defer lb.idleWg.Done()
+	ticker := time.NewTicker(lb.idleTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			lb.removeIdleExporters(context.Background())
+		case <-lb.stopIdle:
+			return
+		}
+	}
+}
+
+// removeIdleExporters shuts down the exporters that weren't used for the idle timeout,
+// they are created again when routed to.
+func (lb *loadBalancerImp) removeIdleExporters(ctx context.Context) {
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
+
+	lb.usageLock.Lock()
+	var idle []string
+	for endpoint := range lb.exporters {
+		if lb.now().Sub(lb.lastUsed[endpoint]) >= lb.idleTimeout {
+			idle = append(idle, endpoint)
+		}
+	}
+	lb.usageLock.Unlock()
+
+	for _, endpoint := range idle {
+		lb.logger.Debug("shutting down idle exporter", zap.String("endpoint", endpoint))
+		lb.removeExporter(ctx, endpoint)
+	}
+}
+
 func endpointFound(endpoint string, endpoints []string) bool {
 	for _, candidate := range endpoints {
 		if candidate == endpoint {
@@ -170,25 +294,57 @@ func endpointFound(endpoint string, endpoints []string) bool {
 	return false
 }
 
-func (lb *loadBalancerImp) Shutdown(context.Context) error {
+func (lb *loadBalancerImp) Shutdown(ctx context.Context) error {
 	lb.stopped = true
-	return nil
+	if lb.stopIdle != nil {
+		close(lb.stopIdle)
+		lb.idleWg.Wait()
+	}
+	return lb.res.shutdown(ctx)
 }
 
 func (lb *loadBalancerImp) Endpoint(identifier []byte) string {
 	lb.updateLock.RLock()
 	defer lb.updateLock.RUnlock()
 
+	if lb.locality != nil {
+		// spill over to the other zones only when no local endpoint is healthy
+		if endpoint := lb.locality.endpointFor(identifier); endpoint != "" {
+			return endpoint
+		}
+	}
 	return lb.ring.endpointFor(identifier)
 }
 
+func (lb *loadBalancerImp) IsResolved(endpoint string) bool {
+	lb.updateLock.RLock()
+	defer lb.updateLock.RUnlock()
+
+	return lb.ring.contains(endpoint)
+}
+
+func (lb *loadBalancerImp) ReportFailure(endpoint string) {
+	if lb.locality != nil {
+		lb.locality.markUnhealthy(endpoint)
+	}
+}
+
 func (lb *loadBalancerImp) Exporter(endpoint string) (component.Exporter, error) {
 	// NOTE: make rolling updates of next tier of collectors work. currently, this may cause
 	// data loss because the latest batches sent to outdated backend will never find their way out.
 	// for details: https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/1690
+	key := endpointWithPort(endpoint)
 	lb.updateLock.RLock()
-	exp, found := lb.exporters[endpointWithPort(endpoint)]
+	exp, found := lb.exporters[key]
+	if found && lb.lazy {
+		// the usage is recorded under the update lock, so that an exporter being
+		// returned isn't shut down as idle
+		lb.markUsed(key)
+	}
 	lb.updateLock.RUnlock()
+	if !found && lb.lazy && endpoint != "" {
+		return lb.lazyExporter(key)
+	}
 	if !found {
 		// something is really wrong... how come we couldn't find the exporter??
 		return nil, fmt.Errorf("couldn't find the exporter for the endpoint %q", endpoint)
@@ -196,3 +352,25 @@ func (lb *loadBalancerImp) Exporter(endpoint string) (component.Exporter, error)
 
 	return exp, nil
 }
+
+// lazyExporter returns the exporter of the endpoint, creating it when it doesn't exist yet.
+func (lb *loadBalancerImp) lazyExporter(endpoint string) (component.Exporter, error) {
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
+
+	exp, found := lb.exporters[endpoint]
+	if !found {
+		var err error
+		if exp, err = lb.addExporter(context.Background(), endpoint); err != nil {
+			return nil, fmt.Errorf("couldn't add the exporter for the endpoint %q: %w", endpoint, err)
+		}
+	}
+	lb.markUsed(endpoint)
+	return exp, nil
+}
+
+func (lb *loadBalancerImp) markUsed(endpoint string) {
+	lb.usageLock.Lock()
+	lb.lastUsed[endpoint] = lb.now()
+	lb.usageLock.Unlock()
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -379,6 +380,116 @@ func TestFailedExporterInRing(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestNewLoadBalancerNegativeIdleTimeout(t *testing.T) {
+	// prepare
+	cfg := simpleConfig()
+	cfg.LazyExporters = &LazyExporters{IdleTimeout: -time.Second}
+
+	// test
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, nil)
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errNegativeIdleTimeout, err)
+}
+
+func TestLazyExporters(t *testing.T) {
+	// prepare
+	cfg := simpleConfig()
+	cfg.LazyExporters = &LazyExporters{}
+	var created []string
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		created = append(created, endpoint)
+		return newNopMockExporter(), nil
+	}
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, componentFactory)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	// test
+	p.onBackendChanges([]string{"endpoint-1", "endpoint-2"})
+
+	// verify
+	assert.Empty(t, p.exporters, "the exporters are created when first routed to")
+
+	// test
+	exp, err := p.Exporter("endpoint-1")
+	require.NoError(t, err)
+	again, err := p.Exporter("endpoint-1")
+	require.NoError(t, err)
+
+	// verify
+	assert.Equal(t, exp, again)
+	assert.Equal(t, []string{"endpoint-1:4317"}, created)
+
+	// test
+	p.onBackendChanges([]string{"endpoint-2"})
+
+	// verify
+	assert.Empty(t, p.exporters)
+	assert.Empty(t, p.lastUsed)
+}
+
+func TestLazyExporterCreationFailure(t *testing.T) {
+	// prepare
+	cfg := simpleConfig()
+	cfg.LazyExporters = &LazyExporters{}
+	expectedErr := errors.New("some expected error")
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return nil, expectedErr
+	}
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, componentFactory)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	// test
+	_, err = p.Exporter("endpoint-1")
+
+	// verify
+	assert.ErrorIs(t, err, expectedErr)
+	assert.Empty(t, p.exporters)
+}
+
+func TestRemoveIdleExporters(t *testing.T) {
+	// prepare
+	cfg := simpleConfig()
+	cfg.LazyExporters = &LazyExporters{IdleTimeout: time.Minute}
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return newNopMockExporter(), nil
+	}
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, componentFactory)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, p.Shutdown(context.Background())) }()
+
+	now := time.Now()
+	p.now = func() time.Time { return now }
+	_, err = p.Exporter("endpoint-1")
+	require.NoError(t, err)
+	_, err = p.Exporter("endpoint-2")
+	require.NoError(t, err)
+
+	// test
+	now = now.Add(30 * time.Second)
+	_, err = p.Exporter("endpoint-2")
+	require.NoError(t, err)
+	now = now.Add(30 * time.Second)
+	p.removeIdleExporters(context.Background())
+
+	// verify
+	assert.Len(t, p.exporters, 1)
+	assert.Contains(t, p.exporters, "endpoint-2:4317")
+
+	// test
+	now = now.Add(time.Minute)
+	p.removeIdleExporters(context.Background())
+
+	// verify
+	assert.Empty(t, p.exporters)
+	assert.Empty(t, p.lastUsed)
+}
+
 func newNopMockExporter() component.Exporter {
 	return mockComponent{}
 }
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultUnhealthyDuration = 30 * time.Second
+
+// localityPreference routes the data to the healthy endpoints in the same zone
+// as the exporter, the other endpoints are only used when none is available.
+type localityPreference struct {
+	zone              string
+	endpointZones     map[string]string
+	unhealthyDuration time.Duration
+
+	mu        sync.Mutex
+	local     []string
+	unhealthy map[string]time.Time
+	// ring holds the healthy local endpoints, it is rebuilt on the next lookup when nil
+	// or when the first of the unhealthy endpoints becomes healthy again.
+	ring       *hashRing
+	nextExpiry time.Time
+}
+
+func newLocalityPreference(cfg *LocalitySettings) *localityPreference {
+	endpointZones := make(map[string]string, len(cfg.EndpointZones))
+	for endpoint, zone := range cfg.EndpointZones {
+		endpointZones[endpointWithPort(endpoint)] = zone
+	}
+	unhealthyDuration := cfg.UnhealthyDuration
+	if unhealthyDuration <= 0 {
+		unhealthyDuration = defaultUnhealthyDuration
+	}
+	return &localityPreference{
+		zone:              cfg.Zone,
+		endpointZones:     endpointZones,
+		unhealthyDuration: unhealthyDuration,
+		unhealthy:         map[string]time.Time{},
+	}
+}
+
+// setEndpoints keeps the resolved endpoints in the same zone as the exporter.
+func (l *localityPreference) setEndpoints(endpoints []string) {
+	var local []string
+	for _, endpoint := range endpoints {
+		if l.endpointZones[endpointWithPort(endpoint)] == l.zone {
+			local = append(local, endpoint)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.local = local
+	l.ring = nil
+}
+
+// markUnhealthy excludes the endpoint from the local endpoints for the unhealthy duration.
+func (l *localityPreference) markUnhealthy(endpoint string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.unhealthy[endpointWithPort(endpoint)] = time.Now().Add(l.unhealthyDuration)
+	l.ring = nil
+}
+
+// endpointFor returns the healthy local endpoint responsible for the identifier,
+// or an empty string when no local endpoint is healthy.
+func (l *localityPreference) endpointFor(identifier []byte) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.ring == nil || (!l.nextExpiry.IsZero() && !now.Before(l.nextExpiry)) {
+		l.rebuild(now)
+	}
+	return l.ring.endpointFor(identifier)
+}
+
+func (l *localityPreference) rebuild(now time.Time) {
+	l.nextExpiry = time.Time{}
+	for endpoint, until := range l.unhealthy {
+		if !now.Before(until) {
+			delete(l.unhealthy, endpoint)
+			continue
+		}
+		if l.nextExpiry.IsZero() || until.Before(l.nextExpiry) {
+			l.nextExpiry = until
+		}
+	}
+
+	healthy := make([]string, 0, len(l.local))
+	for _, endpoint := range l.local {
+		if _, found := l.unhealthy[endpointWithPort(endpoint)]; !found {
+			healthy = append(healthy, endpoint)
+		}
+	}
+	l.ring = newHashRing(healthy)
+}
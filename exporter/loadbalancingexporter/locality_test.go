@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func TestLocalityPreferenceLocalEndpoints(t *testing.T) {
+	// prepare
+	l := newLocalityPreference(&LocalitySettings{
+		Zone: "zone-a",
+		EndpointZones: map[string]string{
+			"endpoint-1":      "zone-a",
+			"endpoint-2:4317": "zone-b",
+			"endpoint-3":      "zone-a",
+		},
+	})
+	l.setEndpoints([]string{"endpoint-1", "endpoint-2", "endpoint-3", "endpoint-4"})
+
+	// test and verify
+	for i := 0; i < 100; i++ {
+		endpoint := l.endpointFor([]byte(fmt.Sprintf("trace-%d", i)))
+		assert.Contains(t, []string{"endpoint-1", "endpoint-3"}, endpoint)
+	}
+}
+
+func TestLocalityPreferenceUnhealthyEndpoints(t *testing.T) {
+	// prepare
+	l := newLocalityPreference(&LocalitySettings{
+		Zone:              "zone-a",
+		EndpointZones:     map[string]string{"endpoint-1": "zone-a", "endpoint-2": "zone-a"},
+		UnhealthyDuration: 50 * time.Millisecond,
+	})
+	l.setEndpoints([]string{"endpoint-1", "endpoint-2"})
+
+	// test
+	l.markUnhealthy("endpoint-1")
+
+	// verify
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, "endpoint-2", l.endpointFor([]byte(fmt.Sprintf("trace-%d", i))))
+	}
+
+	l.markUnhealthy("endpoint-2")
+	assert.Equal(t, "", l.endpointFor([]byte("trace")))
+
+	// the endpoints are preferred again once the unhealthy duration elapsed
+	assert.Eventually(t, func() bool {
+		return l.endpointFor([]byte("trace")) != ""
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLoadBalancerLocalitySpillOver(t *testing.T) {
+	// prepare
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		Locality: &LocalitySettings{
+			Zone:          "zone-a",
+			EndpointZones: map[string]string{"endpoint-1": "zone-a", "endpoint-2": "zone-b"},
+		},
+	}
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return newNopMockExporter(), nil
+	}
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, componentFactory)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+	p.onBackendChanges([]string{"endpoint-1:4317", "endpoint-2:4317"})
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// test and verify
+	assert.Equal(t, "endpoint-1:4317", p.Endpoint([]byte("trace")))
+
+	p.ReportFailure("endpoint-1:4317")
+	assert.Equal(t, "endpoint-2:4317", p.Endpoint([]byte("trace")))
+}
@@ -25,6 +25,7 @@ import (
 	"go.opencensus.io/tag"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -37,6 +38,9 @@ var _ component.LogsExporter = (*logExporterImp)(nil)
 
 type logExporterImp struct {
 	loadBalancer loadBalancer
+	routingKey   routingKey
+	// routingAttribute is the resource attribute routed by, unless routed by trace ID.
+	routingAttribute string
 
 	stopped    bool
 	shutdownWg sync.WaitGroup
@@ -44,6 +48,11 @@ type logExporterImp struct {
 
 // Create new logs exporter
 func newLogsExporter(params component.ExporterCreateSettings, cfg component.ExporterConfig) (*logExporterImp, error) {
+	key, attribute, err := parseRoutingKey(cfg.(*Config).RoutingKeys.Logs, traceIDRouting)
+	if err != nil {
+		return nil, err
+	}
+
 	exporterFactory := otlpexporter.NewFactory()
 
 	lb, err := newLoadBalancer(params, cfg, func(ctx context.Context, endpoint string) (component.Exporter, error) {
@@ -55,7 +64,9 @@ func newLogsExporter(params component.ExporterCreateSettings, cfg component.Expo
 	}
 
 	return &logExporterImp{
-		loadBalancer: lb,
+		loadBalancer:     lb,
+		routingKey:       key,
+		routingAttribute: attribute,
 	}, nil
 }
 
@@ -67,13 +78,17 @@ func (e *logExporterImp) Start(ctx context.Context, host component.Host) error {
 	return e.loadBalancer.Start(ctx, host)
 }
 
-func (e *logExporterImp) Shutdown(context.Context) error {
+func (e *logExporterImp) Shutdown(ctx context.Context) error {
 	e.stopped = true
 	e.shutdownWg.Wait()
-	return nil
+	return e.loadBalancer.Shutdown(ctx)
 }
 
 func (e *logExporterImp) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	if e.routingKey != traceIDRouting {
+		return e.consumeLogsByResource(ctx, ld)
+	}
+
 	var errs error
 	batches := batchpersignal.SplitLogs(ld)
 	for _, batch := range batches {
@@ -83,6 +98,33 @@ func (e *logExporterImp) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 	return errs
 }
 
+// consumeLogsByResource routes each resource by the value of its routing attribute, the resources
+// routed to the same endpoint being exported together.
+func (e *logExporterImp) consumeLogsByResource(ctx context.Context, ld plog.Logs) error {
+	batches := map[string]plog.Logs{}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		id, err := routingIdentifierFromResource(rl.Resource(), e.routingKey, e.routingAttribute)
+		if err != nil {
+			return err
+		}
+		endpoint := e.loadBalancer.Endpoint([]byte(id))
+		batch, ok := batches[endpoint]
+		if !ok {
+			batch = plog.NewLogs()
+			batches[endpoint] = batch
+		}
+		rl.CopyTo(batch.ResourceLogs().AppendEmpty())
+	}
+
+	var errs error
+	for endpoint, batch := range batches {
+		errs = multierr.Append(errs, e.consumeLogForEndpoint(ctx, endpoint, batch))
+	}
+	return errs
+}
+
 func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 	traceID := traceIDFromLogs(ld)
 	balancingKey := traceID
@@ -93,7 +135,10 @@ func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 		balancingKey = random()
 	}
 
-	endpoint := e.loadBalancer.Endpoint(balancingKey[:])
+	return e.consumeLogForEndpoint(ctx, e.loadBalancer.Endpoint(balancingKey[:]), ld)
+}
+
+func (e *logExporterImp) consumeLogForEndpoint(ctx context.Context, endpoint string, ld plog.Logs) error {
 	exp, err := e.loadBalancer.Exporter(endpoint)
 	if err != nil {
 		return err
@@ -118,6 +163,9 @@ func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 			ctx,
 			[]tag.Mutator{tag.Upsert(endpointTagKey, endpoint), successFalseMutator},
 			mBackendLatency.M(duration.Milliseconds()))
+		if !consumererror.IsPermanent(err) {
+			e.loadBalancer.ReportFailure(endpoint)
+		}
 	}
 
 	return err
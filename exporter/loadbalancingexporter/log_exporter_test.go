@@ -54,6 +54,15 @@ func TestNewLogsExporter(t *testing.T) {
 			},
 			errNoResolver,
 		},
+		{
+			"unsupported routing key",
+			func() *Config {
+				cfg := simpleConfig()
+				cfg.RoutingKeys.Logs = "spanID"
+				return cfg
+			}(),
+			errors.New("unsupported routing_key: spanID"),
+		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
 			// test
@@ -235,6 +244,63 @@ func TestLogBatchWithTwoTraces(t *testing.T) {
 	assert.Len(t, sink.AllLogs(), 2)
 }
 
+func TestConsumeLogsServiceBased(t *testing.T) {
+	var mu sync.Mutex
+	sinks := map[string]*consumertest.LogsSink{}
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		sink := new(consumertest.LogsSink)
+		sinks[endpoint] = sink
+		return newMockLogsExporter(sink.ConsumeLogs), nil
+	}
+	cfg := simpleConfig()
+	cfg.Resolver.Static.Hostnames = []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+	cfg.RoutingKeys.Logs = "service"
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, componentFactory)
+	require.NoError(t, err)
+
+	p, err := newLogsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, svcRouting, p.routingKey)
+	p.loadBalancer = lb
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// the logs of a service are routed together whatever their trace ID
+	services := []string{"checkout", "cart", "payment", "checkout", "shipping"}
+	batch := plog.NewLogs()
+	for i, svc := range services {
+		rl := batch.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", svc)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().SetTraceID(pcommon.TraceID([16]byte{byte(i)}))
+	}
+
+	// test
+	require.NoError(t, p.ConsumeLogs(context.Background(), batch))
+
+	// verify
+	received := 0
+	for endpoint, sink := range sinks {
+		assert.LessOrEqual(t, len(sink.AllLogs()), 1)
+		for _, ld := range sink.AllLogs() {
+			for i := 0; i < ld.ResourceLogs().Len(); i++ {
+				svc, ok := ld.ResourceLogs().At(i).Resource().Attributes().Get("service.name")
+				require.True(t, ok)
+				assert.Equal(t, endpoint, endpointWithPort(lb.Endpoint([]byte(svc.Str()))))
+				received++
+			}
+		}
+	}
+	assert.Equal(t, len(services), received)
+
+	// a resource without the attribute is rejected
+	assert.Equal(t, errNoServiceName, p.ConsumeLogs(context.Background(), simpleLogs()))
+}
+
 func TestNoLogsInBatch(t *testing.T) {
 	for _, tt := range []struct {
 		desc  string
@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestNewMetricsExporter(t *testing.T) {
+	traceIDRoutingConfig := simpleConfig()
+	traceIDRoutingConfig.RoutingKeys.Metrics = "traceID"
+	attrRoutingConfig := simpleConfig()
+	attrRoutingConfig.RoutingKeys.Metrics = "resource.k8s.namespace.name"
+	// the routing key of the traces doesn't apply to the metrics
+	tracesRoutingConfig := simpleConfig()
+	tracesRoutingConfig.RoutingKey = "traceID"
+
+	for _, tt := range []struct {
+		desc   string
+		config *Config
+		err    error
+	}{
+		{
+			"simple",
+			simpleConfig(),
+			nil,
+		},
+		{
+			"service",
+			serviceBasedRoutingConfig(),
+			nil,
+		},
+		{
+			"traceID",
+			traceIDRoutingConfig,
+			errTraceIDMetricsRouting,
+		},
+		{
+			"resource attribute",
+			attrRoutingConfig,
+			nil,
+		},
+		{
+			"traces routing key",
+			tracesRoutingConfig,
+			nil,
+		},
+		{
+			"empty",
+			&Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+			},
+			errNoResolver,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			_, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), tt.config)
+
+			// verify
+			require.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestConsumeMetricsRoutesByService(t *testing.T) {
+	var mu sync.Mutex
+	sinks := map[string]*consumertest.MetricsSink{}
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		sink := new(consumertest.MetricsSink)
+		sinks[endpoint] = sink
+		return newMockMetricsExporter(sink.ConsumeMetrics), nil
+	}
+	cfg := simpleConfig()
+	cfg.Resolver.Static.Hostnames = []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, componentFactory)
+	require.NoError(t, err)
+
+	p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	p.loadBalancer = lb
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	services := []string{"checkout", "cart", "payment", "checkout", "shipping"}
+	md := pmetric.NewMetrics()
+	for _, svc := range services {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", svc)
+		rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("requests")
+	}
+
+	// test
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+	// verify
+	received := 0
+	for endpoint, sink := range sinks {
+		// the resources routed to the same endpoint are exported together
+		assert.LessOrEqual(t, len(sink.AllMetrics()), 1)
+		for _, batch := range sink.AllMetrics() {
+			for i := 0; i < batch.ResourceMetrics().Len(); i++ {
+				svc, ok := batch.ResourceMetrics().At(i).Resource().Attributes().Get("service.name")
+				require.True(t, ok)
+				assert.Equal(t, endpoint, endpointWithPort(lb.Endpoint([]byte(svc.Str()))))
+				received++
+			}
+		}
+	}
+	assert.Equal(t, len(services), received)
+}
+
+func TestConsumeMetricsRoutesByResourceAttribute(t *testing.T) {
+	var mu sync.Mutex
+	sinks := map[string]*consumertest.MetricsSink{}
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		sink := new(consumertest.MetricsSink)
+		sinks[endpoint] = sink
+		return newMockMetricsExporter(sink.ConsumeMetrics), nil
+	}
+	cfg := simpleConfig()
+	cfg.Resolver.Static.Hostnames = []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+	cfg.RoutingKeys.Metrics = "resource.k8s.namespace.name"
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, componentFactory)
+	require.NoError(t, err)
+
+	p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	p.loadBalancer = lb
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	md := pmetric.NewMetrics()
+	for i := 0; i < 10; i++ {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", fmt.Sprintf("service-%d", i))
+		rm.Resource().Attributes().PutStr("k8s.namespace.name", "shop")
+	}
+
+	// test
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+	// verify: all the services of the namespace are sent together to the same endpoint
+	endpoint := endpointWithPort(lb.Endpoint([]byte("shop")))
+	require.Len(t, sinks[endpoint].AllMetrics(), 1)
+	assert.Equal(t, 10, sinks[endpoint].AllMetrics()[0].ResourceMetrics().Len())
+
+	// a resource without the attribute is rejected
+	md = pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().Resource().Attributes().PutStr("service.name", "checkout")
+	assert.EqualError(t, p.ConsumeMetrics(context.Background(), md), `unable to get the "k8s.namespace.name" resource attribute`)
+}
+
+func TestConsumeMetricsWithoutServiceName(t *testing.T) {
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return newMockMetricsExporter(nil), nil
+	}
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), simpleConfig(), componentFactory)
+	require.NoError(t, err)
+
+	p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), simpleConfig())
+	require.NoError(t, err)
+	p.loadBalancer = lb
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+
+	// test
+	err = p.ConsumeMetrics(context.Background(), md)
+
+	// verify
+	assert.Equal(t, errNoServiceName, err)
+}
+
+func TestConsumeMetricsUnexpectedExporterType(t *testing.T) {
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return newNopMockExporter(), nil
+	}
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), simpleConfig(), componentFactory)
+	require.NoError(t, err)
+
+	p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), simpleConfig())
+	require.NoError(t, err)
+	p.loadBalancer = lb
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().Resource().Attributes().PutStr("service.name", "checkout")
+
+	// test
+	err = p.ConsumeMetrics(context.Background(), md)
+
+	// verify
+	assert.EqualError(t, err, fmt.Sprintf("unable to export metrics, unexpected exporter type: expected *component.MetricsExporter but got %T", newNopMockExporter()))
+}
+
+type mockMetricsExporter struct {
+	component.Component
+	consumemetricsfn func(ctx context.Context, md pmetric.Metrics) error
+}
+
+func (e *mockMetricsExporter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (e *mockMetricsExporter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	if e.consumemetricsfn == nil {
+		return nil
+	}
+	return e.consumemetricsfn(ctx, md)
+}
+
+func newMockMetricsExporter(consumemetricsfn func(ctx context.Context, md pmetric.Metrics) error) component.MetricsExporter {
+	return &mockMetricsExporter{
+		Component:        mockComponent{},
+		consumemetricsfn: consumemetricsfn,
+	}
+}
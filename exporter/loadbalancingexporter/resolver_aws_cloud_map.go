@@ -0,0 +1,255 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+)
+
+var _ resolver = (*cloudMapResolver)(nil)
+
+const (
+	defaultCloudMapInterval = 30 * time.Second
+	defaultCloudMapTimeout  = 5 * time.Second
+)
+
+var (
+	errNoCloudMapNamespace = errors.New("no namespace specified to discover the backends")
+	errNoCloudMapService   = errors.New("no service name specified to discover the backends")
+
+	cloudMapResolverMutator = tag.Upsert(tag.MustNewKey("resolver"), "aws_cloud_map")
+
+	cloudMapResolverSuccessTrueMutators  = []tag.Mutator{cloudMapResolverMutator, successTrueMutator}
+	cloudMapResolverSuccessFalseMutators = []tag.Mutator{cloudMapResolverMutator, successFalseMutator}
+)
+
+// discoveryClient discovers the instances registered in AWS Cloud Map.
+type discoveryClient interface {
+	DiscoverInstancesWithContext(aws.Context, *servicediscovery.DiscoverInstancesInput, ...request.Option) (*servicediscovery.DiscoverInstancesOutput, error)
+}
+
+// cloudMapResolver discovers the backends registered as the instances of an AWS Cloud Map service.
+type cloudMapResolver struct {
+	logger *zap.Logger
+
+	client      discoveryClient
+	input       *servicediscovery.DiscoverInstancesInput
+	port        string
+	resInterval time.Duration
+	resTimeout  time.Duration
+
+	endpoints         []string
+	onChangeCallbacks []func([]string)
+
+	stopCh             chan (struct{})
+	updateLock         sync.Mutex
+	shutdownWg         sync.WaitGroup
+	changeCallbackLock sync.RWMutex
+}
+
+func newCloudMapResolver(logger *zap.Logger, cfg *AWSCloudMapResolver) (*cloudMapResolver, error) {
+	if cfg.NamespaceName == "" {
+		return nil, errNoCloudMapNamespace
+	}
+	if cfg.ServiceName == "" {
+		return nil, errNoCloudMapService
+	}
+	healthStatus := cfg.HealthStatus
+	if healthStatus == "" {
+		healthStatus = servicediscovery.HealthStatusFilterHealthy
+	}
+	if !contains(servicediscovery.HealthStatusFilter_Values(), healthStatus) {
+		return nil, fmt.Errorf("unsupported health status %q, must be one of %q", healthStatus, servicediscovery.HealthStatusFilter_Values())
+	}
+	interval, timeout := cfg.Interval, cfg.Timeout
+	if interval == 0 {
+		interval = defaultCloudMapInterval
+	}
+	if timeout == 0 {
+		timeout = defaultCloudMapTimeout
+	}
+
+	awsCfg := aws.NewConfig().WithCredentialsChainVerboseErrors(true)
+	if cfg.Region != "" {
+		awsCfg = awsCfg.WithRegion(cfg.Region)
+	}
+	sess, err := session.NewSession(awsCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the aws session: %w", err)
+	}
+
+	input := &servicediscovery.DiscoverInstancesInput{
+		NamespaceName: aws.String(cfg.NamespaceName),
+		ServiceName:   aws.String(cfg.ServiceName),
+		HealthStatus:  aws.String(healthStatus),
+	}
+	if len(cfg.QueryParameters) > 0 {
+		input.QueryParameters = aws.StringMap(cfg.QueryParameters)
+	}
+	if cfg.MaxInstances > 0 {
+		input.MaxResults = aws.Int64(int64(cfg.MaxInstances))
+	}
+
+	return &cloudMapResolver{
+		logger:      logger,
+		client:      servicediscovery.New(sess),
+		input:       input,
+		port:        cfg.Port,
+		resInterval: interval,
+		resTimeout:  timeout,
+		stopCh:      make(chan struct{}),
+	}, nil
+}
+
+func (r *cloudMapResolver) start(ctx context.Context) error {
+	if _, err := r.resolve(ctx); err != nil {
+		r.logger.Warn("failed to resolve", zap.Error(err))
+	}
+
+	go r.periodicallyResolve()
+
+	r.logger.Debug("AWS Cloud Map resolver started",
+		zap.String("namespace", aws.StringValue(r.input.NamespaceName)), zap.String("service_name", aws.StringValue(r.input.ServiceName)),
+		zap.Duration("interval", r.resInterval), zap.Duration("timeout", r.resTimeout))
+	return nil
+}
+
+func (r *cloudMapResolver) shutdown(ctx context.Context) error {
+	r.changeCallbackLock.Lock()
+	r.onChangeCallbacks = nil
+	r.changeCallbackLock.Unlock()
+
+	close(r.stopCh)
+	r.shutdownWg.Wait()
+	return nil
+}
+
+func (r *cloudMapResolver) periodicallyResolve() {
+	ticker := time.NewTicker(r.resInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.resTimeout)
+			if _, err := r.resolve(ctx); err != nil {
+				r.logger.Warn("failed to resolve", zap.Error(err))
+			} else {
+				r.logger.Debug("resolved successfully")
+			}
+			cancel()
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+func (r *cloudMapResolver) resolve(ctx context.Context) ([]string, error) {
+	r.shutdownWg.Add(1)
+	defer r.shutdownWg.Done()
+
+	out, err := r.client.DiscoverInstancesWithContext(ctx, r.input)
+	if err != nil {
+		_ = stats.RecordWithTags(ctx, cloudMapResolverSuccessFalseMutators, mNumResolutions.M(1))
+		return nil, err
+	}
+
+	_ = stats.RecordWithTags(ctx, cloudMapResolverSuccessTrueMutators, mNumResolutions.M(1))
+
+	var backends []string
+	for _, instance := range out.Instances {
+		backend, ok := r.endpointFor(instance)
+		if !ok {
+			r.logger.Debug("skipping the instance without ip address", zap.String("instance_id", aws.StringValue(instance.InstanceId)))
+			continue
+		}
+		backends = append(backends, backend)
+	}
+
+	// keep it always in the same order
+	sort.Strings(backends)
+
+	r.updateLock.Lock()
+	if equalStringSlice(r.endpoints, backends) {
+		r.updateLock.Unlock()
+		return backends, nil
+	}
+	// the list has changed!
+	r.endpoints = backends
+	r.updateLock.Unlock()
+	_ = stats.RecordWithTags(ctx, cloudMapResolverSuccessTrueMutators, mNumBackends.M(int64(len(backends))))
+
+	// propagate the change
+	r.changeCallbackLock.RLock()
+	for _, callback := range r.onChangeCallbacks {
+		callback(backends)
+	}
+	r.changeCallbackLock.RUnlock()
+
+	return backends, nil
+}
+
+// endpointFor returns the endpoint of the instance, from its IPv4 address, or its IPv6 address,
+// and the configured port, or the port it was registered with.
+func (r *cloudMapResolver) endpointFor(instance *servicediscovery.HttpInstanceSummary) (string, bool) {
+	ip := aws.StringValue(instance.Attributes["AWS_INSTANCE_IPV4"])
+	if ip == "" {
+		ip = aws.StringValue(instance.Attributes["AWS_INSTANCE_IPV6"])
+	}
+	if ip == "" {
+		return "", false
+	}
+
+	port := r.port
+	if port == "" {
+		port = aws.StringValue(instance.Attributes["AWS_INSTANCE_PORT"])
+	}
+	if port == "" {
+		if net.ParseIP(ip).To4() == nil {
+			return fmt.Sprintf("[%s]", ip), true
+		}
+		return ip, true
+	}
+	return net.JoinHostPort(ip, port), true
+}
+
+func (r *cloudMapResolver) onChange(f func([]string)) {
+	r.changeCallbackLock.Lock()
+	defer r.changeCallbackLock.Unlock()
+	r.onChangeCallbacks = append(r.onChangeCallbacks, f)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
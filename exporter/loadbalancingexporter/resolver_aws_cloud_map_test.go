@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/servicediscovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+)
+
+var _ discoveryClient = (*mockDiscoveryClient)(nil)
+
+type mockDiscoveryClient struct {
+	mu        sync.Mutex
+	inputs    []*servicediscovery.DiscoverInstancesInput
+	instances []*servicediscovery.HttpInstanceSummary
+	err       error
+}
+
+func (m *mockDiscoveryClient) DiscoverInstancesWithContext(_ aws.Context, input *servicediscovery.DiscoverInstancesInput, _ ...request.Option) (*servicediscovery.DiscoverInstancesOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputs = append(m.inputs, input)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &servicediscovery.DiscoverInstancesOutput{Instances: m.instances}, nil
+}
+
+func (m *mockDiscoveryClient) setInstances(instances ...*servicediscovery.HttpInstanceSummary) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instances = instances
+}
+
+func cloudMapInstance(id string, attributes map[string]string) *servicediscovery.HttpInstanceSummary {
+	return &servicediscovery.HttpInstanceSummary{
+		InstanceId: aws.String(id),
+		Attributes: aws.StringMap(attributes),
+	}
+}
+
+func newTestCloudMapResolver(t *testing.T, cfg *AWSCloudMapResolver) (*cloudMapResolver, *mockDiscoveryClient) {
+	res, err := newCloudMapResolver(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	client := &mockDiscoveryClient{}
+	res.client = client
+	return res, client
+}
+
+func TestCloudMapResolve(t *testing.T) {
+	res, client := newTestCloudMapResolver(t, &AWSCloudMapResolver{
+		NamespaceName:   "collectors.local",
+		ServiceName:     "tier-2",
+		QueryParameters: map[string]string{"zone": "eu-west-1a"},
+		MaxInstances:    50,
+	})
+	client.setInstances(
+		cloudMapInstance("b", map[string]string{"AWS_INSTANCE_IPV4": "10.0.0.2", "AWS_INSTANCE_PORT": "4317"}),
+		cloudMapInstance("a", map[string]string{"AWS_INSTANCE_IPV4": "10.0.0.1"}),
+		cloudMapInstance("c", map[string]string{"AWS_INSTANCE_IPV6": "2001:db8::1", "AWS_INSTANCE_PORT": "4317"}),
+		// an instance without ip address can't be a backend
+		cloudMapInstance("d", map[string]string{"AWS_INSTANCE_CNAME": "backend.example.com"}),
+	)
+
+	var notified []string
+	res.onChange(func(endpoints []string) {
+		notified = endpoints
+	})
+
+	resolved, err := res.resolve(context.Background())
+	require.NoError(t, err)
+	expected := []string{"10.0.0.1", "10.0.0.2:4317", "[2001:db8::1]:4317"}
+	assert.Equal(t, expected, resolved)
+	assert.Equal(t, expected, notified)
+
+	require.Len(t, client.inputs, 1)
+	assert.Equal(t, &servicediscovery.DiscoverInstancesInput{
+		NamespaceName:   aws.String("collectors.local"),
+		ServiceName:     aws.String("tier-2"),
+		HealthStatus:    aws.String(servicediscovery.HealthStatusFilterHealthy),
+		QueryParameters: aws.StringMap(map[string]string{"zone": "eu-west-1a"}),
+		MaxResults:      aws.Int64(50),
+	}, client.inputs[0])
+
+	// the callbacks are only notified when the backends changed
+	notified = nil
+	_, err = res.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, notified)
+}
+
+func TestCloudMapResolvePortOverride(t *testing.T) {
+	res, client := newTestCloudMapResolver(t, &AWSCloudMapResolver{NamespaceName: "collectors.local", ServiceName: "tier-2", Port: "55690"})
+	client.setInstances(cloudMapInstance("a", map[string]string{"AWS_INSTANCE_IPV4": "10.0.0.1", "AWS_INSTANCE_PORT": "8080"}))
+
+	resolved, err := res.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1:55690"}, resolved)
+}
+
+func TestCloudMapResolveFailure(t *testing.T) {
+	res, client := newTestCloudMapResolver(t, &AWSCloudMapResolver{NamespaceName: "collectors.local", ServiceName: "tier-2"})
+	client.setInstances(cloudMapInstance("a", map[string]string{"AWS_INSTANCE_IPV4": "10.0.0.1"}))
+	_, err := res.resolve(context.Background())
+	require.NoError(t, err)
+
+	// the backends are kept when the discovery fails
+	var notified []string
+	res.onChange(func(endpoints []string) {
+		notified = endpoints
+	})
+	client.err = errors.New("throttled")
+	_, err = res.resolve(context.Background())
+	assert.EqualError(t, err, "throttled")
+	assert.Nil(t, notified)
+	assert.Equal(t, []string{"10.0.0.1"}, res.endpoints)
+}
+
+func TestCloudMapStartAndShutdown(t *testing.T) {
+	res, client := newTestCloudMapResolver(t, &AWSCloudMapResolver{NamespaceName: "collectors.local", ServiceName: "tier-2"})
+	client.setInstances(cloudMapInstance("a", map[string]string{"AWS_INSTANCE_IPV4": "10.0.0.1"}))
+
+	var notified []string
+	res.onChange(func(endpoints []string) {
+		notified = endpoints
+	})
+	require.NoError(t, res.start(context.Background()))
+	assert.Equal(t, []string{"10.0.0.1"}, notified)
+
+	require.NoError(t, res.shutdown(context.Background()))
+	assert.Len(t, res.onChangeCallbacks, 0)
+}
+
+func TestNewCloudMapResolverInvalidConfig(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		cfg  *AWSCloudMapResolver
+		err  string
+	}{
+		{
+			desc: "no namespace",
+			cfg:  &AWSCloudMapResolver{ServiceName: "tier-2"},
+			err:  errNoCloudMapNamespace.Error(),
+		},
+		{
+			desc: "no service name",
+			cfg:  &AWSCloudMapResolver{NamespaceName: "collectors.local"},
+			err:  errNoCloudMapService.Error(),
+		},
+		{
+			desc: "unknown health status",
+			cfg:  &AWSCloudMapResolver{NamespaceName: "collectors.local", ServiceName: "tier-2", HealthStatus: "SICK"},
+			err:  `unsupported health status "SICK", must be one of ["HEALTHY" "UNHEALTHY" "ALL" "HEALTHY_OR_ELSE_ALL"]`,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			res, err := newCloudMapResolver(zap.NewNop(), tt.cfg)
+			assert.Nil(t, res)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestNewLoadBalancerCloudMapResolver(t *testing.T) {
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			AWSCloudMap: &AWSCloudMapResolver{NamespaceName: "collectors.local", ServiceName: "tier-2"},
+		},
+	}
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &cloudMapResolver{}, p.res)
+
+	cfg.Resolver.DNS = &DNSResolver{Hostname: "service-1"}
+	p, err = newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, nil)
+	assert.Nil(t, p)
+	assert.Equal(t, errMultipleResolversProvided, err)
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+const (
+	// resourceRoutingPrefix prefixes the name of the resource attribute to route by
+	resourceRoutingPrefix = "resource."
+	serviceNameAttribute  = "service.name"
+)
+
+var errNoServiceName = errors.New("unable to get service name")
+
+// parseRoutingKey returns the routing key, and the resource attribute routed by, of the configured value,
+// the default key being used when the value is empty.
+func parseRoutingKey(value string, defaultKey routingKey) (routingKey, string, error) {
+	switch {
+	case value == "":
+		if defaultKey == svcRouting {
+			return svcRouting, serviceNameAttribute, nil
+		}
+		return defaultKey, "", nil
+	case value == "traceID":
+		return traceIDRouting, "", nil
+	case value == "service":
+		return svcRouting, serviceNameAttribute, nil
+	case strings.HasPrefix(value, resourceRoutingPrefix) && len(value) > len(resourceRoutingPrefix):
+		return attrRouting, strings.TrimPrefix(value, resourceRoutingPrefix), nil
+	}
+	return 0, "", fmt.Errorf("unsupported routing_key: %s", value)
+}
+
+// routingIdentifierFromResource returns the value of the resource attribute routed by.
+func routingIdentifierFromResource(res pcommon.Resource, key routingKey, attribute string) (string, error) {
+	v, ok := res.Attributes().Get(attribute)
+	if !ok {
+		if key == svcRouting {
+			return "", errNoServiceName
+		}
+		return "", fmt.Errorf("unable to get the %q resource attribute", attribute)
+	}
+	return v.AsString(), nil
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestParseRoutingKey(t *testing.T) {
+	for _, tt := range []struct {
+		value      string
+		defaultKey routingKey
+		key        routingKey
+		attribute  string
+		err        error
+	}{
+		{"", traceIDRouting, traceIDRouting, "", nil},
+		{"", svcRouting, svcRouting, serviceNameAttribute, nil},
+		{"traceID", svcRouting, traceIDRouting, "", nil},
+		{"service", traceIDRouting, svcRouting, serviceNameAttribute, nil},
+		{"resource.k8s.namespace.name", traceIDRouting, attrRouting, "k8s.namespace.name", nil},
+		{"resource.", traceIDRouting, 0, "", errors.New("unsupported routing_key: resource.")},
+		{"spanID", traceIDRouting, 0, "", errors.New("unsupported routing_key: spanID")},
+	} {
+		t.Run(tt.value, func(t *testing.T) {
+			key, attribute, err := parseRoutingKey(tt.value, tt.defaultKey)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.key, key)
+			assert.Equal(t, tt.attribute, attribute)
+		})
+	}
+}
+
+func TestRoutingIdentifierFromResource(t *testing.T) {
+	res := pcommon.NewResource()
+	res.Attributes().PutStr("service.name", "checkout")
+	res.Attributes().PutInt("tenant.id", 42)
+
+	id, err := routingIdentifierFromResource(res, svcRouting, serviceNameAttribute)
+	assert.NoError(t, err)
+	assert.Equal(t, "checkout", id)
+
+	id, err = routingIdentifierFromResource(res, attrRouting, "tenant.id")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", id)
+
+	_, err = routingIdentifierFromResource(pcommon.NewResource(), svcRouting, serviceNameAttribute)
+	assert.Equal(t, errNoServiceName, err)
+
+	_, err = routingIdentifierFromResource(res, attrRouting, "k8s.pod.name")
+	assert.EqualError(t, err, `unable to get the "k8s.pod.name" resource attribute`)
+}
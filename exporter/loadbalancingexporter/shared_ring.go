@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// sharedRings holds the rings shared by the exporters of the different pipelines, by name.
+var sharedRings = struct {
+	sync.Mutex
+	rings map[string]*sharedRing
+}{rings: map[string]*sharedRing{}}
+
+// sharedRing shares the resolved backends and their health between the load balancers
+// of the exporters configured with the same shared ring, so that the data of all the
+// signals with the same routing key is sent to the same backend.
+type sharedRing struct {
+	name     string
+	resolver resolver
+	locality *localityPreference
+	// settings are the resolver, locality and health check settings of the exporter that created the ring.
+	settings Config
+
+	mu        sync.Mutex
+	members   []*sharedRingMember
+	endpoints []string
+	resolved  bool
+}
+
+// joinSharedRing returns the resolver and the locality preference of the shared ring of the config,
+// creating the ring from the given ones when it doesn't exist yet.
+func joinSharedRing(cfg *Config, res resolver, locality *localityPreference) (resolver, *localityPreference, error) {
+	sharedRings.Lock()
+	defer sharedRings.Unlock()
+
+	settings := Config{Resolver: cfg.Resolver, Locality: cfg.Locality, HealthCheck: cfg.HealthCheck}
+	ring, found := sharedRings.rings[cfg.SharedRing]
+	if !found {
+		ring = &sharedRing{name: cfg.SharedRing, resolver: res, locality: locality, settings: settings}
+		sharedRings.rings[cfg.SharedRing] = ring
+	} else if !reflect.DeepEqual(ring.settings, settings) {
+		return nil, nil, fmt.Errorf("the exporters sharing the ring %q must have the same resolver, locality and health check settings", cfg.SharedRing)
+	}
+	return &sharedRingMember{ring: ring}, ring.locality, nil
+}
+
+// broadcast notifies all the started members of the ring of the resolved backends.
+func (r *sharedRing) broadcast(endpoints []string) {
+	r.mu.Lock()
+	r.endpoints = endpoints
+	r.resolved = true
+	members := append([]*sharedRingMember(nil), r.members...)
+	r.mu.Unlock()
+
+	for _, m := range members {
+		m.notify(endpoints)
+	}
+}
+
+// sharedRingMember is the resolver of a load balancer using a shared ring, the resolver
+// of the ring is started with the first member and shut down with the last one.
+type sharedRingMember struct {
+	ring *sharedRing
+
+	mu        sync.Mutex
+	callbacks []func([]string)
+}
+
+var _ resolver = (*sharedRingMember)(nil)
+
+func (m *sharedRingMember) resolve(ctx context.Context) ([]string, error) {
+	return m.ring.resolver.resolve(ctx)
+}
+
+func (m *sharedRingMember) start(ctx context.Context) error {
+	r := m.ring
+	r.mu.Lock()
+	r.members = append(r.members, m)
+	first := len(r.members) == 1
+	if first {
+		r.resolver.onChange(r.broadcast)
+	}
+	endpoints, resolved := r.endpoints, r.resolved
+	r.mu.Unlock()
+
+	if first {
+		return r.resolver.start(ctx)
+	}
+	// the ring was already resolved for the other members
+	if resolved {
+		m.notify(endpoints)
+	}
+	return nil
+}
+
+func (m *sharedRingMember) shutdown(ctx context.Context) error {
+	r := m.ring
+	r.mu.Lock()
+	for i, member := range r.members {
+		if member == m {
+			r.members = append(r.members[:i], r.members[i+1:]...)
+			break
+		}
+	}
+	last := len(r.members) == 0
+	r.mu.Unlock()
+
+	m.mu.Lock()
+	m.callbacks = nil
+	m.mu.Unlock()
+
+	if !last {
+		return nil
+	}
+	sharedRings.Lock()
+	if sharedRings.rings[r.name] == r {
+		delete(sharedRings.rings, r.name)
+	}
+	sharedRings.Unlock()
+	return r.resolver.shutdown(ctx)
+}
+
+func (m *sharedRingMember) onChange(f func([]string)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callbacks = append(m.callbacks, f)
+}
+
+func (m *sharedRingMember) notify(endpoints []string) {
+	m.mu.Lock()
+	callbacks := make([]func([]string), len(m.callbacks))
+	copy(callbacks, m.callbacks)
+	m.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(endpoints)
+	}
+}
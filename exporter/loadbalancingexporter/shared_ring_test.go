@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func sharedRingConfig(name string) *Config {
+	return &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2", "endpoint-3"}},
+		},
+		Locality: &LocalitySettings{
+			Zone:          "zone-a",
+			EndpointZones: map[string]string{"endpoint-1": "zone-a", "endpoint-2": "zone-a", "endpoint-3": "zone-b"},
+		},
+		SharedRing: name,
+	}
+}
+
+func TestSharedRing(t *testing.T) {
+	// prepare
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return newNopMockExporter(), nil
+	}
+	traces, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), sharedRingConfig("shared"), componentFactory)
+	require.NoError(t, err)
+	logs, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), sharedRingConfig("shared"), componentFactory)
+	require.NoError(t, err)
+	require.Len(t, sharedRings.rings, 1)
+
+	starts := 0
+	shutdowns := 0
+	res := &mockResolver{
+		triggerCallbacks: true,
+		onStart: func(context.Context) error {
+			starts++
+			return nil
+		},
+		onShutdown: func(context.Context) error {
+			shutdowns++
+			return nil
+		},
+		onResolve: func(context.Context) ([]string, error) {
+			return []string{"endpoint-1", "endpoint-2", "endpoint-3"}, nil
+		},
+	}
+	sharedRings.rings["shared"].resolver = res
+
+	// test
+	require.NoError(t, traces.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, logs.Start(context.Background(), componenttest.NewNopHost()))
+
+	// verify
+	assert.Equal(t, 1, starts, "the resolver should be started once")
+	assert.Len(t, traces.exporters, 3)
+	assert.Len(t, logs.exporters, 3, "the backends resolved before the start should be used")
+	for i := 0; i < 100; i++ {
+		id := []byte(fmt.Sprintf("trace-%d", i))
+		assert.Equal(t, traces.Endpoint(id), logs.Endpoint(id))
+	}
+
+	// the backends failing for one signal aren't preferred for the others
+	endpoint := traces.Endpoint([]byte("trace"))
+	traces.ReportFailure(endpoint)
+	assert.NotEqual(t, endpoint, logs.Endpoint([]byte("trace")))
+
+	// the resolver is shut down with the last exporter
+	require.NoError(t, traces.Shutdown(context.Background()))
+	assert.Equal(t, 0, shutdowns)
+	require.NoError(t, logs.Shutdown(context.Background()))
+	assert.Equal(t, 1, shutdowns)
+	assert.Len(t, sharedRings.rings, 0)
+}
+
+func TestSharedRingDifferentSettings(t *testing.T) {
+	// prepare
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), sharedRingConfig("different"), nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	cfg := sharedRingConfig("different")
+	cfg.Locality.Zone = "zone-b"
+
+	// test
+	_, err = newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, nil)
+
+	// verify
+	assert.EqualError(t, err, `the exporters sharing the ring "different" must have the same resolver, locality and health check settings`)
+}
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+const defaultStickinessMaxTraces = 100000
+
+var (
+	errNonPositiveStickinessTTL = errors.New("the ttl of the stickiness must be positive")
+	errNegativeStickinessTraces = errors.New("the max traces of the stickiness must not be negative")
+	errStickinessRoutingKey     = errors.New("the stickiness is only supported with the traceID routing key")
+)
+
+// stickyRoutes remembers the endpoint each trace was first routed to, for the TTL after the last
+// spans of the trace. The least recently seen traces are forgotten first when the cache is full.
+type stickyRoutes struct {
+	ttl       time.Duration
+	maxTraces int
+
+	mu sync.Mutex
+	// routes holds the elements of the order list per trace ID.
+	routes map[string]*list.Element
+	// order holds the routes, the most recently seen first. As the TTL is extended each time a trace
+	// is seen, the routes are also ordered by expiry.
+	order *list.List
+	// now returns the current time, it is replaced by the tests.
+	now func() time.Time
+}
+
+type stickyRoute struct {
+	traceID  string
+	endpoint string
+	expiry   time.Time
+}
+
+func newStickyRoutes(cfg *Stickiness) (*stickyRoutes, error) {
+	if cfg.TTL <= 0 {
+		return nil, errNonPositiveStickinessTTL
+	}
+	if cfg.MaxTraces < 0 {
+		return nil, errNegativeStickinessTraces
+	}
+	maxTraces := cfg.MaxTraces
+	if maxTraces == 0 {
+		maxTraces = defaultStickinessMaxTraces
+	}
+	return &stickyRoutes{
+		ttl:       cfg.TTL,
+		maxTraces: maxTraces,
+		routes:    map[string]*list.Element{},
+		order:     list.New(),
+		now:       time.Now,
+	}, nil
+}
+
+// endpointFor returns the endpoint the trace is routed to. A trace seen within the TTL is routed to
+// the endpoint it was first routed to while this endpoint is valid, otherwise to the endpoint
+// returned by next, which is then remembered for the trace.
+func (s *stickyRoutes) endpointFor(traceID string, valid func(endpoint string) bool, next func() string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.evictExpired(now)
+
+	if elem, ok := s.routes[traceID]; ok {
+		route := elem.Value.(*stickyRoute)
+		if valid(route.endpoint) {
+			route.expiry = now.Add(s.ttl)
+			s.order.MoveToFront(elem)
+			return route.endpoint
+		}
+		s.remove(elem)
+	}
+
+	endpoint := next()
+	if endpoint == "" {
+		return endpoint
+	}
+	s.routes[traceID] = s.order.PushFront(&stickyRoute{traceID: traceID, endpoint: endpoint, expiry: now.Add(s.ttl)})
+	for s.order.Len() > s.maxTraces {
+		s.remove(s.order.Back())
+	}
+	return endpoint
+}
+
+// evictExpired forgets the traces not seen for the TTL, the caller must hold the lock.
+func (s *stickyRoutes) evictExpired(now time.Time) {
+	for elem := s.order.Back(); elem != nil && !now.Before(elem.Value.(*stickyRoute).expiry); elem = s.order.Back() {
+		s.remove(elem)
+	}
+}
+
+func (s *stickyRoutes) remove(elem *list.Element) {
+	s.order.Remove(elem)
+	delete(s.routes, elem.Value.(*stickyRoute).traceID)
+}
+
+func (s *stickyRoutes) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestNewStickyRoutes(t *testing.T) {
+	_, err := newStickyRoutes(&Stickiness{})
+	assert.Equal(t, errNonPositiveStickinessTTL, err)
+
+	_, err = newStickyRoutes(&Stickiness{TTL: time.Minute, MaxTraces: -1})
+	assert.Equal(t, errNegativeStickinessTraces, err)
+
+	s, err := newStickyRoutes(&Stickiness{TTL: time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, defaultStickinessMaxTraces, s.maxTraces)
+}
+
+func TestStickyRoutes(t *testing.T) {
+	s, err := newStickyRoutes(&Stickiness{TTL: time.Minute, MaxTraces: 2})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+
+	resolved := map[string]bool{"endpoint-1": true, "endpoint-2": true}
+	valid := func(endpoint string) bool { return resolved[endpoint] }
+	next := func(endpoint string) func() string {
+		return func() string { return endpoint }
+	}
+
+	assert.Equal(t, "endpoint-1", s.endpointFor("trace-1", valid, next("endpoint-1")))
+	// the trace stays on its endpoint when the ring changes
+	assert.Equal(t, "endpoint-1", s.endpointFor("trace-1", valid, next("endpoint-2")))
+
+	// the TTL is extended each time the trace is seen
+	now = now.Add(50 * time.Second)
+	assert.Equal(t, "endpoint-1", s.endpointFor("trace-1", valid, next("endpoint-2")))
+	now = now.Add(50 * time.Second)
+	assert.Equal(t, "endpoint-1", s.endpointFor("trace-1", valid, next("endpoint-2")))
+
+	// the trace is forgotten after the TTL
+	now = now.Add(time.Minute)
+	assert.Equal(t, "endpoint-2", s.endpointFor("trace-1", valid, next("endpoint-2")))
+	assert.Equal(t, 1, s.len())
+
+	// the trace is routed again when its endpoint isn't resolved anymore
+	delete(resolved, "endpoint-2")
+	assert.Equal(t, "endpoint-1", s.endpointFor("trace-1", valid, next("endpoint-1")))
+
+	// no endpoint is remembered when there is none
+	assert.Equal(t, "", s.endpointFor("trace-2", valid, next("")))
+	assert.Equal(t, 1, s.len())
+}
+
+func TestStickyRoutesMaxTraces(t *testing.T) {
+	s, err := newStickyRoutes(&Stickiness{TTL: time.Minute, MaxTraces: 2})
+	require.NoError(t, err)
+
+	valid := func(string) bool { return true }
+	next := func(endpoint string) func() string {
+		return func() string { return endpoint }
+	}
+
+	s.endpointFor("trace-1", valid, next("endpoint-1"))
+	s.endpointFor("trace-2", valid, next("endpoint-1"))
+	// trace-1 becomes the most recently seen
+	s.endpointFor("trace-1", valid, next("endpoint-1"))
+	s.endpointFor("trace-3", valid, next("endpoint-1"))
+	assert.Equal(t, 2, s.len())
+
+	// trace-2 was forgotten first
+	assert.Equal(t, "endpoint-2", s.endpointFor("trace-2", valid, next("endpoint-2")))
+	assert.Equal(t, "endpoint-1", s.endpointFor("trace-3", valid, next("endpoint-2")))
+}
+
+func TestNewTracesExporterStickiness(t *testing.T) {
+	cfg := simpleConfig()
+	cfg.Stickiness = &Stickiness{}
+	_, err := newTracesExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	assert.Equal(t, errNonPositiveStickinessTTL, err)
+
+	cfg = serviceBasedRoutingConfig()
+	cfg.Stickiness = &Stickiness{TTL: time.Minute}
+	_, err = newTracesExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	assert.Equal(t, errStickinessRoutingKey, err)
+}
+
+func TestConsumeTracesStickiness(t *testing.T) {
+	cfg := simpleConfig()
+	cfg.Stickiness = &Stickiness{TTL: time.Minute}
+
+	received := map[string]int{}
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return newMockTracesExporter(func(ctx context.Context, td ptrace.Traces) error {
+			received[endpoint]++
+			return nil
+		}), nil
+	}
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, componentFactory)
+	require.NoError(t, err)
+
+	p, err := newTracesExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	p.loadBalancer = lb
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// find a trace moving to the new endpoint once it is resolved
+	scaled := newHashRing([]string{"endpoint-1", "endpoint-2"})
+	var traceID [16]byte
+	for scaled.endpointFor(traceID[:]) != "endpoint-2" {
+		traceID[0]++
+	}
+	traces := func() ptrace.Traces {
+		td := ptrace.NewTraces()
+		appendSimpleTraceWithID(td.ResourceSpans().AppendEmpty(), traceID)
+		return td
+	}
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces()))
+	assert.Equal(t, map[string]int{"endpoint-1:4317": 1}, received)
+
+	// the late spans follow the first ones after scaling out
+	lb.onBackendChanges([]string{"endpoint-1", "endpoint-2"})
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces()))
+	assert.Equal(t, map[string]int{"endpoint-1:4317": 2}, received)
+
+	// the trace is routed again once its endpoint is removed
+	lb.onBackendChanges([]string{"endpoint-2"})
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces()))
+	assert.Equal(t, map[string]int{"endpoint-1:4317": 2, "endpoint-2:4317": 1}, received)
+}
@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/multierr"
@@ -38,6 +39,10 @@ var _ component.TracesExporter = (*traceExporterImp)(nil)
 type traceExporterImp struct {
 	loadBalancer loadBalancer
 	routingKey   routingKey
+	// routingAttribute is the resource attribute routed by, unless routed by trace ID.
+	routingAttribute string
+	// sticky is set when the late spans of the traces are routed to the endpoint of their first spans.
+	sticky *stickyRoutes
 
 	stopped    bool
 	shutdownWg sync.WaitGroup
@@ -55,14 +60,23 @@ func newTracesExporter(params component.ExporterCreateSettings, cfg component.Ex
 		return nil, err
 	}
 
-	traceExporter := traceExporterImp{loadBalancer: lb, routingKey: traceIDRouting}
+	traceExporter := traceExporterImp{loadBalancer: lb}
 
-	switch cfg.(*Config).RoutingKey {
-	case "service":
-		traceExporter.routingKey = svcRouting
-	case "traceID", "":
-	default:
-		return nil, fmt.Errorf("unsupported routing_key: %s", cfg.(*Config).RoutingKey)
+	key := cfg.(*Config).RoutingKeys.Traces
+	if key == "" {
+		key = cfg.(*Config).RoutingKey
+	}
+	if traceExporter.routingKey, traceExporter.routingAttribute, err = parseRoutingKey(key, traceIDRouting); err != nil {
+		return nil, err
+	}
+
+	if stickiness := cfg.(*Config).Stickiness; stickiness != nil {
+		if traceExporter.routingKey != traceIDRouting {
+			return nil, errStickinessRoutingKey
+		}
+		if traceExporter.sticky, err = newStickyRoutes(stickiness); err != nil {
+			return nil, err
+		}
 	}
 	return &traceExporter, nil
 }
@@ -82,10 +96,10 @@ func (e *traceExporterImp) Start(ctx context.Context, host component.Host) error
 	return e.loadBalancer.Start(ctx, host)
 }
 
-func (e *traceExporterImp) Shutdown(context.Context) error {
+func (e *traceExporterImp) Shutdown(ctx context.Context) error {
 	e.stopped = true
 	e.shutdownWg.Wait()
-	return nil
+	return e.loadBalancer.Shutdown(ctx)
 }
 
 func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
@@ -100,12 +114,12 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 
 func (e *traceExporterImp) consumeTrace(ctx context.Context, td ptrace.Traces) error {
 	var exp component.Exporter
-	routingIds, err := routingIdentifiersFromTraces(td, e.routingKey)
+	routingIds, err := routingIdentifiersFromTraces(td, e.routingKey, e.routingAttribute)
 	if err != nil {
 		return err
 	}
 	for rid := range routingIds {
-		endpoint := e.loadBalancer.Endpoint([]byte(rid))
+		endpoint := e.endpointFor(rid)
 		exp, err = e.loadBalancer.Exporter(endpoint)
 		if err != nil {
 			return err
@@ -131,12 +145,26 @@ func (e *traceExporterImp) consumeTrace(ctx context.Context, td ptrace.Traces) e
 				ctx,
 				[]tag.Mutator{tag.Upsert(endpointTagKey, endpoint), successFalseMutator},
 				mBackendLatency.M(duration.Milliseconds()))
+			if !consumererror.IsPermanent(err) {
+				e.loadBalancer.ReportFailure(endpoint)
+			}
 		}
 	}
 	return err
 }
 
-func routingIdentifiersFromTraces(td ptrace.Traces, key routingKey) (map[string]bool, error) {
+// endpointFor returns the endpoint of the routing identifier. With the stickiness, a trace stays
+// routed to the endpoint of its first spans while this endpoint is resolved.
+func (e *traceExporterImp) endpointFor(rid string) string {
+	if e.sticky == nil {
+		return e.loadBalancer.Endpoint([]byte(rid))
+	}
+	return e.sticky.endpointFor(rid, e.loadBalancer.IsResolved, func() string {
+		return e.loadBalancer.Endpoint([]byte(rid))
+	})
+}
+
+func routingIdentifiersFromTraces(td ptrace.Traces, key routingKey, attribute string) (map[string]bool, error) {
 	ids := make(map[string]bool)
 	rs := td.ResourceSpans()
 	if rs.Len() == 0 {
@@ -153,13 +181,13 @@ func routingIdentifiersFromTraces(td ptrace.Traces, key routingKey) (map[string]
 		return nil, errors.New("empty spans")
 	}
 
-	if key == svcRouting {
+	if key != traceIDRouting {
 		for i := 0; i < rs.Len(); i++ {
-			svc, ok := rs.At(i).Resource().Attributes().Get("service.name")
-			if !ok {
-				return nil, errors.New("unable to get service name")
+			id, err := routingIdentifierFromResource(rs.At(i).Resource(), key, attribute)
+			if err != nil {
+				return nil, err
 			}
-			ids[svc.Str()] = true
+			ids[id] = true
 		}
 		return ids, nil
 	}
@@ -199,6 +199,48 @@ func TestConsumeTracesServiceBased(t *testing.T) {
 	assert.Nil(t, res)
 }
 
+func TestTracesRoutingKeys(t *testing.T) {
+	for _, tt := range []struct {
+		desc       string
+		routingKey string
+		traces     string
+		key        routingKey
+		attribute  string
+	}{
+		{"default", "", "", traceIDRouting, ""},
+		{"routing_key", "service", "", svcRouting, serviceNameAttribute},
+		{"routing_keys", "", "resource.tenant", attrRouting, "tenant"},
+		{"routing_keys over routing_key", "service", "traceID", traceIDRouting, ""},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg := simpleConfig()
+			cfg.RoutingKey = tt.routingKey
+			cfg.RoutingKeys.Traces = tt.traces
+
+			p, err := newTracesExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.key, p.routingKey)
+			assert.Equal(t, tt.attribute, p.routingAttribute)
+		})
+	}
+}
+
+func TestAttributeBasedRouting(t *testing.T) {
+	batch := ptrace.NewTraces()
+	for _, tenant := range []string{"acme", "globex", "acme"} {
+		rs := batch.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("tenant", tenant)
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4}))
+	}
+
+	res, err := routingIdentifiersFromTraces(batch, attrRouting, "tenant")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"acme": true, "globex": true}, res)
+
+	_, err = routingIdentifiersFromTraces(batch, attrRouting, "k8s.namespace.name")
+	assert.EqualError(t, err, `unable to get the "k8s.namespace.name" resource attribute`)
+}
+
 func TestServiceBasedRoutingForSameTraceId(t *testing.T) {
 	b := pcommon.TraceID([16]byte{1, 2, 3, 4})
 	for _, tt := range []struct {
@@ -221,7 +263,7 @@ func TestServiceBasedRoutingForSameTraceId(t *testing.T) {
 		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
-			res, err := routingIdentifiersFromTraces(tt.batch, tt.routingKey)
+			res, err := routingIdentifiersFromTraces(tt.batch, tt.routingKey, serviceNameAttribute)
 			assert.Equal(t, err, nil)
 			assert.Equal(t, res, tt.res)
 		})
@@ -392,7 +434,7 @@ func TestNoTracesInBatch(t *testing.T) {
 		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
-			res, err := routingIdentifiersFromTraces(tt.batch, tt.routingKey)
+			res, err := routingIdentifiersFromTraces(tt.batch, tt.routingKey, serviceNameAttribute)
 			assert.Equal(t, err, tt.err)
 			assert.Equal(t, res, map[string]bool(nil))
 		})
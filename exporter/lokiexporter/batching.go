@@ -0,0 +1,234 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lokiexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/lokiexporter"
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	tagTenantKey, _ = tag.NewKey("tenant")
+
+	mBatchSize        = stats.Int64("batch_size", "Number of log entries of the batches pushed to the tenant by the adaptive batching", stats.UnitDimensionless)
+	mBatchConcurrency = stats.Int64("batch_concurrency", "Number of batches pushed concurrently to the tenant by the adaptive batching", stats.UnitDimensionless)
+)
+
+// batchState is the current batch size and concurrency of a tenant.
+type batchState struct {
+	size        int
+	concurrency int
+	// epoch is incremented on every decrease, so that the batches pushed concurrently
+	// before the decrease don't decrease the state again when they fail the same way.
+	epoch int
+}
+
+// adaptiveBatcher splits the push requests into batches, and adapts the size and the
+// concurrency of the batches of each tenant to the feedback of Loki: they are increased
+// additively while the pushes succeed, and decreased multiplicatively when Loki rate limits
+// the pushes, fails, or takes too long to respond.
+type adaptiveBatcher struct {
+	settings AdaptiveBatchingSettings
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu     sync.Mutex
+	states map[string]*batchState
+}
+
+func newAdaptiveBatcher(settings AdaptiveBatchingSettings, logger *zap.Logger) *adaptiveBatcher {
+	return &adaptiveBatcher{
+		settings: settings,
+		logger:   logger,
+		now:      time.Now,
+		states:   map[string]*batchState{},
+	}
+}
+
+// send splits the request into batches and pushes them, at most the concurrency of the
+// tenant at a time. No batch is pushed anymore once one failed, and the errors of the
+// failed batches are returned: the whole request is retried then, Loki ignoring the
+// entries it already received.
+func (b *adaptiveBatcher) send(ctx context.Context, tenant string, request *logproto.PushRequest, push func(context.Context, *logproto.PushRequest) error) error {
+	b.mu.Lock()
+	state := b.state(tenant)
+	size, concurrency, epoch := state.size, state.concurrency, state.epoch
+	b.mu.Unlock()
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		errs   error
+		failed bool
+	)
+	sem := make(chan struct{}, concurrency)
+	for _, batch := range splitPushRequest(request, size) {
+		sem <- struct{}{}
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+		if stop {
+			break
+		}
+
+		wg.Add(1)
+		go func(batch *logproto.PushRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			start := b.now()
+			err := push(ctx, batch)
+			b.feedback(ctx, tenant, epoch, err, b.now().Sub(start))
+			if err != nil {
+				mu.Lock()
+				errs = multierr.Append(errs, err)
+				failed = true
+				mu.Unlock()
+			}
+		}(batch)
+	}
+	wg.Wait()
+	return errs
+}
+
+// feedback adapts the batch size and the concurrency of the tenant to the outcome of a push.
+// The errors which aren't caused by an overloaded Loki leave them unchanged.
+func (b *adaptiveBatcher) feedback(ctx context.Context, tenant string, epoch int, err error, latency time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state(tenant)
+	slow := b.settings.LatencyThreshold > 0 && latency > b.settings.LatencyThreshold
+	switch {
+	case isBackpressure(err) || (err == nil && slow):
+		if epoch != state.epoch {
+			// the state was already decreased for the batches pushed at the same time
+			return
+		}
+		state.size = maxInt(b.settings.MinBatchSize, int(float64(state.size)*b.settings.DecreaseFactor))
+		state.concurrency = maxInt(1, int(float64(state.concurrency)*b.settings.DecreaseFactor))
+		state.epoch++
+		b.logger.Debug(
+			"decreased the batch size of the tenant",
+			zap.String("tenant", tenant),
+			zap.Int("size", state.size),
+			zap.Int("concurrency", state.concurrency),
+			zap.Duration("latency", latency),
+			zap.Error(err),
+		)
+	case err == nil:
+		if state.size < b.settings.MaxBatchSize {
+			state.size = minInt(b.settings.MaxBatchSize, state.size+b.settings.IncreaseStep)
+		} else if state.concurrency < b.settings.MaxConcurrency {
+			state.concurrency++
+		} else {
+			return
+		}
+	default:
+		return
+	}
+
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Upsert(tagTenantKey, tenant)},
+		mBatchSize.M(int64(state.size)),
+		mBatchConcurrency.M(int64(state.concurrency)),
+	)
+}
+
+// state returns the state of the tenant, starting with the smallest batches pushed one at a time.
+func (b *adaptiveBatcher) state(tenant string) *batchState {
+	state, ok := b.states[tenant]
+	if !ok {
+		state = &batchState{size: b.settings.MinBatchSize, concurrency: 1}
+		b.states[tenant] = state
+	}
+	return state
+}
+
+// isBackpressure returns whether the error means that Loki is overloaded: the push was rate
+// limited, failed on the server side, or timed out.
+func isBackpressure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code == http.StatusTooManyRequests || statusErr.code >= http.StatusInternalServerError
+	}
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		switch grpcErr.GRPCStatus().Code() {
+		case codes.ResourceExhausted, codes.Unavailable, codes.DeadlineExceeded:
+			return true
+		}
+		return false
+	}
+	var timeoutErr interface{ Timeout() bool }
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &timeoutErr) && timeoutErr.Timeout())
+}
+
+// splitPushRequest splits the request into batches of at most size entries. The streams are
+// split over several batches when needed, keeping the order of their entries.
+func splitPushRequest(request *logproto.PushRequest, size int) []*logproto.PushRequest {
+	var batches []*logproto.PushRequest
+	batch := &logproto.PushRequest{}
+	count := 0
+	for _, stream := range request.Streams {
+		entries := stream.Entries
+		for len(entries) > 0 {
+			n := minInt(size-count, len(entries))
+			part := stream
+			part.Entries = entries[:n]
+			batch.Streams = append(batch.Streams, part)
+			entries = entries[n:]
+			count += n
+			if count == size {
+				batches = append(batches, batch)
+				batch = &logproto.PushRequest{}
+				count = 0
+			}
+		}
+	}
+	if count > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lokiexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestAdaptiveBatcher() *adaptiveBatcher {
+	return newAdaptiveBatcher(AdaptiveBatchingSettings{
+		Enabled:          true,
+		MinBatchSize:     2,
+		MaxBatchSize:     6,
+		MaxConcurrency:   3,
+		IncreaseStep:     2,
+		DecreaseFactor:   0.5,
+		LatencyThreshold: time.Second,
+	}, zap.NewNop())
+}
+
+func TestSplitPushRequest(t *testing.T) {
+	start := time.Unix(1000, 0)
+	request := &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{job="a"}`, Entries: entries(start, "a1", "a2", "a3")},
+		{Labels: `{job="b"}`, Entries: entries(start, "b1", "b2")},
+	}}
+
+	batches := splitPushRequest(request, 2)
+	require.Len(t, batches, 3)
+	require.Len(t, batches[0].Streams, 1)
+	assert.Equal(t, []string{"a1", "a2"}, lines(batches[0].Streams[0]))
+	require.Len(t, batches[1].Streams, 2)
+	assert.Equal(t, `{job="a"}`, batches[1].Streams[0].Labels)
+	assert.Equal(t, []string{"a3"}, lines(batches[1].Streams[0]))
+	assert.Equal(t, `{job="b"}`, batches[1].Streams[1].Labels)
+	assert.Equal(t, []string{"b1"}, lines(batches[1].Streams[1]))
+	require.Len(t, batches[2].Streams, 1)
+	assert.Equal(t, []string{"b2"}, lines(batches[2].Streams[0]))
+
+	// a request smaller than the batch size is sent as is
+	batches = splitPushRequest(request, 10)
+	require.Len(t, batches, 1)
+	assert.Equal(t, request.Streams, batches[0].Streams)
+}
+
+func TestAdaptiveBatcher_feedback(t *testing.T) {
+	b := newTestAdaptiveBatcher()
+	ctx := context.Background()
+	size := func() (int, int) {
+		s := b.state("acme")
+		return s.size, s.concurrency
+	}
+
+	// the batch size grows up to its maximum before the concurrency does
+	for _, expected := range [][2]int{{4, 1}, {6, 1}, {6, 2}, {6, 3}, {6, 3}} {
+		b.feedback(ctx, "acme", b.state("acme").epoch, nil, time.Millisecond)
+		s, c := size()
+		assert.Equal(t, expected, [2]int{s, c})
+	}
+
+	// a rate limit halves both
+	rateLimited := &httpStatusError{code: http.StatusTooManyRequests}
+	epoch := b.state("acme").epoch
+	b.feedback(ctx, "acme", epoch, rateLimited, time.Millisecond)
+	s, c := size()
+	assert.Equal(t, [2]int{3, 1}, [2]int{s, c})
+
+	// the batches pushed at the same time don't decrease it again
+	b.feedback(ctx, "acme", epoch, rateLimited, time.Millisecond)
+	s, c = size()
+	assert.Equal(t, [2]int{3, 1}, [2]int{s, c})
+
+	// a slow push decreases it down to the minimum
+	b.feedback(ctx, "acme", b.state("acme").epoch, nil, 2*time.Second)
+	s, c = size()
+	assert.Equal(t, [2]int{2, 1}, [2]int{s, c})
+
+	// the other errors leave it unchanged
+	b.feedback(ctx, "acme", b.state("acme").epoch, &httpStatusError{code: http.StatusBadRequest}, time.Millisecond)
+	s, c = size()
+	assert.Equal(t, [2]int{2, 1}, [2]int{s, c})
+
+	// the tenants are independent
+	assert.Equal(t, 2, b.state("other").size)
+}
+
+func TestIsBackpressure(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected bool
+	}{
+		{err: nil, expected: false},
+		{err: consumererror.NewLogs(&httpStatusError{code: http.StatusTooManyRequests}, plog.NewLogs()), expected: true},
+		{err: consumererror.NewLogs(&httpStatusError{code: http.StatusBadGateway}, plog.NewLogs()), expected: true},
+		{err: consumererror.NewLogs(&httpStatusError{code: http.StatusBadRequest}, plog.NewLogs()), expected: false},
+		{err: fmt.Errorf("failed to push the logs: %w", status.Error(codes.ResourceExhausted, "limited")), expected: true},
+		{err: fmt.Errorf("failed to push the logs: %w", status.Error(codes.InvalidArgument, "out of order")), expected: false},
+		{err: fmt.Errorf("post: %w", context.DeadlineExceeded), expected: true},
+		{err: errors.New("connection refused"), expected: false},
+	}
+	for _, tC := range testCases {
+		t.Run(fmt.Sprint(tC.err), func(t *testing.T) {
+			assert.Equal(t, tC.expected, isBackpressure(tC.err))
+		})
+	}
+}
+
+func TestPushLogDataAdaptiveBatching(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		pushes  []int
+		limited = true
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encPayload, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		decPayload, err := snappy.Decode(nil, encPayload)
+		require.NoError(t, err)
+		pushRequest := &logproto.PushRequest{}
+		require.NoError(t, proto.Unmarshal(decPayload, pushRequest))
+
+		mu.Lock()
+		defer mu.Unlock()
+		count := 0
+		for _, stream := range pushRequest.Streams {
+			count += len(stream.Entries)
+		}
+		pushes = append(pushes, count)
+		if limited {
+			limited = false
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < 5; i++ {
+		records.AppendEmpty().Body().SetStr(fmt.Sprintf("line %d", i))
+	}
+
+	cfg := &Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: ts.URL,
+		},
+		AdaptiveBatching: AdaptiveBatchingSettings{
+			Enabled:        true,
+			MinBatchSize:   2,
+			MaxBatchSize:   4,
+			MaxConcurrency: 1,
+			IncreaseStep:   2,
+			DecreaseFactor: 0.5,
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	exp, err := newNextExporter(cfg, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	// the first batch is rate limited, the next ones aren't pushed
+	err = exp.pushLogData(context.Background(), ld)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, []int{2}, pushes)
+
+	// the batch size grows with each successful push
+	require.NoError(t, exp.pushLogData(context.Background(), ld))
+	assert.Equal(t, []int{2, 2, 2, 1}, pushes)
+	require.NoError(t, exp.pushLogData(context.Background(), ld))
+	assert.Equal(t, []int{2, 2, 2, 1, 4, 1}, pushes)
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lokiexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/lokiexporter"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// pushCompressor compresses the push requests on top of their snappy payload, it is safe for
+// concurrent use by the batches pushed at the same time.
+type pushCompressor struct {
+	// encoding is the Content-Encoding of the compressed requests.
+	encoding string
+	compress func(payload []byte) ([]byte, error)
+}
+
+// newPushCompressor returns the compressor of the settings, or nil when the requests are only
+// snappy compressed.
+func newPushCompressor(s PushCompressionSettings) (*pushCompressor, error) {
+	switch s.Algorithm {
+	case pushCompressionGzip:
+		level := s.Level
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		writers := sync.Pool{New: func() interface{} {
+			// the level is validated with the config
+			w, _ := gzip.NewWriterLevel(nil, level)
+			return w
+		}}
+		return &pushCompressor{
+			encoding: pushCompressionGzip,
+			compress: func(payload []byte) ([]byte, error) {
+				w := writers.Get().(*gzip.Writer)
+				defer writers.Put(w)
+
+				var buf bytes.Buffer
+				w.Reset(&buf)
+				if _, err := w.Write(payload); err != nil {
+					return nil, err
+				}
+				if err := w.Close(); err != nil {
+					return nil, err
+				}
+				return buf.Bytes(), nil
+			},
+		}, nil
+	case pushCompressionZstd:
+		var opts []zstd.EOption
+		if s.Level > 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(s.Level)))
+		}
+		encoder, err := zstd.NewWriter(nil, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &pushCompressor{
+			encoding: pushCompressionZstd,
+			compress: func(payload []byte) ([]byte, error) {
+				return encoder.EncodeAll(payload, nil), nil
+			},
+		}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lokiexporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func decompress(t *testing.T, encoding string, body []byte) []byte {
+	switch encoding {
+	case pushCompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		body, err = io.ReadAll(r)
+		require.NoError(t, err)
+	case pushCompressionZstd:
+		d, err := zstd.NewReader(nil)
+		require.NoError(t, err)
+		defer d.Close()
+		body, err = d.DecodeAll(body, nil)
+		require.NoError(t, err)
+	}
+	return body
+}
+
+func TestPushCompressor(t *testing.T) {
+	payload := bytes.Repeat([]byte(`{"level":"info","msg":"request served"}`), 100)
+	for _, s := range []PushCompressionSettings{
+		{Algorithm: pushCompressionGzip},
+		{Algorithm: pushCompressionGzip, Level: gzip.BestSpeed},
+		{Algorithm: pushCompressionZstd},
+		{Algorithm: pushCompressionZstd, Level: 19},
+	} {
+		c, err := newPushCompressor(s)
+		require.NoError(t, err)
+		assert.Equal(t, s.Algorithm, c.encoding)
+
+		// the compressor can be reused
+		for i := 0; i < 2; i++ {
+			compressed, err := c.compress(payload)
+			require.NoError(t, err)
+			assert.Less(t, len(compressed), len(payload))
+			assert.Equal(t, payload, decompress(t, s.Algorithm, compressed))
+		}
+	}
+
+	for _, s := range []PushCompressionSettings{{}, {Algorithm: pushCompressionSnappy}} {
+		c, err := newPushCompressor(s)
+		require.NoError(t, err)
+		assert.Nil(t, c)
+	}
+}
+
+func TestPushLogDataCompressed(t *testing.T) {
+	for _, algorithm := range []string{pushCompressionSnappy, pushCompressionGzip, pushCompressionZstd} {
+		t.Run(algorithm, func(t *testing.T) {
+			var encoding string
+			actualPushRequest := &logproto.PushRequest{}
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				encoding = r.Header.Get("Content-Encoding")
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				// the payload is snappy compressed whatever the algorithm
+				decPayload, err := snappy.Decode(nil, decompress(t, encoding, body))
+				require.NoError(t, err)
+				require.NoError(t, proto.Unmarshal(decPayload, actualPushRequest))
+			}))
+			defer ts.Close()
+
+			cfg := &Config{
+				HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL},
+				PushCompression:    PushCompressionSettings{Algorithm: algorithm},
+			}
+			exp, err := NewFactory().CreateLogsExporter(context.Background(), componenttest.NewNopExporterCreateSettings(), cfg)
+			require.NoError(t, err)
+			require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+
+			ld := plog.NewLogs()
+			ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("request served")
+			require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+
+			if algorithm == pushCompressionSnappy {
+				assert.Empty(t, encoding)
+			} else {
+				assert.Equal(t, algorithm, encoding)
+			}
+			require.Len(t, actualPushRequest.Streams, 1)
+			assert.Len(t, actualPushRequest.Streams[0].Entries, 1)
+			assert.NoError(t, exp.Shutdown(context.Background()))
+		})
+	}
+}
@@ -15,12 +15,17 @@
 package lokiexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/lokiexporter"
 
 import (
+	"compress/gzip"
 	"fmt"
 	"net/url"
+	"time"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
 )
 
 // Config defines configuration for Loki exporter.
@@ -30,6 +35,30 @@ type Config struct {
 	exporterhelper.QueueSettings  `mapstructure:"sending_queue"`
 	exporterhelper.RetrySettings  `mapstructure:"retry_on_failure"`
 
+	// LabelExpressions maps Loki label names to OTTL expressions evaluated against each log record,
+	// such as `ConvertCase(severity_text, "lower")`. The result of the expression is used as
+	// the label value. Records for which an expression returns nil, or whose where clause doesn't
+	// match, don't get the label. Label expressions are not supported in legacy mode.
+	LabelExpressions map[string]string `mapstructure:"label_expressions"`
+
+	// StreamRateLimit limits the rate at which log lines are sent to each Loki stream, mirroring
+	// the per stream rate limits enforced by Loki. Stream rate limits are not supported in legacy mode.
+	StreamRateLimit StreamRateLimitSettings `mapstructure:"stream_rate_limit"`
+
+	// GRPC enables pushing the logs to the gRPC endpoint of the Loki distributors instead of the
+	// HTTP push API, for the deployments where only the former is exposed. The HTTP client settings
+	// are then ignored, except for the timeout applied to each push. gRPC is not supported in legacy mode.
+	GRPC *configgrpc.GRPCClientSettings `mapstructure:"grpc"`
+
+	// AdaptiveBatching splits the push requests into batches whose size and concurrency adapt to
+	// the responses of Loki, shrinking on rate limits, server errors and slow pushes, and growing
+	// again while the pushes succeed. Adaptive batching is not supported in legacy mode.
+	AdaptiveBatching AdaptiveBatchingSettings `mapstructure:"adaptive_batching"`
+
+	// PushCompression defines the compression of the HTTP push requests. Push compression is not
+	// supported in legacy mode.
+	PushCompression PushCompressionSettings `mapstructure:"push_compression"`
+
 	// TenantID defines the tenant ID to associate log streams with.
 	// Deprecated: [v0.57.0] use the attribute processor to add a `loki.tenant` hint.
 	// See this component's documentation for more information on how to specify the hint.
@@ -53,13 +82,50 @@ type Config struct {
 }
 
 func (c *Config) Validate() error {
-	if _, err := url.Parse(c.Endpoint); c.Endpoint == "" || err != nil {
+	if c.GRPC != nil {
+		if c.GRPC.Endpoint == "" {
+			return fmt.Errorf("\"grpc.endpoint\" must be set")
+		}
+	} else if _, err := url.Parse(c.Endpoint); c.Endpoint == "" || err != nil {
 		return fmt.Errorf("\"endpoint\" must be a valid URL")
 	}
 
 	// further validation is needed only if we are in legacy mode
 	if !c.isLegacy() {
-		return nil
+		if err := c.StreamRateLimit.validate(); err != nil {
+			return err
+		}
+		if err := c.AdaptiveBatching.validate(); err != nil {
+			return err
+		}
+		if err := c.PushCompression.validate(); err != nil {
+			return err
+		}
+		if c.PushCompression.enabled() && (c.GRPC != nil || c.Compression != "") {
+			return fmt.Errorf("\"push_compression\" can't be used together with \"grpc\" or \"compression\"")
+		}
+		_, err := newLabelExpressions(c.LabelExpressions, component.TelemetrySettings{Logger: zap.NewNop()})
+		return err
+	}
+
+	if len(c.LabelExpressions) > 0 {
+		return fmt.Errorf("\"label_expressions\" can't be used together with the deprecated settings")
+	}
+
+	if c.StreamRateLimit.Enabled {
+		return fmt.Errorf("\"stream_rate_limit\" can't be used together with the deprecated settings")
+	}
+
+	if c.AdaptiveBatching.Enabled {
+		return fmt.Errorf("\"adaptive_batching\" can't be used together with the deprecated settings")
+	}
+
+	if c.GRPC != nil {
+		return fmt.Errorf("\"grpc\" can't be used together with the deprecated settings")
+	}
+
+	if c.PushCompression.enabled() {
+		return fmt.Errorf("\"push_compression\" can't be used together with the deprecated settings")
 	}
 
 	if c.Tenant != nil {
@@ -79,6 +145,148 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// StreamRateLimitSettings defines the client-side rate limit applied to each Loki stream. The log
+// lines exceeding the limit are shed before the push request is sent, as Loki would reject them.
+type StreamRateLimitSettings struct {
+	// Enabled enables the per stream rate limit.
+	Enabled bool `mapstructure:"enabled"`
+
+	// BytesPerSecond is the rate at which log line bytes can be sent to a single stream, like
+	// the `per_stream_rate_limit` Loki limit.
+	BytesPerSecond int `mapstructure:"bytes_per_second"`
+
+	// BurstBytes is the number of log line bytes that can be sent at once to a single stream,
+	// like the `per_stream_rate_limit_burst` Loki limit. Log lines larger than the burst are always shed.
+	BurstBytes int `mapstructure:"burst_bytes"`
+
+	// Shedding defines which entries of a stream are shed when the limit is exceeded, either
+	// `drop_oldest` or `drop_newest`.
+	Shedding string `mapstructure:"shedding"`
+}
+
+const (
+	sheddingDropOldest = "drop_oldest"
+	sheddingDropNewest = "drop_newest"
+)
+
+func (s *StreamRateLimitSettings) validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if s.BytesPerSecond <= 0 {
+		return fmt.Errorf("\"stream_rate_limit.bytes_per_second\" must be positive")
+	}
+	if s.BurstBytes < s.BytesPerSecond {
+		return fmt.Errorf("\"stream_rate_limit.burst_bytes\" must be greater than or equal to \"stream_rate_limit.bytes_per_second\"")
+	}
+	if s.Shedding != sheddingDropOldest && s.Shedding != sheddingDropNewest {
+		return fmt.Errorf("invalid \"stream_rate_limit.shedding\" %q, must be one of '%s', '%s'", s.Shedding, sheddingDropOldest, sheddingDropNewest)
+	}
+	return nil
+}
+
+// PushCompressionSettings defines the compression of the HTTP push requests. The protobuf payload
+// is always snappy compressed, as required by Loki, and the request is then compressed with the
+// gzip or zstd algorithm and sent with the matching Content-Encoding header, to be decompressed by
+// Loki, which supports gzip, or by a gateway in front of it.
+type PushCompressionSettings struct {
+	// Algorithm is the algorithm the push requests are compressed with: `snappy` (default) only
+	// compresses the payload, `gzip` and `zstd` also compress the request.
+	Algorithm string `mapstructure:"algorithm"`
+
+	// Level is the compression level of the algorithm, from 1 (fastest) to 9 (best compression)
+	// for gzip, and from 1 (fastest) to 22 (best compression) for zstd. Zero uses the default
+	// level of the algorithm.
+	Level int `mapstructure:"level"`
+}
+
+const (
+	pushCompressionSnappy = "snappy"
+	pushCompressionGzip   = "gzip"
+	pushCompressionZstd   = "zstd"
+)
+
+// enabled returns whether the push requests are compressed on top of their snappy payload.
+func (s *PushCompressionSettings) enabled() bool {
+	return s.Algorithm != "" && s.Algorithm != pushCompressionSnappy
+}
+
+func (s *PushCompressionSettings) validate() error {
+	maxLevel := 0
+	switch s.Algorithm {
+	case "", pushCompressionSnappy:
+	case pushCompressionGzip:
+		maxLevel = gzip.BestCompression
+	case pushCompressionZstd:
+		maxLevel = 22
+	default:
+		return fmt.Errorf("invalid \"push_compression.algorithm\" %q, must be one of '%s', '%s', '%s'", s.Algorithm, pushCompressionSnappy, pushCompressionGzip, pushCompressionZstd)
+	}
+	if s.Level < 0 || s.Level > maxLevel {
+		if maxLevel == 0 {
+			return fmt.Errorf("\"push_compression.level\" can't be set for the '%s' algorithm", pushCompressionSnappy)
+		}
+		return fmt.Errorf("\"push_compression.level\" must be between 1 and %d for the '%s' algorithm", maxLevel, s.Algorithm)
+	}
+	return nil
+}
+
+// AdaptiveBatchingSettings defines how the push requests are split into batches, and how the size
+// and the concurrency of the batches follow the feedback of Loki, additive increase and
+// multiplicative decrease (AIMD) like TCP congestion control.
+type AdaptiveBatchingSettings struct {
+	// Enabled enables the adaptive batching.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MinBatchSize is the number of log entries of the smallest batch, and of the first batches sent
+	// to a tenant.
+	MinBatchSize int `mapstructure:"min_batch_size"`
+
+	// MaxBatchSize is the number of log entries of the largest batch.
+	MaxBatchSize int `mapstructure:"max_batch_size"`
+
+	// MaxConcurrency is the maximum number of batches of a tenant pushed at the same time. Batches
+	// are pushed concurrently only once the batch size reached its maximum, which requires Loki to
+	// accept out of order writes for the streams spread over several batches.
+	MaxConcurrency int `mapstructure:"max_concurrency"`
+
+	// IncreaseStep is the number of log entries added to the batch size after a successful push.
+	IncreaseStep int `mapstructure:"increase_step"`
+
+	// DecreaseFactor is the factor applied to the batch size and the concurrency when Loki responds
+	// with a rate limit (429) or a server error (5xx), or when the push is too slow.
+	DecreaseFactor float64 `mapstructure:"decrease_factor"`
+
+	// LatencyThreshold is the duration above which a successful push is considered too slow, and
+	// handled like a rate limit. Zero ignores the latency.
+	LatencyThreshold time.Duration `mapstructure:"latency_threshold"`
+}
+
+func (s *AdaptiveBatchingSettings) validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if s.MinBatchSize <= 0 {
+		return fmt.Errorf("\"adaptive_batching.min_batch_size\" must be positive")
+	}
+	if s.MaxBatchSize < s.MinBatchSize {
+		return fmt.Errorf("\"adaptive_batching.max_batch_size\" must be greater than or equal to \"adaptive_batching.min_batch_size\"")
+	}
+	if s.MaxConcurrency <= 0 {
+		return fmt.Errorf("\"adaptive_batching.max_concurrency\" must be positive")
+	}
+	if s.IncreaseStep <= 0 {
+		return fmt.Errorf("\"adaptive_batching.increase_step\" must be positive")
+	}
+	if s.DecreaseFactor <= 0 || s.DecreaseFactor >= 1 {
+		return fmt.Errorf("\"adaptive_batching.decrease_factor\" must be between 0 and 1, exclusive")
+	}
+	if s.LatencyThreshold < 0 {
+		return fmt.Errorf("\"adaptive_batching.latency_threshold\" must not be negative")
+	}
+	return nil
+}
+
 func (c *Config) isLegacy() bool {
 	if c.Format != nil && *c.Format == "body" {
 		return true
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
@@ -71,6 +72,102 @@ func TestLoadConfigNewExporter(t *testing.T) {
 					NumConsumers: 2,
 					QueueSize:    10,
 				},
+				StreamRateLimit:  defaultStreamRateLimitSettings(),
+				AdaptiveBatching: defaultAdaptiveBatchingSettings(),
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "label_expressions"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Endpoint:        "https://loki:3100/loki/api/v1/push",
+					Timeout:         30 * time.Second,
+					Headers:         map[string]string{},
+					WriteBufferSize: 512 * 1024,
+				},
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				StreamRateLimit:  defaultStreamRateLimitSettings(),
+				AdaptiveBatching: defaultAdaptiveBatchingSettings(),
+				LabelExpressions: map[string]string{
+					"level":   `ConvertCase(severity_text, "lower")`,
+					"service": `Concat([resource.attributes["service.namespace"], resource.attributes["service.name"]], "/")`,
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "stream_rate_limit"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Endpoint:        "https://loki:3100/loki/api/v1/push",
+					Timeout:         30 * time.Second,
+					Headers:         map[string]string{},
+					WriteBufferSize: 512 * 1024,
+				},
+				RetrySettings: exporterhelper.NewDefaultRetrySettings(),
+				QueueSettings: exporterhelper.NewDefaultQueueSettings(),
+				StreamRateLimit: StreamRateLimitSettings{
+					Enabled:        true,
+					BytesPerSecond: 1 << 20,
+					BurstBytes:     4 << 20,
+					Shedding:       sheddingDropOldest,
+				},
+				AdaptiveBatching: defaultAdaptiveBatchingSettings(),
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "adaptive_batching"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Endpoint:        "https://loki:3100/loki/api/v1/push",
+					Timeout:         30 * time.Second,
+					Headers:         map[string]string{},
+					WriteBufferSize: 512 * 1024,
+				},
+				RetrySettings:   exporterhelper.NewDefaultRetrySettings(),
+				QueueSettings:   exporterhelper.NewDefaultQueueSettings(),
+				StreamRateLimit: defaultStreamRateLimitSettings(),
+				AdaptiveBatching: AdaptiveBatchingSettings{
+					Enabled:          true,
+					MinBatchSize:     500,
+					MaxBatchSize:     20000,
+					MaxConcurrency:   8,
+					IncreaseStep:     500,
+					DecreaseFactor:   0.7,
+					LatencyThreshold: 2 * time.Second,
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "grpc"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Timeout:         30 * time.Second,
+					Headers:         map[string]string{},
+					WriteBufferSize: 512 * 1024,
+				},
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				StreamRateLimit:  defaultStreamRateLimitSettings(),
+				AdaptiveBatching: defaultAdaptiveBatchingSettings(),
+				GRPC: &configgrpc.GRPCClientSettings{
+					Endpoint: "loki-distributor:9095",
+					TLSSetting: configtls.TLSClientSetting{
+						TLSSetting: configtls.TLSSetting{
+							CAFile: "/var/lib/mycert.pem",
+						},
+					},
+					Keepalive: &configgrpc.KeepaliveClientConfig{
+						Time:    30 * time.Second,
+						Timeout: 10 * time.Second,
+					},
+					WriteBufferSize: 512 * 1024,
+					Headers:         map[string]string{"X-Custom-Header": "loki_rocks"},
+				},
 			},
 		},
 	}
@@ -90,6 +187,219 @@ func TestLoadConfigNewExporter(t *testing.T) {
 	}
 }
 
+func TestStreamRateLimitValidate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		settings StreamRateLimitSettings
+		err      string
+	}{
+		{
+			desc:     "disabled",
+			settings: StreamRateLimitSettings{},
+		},
+		{
+			desc:     "enabled",
+			settings: StreamRateLimitSettings{Enabled: true, BytesPerSecond: 10, BurstBytes: 10, Shedding: sheddingDropOldest},
+		},
+		{
+			desc:     "no rate",
+			settings: StreamRateLimitSettings{Enabled: true, BurstBytes: 10, Shedding: sheddingDropNewest},
+			err:      "\"stream_rate_limit.bytes_per_second\" must be positive",
+		},
+		{
+			desc:     "burst lower than the rate",
+			settings: StreamRateLimitSettings{Enabled: true, BytesPerSecond: 10, BurstBytes: 5, Shedding: sheddingDropNewest},
+			err:      "\"stream_rate_limit.burst_bytes\" must be greater than or equal to \"stream_rate_limit.bytes_per_second\"",
+		},
+		{
+			desc:     "unknown shedding",
+			settings: StreamRateLimitSettings{Enabled: true, BytesPerSecond: 10, BurstBytes: 10, Shedding: "drop_random"},
+			err:      "invalid \"stream_rate_limit.shedding\" \"drop_random\", must be one of 'drop_oldest', 'drop_newest'",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cfg := &Config{
+				HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "https://loki.example.com"},
+				StreamRateLimit:    tC.settings,
+			}
+			err := cfg.Validate()
+			if tC.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tC.err)
+		})
+	}
+
+	cfg := &Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "https://loki.example.com"},
+		TenantID:           stringp("acme"),
+		StreamRateLimit:    StreamRateLimitSettings{Enabled: true},
+	}
+	assert.EqualError(t, cfg.Validate(), "\"stream_rate_limit\" can't be used together with the deprecated settings")
+}
+
+func TestPushCompressionValidate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		settings PushCompressionSettings
+		err      string
+	}{
+		{
+			desc: "default",
+		},
+		{
+			desc:     "snappy",
+			settings: PushCompressionSettings{Algorithm: pushCompressionSnappy},
+		},
+		{
+			desc:     "gzip with level",
+			settings: PushCompressionSettings{Algorithm: pushCompressionGzip, Level: 9},
+		},
+		{
+			desc:     "zstd with level",
+			settings: PushCompressionSettings{Algorithm: pushCompressionZstd, Level: 22},
+		},
+		{
+			desc:     "unknown algorithm",
+			settings: PushCompressionSettings{Algorithm: "lz4"},
+			err:      "invalid \"push_compression.algorithm\" \"lz4\", must be one of 'snappy', 'gzip', 'zstd'",
+		},
+		{
+			desc:     "snappy with level",
+			settings: PushCompressionSettings{Level: 3},
+			err:      "\"push_compression.level\" can't be set for the 'snappy' algorithm",
+		},
+		{
+			desc:     "gzip level too high",
+			settings: PushCompressionSettings{Algorithm: pushCompressionGzip, Level: 10},
+			err:      "\"push_compression.level\" must be between 1 and 9 for the 'gzip' algorithm",
+		},
+		{
+			desc:     "negative zstd level",
+			settings: PushCompressionSettings{Algorithm: pushCompressionZstd, Level: -1},
+			err:      "\"push_compression.level\" must be between 1 and 22 for the 'zstd' algorithm",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cfg := &Config{
+				HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "https://loki.example.com"},
+				PushCompression:    tC.settings,
+			}
+			err := cfg.Validate()
+			if tC.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tC.err)
+		})
+	}
+
+	cfg := &Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "https://loki.example.com", Compression: "gzip"},
+		PushCompression:    PushCompressionSettings{Algorithm: pushCompressionZstd},
+	}
+	assert.EqualError(t, cfg.Validate(), "\"push_compression\" can't be used together with \"grpc\" or \"compression\"")
+
+	cfg = &Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "https://loki.example.com"},
+		TenantID:           stringp("acme"),
+		PushCompression:    PushCompressionSettings{Algorithm: pushCompressionZstd},
+	}
+	assert.EqualError(t, cfg.Validate(), "\"push_compression\" can't be used together with the deprecated settings")
+}
+
+func TestAdaptiveBatchingValidate(t *testing.T) {
+	valid := func() AdaptiveBatchingSettings {
+		s := defaultAdaptiveBatchingSettings()
+		s.Enabled = true
+		return s
+	}
+	testCases := []struct {
+		desc   string
+		modify func(*AdaptiveBatchingSettings)
+		err    string
+	}{
+		{
+			desc:   "disabled",
+			modify: func(s *AdaptiveBatchingSettings) { *s = AdaptiveBatchingSettings{} },
+		},
+		{
+			desc:   "enabled",
+			modify: func(*AdaptiveBatchingSettings) {},
+		},
+		{
+			desc:   "no min batch size",
+			modify: func(s *AdaptiveBatchingSettings) { s.MinBatchSize = 0 },
+			err:    "\"adaptive_batching.min_batch_size\" must be positive",
+		},
+		{
+			desc:   "max batch size lower than the min",
+			modify: func(s *AdaptiveBatchingSettings) { s.MaxBatchSize = 10 },
+			err:    "\"adaptive_batching.max_batch_size\" must be greater than or equal to \"adaptive_batching.min_batch_size\"",
+		},
+		{
+			desc:   "no concurrency",
+			modify: func(s *AdaptiveBatchingSettings) { s.MaxConcurrency = 0 },
+			err:    "\"adaptive_batching.max_concurrency\" must be positive",
+		},
+		{
+			desc:   "no increase",
+			modify: func(s *AdaptiveBatchingSettings) { s.IncreaseStep = 0 },
+			err:    "\"adaptive_batching.increase_step\" must be positive",
+		},
+		{
+			desc:   "no decrease",
+			modify: func(s *AdaptiveBatchingSettings) { s.DecreaseFactor = 1 },
+			err:    "\"adaptive_batching.decrease_factor\" must be between 0 and 1, exclusive",
+		},
+		{
+			desc:   "negative latency threshold",
+			modify: func(s *AdaptiveBatchingSettings) { s.LatencyThreshold = -time.Second },
+			err:    "\"adaptive_batching.latency_threshold\" must not be negative",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			settings := valid()
+			tC.modify(&settings)
+			cfg := &Config{
+				HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "https://loki.example.com"},
+				AdaptiveBatching:   settings,
+			}
+			err := cfg.Validate()
+			if tC.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tC.err)
+		})
+	}
+
+	cfg := &Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: "https://loki.example.com"},
+		TenantID:           stringp("acme"),
+		AdaptiveBatching:   valid(),
+	}
+	assert.EqualError(t, cfg.Validate(), "\"adaptive_batching\" can't be used together with the deprecated settings")
+}
+
+func TestGRPCValidate(t *testing.T) {
+	cfg := &Config{GRPC: &configgrpc.GRPCClientSettings{Endpoint: "loki-distributor:9095"}}
+	assert.NoError(t, cfg.Validate())
+
+	cfg = &Config{GRPC: &configgrpc.GRPCClientSettings{}}
+	assert.EqualError(t, cfg.Validate(), "\"grpc.endpoint\" must be set")
+
+	cfg = &Config{
+		GRPC:     &configgrpc.GRPCClientSettings{Endpoint: "loki-distributor:9095"},
+		TenantID: stringp("acme"),
+	}
+	assert.EqualError(t, cfg.Validate(), "\"grpc\" can't be used together with the deprecated settings")
+}
+
 func TestIsLegacy(t *testing.T) {
 	testCases := []struct {
 		desc    string
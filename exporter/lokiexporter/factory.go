@@ -16,7 +16,9 @@ package lokiexporter // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"context"
+	"fmt"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 )
 
@@ -44,6 +46,10 @@ func createLogsExporter(ctx context.Context, set component.ExporterCreateSetting
 		return nil, err
 	}
 
+	if err := view.Register(MetricViews()...); err != nil {
+		return nil, fmt.Errorf("cannot register Loki exporter metric views: %w", err)
+	}
+
 	if expCfg.isLegacy() {
 		return createLegacyLogsExporter(ctx, set, expCfg)
 	}
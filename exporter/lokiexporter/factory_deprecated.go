@@ -34,8 +34,20 @@ func createDefaultLegacyConfig() component.ExporterConfig {
 			// We almost read 0 bytes, so no need to tune ReadBufferSize.
 			WriteBufferSize: 512 * 1024,
 		},
-		RetrySettings: exporterhelper.NewDefaultRetrySettings(),
-		QueueSettings: exporterhelper.NewDefaultQueueSettings(),
+		RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+		QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+		StreamRateLimit:  defaultStreamRateLimitSettings(),
+		AdaptiveBatching: defaultAdaptiveBatchingSettings(),
+	}
+}
+
+// defaultStreamRateLimitSettings returns the disabled stream rate limit, with the
+// same defaults as the per stream rate limit of Loki.
+func defaultStreamRateLimitSettings() StreamRateLimitSettings {
+	return StreamRateLimitSettings{
+		BytesPerSecond: 3 << 20,
+		BurstBytes:     15 << 20,
+		Shedding:       sheddingDropNewest,
 	}
 }
 
@@ -55,3 +67,15 @@ func createLegacyLogsExporter(ctx context.Context, set component.ExporterCreateS
 		exporterhelper.WithShutdown(exp.stop),
 	)
 }
+
+// defaultAdaptiveBatchingSettings returns the disabled adaptive batching.
+func defaultAdaptiveBatchingSettings() AdaptiveBatchingSettings {
+	return AdaptiveBatchingSettings{
+		MinBatchSize:     100,
+		MaxBatchSize:     10000,
+		MaxConcurrency:   4,
+		IncreaseStep:     100,
+		DecreaseFactor:   0.5,
+		LatencyThreshold: 5 * time.Second,
+	}
+}
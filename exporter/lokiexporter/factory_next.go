@@ -18,11 +18,15 @@ import (
 	"context"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 func createNextLogsExporter(ctx context.Context, set component.ExporterCreateSettings, cfg *Config) (component.LogsExporter, error) {
-	exp := newNextExporter(cfg, set.TelemetrySettings)
+	exp, err := newNextExporter(cfg, set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
 
 	return exporterhelper.NewLogsExporter(
 		ctx,
@@ -35,5 +39,7 @@ func createNextLogsExporter(ctx context.Context, set component.ExporterCreateSet
 		exporterhelper.WithQueue(cfg.QueueSettings),
 		exporterhelper.WithStart(exp.start),
 		exporterhelper.WithShutdown(exp.stop),
+		// the label expressions add their results to the log records
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: exp.labels != nil}),
 	)
 }
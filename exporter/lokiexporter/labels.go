@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lokiexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/lokiexporter"
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+const hintAttributes = "loki.attribute.labels"
+
+// labelFunctions returns the OTTL functions that can be used in label expressions.
+// Only functions returning a value are meaningful here, functions mutating the
+// telemetry are intentionally left out.
+func labelFunctions() map[string]interface{} {
+	return map[string]interface{}{
+		"Concat":      ottlfuncs.Concat[ottllog.TransformContext],
+		"ConvertCase": ottlfuncs.ConvertCase[ottllog.TransformContext],
+		"Int":         ottlfuncs.Int[ottllog.TransformContext],
+	}
+}
+
+type labelExpression struct {
+	name      string
+	statement *ottl.Statement[ottllog.TransformContext]
+}
+
+// labelExpressions evaluates the configured OTTL label expressions for every log
+// record and promotes the results to Loki labels using the attribute labels hint.
+type labelExpressions struct {
+	expressions []labelExpression
+}
+
+func newLabelExpressions(expressions map[string]string, settings component.TelemetrySettings) (*labelExpressions, error) {
+	if len(expressions) == 0 {
+		return nil, nil
+	}
+
+	// sort the label names, so that the evaluation order is stable
+	names := make([]string, 0, len(expressions))
+	for name := range expressions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parser := ottllog.NewParser(labelFunctions(), settings)
+	le := &labelExpressions{}
+	for _, name := range names {
+		if !model.LabelName(name).IsValid() {
+			return nil, fmt.Errorf("invalid label name %q in label_expressions", name)
+		}
+		statements, err := parser.ParseStatements([]string{expressions[name]})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse label expression for %q: %w", name, err)
+		}
+		le.expressions = append(le.expressions, labelExpression{name: name, statement: statements[0]})
+	}
+	return le, nil
+}
+
+// apply adds the expression results as record attributes, hinted to be converted
+// into labels. Expressions returning nil, or whose condition does not match, do not
+// produce a label. An attribute already holding the name of a label is left as is,
+// its value is used as the label value.
+//
+// The logs are modified in place, the exporter declares it mutates the data. apply
+// is idempotent, so that the logs can be converted again when the push is retried.
+func (le *labelExpressions) apply(ctx context.Context, ld plog.Logs) error {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			logs := sl.LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				if err := le.applyToRecord(ctx, ottllog.NewTransformContext(logs.At(k), sl.Scope(), rl.Resource())); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (le *labelExpressions) applyToRecord(ctx context.Context, tCtx ottllog.TransformContext) error {
+	labels := make(map[string]string, len(le.expressions))
+	for _, e := range le.expressions {
+		val, matched, err := e.statement.Execute(ctx, tCtx)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate label expression for %q: %w", e.name, err)
+		}
+		if !matched || val == nil {
+			continue
+		}
+		labels[e.name] = fmt.Sprint(val)
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+
+	attrs := tCtx.GetLogRecord().Attributes()
+	var hint []string
+	hinted := map[string]bool{}
+	if existing, found := attrs.Get(hintAttributes); found {
+		switch existing.Type() {
+		case pcommon.ValueTypeSlice:
+			for _, name := range existing.Slice().AsRaw() {
+				hint = append(hint, fmt.Sprint(name))
+			}
+		default:
+			if existing.AsString() != "" {
+				hint = append(hint, strings.Split(existing.AsString(), ",")...)
+			}
+		}
+	}
+	for _, name := range hint {
+		hinted[strings.TrimSpace(name)] = true
+	}
+	for _, e := range le.expressions {
+		value, ok := labels[e.name]
+		if !ok {
+			continue
+		}
+		if _, found := attrs.Get(e.name); !found {
+			attrs.PutStr(e.name, value)
+		}
+		if !hinted[e.name] {
+			hint = append(hint, e.name)
+			hinted[e.name] = true
+		}
+	}
+	attrs.PutStr(hintAttributes, strings.Join(hint, ","))
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lokiexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/loki"
+)
+
+func TestNewLabelExpressions(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		expressions map[string]string
+		expectedErr string
+	}{
+		{
+			desc: "no expressions",
+		},
+		{
+			desc: "valid expressions",
+			expressions: map[string]string{
+				"level":   `ConvertCase(severity_text, "lower")`,
+				"service": `Concat([resource.attributes["service.namespace"], resource.attributes["service.name"]], "/")`,
+			},
+		},
+		{
+			desc: "invalid label name",
+			expressions: map[string]string{
+				"1level": `ConvertCase(severity_text, "lower")`,
+			},
+			expectedErr: `invalid label name "1level"`,
+		},
+		{
+			desc: "unknown function",
+			expressions: map[string]string{
+				"level": `set(attributes["level"], "info")`,
+			},
+			expectedErr: `failed to parse label expression for "level"`,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			le, err := newLabelExpressions(tC.expressions, componenttest.NewNopTelemetrySettings())
+			if tC.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tC.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			if len(tC.expressions) == 0 {
+				assert.Nil(t, le)
+			} else {
+				assert.Len(t, le.expressions, len(tC.expressions))
+			}
+		})
+	}
+}
+
+func TestLabelExpressionsApply(t *testing.T) {
+	le, err := newLabelExpressions(map[string]string{
+		"level":   `ConvertCase(severity_text, "lower")`,
+		"service": `Concat([resource.attributes["service.namespace"], resource.attributes["service.name"]], "/")`,
+		"user":    `Concat([attributes["user.id"]], "") where attributes["user.id"] != nil`,
+	}, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.namespace", "shop")
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetSeverityText("ERROR")
+	lr.Attributes().PutStr("loki.attribute.labels", "http.method")
+	lr.Attributes().PutStr("http.method", "GET")
+	// an existing attribute isn't overwritten by the label expression
+	lr.Attributes().PutStr("service", "payments")
+
+	require.NoError(t, le.apply(context.Background(), ld))
+	hint, _ := lr.Attributes().Get("loki.attribute.labels")
+	assert.Equal(t, "http.method,level,service", hint.Str())
+	service, _ := lr.Attributes().Get("service")
+	assert.Equal(t, "payments", service.Str())
+
+	// applying the expressions again, as on a retried push, doesn't change the logs
+	require.NoError(t, le.apply(context.Background(), ld))
+	assert.Equal(t, 4, lr.Attributes().Len())
+	hint, _ = lr.Attributes().Get("loki.attribute.labels")
+	assert.Equal(t, "http.method,level,service", hint.Str())
+
+	requests := loki.LogsToLokiRequests(ld)
+	require.Len(t, requests, 1)
+	streams := requests[""].Streams
+	require.Len(t, streams, 1)
+	assert.Equal(t, `{exporter="OTLP", http.method="GET", level="error", service="payments"}`, streams[0].Labels)
+}
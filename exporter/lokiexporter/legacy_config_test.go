@@ -73,7 +73,9 @@ func TestLoadConfig(t *testing.T) {
 					NumConsumers: 2,
 					QueueSize:    10,
 				},
-				TenantID: stringp("example"),
+				StreamRateLimit:  defaultStreamRateLimitSettings(),
+				AdaptiveBatching: defaultAdaptiveBatchingSettings(),
+				TenantID:         stringp("example"),
 				Labels: &LabelsConfig{
 					Attributes: map[string]string{
 						conventions.AttributeContainerName:  "container_name",
@@ -120,7 +122,9 @@ func TestLoadConfig(t *testing.T) {
 					NumConsumers: 10,
 					QueueSize:    5000,
 				},
-				TenantID: stringp("example"),
+				StreamRateLimit:  defaultStreamRateLimitSettings(),
+				AdaptiveBatching: defaultAdaptiveBatchingSettings(),
+				TenantID:         stringp("example"),
 				Labels: &LabelsConfig{
 					RecordAttributes: map[string]string{
 						"traceID": "traceid",
@@ -28,6 +28,10 @@ import (
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/loki/pkg/logproto"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/loki"
 )
@@ -36,24 +40,64 @@ type nextLokiExporter struct {
 	config   *Config
 	settings component.TelemetrySettings
 	client   *http.Client
-	wg       sync.WaitGroup
+	// clientConn and pusher push the logs when gRPC is enabled, instead of the HTTP client.
+	clientConn *grpc.ClientConn
+	pusher     logproto.PusherClient
+	labels     *labelExpressions
+	limiter    *streamRateLimiter
+	batcher    *adaptiveBatcher
+	// compressor compresses the HTTP push requests on top of their snappy payload, if configured.
+	compressor *pushCompressor
+	wg         sync.WaitGroup
 }
 
-func newNextExporter(config *Config, settings component.TelemetrySettings) *nextLokiExporter {
+func newNextExporter(config *Config, settings component.TelemetrySettings) (*nextLokiExporter, error) {
 	settings.Logger.Info("using the new Loki exporter")
 
-	return &nextLokiExporter{
+	labels, err := newLabelExpressions(config.LabelExpressions, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &nextLokiExporter{
 		config:   config,
 		settings: settings,
+		labels:   labels,
+	}
+	if config.StreamRateLimit.Enabled {
+		exp.limiter = newStreamRateLimiter(config.StreamRateLimit, settings.Logger)
 	}
+	if config.AdaptiveBatching.Enabled {
+		exp.batcher = newAdaptiveBatcher(config.AdaptiveBatching, settings.Logger)
+	}
+	if exp.compressor, err = newPushCompressor(config.PushCompression); err != nil {
+		return nil, err
+	}
+	return exp, nil
 }
 
 func (l *nextLokiExporter) pushLogData(ctx context.Context, ld plog.Logs) error {
+	// the label expressions are evaluated again when the push is retried,
+	// apply leaves the logs it already processed unchanged
+	if l.labels != nil {
+		if err := l.labels.apply(ctx, ld); err != nil {
+			return consumererror.NewPermanent(err)
+		}
+	}
+
 	requests := loki.LogsToLokiRequests(ld)
 
 	var errs error
 	for tenant, request := range requests {
+		// the entries shed by the rate limit are dropped, they are not retried
+		if l.limiter != nil && len(request.Streams) > 0 && !l.limiter.limit(ctx, tenant, request.PushRequest) {
+			continue
+		}
 		err := l.sendPushRequest(ctx, tenant, request, ld)
+		if err != nil && l.limiter != nil && !consumererror.IsPermanent(err) {
+			// the push is retried, the entries are charged again then
+			l.limiter.refund(tenant, request.PushRequest)
+		}
 		errs = multierr.Append(errs, err)
 	}
 
@@ -74,10 +118,34 @@ func (l *nextLokiExporter) sendPushRequest(ctx context.Context, tenant string, r
 		)
 	}
 
+	if l.batcher != nil {
+		return l.batcher.send(ctx, tenant, pushReq, func(ctx context.Context, batch *logproto.PushRequest) error {
+			return l.push(ctx, tenant, batch, ld)
+		})
+	}
+	return l.push(ctx, tenant, pushReq, ld)
+}
+
+// push sends the push request to Loki, through gRPC when it is enabled.
+func (l *nextLokiExporter) push(ctx context.Context, tenant string, pushReq *logproto.PushRequest, ld plog.Logs) error {
+	if l.pusher != nil {
+		return l.sendGRPCPushRequest(ctx, tenant, pushReq, ld)
+	}
+	return l.sendHTTPPushRequest(ctx, tenant, pushReq, ld)
+}
+
+// sendHTTPPushRequest sends the push request to the HTTP push API, snappy compressed, and then
+// compressed with the push compression algorithm, if any.
+func (l *nextLokiExporter) sendHTTPPushRequest(ctx context.Context, tenant string, pushReq *logproto.PushRequest, ld plog.Logs) error {
 	buf, err := encode(pushReq)
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
+	if l.compressor != nil {
+		if buf, err = l.compressor.compress(buf); err != nil {
+			return consumererror.NewPermanent(err)
+		}
+	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", l.config.HTTPClientSettings.Endpoint, bytes.NewReader(buf))
 	if err != nil {
@@ -88,6 +156,9 @@ func (l *nextLokiExporter) sendPushRequest(ctx context.Context, tenant string, r
 		req.Header.Set(k, v)
 	}
 	req.Header.Set("Content-Type", "application/x-protobuf")
+	if l.compressor != nil {
+		req.Header.Set("Content-Encoding", l.compressor.encoding)
+	}
 	if len(tenant) > 0 {
 		req.Header.Set("X-Scope-OrgID", tenant)
 	}
@@ -108,14 +179,22 @@ func (l *nextLokiExporter) sendPushRequest(ctx context.Context, tenant string, r
 		if scanner.Scan() {
 			line = scanner.Text()
 		}
-		err = fmt.Errorf("HTTP %d %q: %s", resp.StatusCode, http.StatusText(resp.StatusCode), line)
-		return consumererror.NewLogs(err, ld)
+		return consumererror.NewLogs(&httpStatusError{code: resp.StatusCode, message: line}, ld)
 	}
 
 	return nil
 }
 
-func (l *nextLokiExporter) start(_ context.Context, host component.Host) (err error) {
+func (l *nextLokiExporter) start(ctx context.Context, host component.Host) (err error) {
+	if l.config.GRPC != nil {
+		l.clientConn, err = l.config.GRPC.ToClientConn(ctx, host, l.settings)
+		if err != nil {
+			return err
+		}
+		l.pusher = logproto.NewPusherClient(l.clientConn)
+		return nil
+	}
+
 	client, err := l.config.HTTPClientSettings.ToClient(host, l.settings)
 	if err != nil {
 		return err
@@ -128,5 +207,38 @@ func (l *nextLokiExporter) start(_ context.Context, host component.Host) (err er
 
 func (l *nextLokiExporter) stop(context.Context) (err error) {
 	l.wg.Wait()
+	if l.clientConn != nil {
+		return l.clientConn.Close()
+	}
 	return nil
 }
+
+// sendGRPCPushRequest pushes the request to the gRPC endpoint of the distributors, which
+// takes the same push request as the HTTP push API, uncompressed.
+func (l *nextLokiExporter) sendGRPCPushRequest(ctx context.Context, tenant string, pushReq *logproto.PushRequest, ld plog.Logs) error {
+	md := metadata.New(l.config.GRPC.Headers)
+	if len(tenant) > 0 {
+		md.Set("X-Scope-OrgID", tenant)
+	}
+	ctx = metadata.NewOutgoingContext(ctx, md)
+	if l.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.config.Timeout)
+		defer cancel()
+	}
+
+	if _, err := l.pusher.Push(ctx, pushReq, grpc.WaitForReady(l.config.GRPC.WaitForReady)); err != nil {
+		return consumererror.NewLogs(fmt.Errorf("failed to push the logs: %w", err), ld)
+	}
+	return nil
+}
+
+// httpStatusError is the error returned when Loki responds to a push with a non-2xx status code.
+type httpStatusError struct {
+	code    int
+	message string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d %q: %s", e.code, http.StatusText(e.code), e.message)
+}
@@ -18,8 +18,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
@@ -28,8 +30,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/plog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/loki"
 )
 
 func TestPushLogData(t *testing.T) {
@@ -300,3 +311,132 @@ func TestLogsToLokiRequestWithGroupingByTenant(t *testing.T) {
 		})
 	}
 }
+
+func TestPushLogDataRateLimitRetry(t *testing.T) {
+	var pushes []int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encPayload, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		decPayload, err := snappy.Decode(nil, encPayload)
+		require.NoError(t, err)
+		pushRequest := &logproto.PushRequest{}
+		require.NoError(t, proto.Unmarshal(decPayload, pushRequest))
+
+		pushes = append(pushes, len(pushRequest.Streams))
+		// the first push fails with a retryable error
+		if len(pushes) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("a log line")
+	requests := loki.LogsToLokiRequests(ld)
+	require.Len(t, requests, 1)
+	size := len(requests[""].PushRequest.Streams[0].Entries[0].Line)
+
+	// the burst only allows to send the entry once
+	cfg := &Config{
+		HTTPClientSettings: confighttp.HTTPClientSettings{
+			Endpoint: ts.URL,
+		},
+		StreamRateLimit: StreamRateLimitSettings{
+			Enabled:        true,
+			BytesPerSecond: 1,
+			BurstBytes:     size,
+			Shedding:       sheddingDropNewest,
+		},
+	}
+	exp, err := newNextExporter(cfg, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	err = exp.pushLogData(context.Background(), ld)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+
+	// the retry is charged again instead of being shed
+	require.NoError(t, exp.pushLogData(context.Background(), ld))
+	assert.Equal(t, []int{1, 1}, pushes)
+
+	// the entry was charged once it was sent
+	require.NoError(t, exp.pushLogData(context.Background(), ld))
+	assert.Equal(t, []int{1, 1}, pushes)
+}
+
+// mockPusherServer is a Loki distributor receiving the push requests over gRPC.
+type mockPusherServer struct {
+	mu       sync.Mutex
+	requests []*logproto.PushRequest
+	tenants  []string
+	headers  []string
+	err      error
+}
+
+func (s *mockPusherServer) Push(ctx context.Context, req *logproto.PushRequest) (*logproto.PushResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.requests = append(s.requests, req)
+	s.tenants = append(s.tenants, md.Get("X-Scope-OrgID")...)
+	s.headers = append(s.headers, md.Get("X-Custom-Header")...)
+	return &logproto.PushResponse{}, nil
+}
+
+func startMockPusherServer(t *testing.T) (*mockPusherServer, string) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	pusher := &mockPusherServer{}
+	logproto.RegisterPusherServer(srv, pusher)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+	return pusher, ln.Addr().String()
+}
+
+func TestPushLogDataGRPC(t *testing.T) {
+	pusher, endpoint := startMockPusherServer(t)
+
+	cfg := &Config{
+		GRPC: &configgrpc.GRPCClientSettings{
+			Endpoint:   endpoint,
+			TLSSetting: configtls.TLSClientSetting{Insecure: true},
+			Headers:    map[string]string{"X-Custom-Header": "loki_rocks"},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	exp, err := newNextExporter(cfg, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, exp.stop(context.Background()))
+	}()
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("tenant.id", "acme")
+	rl.Resource().Attributes().PutStr("loki.tenant", "tenant.id")
+	rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("a log line")
+
+	require.NoError(t, exp.pushLogData(context.Background(), ld))
+	require.Len(t, pusher.requests, 1)
+	require.Len(t, pusher.requests[0].Streams, 1)
+	assert.Equal(t, `{exporter="OTLP", tenant.id="acme"}`, pusher.requests[0].Streams[0].Labels)
+	require.Len(t, pusher.requests[0].Streams[0].Entries, 1)
+	assert.Equal(t, []string{"acme"}, pusher.tenants)
+	assert.Equal(t, []string{"loki_rocks"}, pusher.headers)
+
+	// the failed pushes are retried
+	pusher.mu.Lock()
+	pusher.err = status.Error(codes.Unavailable, "ingesters unavailable")
+	pusher.mu.Unlock()
+	err = exp.pushLogData(context.Background(), ld)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+}
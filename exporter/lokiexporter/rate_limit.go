@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lokiexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/lokiexporter"
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+)
+
+var (
+	mShedEntries = stats.Int64("shed_entries", "Number of log entries shed by the per stream rate limit", stats.UnitDimensionless)
+	mShedBytes   = stats.Int64("shed_bytes", "Number of log line bytes shed by the per stream rate limit", stats.UnitBytes)
+)
+
+// MetricViews returns the metrics views related to the Loki exporter.
+func MetricViews() []*view.View {
+	return metricViews
+}
+
+// metricViews are built once, a view can only be registered again when it is the very same view.
+var metricViews = []*view.View{
+	{
+		Name:        buildExporterCustomMetricName(mShedEntries.Name()),
+		Measure:     mShedEntries,
+		Description: mShedEntries.Description(),
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        buildExporterCustomMetricName(mShedBytes.Name()),
+		Measure:     mShedBytes,
+		Description: mShedBytes.Description(),
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        buildExporterCustomMetricName(mBatchSize.Name()),
+		Measure:     mBatchSize,
+		Description: mBatchSize.Description(),
+		TagKeys:     []tag.Key{tagTenantKey},
+		Aggregation: view.LastValue(),
+	},
+	{
+		Name:        buildExporterCustomMetricName(mBatchConcurrency.Name()),
+		Measure:     mBatchConcurrency,
+		Description: mBatchConcurrency.Description(),
+		TagKeys:     []tag.Key{tagTenantKey},
+		Aggregation: view.LastValue(),
+	},
+}
+
+// buildExporterCustomMetricName builds the name of an exporter metric following the
+// collector standards, like obsreport.BuildProcessorCustomMetricName does for processors.
+func buildExporterCustomMetricName(metric string) string {
+	return "exporter/" + typeStr + "/" + metric
+}
+
+// streamBucket is the token bucket of a single stream, a token being a byte of log line.
+type streamBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// streamRateLimiter applies the per stream rate limit to the push requests. Like Loki,
+// only the size of the log lines counts towards the limit.
+type streamRateLimiter struct {
+	settings StreamRateLimitSettings
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*streamBucket
+	lastSweep time.Time
+}
+
+func newStreamRateLimiter(settings StreamRateLimitSettings, logger *zap.Logger) *streamRateLimiter {
+	return &streamRateLimiter{
+		settings: settings,
+		logger:   logger,
+		now:      time.Now,
+		buckets:  map[string]*streamBucket{},
+	}
+}
+
+// limit sheds the entries exceeding the rate limit of their stream from the request,
+// and removes the streams left without entries. It returns whether the request still
+// holds entries to send.
+func (l *streamRateLimiter) limit(ctx context.Context, tenant string, request *logproto.PushRequest) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	shedEntries, shedBytes := 0, 0
+	streams := request.Streams[:0]
+	for _, stream := range request.Streams {
+		bucket := l.bucket(tenant+"\x00"+stream.Labels, now)
+
+		var entries, bytes int
+		stream.Entries, bucket.tokens, entries, bytes = l.admit(stream.Entries, bucket.tokens)
+		shedEntries += entries
+		shedBytes += bytes
+		if len(stream.Entries) > 0 {
+			streams = append(streams, stream)
+		}
+	}
+	request.Streams = streams
+	l.sweep(now)
+
+	if shedEntries > 0 {
+		l.logger.Debug(
+			"log entries exceeding the stream rate limit were shed",
+			zap.String("tenant", tenant),
+			zap.Int("entries", shedEntries),
+			zap.Int("bytes", shedBytes),
+		)
+		stats.Record(ctx, mShedEntries.M(int64(shedEntries)), mShedBytes.M(int64(shedBytes)))
+	}
+	return len(request.Streams) > 0
+}
+
+// refund gives the tokens of the entries back to their stream, when the request holding
+// them failed and will be retried. The entries are then charged again on the retry only.
+func (l *streamRateLimiter) refund(tenant string, request *logproto.PushRequest) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, stream := range request.Streams {
+		b, ok := l.buckets[tenant+"\x00"+stream.Labels]
+		if !ok {
+			continue
+		}
+		for _, entry := range stream.Entries {
+			b.tokens += float64(len(entry.Line))
+		}
+		b.tokens = math.Min(float64(l.settings.BurstBytes), b.tokens)
+	}
+}
+
+// bucket returns the bucket of the stream, refilled for the time elapsed since it was last used.
+func (l *streamRateLimiter) bucket(key string, now time.Time) *streamBucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &streamBucket{tokens: float64(l.settings.BurstBytes)}
+		l.buckets[key] = b
+	} else if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(l.settings.BurstBytes), b.tokens+elapsed.Seconds()*float64(l.settings.BytesPerSecond))
+	}
+	b.last = now
+	return b
+}
+
+// admit returns the entries fitting in the available tokens, in their original order.
+// Entries are admitted from the oldest or the newest one depending on the shedding
+// policy, and everything after the first entry that doesn't fit is shed.
+func (l *streamRateLimiter) admit(entries []logproto.Entry, tokens float64) ([]logproto.Entry, float64, int, int) {
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return entries[order[i]].Timestamp.Before(entries[order[j]].Timestamp)
+	})
+	if l.settings.Shedding == sheddingDropOldest {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	admitted := make([]bool, len(entries))
+	for _, idx := range order {
+		size := float64(len(entries[idx].Line))
+		if size > tokens {
+			break
+		}
+		tokens -= size
+		admitted[idx] = true
+	}
+
+	kept := entries[:0]
+	shedEntries, shedBytes := 0, 0
+	for i, entry := range entries {
+		if admitted[i] {
+			kept = append(kept, entry)
+			continue
+		}
+		shedEntries++
+		shedBytes += len(entry.Line)
+	}
+	return kept, tokens, shedEntries, shedBytes
+}
+
+// sweep forgets the buckets which would be full again by now, as they don't differ
+// from the bucket of a new stream. It runs at most once per refill period.
+func (l *streamRateLimiter) sweep(now time.Time) {
+	refill := time.Duration(float64(l.settings.BurstBytes) / float64(l.settings.BytesPerSecond) * float64(time.Second))
+	if now.Sub(l.lastSweep) < refill {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
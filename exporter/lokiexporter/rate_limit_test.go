@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lokiexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestStreamRateLimiter(shedding string, now *time.Time) *streamRateLimiter {
+	l := newStreamRateLimiter(StreamRateLimitSettings{
+		Enabled:        true,
+		BytesPerSecond: 10,
+		BurstBytes:     20,
+		Shedding:       shedding,
+	}, zap.NewNop())
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+// entries returns one entry per line, timestamped one second apart in the given order.
+func entries(start time.Time, lines ...string) []logproto.Entry {
+	out := make([]logproto.Entry, 0, len(lines))
+	for i, line := range lines {
+		out = append(out, logproto.Entry{Timestamp: start.Add(time.Duration(i) * time.Second), Line: line})
+	}
+	return out
+}
+
+func lines(stream logproto.Stream) []string {
+	var out []string
+	for _, e := range stream.Entries {
+		out = append(out, e.Line)
+	}
+	return out
+}
+
+func TestStreamRateLimiter_shedding(t *testing.T) {
+	now := time.Unix(1000, 0)
+	testCases := []struct {
+		shedding string
+		expected []string
+	}{
+		{shedding: sheddingDropNewest, expected: []string{"aaaaa", "bbbbb", "ccccc", "ddddd"}},
+		{shedding: sheddingDropOldest, expected: []string{"bbbbb", "ccccc", "ddddd", "eeeee"}},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.shedding, func(t *testing.T) {
+			l := newTestStreamRateLimiter(tC.shedding, &now)
+			req := &logproto.PushRequest{Streams: []logproto.Stream{
+				{Labels: `{job="a"}`, Entries: entries(now, "aaaaa", "bbbbb", "ccccc", "ddddd", "eeeee")},
+			}}
+
+			assert.True(t, l.limit(context.Background(), "", req))
+			require.Len(t, req.Streams, 1)
+			assert.Equal(t, tC.expected, lines(req.Streams[0]))
+		})
+	}
+}
+
+func TestStreamRateLimiter_outOfOrderEntries(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newTestStreamRateLimiter(sheddingDropOldest, &now)
+
+	stream := logproto.Stream{Labels: `{job="a"}`, Entries: entries(now, "aaaaaaaaaa", "bbbbbbbbbb", "cccccccccc")}
+	// the first entry is the newest one, the original order is kept for the entries sent
+	stream.Entries[0].Timestamp = now.Add(time.Minute)
+	req := &logproto.PushRequest{Streams: []logproto.Stream{stream}}
+
+	assert.True(t, l.limit(context.Background(), "", req))
+	assert.Equal(t, []string{"aaaaaaaaaa", "cccccccccc"}, lines(req.Streams[0]))
+}
+
+func TestStreamRateLimiter_refill(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newTestStreamRateLimiter(sheddingDropNewest, &now)
+	push := func(tenant, labels string, lines ...string) *logproto.PushRequest {
+		req := &logproto.PushRequest{Streams: []logproto.Stream{{Labels: labels, Entries: entries(now, lines...)}}}
+		l.limit(context.Background(), tenant, req)
+		return req
+	}
+
+	// the burst is consumed, then refilled at the configured rate
+	assert.Len(t, push("", `{job="a"}`, "aaaaaaaaaaaaaaaaaaaa").Streams, 1)
+	assert.Len(t, push("", `{job="a"}`, "a").Streams, 0)
+	now = now.Add(500 * time.Millisecond)
+	assert.Len(t, push("", `{job="a"}`, "aaaaa").Streams, 1)
+	assert.Len(t, push("", `{job="a"}`, "a").Streams, 0)
+
+	// other streams and the same stream of other tenants have their own limit
+	assert.Len(t, push("", `{job="b"}`, "bbbbbbbbbbbbbbbbbbbb").Streams, 1)
+	assert.Len(t, push("acme", `{job="a"}`, "aaaaaaaaaaaaaaaaaaaa").Streams, 1)
+
+	// the refill never exceeds the burst, and lines larger than the burst are always shed
+	now = now.Add(time.Hour)
+	assert.Len(t, push("", `{job="a"}`, "aaaaaaaaaaaaaaaaaaaaa").Streams, 0)
+	assert.Len(t, push("", `{job="a"}`, "aaaaaaaaaaaaaaaaaaaa").Streams, 1)
+}
+
+func TestStreamRateLimiter_sweep(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newTestStreamRateLimiter(sheddingDropNewest, &now)
+
+	req := &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{job="a"}`, Entries: entries(now, "a")},
+		{Labels: `{job="b"}`, Entries: entries(now, "b")},
+	}}
+	l.limit(context.Background(), "", req)
+	assert.Len(t, l.buckets, 2)
+
+	// the buckets are full again after burst / rate seconds
+	now = now.Add(2 * time.Second)
+	req = &logproto.PushRequest{Streams: []logproto.Stream{{Labels: `{job="a"}`, Entries: entries(now, "a")}}}
+	l.limit(context.Background(), "", req)
+	assert.Len(t, l.buckets, 1)
+	assert.Contains(t, l.buckets, "\x00"+`{job="a"}`)
+}
+
+func TestStreamRateLimiter_refund(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newTestStreamRateLimiter(sheddingDropNewest, &now)
+
+	req := &logproto.PushRequest{Streams: []logproto.Stream{{Labels: `{job="a"}`, Entries: entries(now, "aaaaaaaaaaaaaaa")}}}
+	require.True(t, l.limit(context.Background(), "", req))
+
+	// the failed request gives its tokens back, so that its retry is admitted again
+	l.refund("", req)
+	retry := &logproto.PushRequest{Streams: []logproto.Stream{{Labels: `{job="a"}`, Entries: entries(now, "aaaaaaaaaaaaaaa")}}}
+	require.True(t, l.limit(context.Background(), "", retry))
+	assert.Equal(t, []string{"aaaaaaaaaaaaaaa"}, lines(retry.Streams[0]))
+
+	// the refund never exceeds the burst
+	l.refund("", retry)
+	l.refund("", retry)
+	assert.Equal(t, float64(20), l.buckets["\x00"+`{job="a"}`].tokens)
+}
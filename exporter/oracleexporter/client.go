@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/oracleexporter"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/klauspost/compress/zstd"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"golang.org/x/net/http2"
+)
+
+// newHTTPClient returns the client of the REST ingestion path. The payloads are
+// compressed by the exporter, see compress, so the client never compresses them.
+//
+// The HTTP/2 keepalive can't be tuned through confighttp. When it is enabled, the
+// base transport built by confighttp is configured for it and wrapped the same way
+// confighttp does: headers, instrumentation, auth, then the custom round tripper.
+func newHTTPClient(cfg *Config, host component.Host, settings component.TelemetrySettings) (*http.Client, error) {
+	clientSettings := cfg.HTTPClientSettings
+	clientSettings.Compression = ""
+	if cfg.HTTP2.ReadIdleTimeout <= 0 {
+		return clientSettings.ToClient(host, settings)
+	}
+
+	// without any of the wrapping settings, confighttp returns its base transport
+	baseSettings := confighttp.HTTPClientSettings{
+		Endpoint:            clientSettings.Endpoint,
+		TLSSetting:          clientSettings.TLSSetting,
+		ReadBufferSize:      clientSettings.ReadBufferSize,
+		WriteBufferSize:     clientSettings.WriteBufferSize,
+		Timeout:             clientSettings.Timeout,
+		MaxIdleConns:        clientSettings.MaxIdleConns,
+		MaxIdleConnsPerHost: clientSettings.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     clientSettings.MaxConnsPerHost,
+		IdleConnTimeout:     clientSettings.IdleConnTimeout,
+	}
+	baseClient, err := baseSettings.ToClient(host, component.TelemetrySettings{})
+	if err != nil {
+		return nil, err
+	}
+	transport, ok := baseClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected transport %T", baseClient.Transport)
+	}
+
+	h2Transport, err := http2.ConfigureTransports(transport)
+	if err != nil {
+		return nil, err
+	}
+	// a ping is sent when no frame was received for ReadIdleTimeout, and the
+	// connection is closed when it isn't answered within PingTimeout
+	h2Transport.ReadIdleTimeout = cfg.HTTP2.ReadIdleTimeout
+	h2Transport.PingTimeout = cfg.HTTP2.PingTimeout
+
+	var roundTripper http.RoundTripper = transport
+	if len(clientSettings.Headers) > 0 {
+		roundTripper = &headerRoundTripper{transport: roundTripper, headers: clientSettings.Headers}
+	}
+	if settings.TracerProvider != nil && settings.MeterProvider != nil {
+		roundTripper = otelhttp.NewTransport(
+			roundTripper,
+			otelhttp.WithTracerProvider(settings.TracerProvider),
+			otelhttp.WithMeterProvider(settings.MeterProvider),
+			otelhttp.WithPropagators(otel.GetTextMapPropagator()),
+		)
+	}
+	if clientSettings.Auth != nil {
+		extensions := host.GetExtensions()
+		if extensions == nil {
+			return nil, errors.New("extensions configuration not found")
+		}
+		authenticator, err := clientSettings.Auth.GetClientAuthenticator(extensions)
+		if err != nil {
+			return nil, err
+		}
+		if roundTripper, err = authenticator.RoundTripper(roundTripper); err != nil {
+			return nil, err
+		}
+	}
+	if clientSettings.CustomRoundTripper != nil {
+		if roundTripper, err = clientSettings.CustomRoundTripper(roundTripper); err != nil {
+			return nil, err
+		}
+	}
+	return &http.Client{
+		Transport: roundTripper,
+		Timeout:   clientSettings.Timeout,
+	}, nil
+}
+
+type headerRoundTripper struct {
+	transport http.RoundTripper
+	headers   map[string]string
+}
+
+func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+	return h.transport.RoundTrip(req)
+}
+
+// compress returns the body compressed with the configured compression, to be sent
+// with the matching Content-Encoding header.
+func compress(compression configcompression.CompressionType, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	var err error
+	switch compression {
+	case configcompression.Gzip:
+		writer = gzip.NewWriter(&buf)
+	case configcompression.Zstd:
+		writer, err = zstd.NewWriter(&buf)
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err = writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
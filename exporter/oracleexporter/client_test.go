@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/configcompression"
+)
+
+type authHost struct {
+	component.Host
+	extensions map[component.ID]component.Extension
+}
+
+func (h *authHost) GetExtensions() map[component.ID]component.Extension {
+	return h.extensions
+}
+
+type bearerRoundTripper struct {
+	base http.RoundTripper
+}
+
+func (b *bearerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer token")
+	return b.base.RoundTrip(req)
+}
+
+func TestNewHTTPClient_HTTP2KeepsAuthAndHeaders(t *testing.T) {
+	var authorization, userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		userAgent = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	authID := component.NewID("bearer")
+	host := &authHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Extension{
+			authID: configauth.NewClientAuthenticator(configauth.WithClientRoundTripper(func(base http.RoundTripper) (http.RoundTripper, error) {
+				return &bearerRoundTripper{base: base}, nil
+			})),
+		},
+	}
+
+	for _, readIdleTimeout := range []time.Duration{0, 30 * time.Second} {
+		cfg := createDefaultConfig().(*Config)
+		cfg.Endpoint = server.URL
+		cfg.Auth = &configauth.Authentication{AuthenticatorID: authID}
+		cfg.HTTP2.ReadIdleTimeout = readIdleTimeout
+		require.NoError(t, cfg.Validate())
+
+		client, err := newHTTPClient(cfg, host, componenttest.NewNopTelemetrySettings())
+		require.NoError(t, err)
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, "Bearer token", authorization)
+		assert.Equal(t, "OpenTelemetry -> Oracle", userAgent)
+	}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	cfg.Auth = &configauth.Authentication{AuthenticatorID: component.NewID("missing")}
+	cfg.HTTP2.ReadIdleTimeout = 30 * time.Second
+	_, err := newHTTPClient(cfg, host, componenttest.NewNopTelemetrySettings())
+	assert.Error(t, err)
+}
+
+func TestCompress(t *testing.T) {
+	body := []byte(`{"resourceLogs":[]}`)
+
+	compressed, err := compress(configcompression.Gzip, body)
+	require.NoError(t, err)
+	gr, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(gr)
+	require.NoError(t, err)
+	assert.Equal(t, body, decompressed)
+
+	compressed, err = compress(configcompression.Zstd, body)
+	require.NoError(t, err)
+	zr, err := zstd.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	defer zr.Close()
+	decompressed, err = io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, body, decompressed)
+
+	_, err = compress(configcompression.Snappy, body)
+	assert.EqualError(t, err, `unsupported compression "snappy"`)
+}
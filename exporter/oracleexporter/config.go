@@ -16,9 +16,13 @@ package oracleexporter // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
@@ -28,6 +32,11 @@ const (
 	typeStr = "oracle"
 	// The stability level of the exporter.
 	stability = component.StabilityLevelAlpha
+
+	// databaseBackend posts the telemetry to the REST ingestion endpoint of the database.
+	databaseBackend = "database"
+	// ociBackend sends the logs to OCI Logging and the metrics to OCI Monitoring.
+	ociBackend = "oci"
 )
 
 // Config defines configuration for the InfluxDB exporter.
@@ -41,11 +50,234 @@ type Config struct {
 	User string `mapstructure:"user"`
 	// Root user's password
 	Password string `mapstructure:"password"`
+
+	// HTTP2 configures the keepalive of the HTTP/2 connections.
+	HTTP2 HTTP2Settings `mapstructure:"http2"`
+
+	// HealthCheck configures the periodic readback of the database, whose outcome is
+	// reported to the health check extension.
+	HealthCheck HealthCheckSettings `mapstructure:"health_check"`
+
+	// SchemaRouting posts the telemetry of each tenant to the schema of the tenant.
+	SchemaRouting SchemaRoutingSettings `mapstructure:"schema_routing"`
+
+	// WaitEvents configures the periodic scraping of the wait events of the database sessions
+	// of the exporter user, reported as metrics of the exporter.
+	WaitEvents WaitEventsSettings `mapstructure:"wait_events"`
+
+	// Backend is where the telemetry is sent, either "database" or "oci".
+	Backend string `mapstructure:"backend"`
+
+	// OCI configures the OCI Logging and OCI Monitoring APIs of the "oci" backend.
+	OCI OCISettings `mapstructure:"oci"`
+}
+
+// OCISettings defines the credentials and the destinations of the "oci" backend.
+//
+// The credentials are read from the profile of an OCI configuration file, as the OCI
+// SDKs and CLI do, and can be overridden one by one.
+type OCISettings struct {
+	// ConfigFile is the OCI configuration file, defaults to ~/.oci/config.
+	ConfigFile string `mapstructure:"config_file"`
+	// Profile is the profile of the configuration file, defaults to DEFAULT.
+	Profile string `mapstructure:"profile"`
+	// Tenancy is the OCID of the tenancy.
+	Tenancy string `mapstructure:"tenancy"`
+	// User is the OCID of the user the requests are signed for.
+	User string `mapstructure:"user"`
+	// Fingerprint is the fingerprint of the API signing key of the user.
+	Fingerprint string `mapstructure:"fingerprint"`
+	// KeyFile is the PEM file of the private API signing key.
+	KeyFile string `mapstructure:"key_file"`
+	// Region is the region of the APIs, such as us-ashburn-1.
+	Region string `mapstructure:"region"`
+
+	// Logging configures the ingestion of the logs into OCI Logging.
+	Logging OCILoggingSettings `mapstructure:"logging"`
+	// Monitoring configures the posting of the metrics to OCI Monitoring.
+	Monitoring OCIMonitoringSettings `mapstructure:"monitoring"`
+}
+
+// OCILoggingSettings defines the custom log the logs are ingested into.
+type OCILoggingSettings struct {
+	// Endpoint is the logging ingestion endpoint, defaults to the one of the region.
+	Endpoint string `mapstructure:"endpoint"`
+	// LogID is the OCID of the custom log.
+	LogID string `mapstructure:"log_id"`
+}
+
+// OCIMonitoringSettings defines where the metrics are posted.
+type OCIMonitoringSettings struct {
+	// Endpoint is the telemetry ingestion endpoint, defaults to the one of the region.
+	Endpoint string `mapstructure:"endpoint"`
+	// CompartmentID is the OCID of the compartment of the metrics.
+	CompartmentID string `mapstructure:"compartment_id"`
+	// Namespace of the metrics, it can't start with oci_.
+	Namespace string `mapstructure:"namespace"`
+	// ResourceGroup of the metrics, optional.
+	ResourceGroup string `mapstructure:"resource_group"`
+}
+
+var ociNamespaceRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*[a-z0-9]$`)
+
+func (o *OCISettings) validate() error {
+	if o.Logging.LogID == "" && o.Monitoring.CompartmentID == "" {
+		return fmt.Errorf("oci::logging::log_id or oci::monitoring::compartment_id must be set")
+	}
+	if o.Monitoring.CompartmentID == "" {
+		return nil
+	}
+	if !ociNamespaceRegexp.MatchString(o.Monitoring.Namespace) || strings.HasPrefix(o.Monitoring.Namespace, "oci_") {
+		return fmt.Errorf("oci::monitoring::namespace %q is invalid, it must match %s and can't start with oci_",
+			o.Monitoring.Namespace, ociNamespaceRegexp)
+	}
+	return nil
+}
+
+// WaitEventsSettings defines how the wait events of the sessions of the exporter are scraped.
+type WaitEventsSettings struct {
+	// Enabled enables the scraping of the wait events.
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint is the REST-enabled SQL endpoint the wait events are queried from.
+	Endpoint string `mapstructure:"endpoint"`
+	// Interval between two scrapes.
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+func (we *WaitEventsSettings) validate() error {
+	if !we.Enabled {
+		return nil
+	}
+	if we.Endpoint == "" {
+		return fmt.Errorf("wait_events::endpoint must be set")
+	}
+	if we.Interval <= 0 {
+		return fmt.Errorf("wait_events::interval must be positive")
+	}
+	return nil
+}
+
+// SchemaRoutingSettings defines how the telemetry is partitioned between the schemas of
+// the tenants, each schema having its own REST ingestion endpoint and user.
+type SchemaRoutingSettings struct {
+	// FromAttribute is the resource attribute holding the tenant of the telemetry.
+	FromAttribute string `mapstructure:"from_attribute"`
+	// Schemas maps the tenants to their schema. The telemetry of the other tenants, or
+	// without the attribute, is posted to the exporter endpoint.
+	Schemas map[string]SchemaSettings `mapstructure:"schemas"`
+}
+
+// SchemaSettings defines where the telemetry of a tenant is posted.
+type SchemaSettings struct {
+	// Endpoint is the REST ingestion endpoint of the schema.
+	Endpoint string `mapstructure:"endpoint"`
+	// User of the schema.
+	User string `mapstructure:"user"`
+	// Password of the user.
+	Password string `mapstructure:"password"`
+}
+
+func (sr *SchemaRoutingSettings) validate() error {
+	if len(sr.Schemas) == 0 {
+		return nil
+	}
+	if sr.FromAttribute == "" {
+		return fmt.Errorf("schema_routing::from_attribute must be set")
+	}
+	for tenant, schema := range sr.Schemas {
+		if schema.Endpoint == "" {
+			return fmt.Errorf("schema_routing::schemas::%s::endpoint must be set", tenant)
+		}
+	}
+	return nil
+}
+
+// HTTP2Settings defines the health checks of the HTTP/2 connections, which detect
+// the connections silently dropped by the network, such as by a load balancer.
+type HTTP2Settings struct {
+	// ReadIdleTimeout is the time without any frame received after which a ping is
+	// sent on the connection. Zero disables the health checks.
+	ReadIdleTimeout time.Duration `mapstructure:"read_idle_timeout"`
+	// PingTimeout is the time after which the connection is closed when the ping
+	// isn't answered.
+	PingTimeout time.Duration `mapstructure:"ping_timeout"`
+}
+
+// HealthCheckSettings defines how the connectivity to the database is checked.
+type HealthCheckSettings struct {
+	// Enabled enables the health check.
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint is the URL the health check is sent to, defaults to the exporter endpoint.
+	Endpoint string `mapstructure:"endpoint"`
+	// Query is a lightweight validation query, such as `SELECT 1 FROM DUAL`, posted to the
+	// REST-enabled SQL endpoint. A HEAD request is sent instead when it is empty.
+	Query string `mapstructure:"query"`
+	// Interval between two checks.
+	Interval time.Duration `mapstructure:"interval"`
+	// Timeout of a single check.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// FailureThreshold is the number of consecutive failed checks after which the
+	// exporter is reported unhealthy.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+}
+
+func (hc *HealthCheckSettings) validate() error {
+	if !hc.Enabled {
+		return nil
+	}
+	if hc.Interval <= 0 {
+		return fmt.Errorf("health_check::interval must be positive")
+	}
+	if hc.Timeout <= 0 {
+		return fmt.Errorf("health_check::timeout must be positive")
+	}
+	if hc.FailureThreshold <= 0 {
+		return fmt.Errorf("health_check::failure_threshold must be positive")
+	}
+	return nil
 }
 
 func (cfg *Config) Validate() error {
 	if err := cfg.ExporterSettings.Validate(); err != nil {
 		return fmt.Errorf("exporter settings are invalid :%w", err)
 	}
-	return nil
+	switch cfg.Backend {
+	case databaseBackend:
+	case ociBackend:
+		// the database features don't apply to the OCI APIs
+		if cfg.HealthCheck.Enabled || cfg.WaitEvents.Enabled || len(cfg.SchemaRouting.Schemas) > 0 {
+			return fmt.Errorf("health_check, wait_events and schema_routing can't be used with the oci backend")
+		}
+		return cfg.OCI.validate()
+	default:
+		return fmt.Errorf("unsupported backend %q, must be one of database or oci", cfg.Backend)
+	}
+	// The REST ingestion endpoint only accepts gzip and zstd encoded payloads.
+	switch cfg.Compression {
+	case configcompression.Gzip, configcompression.Zstd, "none", "":
+	default:
+		return fmt.Errorf("unsupported compression %q, must be one of gzip, zstd or none", cfg.Compression)
+	}
+	if cfg.MaxIdleConns != nil && *cfg.MaxIdleConns < 0 {
+		return fmt.Errorf("max_idle_conns must not be negative")
+	}
+	if cfg.MaxIdleConnsPerHost != nil && *cfg.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("max_idle_conns_per_host must not be negative")
+	}
+	if cfg.IdleConnTimeout != nil && *cfg.IdleConnTimeout < 0 {
+		return fmt.Errorf("idle_conn_timeout must not be negative")
+	}
+	if cfg.HTTP2.ReadIdleTimeout < 0 {
+		return fmt.Errorf("http2::read_idle_timeout must not be negative")
+	}
+	if cfg.HTTP2.PingTimeout < 0 {
+		return fmt.Errorf("http2::ping_timeout must not be negative")
+	}
+	if err := cfg.HealthCheck.validate(); err != nil {
+		return err
+	}
+	if err := cfg.WaitEvents.validate(); err != nil {
+		return err
+	}
+	return cfg.SchemaRouting.validate()
 }
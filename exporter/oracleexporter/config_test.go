@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -31,6 +32,9 @@ import (
 func TestLoadConfig(t *testing.T) {
 	t.Parallel()
 
+	maxIdleConns := 10
+	idleConnTimeout := 30 * time.Second
+
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
 	require.NoError(t, err)
 
@@ -50,6 +54,10 @@ func TestLoadConfig(t *testing.T) {
 					Endpoint: "http://localhost:8080",
 					Timeout:  500 * time.Millisecond,
 					Headers:  map[string]string{"User-Agent": "OpenTelemetry -> Oracle"},
+
+					Compression:     configcompression.Zstd,
+					MaxIdleConns:    &maxIdleConns,
+					IdleConnTimeout: &idleConnTimeout,
 				},
 				QueueSettings: exporterhelper.QueueSettings{
 					Enabled:      true,
@@ -64,8 +72,62 @@ func TestLoadConfig(t *testing.T) {
 				},
 				User:     "c##cloud$service",
 				Password: "AutoS_Y_S123",
+				HTTP2: HTTP2Settings{
+					ReadIdleTimeout: 30 * time.Second,
+					PingTimeout:     10 * time.Second,
+				},
+				HealthCheck: HealthCheckSettings{
+					Enabled:          true,
+					Query:            "SELECT 1 FROM DUAL",
+					Interval:         10 * time.Second,
+					Timeout:          2 * time.Second,
+					FailureThreshold: 3,
+				},
+				WaitEvents: WaitEventsSettings{
+					Enabled:  true,
+					Endpoint: "http://localhost:8080/ords/otel/_/sql",
+					Interval: 30 * time.Second,
+				},
+				Backend: databaseBackend,
 			},
 		},
+		{
+			id: component.NewIDWithName(typeStr, "schemarouting"),
+			expected: func() component.ExporterConfig {
+				cfg := createDefaultConfig().(*Config)
+				cfg.Endpoint = "http://localhost:8080/ords/shared/otel"
+				cfg.SchemaRouting = SchemaRoutingSettings{
+					FromAttribute: "tenant",
+					Schemas: map[string]SchemaSettings{
+						"acme": {
+							Endpoint: "http://localhost:8080/ords/acme/otel",
+							User:     "acme",
+							Password: "acme_pwd",
+						},
+					},
+				}
+				return cfg
+			}(),
+		},
+		{
+			id: component.NewIDWithName(typeStr, "oci"),
+			expected: func() component.ExporterConfig {
+				cfg := createDefaultConfig().(*Config)
+				cfg.Backend = ociBackend
+				cfg.OCI = OCISettings{
+					Profile: "OTEL",
+					Region:  "us-ashburn-1",
+					Logging: OCILoggingSettings{
+						LogID: "ocid1.log.oc1.iad.amaaaaaa",
+					},
+					Monitoring: OCIMonitoringSettings{
+						CompartmentID: "ocid1.compartment.oc1..aaaaaaaa",
+						Namespace:     "otel_collector",
+					},
+				}
+				return cfg
+			}(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -82,3 +144,82 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCompression(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, configcompression.Gzip, cfg.Compression)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Compression = configcompression.Zstd
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Compression = configcompression.Snappy
+	assert.EqualError(t, cfg.Validate(), `unsupported compression "snappy", must be one of gzip, zstd or none`)
+}
+
+func TestValidateHTTP2(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTP2.ReadIdleTimeout = 30 * time.Second
+	assert.NoError(t, cfg.Validate())
+
+	cfg.HTTP2.PingTimeout = -time.Second
+	assert.EqualError(t, cfg.Validate(), "http2::ping_timeout must not be negative")
+}
+
+func TestValidateHealthCheck(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.HealthCheck.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.HealthCheck.FailureThreshold = 0
+	assert.EqualError(t, cfg.Validate(), "health_check::failure_threshold must be positive")
+
+	cfg.HealthCheck.Interval = 0
+	assert.EqualError(t, cfg.Validate(), "health_check::interval must be positive")
+}
+
+func TestValidateSchemaRouting(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.SchemaRouting.Schemas = map[string]SchemaSettings{"acme": {Endpoint: "http://localhost:8080/ords/acme/otel"}}
+	assert.EqualError(t, cfg.Validate(), "schema_routing::from_attribute must be set")
+
+	cfg.SchemaRouting.FromAttribute = "tenant"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.SchemaRouting.Schemas["globex"] = SchemaSettings{User: "globex"}
+	assert.EqualError(t, cfg.Validate(), "schema_routing::schemas::globex::endpoint must be set")
+}
+
+func TestValidateWaitEvents(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.WaitEvents.Enabled = true
+	assert.EqualError(t, cfg.Validate(), "wait_events::endpoint must be set")
+
+	cfg.WaitEvents.Endpoint = "http://localhost:8080/ords/otel/_/sql"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.WaitEvents.Interval = 0
+	assert.EqualError(t, cfg.Validate(), "wait_events::interval must be positive")
+}
+
+func TestValidateOCI(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Backend = "adb"
+	assert.EqualError(t, cfg.Validate(), `unsupported backend "adb", must be one of database or oci`)
+
+	cfg.Backend = ociBackend
+	assert.EqualError(t, cfg.Validate(), "oci::logging::log_id or oci::monitoring::compartment_id must be set")
+
+	cfg.OCI.Logging.LogID = "ocid1.log.oc1.iad.amaaaaaa"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.OCI.Monitoring.CompartmentID = "ocid1.compartment.oc1..aaaaaaaa"
+	cfg.OCI.Monitoring.Namespace = "oci_collector"
+	assert.EqualError(t, cfg.Validate(), `oci::monitoring::namespace "oci_collector" is invalid, it must match ^[a-z][a-z0-9_]*[a-z0-9]$ and can't start with oci_`)
+
+	cfg.OCI.Monitoring.Namespace = "otel_collector"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.WaitEvents.Enabled = true
+	assert.EqualError(t, cfg.Validate(), "health_check, wait_events and schema_routing can't be used with the oci backend")
+}
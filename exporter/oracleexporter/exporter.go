@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/oracleexporter"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+)
+
+// oracleExporter posts the telemetry, serialized as OTLP JSON, to the REST ingestion endpoint.
+type oracleExporter struct {
+	cfg      *Config
+	settings component.TelemetrySettings
+	client   *http.Client
+
+	// healthChecker is shared by the exporters of the configuration, nil when disabled.
+	healthChecker *sharedcomponent.SharedComponent
+	// waitEvents is shared by the exporters of the configuration, nil when disabled.
+	waitEvents *sharedcomponent.SharedComponent
+
+	tracesMarshaler  ptrace.Marshaler
+	metricsMarshaler pmetric.Marshaler
+	logsMarshaler    plog.Marshaler
+}
+
+func newOracleExporter(cfg *Config, set component.ExporterCreateSettings) *oracleExporter {
+	return &oracleExporter{
+		cfg:              cfg,
+		settings:         set.TelemetrySettings,
+		healthChecker:    getHealthChecker(cfg, set.TelemetrySettings),
+		waitEvents:       getWaitEventsScraper(cfg, set.TelemetrySettings),
+		tracesMarshaler:  &ptrace.JSONMarshaler{},
+		metricsMarshaler: &pmetric.JSONMarshaler{},
+		logsMarshaler:    &plog.JSONMarshaler{},
+	}
+}
+
+// start creates the HTTP client, and starts the health checker and the wait events
+// scraper if they aren't yet.
+func (e *oracleExporter) start(ctx context.Context, host component.Host) error {
+	client, err := newHTTPClient(e.cfg, host, e.settings)
+	if err != nil {
+		return err
+	}
+	e.client = client
+
+	if e.healthChecker != nil {
+		if err = e.healthChecker.Start(ctx, host); err != nil {
+			return err
+		}
+	}
+	if e.waitEvents != nil {
+		return e.waitEvents.Start(ctx, host)
+	}
+	return nil
+}
+
+func (e *oracleExporter) shutdown(ctx context.Context) error {
+	var errs error
+	if e.healthChecker != nil {
+		errs = multierr.Append(errs, e.healthChecker.Shutdown(ctx))
+	}
+	if e.waitEvents != nil {
+		errs = multierr.Append(errs, e.waitEvents.Shutdown(ctx))
+	}
+	return errs
+}
+
+func (e *oracleExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
+	partitions := e.partitionTraces(td)
+	errs := newPartitionErrors(len(partitions))
+	failed := ptrace.NewTraces()
+	for tenant, traces := range partitions {
+		body, err := e.tracesMarshaler.MarshalTraces(traces)
+		if errs.add(e.sendPartition(ctx, "traces", tenant, traces.SpanCount(), body, err)) {
+			traces.ResourceSpans().MoveAndAppendTo(failed.ResourceSpans())
+		}
+	}
+	if errs.partial() {
+		return consumererror.NewTraces(errs.err(e.settings.Logger), failed)
+	}
+	return errs.err(e.settings.Logger)
+}
+
+func (e *oracleExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) error {
+	partitions := e.partitionMetrics(md)
+	errs := newPartitionErrors(len(partitions))
+	failed := pmetric.NewMetrics()
+	for tenant, metrics := range partitions {
+		body, err := e.metricsMarshaler.MarshalMetrics(metrics)
+		if errs.add(e.sendPartition(ctx, "metrics", tenant, metrics.DataPointCount(), body, err)) {
+			metrics.ResourceMetrics().MoveAndAppendTo(failed.ResourceMetrics())
+		}
+	}
+	if errs.partial() {
+		return consumererror.NewMetrics(errs.err(e.settings.Logger), failed)
+	}
+	return errs.err(e.settings.Logger)
+}
+
+func (e *oracleExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
+	partitions := e.partitionLogs(ld)
+	errs := newPartitionErrors(len(partitions))
+	failed := plog.NewLogs()
+	for tenant, logs := range partitions {
+		body, err := e.logsMarshaler.MarshalLogs(logs)
+		if errs.add(e.sendPartition(ctx, "logs", tenant, logs.LogRecordCount(), body, err)) {
+			logs.ResourceLogs().MoveAndAppendTo(failed.ResourceLogs())
+		}
+	}
+	if errs.partial() {
+		return consumererror.NewLogs(errs.err(e.settings.Logger), failed)
+	}
+	return errs.err(e.settings.Logger)
+}
+
+// sendPartition posts the serialized partition of the tenant to its schema, the partition
+// holding rows spans, data points or log records of the signal.
+func (e *oracleExporter) sendPartition(ctx context.Context, signal string, tenant string, rows int, body []byte, marshalErr error) error {
+	if marshalErr != nil {
+		return consumererror.NewPermanent(marshalErr)
+	}
+	return e.send(ctx, signal, rows, e.schemaOf(tenant), body)
+}
+
+// send posts the payload, unless the health check reports the database as unreachable
+// in which case the push fails right away and is retried later.
+func (e *oracleExporter) send(ctx context.Context, signal string, rows int, schema SchemaSettings, body []byte) error {
+	if err := healthStatus(e.healthChecker); err != nil {
+		return fmt.Errorf("oracle database is unreachable: %w", err)
+	}
+
+	compressed := configcompression.IsCompressed(e.cfg.Compression)
+	if compressed {
+		var err error
+		if body, err = compress(e.cfg.Compression, body); err != nil {
+			return consumererror.NewPermanent(err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, schema.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return consumererror.NewPermanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if compressed {
+		req.Header.Set("Content-Encoding", string(e.cfg.Compression))
+	}
+	if schema.User != "" {
+		req.SetBasicAuth(schema.User, schema.Password)
+	}
+
+	start := time.Now()
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	succeeded := resp.StatusCode >= 200 && resp.StatusCode < 300
+	recordBatch(ctx, signal, rows, time.Since(start), succeeded, respBody)
+
+	switch {
+	case succeeded:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("oracle ingestion returned %q %q", resp.Status, string(respBody))
+	default:
+		return consumererror.NewPermanent(fmt.Errorf("oracle ingestion returned %q %q", resp.Status, string(respBody)))
+	}
+}
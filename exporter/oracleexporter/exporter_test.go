@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestOracleExporter_pushLogs(t *testing.T) {
+	var contentType, contentEncoding, user string
+	requests := 0
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		contentType = r.Header.Get("Content-Type")
+		contentEncoding = r.Header.Get("Content-Encoding")
+		user, _, _ = r.BasicAuth()
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	cfg.User = "otel"
+	cfg.HealthCheck.Enabled = true
+
+	exporter := newOracleExporter(cfg, componenttest.NewNopExporterCreateSettings())
+	require.NoError(t, exporter.start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, exporter.shutdown(context.Background())) }()
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+
+	require.NoError(t, exporter.pushLogs(context.Background(), ld))
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, "gzip", contentEncoding)
+	assert.Equal(t, "otel", user)
+
+	status = http.StatusServiceUnavailable
+	err := exporter.pushLogs(context.Background(), ld)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+
+	status = http.StatusBadRequest
+	assert.True(t, consumererror.IsPermanent(exporter.pushLogs(context.Background(), ld)))
+
+	// the database isn't reached while the health check reports it unreachable
+	hc := exporter.healthChecker.Unwrap().(*healthChecker)
+	for i := 0; i < cfg.HealthCheck.FailureThreshold; i++ {
+		hc.report(errors.New("connection refused"))
+	}
+	sent := requests
+	assert.EqualError(t, exporter.pushLogs(context.Background(), ld), "oracle database is unreachable: connection refused")
+	assert.Equal(t, sent, requests)
+}
@@ -16,16 +16,21 @@ package oracleexporter // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configcompression"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 // NewFactory creates a factory for Jaeger Thrift over HTTP exporter.
 func NewFactory() component.ExporterFactory {
+	_ = view.Register(MetricViews()...)
+
 	return component.NewExporterFactory(
 		typeStr,
 		createDefaultConfig,
@@ -37,8 +42,11 @@ func NewFactory() component.ExporterFactory {
 
 func createTraceExporter(ctx context.Context, set component.ExporterCreateSettings, config component.ExporterConfig) (component.TracesExporter, error) {
 	cfg := config.(*Config)
+	if cfg.Backend == ociBackend {
+		return nil, errOCITraces
+	}
 
-	exporter := newTracesExporter(cfg, set)
+	exporter := newOracleExporter(cfg, set)
 
 	return exporterhelper.NewTracesExporter(
 		ctx,
@@ -48,17 +56,30 @@ func createTraceExporter(ctx context.Context, set component.ExporterCreateSettin
 		exporterhelper.WithQueue(cfg.QueueSettings),
 		exporterhelper.WithRetry(cfg.RetrySettings),
 		exporterhelper.WithStart(exporter.start),
+		exporterhelper.WithShutdown(exporter.shutdown),
 	)
 }
 
 func createMetricsExporter(ctx context.Context, set component.ExporterCreateSettings, config component.ExporterConfig) (component.MetricsExporter, error) {
 	cfg := config.(*Config)
-
-	exporter, err := newMetricsExporter(cfg, set)
-	if err != nil {
-		return nil, err
+	if cfg.Backend == ociBackend {
+		if cfg.OCI.Monitoring.CompartmentID == "" {
+			return nil, errors.New("oci::monitoring::compartment_id must be set to export the metrics")
+		}
+		exporter := newOCIExporter(cfg, set)
+		return exporterhelper.NewMetricsExporter(
+			ctx,
+			set,
+			cfg,
+			exporter.pushMetrics,
+			exporterhelper.WithQueue(cfg.QueueSettings),
+			exporterhelper.WithRetry(cfg.RetrySettings),
+			exporterhelper.WithStart(exporter.start),
+		)
 	}
 
+	exporter := newOracleExporter(cfg, set)
+
 	return exporterhelper.NewMetricsExporter(
 		ctx,
 		set,
@@ -67,13 +88,29 @@ func createMetricsExporter(ctx context.Context, set component.ExporterCreateSett
 		exporterhelper.WithQueue(cfg.QueueSettings),
 		exporterhelper.WithRetry(cfg.RetrySettings),
 		exporterhelper.WithStart(exporter.start),
+		exporterhelper.WithShutdown(exporter.shutdown),
 	)
 }
 
 func createLogsExporter(ctx context.Context, set component.ExporterCreateSettings, config component.ExporterConfig) (component.LogsExporter, error) {
 	cfg := config.(*Config)
+	if cfg.Backend == ociBackend {
+		if cfg.OCI.Logging.LogID == "" {
+			return nil, errors.New("oci::logging::log_id must be set to export the logs")
+		}
+		exporter := newOCIExporter(cfg, set)
+		return exporterhelper.NewLogsExporter(
+			ctx,
+			set,
+			cfg,
+			exporter.pushLogs,
+			exporterhelper.WithQueue(cfg.QueueSettings),
+			exporterhelper.WithRetry(cfg.RetrySettings),
+			exporterhelper.WithStart(exporter.start),
+		)
+	}
 
-	exporter := newLogsExporter(cfg, set)
+	exporter := newOracleExporter(cfg, set)
 
 	return exporterhelper.NewLogsExporter(
 		ctx,
@@ -83,19 +120,33 @@ func createLogsExporter(ctx context.Context, set component.ExporterCreateSetting
 		exporterhelper.WithQueue(cfg.QueueSettings),
 		exporterhelper.WithRetry(cfg.RetrySettings),
 		exporterhelper.WithStart(exporter.start),
+		exporterhelper.WithShutdown(exporter.shutdown),
 	)
 }
 
 func createDefaultConfig() component.ExporterConfig {
+	// Keep connections to the REST ingestion endpoint alive between pushes
+	// and compress the payloads, JSON log bodies compress very well.
+	httpSettings := confighttp.NewDefaultHTTPClientSettings()
+	httpSettings.Timeout = 5 * time.Second
+	httpSettings.Headers = map[string]string{
+		"User-Agent": "OpenTelemetry -> Oracle",
+	}
+	httpSettings.Compression = configcompression.Gzip
+
 	return &Config{
-		ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
-		HTTPClientSettings: confighttp.HTTPClientSettings{
-			Timeout: 5 * time.Second,
-			Headers: map[string]string{
-				"User-Agent": "OpenTelemetry -> Oracle",
-			},
+		ExporterSettings:   config.NewExporterSettings(component.NewID(typeStr)),
+		HTTPClientSettings: httpSettings,
+		QueueSettings:      exporterhelper.NewDefaultQueueSettings(),
+		RetrySettings:      exporterhelper.NewDefaultRetrySettings(),
+		HealthCheck: HealthCheckSettings{
+			Interval:         30 * time.Second,
+			Timeout:          5 * time.Second,
+			FailureThreshold: 3,
+		},
+		WaitEvents: WaitEventsSettings{
+			Interval: time.Minute,
 		},
-		QueueSettings: exporterhelper.NewDefaultQueueSettings(),
-		RetrySettings: exporterhelper.NewDefaultRetrySettings(),
+		Backend: databaseBackend,
 	}
 }
@@ -0,0 +1,249 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/oracleexporter"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+)
+
+// healthCheckers holds the health checker of each exporter configuration, shared
+// by the traces, metrics and logs exporters created from it so that the database
+// is checked once per configuration.
+var healthCheckers = sharedcomponent.NewSharedComponents()
+
+var (
+	// unhealthyCheckers is the number of health checkers reporting their database as
+	// unreachable. The pipeline watchers are notified when the first one becomes
+	// unhealthy, and when the last one recovers.
+	unhealthyCheckers   int
+	unhealthyCheckersMu sync.Mutex
+)
+
+// healthChecker periodically checks the connectivity to the database. Once the
+// failure threshold is reached the exporter is unhealthy: the extensions watching
+// the pipelines, such as the health check extension, are notified that the
+// collector isn't ready, and the pushes fail without reaching the database until
+// the next successful check.
+type healthChecker struct {
+	cfg      *Config
+	settings component.TelemetrySettings
+	client   *http.Client
+	watchers []component.PipelineWatcher
+
+	mu       sync.Mutex
+	failures int
+	lastErr  error
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// getHealthChecker returns the health checker shared by the exporters of the
+// configuration, or nil when the health check is disabled.
+func getHealthChecker(cfg *Config, settings component.TelemetrySettings) *sharedcomponent.SharedComponent {
+	if !cfg.HealthCheck.Enabled {
+		return nil
+	}
+	return healthCheckers.GetOrAdd(cfg, func() component.Component {
+		return newHealthChecker(cfg, settings)
+	})
+}
+
+func newHealthChecker(cfg *Config, settings component.TelemetrySettings) *healthChecker {
+	return &healthChecker{
+		cfg:      cfg,
+		settings: settings,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start implements component.Component.
+func (hc *healthChecker) Start(_ context.Context, host component.Host) error {
+	// the validation query is small, it is sent uncompressed
+	clientSettings := hc.cfg.HTTPClientSettings
+	clientSettings.Compression = ""
+	clientSettings.Timeout = hc.cfg.HealthCheck.Timeout
+	client, err := clientSettings.ToClient(host, hc.settings)
+	if err != nil {
+		return err
+	}
+	hc.client = client
+
+	for _, ext := range host.GetExtensions() {
+		if pw, ok := ext.(component.PipelineWatcher); ok {
+			hc.watchers = append(hc.watchers, pw)
+		}
+	}
+
+	hc.wg.Add(1)
+	go hc.run()
+	return nil
+}
+
+// Shutdown implements component.Component.
+func (hc *healthChecker) Shutdown(context.Context) error {
+	hc.stopOnce.Do(func() {
+		close(hc.stopCh)
+	})
+	hc.wg.Wait()
+
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	if hc.lastErr != nil {
+		// the collector is shutting down, the watchers are notified by the service
+		unhealthyCheckersMu.Lock()
+		unhealthyCheckers--
+		unhealthyCheckersMu.Unlock()
+		hc.lastErr = nil
+	}
+	return nil
+}
+
+// Status returns nil while the database is considered reachable, and the error of
+// the last check otherwise.
+func (hc *healthChecker) Status() error {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	return hc.lastErr
+}
+
+func (hc *healthChecker) run() {
+	defer hc.wg.Done()
+
+	ticker := time.NewTicker(hc.cfg.HealthCheck.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			hc.report(hc.check(context.Background()))
+		case <-hc.stopCh:
+			return
+		}
+	}
+}
+
+// check runs the validation query, or sends a HEAD request when there is none.
+func (hc *healthChecker) check(ctx context.Context) error {
+	endpoint := hc.cfg.HealthCheck.Endpoint
+	if endpoint == "" {
+		endpoint = hc.cfg.Endpoint
+	}
+
+	var req *http.Request
+	var err error
+	if hc.cfg.HealthCheck.Query != "" {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(hc.cfg.HealthCheck.Query))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/sql")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodHead, endpoint, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if hc.cfg.User != "" {
+		req.SetBasicAuth(hc.cfg.User, hc.cfg.Password)
+	}
+
+	resp, err := hc.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("health check failed with HTTP %d %q", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return nil
+}
+
+// report updates the health from the outcome of a check.
+func (hc *healthChecker) report(err error) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	if err == nil {
+		if hc.lastErr != nil {
+			hc.settings.Logger.Info("Oracle database is reachable again")
+			hc.notify(false)
+		}
+		hc.failures = 0
+		hc.lastErr = nil
+		return
+	}
+
+	hc.failures++
+	hc.settings.Logger.Debug("Oracle health check failed", zap.Int("failures", hc.failures), zap.Error(err))
+	if hc.failures < hc.cfg.HealthCheck.FailureThreshold {
+		return
+	}
+	if hc.lastErr == nil {
+		hc.settings.Logger.Warn("Oracle database is unreachable, reporting the exporter as unhealthy", zap.Error(err))
+		hc.notify(true)
+	}
+	hc.lastErr = err
+}
+
+// notify tells the pipeline watchers that the collector isn't ready when the first
+// health checker becomes unhealthy, and that it is ready again when the last one recovers.
+func (hc *healthChecker) notify(unhealthy bool) {
+	unhealthyCheckersMu.Lock()
+	defer unhealthyCheckersMu.Unlock()
+
+	if unhealthy {
+		unhealthyCheckers++
+		if unhealthyCheckers != 1 {
+			return
+		}
+	} else {
+		unhealthyCheckers--
+		if unhealthyCheckers != 0 {
+			return
+		}
+	}
+	for _, w := range hc.watchers {
+		var err error
+		if unhealthy {
+			err = w.NotReady()
+		} else {
+			err = w.Ready()
+		}
+		if err != nil {
+			hc.settings.Logger.Warn("Failed to notify the pipeline watcher of the exporter health", zap.Error(err))
+		}
+	}
+}
+
+// healthStatus returns the status of the shared health checker, nil when it is disabled.
+func healthStatus(hc *sharedcomponent.SharedComponent) error {
+	if hc == nil {
+		return nil
+	}
+	return hc.Unwrap().(*healthChecker).Status()
+}
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func TestHealthChecker_check(t *testing.T) {
+	var method, contentType, body, user string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		contentType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		user, _, _ = r.BasicAuth()
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	cfg.User = "otel"
+	cfg.Password = "secret"
+	cfg.HealthCheck.Enabled = true
+
+	hc := newHealthChecker(cfg, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, hc.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, hc.Shutdown(context.Background())) }()
+
+	require.NoError(t, hc.check(context.Background()))
+	assert.Equal(t, http.MethodHead, method)
+	assert.Equal(t, "otel", user)
+
+	cfg.HealthCheck.Query = "SELECT 1 FROM DUAL"
+	require.NoError(t, hc.check(context.Background()))
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "application/sql", contentType)
+	assert.Equal(t, "SELECT 1 FROM DUAL", body)
+
+	status = http.StatusServiceUnavailable
+	assert.EqualError(t, hc.check(context.Background()), `health check failed with HTTP 503 "Service Unavailable"`)
+}
+
+func TestHealthChecker_report(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "http://localhost:8080"
+	cfg.HealthCheck.Enabled = true
+	cfg.HealthCheck.FailureThreshold = 2
+
+	hc := newHealthChecker(cfg, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, hc.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, hc.Shutdown(context.Background())) }()
+
+	errUnreachable := errors.New("connection refused")
+	hc.report(errUnreachable)
+	assert.NoError(t, hc.Status())
+	hc.report(errUnreachable)
+	assert.Equal(t, errUnreachable, hc.Status())
+
+	hc.report(nil)
+	assert.NoError(t, hc.Status())
+
+	// shutting down twice doesn't panic
+	require.NoError(t, hc.Shutdown(context.Background()))
+}
+
+func TestHealthChecker_shared(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Nil(t, getHealthChecker(cfg, componenttest.NewNopTelemetrySettings()))
+	assert.NoError(t, healthStatus(nil))
+
+	cfg.HealthCheck.Enabled = true
+	hc := getHealthChecker(cfg, componenttest.NewNopTelemetrySettings())
+	require.NotNil(t, hc)
+	assert.Same(t, hc, getHealthChecker(cfg, componenttest.NewNopTelemetrySettings()))
+
+	otherCfg := createDefaultConfig().(*Config)
+	otherCfg.HealthCheck.Enabled = true
+	assert.NotSame(t, hc, getHealthChecker(otherCfg, componenttest.NewNopTelemetrySettings()))
+}
+
+type pipelineWatcher struct {
+	component.Extension
+	ready bool
+}
+
+func (w *pipelineWatcher) Ready() error {
+	w.ready = true
+	return nil
+}
+
+func (w *pipelineWatcher) NotReady() error {
+	w.ready = false
+	return nil
+}
+
+type watchedHost struct {
+	component.Host
+	extensions map[component.ID]component.Extension
+}
+
+func (h *watchedHost) GetExtensions() map[component.ID]component.Extension {
+	return h.extensions
+}
+
+func TestHealthChecker_notifiesPipelineWatchers(t *testing.T) {
+	watcher := &pipelineWatcher{ready: true}
+	host := &watchedHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Extension{component.NewID("health_check"): watcher},
+	}
+
+	newChecker := func() *healthChecker {
+		cfg := createDefaultConfig().(*Config)
+		cfg.Endpoint = "http://localhost:8080"
+		cfg.HealthCheck.Enabled = true
+		cfg.HealthCheck.FailureThreshold = 1
+		hc := newHealthChecker(cfg, componenttest.NewNopTelemetrySettings())
+		require.NoError(t, hc.Start(context.Background(), host))
+		return hc
+	}
+	hc1, hc2 := newChecker(), newChecker()
+	defer func() { require.NoError(t, hc2.Shutdown(context.Background())) }()
+
+	errUnreachable := errors.New("connection refused")
+	hc1.report(errUnreachable)
+	assert.False(t, watcher.ready)
+	hc2.report(errUnreachable)
+	assert.False(t, watcher.ready)
+
+	// the collector is ready again once every checker recovered
+	hc1.report(nil)
+	assert.False(t, watcher.ready)
+	hc2.report(nil)
+	assert.True(t, watcher.ready)
+
+	// an unhealthy checker shut down doesn't prevent the next recovery
+	hc1.report(errUnreachable)
+	require.NoError(t, hc1.Shutdown(context.Background()))
+	hc2.report(errUnreachable)
+	hc2.report(nil)
+	assert.True(t, watcher.ready)
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/oracleexporter"
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	tagSignalKey, _ = tag.NewKey("signal")
+	tagCodeKey, _   = tag.NewKey("code")
+	tagEventKey, _  = tag.NewKey("event")
+
+	mRowsWritten     = stats.Int64("exporter_oracle_rows_written", "Spans, metric data points and log records written to the database", stats.UnitDimensionless)
+	mBatchLatency    = stats.Float64("exporter_oracle_batch_latency", "Time taken by the database to ingest a batch", stats.UnitMilliseconds)
+	mOracleErrors    = stats.Int64("exporter_oracle_errors", "Batches rejected by the database, by ORA error code", stats.UnitDimensionless)
+	mWaitEventWaits  = stats.Int64("exporter_oracle_wait_event_waits", "Waits of the sessions of the exporter user on the wait event", stats.UnitDimensionless)
+	mWaitEventWaited = stats.Float64("exporter_oracle_wait_event_time", "Time waited by the sessions of the exporter user on the wait event", stats.UnitMilliseconds)
+)
+
+// oraErrorCode matches the code of the Oracle errors found in the responses of the database.
+var oraErrorCode = regexp.MustCompile(`ORA-\d{5}`)
+
+// MetricViews returns the metrics views related to the Oracle exporter.
+func MetricViews() []*view.View {
+	return []*view.View{
+		{
+			Name:        mRowsWritten.Name(),
+			Measure:     mRowsWritten,
+			Description: mRowsWritten.Description(),
+			TagKeys:     []tag.Key{tagSignalKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mBatchLatency.Name(),
+			Measure:     mBatchLatency,
+			Description: mBatchLatency.Description(),
+			TagKeys:     []tag.Key{tagSignalKey},
+			Aggregation: view.Distribution(5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000),
+		},
+		{
+			Name:        mOracleErrors.Name(),
+			Measure:     mOracleErrors,
+			Description: mOracleErrors.Description(),
+			TagKeys:     []tag.Key{tagSignalKey, tagCodeKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        mWaitEventWaits.Name(),
+			Measure:     mWaitEventWaits,
+			Description: mWaitEventWaits.Description(),
+			TagKeys:     []tag.Key{tagEventKey},
+			Aggregation: view.LastValue(),
+		},
+		{
+			Name:        mWaitEventWaited.Name(),
+			Measure:     mWaitEventWaited,
+			Description: mWaitEventWaited.Description(),
+			TagKeys:     []tag.Key{tagEventKey},
+			Aggregation: view.LastValue(),
+		},
+	}
+}
+
+// recordBatch records the outcome of a batch the database responded to: the rows written when
+// it succeeded, and the ORA error codes of the response otherwise.
+func recordBatch(ctx context.Context, signal string, rows int, latency time.Duration, succeeded bool, respBody []byte) {
+	mutators := []tag.Mutator{tag.Upsert(tagSignalKey, signal)}
+	_ = stats.RecordWithTags(ctx, mutators, mBatchLatency.M(float64(latency)/float64(time.Millisecond)))
+	if succeeded {
+		_ = stats.RecordWithTags(ctx, mutators, mRowsWritten.M(int64(rows)))
+		return
+	}
+	seen := map[string]bool{}
+	for _, code := range oraErrorCode.FindAll(respBody, -1) {
+		if seen[string(code)] {
+			continue
+		}
+		seen[string(code)] = true
+		_ = stats.RecordWithTags(ctx, append(mutators, tag.Upsert(tagCodeKey, string(code))), mOracleErrors.M(1))
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// rowsOf returns the sum recorded by the view for each value of the tag.
+func rowsOf(t *testing.T, name string, key tag.Key) map[string]int64 {
+	rows, err := view.RetrieveData(name)
+	require.NoError(t, err)
+	values := map[string]int64{}
+	for _, row := range rows {
+		for _, tg := range row.Tags {
+			if tg.Key == key {
+				values[tg.Value] = int64(row.Data.(*view.SumData).Value)
+			}
+		}
+	}
+	return values
+}
+
+func TestOracleExporter_metrics(t *testing.T) {
+	// reset the data recorded by the other tests
+	view.Unregister(MetricViews()...)
+	require.NoError(t, view.Register(MetricViews()...))
+	defer view.Unregister(MetricViews()...)
+
+	status := http.StatusOK
+	body := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	exporter := newOracleExporter(cfg, componenttest.NewNopExporterCreateSettings())
+	require.NoError(t, exporter.start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, exporter.shutdown(context.Background())) }()
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty()
+	spans.AppendEmpty()
+	require.NoError(t, exporter.pushTraces(context.Background(), td))
+	assert.Equal(t, map[string]int64{"traces": 2}, rowsOf(t, mRowsWritten.Name(), tagSignalKey))
+
+	status = http.StatusBadRequest
+	body = `{"code":"BadRequest","message":"ORA-01400: cannot insert NULL into (\"OTEL\".\"SPANS\".\"TRACE_ID\")\nORA-06512: at line 1\nORA-01400"}`
+	require.Error(t, exporter.pushTraces(context.Background(), td))
+	assert.Equal(t, map[string]int64{"traces": 2}, rowsOf(t, mRowsWritten.Name(), tagSignalKey))
+	assert.Equal(t, map[string]int64{"ORA-01400": 1, "ORA-06512": 1}, rowsOf(t, mOracleErrors.Name(), tagCodeKey))
+
+	rows, err := view.RetrieveData(mBatchLatency.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, int64(2), rows[0].Data.(*view.DistributionData).Count)
+}
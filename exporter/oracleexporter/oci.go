@@ -0,0 +1,466 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/oracleexporter"
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	// ociMaxMetricStreams is the maximum number of metric streams of a PostMetricData request.
+	ociMaxMetricStreams = 50
+	// ociMaxDimensions is the maximum number of dimensions of a metric stream.
+	ociMaxDimensions = 20
+	// ociLogType is the type of the log entry batches ingested into OCI Logging.
+	ociLogType = "opentelemetry"
+	// ociDefaultSource is the source of the telemetry whose resource has no name.
+	ociDefaultSource = "opentelemetry-collector"
+)
+
+var (
+	errOCITraces = errors.New("the traces can't be exported to the oci backend")
+
+	// ociInvalidMetricNameChars matches the characters not allowed in the OCI metric names.
+	ociInvalidMetricNameChars = regexp.MustCompile(`[^a-zA-Z0-9._\-$]`)
+)
+
+// ociExporter sends the logs to OCI Logging and the metrics to OCI Monitoring, signing
+// the requests with the API signing key of the user.
+type ociExporter struct {
+	cfg      *Config
+	settings component.TelemetrySettings
+	client   *http.Client
+	signer   *ociSigner
+
+	logsURL    string
+	metricsURL string
+}
+
+func newOCIExporter(cfg *Config, set component.ExporterCreateSettings) *ociExporter {
+	return &ociExporter{
+		cfg:      cfg,
+		settings: set.TelemetrySettings,
+	}
+}
+
+// start loads the credentials and creates the HTTP client.
+func (e *ociExporter) start(_ context.Context, host component.Host) error {
+	signer, region, err := newOCISigner(e.cfg.OCI)
+	if err != nil {
+		return err
+	}
+	e.signer = signer
+
+	if e.cfg.OCI.Logging.LogID != "" {
+		endpoint, err := ociEndpoint(e.cfg.OCI.Logging.Endpoint, "https://ingestion.logging.%s.oci.oraclecloud.com", region)
+		if err != nil {
+			return err
+		}
+		e.logsURL = endpoint + "/20200831/logs/" + url.PathEscape(e.cfg.OCI.Logging.LogID) + "/actions/push"
+	}
+	if e.cfg.OCI.Monitoring.CompartmentID != "" {
+		endpoint, err := ociEndpoint(e.cfg.OCI.Monitoring.Endpoint, "https://telemetry-ingestion.%s.oraclecloud.com", region)
+		if err != nil {
+			return err
+		}
+		e.metricsURL = endpoint + "/20180401/metrics"
+	}
+
+	client, err := newHTTPClient(e.cfg, host, e.settings)
+	if err != nil {
+		return err
+	}
+	e.client = client
+	return nil
+}
+
+// ociEndpoint returns the configured endpoint, or the endpoint of the API in the region.
+func ociEndpoint(endpoint string, regionalEndpoint string, region string) (string, error) {
+	if endpoint != "" {
+		return strings.TrimSuffix(endpoint, "/"), nil
+	}
+	if region == "" {
+		return "", fmt.Errorf("oci::region must be set, either explicitly or in the configuration file")
+	}
+	return fmt.Sprintf(regionalEndpoint, region), nil
+}
+
+// ociPutLogsDetails is the payload of the PutLogs requests of the logging ingestion API.
+type ociPutLogsDetails struct {
+	Specversion     string             `json:"specversion"`
+	LogEntryBatches []ociLogEntryBatch `json:"logEntryBatches"`
+}
+
+type ociLogEntryBatch struct {
+	Entries             []ociLogEntry `json:"entries"`
+	Source              string        `json:"source"`
+	Type                string        `json:"type"`
+	Subject             string        `json:"subject,omitempty"`
+	Defaultlogentrytime string        `json:"defaultlogentrytime"`
+}
+
+type ociLogEntry struct {
+	Data string `json:"data"`
+	ID   string `json:"id"`
+	Time string `json:"time"`
+}
+
+// ociLogData is the content of a log entry, serialized as JSON.
+type ociLogData struct {
+	Body           interface{}            `json:"body,omitempty"`
+	SeverityText   string                 `json:"severity_text,omitempty"`
+	SeverityNumber int32                  `json:"severity_number,omitempty"`
+	TraceID        string                 `json:"trace_id,omitempty"`
+	SpanID         string                 `json:"span_id,omitempty"`
+	Attributes     map[string]interface{} `json:"attributes,omitempty"`
+	Resource       map[string]interface{} `json:"resource,omitempty"`
+}
+
+// pushLogs ingests the log records into the custom log, in a batch per resource and scope
+// whose source is the name of the service or of the host.
+func (e *ociExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
+	now := time.Now()
+	details := ociPutLogsDetails{Specversion: "1.0"}
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		resource := rl.Resource().Attributes().AsRaw()
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			if sl.LogRecords().Len() == 0 {
+				continue
+			}
+			batch := ociLogEntryBatch{
+				Source:              ociLogSource(rl.Resource()),
+				Type:                ociLogType,
+				Subject:             sl.Scope().Name(),
+				Defaultlogentrytime: now.UTC().Format(time.RFC3339Nano),
+			}
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				entry, err := newOCILogEntry(sl.LogRecords().At(k), resource, now)
+				if err != nil {
+					return consumererror.NewPermanent(err)
+				}
+				batch.Entries = append(batch.Entries, entry)
+			}
+			details.LogEntryBatches = append(details.LogEntryBatches, batch)
+		}
+	}
+	if len(details.LogEntryBatches) == 0 {
+		return nil
+	}
+	_, err := e.send(ctx, "logs", ld.LogRecordCount(), e.logsURL, details)
+	return err
+}
+
+func ociLogSource(resource pcommon.Resource) string {
+	for _, key := range []string{"service.name", "host.name"} {
+		if v, ok := resource.Attributes().Get(key); ok && v.AsString() != "" {
+			return v.AsString()
+		}
+	}
+	return ociDefaultSource
+}
+
+func newOCILogEntry(lr plog.LogRecord, resource map[string]interface{}, now time.Time) (ociLogEntry, error) {
+	data := ociLogData{
+		Body:           lr.Body().AsRaw(),
+		SeverityText:   lr.SeverityText(),
+		SeverityNumber: int32(lr.SeverityNumber()),
+		Attributes:     lr.Attributes().AsRaw(),
+		Resource:       resource,
+	}
+	if !lr.TraceID().IsEmpty() {
+		data.TraceID = lr.TraceID().HexString()
+	}
+	if !lr.SpanID().IsEmpty() {
+		data.SpanID = lr.SpanID().HexString()
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return ociLogEntry{}, err
+	}
+
+	timestamp := lr.Timestamp()
+	if timestamp == 0 {
+		timestamp = lr.ObservedTimestamp()
+	}
+	t := now
+	if timestamp != 0 {
+		t = timestamp.AsTime()
+	}
+	id, err := newOCILogEntryID()
+	if err != nil {
+		return ociLogEntry{}, err
+	}
+	return ociLogEntry{
+		Data: string(encoded),
+		ID:   id,
+		Time: t.UTC().Format(time.RFC3339Nano),
+	}, nil
+}
+
+// newOCILogEntryID returns a random UUID identifying a log entry.
+func newOCILogEntryID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// ociPostMetricDataDetails is the payload of the PostMetricData requests of the monitoring API.
+type ociPostMetricDataDetails struct {
+	MetricData     []*ociMetricData `json:"metricData"`
+	BatchAtomicity string           `json:"batchAtomicity"`
+}
+
+type ociMetricData struct {
+	Namespace     string            `json:"namespace"`
+	CompartmentID string            `json:"compartmentId"`
+	ResourceGroup string            `json:"resourceGroup,omitempty"`
+	Name          string            `json:"name"`
+	Dimensions    map[string]string `json:"dimensions"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Datapoints    []ociDatapoint    `json:"datapoints"`
+}
+
+type ociDatapoint struct {
+	Timestamp string  `json:"timestamp"`
+	Value     float64 `json:"value"`
+	Count     uint64  `json:"count,omitempty"`
+}
+
+// ociPostMetricDataResponse is the response of the PostMetricData requests, listing the
+// metric streams rejected because of their content.
+type ociPostMetricDataResponse struct {
+	FailedMetricsCount int `json:"failedMetricsCount"`
+	FailedMetrics      []struct {
+		Message string `json:"message"`
+	} `json:"failedMetrics"`
+}
+
+// pushMetrics posts the data points to OCI Monitoring, by batches of metric streams. The
+// histograms and summaries are posted as their mean value, weighted by their count.
+func (e *ociExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) error {
+	streams := e.metricStreams(md)
+	for start := 0; start < len(streams); start += ociMaxMetricStreams {
+		end := start + ociMaxMetricStreams
+		if end > len(streams) {
+			end = len(streams)
+		}
+		details := ociPostMetricDataDetails{MetricData: streams[start:end], BatchAtomicity: "NON_ATOMIC"}
+		rows := 0
+		for _, stream := range details.MetricData {
+			rows += len(stream.Datapoints)
+		}
+
+		respBody, err := e.send(ctx, "metrics", rows, e.metricsURL, details)
+		if err != nil {
+			return err
+		}
+		var resp ociPostMetricDataResponse
+		if err = json.Unmarshal(respBody, &resp); err == nil && resp.FailedMetricsCount > 0 {
+			messages := make([]string, 0, len(resp.FailedMetrics))
+			for _, failed := range resp.FailedMetrics {
+				messages = append(messages, failed.Message)
+			}
+			return consumererror.NewPermanent(fmt.Errorf("oci monitoring rejected %d metric streams: %s",
+				resp.FailedMetricsCount, strings.Join(messages, "; ")))
+		}
+	}
+	return nil
+}
+
+// metricStreams groups the data points by metric stream, identified by the name and the
+// dimensions of the data points.
+func (e *ociExporter) metricStreams(md pmetric.Metrics) []*ociMetricData {
+	var streams []*ociMetricData
+	index := map[string]*ociMetricData{}
+	add := func(m pmetric.Metric, resource pcommon.Map, attributes pcommon.Map, timestamp pcommon.Timestamp, value float64, count uint64) {
+		name := ociInvalidMetricNameChars.ReplaceAllString(m.Name(), "_")
+		dimensions := ociDimensions(resource, attributes)
+		key := name + "\x00" + ociDimensionsKey(dimensions)
+		stream, ok := index[key]
+		if !ok {
+			stream = &ociMetricData{
+				Namespace:     e.cfg.OCI.Monitoring.Namespace,
+				CompartmentID: e.cfg.OCI.Monitoring.CompartmentID,
+				ResourceGroup: e.cfg.OCI.Monitoring.ResourceGroup,
+				Name:          name,
+				Dimensions:    dimensions,
+			}
+			if m.Unit() != "" {
+				stream.Metadata = map[string]string{"unit": m.Unit()}
+			}
+			index[key] = stream
+			streams = append(streams, stream)
+		}
+		stream.Datapoints = append(stream.Datapoints, ociDatapoint{
+			Timestamp: timestamp.AsTime().UTC().Format(time.RFC3339Nano),
+			Value:     value,
+			Count:     count,
+		})
+	}
+	addNumbers := func(m pmetric.Metric, resource pcommon.Map, dps pmetric.NumberDataPointSlice) {
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			value := dp.DoubleValue()
+			if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+				value = float64(dp.IntValue())
+			}
+			add(m, resource, dp.Attributes(), dp.Timestamp(), value, 0)
+		}
+	}
+
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		resource := rm.Resource().Attributes()
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			metrics := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				m := metrics.At(k)
+				switch m.Type() {
+				case pmetric.MetricTypeGauge:
+					addNumbers(m, resource, m.Gauge().DataPoints())
+				case pmetric.MetricTypeSum:
+					addNumbers(m, resource, m.Sum().DataPoints())
+				case pmetric.MetricTypeHistogram:
+					dps := m.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						if dp := dps.At(l); dp.HasSum() && dp.Count() > 0 {
+							add(m, resource, dp.Attributes(), dp.Timestamp(), dp.Sum()/float64(dp.Count()), dp.Count())
+						}
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					dps := m.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						if dp := dps.At(l); dp.HasSum() && dp.Count() > 0 {
+							add(m, resource, dp.Attributes(), dp.Timestamp(), dp.Sum()/float64(dp.Count()), dp.Count())
+						}
+					}
+				case pmetric.MetricTypeSummary:
+					dps := m.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						if dp := dps.At(l); dp.Count() > 0 {
+							add(m, resource, dp.Attributes(), dp.Timestamp(), dp.Sum()/float64(dp.Count()), dp.Count())
+						}
+					}
+				}
+			}
+		}
+	}
+	return streams
+}
+
+// ociDimensions returns the dimensions of a data point, made of its attributes and of the ones of
+// its resource. The dimension keys can't contain periods nor spaces, and at most ociMaxDimensions
+// are kept, the data point attributes first. A metric stream needs at least one dimension, the
+// data points without any attribute are posted with the source dimension.
+func ociDimensions(resource pcommon.Map, attributes pcommon.Map) map[string]string {
+	keyReplacer := strings.NewReplacer(".", "_", " ", "_")
+	dimensions := map[string]string{}
+	for _, attrs := range []pcommon.Map{attributes, resource} {
+		var keys []string
+		attrs.Range(func(k string, v pcommon.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		sort.Strings(keys)
+		for _, k := range keys {
+			v, _ := attrs.Get(k)
+			key, value := keyReplacer.Replace(k), v.AsString()
+			if _, ok := dimensions[key]; ok || value == "" || len(dimensions) == ociMaxDimensions {
+				continue
+			}
+			dimensions[key] = value
+		}
+	}
+	if len(dimensions) == 0 {
+		dimensions["source"] = ociDefaultSource
+	}
+	return dimensions
+}
+
+func ociDimensionsKey(dimensions map[string]string) string {
+	keys := make([]string, 0, len(dimensions))
+	for k := range dimensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('\x00')
+		b.WriteString(dimensions[k])
+		b.WriteByte('\x00')
+	}
+	return b.String()
+}
+
+// send posts the signed payload and returns the body of the response. As for the database,
+// the throttled requests and the server errors are retried.
+func (e *ociExporter) send(ctx context.Context, signal string, rows int, endpoint string, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, consumererror.NewPermanent(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, consumererror.NewPermanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err = e.signer.sign(req, body); err != nil {
+		return nil, consumererror.NewPermanent(err)
+	}
+
+	start := time.Now()
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	succeeded := resp.StatusCode >= 200 && resp.StatusCode < 300
+	recordBatch(ctx, signal, rows, time.Since(start), succeeded, respBody)
+
+	switch {
+	case succeeded:
+		return respBody, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, fmt.Errorf("oci %s ingestion returned %q %q", signal, resp.Status, string(respBody))
+	default:
+		return nil, consumererror.NewPermanent(fmt.Errorf("oci %s ingestion returned %q %q", signal, resp.Status, string(respBody)))
+	}
+}
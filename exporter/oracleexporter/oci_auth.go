@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/oracleexporter"
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOCIConfigFile = "~/.oci/config"
+	defaultOCIProfile    = "DEFAULT"
+)
+
+// ociSignedHeaders are the headers covered by the signature of the requests with a body.
+var ociSignedHeaders = []string{"date", "(request-target)", "host", "content-length", "content-type", "x-content-sha256"}
+
+// ociSigner signs the requests with the API signing key of a user, following the
+// request signature scheme of the OCI APIs implemented by the OCI SDKs.
+type ociSigner struct {
+	keyID string
+	key   *rsa.PrivateKey
+	now   func() time.Time
+}
+
+// newOCISigner loads the credentials of the profile of the configuration file, overridden by
+// the ones explicitly set. It also returns the region, which doesn't need to be in the
+// configuration file when the endpoints are set.
+func newOCISigner(cfg OCISettings) (*ociSigner, string, error) {
+	profile, err := loadOCIProfile(cfg)
+	if err != nil {
+		return nil, "", err
+	}
+	for key, value := range map[string]string{
+		"tenancy":     cfg.Tenancy,
+		"user":        cfg.User,
+		"fingerprint": cfg.Fingerprint,
+		"key_file":    cfg.KeyFile,
+		"region":      cfg.Region,
+	} {
+		if value != "" {
+			profile[key] = value
+		}
+	}
+	for _, key := range []string{"tenancy", "user", "fingerprint", "key_file"} {
+		if profile[key] == "" {
+			return nil, "", fmt.Errorf("oci::%s must be set, either explicitly or in the configuration file", key)
+		}
+	}
+
+	keyFile, err := expandHome(profile["key_file"])
+	if err != nil {
+		return nil, "", err
+	}
+	pemBytes, err := os.ReadFile(filepath.Clean(keyFile))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read the OCI API signing key: %w", err)
+	}
+	key, err := parseOCIPrivateKey(pemBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	return &ociSigner{
+		keyID: profile["tenancy"] + "/" + profile["user"] + "/" + profile["fingerprint"],
+		key:   key,
+		now:   time.Now,
+	}, profile["region"], nil
+}
+
+// loadOCIProfile returns the keys of the profile of the configuration file. A missing default
+// configuration file is ignored, the credentials then have to be set explicitly.
+func loadOCIProfile(cfg OCISettings) (map[string]string, error) {
+	path, profile := cfg.ConfigFile, cfg.Profile
+	if path == "" {
+		path = defaultOCIConfigFile
+	}
+	if profile == "" {
+		profile = defaultOCIProfile
+	}
+	path, err := expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(filepath.Clean(path))
+	if os.IsNotExist(err) && cfg.ConfigFile == "" {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the OCI configuration file: %w", err)
+	}
+	return parseOCIProfile(content, profile)
+}
+
+// parseOCIProfile parses the profile of the INI formatted configuration file. The keys missing
+// from the profile are inherited from the DEFAULT profile, as in the OCI SDKs.
+func parseOCIProfile(content []byte, profile string) (map[string]string, error) {
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if profiles[name] == nil {
+				profiles[name] = map[string]string{}
+			}
+			current = profiles[name]
+		default:
+			key, value, found := strings.Cut(line, "=")
+			if !found || current == nil {
+				return nil, fmt.Errorf("invalid line %q in the OCI configuration file", line)
+			}
+			current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	keys, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found in the OCI configuration file", profile)
+	}
+	merged := map[string]string{}
+	for key, value := range profiles[defaultOCIProfile] {
+		merged[key] = value
+	}
+	for key, value := range keys {
+		merged[key] = value
+	}
+	return merged, nil
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// parseOCIPrivateKey parses the unencrypted PKCS #1 or PKCS #8 RSA key generated for the API signing.
+func parseOCIPrivateKey(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("the OCI API signing key isn't PEM encoded")
+	}
+	if _, encrypted := block.Headers["Proc-Type"]; encrypted || block.Type == "ENCRYPTED PRIVATE KEY" {
+		return nil, fmt.Errorf("encrypted OCI API signing keys aren't supported")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the OCI API signing key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the OCI API signing key must be an RSA key, got %T", key)
+	}
+	return rsaKey, nil
+}
+
+// sign sets the Authorization header of the request, along with the signed headers it computes.
+func (s *ociSigner) sign(req *http.Request, body []byte) error {
+	digest := sha256.Sum256(body)
+	req.Header.Set("Date", s.now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	req.Header.Set("X-Content-Sha256", base64.StdEncoding.EncodeToString(digest[:]))
+	req.Host = req.URL.Host
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, ociSigningDigest(req))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf(
+		`Signature version="1",headers="%s",keyId="%s",algorithm="rsa-sha256",signature="%s"`,
+		strings.Join(ociSignedHeaders, " "), s.keyID, base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// ociSigningDigest returns the SHA-256 digest of the signing string of the request, made of
+// the signed headers one per line.
+func ociSigningDigest(req *http.Request) []byte {
+	lines := make([]string, len(ociSignedHeaders))
+	for i, header := range ociSignedHeaders {
+		var value string
+		switch header {
+		case "(request-target)":
+			value = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			value = req.Host
+		default:
+			value = req.Header.Get(header)
+		}
+		lines[i] = header + ": " + value
+	}
+	digest := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return digest[:]
+}
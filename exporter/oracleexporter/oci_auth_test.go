@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeOCIKey writes a new API signing key to a PEM file of the directory.
+func writeOCIKey(t *testing.T, dir string) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(dir, "oci_api_key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+	return key, path
+}
+
+func TestParseOCIProfile(t *testing.T) {
+	content := []byte(`
+# comment
+[DEFAULT]
+user=ocid1.user.oc1..default
+fingerprint = 20:3b:97:13
+tenancy=ocid1.tenancy.oc1..acme
+region=us-ashburn-1
+
+[ADMIN]
+user=ocid1.user.oc1..admin
+`)
+	profile, err := parseOCIProfile(content, "ADMIN")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"user":        "ocid1.user.oc1..admin",
+		"fingerprint": "20:3b:97:13",
+		"tenancy":     "ocid1.tenancy.oc1..acme",
+		"region":      "us-ashburn-1",
+	}, profile)
+
+	_, err = parseOCIProfile(content, "OPS")
+	assert.EqualError(t, err, `profile "OPS" not found in the OCI configuration file`)
+
+	_, err = parseOCIProfile([]byte("user=ocid1.user.oc1..default"), "DEFAULT")
+	assert.EqualError(t, err, `invalid line "user=ocid1.user.oc1..default" in the OCI configuration file`)
+}
+
+func TestNewOCISigner(t *testing.T) {
+	dir := t.TempDir()
+	_, keyFile := writeOCIKey(t, dir)
+	configFile := filepath.Join(dir, "config")
+	require.NoError(t, os.WriteFile(configFile, []byte(`[DEFAULT]
+user=ocid1.user.oc1..default
+fingerprint=20:3b:97:13
+tenancy=ocid1.tenancy.oc1..acme
+region=us-ashburn-1
+key_file=`+keyFile+`
+`), 0600))
+
+	signer, region, err := newOCISigner(OCISettings{ConfigFile: configFile, User: "ocid1.user.oc1..otel", Region: "eu-frankfurt-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "ocid1.tenancy.oc1..acme/ocid1.user.oc1..otel/20:3b:97:13", signer.keyID)
+	assert.Equal(t, "eu-frankfurt-1", region)
+
+	// the credentials can be set without configuration file
+	_, _, err = newOCISigner(OCISettings{ConfigFile: filepath.Join(dir, "missing")})
+	assert.ErrorContains(t, err, "failed to read the OCI configuration file")
+	_, _, err = newOCISigner(OCISettings{
+		ConfigFile:  configFile,
+		Profile:     "DEFAULT",
+		Tenancy:     "ocid1.tenancy.oc1..acme",
+		User:        "ocid1.user.oc1..otel",
+		Fingerprint: "20:3b:97:13",
+		KeyFile:     filepath.Join(dir, "missing.pem"),
+	})
+	assert.ErrorContains(t, err, "failed to read the OCI API signing key")
+
+	encrypted := filepath.Join(dir, "encrypted.pem")
+	require.NoError(t, os.WriteFile(encrypted, pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte{1}}), 0600))
+	_, _, err = newOCISigner(OCISettings{ConfigFile: configFile, KeyFile: encrypted})
+	assert.EqualError(t, err, "encrypted OCI API signing keys aren't supported")
+}
+
+func TestOCISignerSign(t *testing.T) {
+	key, _ := writeOCIKey(t, t.TempDir())
+	signer := &ociSigner{
+		keyID: "ocid1.tenancy.oc1..acme/ocid1.user.oc1..otel/20:3b:97:13",
+		key:   key,
+		now:   func() time.Time { return time.Date(2022, 11, 15, 10, 0, 0, 0, time.UTC) },
+	}
+	body := []byte(`{"metricData":[]}`)
+	req, err := http.NewRequest(http.MethodPost, "https://telemetry-ingestion.us-ashburn-1.oraclecloud.com/20180401/metrics", strings.NewReader(string(body)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	require.NoError(t, signer.sign(req, body))
+
+	assert.Equal(t, "Tue, 15 Nov 2022 10:00:00 GMT", req.Header.Get("Date"))
+	assert.Equal(t, "17", req.Header.Get("Content-Length"))
+	assert.Equal(t, "ehckKbOjkqpAGOtAH798Bb72JS9Bxz4qH3CFsABB2i8=", req.Header.Get("X-Content-Sha256"))
+
+	authorization := regexp.MustCompile(`^Signature version="1",headers="date \(request-target\) host content-length content-type x-content-sha256",keyId="ocid1.tenancy.oc1..acme/ocid1.user.oc1..otel/20:3b:97:13",algorithm="rsa-sha256",signature="(.+)"$`)
+	matches := authorization.FindStringSubmatch(req.Header.Get("Authorization"))
+	require.Len(t, matches, 2)
+	signature, err := base64.StdEncoding.DecodeString(matches[1])
+	require.NoError(t, err)
+
+	signingString := strings.Join([]string{
+		"date: Tue, 15 Nov 2022 10:00:00 GMT",
+		"(request-target): post /20180401/metrics",
+		"host: telemetry-ingestion.us-ashburn-1.oraclecloud.com",
+		"content-length: 17",
+		"content-type: application/json",
+		"x-content-sha256: ehckKbOjkqpAGOtAH798Bb72JS9Bxz4qH3CFsABB2i8=",
+	}, "\n")
+	digest := sha256.Sum256([]byte(signingString))
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+}
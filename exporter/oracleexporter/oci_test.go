@@ -0,0 +1,278 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// newTestOCIExporter returns a started exporter of the oci backend sending to the server.
+func newTestOCIExporter(t *testing.T, server *httptest.Server) *ociExporter {
+	_, keyFile := writeOCIKey(t, t.TempDir())
+	cfg := createDefaultConfig().(*Config)
+	cfg.Backend = ociBackend
+	cfg.OCI = OCISettings{
+		ConfigFile:  "testdata/oci_config",
+		Tenancy:     "ocid1.tenancy.oc1..acme",
+		User:        "ocid1.user.oc1..otel",
+		Fingerprint: "20:3b:97:13",
+		KeyFile:     keyFile,
+		Logging: OCILoggingSettings{
+			Endpoint: server.URL,
+			LogID:    "ocid1.log.oc1..otel",
+		},
+		Monitoring: OCIMonitoringSettings{
+			Endpoint:      server.URL,
+			CompartmentID: "ocid1.compartment.oc1..otel",
+			Namespace:     "otel_collector",
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	exporter := newOCIExporter(cfg, componenttest.NewNopExporterCreateSettings())
+	require.NoError(t, exporter.start(context.Background(), componenttest.NewNopHost()))
+	return exporter
+}
+
+func TestOCIExporter_pushLogs(t *testing.T) {
+	var path, authorization string
+	var details ociPutLogsDetails
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&details))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	exporter := newTestOCIExporter(t, server)
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("otelcol/checkout")
+	lr := sl.LogRecords().AppendEmpty()
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Date(2022, 11, 15, 10, 0, 0, 0, time.UTC)))
+	lr.SetSeverityText("ERROR")
+	lr.SetSeverityNumber(plog.SeverityNumberError)
+	lr.SetTraceID([16]byte{1})
+	lr.Body().SetStr("payment declined")
+	lr.Attributes().PutStr("order.id", "42")
+	// the resources without name are reported as the collector
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+
+	require.NoError(t, exporter.pushLogs(context.Background(), ld))
+	assert.Equal(t, "/20200831/logs/ocid1.log.oc1..otel/actions/push", path)
+	assert.True(t, strings.HasPrefix(authorization, `Signature version="1"`))
+	assert.Equal(t, "1.0", details.Specversion)
+	require.Len(t, details.LogEntryBatches, 2)
+
+	batch := details.LogEntryBatches[0]
+	assert.Equal(t, "checkout", batch.Source)
+	assert.Equal(t, ociLogType, batch.Type)
+	assert.Equal(t, "otelcol/checkout", batch.Subject)
+	require.Len(t, batch.Entries, 1)
+	assert.Equal(t, "2022-11-15T10:00:00Z", batch.Entries[0].Time)
+	assert.Len(t, batch.Entries[0].ID, 36)
+	assert.JSONEq(t, `{
+		"body": "payment declined",
+		"severity_text": "ERROR",
+		"severity_number": 17,
+		"trace_id": "01000000000000000000000000000000",
+		"attributes": {"order.id": "42"},
+		"resource": {"service.name": "checkout"}
+	}`, batch.Entries[0].Data)
+	assert.Equal(t, ociDefaultSource, details.LogEntryBatches[1].Source)
+
+	status = http.StatusTooManyRequests
+	err := exporter.pushLogs(context.Background(), ld)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+
+	status = http.StatusUnauthorized
+	assert.True(t, consumererror.IsPermanent(exporter.pushLogs(context.Background(), ld)))
+}
+
+func TestOCIExporter_pushMetrics(t *testing.T) {
+	var requests []ociPostMetricDataDetails
+	response := `{"failedMetricsCount":0}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/20180401/metrics", r.URL.Path)
+		var details ociPostMetricDataDetails
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&details))
+		requests = append(requests, details)
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+	exporter := newTestOCIExporter(t, server)
+
+	timestamp := pcommon.NewTimestampFromTime(time.Date(2022, 11, 15, 10, 0, 0, 0, time.UTC))
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	requestCount := metrics.AppendEmpty()
+	requestCount.SetName("http.server.requests")
+	requestCount.SetUnit("1")
+	requestCount.SetEmptySum()
+	for _, method := range []string{"GET", "GET", "POST"} {
+		dp := requestCount.Sum().DataPoints().AppendEmpty()
+		dp.SetTimestamp(timestamp)
+		dp.SetIntValue(3)
+		dp.Attributes().PutStr("http.method", method)
+	}
+	duration := metrics.AppendEmpty()
+	duration.SetName("http.server.duration")
+	dp := duration.SetEmptyHistogram().DataPoints().AppendEmpty()
+	dp.SetTimestamp(timestamp)
+	dp.SetCount(4)
+	dp.SetSum(100)
+	// the histograms without sum can't be posted
+	duration.Histogram().DataPoints().AppendEmpty().SetCount(1)
+
+	require.NoError(t, exporter.pushMetrics(context.Background(), md))
+	require.Len(t, requests, 1)
+	assert.Equal(t, "NON_ATOMIC", requests[0].BatchAtomicity)
+	assert.Equal(t, []*ociMetricData{
+		{
+			Namespace:     "otel_collector",
+			CompartmentID: "ocid1.compartment.oc1..otel",
+			Name:          "http.server.requests",
+			Dimensions:    map[string]string{"http_method": "GET", "service_name": "checkout"},
+			Metadata:      map[string]string{"unit": "1"},
+			Datapoints: []ociDatapoint{
+				{Timestamp: "2022-11-15T10:00:00Z", Value: 3},
+				{Timestamp: "2022-11-15T10:00:00Z", Value: 3},
+			},
+		},
+		{
+			Namespace:     "otel_collector",
+			CompartmentID: "ocid1.compartment.oc1..otel",
+			Name:          "http.server.requests",
+			Dimensions:    map[string]string{"http_method": "POST", "service_name": "checkout"},
+			Metadata:      map[string]string{"unit": "1"},
+			Datapoints:    []ociDatapoint{{Timestamp: "2022-11-15T10:00:00Z", Value: 3}},
+		},
+		{
+			Namespace:     "otel_collector",
+			CompartmentID: "ocid1.compartment.oc1..otel",
+			Name:          "http.server.duration",
+			Dimensions:    map[string]string{"service_name": "checkout"},
+			Datapoints:    []ociDatapoint{{Timestamp: "2022-11-15T10:00:00Z", Value: 25, Count: 4}},
+		},
+	}, requests[0].MetricData)
+
+	// the metric streams rejected by the API aren't retried
+	response = `{"failedMetricsCount":1,"failedMetrics":[{"message":"invalid dimension"}]}`
+	err := exporter.pushMetrics(context.Background(), md)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.EqualError(t, err, "Permanent error: oci monitoring rejected 1 metric streams: invalid dimension")
+}
+
+func TestOCIExporter_pushMetricsBatches(t *testing.T) {
+	var streams []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var details ociPostMetricDataDetails
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&details))
+		streams = append(streams, len(details.MetricData))
+	}))
+	defer server.Close()
+	exporter := newTestOCIExporter(t, server)
+
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	for i := 0; i < 120; i++ {
+		m := metrics.AppendEmpty()
+		m.SetName(fmt.Sprintf("queue/%d size", i))
+		m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(1)
+	}
+
+	require.NoError(t, exporter.pushMetrics(context.Background(), md))
+	assert.Equal(t, []int{50, 50, 20}, streams)
+}
+
+func TestOCIDimensions(t *testing.T) {
+	resource := pcommon.NewMap()
+	resource.PutStr("service.name", "checkout")
+	resource.PutStr("host name", "node-1")
+	attributes := pcommon.NewMap()
+	attributes.PutStr("service.name", "payment")
+	attributes.PutInt("status", 200)
+	attributes.PutStr("empty", "")
+	assert.Equal(t, map[string]string{
+		"service_name": "payment",
+		"status":       "200",
+		"host_name":    "node-1",
+	}, ociDimensions(resource, attributes))
+
+	assert.Equal(t, map[string]string{"source": ociDefaultSource}, ociDimensions(pcommon.NewMap(), pcommon.NewMap()))
+
+	for i := 0; i < 30; i++ {
+		attributes.PutInt(fmt.Sprintf("key%02d", i), int64(i))
+	}
+	assert.Len(t, ociDimensions(resource, attributes), ociMaxDimensions)
+}
+
+func TestOCIExporterStart(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Backend = ociBackend
+	cfg.OCI.ConfigFile = "testdata/oci_config"
+	cfg.OCI.Monitoring = OCIMonitoringSettings{CompartmentID: "ocid1.compartment.oc1..otel", Namespace: "otel_collector"}
+	_, cfg.OCI.KeyFile = writeOCIKey(t, t.TempDir())
+
+	exporter := newOCIExporter(cfg, componenttest.NewNopExporterCreateSettings())
+	require.NoError(t, exporter.start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, "https://telemetry-ingestion.us-phoenix-1.oraclecloud.com/20180401/metrics", exporter.metricsURL)
+	assert.Empty(t, exporter.logsURL)
+	assert.Equal(t, "ocid1.tenancy.oc1..acme/ocid1.user.oc1..otel/20:3b:97:13", exporter.signer.keyID)
+
+	cfg.OCI.Profile = "NOREGION"
+	exporter = newOCIExporter(cfg, componenttest.NewNopExporterCreateSettings())
+	assert.EqualError(t, exporter.start(context.Background(), componenttest.NewNopHost()),
+		"oci::region must be set, either explicitly or in the configuration file")
+}
+
+func TestCreateOCIExporters(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Backend = ociBackend
+	cfg.OCI.Logging.LogID = "ocid1.log.oc1..otel"
+	set := componenttest.NewNopExporterCreateSettings()
+
+	_, err := factory.CreateTracesExporter(context.Background(), set, cfg)
+	assert.Equal(t, errOCITraces, err)
+	_, err = factory.CreateMetricsExporter(context.Background(), set, cfg)
+	assert.EqualError(t, err, "oci::monitoring::compartment_id must be set to export the metrics")
+	exporter, err := factory.CreateLogsExporter(context.Background(), set, cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, exporter)
+}
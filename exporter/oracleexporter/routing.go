@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/oracleexporter"
+
+import (
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// schemaOf returns where the telemetry of the tenant is posted, the exporter endpoint for the
+// tenants without schema.
+func (e *oracleExporter) schemaOf(tenant string) SchemaSettings {
+	if schema, ok := e.cfg.SchemaRouting.Schemas[tenant]; ok {
+		return schema
+	}
+	return SchemaSettings{Endpoint: e.cfg.Endpoint, User: e.cfg.User, Password: e.cfg.Password}
+}
+
+// tenantOf returns the tenant of the resource, or an empty string when the tenant has no schema.
+func (e *oracleExporter) tenantOf(res pcommon.Resource) string {
+	v, ok := res.Attributes().Get(e.cfg.SchemaRouting.FromAttribute)
+	if !ok {
+		return ""
+	}
+	tenant := v.AsString()
+	if _, ok := e.cfg.SchemaRouting.Schemas[tenant]; !ok {
+		return ""
+	}
+	return tenant
+}
+
+// tenantsOf returns the tenant of each resource, or nil when the resources all belong to
+// the same tenant, which is then returned alone.
+func (e *oracleExporter) tenantsOf(n int, resource func(int) pcommon.Resource) ([]string, string) {
+	if len(e.cfg.SchemaRouting.Schemas) == 0 || n == 0 {
+		return nil, ""
+	}
+	tenants := make([]string, n)
+	mixed := false
+	for i := 0; i < n; i++ {
+		tenants[i] = e.tenantOf(resource(i))
+		mixed = mixed || tenants[i] != tenants[0]
+	}
+	if !mixed {
+		return nil, tenants[0]
+	}
+	return tenants, ""
+}
+
+// partitionTraces splits the traces by tenant, the batches of a single tenant are not copied.
+func (e *oracleExporter) partitionTraces(td ptrace.Traces) map[string]ptrace.Traces {
+	rss := td.ResourceSpans()
+	tenants, tenant := e.tenantsOf(rss.Len(), func(i int) pcommon.Resource { return rss.At(i).Resource() })
+	if tenants == nil {
+		return map[string]ptrace.Traces{tenant: td}
+	}
+	partitions := map[string]ptrace.Traces{}
+	for i, tenant := range tenants {
+		traces, ok := partitions[tenant]
+		if !ok {
+			traces = ptrace.NewTraces()
+			partitions[tenant] = traces
+		}
+		rss.At(i).CopyTo(traces.ResourceSpans().AppendEmpty())
+	}
+	return partitions
+}
+
+// partitionMetrics splits the metrics by tenant, the batches of a single tenant are not copied.
+func (e *oracleExporter) partitionMetrics(md pmetric.Metrics) map[string]pmetric.Metrics {
+	rms := md.ResourceMetrics()
+	tenants, tenant := e.tenantsOf(rms.Len(), func(i int) pcommon.Resource { return rms.At(i).Resource() })
+	if tenants == nil {
+		return map[string]pmetric.Metrics{tenant: md}
+	}
+	partitions := map[string]pmetric.Metrics{}
+	for i, tenant := range tenants {
+		metrics, ok := partitions[tenant]
+		if !ok {
+			metrics = pmetric.NewMetrics()
+			partitions[tenant] = metrics
+		}
+		rms.At(i).CopyTo(metrics.ResourceMetrics().AppendEmpty())
+	}
+	return partitions
+}
+
+// partitionLogs splits the logs by tenant, the batches of a single tenant are not copied.
+func (e *oracleExporter) partitionLogs(ld plog.Logs) map[string]plog.Logs {
+	rls := ld.ResourceLogs()
+	tenants, tenant := e.tenantsOf(rls.Len(), func(i int) pcommon.Resource { return rls.At(i).Resource() })
+	if tenants == nil {
+		return map[string]plog.Logs{tenant: ld}
+	}
+	partitions := map[string]plog.Logs{}
+	for i, tenant := range tenants {
+		logs, ok := partitions[tenant]
+		if !ok {
+			logs = plog.NewLogs()
+			partitions[tenant] = logs
+		}
+		rls.At(i).CopyTo(logs.ResourceLogs().AppendEmpty())
+	}
+	return partitions
+}
+
+// partitionErrors collects the errors of the partitions of a batch. When the batch has several
+// partitions, only those failing with a retryable error are retried, the others being either
+// posted or dropped.
+type partitionErrors struct {
+	partitions int
+	retryable  error
+	permanent  error
+}
+
+func newPartitionErrors(partitions int) *partitionErrors {
+	return &partitionErrors{partitions: partitions}
+}
+
+// add records the error of a partition, and reports whether the partition has to be retried
+// on its own.
+func (pe *partitionErrors) add(err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case consumererror.IsPermanent(err):
+		pe.permanent = multierr.Append(pe.permanent, err)
+		return false
+	default:
+		pe.retryable = multierr.Append(pe.retryable, err)
+		return pe.partitions > 1
+	}
+}
+
+// partial reports whether only the failed partitions of the batch have to be retried.
+func (pe *partitionErrors) partial() bool {
+	return pe.partitions > 1 && pe.retryable != nil
+}
+
+// err returns the error of the batch. The permanent errors are logged when retrying the other
+// partitions, as they would otherwise cause the whole batch to be dropped.
+func (pe *partitionErrors) err(logger *zap.Logger) error {
+	if pe.retryable == nil {
+		return pe.permanent
+	}
+	if pe.permanent != nil {
+		logger.Error("Dropping the telemetry of the schemas failing permanently", zap.Error(pe.permanent))
+	}
+	return pe.retryable
+}
@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// schemaServer is an ingestion endpoint recording the logs posted by each user.
+type schemaServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	status int
+	logs   map[string][]plog.Logs
+}
+
+func newSchemaServer(t *testing.T) *schemaServer {
+	s := &schemaServer{status: http.StatusOK, logs: map[string][]plog.Logs{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs(body)
+		require.NoError(t, err)
+		user, _, _ := r.BasicAuth()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.logs[user] = append(s.logs[user], ld)
+		w.WriteHeader(s.status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *schemaServer) setStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func (s *schemaServer) received(user string) []plog.Logs {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logs[user]
+}
+
+func newTenantLogs(tenants ...string) plog.Logs {
+	ld := plog.NewLogs()
+	for _, tenant := range tenants {
+		rl := ld.ResourceLogs().AppendEmpty()
+		if tenant != "" {
+			rl.Resource().Attributes().PutStr("tenant", tenant)
+		}
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello " + tenant)
+	}
+	return ld
+}
+
+func TestOracleExporter_schemaRouting(t *testing.T) {
+	shared := newSchemaServer(t)
+	acme := newSchemaServer(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = shared.URL
+	cfg.User = "otel"
+	cfg.Compression = ""
+	cfg.SchemaRouting = SchemaRoutingSettings{
+		FromAttribute: "tenant",
+		Schemas: map[string]SchemaSettings{
+			"acme": {Endpoint: acme.URL, User: "acme", Password: "acme_pwd"},
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	exporter := newOracleExporter(cfg, componenttest.NewNopExporterCreateSettings())
+	require.NoError(t, exporter.start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, exporter.shutdown(context.Background())) }()
+
+	// the tenants without schema go to the exporter endpoint
+	require.NoError(t, exporter.pushLogs(context.Background(), newTenantLogs("acme", "globex", "", "acme")))
+	require.Len(t, acme.received("acme"), 1)
+	assert.Equal(t, newTenantLogs("acme", "acme"), acme.received("acme")[0])
+	require.Len(t, shared.received("otel"), 1)
+	assert.Equal(t, newTenantLogs("globex", ""), shared.received("otel")[0])
+
+	// only the partitions failing with a retryable error are retried
+	acme.setStatus(http.StatusServiceUnavailable)
+	err := exporter.pushLogs(context.Background(), newTenantLogs("acme", "globex"))
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	var logsErr consumererror.Logs
+	require.True(t, errors.As(err, &logsErr))
+	assert.Equal(t, newTenantLogs("acme"), logsErr.GetLogs())
+	assert.Len(t, shared.received("otel"), 2)
+
+	// the batch of a single tenant is retried as is
+	err = exporter.pushLogs(context.Background(), newTenantLogs("acme"))
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &logsErr))
+
+	// a permanent failure of a schema doesn't prevent the others from being retried
+	acme.setStatus(http.StatusBadRequest)
+	shared.setStatus(http.StatusServiceUnavailable)
+	err = exporter.pushLogs(context.Background(), newTenantLogs("acme", "globex"))
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	require.True(t, errors.As(err, &logsErr))
+	assert.Equal(t, newTenantLogs("globex"), logsErr.GetLogs())
+
+	// the batch is dropped when all its partitions fail permanently
+	shared.setStatus(http.StatusBadRequest)
+	assert.True(t, consumererror.IsPermanent(exporter.pushLogs(context.Background(), newTenantLogs("acme", "globex"))))
+}
@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/oracleexporter"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+)
+
+// waitEventsQuery sums the non-idle wait events of the sessions of the current user, which are
+// the sessions the REST ingestion path writes the telemetry through.
+const waitEventsQuery = `SELECT e.event, SUM(e.total_waits) AS total_waits, SUM(e.time_waited_micro) AS time_waited_micro
+FROM v$session_event e JOIN v$session s ON s.sid = e.sid
+WHERE s.username = USER AND e.wait_class <> 'Idle'
+GROUP BY e.event`
+
+// waitEventsScrapers holds the wait events scraper of each exporter configuration, shared by
+// the traces, metrics and logs exporters created from it.
+var waitEventsScrapers = sharedcomponent.NewSharedComponents()
+
+// waitEventsScraper periodically queries the wait events of the database sessions of the exporter
+// user, so that a slow export can be attributed to the database, such as to commits waiting on
+// `log file sync` or inserts contending on `enq: TX - row lock contention`.
+type waitEventsScraper struct {
+	cfg      *Config
+	settings component.TelemetrySettings
+	client   *http.Client
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// waitEventsResponse is the response of the REST-enabled SQL endpoint to the wait events query.
+type waitEventsResponse struct {
+	Items []struct {
+		ErrorMessage string `json:"errorMessage"`
+		ResultSet    struct {
+			Items []struct {
+				Event           string  `json:"event"`
+				TotalWaits      int64   `json:"total_waits"`
+				TimeWaitedMicro float64 `json:"time_waited_micro"`
+			} `json:"items"`
+		} `json:"resultSet"`
+	} `json:"items"`
+}
+
+// getWaitEventsScraper returns the wait events scraper shared by the exporters of the
+// configuration, or nil when the scraping is disabled.
+func getWaitEventsScraper(cfg *Config, settings component.TelemetrySettings) *sharedcomponent.SharedComponent {
+	if !cfg.WaitEvents.Enabled {
+		return nil
+	}
+	return waitEventsScrapers.GetOrAdd(cfg, func() component.Component {
+		return newWaitEventsScraper(cfg, settings)
+	})
+}
+
+func newWaitEventsScraper(cfg *Config, settings component.TelemetrySettings) *waitEventsScraper {
+	return &waitEventsScraper{
+		cfg:      cfg,
+		settings: settings,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start implements component.Component.
+func (s *waitEventsScraper) Start(_ context.Context, host component.Host) error {
+	// the query is small, it is sent uncompressed
+	clientSettings := s.cfg.HTTPClientSettings
+	clientSettings.Compression = ""
+	client, err := clientSettings.ToClient(host, s.settings)
+	if err != nil {
+		return err
+	}
+	s.client = client
+
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Shutdown implements component.Component.
+func (s *waitEventsScraper) Shutdown(context.Context) error {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+	return nil
+}
+
+func (s *waitEventsScraper) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.WaitEvents.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.scrape(context.Background()); err != nil {
+				s.settings.Logger.Warn("Failed to scrape the Oracle wait events", zap.Error(err))
+			}
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// scrape queries the wait events and records them.
+func (s *waitEventsScraper) scrape(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.WaitEvents.Endpoint, strings.NewReader(waitEventsQuery))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/sql")
+	if s.cfg.User != "" {
+		req.SetBasicAuth(s.cfg.User, s.cfg.Password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("wait events query failed with HTTP %d %q", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	var events waitEventsResponse
+	if err = json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return fmt.Errorf("failed to decode the wait events: %w", err)
+	}
+	for _, item := range events.Items {
+		if item.ErrorMessage != "" {
+			return fmt.Errorf("wait events query failed: %s", item.ErrorMessage)
+		}
+		for _, event := range item.ResultSet.Items {
+			_ = stats.RecordWithTags(
+				ctx,
+				[]tag.Mutator{tag.Upsert(tagEventKey, event.Event)},
+				mWaitEventWaits.M(event.TotalWaits),
+				mWaitEventWaited.M(event.TimeWaitedMicro/1000),
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracleexporter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func TestWaitEventsScraper_scrape(t *testing.T) {
+	view.Unregister(MetricViews()...)
+	require.NoError(t, view.Register(MetricViews()...))
+	defer view.Unregister(MetricViews()...)
+
+	var contentType, query, user string
+	response := `{"items":[{"statementId":1,"statementType":"query","resultSet":{"items":[
+		{"event":"log file sync","total_waits":12,"time_waited_micro":34000},
+		{"event":"enq: TX - row lock contention","total_waits":3,"time_waited_micro":1500000}
+	]},"response":[],"result":0}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		query = string(b)
+		user, _, _ = r.BasicAuth()
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.User = "otel"
+	cfg.WaitEvents.Enabled = true
+	cfg.WaitEvents.Endpoint = server.URL
+
+	s := newWaitEventsScraper(cfg, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, s.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, s.Shutdown(context.Background())) }()
+
+	require.NoError(t, s.scrape(context.Background()))
+	assert.Equal(t, "application/sql", contentType)
+	assert.Equal(t, waitEventsQuery, query)
+	assert.Equal(t, "otel", user)
+
+	waits := map[string]float64{}
+	waited := map[string]float64{}
+	for name, values := range map[string]map[string]float64{mWaitEventWaits.Name(): waits, mWaitEventWaited.Name(): waited} {
+		rows, err := view.RetrieveData(name)
+		require.NoError(t, err)
+		for _, row := range rows {
+			values[row.Tags[0].Value] = row.Data.(*view.LastValueData).Value
+		}
+	}
+	assert.Equal(t, map[string]float64{"log file sync": 12, "enq: TX - row lock contention": 3}, waits)
+	assert.Equal(t, map[string]float64{"log file sync": 34, "enq: TX - row lock contention": 1500}, waited)
+
+	response = `{"items":[{"statementId":1,"errorCode":942,"errorMessage":"ORA-00942: table or view does not exist"}]}`
+	assert.EqualError(t, s.scrape(context.Background()), "wait events query failed: ORA-00942: table or view does not exist")
+}
+
+func TestWaitEventsScraper_shared(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Nil(t, getWaitEventsScraper(cfg, componenttest.NewNopTelemetrySettings()))
+
+	cfg.WaitEvents.Enabled = true
+	s := getWaitEventsScraper(cfg, componenttest.NewNopTelemetrySettings())
+	require.NotNil(t, s)
+	assert.Same(t, s, getWaitEventsScraper(cfg, componenttest.NewNopTelemetrySettings()))
+}
@@ -15,7 +15,11 @@
 package pulsarexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/pulsarexporter"
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/auth"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
@@ -31,8 +35,11 @@ type Config struct {
 
 	// Endpoint of pulsar broker (default "pulsar://localhost:6650")
 	Endpoint string `mapstructure:"endpoint"`
-	// The name of the pulsar topic to export to (default otlp_spans for traces, otlp_metrics for metrics)
+	// The name of the pulsar topic to export to (default otlp_spans for traces, otlp_metrics for metrics).
+	// The topic can contain resource attribute placeholders, e.g. persistent://tenant/{service.namespace}/otlp-spans
 	Topic string `mapstructure:"topic"`
+	// TopicRouting configures how topics resolved from a topic template are handled
+	TopicRouting TopicRouting `mapstructure:"topic_routing"`
 	// Encoding of messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
 	// Set the path to the trusted TLS certificate file
@@ -40,6 +47,35 @@ type Config struct {
 	// Configure whether the Pulsar client accept untrusted TLS certificate from broker (default: false)
 	TLSAllowInsecureConnection bool           `mapstructure:"tls_allow_insecure_connection"`
 	Authentication             Authentication `mapstructure:"auth"`
+	// MaxMessageSize is the maximum size in bytes of the payload of a message (default 5242880, the
+	// broker default maxMessageSize). Batches marshaled into larger payloads are split, unless
+	// chunking is enabled. Zero disables the splitting.
+	MaxMessageSize int `mapstructure:"max_message_size"`
+	// Chunking configures the producer to split large messages into chunks reassembled by the consumers.
+	Chunking Chunking `mapstructure:"chunking"`
+}
+
+// Chunking defines the chunking of the messages by the producers.
+type Chunking struct {
+	// Enabled enables the chunking of the messages larger than the broker maxMessageSize, the
+	// batching of the producer is disabled as both can't be used together. The consumers must
+	// support chunking.
+	Enabled bool `mapstructure:"enabled"`
+	// MaxChunkSize is the maximum size in bytes of a chunk, defaults to the broker maxMessageSize.
+	MaxChunkSize uint `mapstructure:"max_chunk_size"`
+}
+
+// TopicRouting defines the settings used when the topic is templated from resource attributes.
+type TopicRouting struct {
+	// CacheSize is the maximum number of producers kept open for the resolved topics (default 100).
+	// The least recently used producer is closed once the limit is reached.
+	CacheSize int `mapstructure:"cache_size"`
+	// CreationPolicy defines what happens when a topic can't be resolved because of missing attributes,
+	// or when no producer can be created for it, for instance because the broker doesn't allow
+	// topic auto creation: "fail" (default) rejects the data, "fallback" sends it to FallbackTopic.
+	CreationPolicy string `mapstructure:"creation_policy"`
+	// FallbackTopic is the topic used by the "fallback" creation policy.
+	FallbackTopic string `mapstructure:"fallback_topic"`
 }
 
 type Authentication struct {
@@ -56,6 +92,9 @@ type TLS struct {
 
 type Token struct {
 	Token string `mapstructure:"Token"`
+	// File is the path of a file holding the token instead. It is read each time the client needs
+	// the token, so that the token can be rotated without restarting the collector.
+	File string `mapstructure:"file"`
 }
 
 type Athenz struct {
@@ -68,34 +107,88 @@ type Athenz struct {
 	ZtsURL          string `mapstructure:"zts_url"`
 }
 
+// OAuth2 configures the OAuth2 client credentials flow. The access token is refreshed by the
+// client before it expires.
 type OAuth2 struct {
 	IssuerURL string `mapstructure:"issuer_url"`
 	ClientID  string `mapstructure:"client_id"`
 	Audience  string `mapstructure:"audience"`
+	// PrivateKey is the path of the JSON key file holding the client credentials (type, client_id,
+	// client_secret and issuer_url), or its content as a data URL.
+	PrivateKey string `mapstructure:"private_key"`
+	// Scope is the space separated list of the scopes requested in addition to the audience.
+	Scope string `mapstructure:"scope"`
 }
 
 var _ component.ExporterConfig = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
+	routing := cfg.TopicRouting
+	switch routing.CreationPolicy {
+	case "", creationPolicyFail:
+	case creationPolicyFallback:
+		if routing.FallbackTopic == "" {
+			return errors.New("topic_routing.fallback_topic is required with the fallback creation policy")
+		}
+		if isTopicTemplate(routing.FallbackTopic) {
+			return errors.New("topic_routing.fallback_topic must not be a template")
+		}
+	default:
+		return fmt.Errorf("unknown topic_routing.creation_policy %q, must be one of %q or %q",
+			routing.CreationPolicy, creationPolicyFail, creationPolicyFallback)
+	}
+	if routing.CacheSize < 0 {
+		return errors.New("topic_routing.cache_size must not be negative")
+	}
+	if cfg.MaxMessageSize < 0 {
+		return errors.New("max_message_size must not be negative")
+	}
+	return cfg.Authentication.validate()
+}
 
+func (a *Authentication) validate() error {
+	if a.Token != nil && (a.Token.Token == "") == (a.Token.File == "") {
+		return errors.New("auth.token requires either a token or a file")
+	}
+	if a.OAuth2 != nil {
+		switch {
+		case a.OAuth2.IssuerURL == "":
+			return errors.New("auth.oauth2.issuer_url is required")
+		case a.OAuth2.Audience == "":
+			return errors.New("auth.oauth2.audience is required")
+		case a.OAuth2.PrivateKey == "":
+			return errors.New("auth.oauth2.private_key is required")
+		}
+	}
 	return nil
 }
 
-func (cfg *Config) auth() pulsar.Authentication {
+func (cfg *Config) auth() (pulsar.Authentication, error) {
 	authentication := cfg.Authentication
 	if authentication.TLS != nil {
-		return pulsar.NewAuthenticationTLS(authentication.TLS.CertFile, authentication.TLS.KeyFile)
+		return pulsar.NewAuthenticationTLS(authentication.TLS.CertFile, authentication.TLS.KeyFile), nil
 	}
 	if authentication.Token != nil {
-		return pulsar.NewAuthenticationToken(authentication.Token.Token)
+		if authentication.Token.File != "" {
+			return pulsar.NewAuthenticationTokenFromFile(authentication.Token.File), nil
+		}
+		return pulsar.NewAuthenticationToken(authentication.Token.Token), nil
 	}
 	if authentication.OAuth2 != nil {
-		return pulsar.NewAuthenticationOAuth2(map[string]string{
-			"issuerUrl": authentication.OAuth2.IssuerURL,
-			"clientId":  authentication.OAuth2.ClientID,
-			"audience":  authentication.OAuth2.Audience,
+		// the provider is created directly to report the errors, pulsar.NewAuthenticationOAuth2 ignores them
+		provider, err := auth.NewAuthenticationOAuth2WithParams(map[string]string{
+			auth.ConfigParamType:      auth.ConfigParamTypeClientCredentials,
+			auth.ConfigParamIssuerURL: authentication.OAuth2.IssuerURL,
+			auth.ConfigParamClientID:  authentication.OAuth2.ClientID,
+			auth.ConfigParamAudience:  authentication.OAuth2.Audience,
+			auth.ConfigParamKeyFile:   authentication.OAuth2.PrivateKey,
+			auth.ConfigParamScope:     authentication.OAuth2.Scope,
 		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to authorize with OAuth2: %w", err)
+		}
+		return provider, nil
 	}
 	if authentication.Athenz != nil {
 		return pulsar.NewAuthenticationAthenz(map[string]string{
@@ -106,13 +199,13 @@ func (cfg *Config) auth() pulsar.Authentication {
 			"keyId":           authentication.Athenz.KeyID,
 			"principalHeader": authentication.Athenz.PrincipalHeader,
 			"ztsUrl":          authentication.Athenz.ZtsURL,
-		})
+		}), nil
 	}
 
-	return nil
+	return nil, nil
 }
 
-func (cfg *Config) clientOptions() pulsar.ClientOptions {
+func (cfg *Config) clientOptions() (pulsar.ClientOptions, error) {
 	options := pulsar.ClientOptions{
 		URL: cfg.Endpoint,
 	}
@@ -122,7 +215,33 @@ func (cfg *Config) clientOptions() pulsar.ClientOptions {
 		options.TLSTrustCertsFilePath = cfg.TLSTrustCertsFilePath
 	}
 
-	options.Authentication = cfg.auth()
+	authentication, err := cfg.auth()
+	if err != nil {
+		return options, err
+	}
+	options.Authentication = authentication
+
+	return options, nil
+}
 
+func (cfg *Config) producerOptions(topic string) pulsar.ProducerOptions {
+	options := pulsar.ProducerOptions{
+		Topic:       topic,
+		SendTimeout: cfg.Timeout,
+	}
+	if cfg.Chunking.Enabled {
+		options.EnableChunking = true
+		options.DisableBatching = true
+		options.ChunkMaxMessageSize = cfg.Chunking.MaxChunkSize
+	}
 	return options
 }
+
+// maxMessageSize returns the size above which batches are split, the producer takes
+// care of the large messages when chunking is enabled.
+func (cfg *Config) maxMessageSize() int {
+	if cfg.Chunking.Enabled {
+		return 0
+	}
+	return cfg.MaxMessageSize
+}
@@ -15,11 +15,16 @@
 package pulsarexporter
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/apache/pulsar-client-go/pulsar/auth"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -56,11 +61,93 @@ func TestLoadConfig(t *testing.T) {
 					NumConsumers: 2,
 					QueueSize:    10,
 				},
-				Endpoint:              "pulsar://localhost:6650",
-				Topic:                 "spans",
+				Endpoint: "pulsar://localhost:6650",
+				Topic:    "spans",
+				TopicRouting: TopicRouting{
+					CacheSize:      defaultTopicCacheSize,
+					CreationPolicy: creationPolicyFail,
+				},
 				Encoding:              "otlp-spans",
 				TLSTrustCertsFilePath: "ca.pem",
 				Authentication:        Authentication{TLS: &TLS{CertFile: "cert.pem", KeyFile: "key.pem"}},
+				MaxMessageSize:        defaultMaxMessageSize,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "templated_topic"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				TimeoutSettings:  exporterhelper.NewDefaultTimeoutSettings(),
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				Endpoint:         "pulsar://localhost:6650",
+				Topic:            "persistent://tenant/{service.namespace}/otlp-spans",
+				TopicRouting: TopicRouting{
+					CacheSize:      10,
+					CreationPolicy: creationPolicyFallback,
+					FallbackTopic:  "persistent://tenant/default/otlp-spans",
+				},
+				Encoding:       defaultEncoding,
+				MaxMessageSize: defaultMaxMessageSize,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "oauth2"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				TimeoutSettings:  exporterhelper.NewDefaultTimeoutSettings(),
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				Endpoint:         "pulsar://localhost:6650",
+				TopicRouting: TopicRouting{
+					CacheSize:      defaultTopicCacheSize,
+					CreationPolicy: creationPolicyFail,
+				},
+				Encoding:       defaultEncoding,
+				MaxMessageSize: defaultMaxMessageSize,
+				Authentication: Authentication{OAuth2: &OAuth2{
+					IssuerURL:  "https://auth.streamnative.cloud/",
+					Audience:   "urn:sn:pulsar:tenant:instance",
+					PrivateKey: "/etc/pulsar/credentials.json",
+					Scope:      "profile",
+				}},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "token_file"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				TimeoutSettings:  exporterhelper.NewDefaultTimeoutSettings(),
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				Endpoint:         "pulsar://localhost:6650",
+				TopicRouting: TopicRouting{
+					CacheSize:      defaultTopicCacheSize,
+					CreationPolicy: creationPolicyFail,
+				},
+				Encoding:       defaultEncoding,
+				MaxMessageSize: defaultMaxMessageSize,
+				Authentication: Authentication{Token: &Token{File: "/var/run/secrets/pulsar/token"}},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "chunking"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				TimeoutSettings:  exporterhelper.NewDefaultTimeoutSettings(),
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				Endpoint:         "pulsar://localhost:6650",
+				TopicRouting: TopicRouting{
+					CacheSize:      defaultTopicCacheSize,
+					CreationPolicy: creationPolicyFail,
+				},
+				Encoding:       defaultEncoding,
+				MaxMessageSize: 1048576,
+				Chunking: Chunking{
+					Enabled:      true,
+					MaxChunkSize: 524288,
+				},
 			},
 		},
 	}
@@ -90,7 +177,8 @@ func TestClientOptions(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, component.UnmarshalExporterConfig(sub, cfg))
 
-	options := cfg.(*Config).clientOptions()
+	options, err := cfg.(*Config).clientOptions()
+	require.NoError(t, err)
 
 	assert.Equal(t, &pulsar.ClientOptions{
 		URL:                   "pulsar://localhost:6650",
@@ -99,3 +187,132 @@ func TestClientOptions(t *testing.T) {
 	}, &options)
 
 }
+
+func TestProducerOptions(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, pulsar.ProducerOptions{Topic: "spans", SendTimeout: cfg.Timeout}, cfg.producerOptions("spans"))
+	assert.Equal(t, defaultMaxMessageSize, cfg.maxMessageSize())
+
+	cfg.Chunking = Chunking{Enabled: true, MaxChunkSize: 1024}
+	assert.Equal(t, pulsar.ProducerOptions{
+		Topic:               "spans",
+		SendTimeout:         cfg.Timeout,
+		EnableChunking:      true,
+		DisableBatching:     true,
+		ChunkMaxMessageSize: 1024,
+	}, cfg.producerOptions("spans"))
+	assert.Zero(t, cfg.maxMessageSize())
+}
+
+func TestValidateAuthentication(t *testing.T) {
+	tests := []struct {
+		name string
+		auth Authentication
+		err  string
+	}{
+		{
+			name: "token",
+			auth: Authentication{Token: &Token{Token: "token"}},
+		},
+		{
+			name: "token and file",
+			auth: Authentication{Token: &Token{Token: "token", File: "token.txt"}},
+			err:  "auth.token requires either a token or a file",
+		},
+		{
+			name: "empty token",
+			auth: Authentication{Token: &Token{}},
+			err:  "auth.token requires either a token or a file",
+		},
+		{
+			name: "oauth2 without issuer",
+			auth: Authentication{OAuth2: &OAuth2{Audience: "audience", PrivateKey: "key.json"}},
+			err:  "auth.oauth2.issuer_url is required",
+		},
+		{
+			name: "oauth2 without audience",
+			auth: Authentication{OAuth2: &OAuth2{IssuerURL: "https://issuer", PrivateKey: "key.json"}},
+			err:  "auth.oauth2.audience is required",
+		},
+		{
+			name: "oauth2 without private key",
+			auth: Authentication{OAuth2: &OAuth2{IssuerURL: "https://issuer", Audience: "audience"}},
+			err:  "auth.oauth2.private_key is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Authentication = tt.auth
+			if tt.err == "" {
+				assert.NoError(t, cfg.Validate())
+				return
+			}
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
+
+func TestTokenFileAuthentication(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(file, []byte("first\n"), 0600))
+	cfg := createDefaultConfig().(*Config)
+	cfg.Authentication = Authentication{Token: &Token{File: file}}
+
+	authentication, err := cfg.auth()
+	require.NoError(t, err)
+	provider := authentication.(auth.Provider)
+	require.NoError(t, provider.Init())
+	data, err := provider.GetData()
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+
+	// the rotated token is used without recreating the client
+	require.NoError(t, os.WriteFile(file, []byte("second"), 0600))
+	data, err = provider.GetData()
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+}
+
+func TestOAuth2Authentication(t *testing.T) {
+	var tokenRequests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer":"http://%s","token_endpoint":"http://%s/oauth/token"}`, r.Host, r.Host)
+		case "/oauth/token":
+			tokenRequests++
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "urn:sn:pulsar:tenant:instance", r.PostForm.Get("audience"))
+			fmt.Fprint(w, `{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	keyFile := filepath.Join(t.TempDir(), "credentials.json")
+	require.NoError(t, os.WriteFile(keyFile, []byte(fmt.Sprintf(
+		`{"type":"client_credentials","client_id":"client","client_secret":"secret","issuer_url":%q}`, srv.URL)), 0600))
+	cfg := createDefaultConfig().(*Config)
+	cfg.Authentication = Authentication{OAuth2: &OAuth2{
+		IssuerURL:  srv.URL,
+		Audience:   "urn:sn:pulsar:tenant:instance",
+		PrivateKey: keyFile,
+	}}
+	require.NoError(t, cfg.Validate())
+
+	authentication, err := cfg.auth()
+	require.NoError(t, err)
+	provider := authentication.(auth.Provider)
+	require.NoError(t, provider.Init())
+	data, err := provider.GetData()
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", string(data))
+	assert.Equal(t, 1, tokenRequests)
+
+	cfg.Authentication.OAuth2.PrivateKey = filepath.Join(t.TempDir(), "missing.json")
+	_, err = cfg.clientOptions()
+	assert.ErrorContains(t, err, "failed to authorize with OAuth2")
+}
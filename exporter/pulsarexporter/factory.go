@@ -33,6 +33,8 @@ const (
 	defaultLogsTopic    = "otlp_logs"
 	defaultEncoding     = "otlp_proto"
 	defaultBroker       = "pulsar://localhost:6650"
+	// same as the default maxMessageSize of the brokers
+	defaultMaxMessageSize = 5 * 1024 * 1024
 )
 
 // FactoryOption applies changes to pulsarExporterFactory.
@@ -74,9 +76,14 @@ func createDefaultConfig() component.ExporterConfig {
 		QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
 		Endpoint:         defaultBroker,
 		// using an empty topic to track when it has not been set by user, default is based on traces or metrics.
-		Topic:          "",
+		Topic: "",
+		TopicRouting: TopicRouting{
+			CacheSize:      defaultTopicCacheSize,
+			CreationPolicy: creationPolicyFail,
+		},
 		Encoding:       defaultEncoding,
 		Authentication: Authentication{},
+		MaxMessageSize: defaultMaxMessageSize,
 	}
 }
 
@@ -35,9 +35,14 @@ func Test_createDefaultConfig(t *testing.T) {
 		QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
 		Endpoint:         defaultBroker,
 		// using an empty topic to track when it has not been set by user, default is based on traces or metrics.
-		Topic:          "",
+		Topic: "",
+		TopicRouting: TopicRouting{
+			CacheSize:      defaultTopicCacheSize,
+			CreationPolicy: creationPolicyFail,
+		},
 		Encoding:       defaultEncoding,
 		Authentication: Authentication{},
+		MaxMessageSize: defaultMaxMessageSize,
 	})
 }
 
@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/pulsarexporter"
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	mPublishLatency  = stats.Int64("publish_latency", "Time between the publishing of a message and its acknowledgment by the broker", stats.UnitMilliseconds)
+	mPendingMessages = stats.Int64("pending_messages", "Number of messages published and not yet acknowledged by the broker", stats.UnitDimensionless)
+	mPublishFailures = stats.Int64("publish_failures", "Number of messages the broker failed to acknowledge", stats.UnitDimensionless)
+
+	tagTopicKey     = tag.MustNewKey("topic")
+	tagErrorTypeKey = tag.MustNewKey("error_type")
+)
+
+// MetricViews returns the metrics views related to the Pulsar exporter.
+func MetricViews() []*view.View {
+	return metricViews
+}
+
+// metricViews are built once, a view can only be registered again when it is the very same view.
+var metricViews = []*view.View{
+	{
+		Name:        buildExporterCustomMetricName(mPublishLatency.Name()),
+		Measure:     mPublishLatency,
+		Description: mPublishLatency.Description(),
+		TagKeys:     []tag.Key{tagTopicKey},
+		Aggregation: view.Distribution(0, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000),
+	},
+	{
+		Name:        buildExporterCustomMetricName(mPendingMessages.Name()),
+		Measure:     mPendingMessages,
+		Description: mPendingMessages.Description(),
+		TagKeys:     []tag.Key{tagTopicKey},
+		Aggregation: view.LastValue(),
+	},
+	{
+		Name:        buildExporterCustomMetricName(mPublishFailures.Name()),
+		Measure:     mPublishFailures,
+		Description: mPublishFailures.Description(),
+		TagKeys:     []tag.Key{tagTopicKey, tagErrorTypeKey},
+		Aggregation: view.Sum(),
+	},
+}
+
+// buildExporterCustomMetricName builds the name of an exporter metric following the
+// collector standards, like obsreport.BuildProcessorCustomMetricName does for processors.
+func buildExporterCustomMetricName(metric string) string {
+	return "exporter/" + typeStr + "/" + metric
+}
+
+// pulsarErrorTypes maps the results of the failed publishes to the error_type tag, the
+// throttling by the broker being reported as quota_exceeded.
+var pulsarErrorTypes = map[pulsar.Result]string{
+	pulsar.TimeoutError:                          "timeout",
+	pulsar.ProducerQueueIsFull:                   "queue_full",
+	pulsar.ClientMemoryBufferIsFull:              "queue_full",
+	pulsar.ProducerBlockedQuotaExceededError:     "quota_exceeded",
+	pulsar.ProducerBlockedQuotaExceededException: "quota_exceeded",
+	pulsar.MessageTooBig:                         "message_too_big",
+	pulsar.ProducerClosed:                        "producer_closed",
+	pulsar.AlreadyClosedError:                    "producer_closed",
+	pulsar.TopicTerminated:                       "topic_terminated",
+	pulsar.NotConnectedError:                     "not_connected",
+	pulsar.ConnectError:                          "not_connected",
+	pulsar.AuthenticationError:                   "unauthorized",
+	pulsar.AuthorizationError:                    "unauthorized",
+	pulsar.BrokerPersistenceError:                "broker_persistence",
+}
+
+// errorType returns the error_type tag of a failed publish.
+func errorType(err error) string {
+	var pulsarErr *pulsar.Error
+	switch {
+	case errors.As(err, &pulsarErr):
+		if t, ok := pulsarErrorTypes[pulsarErr.Result()]; ok {
+			return t
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	}
+	return "other"
+}
+
+// pendingMessages counts the messages of each topic waiting for their acknowledgment, across
+// the exporters publishing to the topic.
+var pendingMessages = &pendingCounter{pending: map[string]int64{}}
+
+type pendingCounter struct {
+	mu      sync.Mutex
+	pending map[string]int64
+}
+
+// add updates the number of pending messages of the topic and records it.
+func (c *pendingCounter) add(topic string, delta int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending[topic] += delta
+	pending := c.pending[topic]
+	if pending == 0 {
+		delete(c.pending, topic)
+	}
+	_ = stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Upsert(tagTopicKey, topic)}, mPendingMessages.M(pending))
+}
+
+// recordPublish records the outcome of the publishing of a message to the topic.
+func recordPublish(topic string, latencyMs int64, err error) {
+	mutators := []tag.Mutator{tag.Upsert(tagTopicKey, topic)}
+	if err != nil {
+		_ = stats.RecordWithTags(context.Background(), append(mutators, tag.Upsert(tagErrorTypeKey, errorType(err))), mPublishFailures.M(1))
+		return
+	}
+	_ = stats.RecordWithTags(context.Background(), mutators, mPublishLatency.M(latencyMs))
+}
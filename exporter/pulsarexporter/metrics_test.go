@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+// topicRows returns the rows of the view recorded for the topic, keyed by error type for the failures.
+func topicRows(t *testing.T, metric, topic string) map[string]view.AggregationData {
+	rows, err := view.RetrieveData(buildExporterCustomMetricName(metric))
+	require.NoError(t, err)
+	data := map[string]view.AggregationData{}
+	for _, row := range rows {
+		var errType string
+		matches := false
+		for _, tg := range row.Tags {
+			switch tg {
+			case tag.Tag{Key: tagTopicKey, Value: topic}:
+				matches = true
+			default:
+				if tg.Key == tagErrorTypeKey {
+					errType = tg.Value
+				}
+			}
+		}
+		if matches {
+			data[errType] = row.Data
+		}
+	}
+	return data
+}
+
+func TestSendMessagesMetrics(t *testing.T) {
+	require.NoError(t, view.Register(MetricViews()...))
+
+	messages := []*pulsar.ProducerMessage{{Payload: []byte("a")}, {Payload: []byte("b")}}
+	require.NoError(t, sendMessages(context.Background(), &mockProducer{topic: "metrics-ok"}, messages))
+
+	latency := topicRows(t, mPublishLatency.Name(), "metrics-ok")
+	require.Len(t, latency, 1)
+	assert.Equal(t, int64(2), latency[""].(*view.DistributionData).Count)
+	pending := topicRows(t, mPendingMessages.Name(), "metrics-ok")
+	require.Len(t, pending, 1)
+	assert.Equal(t, float64(0), pending[""].(*view.LastValueData).Value)
+	assert.Empty(t, topicRows(t, mPublishFailures.Name(), "metrics-ok"))
+
+	throttled := &mockProducer{topic: "metrics-throttled", sendErr: &pulsar.Error{}}
+	require.Error(t, sendMessages(context.Background(), throttled, messages))
+	throttled.sendErr = context.DeadlineExceeded
+	require.Error(t, sendMessages(context.Background(), throttled, messages[:1]))
+
+	failures := map[string]float64{}
+	for errType, data := range topicRows(t, mPublishFailures.Name(), "metrics-throttled") {
+		failures[errType] = data.(*view.SumData).Value
+	}
+	assert.Equal(t, map[string]float64{"other": 2, "timeout": 1}, failures)
+	assert.Empty(t, topicRows(t, mPublishLatency.Name(), "metrics-throttled"))
+}
+
+func TestErrorType(t *testing.T) {
+	for _, tt := range []struct {
+		err      error
+		expected string
+	}{
+		{err: errors.New("unexpected"), expected: "other"},
+		{err: &pulsar.Error{}, expected: "other"},
+		{err: context.DeadlineExceeded, expected: "timeout"},
+		{err: fmt.Errorf("send: %w", context.Canceled), expected: "canceled"},
+	} {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, errorType(tt.err))
+		})
+	}
+}
@@ -17,8 +17,11 @@ package pulsarexporter // import "github.com/open-telemetry/opentelemetry-collec
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -33,124 +36,234 @@ var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
 type PulsarTracesProducer struct {
 	client    pulsar.Client
 	producer  pulsar.Producer
+	topics    *topicProducers
 	topic     string
 	marshaler TracesMarshaler
 	logger    *zap.Logger
+	// maxMessageSize is the maximum payload size of the messages, zero when chunking is enabled.
+	maxMessageSize int
 }
 
 func (e *PulsarTracesProducer) tracesPusher(ctx context.Context, td ptrace.Traces) error {
-	messages, err := e.marshaler.Marshal(td, e.topic)
-	if err != nil {
-		return consumererror.NewPermanent(err)
+	if e.topics != nil {
+		return e.pushByTopic(ctx, td)
 	}
 
-	var errs error
-	for _, message := range messages {
+	messages, err := marshalTraces(e.marshaler, td, e.topic, e.maxMessageSize)
+	return sendMarshaled(ctx, e.producer, messages, err)
+}
 
-		e.producer.SendAsync(ctx, message, func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
-			if err != nil {
-				errs = multierr.Append(errs, err)
-			}
-		})
+// pushByTopic sends the data of each resource to the topic resolved from its attributes.
+// Only the data of the topics it failed to be sent to is returned for retry, the data
+// which can't be resolved to a topic or marshaled is dropped.
+func (e *PulsarTracesProducer) pushByTopic(ctx context.Context, td ptrace.Traces) error {
+	byTopic, dropped := e.topics.tracesByTopic(td)
 
+	var errs error
+	failed := ptrace.NewTraces()
+	for topic, data := range byTopic {
+		messages, marshalErr := marshalTraces(e.marshaler, data, topic, e.maxMessageSize)
+		dropped = multierr.Append(dropped, marshalErr)
+		if err := e.topics.send(ctx, topic, messages); err != nil {
+			errs = multierr.Append(errs, err)
+			data.ResourceSpans().MoveAndAppendTo(failed.ResourceSpans())
+		}
 	}
 
-	return errs
+	if errs != nil {
+		return consumererror.NewTraces(multierr.Append(errs, dropped), failed)
+	}
+	if dropped != nil {
+		return consumererror.NewPermanent(dropped)
+	}
+	return nil
 }
 
 func (e *PulsarTracesProducer) Close(context.Context) error {
-	e.producer.Close()
-	e.client.Close()
+	closeProducers(e.client, e.producer, e.topics)
 	return nil
 }
 
 type PulsarMetricsProducer struct {
 	client    pulsar.Client
 	producer  pulsar.Producer
+	topics    *topicProducers
 	topic     string
 	marshaler MetricsMarshaler
 	logger    *zap.Logger
+	// maxMessageSize is the maximum payload size of the messages, zero when chunking is enabled.
+	maxMessageSize int
 }
 
 func (e *PulsarMetricsProducer) metricsDataPusher(ctx context.Context, md pmetric.Metrics) error {
-	messages, err := e.marshaler.Marshal(md, e.topic)
-	if err != nil {
-		return consumererror.NewPermanent(err)
+	if e.topics != nil {
+		return e.pushByTopic(ctx, md)
 	}
 
-	var errs error
-	for _, message := range messages {
+	messages, err := marshalMetrics(e.marshaler, md, e.topic, e.maxMessageSize)
+	return sendMarshaled(ctx, e.producer, messages, err)
+}
 
-		e.producer.SendAsync(ctx, message, func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
-			if err != nil {
-				errs = multierr.Append(errs, err)
-			}
-		})
+// pushByTopic sends the data of each resource to the topic resolved from its attributes.
+// Only the data of the topics it failed to be sent to is returned for retry, the data
+// which can't be resolved to a topic or marshaled is dropped.
+func (e *PulsarMetricsProducer) pushByTopic(ctx context.Context, md pmetric.Metrics) error {
+	byTopic, dropped := e.topics.metricsByTopic(md)
 
+	var errs error
+	failed := pmetric.NewMetrics()
+	for topic, data := range byTopic {
+		messages, marshalErr := marshalMetrics(e.marshaler, data, topic, e.maxMessageSize)
+		dropped = multierr.Append(dropped, marshalErr)
+		if err := e.topics.send(ctx, topic, messages); err != nil {
+			errs = multierr.Append(errs, err)
+			data.ResourceMetrics().MoveAndAppendTo(failed.ResourceMetrics())
+		}
 	}
 
-	return errs
+	if errs != nil {
+		return consumererror.NewMetrics(multierr.Append(errs, dropped), failed)
+	}
+	if dropped != nil {
+		return consumererror.NewPermanent(dropped)
+	}
+	return nil
 }
 
 func (e *PulsarMetricsProducer) Close(context.Context) error {
-	e.producer.Close()
-	e.client.Close()
+	closeProducers(e.client, e.producer, e.topics)
 	return nil
 }
 
 type PulsarLogsProducer struct {
 	client    pulsar.Client
 	producer  pulsar.Producer
+	topics    *topicProducers
 	topic     string
 	marshaler LogsMarshaler
 	logger    *zap.Logger
+	// maxMessageSize is the maximum payload size of the messages, zero when chunking is enabled.
+	maxMessageSize int
 }
 
 func (e *PulsarLogsProducer) logsDataPusher(ctx context.Context, ld plog.Logs) error {
-	messages, err := e.marshaler.Marshal(ld, e.topic)
-	if err != nil {
-		return consumererror.NewPermanent(err)
+	if e.topics != nil {
+		return e.pushByTopic(ctx, ld)
 	}
 
+	messages, err := marshalLogs(e.marshaler, ld, e.topic, e.maxMessageSize)
+	return sendMarshaled(ctx, e.producer, messages, err)
+}
+
+// pushByTopic sends the data of each resource to the topic resolved from its attributes.
+// Only the data of the topics it failed to be sent to is returned for retry, the data
+// which can't be resolved to a topic or marshaled is dropped.
+func (e *PulsarLogsProducer) pushByTopic(ctx context.Context, ld plog.Logs) error {
+	byTopic, dropped := e.topics.logsByTopic(ld)
+
 	var errs error
+	failed := plog.NewLogs()
+	for topic, data := range byTopic {
+		messages, marshalErr := marshalLogs(e.marshaler, data, topic, e.maxMessageSize)
+		dropped = multierr.Append(dropped, marshalErr)
+		if err := e.topics.send(ctx, topic, messages); err != nil {
+			errs = multierr.Append(errs, err)
+			data.ResourceLogs().MoveAndAppendTo(failed.ResourceLogs())
+		}
+	}
+
+	if errs != nil {
+		return consumererror.NewLogs(multierr.Append(errs, dropped), failed)
+	}
+	if dropped != nil {
+		return consumererror.NewPermanent(dropped)
+	}
+	return nil
+}
+
+func (e *PulsarLogsProducer) Close(context.Context) error {
+	closeProducers(e.client, e.producer, e.topics)
+	return nil
+}
+
+// sendMessages sends the messages asynchronously and waits for all of them to be
+// acknowledged, or to fail. The publish latency, the pending messages and the failures
+// are recorded per topic.
+func sendMessages(ctx context.Context, producer pulsar.Producer, messages []*pulsar.ProducerMessage) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs error
+	)
+	topic := producer.Topic()
+	wg.Add(len(messages))
+	pendingMessages.add(topic, int64(len(messages)))
 	for _, message := range messages {
 
-		e.producer.SendAsync(ctx, message, func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
+		start := time.Now()
+		producer.SendAsync(ctx, message, func(_ pulsar.MessageID, _ *pulsar.ProducerMessage, err error) {
+			defer wg.Done()
+			pendingMessages.add(topic, -1)
+			recordPublish(topic, time.Since(start).Milliseconds(), err)
 			if err != nil {
+				mu.Lock()
 				errs = multierr.Append(errs, err)
+				mu.Unlock()
 			}
 		})
 
 	}
+	wg.Wait()
 
 	return errs
 }
 
-func (e *PulsarLogsProducer) Close(context.Context) error {
-	e.producer.Close()
-	e.client.Close()
-	return nil
+func closeProducers(client pulsar.Client, producer pulsar.Producer, topics *topicProducers) {
+	if producer != nil {
+		producer.Close()
+	}
+	if topics != nil {
+		topics.Close()
+	}
+	if client != nil {
+		client.Close()
+	}
 }
 
-func newPulsarProducer(config Config) (pulsar.Client, pulsar.Producer, error) {
-	options := config.clientOptions()
+// newPulsarProducer creates the client and the producer for the configured topic. If the
+// topic is a template, the producers are instead created on demand for each resolved topic.
+func newPulsarProducer(config Config, logger *zap.Logger) (pulsar.Client, pulsar.Producer, *topicProducers, error) {
+	if err := view.Register(MetricViews()...); err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot register Pulsar exporter metric views: %w", err)
+	}
+
+	options, err := config.clientOptions()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	client, err := pulsar.NewClient(options)
 
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	producer, err := client.CreateProducer(pulsar.ProducerOptions{
-		Topic:       config.Topic,
-		SendTimeout: config.Timeout,
-	})
+	createProducer := func(topic string) (pulsar.Producer, error) {
+		return client.CreateProducer(config.producerOptions(topic))
+	}
+
+	if isTopicTemplate(config.Topic) {
+		return client, nil, newTopicProducers(config, createProducer, logger), nil
+	}
+
+	producer, err := createProducer(config.Topic)
 
 	if err != nil {
-		return nil, nil, err
+		client.Close()
+		return nil, nil, nil, err
 	}
 
-	return client, producer, nil
+	return client, producer, nil, nil
 }
 
 func newMetricsExporter(config Config, set component.ExporterCreateSettings, marshalers map[string]MetricsMarshaler) (*PulsarMetricsProducer, error) {
@@ -158,7 +271,7 @@ func newMetricsExporter(config Config, set component.ExporterCreateSettings, mar
 	if marshaler == nil {
 		return nil, errUnrecognizedEncoding
 	}
-	client, producer, err := newPulsarProducer(config)
+	client, producer, topics, err := newPulsarProducer(config, set.Logger)
 	if err != nil {
 		return nil, err
 	}
@@ -166,9 +279,12 @@ func newMetricsExporter(config Config, set component.ExporterCreateSettings, mar
 	return &PulsarMetricsProducer{
 		client:    client,
 		producer:  producer,
+		topics:    topics,
 		topic:     config.Topic,
 		marshaler: marshaler,
 		logger:    set.Logger,
+
+		maxMessageSize: config.maxMessageSize(),
 	}, nil
 
 }
@@ -178,16 +294,19 @@ func newTracesExporter(config Config, set component.ExporterCreateSettings, mars
 	if marshaler == nil {
 		return nil, errUnrecognizedEncoding
 	}
-	client, producer, err := newPulsarProducer(config)
+	client, producer, topics, err := newPulsarProducer(config, set.Logger)
 	if err != nil {
 		return nil, err
 	}
 	return &PulsarTracesProducer{
 		client:    client,
 		producer:  producer,
+		topics:    topics,
 		topic:     config.Topic,
 		marshaler: marshaler,
 		logger:    set.Logger,
+
+		maxMessageSize: config.maxMessageSize(),
 	}, nil
 }
 
@@ -196,7 +315,7 @@ func newLogsExporter(config Config, set component.ExporterCreateSettings, marsha
 	if marshaler == nil {
 		return nil, errUnrecognizedEncoding
 	}
-	client, producer, err := newPulsarProducer(config)
+	client, producer, topics, err := newPulsarProducer(config, set.Logger)
 	if err != nil {
 		return nil, err
 	}
@@ -204,9 +323,12 @@ func newLogsExporter(config Config, set component.ExporterCreateSettings, marsha
 	return &PulsarLogsProducer{
 		client:    client,
 		producer:  producer,
+		topics:    topics,
 		topic:     config.Topic,
 		marshaler: marshaler,
 		logger:    set.Logger,
+
+		maxMessageSize: config.maxMessageSize(),
 	}, nil
 
 }
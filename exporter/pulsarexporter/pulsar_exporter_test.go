@@ -87,8 +87,10 @@ func (c *customTraceMarshaler) Encoding() string {
 }
 
 type mockProducer struct {
-	topic string
-	name  string
+	topic   string
+	name    string
+	sendErr error
+	closed  bool
 }
 
 func (c *mockProducer) Topic() string {
@@ -103,8 +105,8 @@ func (c *mockProducer) Send(context.Context, *pulsar.ProducerMessage) (pulsar.Me
 	return nil, nil
 }
 
-func (c *mockProducer) SendAsync(context.Context, *pulsar.ProducerMessage, func(pulsar.MessageID, *pulsar.ProducerMessage, error)) {
-
+func (c *mockProducer) SendAsync(_ context.Context, message *pulsar.ProducerMessage, callback func(pulsar.MessageID, *pulsar.ProducerMessage, error)) {
+	go callback(nil, message, c.sendErr)
 }
 
 func (c *mockProducer) LastSequenceID() int64 {
@@ -116,4 +118,5 @@ func (c *mockProducer) Flush() error {
 }
 
 func (c *mockProducer) Close() {
+	c.closed = true
 }
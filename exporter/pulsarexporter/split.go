@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/pulsarexporter"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
+)
+
+// sendMarshaled sends the messages marshaled so far. The marshaling error, if any, is
+// reported as a permanent error since retrying the same data would fail again.
+func sendMarshaled(ctx context.Context, producer pulsar.Producer, messages []*pulsar.ProducerMessage, marshalErr error) error {
+	err := sendMessages(ctx, producer, messages)
+	if marshalErr != nil {
+		err = multierr.Append(err, consumererror.NewPermanent(marshalErr))
+	}
+	return err
+}
+
+func withinSize(messages []*pulsar.ProducerMessage, maxSize int) bool {
+	for _, message := range messages {
+		if len(message.Payload) > maxSize {
+			return false
+		}
+	}
+	return true
+}
+
+// marshalTraces marshals the traces into messages whose payload doesn't exceed maxSize,
+// splitting the traces in halves as long as needed. The spans which can't be split
+// further are dropped and reported in the returned error. A zero maxSize disables the
+// splitting.
+func marshalTraces(marshaler TracesMarshaler, td ptrace.Traces, topic string, maxSize int) ([]*pulsar.ProducerMessage, error) {
+	messages, err := marshaler.Marshal(td, topic)
+	if err != nil || maxSize <= 0 || withinSize(messages, maxSize) {
+		return messages, err
+	}
+	first, second, ok := splitTraces(td)
+	if !ok {
+		return nil, fmt.Errorf("span larger than the maximum message size of %d bytes", maxSize)
+	}
+	messages, err = marshalTraces(marshaler, first, topic, maxSize)
+	secondMessages, secondErr := marshalTraces(marshaler, second, topic, maxSize)
+	return append(messages, secondMessages...), multierr.Append(err, secondErr)
+}
+
+// splitTraces splits the traces in two halves, by resource when there are several of
+// them, by span otherwise. It returns false when there is a single span.
+func splitTraces(td ptrace.Traces) (ptrace.Traces, ptrace.Traces, bool) {
+	first, second := ptrace.NewTraces(), ptrace.NewTraces()
+	rss := td.ResourceSpans()
+	if rss.Len() > 1 {
+		for i := 0; i < rss.Len(); i++ {
+			dest := first
+			if i >= rss.Len()/2 {
+				dest = second
+			}
+			rss.At(i).CopyTo(dest.ResourceSpans().AppendEmpty())
+		}
+		return first, second, true
+	}
+	if td.SpanCount() < 2 {
+		return first, second, false
+	}
+
+	rs := rss.At(0)
+	firstRS, secondRS := first.ResourceSpans().AppendEmpty(), second.ResourceSpans().AppendEmpty()
+	for _, dest := range []ptrace.ResourceSpans{firstRS, secondRS} {
+		rs.Resource().CopyTo(dest.Resource())
+		dest.SetSchemaUrl(rs.SchemaUrl())
+	}
+	half, seen := td.SpanCount()/2, 0
+	for j := 0; j < rs.ScopeSpans().Len(); j++ {
+		ss := rs.ScopeSpans().At(j)
+		spans := ss.Spans()
+		inFirst := clamp(half-seen, 0, spans.Len())
+		for _, part := range []struct {
+			dest     ptrace.ResourceSpans
+			from, to int
+		}{{firstRS, 0, inFirst}, {secondRS, inFirst, spans.Len()}} {
+			if part.from == part.to {
+				continue
+			}
+			destSS := part.dest.ScopeSpans().AppendEmpty()
+			ss.Scope().CopyTo(destSS.Scope())
+			destSS.SetSchemaUrl(ss.SchemaUrl())
+			for k := part.from; k < part.to; k++ {
+				spans.At(k).CopyTo(destSS.Spans().AppendEmpty())
+			}
+		}
+		seen += spans.Len()
+	}
+	return first, second, true
+}
+
+// marshalMetrics is the metrics counterpart of marshalTraces, metrics are split by
+// resource then by metric, the data points of a single metric are not split.
+func marshalMetrics(marshaler MetricsMarshaler, md pmetric.Metrics, topic string, maxSize int) ([]*pulsar.ProducerMessage, error) {
+	messages, err := marshaler.Marshal(md, topic)
+	if err != nil || maxSize <= 0 || withinSize(messages, maxSize) {
+		return messages, err
+	}
+	first, second, ok := splitMetrics(md)
+	if !ok {
+		return nil, fmt.Errorf("metric larger than the maximum message size of %d bytes", maxSize)
+	}
+	messages, err = marshalMetrics(marshaler, first, topic, maxSize)
+	secondMessages, secondErr := marshalMetrics(marshaler, second, topic, maxSize)
+	return append(messages, secondMessages...), multierr.Append(err, secondErr)
+}
+
+func splitMetrics(md pmetric.Metrics) (pmetric.Metrics, pmetric.Metrics, bool) {
+	first, second := pmetric.NewMetrics(), pmetric.NewMetrics()
+	rms := md.ResourceMetrics()
+	if rms.Len() > 1 {
+		for i := 0; i < rms.Len(); i++ {
+			dest := first
+			if i >= rms.Len()/2 {
+				dest = second
+			}
+			rms.At(i).CopyTo(dest.ResourceMetrics().AppendEmpty())
+		}
+		return first, second, true
+	}
+	if md.MetricCount() < 2 {
+		return first, second, false
+	}
+
+	rm := rms.At(0)
+	firstRM, secondRM := first.ResourceMetrics().AppendEmpty(), second.ResourceMetrics().AppendEmpty()
+	for _, dest := range []pmetric.ResourceMetrics{firstRM, secondRM} {
+		rm.Resource().CopyTo(dest.Resource())
+		dest.SetSchemaUrl(rm.SchemaUrl())
+	}
+	half, seen := md.MetricCount()/2, 0
+	for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+		sm := rm.ScopeMetrics().At(j)
+		metrics := sm.Metrics()
+		inFirst := clamp(half-seen, 0, metrics.Len())
+		for _, part := range []struct {
+			dest     pmetric.ResourceMetrics
+			from, to int
+		}{{firstRM, 0, inFirst}, {secondRM, inFirst, metrics.Len()}} {
+			if part.from == part.to {
+				continue
+			}
+			destSM := part.dest.ScopeMetrics().AppendEmpty()
+			sm.Scope().CopyTo(destSM.Scope())
+			destSM.SetSchemaUrl(sm.SchemaUrl())
+			for k := part.from; k < part.to; k++ {
+				metrics.At(k).CopyTo(destSM.Metrics().AppendEmpty())
+			}
+		}
+		seen += metrics.Len()
+	}
+	return first, second, true
+}
+
+// marshalLogs is the logs counterpart of marshalTraces.
+func marshalLogs(marshaler LogsMarshaler, ld plog.Logs, topic string, maxSize int) ([]*pulsar.ProducerMessage, error) {
+	messages, err := marshaler.Marshal(ld, topic)
+	if err != nil || maxSize <= 0 || withinSize(messages, maxSize) {
+		return messages, err
+	}
+	first, second, ok := splitLogs(ld)
+	if !ok {
+		return nil, fmt.Errorf("log record larger than the maximum message size of %d bytes", maxSize)
+	}
+	messages, err = marshalLogs(marshaler, first, topic, maxSize)
+	secondMessages, secondErr := marshalLogs(marshaler, second, topic, maxSize)
+	return append(messages, secondMessages...), multierr.Append(err, secondErr)
+}
+
+func splitLogs(ld plog.Logs) (plog.Logs, plog.Logs, bool) {
+	first, second := plog.NewLogs(), plog.NewLogs()
+	rls := ld.ResourceLogs()
+	if rls.Len() > 1 {
+		for i := 0; i < rls.Len(); i++ {
+			dest := first
+			if i >= rls.Len()/2 {
+				dest = second
+			}
+			rls.At(i).CopyTo(dest.ResourceLogs().AppendEmpty())
+		}
+		return first, second, true
+	}
+	if ld.LogRecordCount() < 2 {
+		return first, second, false
+	}
+
+	rl := rls.At(0)
+	firstRL, secondRL := first.ResourceLogs().AppendEmpty(), second.ResourceLogs().AppendEmpty()
+	for _, dest := range []plog.ResourceLogs{firstRL, secondRL} {
+		rl.Resource().CopyTo(dest.Resource())
+		dest.SetSchemaUrl(rl.SchemaUrl())
+	}
+	half, seen := ld.LogRecordCount()/2, 0
+	for j := 0; j < rl.ScopeLogs().Len(); j++ {
+		sl := rl.ScopeLogs().At(j)
+		records := sl.LogRecords()
+		inFirst := clamp(half-seen, 0, records.Len())
+		for _, part := range []struct {
+			dest     plog.ResourceLogs
+			from, to int
+		}{{firstRL, 0, inFirst}, {secondRL, inFirst, records.Len()}} {
+			if part.from == part.to {
+				continue
+			}
+			destSL := part.dest.ScopeLogs().AppendEmpty()
+			sl.Scope().CopyTo(destSL.Scope())
+			destSL.SetSchemaUrl(sl.SchemaUrl())
+			for k := part.from; k < part.to; k++ {
+				records.At(k).CopyTo(destSL.LogRecords().AppendEmpty())
+			}
+		}
+		seen += records.Len()
+	}
+	return first, second, true
+}
+
+func clamp(v, low, high int) int {
+	if v < low {
+		return low
+	}
+	if v > high {
+		return high
+	}
+	return v
+}
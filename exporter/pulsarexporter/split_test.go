@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+)
+
+func TestSplitTraces(t *testing.T) {
+	first, second, ok := splitTraces(testdata.GenerateTracesManySpansSameResource(5))
+	require.True(t, ok)
+	assert.Equal(t, 2, first.SpanCount())
+	assert.Equal(t, 3, second.SpanCount())
+	assert.Equal(t, 1, first.ResourceSpans().Len())
+	assert.Equal(t, 1, second.ResourceSpans().Len())
+
+	first, second, ok = splitTraces(testdata.GenerateTracesTwoSpansSameResourceOneDifferent())
+	require.True(t, ok)
+	assert.Equal(t, 1, first.ResourceSpans().Len())
+	assert.Equal(t, 1, second.ResourceSpans().Len())
+
+	_, _, ok = splitTraces(testdata.GenerateTracesOneSpan())
+	assert.False(t, ok)
+}
+
+func TestSplitMetrics(t *testing.T) {
+	first, second, ok := splitMetrics(testdata.GenerateMetricsManyMetricsSameResource(4))
+	require.True(t, ok)
+	assert.Equal(t, 2, first.MetricCount())
+	assert.Equal(t, 2, second.MetricCount())
+
+	_, _, ok = splitMetrics(testdata.GenerateMetricsOneMetric())
+	assert.False(t, ok)
+}
+
+func TestSplitLogs(t *testing.T) {
+	first, second, ok := splitLogs(testdata.GenerateLogsManyLogRecordsSameResource(3))
+	require.True(t, ok)
+	assert.Equal(t, 1, first.LogRecordCount())
+	assert.Equal(t, 2, second.LogRecordCount())
+
+	_, _, ok = splitLogs(testdata.GenerateLogsOneLogRecord())
+	assert.False(t, ok)
+}
+
+func TestMarshalTraces_maxSize(t *testing.T) {
+	marshaler := tracesMarshalers()[defaultEncoding]
+	td := testdata.GenerateTracesManySpansSameResource(10)
+
+	whole, err := marshaler.Marshal(td, "default")
+	require.NoError(t, err)
+	require.Len(t, whole, 1)
+	one, err := marshaler.Marshal(testdata.GenerateTracesOneSpan(), "default")
+	require.NoError(t, err)
+
+	maxSize := len(whole[0].Payload) / 3
+	require.Greater(t, maxSize, len(one[0].Payload))
+	messages, err := marshalTraces(marshaler, td, "default", maxSize)
+	require.NoError(t, err)
+	assert.Greater(t, len(messages), 1)
+	for _, message := range messages {
+		assert.LessOrEqual(t, len(message.Payload), maxSize)
+	}
+
+	messages, err = marshalTraces(marshaler, td, "default", 0)
+	require.NoError(t, err)
+	assert.Len(t, messages, 1)
+
+	messages, err = marshalTraces(marshaler, td, "default", 1)
+	assert.Error(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestMarshalLogs_maxSize(t *testing.T) {
+	marshaler := logsMarshalers()[defaultEncoding]
+	ld := testdata.GenerateLogsManyLogRecordsSameResource(10)
+
+	whole, err := marshaler.Marshal(ld, "default")
+	require.NoError(t, err)
+	require.Len(t, whole, 1)
+
+	maxSize := len(whole[0].Payload) / 2
+	messages, err := marshalLogs(marshaler, ld, "default", maxSize)
+	require.NoError(t, err)
+	assert.Greater(t, len(messages), 1)
+	for _, message := range messages {
+		assert.LessOrEqual(t, len(message.Payload), maxSize)
+	}
+}
+
+func TestSendMarshaled_permanentError(t *testing.T) {
+	marshaler := metricsMarshalers()[defaultEncoding]
+	messages, marshalErr := marshalMetrics(marshaler, testdata.GenerateMetricsOneMetric(), "default", 1)
+	require.Error(t, marshalErr)
+
+	err := sendMarshaled(context.Background(), &mockProducer{name: "producer1", topic: "default"}, messages, marshalErr)
+	assert.True(t, consumererror.IsPermanent(err))
+}
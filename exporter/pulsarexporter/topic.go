@@ -0,0 +1,271 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/pulsarexporter"
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+const (
+	// creationPolicyFail returns an error when the topic of some data can't be resolved,
+	// or when a producer can't be created for it.
+	creationPolicyFail = "fail"
+	// creationPolicyFallback sends the data to the fallback topic instead.
+	creationPolicyFallback = "fallback"
+
+	defaultTopicCacheSize = 100
+)
+
+var topicPlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// isTopicTemplate returns true if the topic contains resource attribute placeholders.
+func isTopicTemplate(topic string) bool {
+	return topicPlaceholder.MatchString(topic)
+}
+
+// resolveTopic replaces the placeholders of the topic template with the values of
+// the matching resource attributes. It returns false if any attribute is missing, or
+// holds a "/" which would change the tenant, namespace or topic the data is sent to.
+func resolveTopic(template string, resource pcommon.Resource) (string, bool) {
+	resolved := true
+	topic := topicPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := strings.TrimSpace(placeholder[1 : len(placeholder)-1])
+		v, ok := resource.Attributes().Get(name)
+		if !ok || v.AsString() == "" || strings.Contains(v.AsString(), "/") {
+			resolved = false
+			return ""
+		}
+		return v.AsString()
+	})
+	return topic, resolved
+}
+
+// topicProducers keeps the producers of the topics resolved from a topic template.
+// Producers are created the first time a topic is seen, and the least recently used
+// producer is evicted once the cache is full. An evicted producer is only closed once
+// the sends in flight through it are done.
+type topicProducers struct {
+	template       string
+	creationPolicy string
+	fallbackTopic  string
+	cacheSize      int
+	createProducer func(topic string) (pulsar.Producer, error)
+	logger         *zap.Logger
+
+	mu        sync.Mutex
+	lru       *list.List
+	producers map[string]*list.Element
+}
+
+type cachedProducer struct {
+	topic    string
+	producer pulsar.Producer
+	// refs is the number of pushes using the producer, guarded by topicProducers.mu.
+	refs    int
+	evicted bool
+}
+
+func newTopicProducers(config Config, createProducer func(topic string) (pulsar.Producer, error), logger *zap.Logger) *topicProducers {
+	cacheSize := config.TopicRouting.CacheSize
+	if cacheSize <= 0 {
+		cacheSize = defaultTopicCacheSize
+	}
+	return &topicProducers{
+		template:       config.Topic,
+		creationPolicy: config.TopicRouting.CreationPolicy,
+		fallbackTopic:  config.TopicRouting.FallbackTopic,
+		cacheSize:      cacheSize,
+		createProducer: createProducer,
+		logger:         logger,
+		lru:            list.New(),
+		producers:      map[string]*list.Element{},
+	}
+}
+
+// topicFor returns the topic the data of the given resource is sent to.
+func (p *topicProducers) topicFor(resource pcommon.Resource) (string, error) {
+	topic, ok := resolveTopic(p.template, resource)
+	if ok {
+		return topic, nil
+	}
+	if p.creationPolicy == creationPolicyFallback {
+		return p.fallbackTopic, nil
+	}
+	return "", fmt.Errorf("failed to resolve topic %q, resource attributes are missing or invalid", p.template)
+}
+
+// acquire returns the producer for the topic, creating it if needed. The producer
+// must be released once the messages sent through it are acknowledged.
+func (p *topicProducers) acquire(topic string) (*cachedProducer, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if elem, ok := p.producers[topic]; ok {
+		return p.use(elem), nil
+	}
+
+	producer, err := p.createProducer(topic)
+	if err != nil {
+		if p.creationPolicy != creationPolicyFallback || topic == p.fallbackTopic {
+			return nil, err
+		}
+		p.logger.Warn("Failed to create producer, using the fallback topic",
+			zap.String("topic", topic),
+			zap.String("fallback_topic", p.fallbackTopic),
+			zap.Error(err))
+		topic = p.fallbackTopic
+		if elem, ok := p.producers[topic]; ok {
+			return p.use(elem), nil
+		}
+		if producer, err = p.createProducer(topic); err != nil {
+			return nil, err
+		}
+	}
+
+	elem := p.lru.PushFront(&cachedProducer{topic: topic, producer: producer})
+	p.producers[topic] = elem
+	if p.lru.Len() > p.cacheSize {
+		oldest := p.lru.Remove(p.lru.Back()).(*cachedProducer)
+		delete(p.producers, oldest.topic)
+		oldest.evicted = true
+		if oldest.refs == 0 {
+			oldest.producer.Close()
+		}
+	}
+	return p.use(elem), nil
+}
+
+func (p *topicProducers) use(elem *list.Element) *cachedProducer {
+	p.lru.MoveToFront(elem)
+	cp := elem.Value.(*cachedProducer)
+	cp.refs++
+	return cp
+}
+
+// release releases a producer returned by acquire, closing it if it has been evicted
+// in the meantime and no other push uses it.
+func (p *topicProducers) release(cp *cachedProducer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cp.refs--
+	if cp.evicted && cp.refs == 0 {
+		cp.producer.Close()
+	}
+}
+
+// send sends the messages to the topic, through the producer returned by acquire.
+func (p *topicProducers) send(ctx context.Context, topic string, messages []*pulsar.ProducerMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	cp, err := p.acquire(topic)
+	if err != nil {
+		return err
+	}
+	defer p.release(cp)
+	return sendMessages(ctx, cp.producer, messages)
+}
+
+func (p *topicProducers) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for elem := p.lru.Front(); elem != nil; elem = elem.Next() {
+		elem.Value.(*cachedProducer).producer.Close()
+	}
+	p.lru.Init()
+	p.producers = map[string]*list.Element{}
+}
+
+// tracesByTopic splits the traces by the topic resolved from their resource. The resources
+// whose topic can't be resolved are left out and reported in the returned error.
+func (p *topicProducers) tracesByTopic(td ptrace.Traces) (map[string]ptrace.Traces, error) {
+	out := map[string]ptrace.Traces{}
+	var errs error
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		topic, err := p.topicFor(rs.Resource())
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		traces, ok := out[topic]
+		if !ok {
+			traces = ptrace.NewTraces()
+			out[topic] = traces
+		}
+		rs.CopyTo(traces.ResourceSpans().AppendEmpty())
+	}
+	return out, errs
+}
+
+// metricsByTopic is the metrics counterpart of tracesByTopic.
+func (p *topicProducers) metricsByTopic(md pmetric.Metrics) (map[string]pmetric.Metrics, error) {
+	out := map[string]pmetric.Metrics{}
+	var errs error
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		topic, err := p.topicFor(rm.Resource())
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		metrics, ok := out[topic]
+		if !ok {
+			metrics = pmetric.NewMetrics()
+			out[topic] = metrics
+		}
+		rm.CopyTo(metrics.ResourceMetrics().AppendEmpty())
+	}
+	return out, errs
+}
+
+// logsByTopic is the logs counterpart of tracesByTopic.
+func (p *topicProducers) logsByTopic(ld plog.Logs) (map[string]plog.Logs, error) {
+	out := map[string]plog.Logs{}
+	var errs error
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		topic, err := p.topicFor(rl.Resource())
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		logs, ok := out[topic]
+		if !ok {
+			logs = plog.NewLogs()
+			out[topic] = logs
+		}
+		rl.CopyTo(logs.ResourceLogs().AppendEmpty())
+	}
+	return out, errs
+}
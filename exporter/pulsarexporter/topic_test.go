@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+func TestResolveTopic(t *testing.T) {
+	res := pcommon.NewResource()
+	res.Attributes().PutStr("service.namespace", "team-a")
+
+	topic, ok := resolveTopic("persistent://tenant/{service.namespace}/otlp-spans", res)
+	assert.True(t, ok)
+	assert.Equal(t, "persistent://tenant/team-a/otlp-spans", topic)
+
+	_, ok = resolveTopic("persistent://tenant/{service.name}/otlp-spans", res)
+	assert.False(t, ok)
+
+	res.Attributes().PutStr("service.namespace", "other-tenant/ns")
+	_, ok = resolveTopic("persistent://tenant/{service.namespace}/otlp-spans", res)
+	assert.False(t, ok)
+
+	assert.True(t, isTopicTemplate("persistent://tenant/{service.namespace}/otlp-spans"))
+	assert.False(t, isTopicTemplate("otlp_spans"))
+}
+
+func newTestTopicProducers(policy string, cacheSize int, failing map[string]bool) (*topicProducers, map[string]int) {
+	topics, created, _ := newTestTopicProducersWithErrors(policy, cacheSize, failing, nil)
+	return topics, created
+}
+
+func newTestTopicProducersWithErrors(policy string, cacheSize int, failing map[string]bool, sendErrs map[string]error) (*topicProducers, map[string]int, map[string]*mockProducer) {
+	created := map[string]int{}
+	producers := map[string]*mockProducer{}
+	cfg := Config{
+		Topic: "{service.namespace}-spans",
+		TopicRouting: TopicRouting{
+			CacheSize:      cacheSize,
+			CreationPolicy: policy,
+			FallbackTopic:  "default-spans",
+		},
+	}
+	createProducer := func(topic string) (pulsar.Producer, error) {
+		if failing[topic] {
+			return nil, errors.New("topic does not exist")
+		}
+		created[topic]++
+		producers[topic] = &mockProducer{name: topic, topic: topic, sendErr: sendErrs[topic]}
+		return producers[topic], nil
+	}
+	return newTopicProducers(cfg, createProducer, zap.NewNop()), created, producers
+}
+
+func tracesWithNamespaces(namespaces ...string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	for _, ns := range namespaces {
+		rs := td.ResourceSpans().AppendEmpty()
+		if ns != "" {
+			rs.Resource().Attributes().PutStr("service.namespace", ns)
+		}
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+	}
+	return td
+}
+
+func TestTopicProducers_tracesByTopic(t *testing.T) {
+	topics, _ := newTestTopicProducers(creationPolicyFail, 10, nil)
+	byTopic, err := topics.tracesByTopic(tracesWithNamespaces("a", "b", "a"))
+	require.NoError(t, err)
+	require.Len(t, byTopic, 2)
+	assert.Equal(t, 2, byTopic["a-spans"].ResourceSpans().Len())
+	assert.Equal(t, 1, byTopic["b-spans"].ResourceSpans().Len())
+
+	byTopic, err = topics.tracesByTopic(tracesWithNamespaces("a", ""))
+	assert.Error(t, err)
+	require.Len(t, byTopic, 1)
+	assert.Equal(t, 1, byTopic["a-spans"].ResourceSpans().Len())
+
+	topics, _ = newTestTopicProducers(creationPolicyFallback, 10, nil)
+	byTopic, err = topics.tracesByTopic(tracesWithNamespaces("a", ""))
+	require.NoError(t, err)
+	assert.Equal(t, 1, byTopic["default-spans"].ResourceSpans().Len())
+}
+
+func TestTopicProducers_get(t *testing.T) {
+	topics, created := newTestTopicProducers(creationPolicyFail, 2, nil)
+
+	for _, topic := range []string{"a", "b", "a", "c", "a", "b"} {
+		cp, err := topics.acquire(topic)
+		require.NoError(t, err)
+		assert.Equal(t, topic, cp.producer.Topic())
+		topics.release(cp)
+	}
+	// "b" was evicted when "c" was added, so it had to be created again.
+	assert.Equal(t, map[string]int{"a": 1, "b": 2, "c": 1}, created)
+	assert.Equal(t, 2, topics.lru.Len())
+
+	topics.Close()
+	assert.Equal(t, 0, topics.lru.Len())
+}
+
+func TestTopicProducers_get_creationPolicy(t *testing.T) {
+	failing := map[string]bool{"missing": true}
+
+	topics, _ := newTestTopicProducers(creationPolicyFail, 10, failing)
+	_, err := topics.acquire("missing")
+	assert.Error(t, err)
+
+	topics, _ = newTestTopicProducers(creationPolicyFallback, 10, failing)
+	cp, err := topics.acquire("missing")
+	require.NoError(t, err)
+	assert.Equal(t, "default-spans", cp.producer.Topic())
+}
+
+func TestTopicProducers_evictionWaitsForRelease(t *testing.T) {
+	topics, _, producers := newTestTopicProducersWithErrors(creationPolicyFail, 1, nil, nil)
+
+	inFlight, err := topics.acquire("a")
+	require.NoError(t, err)
+	cp, err := topics.acquire("b")
+	require.NoError(t, err)
+	topics.release(cp)
+
+	// "a" is evicted by "b" but still used, it is closed once released.
+	assert.False(t, producers["a"].closed)
+	topics.release(inFlight)
+	assert.True(t, producers["a"].closed)
+	assert.False(t, producers["b"].closed)
+}
+
+func TestTracesPusher_templatedTopic(t *testing.T) {
+	topics, created := newTestTopicProducers(creationPolicyFail, 10, nil)
+	producer := PulsarTracesProducer{topics: topics, marshaler: tracesMarshalers()[defaultEncoding]}
+
+	err := producer.tracesPusher(context.Background(), tracesWithNamespaces("a", "b"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a-spans": 1, "b-spans": 1}, created)
+}
+
+func TestTracesPusher_templatedTopicPartialFailure(t *testing.T) {
+	topics, _, _ := newTestTopicProducersWithErrors(creationPolicyFail, 10, nil, map[string]error{"b-spans": errors.New("timeout")})
+	producer := PulsarTracesProducer{topics: topics, marshaler: tracesMarshalers()[defaultEncoding]}
+
+	// "a" is sent, "b" fails to be sent and the resource without namespace can't be resolved.
+	err := producer.tracesPusher(context.Background(), tracesWithNamespaces("a", "b", ""))
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	var tracesErr consumererror.Traces
+	require.True(t, errors.As(err, &tracesErr))
+	failed := tracesErr.GetTraces()
+	require.Equal(t, 1, failed.ResourceSpans().Len())
+	ns, _ := failed.ResourceSpans().At(0).Resource().Attributes().Get("service.namespace")
+	assert.Equal(t, "b", ns.Str())
+
+	err = producer.tracesPusher(context.Background(), tracesWithNamespaces("a", ""))
+	assert.True(t, consumererror.IsPermanent(err))
+}
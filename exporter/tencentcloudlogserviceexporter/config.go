@@ -16,19 +16,26 @@ package tencentcloudlogserviceexporter // import "github.com/open-telemetry/open
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 // Config defines configuration for TencentCloud Log Service exporter.
 type Config struct {
-	config.ExporterSettings `mapstructure:",squash"`
-	// LogService's Region, https://cloud.tencent.com/document/product/614/18940
+	config.ExporterSettings        `mapstructure:",squash"`
+	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
+	// LogService's Region, https://cloud.tencent.com/document/product/614/18940.
+	// When empty, the region of the CVM instance is discovered from its metadata, see AutoDiscovery
 	// for TencentCloud Kubernetes(or CVM), set ap-{region}.cls.tencentyun.com, eg ap-beijing.cls.tencentyun.com;
 	//  others set ap-{region}.cls.tencentcs.com, eg ap-beijing.cls.tencentcs.com
 	Region string `mapstructure:"region"`
-	// LogService's LogSet Name
+	// LogService's LogSet Name. When empty, the LogSet of the region is looked up in AutoDiscovery.LogSets
 	LogSet string `mapstructure:"logset"`
 	// LogService's Topic Name
 	Topic string `mapstructure:"topic"`
@@ -36,14 +43,114 @@ type Config struct {
 	SecretID string `mapstructure:"secret_id"`
 	// TencentCloud access key secret
 	SecretKey string `mapstructure:"secret_key"`
+	// Mapping controls how log records are mapped to LogService keys
+	Mapping MappingSettings `mapstructure:"mapping"`
+	// LogGroup controls the source and filename of the LogService log groups, and the time of their logs
+	LogGroup LogGroupSettings `mapstructure:"log_group"`
+	// AutoDiscovery controls how the region and the LogSet are set when they aren't configured
+	AutoDiscovery AutoDiscoverySettings `mapstructure:"auto_discovery"`
+}
+
+// AutoDiscoverySettings defines how the region is discovered from the CVM instance metadata, and
+// how the LogSet is picked for the region, so that a single configuration fits the whole fleet.
+type AutoDiscoverySettings struct {
+	// MetadataEndpoint is the base URL of the CVM instance metadata, the region is read from its
+	// "placement/region" path.
+	MetadataEndpoint string `mapstructure:"metadata_endpoint"`
+	// Timeout of the metadata requests.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// LogSets maps the regions to the LogSet used in the region when LogSet isn't set.
+	LogSets map[string]string `mapstructure:"logsets"`
+}
+
+// LogGroupSettings defines how the log group metadata and the log times are set.
+type LogGroupSettings struct {
+	// SourceAttributes lists the attributes holding the source of the logs, such as the IP of the host
+	// they were forwarded from. Log record attributes are looked up before resource attributes, and
+	// the first one found is used.
+	SourceAttributes []string `mapstructure:"source_attributes"`
+	// Source is the source used when none of the SourceAttributes is found. When empty, LogService
+	// sets the IP of the collector as source.
+	Source string `mapstructure:"source"`
+	// FilenameAttributes lists the attributes holding the filename of the logs, looked up the same
+	// way as SourceAttributes.
+	FilenameAttributes []string `mapstructure:"filename_attributes"`
+	// Filename is the filename used when none of the FilenameAttributes is found.
+	Filename string `mapstructure:"filename"`
+	// TimeAttributes lists the log record attributes holding the time of the log, the first one
+	// holding a valid time is used. String values are parsed as RFC3339 times, numeric values as
+	// Unix times in seconds, milliseconds, microseconds or nanoseconds depending on their magnitude.
+	// When none is found, the timestamp of the record is used, then its observed timestamp.
+	TimeAttributes []string `mapstructure:"time_attributes"`
+}
+
+// MappingSettings defines how log record fields are mapped to LogService keys.
+type MappingSettings struct {
+	// Attributes promotes log record attributes to top level LogService keys. The map key is the
+	// attribute name and the value the LogService key, an empty value keeps the attribute name.
+	// Promoted attributes are removed from the "attribute" key. The keys written for every log,
+	// such as "content" or "host", can't be used.
+	Attributes map[string]string `mapstructure:"attributes"`
+	// ResourceAttributes promotes resource attributes to top level LogService keys, the same way
+	// as Attributes. Promoted attributes are removed from the "resource" key.
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
+	// FlattenBody maps the fields of map bodies, or string bodies holding a JSON object, to
+	// "content.<path>" keys instead of a single "content" key.
+	FlattenBody bool `mapstructure:"flatten_body"`
+	// MaxDepth limits the nesting levels flattened from the body, deeper values are kept
+	// JSON encoded. Zero means no limit.
+	MaxDepth int `mapstructure:"max_depth"`
+	// MaxFields caps the number of keys of a single LogService log. Only the promoted attribute
+	// and flattened body keys are dropped to honor it, the keys written for every log are always
+	// kept. Zero means no limit.
+	MaxFields int `mapstructure:"max_fields"`
 }
 
 var _ component.ExporterConfig = (*Config)(nil)
 
 // Validate checks if the exporter configuration is valid
 func (cfg *Config) Validate() error {
-	if cfg == nil || cfg.Region == "" || cfg.LogSet == "" || cfg.Topic == "" {
-		return errors.New("missing tencentcloudlogservice params: Region, LogSet, Topic")
+	if cfg == nil || cfg.Topic == "" {
+		return errors.New("missing tencentcloudlogservice params: Topic")
+	}
+	if cfg.LogSet == "" && len(cfg.AutoDiscovery.LogSets) == 0 {
+		return errors.New("missing tencentcloudlogservice params: LogSet, or auto_discovery.logsets")
+	}
+	if cfg.Region == "" {
+		if cfg.AutoDiscovery.MetadataEndpoint == "" {
+			return errors.New("auto_discovery.metadata_endpoint must be set to discover the region")
+		}
+		if cfg.AutoDiscovery.Timeout <= 0 {
+			return errors.New("auto_discovery.timeout must be positive")
+		}
+	} else if cfg.LogSet == "" && cfg.AutoDiscovery.LogSets[cfg.Region] == "" {
+		return fmt.Errorf("auto_discovery.logsets has no LogSet for the region %q", cfg.Region)
+	}
+	if err := cfg.QueueSettings.Validate(); err != nil {
+		return fmt.Errorf("sending_queue settings has invalid configuration: %w", err)
+	}
+	if cfg.Mapping.MaxDepth < 0 {
+		return errors.New("mapping.max_depth must not be negative")
+	}
+	if cfg.Mapping.MaxFields < 0 {
+		return errors.New("mapping.max_fields must not be negative")
+	}
+	if err := validatePromotedKeys("mapping.attributes", cfg.Mapping.Attributes); err != nil {
+		return err
+	}
+	return validatePromotedKeys("mapping.resource_attributes", cfg.Mapping.ResourceAttributes)
+}
+
+// validatePromotedKeys checks that the promoted attributes don't override the keys
+// written for every log.
+func validatePromotedKeys(field string, promoted map[string]string) error {
+	for name, key := range promoted {
+		if key == "" {
+			key = name
+		}
+		if _, ok := reservedKeys[key]; ok {
+			return fmt.Errorf("%s: attribute %q can't be promoted to the reserved key %q", field, name, key)
+		}
 	}
 	return nil
 }
@@ -17,12 +17,14 @@ package tencentcloudlogserviceexporter
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -31,6 +33,8 @@ func TestLoadConfig(t *testing.T) {
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
 	require.NoError(t, err)
 
+	storageID := component.NewID("file_storage")
+
 	tests := []struct {
 		id       component.ID
 		expected component.ExporterConfig
@@ -39,11 +43,97 @@ func TestLoadConfig(t *testing.T) {
 			id: component.NewIDWithName(typeStr, "2"),
 			expected: &Config{
 				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				TimeoutSettings:  exporterhelper.NewDefaultTimeoutSettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
 				Region:           "ap-beijing",
 				LogSet:           "demo-logset",
 				Topic:            "demo-topic",
 				SecretID:         "demo-secret-id",
 				SecretKey:        "demo-secret-key",
+				AutoDiscovery:    defaultAutoDiscoverySettings(),
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "mapping"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				TimeoutSettings:  exporterhelper.NewDefaultTimeoutSettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+				Region:           "ap-beijing",
+				LogSet:           "demo-logset",
+				Topic:            "demo-topic",
+				Mapping: MappingSettings{
+					Attributes:         map[string]string{"http.method": "method"},
+					ResourceAttributes: map[string]string{"k8s.pod.name": ""},
+					FlattenBody:        true,
+					MaxDepth:           2,
+					MaxFields:          100,
+				},
+				AutoDiscovery: defaultAutoDiscoverySettings(),
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "log_group"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				TimeoutSettings:  exporterhelper.NewDefaultTimeoutSettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+				Region:           "ap-beijing",
+				LogSet:           "demo-logset",
+				Topic:            "demo-topic",
+				LogGroup: LogGroupSettings{
+					SourceAttributes:   []string{"host.ip", "net.host.ip"},
+					FilenameAttributes: []string{"log.file.path"},
+					Filename:           "stdout",
+					TimeAttributes:     []string{"time", "timestamp"},
+				},
+				AutoDiscovery: defaultAutoDiscoverySettings(),
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "queue"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				TimeoutSettings: exporterhelper.TimeoutSettings{
+					Timeout: 10 * time.Second,
+				},
+				QueueSettings: exporterhelper.QueueSettings{
+					Enabled:      true,
+					NumConsumers: 2,
+					QueueSize:    100,
+					StorageID:    &storageID,
+				},
+				RetrySettings: exporterhelper.RetrySettings{
+					Enabled:         true,
+					InitialInterval: 10 * time.Second,
+					MaxInterval:     time.Minute,
+					MaxElapsedTime:  10 * time.Minute,
+				},
+				Region:        "ap-beijing",
+				LogSet:        "demo-logset",
+				Topic:         "demo-topic",
+				AutoDiscovery: defaultAutoDiscoverySettings(),
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "auto_discovery"),
+			expected: &Config{
+				ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+				TimeoutSettings:  exporterhelper.NewDefaultTimeoutSettings(),
+				QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+				RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+				Topic:            "demo-topic",
+				AutoDiscovery: AutoDiscoverySettings{
+					MetadataEndpoint: "http://169.254.0.23/latest/meta-data",
+					Timeout:          2 * time.Second,
+					LogSets: map[string]string{
+						"ap-beijing":  "beijing-logset",
+						"ap-shanghai": "shanghai-logset",
+					},
+				},
 			},
 		},
 	}
@@ -62,3 +152,57 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateReservedKeys(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Region = "ap-beijing"
+	cfg.LogSet = "demo-logset"
+	cfg.Topic = "demo-topic"
+
+	cfg.Mapping.Attributes = map[string]string{"http.method": "method"}
+	cfg.Mapping.ResourceAttributes = map[string]string{"k8s.pod.name": ""}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Mapping.Attributes = map[string]string{"msg": "content"}
+	assert.EqualError(t, cfg.Validate(), `mapping.attributes: attribute "msg" can't be promoted to the reserved key "content"`)
+
+	cfg.Mapping.Attributes = nil
+	cfg.Mapping.ResourceAttributes = map[string]string{"host": ""}
+	assert.EqualError(t, cfg.Validate(), `mapping.resource_attributes: attribute "host" can't be promoted to the reserved key "host"`)
+}
+
+func TestValidateQueue(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Region = "ap-beijing"
+	cfg.LogSet = "demo-logset"
+	cfg.Topic = "demo-topic"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.QueueSettings.QueueSize = 0
+	assert.EqualError(t, cfg.Validate(), "sending_queue settings has invalid configuration: queue size must be positive")
+}
+
+func TestValidateAutoDiscovery(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Topic = "demo-topic"
+	assert.EqualError(t, cfg.Validate(), "missing tencentcloudlogservice params: LogSet, or auto_discovery.logsets")
+
+	// the region is discovered
+	cfg.LogSet = "demo-logset"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.AutoDiscovery.Timeout = 0
+	assert.EqualError(t, cfg.Validate(), "auto_discovery.timeout must be positive")
+
+	cfg.AutoDiscovery.MetadataEndpoint = ""
+	assert.EqualError(t, cfg.Validate(), "auto_discovery.metadata_endpoint must be set to discover the region")
+
+	// the LogSet of a configured region is looked up in the logsets
+	cfg.Region = "ap-beijing"
+	cfg.LogSet = ""
+	cfg.AutoDiscovery.LogSets = map[string]string{"ap-shanghai": "shanghai-logset"}
+	assert.EqualError(t, cfg.Validate(), `auto_discovery.logsets has no LogSet for the region "ap-beijing"`)
+
+	cfg.AutoDiscovery.LogSets["ap-beijing"] = "beijing-logset"
+	assert.NoError(t, cfg.Validate())
+}
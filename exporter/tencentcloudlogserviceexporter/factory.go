@@ -16,9 +16,11 @@ package tencentcloudlogserviceexporter // import "github.com/open-telemetry/open
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 )
 
 const (
@@ -40,6 +42,18 @@ func NewFactory() component.ExporterFactory {
 func createDefaultConfig() component.ExporterConfig {
 	return &Config{
 		ExporterSettings: config.NewExporterSettings(component.NewID(typeStr)),
+		TimeoutSettings:  exporterhelper.NewDefaultTimeoutSettings(),
+		QueueSettings:    exporterhelper.NewDefaultQueueSettings(),
+		RetrySettings:    exporterhelper.NewDefaultRetrySettings(),
+		AutoDiscovery:    defaultAutoDiscoverySettings(),
+	}
+}
+
+// defaultAutoDiscoverySettings discovers the region from the metadata of the CVM instance.
+func defaultAutoDiscoverySettings() AutoDiscoverySettings {
+	return AutoDiscoverySettings{
+		MetadataEndpoint: defaultMetadataEndpoint,
+		Timeout:          5 * time.Second,
 	}
 }
 
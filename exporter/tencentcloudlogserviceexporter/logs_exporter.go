@@ -25,31 +25,66 @@ import (
 
 // newLogsExporter return a new LogService logs exporter.
 func newLogsExporter(set component.ExporterCreateSettings, cfg component.ExporterConfig) (component.LogsExporter, error) {
+	c := cfg.(*Config)
 	l := &logServiceLogsSender{
-		logger: set.Logger,
+		logger:   set.Logger,
+		config:   c,
+		mapping:  c.Mapping,
+		logGroup: c.LogGroup,
 	}
 
-	l.client = newLogServiceClient(cfg.(*Config), set.Logger)
+	// the region is discovered when the exporter starts
+	if c.Region != "" {
+		resolved, err := resolveConfig(context.Background(), c)
+		if err != nil {
+			return nil, err
+		}
+		l.client = newLogServiceClient(resolved, set.Logger)
+	}
 
 	return exporterhelper.NewLogsExporter(
 		context.TODO(),
 		set,
 		cfg,
-		l.pushLogsData)
+		l.pushLogsData,
+		// the LogService client doesn't retry nor buffer, exporterhelper does it.
+		exporterhelper.WithStart(l.start),
+		exporterhelper.WithTimeout(c.TimeoutSettings),
+		exporterhelper.WithQueue(c.QueueSettings),
+		exporterhelper.WithRetry(c.RetrySettings))
 }
 
 type logServiceLogsSender struct {
-	logger *zap.Logger
-	client logServiceClient
+	logger   *zap.Logger
+	config   *Config
+	client   logServiceClient
+	mapping  MappingSettings
+	logGroup LogGroupSettings
+}
+
+// start creates the LogService client once the region is discovered from the instance
+// metadata, when the region isn't configured.
+func (s *logServiceLogsSender) start(ctx context.Context, _ component.Host) error {
+	if s.client != nil {
+		return nil
+	}
+	resolved, err := resolveConfig(ctx, s.config)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Discovered the LogService region from the instance metadata",
+		zap.String("region", resolved.Region), zap.String("logset", resolved.LogSet))
+	s.client = newLogServiceClient(resolved, s.logger)
+	return nil
 }
 
 func (s *logServiceLogsSender) pushLogsData(
 	ctx context.Context,
 	md plog.Logs) error {
 	var err error
-	clsLogs := convertLogs(md)
-	if len(clsLogs) > 0 {
-		err = s.client.sendLogs(clsLogs)
+	logGroups := convertLogs(md, s.mapping, s.logGroup)
+	if len(logGroups) > 0 {
+		err = s.client.sendLogGroups(ctx, logGroups)
 	}
 	return err
 }
@@ -16,6 +16,8 @@ package tencentcloudlogserviceexporter // import "github.com/open-telemetry/open
 
 import (
 	"encoding/json"
+	"math"
+	"sort"
 	"strconv"
 	"time"
 
@@ -46,32 +48,142 @@ const (
 	clsLogInstrumentationVersion = "otlp.version"
 )
 
-func convertLogs(ld plog.Logs) []*cls.Log {
-	clsLogs := make([]*cls.Log, 0, ld.LogRecordCount())
+// reservedKeys are the keys written for every log, they can't be used by promoted attributes.
+var reservedKeys = map[string]struct{}{
+	traceIDField:                 {},
+	spanIDField:                  {},
+	clsLogTimeUnixNano:           {},
+	clsLogSeverityNumber:         {},
+	clsLogSeverityText:           {},
+	clsLogContent:                {},
+	clsLogAttribute:              {},
+	clsLogFlags:                  {},
+	clsLogResource:               {},
+	clsLogHost:                   {},
+	clsLogService:                {},
+	clsLogInstrumentationName:    {},
+	clsLogInstrumentationVersion: {},
+}
+
+// convertLogs converts the logs into LogService log groups, one per source and filename.
+func convertLogs(ld plog.Logs, mapping MappingSettings, logGroup LogGroupSettings) []*cls.LogGroup {
+	var logGroups []*cls.LogGroup
+	groupIndex := map[[2]string]*cls.LogGroup{}
 
 	rls := ld.ResourceLogs()
 	for i := 0; i < rls.Len(); i++ {
 		rl := rls.At(i)
 		ills := rl.ScopeLogs()
 		resource := rl.Resource()
-		resourceContents := resourceToLogContents(resource)
+		resourceContents := resourceToLogContents(resource, mapping.ResourceAttributes)
+		promotedResourceContents := promotedAttributesToLogContents(resource.Attributes(), mapping.ResourceAttributes)
 		for j := 0; j < ills.Len(); j++ {
 			ils := ills.At(j)
 			instrumentationLibraryContents := instrumentationLibraryToLogContents(ils.Scope())
 			logs := ils.LogRecords()
 			for j := 0; j < logs.Len(); j++ {
-				clsLog := mapLogRecordToLogService(logs.At(j), resourceContents, instrumentationLibraryContents)
-				if clsLog != nil {
-					clsLogs = append(clsLogs, clsLog)
+				lr := logs.At(j)
+				clsLog := mapLogRecordToLogService(lr, resourceContents, promotedResourceContents, instrumentationLibraryContents, mapping, logGroup.TimeAttributes)
+				if clsLog == nil {
+					continue
+				}
+
+				key := [2]string{
+					lookupAttribute(logGroup.SourceAttributes, logGroup.Source, lr.Attributes(), resource.Attributes()),
+					lookupAttribute(logGroup.FilenameAttributes, logGroup.Filename, lr.Attributes(), resource.Attributes()),
+				}
+				group, ok := groupIndex[key]
+				if !ok {
+					group = &cls.LogGroup{}
+					if key[0] != "" {
+						group.Source = proto.String(key[0])
+					}
+					if key[1] != "" {
+						group.Filename = proto.String(key[1])
+					}
+					groupIndex[key] = group
+					logGroups = append(logGroups, group)
 				}
+				group.Logs = append(group.Logs, clsLog)
 			}
 		}
 	}
 
-	return clsLogs
+	return logGroups
+}
+
+// lookupAttribute returns the value of the first of the names found in the record
+// attributes, then in the resource attributes, or def when none is found.
+func lookupAttribute(names []string, def string, attrs, resourceAttrs pcommon.Map) string {
+	for _, m := range []pcommon.Map{attrs, resourceAttrs} {
+		for _, name := range names {
+			if v, ok := m.Get(name); ok && v.AsString() != "" {
+				return v.AsString()
+			}
+		}
+	}
+	return def
+}
+
+// logTime returns the time of the first of the time attributes holding a valid time,
+// falling back to the timestamp, the observed timestamp and finally the current time.
+func logTime(lr plog.LogRecord, timeAttributes []string) time.Time {
+	for _, name := range timeAttributes {
+		if v, ok := lr.Attributes().Get(name); ok {
+			if t, ok := parseTimeValue(v); ok {
+				return t
+			}
+		}
+	}
+	if lr.Timestamp() > 0 {
+		return lr.Timestamp().AsTime()
+	}
+	if lr.ObservedTimestamp() > 0 {
+		return lr.ObservedTimestamp().AsTime()
+	}
+	return time.Now()
+}
+
+func parseTimeValue(v pcommon.Value) (time.Time, bool) {
+	switch v.Type() {
+	case pcommon.ValueTypeStr:
+		if t, err := time.Parse(time.RFC3339Nano, v.Str()); err == nil {
+			return t, true
+		}
+		if i, err := strconv.ParseInt(v.Str(), 10, 64); err == nil && i > 0 {
+			return unixTime(i), true
+		}
+	case pcommon.ValueTypeInt:
+		if v.Int() > 0 {
+			return unixTime(v.Int()), true
+		}
+	case pcommon.ValueTypeDouble:
+		if f := v.Double(); f > 0 && f < 1e11 {
+			// fractional seconds
+			return time.Unix(0, int64(f*1e9)), true
+		} else if f >= 1e11 && f < math.MaxInt64 {
+			return unixTime(int64(f)), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// unixTime converts a Unix time whose unit is guessed from its magnitude, seconds
+// being assumed up to year 5138.
+func unixTime(v int64) time.Time {
+	switch {
+	case v < 1e11:
+		return time.Unix(v, 0)
+	case v < 1e14:
+		return time.UnixMilli(v)
+	case v < 1e17:
+		return time.UnixMicro(v)
+	default:
+		return time.Unix(0, v)
+	}
 }
 
-func resourceToLogContents(resource pcommon.Resource) []*cls.Log_Content {
+func resourceToLogContents(resource pcommon.Resource, promoted map[string]string) []*cls.Log_Content {
 	attrs := resource.Attributes()
 
 	var hostname, serviceName string
@@ -88,6 +200,9 @@ func resourceToLogContents(resource pcommon.Resource) []*cls.Log_Content {
 		if k == conventions.AttributeServiceName || k == conventions.AttributeHostName {
 			return true
 		}
+		if _, ok := promoted[k]; ok {
+			return true
+		}
 		fields[k] = v.AsString()
 		return true
 	})
@@ -112,6 +227,83 @@ func resourceToLogContents(resource pcommon.Resource) []*cls.Log_Content {
 	}
 }
 
+// promotedAttributesToLogContents returns the attributes configured to be promoted
+// to top level LogService keys, sorted by key.
+func promotedAttributesToLogContents(attrs pcommon.Map, promoted map[string]string) []*cls.Log_Content {
+	contents := make([]*cls.Log_Content, 0, len(promoted))
+	for name, key := range promoted {
+		v, ok := attrs.Get(name)
+		if !ok {
+			continue
+		}
+		if key == "" {
+			key = name
+		}
+		contents = append(contents, &cls.Log_Content{
+			Key:   proto.String(key),
+			Value: proto.String(v.AsString()),
+		})
+	}
+	sort.Slice(contents, func(i, j int) bool {
+		return contents[i].GetKey() < contents[j].GetKey()
+	})
+	return contents
+}
+
+// flattenBody returns the fields of the body as "content.<path>" keys. It returns nil
+// if the body is neither a map nor a string holding a non empty JSON object.
+func flattenBody(body pcommon.Value, maxDepth int) []*cls.Log_Content {
+	var fields map[string]interface{}
+	switch body.Type() {
+	case pcommon.ValueTypeMap:
+		fields = body.Map().AsRaw()
+	case pcommon.ValueTypeStr:
+		if err := json.Unmarshal([]byte(body.Str()), &fields); err != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var contents []*cls.Log_Content
+	flattenFields(clsLogContent, fields, 1, maxDepth, &contents)
+	return contents
+}
+
+func flattenFields(prefix string, fields map[string]interface{}, depth int, maxDepth int, contents *[]*cls.Log_Content) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := prefix + "." + k
+		if nested, ok := fields[k].(map[string]interface{}); ok && (maxDepth == 0 || depth < maxDepth) {
+			flattenFields(key, nested, depth+1, maxDepth, contents)
+			continue
+		}
+		*contents = append(*contents, &cls.Log_Content{
+			Key:   proto.String(key),
+			Value: proto.String(fieldValueToString(fields[k])),
+		})
+	}
+}
+
+func fieldValueToString(v interface{}) string {
+	if str, ok := v.(string); ok {
+		return str
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(buf)
+}
+
 func instrumentationLibraryToLogContents(scope pcommon.InstrumentationScope) []*cls.Log_Content {
 	return []*cls.Log_Content{
 		{
@@ -127,7 +319,10 @@ func instrumentationLibraryToLogContents(scope pcommon.InstrumentationScope) []*
 
 func mapLogRecordToLogService(lr plog.LogRecord,
 	resourceContents,
-	instrumentationLibraryContents []*cls.Log_Content) *cls.Log {
+	promotedResourceContents,
+	instrumentationLibraryContents []*cls.Log_Content,
+	mapping MappingSettings,
+	timeAttributes []string) *cls.Log {
 	if lr.Body().Type() == pcommon.ValueTypeEmpty {
 		return nil
 	}
@@ -139,6 +334,9 @@ func mapLogRecordToLogService(lr plog.LogRecord,
 
 	fields := map[string]interface{}{}
 	lr.Attributes().Range(func(k string, v pcommon.Value) bool {
+		if _, ok := mapping.Attributes[k]; ok {
+			return true
+		}
 		fields[k] = v.AsString()
 		return true
 	})
@@ -147,6 +345,11 @@ func mapLogRecordToLogService(lr plog.LogRecord,
 		return nil
 	}
 
+	var bodyContents []*cls.Log_Content
+	if mapping.FlattenBody {
+		bodyContents = flattenBody(lr.Body(), mapping.MaxDepth)
+	}
+
 	contentsBuffer := []*cls.Log_Content{
 		{
 			Key:   proto.String(clsLogTimeUnixNano),
@@ -164,10 +367,14 @@ func mapLogRecordToLogService(lr plog.LogRecord,
 			Key:   proto.String(clsLogAttribute),
 			Value: proto.String(string(attributeBuffer)),
 		},
-		{
+	}
+	if bodyContents == nil {
+		contentsBuffer = append(contentsBuffer, &cls.Log_Content{
 			Key:   proto.String(clsLogContent),
 			Value: proto.String(lr.Body().AsString()),
-		},
+		})
+	}
+	contentsBuffer = append(contentsBuffer, []*cls.Log_Content{
 		{
 			Key:   proto.String(clsLogFlags),
 			Value: proto.String(strconv.FormatUint(uint64(lr.Flags()), 16)),
@@ -180,18 +387,30 @@ func mapLogRecordToLogService(lr plog.LogRecord,
 			Key:   proto.String(spanIDField),
 			Value: proto.String(traceutil.SpanIDToHexOrEmptyString(lr.SpanID())),
 		},
-	}
+	}...)
 
 	clsLog.Contents = append(clsLog.Contents, resourceContents...)
 	clsLog.Contents = append(clsLog.Contents, instrumentationLibraryContents...)
 	clsLog.Contents = append(clsLog.Contents, contentsBuffer...)
 
-	if lr.Timestamp() > 0 {
-		// convert time nano to time seconds
-		clsLog.Time = proto.Int64(int64(lr.Timestamp() / 1000000000))
-	} else {
-		clsLog.Time = proto.Int64(time.Now().Unix())
+	// the promoted attributes and flattened body keys are the only ones dropped to honor
+	// max_fields, the core keys are always kept.
+	extraContents := make([]*cls.Log_Content, 0, len(promotedResourceContents)+len(mapping.Attributes)+len(bodyContents))
+	extraContents = append(extraContents, promotedResourceContents...)
+	extraContents = append(extraContents, promotedAttributesToLogContents(lr.Attributes(), mapping.Attributes)...)
+	extraContents = append(extraContents, bodyContents...)
+	if mapping.MaxFields > 0 {
+		limit := mapping.MaxFields - len(clsLog.Contents)
+		if limit < 0 {
+			limit = 0
+		}
+		if len(extraContents) > limit {
+			extraContents = extraContents[:limit]
+		}
 	}
+	clsLog.Contents = append(clsLog.Contents, extraContents...)
+
+	clsLog.Time = proto.Int64(logTime(lr, timeAttributes).Unix())
 
 	return &clsLog
 }
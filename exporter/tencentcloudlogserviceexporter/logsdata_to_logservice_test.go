@@ -19,13 +19,17 @@ import (
 	"os"
 	"reflect"
 	"sort"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+
+	cls "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/tencentcloudlogserviceexporter/proto"
 )
 
 type logKeyValuePair struct {
@@ -99,7 +103,11 @@ func createLogData(numberOfLogs int) plog.Logs {
 func TestConvertLogs(t *testing.T) {
 	totalLogCount := 10
 	validLogCount := totalLogCount - 1
-	gotLogs := convertLogs(createLogData(10))
+	gotLogGroups := convertLogs(createLogData(10), MappingSettings{}, LogGroupSettings{})
+	require.Len(t, gotLogGroups, 1)
+	assert.Nil(t, gotLogGroups[0].Source)
+	assert.Nil(t, gotLogGroups[0].Filename)
+	gotLogs := gotLogGroups[0].Logs
 	assert.Equal(t, len(gotLogs), 9)
 
 	gotLogPairs := make([][]logKeyValuePair, 0, len(gotLogs))
@@ -140,3 +148,232 @@ func loadFromJSON(file string, obj interface{}) error {
 
 	return err
 }
+
+func TestConvertLogsWithMapping(t *testing.T) {
+	newLogs := func(fillBody func(pcommon.Value)) plog.Logs {
+		logs := plog.NewLogs()
+		rl := logs.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("k8s.pod.name", "checkout-1")
+		rl.Resource().Attributes().PutStr("cloud.region", "ap-beijing")
+		lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		lr.Attributes().PutStr("http.method", "GET")
+		lr.Attributes().PutStr("user.id", "42")
+		fillBody(lr.Body())
+		return logs
+	}
+	contentsOf := func(log *cls.Log) map[string]string {
+		out := map[string]string{}
+		for _, content := range log.Contents {
+			out[content.GetKey()] = content.GetValue()
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		mapping  MappingSettings
+		body     func(pcommon.Value)
+		expected map[string]string
+		missing  []string
+		length   int
+	}{
+		{
+			name: "promoted attributes",
+			mapping: MappingSettings{
+				Attributes:         map[string]string{"http.method": "method"},
+				ResourceAttributes: map[string]string{"k8s.pod.name": ""},
+			},
+			body: func(v pcommon.Value) { v.SetStr("hello") },
+			expected: map[string]string{
+				"method":       "GET",
+				"k8s.pod.name": "checkout-1",
+				"attribute":    `{"user.id":"42"}`,
+				"resource":     `{"cloud.region":"ap-beijing"}`,
+				"content":      "hello",
+			},
+		},
+		{
+			name:    "flatten map body",
+			mapping: MappingSettings{FlattenBody: true},
+			body: func(v pcommon.Value) {
+				m := v.SetEmptyMap()
+				m.PutStr("msg", "hello")
+				m.PutEmptyMap("http").PutEmptyMap("request").PutInt("size", 12)
+			},
+			expected: map[string]string{
+				"content.msg":               "hello",
+				"content.http.request.size": "12",
+			},
+			missing: []string{clsLogContent},
+		},
+		{
+			name:    "flatten JSON string body with max depth",
+			mapping: MappingSettings{FlattenBody: true, MaxDepth: 1},
+			body: func(v pcommon.Value) {
+				v.SetStr(`{"msg":"hello","http":{"status":200}}`)
+			},
+			expected: map[string]string{
+				"content.msg":  "hello",
+				"content.http": `{"status":200}`,
+			},
+			missing: []string{clsLogContent},
+		},
+		{
+			name:    "flatten non JSON string body",
+			mapping: MappingSettings{FlattenBody: true},
+			body:    func(v pcommon.Value) { v.SetStr("hello") },
+			expected: map[string]string{
+				"content": "hello",
+			},
+		},
+		{
+			name:    "max fields",
+			mapping: MappingSettings{FlattenBody: true, MaxFields: 13},
+			body: func(v pcommon.Value) {
+				m := v.SetEmptyMap()
+				m.PutStr("a", "1")
+				m.PutStr("b", "2")
+				m.PutStr("c", "3")
+			},
+			expected: map[string]string{
+				"content.a": "1",
+			},
+			missing: []string{"content.b", "content.c"},
+			length:  13,
+		},
+		{
+			name: "max fields keeps core keys",
+			mapping: MappingSettings{
+				Attributes: map[string]string{"http.method": "method"},
+				MaxFields:  5,
+			},
+			body: func(v pcommon.Value) { v.SetStr("hello") },
+			expected: map[string]string{
+				"attribute": `{"user.id":"42"}`,
+				"content":   "hello",
+				"spanID":    "",
+			},
+			missing: []string{"method"},
+			length:  13,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLogGroups := convertLogs(newLogs(tt.body), tt.mapping, LogGroupSettings{})
+			require.Len(t, gotLogGroups, 1)
+			gotLogs := gotLogGroups[0].Logs
+			require.Len(t, gotLogs, 1)
+
+			contents := contentsOf(gotLogs[0])
+			for k, v := range tt.expected {
+				assert.Equal(t, v, contents[k], k)
+			}
+			for _, k := range tt.missing {
+				assert.NotContains(t, contents, k)
+			}
+			if tt.length > 0 {
+				assert.Len(t, gotLogs[0].Contents, tt.length)
+			}
+		})
+	}
+}
+
+func TestConvertLogsWithLogGroup(t *testing.T) {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("host.ip", "10.0.0.1")
+	lrs := rl.ScopeLogs().AppendEmpty().LogRecords()
+	for _, file := range []string{"/var/log/a.log", "/var/log/b.log", "/var/log/a.log", ""} {
+		lr := lrs.AppendEmpty()
+		lr.Body().SetStr("hello")
+		if file != "" {
+			lr.Attributes().PutStr("log.file.path", file)
+		}
+	}
+	// the record attribute takes precedence over the resource attribute
+	lrs.At(1).Attributes().PutStr("host.ip", "10.0.0.2")
+
+	logGroups := convertLogs(logs, MappingSettings{}, LogGroupSettings{
+		SourceAttributes:   []string{"host.ip"},
+		FilenameAttributes: []string{"log.file.path"},
+		Filename:           "unknown",
+	})
+	require.Len(t, logGroups, 3)
+	assert.Equal(t, "10.0.0.1", logGroups[0].GetSource())
+	assert.Equal(t, "/var/log/a.log", logGroups[0].GetFilename())
+	assert.Len(t, logGroups[0].Logs, 2)
+	assert.Equal(t, "10.0.0.2", logGroups[1].GetSource())
+	assert.Equal(t, "/var/log/b.log", logGroups[1].GetFilename())
+	assert.Len(t, logGroups[1].Logs, 1)
+	assert.Equal(t, "10.0.0.1", logGroups[2].GetSource())
+	assert.Equal(t, "unknown", logGroups[2].GetFilename())
+	assert.Len(t, logGroups[2].Logs, 1)
+}
+
+func TestLogTime(t *testing.T) {
+	expected := time.Date(2022, 11, 20, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		fill     func(lr plog.LogRecord)
+		expected time.Time
+	}{
+		{
+			name: "RFC3339 attribute",
+			fill: func(lr plog.LogRecord) {
+				lr.Attributes().PutStr("time", "2022-11-20T10:30:15Z")
+				lr.SetTimestamp(pcommon.NewTimestampFromTime(expected.Add(time.Hour)))
+			},
+			expected: expected,
+		},
+		{
+			name: "Unix seconds attribute",
+			fill: func(lr plog.LogRecord) {
+				lr.Attributes().PutInt("time", expected.Unix())
+			},
+			expected: expected,
+		},
+		{
+			name: "Unix milliseconds attribute",
+			fill: func(lr plog.LogRecord) {
+				lr.Attributes().PutStr("time", strconv.FormatInt(expected.UnixMilli(), 10))
+			},
+			expected: expected,
+		},
+		{
+			name: "Unix fractional seconds attribute",
+			fill: func(lr plog.LogRecord) {
+				lr.Attributes().PutDouble("time", float64(expected.Unix())+0.5)
+			},
+			expected: expected,
+		},
+		{
+			name: "invalid attribute falls back to the timestamp",
+			fill: func(lr plog.LogRecord) {
+				lr.Attributes().PutStr("time", "yesterday")
+				lr.SetTimestamp(pcommon.NewTimestampFromTime(expected))
+				lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(expected.Add(time.Hour)))
+			},
+			expected: expected,
+		},
+		{
+			name: "observed timestamp",
+			fill: func(lr plog.LogRecord) {
+				lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(expected))
+			},
+			expected: expected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lr := plog.NewLogRecord()
+			tt.fill(lr)
+			assert.Equal(t, tt.expected.Unix(), logTime(lr, []string{"time"}).Unix())
+		})
+	}
+
+	// the current time is used when the record has no time at all
+	assert.WithinDuration(t, time.Now(), logTime(plog.NewLogRecord(), []string{"time"}), time.Minute)
+}
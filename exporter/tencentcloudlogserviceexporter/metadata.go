@@ -0,0 +1,76 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tencentcloudlogserviceexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/tencentcloudlogserviceexporter"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// defaultMetadataEndpoint is the base URL of the instance metadata of the CVM instances,
+// https://cloud.tencent.com/document/product/213/4934
+const defaultMetadataEndpoint = "http://metadata.tencentyun.com/latest/meta-data"
+
+// discoverRegion returns the region of the CVM instance the collector runs on, such as ap-beijing.
+func discoverRegion(ctx context.Context, settings AutoDiscoverySettings) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, settings.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(settings.MetadataEndpoint, "/")+"/placement/region", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query the instance metadata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", fmt.Errorf("failed to read the instance metadata: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("instance metadata returned HTTP %d %q", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	region := strings.TrimSpace(string(body))
+	if region == "" {
+		return "", fmt.Errorf("instance metadata returned an empty region")
+	}
+	return region, nil
+}
+
+// resolveConfig returns the configuration with the region discovered from the instance metadata
+// when it isn't set, and with the LogSet of the region when the LogSet isn't set.
+func resolveConfig(ctx context.Context, cfg *Config) (*Config, error) {
+	resolved := *cfg
+	if resolved.Region == "" {
+		region, err := discoverRegion(ctx, cfg.AutoDiscovery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover the region: %w", err)
+		}
+		resolved.Region = region
+	}
+	if resolved.LogSet == "" {
+		resolved.LogSet = cfg.AutoDiscovery.LogSets[resolved.Region]
+		if resolved.LogSet == "" {
+			return nil, fmt.Errorf("auto_discovery.logsets has no LogSet for the region %q", resolved.Region)
+		}
+	}
+	return &resolved, nil
+}
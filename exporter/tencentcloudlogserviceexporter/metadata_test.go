@@ -0,0 +1,110 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tencentcloudlogserviceexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func newMetadataServer(t *testing.T, status int, region string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/latest/meta-data/placement/region" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(region))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResolveConfig(t *testing.T) {
+	server := newMetadataServer(t, http.StatusOK, "ap-shanghai\n")
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Topic = "demo-topic"
+	cfg.AutoDiscovery.MetadataEndpoint = server.URL + "/latest/meta-data/"
+	cfg.AutoDiscovery.LogSets = map[string]string{
+		"ap-beijing":  "beijing-logset",
+		"ap-shanghai": "shanghai-logset",
+	}
+	require.NoError(t, cfg.Validate())
+
+	resolved, err := resolveConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "ap-shanghai", resolved.Region)
+	assert.Equal(t, "shanghai-logset", resolved.LogSet)
+	assert.Empty(t, cfg.Region, "the configuration isn't modified")
+
+	// a configured LogSet takes precedence
+	cfg.LogSet = "demo-logset"
+	resolved, err = resolveConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "demo-logset", resolved.LogSet)
+
+	// a configured region isn't discovered
+	cfg.Region = "ap-beijing"
+	cfg.LogSet = ""
+	cfg.AutoDiscovery.MetadataEndpoint = "http://invalid.invalid"
+	resolved, err = resolveConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "beijing-logset", resolved.LogSet)
+}
+
+func TestResolveConfig_errors(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Topic = "demo-topic"
+	cfg.AutoDiscovery.LogSets = map[string]string{"ap-beijing": "beijing-logset"}
+
+	cfg.AutoDiscovery.MetadataEndpoint = newMetadataServer(t, http.StatusOK, "ap-guangzhou").URL + "/latest/meta-data"
+	_, err := resolveConfig(context.Background(), cfg)
+	assert.EqualError(t, err, `auto_discovery.logsets has no LogSet for the region "ap-guangzhou"`)
+
+	cfg.AutoDiscovery.MetadataEndpoint = newMetadataServer(t, http.StatusNotFound, "").URL + "/latest/meta-data"
+	_, err = resolveConfig(context.Background(), cfg)
+	assert.EqualError(t, err, `failed to discover the region: instance metadata returned HTTP 404 "Not Found"`)
+
+	cfg.AutoDiscovery.MetadataEndpoint = newMetadataServer(t, http.StatusOK, " ").URL + "/latest/meta-data"
+	_, err = resolveConfig(context.Background(), cfg)
+	assert.EqualError(t, err, "failed to discover the region: instance metadata returned an empty region")
+}
+
+func TestLogsExporterStart_discoversRegion(t *testing.T) {
+	server := newMetadataServer(t, http.StatusOK, "ap-beijing")
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Topic = "demo-topic"
+	cfg.AutoDiscovery.MetadataEndpoint = server.URL + "/latest/meta-data"
+	cfg.AutoDiscovery.LogSets = map[string]string{"ap-beijing": "beijing-logset"}
+
+	exp, err := newLogsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, exp.Shutdown(context.Background()))
+
+	cfg.AutoDiscovery.LogSets = map[string]string{"ap-shanghai": "shanghai-logset"}
+	exp, err = newLogsExporter(componenttest.NewNopExporterCreateSettings(), cfg)
+	require.NoError(t, err)
+	assert.EqualError(t, exp.Start(context.Background(), componenttest.NewNopHost()),
+		`auto_discovery.logsets has no LogSet for the region "ap-beijing"`)
+}
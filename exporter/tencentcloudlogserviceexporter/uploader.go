@@ -15,10 +15,16 @@
 package tencentcloudlogserviceexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/tencentcloudlogserviceexporter"
 
 import (
+	"context"
+	"errors"
+	"strings"
+
 	"github.com/pierrec/lz4"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
 	tchttp "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
 	"github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/profile"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.uber.org/zap"
 	pb "google.golang.org/protobuf/proto"
 
@@ -27,8 +33,8 @@ import (
 
 // logServiceClient log Service's client wrapper
 type logServiceClient interface {
-	// sendLogs send message to LogService
-	sendLogs(logs []*cls.Log) error
+	// sendLogGroups send message to LogService
+	sendLogGroups(ctx context.Context, logGroups []*cls.LogGroup) error
 }
 
 type logServiceClientImpl struct {
@@ -43,8 +49,13 @@ type logServiceClientImpl struct {
 func newLogServiceClient(config *Config, logger *zap.Logger) logServiceClient {
 	credential := common.NewCredential(config.SecretID, config.SecretKey)
 
+	// the failed uploads are retried by exporterhelper, according to retry_on_failure.
+	clientProfile := profile.NewClientProfile()
+	clientProfile.NetworkFailureMaxRetries = 0
+	clientProfile.RateLimitExceededMaxRetries = 0
+
 	c := &logServiceClientImpl{
-		clientInstance: common.NewCommonClient(credential, config.Region, profile.NewClientProfile()),
+		clientInstance: common.NewCommonClient(credential, config.Region, clientProfile),
 		logset:         config.LogSet,
 		topic:          config.Topic,
 		logger:         logger,
@@ -54,21 +65,16 @@ func newLogServiceClient(config *Config, logger *zap.Logger) logServiceClient {
 	return c
 }
 
-// sendLogs send message to LogService
-func (c *logServiceClientImpl) sendLogs(logs []*cls.Log) error {
+// sendLogGroups send message to LogService
+func (c *logServiceClientImpl) sendLogGroups(ctx context.Context, logGroups []*cls.LogGroup) error {
 	headers := map[string]string{
 		"X-CLS-TopicId": c.topic,
 		"X-CLS-HashKey": c.hashkey,
 	}
 	commpresstype := ""
 
-	logGroup := cls.LogGroup{
-		Logs: logs,
-	}
 	logGroupList := cls.LogGroupList{
-		LogGroupList: []*cls.LogGroup{
-			&logGroup,
-		},
+		LogGroupList: logGroups,
 	}
 	data, _ := pb.Marshal(&logGroupList)
 
@@ -83,8 +89,38 @@ func (c *logServiceClientImpl) sendLogs(logs []*cls.Log) error {
 
 	request := tchttp.NewCommonRequest("cls", "2020-10-16", "UploadLog")
 	request.SetOctetStreamParameters(headers, data)
+	request.SetContext(ctx)
 
 	response := tchttp.NewCommonResponse()
 
-	return c.clientInstance.SendOctetStream(request, response)
+	return classifyError(c.clientInstance.SendOctetStream(request, response))
+}
+
+// permanentErrorCodes are the prefixes of the codes of the LogService errors which can't be
+// fixed by retrying the upload, https://cloud.tencent.com/document/product/614/12402
+var permanentErrorCodes = []string{
+	"AuthFailure",
+	"ClientError.CredentialError",
+	"InvalidParameter",
+	"LimitExceeded.LogSize",
+	"MissingParameter",
+	"OperationDenied",
+	"ResourceNotFound",
+	"UnauthorizedOperation",
+	"UnsupportedOperation",
+}
+
+// classifyError marks the errors which can't be fixed by retrying the upload as permanent,
+// so that exporterhelper drops the logs instead of retrying them.
+func classifyError(err error) error {
+	var sdkErr *tcerr.TencentCloudSDKError
+	if !errors.As(err, &sdkErr) {
+		return err
+	}
+	for _, code := range permanentErrorCodes {
+		if strings.HasPrefix(sdkErr.Code, code) {
+			return consumererror.NewPermanent(err)
+		}
+	}
+	return err
 }
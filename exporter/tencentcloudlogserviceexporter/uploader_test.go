@@ -0,0 +1,49 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tencentcloudlogserviceexporter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tcerr "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/errors"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+func TestClassifyError(t *testing.T) {
+	assert.NoError(t, classifyError(nil))
+
+	for _, tt := range []struct {
+		err       error
+		permanent bool
+	}{
+		{err: errors.New("connection reset by peer")},
+		{err: tcerr.NewTencentCloudSDKError("ClientError.NetworkError", "timeout", "")},
+		{err: tcerr.NewTencentCloudSDKError("InternalError", "internal error", "id")},
+		{err: tcerr.NewTencentCloudSDKError("RequestLimitExceeded", "too many requests", "id")},
+		{err: tcerr.NewTencentCloudSDKError("AuthFailure.SignatureFailure", "invalid signature", "id"), permanent: true},
+		{err: tcerr.NewTencentCloudSDKError("ResourceNotFound.TopicNotExist", "topic not found", "id"), permanent: true},
+		{err: tcerr.NewTencentCloudSDKError("LimitExceeded.LogSize", "log too large", "id"), permanent: true},
+		{err: fmt.Errorf("upload: %w", tcerr.NewTencentCloudSDKError("InvalidParameter", "bad", "id")), permanent: true},
+	} {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			err := classifyError(tt.err)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.permanent, consumererror.IsPermanent(err))
+		})
+	}
+}
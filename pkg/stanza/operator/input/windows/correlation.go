@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package windows // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/windows"
+
+import (
+	"fmt"
+	"time"
+)
+
+// CorrelationConfig configures the merging of the events sharing an activity ID, e.g. the chain of
+// logon events 4624 and 4627, into a single entry.
+type CorrelationConfig struct {
+	// Enabled merges the correlated events.
+	Enabled bool `mapstructure:"enabled,omitempty"`
+	// Window is how long the events of an activity are collected after its first event, before they
+	// are emitted as a single entry.
+	Window time.Duration `mapstructure:"window,omitempty"`
+	// EventIDs restricts the correlation to the events with these IDs, the other events are emitted
+	// on their own. All the events with an activity ID are correlated by default.
+	EventIDs []uint32 `mapstructure:"event_ids,omitempty"`
+	// MaxPending is the maximum number of activities collected at the same time, the oldest one is
+	// emitted early when it is reached.
+	MaxPending int `mapstructure:"max_pending,omitempty"`
+}
+
+// build returns the correlator of the configuration, nil if the correlation isn't enabled.
+func (c CorrelationConfig) build() (*correlator, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	if c.Window <= 0 {
+		return nil, fmt.Errorf("the `correlation.window` field must be greater than zero")
+	}
+	if c.MaxPending < 1 {
+		return nil, fmt.Errorf("the `correlation.max_pending` field must be greater than zero")
+	}
+	var eventIDs map[uint32]bool
+	if len(c.EventIDs) > 0 {
+		eventIDs = make(map[uint32]bool, len(c.EventIDs))
+		for _, id := range c.EventIDs {
+			eventIDs[id] = true
+		}
+	}
+	return &correlator{
+		window:     c.Window,
+		eventIDs:   eventIDs,
+		maxPending: c.MaxPending,
+		activities: map[string]*activity{},
+	}, nil
+}
+
+// correlator collects the events of the activities until their window elapses. It is only used by
+// the goroutine reading the events.
+type correlator struct {
+	window     time.Duration
+	eventIDs   map[uint32]bool
+	maxPending int
+
+	activities map[string]*activity
+	// order holds the activity IDs by first event, which is also the order their windows elapse in.
+	order []string
+}
+
+// activity holds the events of an activity collected until the window elapses.
+type activity struct {
+	events  []EventXML
+	expires time.Time
+}
+
+// add collects the event with the events of its activity, it returns false if the event isn't
+// correlated and must be emitted on its own. The events of the oldest activities are returned
+// when more than max_pending activities are collected.
+func (c *correlator) add(event EventXML, now time.Time) (bool, [][]EventXML) {
+	activityID := event.Correlation.ActivityID
+	if activityID == "" || (c.eventIDs != nil && !c.eventIDs[event.EventID.ID]) {
+		return false, nil
+	}
+	if a, ok := c.activities[activityID]; ok {
+		a.events = append(a.events, event)
+		return true, nil
+	}
+	c.activities[activityID] = &activity{events: []EventXML{event}, expires: now.Add(c.window)}
+	c.order = append(c.order, activityID)
+
+	var evicted [][]EventXML
+	for len(c.order) > c.maxPending {
+		evicted = append(evicted, c.pop())
+	}
+	return true, evicted
+}
+
+// expired returns the events of the activities whose window elapsed.
+func (c *correlator) expired(now time.Time) [][]EventXML {
+	var expired [][]EventXML
+	for len(c.order) > 0 && !now.Before(c.activities[c.order[0]].expires) {
+		expired = append(expired, c.pop())
+	}
+	return expired
+}
+
+// drain returns the events of all the activities.
+func (c *correlator) drain() [][]EventXML {
+	drained := make([][]EventXML, 0, len(c.order))
+	for len(c.order) > 0 {
+		drained = append(drained, c.pop())
+	}
+	return drained
+}
+
+// pop removes the oldest activity and returns its events.
+func (c *correlator) pop() []EventXML {
+	activityID := c.order[0]
+	c.order = c.order[1:]
+	events := c.activities[activityID].events
+	delete(c.activities, activityID)
+	return events
+}
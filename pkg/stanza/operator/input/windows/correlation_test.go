@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package windows
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func correlatedEvent(id uint32, activityID string) EventXML {
+	return EventXML{EventID: EventID{ID: id}, Correlation: Correlation{ActivityID: activityID}}
+}
+
+func eventIDs(events []EventXML) []uint32 {
+	ids := make([]uint32, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.EventID.ID)
+	}
+	return ids
+}
+
+func TestCorrelationConfigBuild(t *testing.T) {
+	c, err := CorrelationConfig{Window: time.Second, MaxPending: 10}.build()
+	require.NoError(t, err)
+	require.Nil(t, c, "the correlation is disabled by default")
+
+	_, err = CorrelationConfig{Enabled: true, MaxPending: 10}.build()
+	require.EqualError(t, err, "the `correlation.window` field must be greater than zero")
+
+	_, err = CorrelationConfig{Enabled: true, Window: time.Second}.build()
+	require.EqualError(t, err, "the `correlation.max_pending` field must be greater than zero")
+}
+
+func TestCorrelator(t *testing.T) {
+	c, err := CorrelationConfig{Enabled: true, Window: 5 * time.Second, MaxPending: 2, EventIDs: []uint32{4624, 4627, 4672}}.build()
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+
+	// the events without activity ID or with another event ID aren't correlated
+	correlated, _ := c.add(correlatedEvent(4624, ""), now)
+	require.False(t, correlated)
+	correlated, _ = c.add(correlatedEvent(4634, "{A}"), now)
+	require.False(t, correlated)
+
+	for _, event := range []EventXML{correlatedEvent(4624, "{A}"), correlatedEvent(4627, "{A}"), correlatedEvent(4624, "{B}")} {
+		correlated, evicted := c.add(event, now)
+		require.True(t, correlated)
+		require.Empty(t, evicted)
+	}
+	correlated, _ = c.add(correlatedEvent(4672, "{A}"), now.Add(time.Second))
+	require.True(t, correlated)
+
+	require.Empty(t, c.expired(now.Add(4*time.Second)))
+	expired := c.expired(now.Add(5 * time.Second))
+	require.Len(t, expired, 2)
+	require.Equal(t, []uint32{4624, 4627, 4672}, eventIDs(expired[0]))
+	require.Equal(t, []uint32{4624}, eventIDs(expired[1]))
+
+	// the oldest activity is emitted early when max_pending is reached
+	c.add(correlatedEvent(4624, "{C}"), now)
+	c.add(correlatedEvent(4624, "{D}"), now)
+	_, evicted := c.add(correlatedEvent(4627, "{E}"), now)
+	require.Len(t, evicted, 1)
+	require.Equal(t, "{C}", evicted[0][0].Correlation.ActivityID)
+
+	drained := c.drain()
+	require.Len(t, drained, 2)
+	require.Empty(t, c.activities)
+}
+
+func TestInputSendCorrelatedEvents(t *testing.T) {
+	cfg := NewConfigWithID("test")
+	cfg.Channel = "Security"
+	cfg.OutputIDs = []string{"fake"}
+	cfg.Correlation.Enabled = true
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+	input := op.(*Input)
+
+	logon := correlatedEvent(4624, "{A}")
+	logon.Level = "4"
+	logon.TimeCreated.SystemTime = "2022-04-22T10:20:52.3778625Z"
+	failure := correlatedEvent(4627, "{A}")
+	failure.Level = "2"
+	input.sendEvent(context.Background(), logon)
+	input.sendEvent(context.Background(), failure)
+	input.sendEvent(context.Background(), correlatedEvent(4688, ""))
+
+	// the uncorrelated event is emitted right away
+	uncorrelated := <-fake.Received
+	require.Equal(t, uint32(4688), uncorrelated.Body.(map[string]interface{})["event_id"].(map[string]interface{})["id"])
+
+	for _, events := range input.correlator.drain() {
+		input.sendEvents(context.Background(), events)
+	}
+	merged := <-fake.Received
+	body := merged.Body.(map[string]interface{})
+	require.Equal(t, uint32(4624), body["event_id"].(map[string]interface{})["id"])
+	require.Equal(t, map[string]interface{}{"activity_id": "{A}", "related_activity_id": ""}, body["correlation"])
+	correlatedEvents := body["correlated_events"].([]interface{})
+	require.Len(t, correlatedEvents, 1)
+	require.Equal(t, uint32(4627), correlatedEvents[0].(map[string]interface{})["event_id"].(map[string]interface{})["id"])
+	require.Equal(t, entry.Error, merged.Severity, "the highest severity of the events applies")
+	require.Equal(t, time.Date(2022, 4, 22, 10, 20, 52, 377862500, time.UTC), merged.Timestamp)
+}
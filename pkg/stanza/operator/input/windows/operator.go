@@ -31,6 +31,9 @@ import (
 
 const operatorType = "windows_eventlog_input"
 
+// hostNameResourceKey is the resource attribute set to the source computer of the events.
+const hostNameResourceKey = "host.name"
+
 func init() {
 	operator.Register(operatorType, func() operator.Builder { return NewConfig() })
 }
@@ -43,10 +46,15 @@ func NewConfig() *Config {
 // NewConfig will return an event log config with default values.
 func NewConfigWithID(operatorID string) *Config {
 	return &Config{
-		InputConfig:  helper.NewInputConfig(operatorID, operatorType),
-		MaxReads:     100,
-		StartAt:      "end",
-		PollInterval: 1 * time.Second,
+		InputConfig:   helper.NewInputConfig(operatorID, operatorType),
+		MaxReads:      100,
+		StartAt:       "end",
+		PollInterval:  1 * time.Second,
+		RenderWorkers: 1,
+		Correlation: CorrelationConfig{
+			Window:     5 * time.Second,
+			MaxPending: 1000,
+		},
 	}
 }
 
@@ -57,6 +65,22 @@ type Config struct {
 	MaxReads           int           `mapstructure:"max_reads,omitempty"`
 	StartAt            string        `mapstructure:"start_at,omitempty"`
 	PollInterval       time.Duration `mapstructure:"poll_interval,omitempty"`
+	// RenderWorkers is the number of events of a batch rendered in parallel. Raising it helps
+	// keeping up with high volume channels such as ForwardedEvents.
+	RenderWorkers int `mapstructure:"render_workers,omitempty"`
+	// SourceComputerResource sets the host.name resource attribute to the computer the event
+	// originates from, which differs from the local host for forwarded events.
+	SourceComputerResource bool `mapstructure:"source_computer_resource,omitempty"`
+	// Severity customizes the mapping of the levels and keywords of the events to severities.
+	Severity SeverityConfig `mapstructure:"severity,omitempty"`
+	// BatchSize is the number of events of a read rendered and emitted at a time, it defaults to
+	// MaxReads. The next batch is only rendered once the pipeline accepted the previous one.
+	BatchSize int `mapstructure:"batch_size,omitempty"`
+	// MaxEventsPerPoll is the maximum number of events read on each poll, the remaining events are
+	// read on the next polls. There is no limit by default.
+	MaxEventsPerPoll int `mapstructure:"max_events_per_poll,omitempty"`
+	// Correlation merges the events sharing an activity ID within a window into a single entry.
+	Correlation CorrelationConfig `mapstructure:"correlation,omitempty"`
 }
 
 // Build will build a windows event log operator.
@@ -78,13 +102,52 @@ func (c *Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		return nil, fmt.Errorf("the `start_at` field must be set to `beginning` or `end`")
 	}
 
+	if c.RenderWorkers < 1 {
+		return nil, fmt.Errorf("the `render_workers` field must be greater than zero")
+	}
+
+	if c.BatchSize < 0 {
+		return nil, fmt.Errorf("the `batch_size` field must not be negative")
+	}
+
+	if c.MaxEventsPerPoll < 0 {
+		return nil, fmt.Errorf("the `max_events_per_poll` field must not be negative")
+	}
+
+	batchSize := c.BatchSize
+	if batchSize == 0 || batchSize > c.MaxReads {
+		batchSize = c.MaxReads
+	}
+
+	severity, err := c.Severity.build(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	correlator, err := c.Correlation.build()
+	if err != nil {
+		return nil, err
+	}
+
+	// each worker renders events in its own buffer
+	buffers := make([]Buffer, c.RenderWorkers)
+	for i := range buffers {
+		buffers[i] = NewBuffer()
+	}
+
 	return &Input{
-		InputOperator: inputOperator,
-		buffer:        NewBuffer(),
-		channel:       c.Channel,
-		maxReads:      c.MaxReads,
-		startAt:       c.StartAt,
-		pollInterval:  c.PollInterval,
+		InputOperator:          inputOperator,
+		buffer:                 buffers[0],
+		renderBuffers:          buffers,
+		channel:                c.Channel,
+		maxReads:               c.MaxReads,
+		startAt:                c.StartAt,
+		pollInterval:           c.PollInterval,
+		sourceComputerResource: c.SourceComputerResource,
+		severity:               severity,
+		batchSize:              batchSize,
+		maxEventsPerPoll:       c.MaxEventsPerPoll,
+		correlator:             correlator,
 	}, nil
 }
 
@@ -101,6 +164,14 @@ type Input struct {
 	persister    operator.Persister
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
+
+	renderBuffers          []Buffer
+	publishers             *publisherCache
+	sourceComputerResource bool
+	severity               *severityMapper
+	batchSize              int
+	maxEventsPerPoll       int
+	correlator             *correlator
 }
 
 // Start will start reading events from a subscription.
@@ -123,6 +194,8 @@ func (e *Input) Start(persister operator.Persister) error {
 		}
 	}
 
+	e.publishers = newPublisherCache()
+
 	e.subscription = NewSubscription()
 	if err := e.subscription.Open(e.channel, e.startAt, e.bookmark); err != nil {
 		return fmt.Errorf("failed to open subscription: %w", err)
@@ -138,6 +211,13 @@ func (e *Input) Stop() error {
 	e.cancel()
 	e.wg.Wait()
 
+	if e.correlator != nil {
+		// the events are emitted even though they were collected for less than the window
+		for _, events := range e.correlator.drain() {
+			e.sendEvents(context.Background(), events)
+		}
+	}
+
 	if err := e.subscription.Close(); err != nil {
 		return fmt.Errorf("failed to close subscription: %w", err)
 	}
@@ -146,6 +226,10 @@ func (e *Input) Stop() error {
 		return fmt.Errorf("failed to close bookmark: %w", err)
 	}
 
+	if err := e.publishers.Close(); err != nil {
+		return fmt.Errorf("failed to close publishers: %w", err)
+	}
+
 	return nil
 }
 
@@ -162,72 +246,190 @@ func (e *Input) readOnInterval(ctx context.Context) {
 			return
 		case <-ticker.C:
 			e.readToEnd(ctx)
+			if e.correlator != nil {
+				for _, events := range e.correlator.expired(time.Now()) {
+					e.sendEvents(ctx, events)
+				}
+			}
 		}
 	}
 }
 
-// readToEnd will read events from the subscription until it reaches the end of the channel.
+// readToEnd will read events from the subscription until it reaches the end of the channel,
+// or until max_events_per_poll events were read.
 func (e *Input) readToEnd(ctx context.Context) {
+	read := 0
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			if count := e.read(ctx); count == 0 {
+			maxReads := e.readsLeft(read)
+			if maxReads == 0 {
+				e.Debugf("Read %d events, the remaining events are read on the next poll", read)
 				return
 			}
+			count := e.read(ctx, maxReads)
+			if count == 0 {
+				return
+			}
+			read += count
 		}
 	}
 }
 
-// read will read events from the subscription.
-func (e *Input) read(ctx context.Context) int {
-	events, err := e.subscription.Read(e.maxReads)
+// readsLeft returns the number of events to read next, given the number of events already read
+// on this poll.
+func (e *Input) readsLeft(read int) int {
+	if e.maxEventsPerPoll == 0 || e.maxEventsPerPoll-read >= e.maxReads {
+		return e.maxReads
+	}
+	if read >= e.maxEventsPerPoll {
+		return 0
+	}
+	return e.maxEventsPerPoll - read
+}
+
+// read will read up to maxReads events from the subscription.
+func (e *Input) read(ctx context.Context, maxReads int) int {
+	events, err := e.subscription.Read(maxReads)
 	if err != nil {
 		e.Errorf("Failed to read events from subscription: %s", err)
 		return 0
 	}
 
-	for i, event := range events {
-		e.processEvent(ctx, event)
-		if len(events) == i+1 {
-			e.updateBookmarkOffset(ctx, event)
+	// the events are sent in order by batches of batch_size once the whole batch is rendered,
+	// so at most batch_size rendered events are held in memory. Sending blocks while the
+	// pipeline is busy, so the next batch is only rendered once the previous one was accepted.
+	for start := 0; start < len(events); start += e.batchSize {
+		end := start + e.batchSize
+		if end > len(events) {
+			end = len(events)
+		}
+		batch := events[start:end]
+
+		if ctx.Err() != nil {
+			// the remaining events are read again from the bookmark on restart
+			for _, event := range events[start:] {
+				event.Close()
+			}
+			break
+		}
+
+		rendered := e.renderEvents(batch)
+		for i := range batch {
+			if rendered[i] != nil {
+				e.sendEvent(ctx, *rendered[i])
+			}
+		}
+		e.updateBookmarkOffset(ctx, batch[len(batch)-1])
+		for _, event := range batch {
+			event.Close()
 		}
-		event.Close()
 	}
 
 	return len(events)
 }
 
-// processEvent will process and send an event retrieved from windows event log.
-func (e *Input) processEvent(ctx context.Context, event Event) {
-	simpleEvent, err := event.RenderSimple(e.buffer)
+// renderEvents will render the events, in parallel if several render workers are configured.
+// The returned slice holds nil for the events that could not be rendered.
+func (e *Input) renderEvents(events []Event) []*EventXML {
+	rendered := make([]*EventXML, len(events))
+
+	workers := len(e.renderBuffers)
+	if workers > len(events) {
+		workers = len(events)
+	}
+	if workers <= 1 {
+		for i := range events {
+			rendered[i] = e.renderEvent(events[i], e.buffer)
+		}
+		return rendered
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(buffer Buffer) {
+			defer wg.Done()
+			for i := range indexes {
+				rendered[i] = e.renderEvent(events[i], buffer)
+			}
+		}(e.renderBuffers[w])
+	}
+	for i := range events {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return rendered
+}
+
+// renderEvent will render an event retrieved from windows event log, with its metadata when available.
+func (e *Input) renderEvent(event Event, buffer Buffer) *EventXML {
+	simpleEvent, err := event.RenderSimple(buffer)
 	if err != nil {
 		e.Errorf("Failed to render simple event: %s", err)
-		return
+		return nil
+	}
+
+	// forwarded events collected in the rendered text format already contain their metadata
+	if simpleEvent.Message != "" {
+		return &simpleEvent
 	}
 
-	publisher := NewPublisher()
-	if err := publisher.Open(simpleEvent.Provider.Name); err != nil {
+	publisher, err := e.publishers.get(simpleEvent.Provider.Name)
+	if err != nil {
 		e.Errorf("Failed to open publisher: %s: writing log entry to pipeline without metadata", err)
-		e.sendEvent(ctx, simpleEvent)
-		return
+		return &simpleEvent
 	}
-	defer publisher.Close()
 
-	formattedEvent, err := event.RenderFormatted(e.buffer, publisher)
+	formattedEvent, err := event.RenderFormatted(buffer, publisher)
 	if err != nil {
 		e.Errorf("Failed to render formatted event: %s", err)
-		e.sendEvent(ctx, simpleEvent)
-		return
+		return &simpleEvent
 	}
 
-	e.sendEvent(ctx, formattedEvent)
+	return &formattedEvent
 }
 
-// sendEvent will send EventXML as an entry to the operator's output.
+// sendEvent will send EventXML as an entry to the operator's output, or collect it with the
+// events of its activity when they are correlated.
 func (e *Input) sendEvent(ctx context.Context, eventXML EventXML) {
+	if e.correlator == nil {
+		e.sendEvents(ctx, []EventXML{eventXML})
+		return
+	}
+	correlated, evicted := e.correlator.add(eventXML, time.Now())
+	for _, events := range evicted {
+		e.sendEvents(ctx, events)
+	}
+	if !correlated {
+		e.sendEvents(ctx, []EventXML{eventXML})
+	}
+}
+
+// sendEvents will send the events of an activity as a single entry to the operator's output. The
+// entry is the one of the first event, holding the bodies of the other events in correlated_events
+// and the highest severity of the events.
+func (e *Input) sendEvents(ctx context.Context, events []EventXML) {
+	eventXML := events[0]
 	body := eventXML.parseBody()
+	severity := e.severity.severity(&eventXML)
+	if len(events) > 1 {
+		correlated := make([]interface{}, 0, len(events)-1)
+		for i := range events[1:] {
+			event := &events[i+1]
+			correlated = append(correlated, event.parseBody())
+			if s := e.severity.severity(event); s > severity {
+				severity = s
+			}
+		}
+		body["correlated_events"] = correlated
+	}
+
 	entry, err := e.NewEntry(body)
 	if err != nil {
 		e.Errorf("Failed to create entry: %s", err)
@@ -235,7 +437,10 @@ func (e *Input) sendEvent(ctx context.Context, eventXML EventXML) {
 	}
 
 	entry.Timestamp = eventXML.parseTimestamp()
-	entry.Severity = eventXML.parseRenderedSeverity()
+	entry.Severity = severity
+	if e.sourceComputerResource && eventXML.Computer != "" {
+		entry.AddResourceKey(hostNameResourceKey, eventXML.Computer)
+	}
 	e.Write(ctx, entry)
 }
 
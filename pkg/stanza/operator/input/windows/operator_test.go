@@ -0,0 +1,203 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package windows
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/unicode"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+// renderMockProc returns a mock of EvtRender writing the xml of the event handle to the buffer.
+// It records the buffers it rendered to.
+func renderMockProc(render func(handle uintptr) string, buffers *sync.Map) SyscallProc {
+	return MockProc{
+		call: func(a ...uintptr) (uintptr, uintptr, error) {
+			utf16, err := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder().String(render(a[1]))
+			if err != nil {
+				return 0, 0, ErrorInvalidOperation
+			}
+
+			// the arguments are the buffer, its size and the pointer to the used size
+			buffer := unsafe.Slice(*(**byte)(unsafe.Pointer(&a[4])), a[3])
+			bufferUsed := *(**uint32)(unsafe.Pointer(&a[5]))
+			*bufferUsed = uint32(len(utf16))
+			if len(utf16) > len(buffer) {
+				return 0, 0, ErrorInsufficientBuffer
+			}
+			copy(buffer, utf16)
+			buffers.Store(a[4], struct{}{})
+			return 1, 0, ErrorSuccess
+		},
+	}
+}
+
+func newTestInput(t *testing.T, workers int) *Input {
+	cfg := NewConfigWithID("test")
+	cfg.Channel = "ForwardedEvents"
+	cfg.RenderWorkers = workers
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	input := op.(*Input)
+	input.publishers = newPublisherCache()
+	return input
+}
+
+func TestInputRenderEvents(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			input := newTestInput(t, workers)
+
+			var buffers sync.Map
+			renderProc = renderMockProc(func(handle uintptr) string {
+				return fmt.Sprintf(`<Event><System><Provider Name="provider"/><EventRecordID>%d</EventRecordID></System><RenderingInfo><Message>message %d</Message></RenderingInfo></Event>`, handle, handle)
+			}, &buffers)
+
+			events := make([]Event, 100)
+			for i := range events {
+				events[i] = NewEvent(uintptr(i + 1))
+			}
+			// an event without handle can't be rendered
+			events[50] = NewEvent(0)
+
+			rendered := input.renderEvents(events)
+			require.Len(t, rendered, len(events))
+			for i, event := range rendered {
+				if i == 50 {
+					require.Nil(t, event)
+					continue
+				}
+				require.NotNil(t, event)
+				require.Equal(t, uint64(i+1), event.RecordID)
+				require.Equal(t, fmt.Sprintf("message %d", i+1), event.Message)
+			}
+
+			// the workers only render to their own buffer
+			own := map[uintptr]bool{}
+			for i := range input.renderBuffers {
+				own[uintptr(unsafe.Pointer(input.renderBuffers[i].FirstByte()))] = true
+			}
+			used := 0
+			buffers.Range(func(key, _ interface{}) bool {
+				require.True(t, own[key.(uintptr)])
+				used++
+				return true
+			})
+			require.LessOrEqual(t, used, workers)
+		})
+	}
+}
+
+func TestInputRenderEventsGrowsWorkerBuffers(t *testing.T) {
+	input := newTestInput(t, 2)
+	message := strings.Repeat("a", defaultBufferSize)
+
+	var buffers sync.Map
+	renderProc = renderMockProc(func(handle uintptr) string {
+		return fmt.Sprintf(`<Event><System><EventRecordID>%d</EventRecordID></System><RenderingInfo><Message>%s</Message></RenderingInfo></Event>`, handle, message)
+	}, &buffers)
+
+	events := []Event{NewEvent(1), NewEvent(2), NewEvent(3)}
+	rendered := input.renderEvents(events)
+	for i, event := range rendered {
+		require.NotNil(t, event)
+		require.Equal(t, uint64(i+1), event.RecordID)
+	}
+}
+
+func TestInputRenderEventsWithoutPublisher(t *testing.T) {
+	input := newTestInput(t, 4)
+
+	var buffers sync.Map
+	renderProc = renderMockProc(func(handle uintptr) string {
+		return fmt.Sprintf(`<Event><System><Provider Name="provider"/><EventRecordID>%d</EventRecordID></System></Event>`, handle)
+	}, &buffers)
+
+	var opened int32
+	openPublisherMetadataProc = MockProc{
+		call: func(a ...uintptr) (uintptr, uintptr, error) {
+			atomic.AddInt32(&opened, 1)
+			return 0, 0, ErrorNotSupported
+		},
+	}
+
+	events := make([]Event, 20)
+	for i := range events {
+		events[i] = NewEvent(uintptr(i + 1))
+	}
+
+	// the events are sent without metadata, the publisher is only looked up once
+	rendered := input.renderEvents(events)
+	for i, event := range rendered {
+		require.NotNil(t, event)
+		require.Equal(t, uint64(i+1), event.RecordID)
+		require.Empty(t, event.Message)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&opened))
+
+	closeProc = SimpleMockProc(1, 0, ErrorSuccess)
+	require.NoError(t, input.publishers.Close())
+}
+
+func TestConfigBuildBatching(t *testing.T) {
+	cfg := NewConfigWithID("test")
+	cfg.Channel = "Security"
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.Equal(t, cfg.MaxReads, op.(*Input).batchSize, "the batch size defaults to max_reads")
+
+	cfg.BatchSize = 10
+	op, err = cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.Equal(t, 10, op.(*Input).batchSize)
+
+	cfg.BatchSize = 1000
+	op, err = cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.Equal(t, cfg.MaxReads, op.(*Input).batchSize, "the batch size is limited to max_reads")
+
+	cfg.BatchSize = -1
+	_, err = cfg.Build(testutil.Logger(t))
+	require.EqualError(t, err, "the `batch_size` field must not be negative")
+
+	cfg.BatchSize = 0
+	cfg.MaxEventsPerPoll = -1
+	_, err = cfg.Build(testutil.Logger(t))
+	require.EqualError(t, err, "the `max_events_per_poll` field must not be negative")
+}
+
+func TestInputReadsLeft(t *testing.T) {
+	input := newTestInput(t, 1)
+	require.Equal(t, 100, input.readsLeft(0))
+	require.Equal(t, 100, input.readsLeft(1000), "there is no limit by default")
+
+	input.maxEventsPerPoll = 250
+	require.Equal(t, 100, input.readsLeft(0))
+	require.Equal(t, 100, input.readsLeft(100))
+	require.Equal(t, 50, input.readsLeft(200))
+	require.Equal(t, 0, input.readsLeft(250))
+}
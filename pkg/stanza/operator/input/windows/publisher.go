@@ -19,7 +19,10 @@ package windows // import "github.com/open-telemetry/opentelemetry-collector-con
 
 import (
 	"fmt"
+	"sync"
 	"syscall"
+
+	"go.uber.org/multierr"
 )
 
 // Publisher is a windows event metadata publisher.
@@ -67,3 +70,52 @@ func NewPublisher() Publisher {
 		handle: 0,
 	}
 }
+
+// publisherCache keeps the publishers open for the lifetime of the input, since opening the
+// publisher metadata for each event is expensive on high volume channels. The providers whose
+// publisher failed to open are remembered as well, so that their metadata isn't looked up again
+// for each of their events.
+type publisherCache struct {
+	mu         sync.Mutex
+	publishers map[string]cachedPublisher
+}
+
+// cachedPublisher is the publisher of a provider, or the error returned when opening it.
+type cachedPublisher struct {
+	publisher Publisher
+	err       error
+}
+
+func newPublisherCache() *publisherCache {
+	return &publisherCache{
+		publishers: map[string]cachedPublisher{},
+	}
+}
+
+// get will return the publisher of the provider, opening it if needed.
+func (c *publisherCache) get(provider string) (Publisher, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.publishers[provider]; ok {
+		return cached.publisher, cached.err
+	}
+
+	publisher := NewPublisher()
+	err := publisher.Open(provider)
+	c.publishers[provider] = cachedPublisher{publisher: publisher, err: err}
+	return publisher, err
+}
+
+// Close will close all the publishers of the cache.
+func (c *publisherCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs error
+	for provider, cached := range c.publishers {
+		errs = multierr.Append(errs, cached.publisher.Close())
+		delete(c.publishers, provider)
+	}
+	return errs
+}
@@ -77,3 +77,46 @@ func TestPublisherCloseSuccess(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, uintptr(0), publisher.handle)
 }
+
+func TestPublisherCacheReusesPublisher(t *testing.T) {
+	cache := newPublisherCache()
+	openPublisherMetadataProc = SimpleMockProc(5, 0, ErrorSuccess)
+	publisher, err := cache.get("provider")
+	require.NoError(t, err)
+	require.Equal(t, uintptr(5), publisher.handle)
+
+	openPublisherMetadataProc = SimpleMockProc(0, 0, ErrorNotSupported)
+	publisher, err = cache.get("provider")
+	require.NoError(t, err)
+	require.Equal(t, uintptr(5), publisher.handle)
+
+	_, err = cache.get("other")
+	require.Error(t, err)
+
+	closeProc = SimpleMockProc(1, 0, ErrorSuccess)
+	require.NoError(t, cache.Close())
+	require.Empty(t, cache.publishers)
+}
+
+func TestPublisherCacheRemembersOpenFailure(t *testing.T) {
+	cache := newPublisherCache()
+	opened := 0
+	openPublisherMetadataProc = MockProc{
+		call: func(a ...uintptr) (uintptr, uintptr, error) {
+			opened++
+			return 0, 0, ErrorNotSupported
+		},
+	}
+
+	_, err := cache.get("provider")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to open publisher handle")
+
+	_, err = cache.get("provider")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to open publisher handle")
+	require.Equal(t, 1, opened)
+
+	require.NoError(t, cache.Close())
+	require.Empty(t, cache.publishers)
+}
@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package windows // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/windows"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+// SeverityConfig customizes the severity of the events. Both mappings have the format of
+// the mapping of the severity parser, from severities to the values they are mapped from.
+type SeverityConfig struct {
+	// Levels maps the levels of the events, by name (e.g. Warning) or by number (e.g. 3),
+	// overriding the default mapping of the levels.
+	Levels map[interface{}]interface{} `mapstructure:"levels,omitempty"`
+	// Keywords maps the keywords of the events, e.g. Audit Failure. A matching keyword takes
+	// precedence over the level, the highest severity is used when several keywords match.
+	Keywords map[interface{}]interface{} `mapstructure:"keywords,omitempty"`
+}
+
+// standardKeywords are the names of the standard keywords, to match the keywords of the
+// events whose keywords aren't rendered but only available as a mask.
+var standardKeywords = map[string]uint64{
+	"Audit Failure": 0x10000000000000,
+	"Audit Success": 0x20000000000000,
+}
+
+// severityMapper maps the level and keywords of the events to severities.
+type severityMapper struct {
+	levels   map[string]entry.Severity
+	keywords map[string]entry.Severity
+}
+
+// build returns the severity mapper of the configuration, nil if it doesn't customize the severities.
+func (c SeverityConfig) build(logger *zap.SugaredLogger) (*severityMapper, error) {
+	if len(c.Levels) == 0 && len(c.Keywords) == 0 {
+		return nil, nil
+	}
+	levels, err := buildSeverityMapping(logger, c.Levels)
+	if err != nil {
+		return nil, fmt.Errorf("invalid `severity.levels` field: %w", err)
+	}
+	keywords, err := buildSeverityMapping(logger, c.Keywords)
+	if err != nil {
+		return nil, fmt.Errorf("invalid `severity.keywords` field: %w", err)
+	}
+	return &severityMapper{levels: levels, keywords: keywords}, nil
+}
+
+// buildSeverityMapping returns the lowercased values mapped to their severity.
+func buildSeverityMapping(logger *zap.SugaredLogger, mapping map[interface{}]interface{}) (map[string]entry.Severity, error) {
+	body := entry.NewBodyField()
+	cfg := helper.SeverityConfig{
+		ParseFrom: &body,
+		Preset:    "none",
+		Mapping:   mapping,
+	}
+	parser, err := cfg.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+	return parser.Mapping, nil
+}
+
+// severity returns the severity of the event.
+func (m *severityMapper) severity(e *EventXML) entry.Severity {
+	severity := e.parseRenderedSeverity()
+	if m == nil {
+		return severity
+	}
+
+	if s, ok := m.levels[strings.ToLower(e.RenderedLevel)]; ok && e.RenderedLevel != "" {
+		severity = s
+	} else if s, ok := m.levels[e.Level]; ok {
+		severity = s
+	}
+
+	matched := false
+	for _, keyword := range e.keywordNames() {
+		if s, ok := m.keywords[strings.ToLower(keyword)]; ok && (!matched || s > severity) {
+			severity = s
+			matched = true
+		}
+	}
+	return severity
+}
+
+// keywordNames returns the keywords of the event. When they aren't rendered, the standard
+// keywords set in the masks are added to the masks themselves.
+func (e *EventXML) keywordNames() []string {
+	if len(e.RenderedKeywords) > 0 {
+		return e.RenderedKeywords
+	}
+	names := append([]string(nil), e.Keywords...)
+	for _, keywords := range e.Keywords {
+		mask, err := strconv.ParseUint(strings.TrimPrefix(keywords, "0x"), 16, 64)
+		if err != nil {
+			continue
+		}
+		for name, bit := range standardKeywords {
+			if mask&bit != 0 {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
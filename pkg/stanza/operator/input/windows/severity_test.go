@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package windows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func TestSeverityMapper(t *testing.T) {
+	mapper, err := SeverityConfig{
+		Levels: map[interface{}]interface{}{
+			"error": "Warning",
+			"info":  0,
+		},
+		Keywords: map[interface{}]interface{}{
+			"error":  "Audit Failure",
+			"debug":  "Audit Success",
+			"fatal2": "Custom",
+		},
+	}.build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		event    EventXML
+		severity entry.Severity
+	}{
+		{"rendered level", EventXML{RenderedLevel: "Warning", Level: "3"}, entry.Error},
+		{"level number", EventXML{Level: "0"}, entry.Info},
+		{"default level", EventXML{RenderedLevel: "Critical"}, entry.Fatal},
+		{"rendered keyword", EventXML{RenderedLevel: "Information", RenderedKeywords: []string{"Audit Failure"}}, entry.Error},
+		{"lower keyword", EventXML{RenderedLevel: "Error", RenderedKeywords: []string{"Audit Success"}}, entry.Debug},
+		{"highest keyword", EventXML{RenderedKeywords: []string{"Audit Failure", "Custom"}}, entry.Fatal2},
+		{"keyword mask", EventXML{Level: "0", Keywords: []string{"0x8010000000000000"}}, entry.Error},
+		{"unmatched keyword mask", EventXML{Level: "4", Keywords: []string{"0x80000000000000"}}, entry.Info},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.severity, mapper.severity(&tc.event))
+		})
+	}
+}
+
+func TestSeverityMapperDefault(t *testing.T) {
+	mapper, err := SeverityConfig{}.build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.Nil(t, mapper)
+
+	event := EventXML{RenderedLevel: "Information", RenderedKeywords: []string{"Audit Failure"}}
+	require.Equal(t, entry.Info, mapper.severity(&event))
+}
+
+func TestSeverityConfigInvalid(t *testing.T) {
+	_, err := SeverityConfig{Keywords: map[interface{}]interface{}{"severe": "Audit Failure"}}.build(testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid `severity.keywords` field")
+}
@@ -43,6 +43,7 @@ type EventXML struct {
 	RenderedKeywords []string    `xml:"RenderingInfo>Keywords>Keyword"`
 	Keywords         []string    `xml:"System>Keywords"`
 	EventData        []string    `xml:"EventData>Data"`
+	Correlation      Correlation `xml:"System>Correlation"`
 }
 
 // parseTimestamp will parse the timestamp of the event.
@@ -135,6 +136,12 @@ func (e *EventXML) parseBody() map[string]interface{} {
 	if len(details) > 0 {
 		body["details"] = details
 	}
+	if e.Correlation.ActivityID != "" {
+		body["correlation"] = map[string]interface{}{
+			"activity_id":         e.Correlation.ActivityID,
+			"related_activity_id": e.Correlation.RelatedActivityID,
+		}
+	}
 	return body
 }
 
@@ -174,3 +181,9 @@ type Provider struct {
 	GUID            string `xml:"Guid,attr"`
 	EventSourceName string `xml:"EventSourceName,attr"`
 }
+
+// Correlation holds the activity identifiers correlating the events of an activity.
+type Correlation struct {
+	ActivityID        string `xml:"ActivityID,attr"`
+	RelatedActivityID string `xml:"RelatedActivityID,attr"`
+}
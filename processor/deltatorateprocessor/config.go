@@ -16,6 +16,7 @@ package deltatorateprocessor // import "github.com/open-telemetry/opentelemetry-
 
 import (
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/config"
 )
@@ -26,6 +27,31 @@ type Config struct {
 
 	// List of delta sum metrics to convert to rates
 	Metrics []string `mapstructure:"metrics"`
+
+	// KeepOriginal keeps the delta sum metrics and emits the rates as new metrics,
+	// named after the delta sum metrics followed by RateSuffix.
+	KeepOriginal bool `mapstructure:"keep_original"`
+
+	// RateSuffix is appended to the name of the rate metrics when KeepOriginal is set.
+	RateSuffix string `mapstructure:"rate_suffix"`
+
+	// UnitAware sets the unit of the rate metrics from the unit of the delta sum metrics,
+	// per second, and converts the values of the metrics measuring a time to seconds.
+	UnitAware bool `mapstructure:"unit_aware"`
+
+	// MinInterval is the shortest interval a rate is computed over. The data points of a
+	// shorter interval are dropped from the rate metrics, instead of reporting the spikes
+	// caused by the division by a near zero interval. Zero disables the guard.
+	MinInterval time.Duration `mapstructure:"min_interval"`
+
+	// MaxRate is the largest absolute value of the rates, the rates above it are clamped.
+	// Zero disables the clamping.
+	MaxRate float64 `mapstructure:"max_rate"`
+
+	// Output is the type of the rate metrics: "gauge" emits gauges, "delta" emits delta sums over the intervals
+	// of the delta sum metrics and "cumulative" emits cumulative sums starting when each series was first seen,
+	// for the backends accepting only a given temporality. It defaults to "gauge".
+	Output string `mapstructure:"output"`
 }
 
 // Validate checks whether the input configuration has all of the required fields for the processor.
@@ -34,5 +60,19 @@ func (config *Config) Validate() error {
 	if len(config.Metrics) == 0 {
 		return fmt.Errorf("metric names are missing")
 	}
+	if config.KeepOriginal && config.RateSuffix == "" {
+		return fmt.Errorf("rate suffix is required when keeping the original metrics")
+	}
+	if config.MinInterval < 0 {
+		return fmt.Errorf("min interval must not be negative")
+	}
+	if config.MaxRate < 0 {
+		return fmt.Errorf("max rate must not be negative")
+	}
+	switch config.Output {
+	case "", gaugeOutput, deltaOutput, cumulativeOutput:
+	default:
+		return fmt.Errorf("unsupported output %q, must be one of %q, %q or %q", config.Output, gaugeOutput, deltaOutput, cumulativeOutput)
+	}
 	return nil
 }
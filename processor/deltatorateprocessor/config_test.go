@@ -17,6 +17,7 @@ package deltatorateprocessor
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -41,8 +42,74 @@ func TestLoadConfig(t *testing.T) {
 					"metric1",
 					"metric2",
 				},
+				RateSuffix: defaultRateSuffix,
+				Output:     gaugeOutput,
 			},
 		},
+		{
+			id: component.NewIDWithName(typeStr, "keep_original"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				Metrics: []string{
+					"metric1",
+				},
+				KeepOriginal: true,
+				RateSuffix:   ".rate",
+				Output:       gaugeOutput,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "unit_aware"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				Metrics: []string{
+					"metric1",
+				},
+				RateSuffix: defaultRateSuffix,
+				UnitAware:  true,
+				Output:     gaugeOutput,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "guards"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				Metrics: []string{
+					"metric1",
+				},
+				RateSuffix:  defaultRateSuffix,
+				MinInterval: 5 * time.Second,
+				MaxRate:     1000,
+				Output:      gaugeOutput,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "cumulative"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				Metrics: []string{
+					"metric1",
+				},
+				RateSuffix: defaultRateSuffix,
+				Output:     cumulativeOutput,
+			},
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "invalid_output"),
+			errorMessage: `unsupported output "histogram", must be one of "gauge", "delta" or "cumulative"`,
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "negative_min_interval"),
+			errorMessage: "min interval must not be negative",
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "negative_max_rate"),
+			errorMessage: "max rate must not be negative",
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "missing_rate_suffix"),
+			errorMessage: "rate suffix is required when keeping the original metrics",
+		},
 		{
 			id:           component.NewIDWithName(typeStr, "missing_name"),
 			errorMessage: "metric names are missing",
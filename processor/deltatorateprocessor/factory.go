@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
@@ -29,12 +30,16 @@ const (
 	typeStr = "deltatorate"
 	// The stability level of the processor.
 	stability = component.StabilityLevelInDevelopment
+	// The default suffix of the rate metrics when the original metrics are kept.
+	defaultRateSuffix = ".per_second"
 )
 
 var processorCapabilities = consumer.Capabilities{MutatesData: true}
 
 // NewFactory returns a new factory for the Delta to Rate processor.
 func NewFactory() component.ProcessorFactory {
+	_ = view.Register(MetricViews()...)
+
 	return component.NewProcessorFactory(
 		typeStr,
 		createDefaultConfig,
@@ -44,6 +49,8 @@ func NewFactory() component.ProcessorFactory {
 func createDefaultConfig() component.ProcessorConfig {
 	return &Config{
 		ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+		RateSuffix:        defaultRateSuffix,
+		Output:            gaugeOutput,
 	}
 }
 
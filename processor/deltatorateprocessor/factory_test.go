@@ -39,6 +39,8 @@ func TestCreateDefaultConfig(t *testing.T) {
 	cfg := factory.CreateDefaultConfig()
 	assert.Equal(t, cfg, &Config{
 		ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+		RateSuffix:        defaultRateSuffix,
+		Output:            gaugeOutput,
 	})
 	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
 }
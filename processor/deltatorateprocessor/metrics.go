@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltatorateprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatorateprocessor"
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+var (
+	tagMetricKey, _ = tag.NewKey("metric")
+
+	mSkippedDataPoints = stats.Int64("processor_deltatorate_skipped_data_points", "Data points dropped from the rate metrics as their interval is below the minimum interval", stats.UnitDimensionless)
+	mClampedDataPoints = stats.Int64("processor_deltatorate_clamped_data_points", "Data points whose rate was clamped to the maximum rate", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views related to the delta to rate processor.
+func MetricViews() []*view.View {
+	return []*view.View{
+		{
+			Name:        obsreport.BuildProcessorCustomMetricName(typeStr, mSkippedDataPoints.Name()),
+			Measure:     mSkippedDataPoints,
+			Description: mSkippedDataPoints.Description(),
+			TagKeys:     []tag.Key{tagMetricKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        obsreport.BuildProcessorCustomMetricName(typeStr, mClampedDataPoints.Name()),
+			Measure:     mClampedDataPoints,
+			Description: mClampedDataPoints.Description(),
+			TagKeys:     []tag.Key{tagMetricKey},
+			Aggregation: view.Sum(),
+		},
+	}
+}
+
+// recordGuards records the data points of the metric skipped or clamped by the guards.
+func recordGuards(ctx context.Context, metric string, skipped, clamped int64) {
+	mutators := []tag.Mutator{tag.Upsert(tagMetricKey, metric)}
+	if skipped > 0 {
+		_ = stats.RecordWithTags(ctx, mutators, mSkippedDataPoints.M(skipped))
+	}
+	if clamped > 0 {
+		_ = stats.RecordWithTags(ctx, mutators, mClampedDataPoints.M(clamped))
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deltatorateprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatorateprocessor"
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	// gaugeOutput emits the rates as gauges.
+	gaugeOutput = "gauge"
+	// deltaOutput emits the rates as delta sums, with the intervals of the delta sum metrics.
+	deltaOutput = "delta"
+	// cumulativeOutput emits the rates as cumulative sums, starting when the series was first seen.
+	cumulativeOutput = "cumulative"
+
+	// staleSeriesTimeout is how long the start of a series is kept in the cumulative output
+	// without receiving a data point.
+	staleSeriesTimeout = 15 * time.Minute
+)
+
+// setEmptyRate sets the rate metric to an empty metric of the type of the output, and returns its data points.
+func (dtrp *deltaToRateProcessor) setEmptyRate(metric pmetric.Metric) pmetric.NumberDataPointSlice {
+	switch dtrp.output {
+	case deltaOutput, cumulativeOutput:
+		sum := metric.SetEmptySum()
+		// a rate can decrease
+		sum.SetIsMonotonic(false)
+		if dtrp.output == deltaOutput {
+			sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		} else {
+			sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		}
+		return sum.DataPoints()
+	default:
+		return metric.SetEmptyGauge().DataPoints()
+	}
+}
+
+// rateDataPoints returns the data points of a rate metric, false if the metric isn't of the type of the output.
+func (dtrp *deltaToRateProcessor) rateDataPoints(metric pmetric.Metric) (pmetric.NumberDataPointSlice, bool) {
+	switch {
+	case dtrp.output == deltaOutput && metric.Type() == pmetric.MetricTypeSum &&
+		metric.Sum().AggregationTemporality() == pmetric.AggregationTemporalityDelta,
+		dtrp.output == cumulativeOutput && metric.Type() == pmetric.MetricTypeSum &&
+			metric.Sum().AggregationTemporality() == pmetric.AggregationTemporalityCumulative:
+		return metric.Sum().DataPoints(), true
+	case (dtrp.output == "" || dtrp.output == gaugeOutput) && metric.Type() == pmetric.MetricTypeGauge:
+		return metric.Gauge().DataPoints(), true
+	default:
+		return pmetric.NumberDataPointSlice{}, false
+	}
+}
+
+// seriesStart is the start of a series in the cumulative output.
+type seriesStart struct {
+	start    pcommon.Timestamp
+	lastSeen time.Time
+}
+
+// seriesStarts holds the start timestamps of the series of the rates in the cumulative output.
+type seriesStarts struct {
+	mu     sync.Mutex
+	starts map[string]*seriesStart
+	now    func() time.Time
+}
+
+func newSeriesStarts() *seriesStarts {
+	return &seriesStarts{starts: map[string]*seriesStart{}, now: time.Now}
+}
+
+// setStart sets the start timestamp of the data point to the start of its series, which starts with
+// its first data point. The series not seen for the stale timeout restart.
+func (s *seriesStarts) setStart(resource pcommon.Resource, name string, dp pmetric.NumberDataPoint) {
+	key := fmt.Sprint(name, resource.Attributes().AsRaw(), dp.Attributes().AsRaw())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	series, ok := s.starts[key]
+	if !ok || now.Sub(series.lastSeen) > staleSeriesTimeout {
+		series = &seriesStart{start: dp.StartTimestamp()}
+		s.starts[key] = series
+	}
+	series.lastSeen = now
+	dp.SetStartTimestamp(series.start)
+}
+
+// removeStale removes the series not seen for the stale timeout.
+func (s *seriesStarts) removeStale() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for key, series := range s.starts {
+		if now.Sub(series.lastSeen) > staleSeriesTimeout {
+			delete(s.starts, key)
+		}
+	}
+}
@@ -17,6 +17,8 @@ package deltatorateprocessor // import "github.com/open-telemetry/opentelemetry-
 import (
 	"context"
 	"fmt"
+	"math"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -27,7 +29,32 @@ import (
 
 type deltaToRateProcessor struct {
 	ConfiguredMetrics map[string]bool
+	keepOriginal      bool
+	rateSuffix        string
+	unitAware         bool
+	minInterval       time.Duration
+	maxRate           float64
+	output            string
 	logger            *zap.Logger
+
+	// starts holds the start timestamps of the series in the cumulative output, nil for the other outputs.
+	starts *seriesStarts
+
+	// unitlessWarned holds the names of the unitless metrics already warned about.
+	unitlessWarned   map[string]bool
+	unitlessWarnedMu sync.Mutex
+}
+
+// timeUnitSeconds holds the number of seconds of the time units, the rates of the metrics
+// measuring a time are converted to seconds per second.
+var timeUnitSeconds = map[string]float64{
+	"ns":  1e-9,
+	"us":  1e-6,
+	"ms":  1e-3,
+	"s":   1,
+	"min": 60,
+	"h":   3600,
+	"d":   86400,
 }
 
 func newDeltaToRateProcessor(config *Config, logger *zap.Logger) *deltaToRateProcessor {
@@ -36,9 +63,22 @@ func newDeltaToRateProcessor(config *Config, logger *zap.Logger) *deltaToRatePro
 		inputMetricSet[name] = true
 	}
 
+	var starts *seriesStarts
+	if config.Output == cumulativeOutput {
+		starts = newSeriesStarts()
+	}
+
 	return &deltaToRateProcessor{
 		ConfiguredMetrics: inputMetricSet,
+		keepOriginal:      config.KeepOriginal,
+		rateSuffix:        config.RateSuffix,
+		unitAware:         config.UnitAware,
+		minInterval:       config.MinInterval,
+		maxRate:           config.MaxRate,
+		output:            config.Output,
 		logger:            logger,
+		starts:            starts,
+		unitlessWarned:    map[string]bool{},
 	}
 }
 
@@ -48,8 +88,11 @@ func (dtrp *deltaToRateProcessor) Start(context.Context, component.Host) error {
 }
 
 // processMetrics implements the ProcessMetricsFunc type.
-func (dtrp *deltaToRateProcessor) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+func (dtrp *deltaToRateProcessor) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
 	resourceMetricsSlice := md.ResourceMetrics()
+	if dtrp.starts != nil {
+		defer dtrp.starts.removeStale()
+	}
 
 	for i := 0; i < resourceMetricsSlice.Len(); i++ {
 		rm := resourceMetricsSlice.At(i)
@@ -57,7 +100,11 @@ func (dtrp *deltaToRateProcessor) processMetrics(_ context.Context, md pmetric.M
 		for i := 0; i < ilms.Len(); i++ {
 			ilm := ilms.At(i)
 			metricSlice := ilm.Metrics()
-			for j := 0; j < metricSlice.Len(); j++ {
+			// The rate metrics appended when keeping the original metrics are not processed.
+			metricCount := metricSlice.Len()
+			// emptied holds the names of the rate metrics left without data point by the interval guard.
+			var emptied map[string]bool
+			for j := 0; j < metricCount; j++ {
 				metric := metricSlice.At(j)
 				if _, ok := dtrp.ConfiguredMetrics[metric.Name()]; !ok {
 					continue
@@ -66,15 +113,24 @@ func (dtrp *deltaToRateProcessor) processMetrics(_ context.Context, md pmetric.M
 					dtrp.logger.Info(fmt.Sprintf("Configured metric for rate calculation %s is not a delta sum\n", metric.Name()))
 					continue
 				}
+				unit, scale := metric.Unit(), float64(1)
+				if dtrp.unitAware {
+					unit, scale = dtrp.rateUnit(metric)
+				}
 				newDoubleDataPointSlice := pmetric.NewNumberDataPointSlice()
 				dataPoints := metric.Sum().DataPoints()
+				var skipped, clamped int64
 
 				for i := 0; i < dataPoints.Len(); i++ {
 					fromDataPoint := dataPoints.At(i)
+					durationNanos := time.Duration(fromDataPoint.Timestamp() - fromDataPoint.StartTimestamp())
+					if durationNanos < dtrp.minInterval {
+						skipped++
+						continue
+					}
 					newDp := newDoubleDataPointSlice.AppendEmpty()
 					fromDataPoint.CopyTo(newDp)
 
-					durationNanos := time.Duration(fromDataPoint.Timestamp() - fromDataPoint.StartTimestamp())
 					var rate float64
 					switch fromDataPoint.ValueType() {
 					case pmetric.NumberDataPointValueTypeDouble:
@@ -84,21 +140,71 @@ func (dtrp *deltaToRateProcessor) processMetrics(_ context.Context, md pmetric.M
 					default:
 						return md, consumererror.NewPermanent(fmt.Errorf("invalid data point type:%d", fromDataPoint.ValueType()))
 					}
+					rate *= scale
+					if dtrp.maxRate > 0 && math.Abs(rate) > dtrp.maxRate {
+						rate = math.Copysign(dtrp.maxRate, rate)
+						clamped++
+					}
 					newDp.SetDoubleValue(rate)
 				}
+				recordGuards(ctx, metric.Name(), skipped, clamped)
 
-				dps := metric.SetEmptyGauge().DataPoints()
+				rateMetric := metric
+				if dtrp.keepOriginal {
+					rateMetric = metricSlice.AppendEmpty()
+					rateMetric.SetName(metric.Name() + dtrp.rateSuffix)
+					rateMetric.SetDescription(metric.Description())
+				}
+				rateMetric.SetUnit(unit)
+				dps := dtrp.setEmptyRate(rateMetric)
 				dps.EnsureCapacity(newDoubleDataPointSlice.Len())
 				for d := 0; d < newDoubleDataPointSlice.Len(); d++ {
 					dp := dps.AppendEmpty()
 					newDoubleDataPointSlice.At(d).CopyTo(dp)
+					if dtrp.starts != nil {
+						dtrp.starts.setStart(rm.Resource(), rateMetric.Name(), dp)
+					}
 				}
+				if dps.Len() == 0 && dataPoints.Len() > 0 {
+					if emptied == nil {
+						emptied = map[string]bool{}
+					}
+					emptied[rateMetric.Name()] = true
+				}
+			}
+			if emptied != nil {
+				metricSlice.RemoveIf(func(m pmetric.Metric) bool {
+					dps, ok := dtrp.rateDataPoints(m)
+					return ok && dps.Len() == 0 && emptied[m.Name()]
+				})
 			}
 		}
 	}
 	return md, nil
 }
 
+// rateUnit returns the unit of the rate of the metric, and the scale converting its values
+// to this unit. A unitless metric is reported once, its rate is assumed to be a count per
+// second.
+func (dtrp *deltaToRateProcessor) rateUnit(metric pmetric.Metric) (string, float64) {
+	unit := metric.Unit()
+	if seconds, ok := timeUnitSeconds[unit]; ok {
+		// a time per second, such as the CPU time, is a ratio
+		return "1", seconds
+	}
+	if unit == "" || unit == "1" {
+		dtrp.unitlessWarnedMu.Lock()
+		defer dtrp.unitlessWarnedMu.Unlock()
+		if !dtrp.unitlessWarned[metric.Name()] {
+			dtrp.unitlessWarned[metric.Name()] = true
+			dtrp.logger.Warn("Configured metric for rate calculation has no unit, its rate is reported as a count per second",
+				zap.String("metric", metric.Name()))
+		}
+		return "1/s", 1
+	}
+	return unit + "/s", 1
+}
+
 // Shutdown is invoked during service shutdown.
 func (dtrp *deltaToRateProcessor) Shutdown(context.Context) error {
 	return nil
@@ -21,27 +21,38 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 type testMetric struct {
 	metricNames     []string
 	metricValues    [][]float64
 	metricIntValues [][]int64
+	metricUnits     []string
 	isDelta         []bool
 	deltaSecond     int
 }
 
 type deltaToRateTest struct {
-	name       string
-	metrics    []string
-	inMetrics  pmetric.Metrics
-	outMetrics pmetric.Metrics
+	name         string
+	metrics      []string
+	keepOriginal bool
+	unitAware    bool
+	minInterval  time.Duration
+	maxRate      float64
+	inMetrics    pmetric.Metrics
+	outMetrics   pmetric.Metrics
 }
 
 var (
@@ -120,6 +131,156 @@ var (
 				metricValues: [][]float64{{1, 2, 3}, {3}},
 			}),
 		},
+		{
+			name:         "delta_to_rate_keep_original",
+			metrics:      []string{"metric_1"},
+			keepOriginal: true,
+			inMetrics: generateSumMetrics(testMetric{
+				metricNames:  []string{"metric_1", "metric_2"},
+				metricValues: [][]float64{{120, 240}, {360}},
+				isDelta:      []bool{true, true},
+				deltaSecond:  120,
+			}),
+			outMetrics: appendMetrics(
+				generateSumMetrics(testMetric{
+					metricNames:  []string{"metric_1", "metric_2"},
+					metricValues: [][]float64{{120, 240}, {360}},
+					isDelta:      []bool{true, true},
+					deltaSecond:  120,
+				}),
+				generateGaugeMetrics(testMetric{
+					metricNames:  []string{"metric_1" + defaultRateSuffix},
+					metricValues: [][]float64{{1, 2}},
+				}),
+			),
+		},
+		{
+			name:      "delta_to_rate_unit_aware",
+			metrics:   []string{"metric_1", "metric_2", "metric_3", "metric_4"},
+			unitAware: true,
+			inMetrics: generateSumMetrics(testMetric{
+				metricNames:  []string{"metric_1", "metric_2", "metric_3", "metric_4"},
+				metricValues: [][]float64{{60000, 120000}, {240}, {360}, {120}},
+				metricUnits:  []string{"ms", "By", "", "{request}"},
+				isDelta:      []bool{true, true, true, true},
+				deltaSecond:  120,
+			}),
+			outMetrics: generateGaugeMetrics(testMetric{
+				metricNames:  []string{"metric_1", "metric_2", "metric_3", "metric_4"},
+				metricValues: [][]float64{{0.5, 1}, {2}, {3}, {1}},
+				metricUnits:  []string{"1", "By/s", "1/s", "{request}/s"},
+			}),
+		},
+		{
+			name:         "delta_to_rate_unit_aware_keep_original",
+			metrics:      []string{"metric_1"},
+			keepOriginal: true,
+			unitAware:    true,
+			inMetrics: generateSumMetrics(testMetric{
+				metricNames:  []string{"metric_1"},
+				metricValues: [][]float64{{240}},
+				metricUnits:  []string{"By"},
+				isDelta:      []bool{true},
+				deltaSecond:  120,
+			}),
+			outMetrics: appendMetrics(
+				generateSumMetrics(testMetric{
+					metricNames:  []string{"metric_1"},
+					metricValues: [][]float64{{240}},
+					metricUnits:  []string{"By"},
+					isDelta:      []bool{true},
+					deltaSecond:  120,
+				}),
+				generateGaugeMetrics(testMetric{
+					metricNames:  []string{"metric_1" + defaultRateSuffix},
+					metricValues: [][]float64{{2}},
+					metricUnits:  []string{"By/s"},
+				}),
+			),
+		},
+		{
+			name:        "delta_to_rate_above_min_interval",
+			metrics:     []string{"metric_1"},
+			minInterval: time.Minute,
+			inMetrics: generateSumMetrics(testMetric{
+				metricNames:  []string{"metric_1"},
+				metricValues: [][]float64{{120, 240}},
+				isDelta:      []bool{true},
+				deltaSecond:  120,
+			}),
+			outMetrics: generateGaugeMetrics(testMetric{
+				metricNames:  []string{"metric_1"},
+				metricValues: [][]float64{{1, 2}},
+			}),
+		},
+		{
+			name:        "delta_to_rate_below_min_interval",
+			metrics:     []string{"metric_1"},
+			minInterval: time.Minute,
+			inMetrics: generateSumMetrics(testMetric{
+				metricNames:  []string{"metric_1", "metric_2"},
+				metricValues: [][]float64{{120, 240}, {360}},
+				isDelta:      []bool{true, true},
+				deltaSecond:  1,
+			}),
+			outMetrics: generateSumMetrics(testMetric{
+				metricNames:  []string{"metric_2"},
+				metricValues: [][]float64{{360}},
+				isDelta:      []bool{true},
+				deltaSecond:  1,
+			}),
+		},
+		{
+			name:         "delta_to_rate_below_min_interval_keep_original",
+			metrics:      []string{"metric_1"},
+			keepOriginal: true,
+			minInterval:  time.Minute,
+			inMetrics: generateSumMetrics(testMetric{
+				metricNames:  []string{"metric_1"},
+				metricValues: [][]float64{{120}},
+				isDelta:      []bool{true},
+				deltaSecond:  0,
+			}),
+			outMetrics: generateSumMetrics(testMetric{
+				metricNames:  []string{"metric_1"},
+				metricValues: [][]float64{{120}},
+				isDelta:      []bool{true},
+				deltaSecond:  0,
+			}),
+		},
+		{
+			name:    "delta_to_rate_max_rate",
+			metrics: []string{"metric_1"},
+			maxRate: 2,
+			inMetrics: generateSumMetrics(testMetric{
+				metricNames:  []string{"metric_1"},
+				metricValues: [][]float64{{120, 240, 360, -600}},
+				isDelta:      []bool{true},
+				deltaSecond:  120,
+			}),
+			outMetrics: generateGaugeMetrics(testMetric{
+				metricNames:  []string{"metric_1"},
+				metricValues: [][]float64{{1, 2, 2, -2}},
+			}),
+		},
+		{
+			name:      "delta_to_rate_max_rate_unit_aware",
+			metrics:   []string{"metric_1"},
+			unitAware: true,
+			maxRate:   1,
+			inMetrics: generateSumMetrics(testMetric{
+				metricNames:  []string{"metric_1"},
+				metricValues: [][]float64{{60000, 240000}},
+				metricUnits:  []string{"ms"},
+				isDelta:      []bool{true},
+				deltaSecond:  120,
+			}),
+			outMetrics: generateGaugeMetrics(testMetric{
+				metricNames:  []string{"metric_1"},
+				metricValues: [][]float64{{0.5, 1}},
+				metricUnits:  []string{"1"},
+			}),
+		},
 	}
 )
 
@@ -131,6 +292,11 @@ func TestCumulativeToDeltaProcessor(t *testing.T) {
 			cfg := &Config{
 				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
 				Metrics:           test.metrics,
+				KeepOriginal:      test.keepOriginal,
+				RateSuffix:        defaultRateSuffix,
+				UnitAware:         test.unitAware,
+				MinInterval:       test.minInterval,
+				MaxRate:           test.maxRate,
 			}
 			factory := NewFactory()
 			mgp, err := factory.CreateMetricsProcessor(
@@ -164,6 +330,7 @@ func TestCumulativeToDeltaProcessor(t *testing.T) {
 				aM := actualMetrics.At(i)
 
 				require.Equal(t, eM.Name(), aM.Name())
+				require.Equal(t, eM.Unit(), aM.Unit())
 
 				if eM.Type() == pmetric.MetricTypeGauge {
 					eDataPoints := eM.Gauge().DataPoints()
@@ -204,6 +371,9 @@ func generateSumMetrics(tm testMetric) pmetric.Metrics {
 	for i, name := range tm.metricNames {
 		m := ms.AppendEmpty()
 		m.SetName(name)
+		if i < len(tm.metricUnits) {
+			m.SetUnit(tm.metricUnits[i])
+		}
 		sum := m.SetEmptySum()
 		sum.SetIsMonotonic(true)
 
@@ -243,6 +413,9 @@ func generateGaugeMetrics(tm testMetric) pmetric.Metrics {
 	for i, name := range tm.metricNames {
 		m := ms.AppendEmpty()
 		m.SetName(name)
+		if i < len(tm.metricUnits) {
+			m.SetUnit(tm.metricUnits[i])
+		}
 		dps := m.SetEmptyGauge().DataPoints()
 		if i < len(tm.metricValues) {
 			for _, value := range tm.metricValues[i] {
@@ -262,3 +435,185 @@ func generateGaugeMetrics(tm testMetric) pmetric.Metrics {
 
 	return md
 }
+
+// appendMetrics appends the metrics of the first scope of from to the first scope of md.
+func appendMetrics(md pmetric.Metrics, from pmetric.Metrics) pmetric.Metrics {
+	fromMetrics := from.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	fromMetrics.MoveAndAppendTo(md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics())
+	return md
+}
+
+func TestDeltaToRateProcessorWarnsUnitlessMetricsOnce(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	dtrp := newDeltaToRateProcessor(&Config{
+		Metrics:   []string{"metric_1", "metric_2"},
+		UnitAware: true,
+	}, zap.New(core))
+
+	for i := 0; i < 2; i++ {
+		_, err := dtrp.processMetrics(context.Background(), generateSumMetrics(testMetric{
+			metricNames:  []string{"metric_1", "metric_2"},
+			metricValues: [][]float64{{120}, {120}},
+			metricUnits:  []string{"", "By"},
+			isDelta:      []bool{true, true},
+			deltaSecond:  120,
+		}))
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "metric_1", logs.All()[0].ContextMap()["metric"])
+}
+
+// guardedDataPoints returns the data points recorded per metric by the view of the measure.
+func guardedDataPoints(t *testing.T, m *stats.Int64Measure) map[string]int64 {
+	rows, err := view.RetrieveData(obsreport.BuildProcessorCustomMetricName(typeStr, m.Name()))
+	require.NoError(t, err)
+	dataPoints := map[string]int64{}
+	for _, row := range rows {
+		for _, tg := range row.Tags {
+			if tg.Key == tagMetricKey {
+				dataPoints[tg.Value] = int64(row.Data.(*view.SumData).Value)
+			}
+		}
+	}
+	return dataPoints
+}
+
+func TestDeltaToRateProcessorRecordsGuards(t *testing.T) {
+	// reset the data recorded by the other tests
+	view.Unregister(MetricViews()...)
+	require.NoError(t, view.Register(MetricViews()...))
+
+	dtrp := newDeltaToRateProcessor(&Config{
+		Metrics:     []string{"metric_1", "metric_2"},
+		MinInterval: time.Minute,
+		MaxRate:     1,
+	}, zap.NewNop())
+
+	md := generateSumMetrics(testMetric{
+		metricNames:  []string{"metric_1"},
+		metricValues: [][]float64{{60, 120, 240}},
+		isDelta:      []bool{true},
+		deltaSecond:  60,
+	})
+	generateSumMetrics(testMetric{
+		metricNames:  []string{"metric_2"},
+		metricValues: [][]float64{{1, 2}},
+		isDelta:      []bool{true},
+		deltaSecond:  10,
+	}).ResourceMetrics().MoveAndAppendTo(md.ResourceMetrics())
+
+	_, err := dtrp.processMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"metric_2": 2}, guardedDataPoints(t, mSkippedDataPoints))
+	assert.Equal(t, map[string]int64{"metric_1": 2}, guardedDataPoints(t, mClampedDataPoints))
+}
+
+// newDeltaSum returns a delta sum metric with a data point over the interval, for the given host.
+func newDeltaSum(name, host string, value float64, start, end time.Time) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("host.name", host)
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(end))
+	dp.SetDoubleValue(value)
+	return md
+}
+
+func TestDeltaToRateProcessorOutput(t *testing.T) {
+	start := time.Unix(1000, 0)
+	end := start.Add(10 * time.Second)
+	tests := []struct {
+		output      string
+		metricType  pmetric.MetricType
+		temporality pmetric.AggregationTemporality
+	}{
+		{output: "", metricType: pmetric.MetricTypeGauge},
+		{output: gaugeOutput, metricType: pmetric.MetricTypeGauge},
+		{output: deltaOutput, metricType: pmetric.MetricTypeSum, temporality: pmetric.AggregationTemporalityDelta},
+		{output: cumulativeOutput, metricType: pmetric.MetricTypeSum, temporality: pmetric.AggregationTemporalityCumulative},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			dtrp := newDeltaToRateProcessor(&Config{
+				Metrics:      []string{"metric_1"},
+				KeepOriginal: true,
+				RateSuffix:   defaultRateSuffix,
+				Output:       tt.output,
+			}, zap.NewNop())
+			md, err := dtrp.processMetrics(context.Background(), newDeltaSum("metric_1", "host-1", 50, start, end))
+			require.NoError(t, err)
+
+			metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			require.Equal(t, 2, metrics.Len())
+			assert.Equal(t, pmetric.MetricTypeSum, metrics.At(0).Type(), "the delta sum is kept")
+			rate := metrics.At(1)
+			assert.Equal(t, "metric_1.per_second", rate.Name())
+			require.Equal(t, tt.metricType, rate.Type())
+			dps, ok := dtrp.rateDataPoints(rate)
+			require.True(t, ok)
+			if tt.metricType == pmetric.MetricTypeSum {
+				assert.False(t, rate.Sum().IsMonotonic())
+				assert.Equal(t, tt.temporality, rate.Sum().AggregationTemporality())
+			}
+			require.Equal(t, 1, dps.Len())
+			assert.Equal(t, float64(5), dps.At(0).DoubleValue())
+			assert.Equal(t, pcommon.NewTimestampFromTime(start), dps.At(0).StartTimestamp())
+			assert.Equal(t, pcommon.NewTimestampFromTime(end), dps.At(0).Timestamp())
+		})
+	}
+}
+
+func TestDeltaToRateProcessorCumulativeStarts(t *testing.T) {
+	dtrp := newDeltaToRateProcessor(&Config{
+		Metrics: []string{"metric_1"},
+		Output:  cumulativeOutput,
+	}, zap.NewNop())
+	now := time.Unix(1000, 0).UTC()
+	dtrp.starts.now = func() time.Time { return now }
+
+	rateStart := func(md pmetric.Metrics) time.Time {
+		md, err := dtrp.processMetrics(context.Background(), md)
+		require.NoError(t, err)
+		dps := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+		require.Equal(t, 1, dps.Len())
+		return dps.At(0).StartTimestamp().AsTime()
+	}
+
+	first := now
+	assert.Equal(t, first, rateStart(newDeltaSum("metric_1", "host-1", 10, now, now.Add(time.Minute))))
+	now = now.Add(time.Minute)
+	// the series keeps the start of its first data point
+	assert.Equal(t, first, rateStart(newDeltaSum("metric_1", "host-1", 10, now, now.Add(time.Minute))))
+	// another series starts with its own first data point
+	assert.Equal(t, now, rateStart(newDeltaSum("metric_1", "host-2", 10, now, now.Add(time.Minute))))
+
+	// a series not seen for the stale timeout restarts
+	now = now.Add(staleSeriesTimeout + time.Minute)
+	assert.Equal(t, now, rateStart(newDeltaSum("metric_1", "host-1", 10, now, now.Add(time.Minute))))
+	assert.Len(t, dtrp.starts.starts, 1)
+}
+
+func TestDeltaToRateProcessorRemovesEmptiedRates(t *testing.T) {
+	start := time.Unix(1000, 0)
+	for _, output := range []string{gaugeOutput, deltaOutput, cumulativeOutput} {
+		t.Run(output, func(t *testing.T) {
+			dtrp := newDeltaToRateProcessor(&Config{
+				Metrics:     []string{"metric_1"},
+				MinInterval: time.Minute,
+				Output:      output,
+			}, zap.NewNop())
+			md, err := dtrp.processMetrics(context.Background(), newDeltaSum("metric_1", "host-1", 10, start, start.Add(time.Second)))
+			require.NoError(t, err)
+			assert.Equal(t, 0, md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().Len())
+		})
+	}
+}
@@ -17,6 +17,7 @@ package metricsgenerationprocessor // import "github.com/open-telemetry/opentele
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"go.opentelemetry.io/collector/config"
 )
@@ -39,6 +40,36 @@ const (
 
 	// operationFieldName is the mapstructure field name for Operation field
 	operationFieldName = "operation"
+
+	// metric1WindowFieldName is the mapstructure field name for Metric1Window field
+	metric1WindowFieldName = "metric1_window"
+
+	// metric2WindowFieldName is the mapstructure field name for Metric2Window field
+	metric2WindowFieldName = "metric2_window"
+
+	// joinOnFieldName is the mapstructure field name for JoinOn field
+	joinOnFieldName = "join_on"
+
+	// sizeFieldName is the mapstructure field name for Size field of a Window
+	sizeFieldName = "size"
+
+	// aggregationFieldName is the mapstructure field name for Aggregation field of a Window
+	aggregationFieldName = "aggregation"
+
+	// metricsExporterFieldName is the mapstructure field name for MetricsExporter field
+	metricsExporterFieldName = "metrics_exporter"
+
+	// signalFieldName is the mapstructure field name for Signal field of a SignalRule
+	signalFieldName = "signal"
+
+	// bucketsFieldName is the mapstructure field name for Buckets field of a SignalRule
+	bucketsFieldName = "buckets"
+
+	// sampleFileFieldName is the mapstructure field name for SampleFile field of a ValidationConfig
+	sampleFileFieldName = "sample_file"
+
+	// expectedFileFieldName is the mapstructure field name for ExpectedFile field of a ValidationConfig
+	expectedFileFieldName = "expected_file"
 )
 
 // Config defines the configuration for the processor.
@@ -47,6 +78,29 @@ type Config struct {
 
 	// Set of rules for generating new metrics
 	Rules []Rule `mapstructure:"rules"`
+
+	// Set of rules for generating metrics from the spans and logs going through the processor.
+	SignalRules []SignalRule `mapstructure:"signal_rules"`
+
+	// The metrics exporter the metrics generated from spans and logs are sent to, as the
+	// processor can't emit them in the traces and logs pipelines. A required field if
+	// signal rules are set.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+
+	// Validation checks the rules against a sample of the metrics when the processor starts.
+	Validation ValidationConfig `mapstructure:"validation"`
+}
+
+// ValidationConfig defines the sample of the metrics going through the processor the rules are
+// checked against when the processor starts, failing its start if they don't apply.
+type ValidationConfig struct {
+	// Path of a file holding a sample of the metrics in the OTLP JSON format. The operands of
+	// every rule must be in the sample.
+	SampleFile string `mapstructure:"sample_file"`
+
+	// Path of a file holding, in the OTLP JSON format, the metrics the rules are expected to
+	// generate from the sample.
+	ExpectedFile string `mapstructure:"expected_file"`
 }
 
 type Rule struct {
@@ -70,6 +124,109 @@ type Rule struct {
 
 	// A constant number by which the first operand will be scaled. A required field if the type is scale.
 	ScaleBy float64 `mapstructure:"scale_by"`
+
+	// Aggregates the previous values of each series of the first operand metric. When set,
+	// the aggregated value is used as first operand instead of the current value.
+	Metric1Window *Window `mapstructure:"metric1_window"`
+
+	// Aggregates the previous values of the second operand metric. When set, the aggregated
+	// value is used as second operand instead of the current value.
+	Metric2Window *Window `mapstructure:"metric2_window"`
+
+	// A resource attribute, e.g. host.name, joining the resources of a batch: the second operand metric
+	// is taken from the resource of the batch having the same value of the attribute as the resource of
+	// the first operand metric, instead of from the same resource.
+	JoinOn string `mapstructure:"join_on"`
+}
+
+// Window defines an aggregation over the last values of a series.
+type Window struct {
+	// Number of values, including the current one, the window holds. This is a required field.
+	Size int `mapstructure:"size"`
+
+	// The aggregation to apply over the values of the window. This is a required field.
+	Aggregation AggregationType `mapstructure:"aggregation"`
+}
+
+// SignalRule defines a metric generated from the spans or logs going through the processor.
+type SignalRule struct {
+	// Name of the new metric being generated. This is a required field.
+	Name string `mapstructure:"name"`
+
+	// Description of the new metric being generated.
+	Description string `mapstructure:"description"`
+
+	// The signal the metric is generated from, spans or logs. This is a required field.
+	Signal SignalType `mapstructure:"signal"`
+
+	// The rule type following which the new metric will be generated. This is a required field.
+	Type SignalMetricType `mapstructure:"type"`
+
+	// Attributes of the spans or log records, or of their resource, used as dimensions of
+	// the new metric. The spans or log records missing an attribute don't have the dimension.
+	Attributes []string `mapstructure:"attributes"`
+
+	// Upper bounds of the buckets of the duration histogram, sorted in increasing order.
+	// Defaults to the buckets of the spanmetrics processor.
+	Buckets []time.Duration `mapstructure:"buckets"`
+}
+
+type SignalType string
+
+const (
+
+	// Generates the metric from the spans
+	spansSignal SignalType = "spans"
+
+	// Generates the metric from the log records
+	logsSignal SignalType = "logs"
+)
+
+var signalTypes = map[SignalType]struct{}{spansSignal: {}, logsSignal: {}}
+
+func (st SignalType) isValid() bool {
+	_, ok := signalTypes[st]
+	return ok
+}
+
+var signalTypeKeys = func() []string {
+	ret := make([]string, len(signalTypes))
+	i := 0
+	for k := range signalTypes {
+		ret[i] = string(k)
+		i++
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+type SignalMetricType string
+
+const (
+
+	// Generates a sum counting the spans or log records
+	count SignalMetricType = "count"
+
+	// Generates a histogram of the duration of the spans
+	duration SignalMetricType = "duration"
+)
+
+var signalMetricTypes = map[SignalMetricType]struct{}{count: {}, duration: {}}
+
+func (smt SignalMetricType) isValid() bool {
+	_, ok := signalMetricTypes[smt]
+	return ok
+}
+
+var signalMetricTypeKeys = func() []string {
+	ret := make([]string, len(signalMetricTypes))
+	i := 0
+	for k := range signalMetricTypes {
+		ret[i] = string(k)
+		i++
+	}
+	sort.Strings(ret)
+	return ret
 }
 
 type GenerationType string
@@ -145,6 +302,42 @@ var operationTypeKeys = func() []string {
 	return ret
 }
 
+type AggregationType string
+
+const (
+
+	// Averages the values of the window
+	average AggregationType = "avg"
+
+	// Takes the minimum value of the window
+	minimum AggregationType = "min"
+
+	// Takes the maximum value of the window
+	maximum AggregationType = "max"
+)
+
+var aggregationTypes = map[AggregationType]struct{}{
+	average: {},
+	minimum: {},
+	maximum: {},
+}
+
+func (at AggregationType) isValid() bool {
+	_, ok := aggregationTypes[at]
+	return ok
+}
+
+var aggregationTypeKeys = func() []string {
+	ret := make([]string, len(aggregationTypes))
+	i := 0
+	for k := range aggregationTypes {
+		ret[i] = string(k)
+		i++
+	}
+	sort.Strings(ret)
+	return ret
+}
+
 // Validate checks whether the input configuration has all of the required fields for the processor.
 // An error is returned if there are any invalid inputs.
 func (config *Config) Validate() error {
@@ -176,6 +369,87 @@ func (config *Config) Validate() error {
 		if rule.Operation != "" && !rule.Operation.isValid() {
 			return fmt.Errorf("%q must be in %q", operationFieldName, operationTypeKeys())
 		}
+
+		if err := rule.Metric1Window.validate(metric1WindowFieldName); err != nil {
+			return err
+		}
+
+		if rule.Type == scale && rule.Metric2Window != nil {
+			return fmt.Errorf("field %q is not supported for generation type %q", metric2WindowFieldName, scale)
+		}
+
+		if err := rule.Metric2Window.validate(metric2WindowFieldName); err != nil {
+			return err
+		}
+
+		if rule.Type == scale && rule.JoinOn != "" {
+			return fmt.Errorf("field %q is not supported for generation type %q", joinOnFieldName, scale)
+		}
+	}
+
+	if config.Validation.ExpectedFile != "" && config.Validation.SampleFile == "" {
+		return fmt.Errorf("missing required field %q for field %q", sampleFileFieldName, expectedFileFieldName)
+	}
+
+	if len(config.SignalRules) > 0 && config.MetricsExporter == "" {
+		return fmt.Errorf("missing required field %q for signal rules", metricsExporterFieldName)
+	}
+	for _, rule := range config.SignalRules {
+		if err := rule.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rule *SignalRule) validate() error {
+	if rule.Name == "" {
+		return fmt.Errorf("missing required field %q", nameFieldName)
+	}
+
+	if rule.Signal == "" {
+		return fmt.Errorf("missing required field %q", signalFieldName)
+	}
+
+	if !rule.Signal.isValid() {
+		return fmt.Errorf("%q must be in %q", signalFieldName, signalTypeKeys())
+	}
+
+	if rule.Type == "" {
+		return fmt.Errorf("missing required field %q", typeFieldName)
+	}
+
+	if !rule.Type.isValid() {
+		return fmt.Errorf("%q must be in %q", typeFieldName, signalMetricTypeKeys())
+	}
+
+	if rule.Type == duration && rule.Signal != spansSignal {
+		return fmt.Errorf("generation type %q is only supported for signal %q", duration, spansSignal)
+	}
+
+	if rule.Type != duration && len(rule.Buckets) > 0 {
+		return fmt.Errorf("field %q is only supported for generation type %q", bucketsFieldName, duration)
+	}
+
+	for i := 1; i < len(rule.Buckets); i++ {
+		if rule.Buckets[i] <= rule.Buckets[i-1] {
+			return fmt.Errorf("field %q must be sorted in increasing order", bucketsFieldName)
+		}
+	}
+	return nil
+}
+
+func (w *Window) validate(fieldName string) error {
+	if w == nil {
+		return nil
+	}
+
+	if w.Size <= 0 {
+		return fmt.Errorf("field %q of %q required to be greater than 0", sizeFieldName, fieldName)
+	}
+
+	if !w.Aggregation.isValid() {
+		return fmt.Errorf("%q of %q must be in %q", aggregationFieldName, fieldName, aggregationTypeKeys())
 	}
 	return nil
 }
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -58,6 +59,107 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			id: component.NewIDWithName(typeStr, "window"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				Rules: []Rule{
+					{
+						Name:          "new_metric",
+						Type:          "calculate",
+						Metric1:       "metric1",
+						Metric1Window: &Window{Size: 5, Aggregation: "avg"},
+						Metric2:       "metric2",
+						Metric2Window: &Window{Size: 3, Aggregation: "max"},
+						Operation:     "percent",
+					},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "join"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				Rules: []Rule{
+					{
+						Name:      "new_metric",
+						Type:      "calculate",
+						Metric1:   "metric1",
+						Metric2:   "metric2",
+						Operation: "percent",
+						JoinOn:    "k8s.node.name",
+					},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "signal_rules"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				MetricsExporter:   "otlp/metrics",
+				SignalRules: []SignalRule{
+					{
+						Name:       "span.count",
+						Signal:     "spans",
+						Type:       "count",
+						Attributes: []string{"service.name", "http.method"},
+					},
+					{
+						Name:        "span.duration",
+						Description: "Duration of the spans",
+						Signal:      "spans",
+						Type:        "duration",
+						Attributes:  []string{"service.name"},
+						Buckets:     []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second},
+					},
+					{
+						Name:       "log.count",
+						Signal:     "logs",
+						Type:       "count",
+						Attributes: []string{"severity"},
+					},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "validation"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				Rules: []Rule{
+					{
+						Name:      "pod.cpu.utilized",
+						Type:      "calculate",
+						Metric1:   "pod.cpu.usage",
+						Metric2:   "node.cpu.limit",
+						Operation: "percent",
+					},
+				},
+				Validation: ValidationConfig{
+					SampleFile:   "testdata/validation/sample.json",
+					ExpectedFile: "testdata/validation/expected.json",
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "missing_sample_file"),
+			errorMessage: fmt.Sprintf("missing required field %q for field %q", sampleFileFieldName, expectedFileFieldName),
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "missing_metrics_exporter"),
+			errorMessage: fmt.Sprintf("missing required field %q for signal rules", metricsExporterFieldName),
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "invalid_signal"),
+			errorMessage: fmt.Sprintf("%q must be in %q", signalFieldName, signalTypeKeys()),
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "log_duration"),
+			errorMessage: fmt.Sprintf("generation type %q is only supported for signal %q", duration, spansSignal),
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "unsorted_buckets"),
+			errorMessage: fmt.Sprintf("field %q must be sorted in increasing order", bucketsFieldName),
+		},
 		{
 			id:           component.NewIDWithName(typeStr, "missing_new_metric"),
 			errorMessage: fmt.Sprintf("missing required field %q", nameFieldName),
@@ -86,6 +188,18 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(typeStr, "invalid_operation"),
 			errorMessage: fmt.Sprintf("%q must be in %q", operationFieldName, operationTypeKeys()),
 		},
+		{
+			id:           component.NewIDWithName(typeStr, "invalid_join"),
+			errorMessage: fmt.Sprintf("field %q is not supported for generation type %q", joinOnFieldName, scale),
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "invalid_window_size"),
+			errorMessage: fmt.Sprintf("field %q of %q required to be greater than 0", sizeFieldName, metric1WindowFieldName),
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "invalid_window_aggregation"),
+			errorMessage: fmt.Sprintf("%q of %q must be in %q", aggregationFieldName, metric2WindowFieldName, aggregationTypeKeys()),
+		},
 	}
 
 	for _, tt := range tests {
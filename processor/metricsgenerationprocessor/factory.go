@@ -38,7 +38,9 @@ func NewFactory() component.ProcessorFactory {
 	return component.NewProcessorFactory(
 		typeStr,
 		createDefaultConfig,
-		component.WithMetricsProcessor(createMetricsProcessor, stability))
+		component.WithMetricsProcessor(createMetricsProcessor, stability),
+		component.WithTracesProcessor(createTracesProcessor, stability),
+		component.WithLogsProcessor(createLogsProcessor, stability))
 }
 
 func createDefaultConfig() component.ProcessorConfig {
@@ -60,13 +62,66 @@ func createMetricsProcessor(
 
 	metricsProcessor := newMetricsGenerationProcessor(buildInternalConfig(processorConfig), set.Logger)
 
+	opts := []processorhelper.Option{processorhelper.WithCapabilities(processorCapabilities)}
+	if processorConfig.Validation.SampleFile != "" {
+		opts = append(opts, processorhelper.WithStart(func(context.Context, component.Host) error {
+			return validateRules(processorConfig)
+		}))
+	}
+
 	return processorhelper.NewMetricsProcessor(
 		ctx,
 		set,
 		cfg,
 		nextConsumer,
 		metricsProcessor.processMetrics,
-		processorhelper.WithCapabilities(processorCapabilities))
+		opts...)
+}
+
+func createTracesProcessor(
+	ctx context.Context,
+	set component.ProcessorCreateSettings,
+	cfg component.ProcessorConfig,
+	nextConsumer consumer.Traces,
+) (component.TracesProcessor, error) {
+	processorConfig, ok := cfg.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("configuration parsing error")
+	}
+
+	generator := newSignalMetricsGenerator(processorConfig, spansSignal, set.Logger)
+
+	return processorhelper.NewTracesProcessor(
+		ctx,
+		set,
+		cfg,
+		nextConsumer,
+		generator.processTraces,
+		processorhelper.WithStart(generator.start),
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}))
+}
+
+func createLogsProcessor(
+	ctx context.Context,
+	set component.ProcessorCreateSettings,
+	cfg component.ProcessorConfig,
+	nextConsumer consumer.Logs,
+) (component.LogsProcessor, error) {
+	processorConfig, ok := cfg.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("configuration parsing error")
+	}
+
+	generator := newSignalMetricsGenerator(processorConfig, logsSignal, set.Logger)
+
+	return processorhelper.NewLogsProcessor(
+		ctx,
+		set,
+		cfg,
+		nextConsumer,
+		generator.processLogs,
+		processorhelper.WithStart(generator.start),
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}))
 }
 
 // buildInternalConfig constructs the internal metric generation rules
@@ -82,6 +137,10 @@ func buildInternalConfig(config *Config) []internalRule {
 			metric2:   rule.Metric2,
 			operation: string(rule.Operation),
 			scaleBy:   rule.ScaleBy,
+
+			metric1Window: newSlidingWindow(rule.Metric1Window),
+			metric2Window: newSlidingWindow(rule.Metric2Window),
+			joinOn:        rule.JoinOn,
 		}
 		internalRules[i] = customRule
 	}
@@ -65,9 +65,16 @@ func TestCreateProcessors(t *testing.T) {
 				componenttest.NewNopProcessorCreateSettings(),
 				cfg,
 				consumertest.NewNop())
-			// Not implemented error
-			assert.Error(t, tErr)
-			assert.Nil(t, tp)
+			assert.NotNil(t, tp)
+			assert.NoError(t, tErr)
+
+			lp, lErr := factory.CreateLogsProcessor(
+				context.Background(),
+				componenttest.NewNopProcessorCreateSettings(),
+				cfg,
+				consumertest.NewNop())
+			assert.NotNil(t, lp)
+			assert.NoError(t, lErr)
 
 			mp, mErr := factory.CreateMetricsProcessor(
 				context.Background(),
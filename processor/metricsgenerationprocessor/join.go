@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsgenerationprocessor"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// joinedMetric is a metric with the resource it was received for.
+type joinedMetric struct {
+	metric   pmetric.Metric
+	resource pcommon.Resource
+}
+
+// joinIndex maps each value of a resource attribute to the metrics of the resources of a batch
+// having it, by name. The first resource of the batch having a metric wins.
+type joinIndex map[string]map[string]joinedMetric
+
+func newJoinIndex(rms pmetric.ResourceMetricsSlice, attribute string) joinIndex {
+	index := joinIndex{}
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		value, ok := rm.Resource().Attributes().Get(attribute)
+		if !ok {
+			continue
+		}
+		metrics, ok := index[value.AsString()]
+		if !ok {
+			metrics = map[string]joinedMetric{}
+			index[value.AsString()] = metrics
+		}
+		for name, metric := range getNameToMetricMap(rm) {
+			if _, ok := metrics[name]; !ok {
+				metrics[name] = joinedMetric{metric: metric, resource: rm.Resource()}
+			}
+		}
+	}
+	return index
+}
+
+// lookup returns the metric of the resources of the batch sharing the value of the attribute
+// of the given resource.
+func (index joinIndex) lookup(resource pcommon.Resource, attribute, name string) (joinedMetric, bool) {
+	value, ok := resource.Attributes().Get(attribute)
+	if !ok {
+		return joinedMetric{}, false
+	}
+	metric, ok := index[value.AsString()][name]
+	return metric, ok
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// appendJoinResource appends a resource with a gauge to the metrics, the node attribute is set when not empty.
+func appendJoinResource(md pmetric.Metrics, node, name string, value float64) {
+	rm := md.ResourceMetrics().AppendEmpty()
+	if node != "" {
+		rm.Resource().Attributes().PutStr("k8s.node.name", node)
+	}
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(value)
+}
+
+func TestMetricsGenerationProcessorJoin(t *testing.T) {
+	next := new(consumertest.MetricsSink)
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+		Rules: []Rule{
+			{
+				Name:      "pod.memory.node.utilization",
+				Type:      "calculate",
+				Metric1:   "pod.memory.usage",
+				Metric2:   "node.memory.capacity",
+				Operation: "percent",
+				JoinOn:    "k8s.node.name",
+			},
+		},
+	}
+	mgp, err := NewFactory().CreateMetricsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, mgp.Start(context.Background(), componenttest.NewNopHost()))
+
+	md := pmetric.NewMetrics()
+	appendJoinResource(md, "node-1", "pod.memory.usage", 250)
+	appendJoinResource(md, "node-1", "node.memory.capacity", 1000)
+	// only the first resource having the metric is joined
+	appendJoinResource(md, "node-1", "node.memory.capacity", 2000)
+	appendJoinResource(md, "node-1", "pod.memory.usage", 500)
+	// no resource of the batch has the capacity of the node
+	appendJoinResource(md, "node-2", "pod.memory.usage", 100)
+	// the resource can't be joined without the attribute
+	appendJoinResource(md, "", "pod.memory.usage", 100)
+	require.NoError(t, mgp.ConsumeMetrics(context.Background(), md))
+
+	require.Len(t, next.AllMetrics(), 1)
+	rms := next.AllMetrics()[0].ResourceMetrics()
+	var actual []float64
+	for i := 0; i < rms.Len(); i++ {
+		metrics := rms.At(i).ScopeMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			if metrics.At(j).Name() == "pod.memory.node.utilization" {
+				actual = append(actual, metrics.At(j).Gauge().DataPoints().At(0).DoubleValue())
+			}
+		}
+	}
+	assert.Equal(t, []float64{25, 50}, actual)
+	require.NoError(t, mgp.Shutdown(context.Background()))
+}
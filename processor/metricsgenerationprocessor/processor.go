@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 )
@@ -35,6 +36,11 @@ type internalRule struct {
 	metric2   string
 	operation string
 	scaleBy   float64
+	// metric1Window and metric2Window hold the previous values of the operands, when configured.
+	metric1Window *slidingWindow
+	metric2Window *slidingWindow
+	// joinOn is the resource attribute joining the resource of metric1 with the one of metric2, when set.
+	joinOn string
 }
 
 func newMetricsGenerationProcessor(rules []internalRule, logger *zap.Logger) *metricsGenerationProcessor {
@@ -52,6 +58,8 @@ func (mgp *metricsGenerationProcessor) Start(context.Context, component.Host) er
 // processMetrics implements the ProcessMetricsFunc type.
 func (mgp *metricsGenerationProcessor) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
 	resourceMetricsSlice := md.ResourceMetrics()
+	// the join indexes of the batch, by attribute, are built when a rule needs them
+	joins := map[string]joinIndex{}
 
 	for i := 0; i < resourceMetricsSlice.Len(); i++ {
 		rm := resourceMetricsSlice.At(i)
@@ -66,12 +74,26 @@ func (mgp *metricsGenerationProcessor) processMetrics(_ context.Context, md pmet
 			}
 
 			if rule.ruleType == string(calculate) {
-				metric2, ok := nameToMetricMap[rule.metric2]
+				metric2, ok := joinedMetric{metric: nameToMetricMap[rule.metric2], resource: rm.Resource()}, false
+				if rule.joinOn == "" {
+					_, ok = nameToMetricMap[rule.metric2]
+				} else {
+					index, built := joins[rule.joinOn]
+					if !built {
+						index = newJoinIndex(resourceMetricsSlice, rule.joinOn)
+						joins[rule.joinOn] = index
+					}
+					metric2, ok = index.lookup(rm.Resource(), rule.joinOn, rule.metric2)
+				}
 				if !ok {
 					mgp.logger.Debug("Missing second metric", zap.String("metric_name", rule.metric2))
 					continue
 				}
-				operand2 = getMetricValue(metric2)
+				operand2 = getMetricValue(metric2.metric)
+				if rule.metric2Window != nil {
+					key := seriesKey(metric2.resource.Attributes(), rule.metric2, pcommon.NewMap())
+					operand2 = rule.metric2Window.add(key, operand2)
+				}
 				if operand2 <= 0 {
 					continue
 				}
@@ -0,0 +1,334 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsgenerationprocessor"
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// scopeName is the name of the instrumentation scope of the metrics generated from spans and logs.
+const scopeName = "otelcol/metricsgenerationprocessor"
+
+// defaultDurationBuckets are the buckets of the duration histograms when none is configured,
+// the same as the spanmetrics processor.
+var defaultDurationBuckets = []time.Duration{
+	2 * time.Millisecond,
+	4 * time.Millisecond,
+	6 * time.Millisecond,
+	8 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	400 * time.Millisecond,
+	800 * time.Millisecond,
+	1 * time.Second,
+	1400 * time.Millisecond,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	15 * time.Second,
+}
+
+// signalMetricsGenerator generates metrics from the spans or logs going through the processor,
+// following the signal rules of the signal, and sends them to the configured metrics exporter.
+//
+// The metrics are aggregated per batch and sent with the delta temporality, nothing is kept
+// between the batches but the end time of the previous batch, used as start time of the next.
+type signalMetricsGenerator struct {
+	logger          *zap.Logger
+	exporterName    string
+	rules           []signalRule
+	metricsExporter component.MetricsExporter
+
+	mu            sync.Mutex
+	lastTimestamp pcommon.Timestamp
+	// now returns the processing time, it is replaced by the tests.
+	now func() time.Time
+}
+
+type signalRule struct {
+	SignalRule
+	// bounds are the explicit bounds of the duration histogram, in milliseconds.
+	bounds []float64
+}
+
+func newSignalMetricsGenerator(config *Config, signal SignalType, logger *zap.Logger) *signalMetricsGenerator {
+	g := &signalMetricsGenerator{
+		logger:       logger,
+		exporterName: config.MetricsExporter,
+		now:          time.Now,
+	}
+	for _, rule := range config.SignalRules {
+		if rule.Signal != signal {
+			continue
+		}
+		r := signalRule{SignalRule: rule}
+		if rule.Type == duration {
+			buckets := rule.Buckets
+			if len(buckets) == 0 {
+				buckets = defaultDurationBuckets
+			}
+			for _, bucket := range buckets {
+				r.bounds = append(r.bounds, float64(bucket)/float64(time.Millisecond))
+			}
+		}
+		g.rules = append(g.rules, r)
+	}
+	return g
+}
+
+// start looks up the metrics exporter, among the exporters of the metrics pipelines.
+func (g *signalMetricsGenerator) start(_ context.Context, host component.Host) error {
+	if len(g.rules) == 0 {
+		return nil
+	}
+	var available []string
+	for id, exp := range host.GetExporters()[component.DataTypeMetrics] {
+		if id.String() != g.exporterName {
+			available = append(available, id.String())
+			continue
+		}
+		metricsExp, ok := exp.(component.MetricsExporter)
+		if !ok {
+			return fmt.Errorf("the exporter %q isn't a metrics exporter", g.exporterName)
+		}
+		g.metricsExporter = metricsExp
+		return nil
+	}
+	sort.Strings(available)
+	return fmt.Errorf("failed to find metrics exporter %q; please configure %s from one of: %q",
+		g.exporterName, metricsExporterFieldName, available)
+}
+
+// processTraces implements the ProcessTracesFunc type.
+func (g *signalMetricsGenerator) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	if len(g.rules) == 0 {
+		return td, nil
+	}
+	b := &signalBatch{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		aggs := g.newAggregations()
+		ilss := rs.ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				elapsed := float64(span.EndTimestamp()-span.StartTimestamp()) / float64(time.Millisecond)
+				for _, agg := range aggs {
+					agg.add(span.Attributes(), rs.Resource().Attributes(), elapsed)
+				}
+			}
+		}
+		b.add(rs.Resource(), aggs)
+	}
+	g.export(ctx, b)
+	return td, nil
+}
+
+// processLogs implements the ProcessLogsFunc type.
+func (g *signalMetricsGenerator) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	if len(g.rules) == 0 {
+		return ld, nil
+	}
+	b := &signalBatch{}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		aggs := g.newAggregations()
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			logs := sls.At(j).LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				for _, agg := range aggs {
+					agg.add(logs.At(k).Attributes(), rl.Resource().Attributes(), 0)
+				}
+			}
+		}
+		b.add(rl.Resource(), aggs)
+	}
+	g.export(ctx, b)
+	return ld, nil
+}
+
+// export sends the metrics generated from the batch to the metrics exporter. A failure is
+// logged, the spans and logs are still passed to the next consumer.
+func (g *signalMetricsGenerator) export(ctx context.Context, b *signalBatch) {
+	if len(b.resources) == 0 {
+		return
+	}
+	md := b.metrics(g.timestamps())
+	if err := g.metricsExporter.ConsumeMetrics(ctx, md); err != nil {
+		g.logger.Error("Failed to export the generated metrics",
+			zap.String(metricsExporterFieldName, g.exporterName), zap.Error(err))
+	}
+}
+
+// timestamps returns the start and end times of the batch being processed.
+func (g *signalMetricsGenerator) timestamps() (pcommon.Timestamp, pcommon.Timestamp) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	end := pcommon.NewTimestampFromTime(g.now())
+	start := g.lastTimestamp
+	if start == 0 {
+		start = end
+	}
+	g.lastTimestamp = end
+	return start, end
+}
+
+func (g *signalMetricsGenerator) newAggregations() []*signalAggregation {
+	aggs := make([]*signalAggregation, len(g.rules))
+	for i := range g.rules {
+		aggs[i] = &signalAggregation{rule: &g.rules[i], series: map[string]*signalSeries{}}
+	}
+	return aggs
+}
+
+// signalBatch holds the aggregations of the resources of a batch of spans or logs.
+type signalBatch struct {
+	resources []pcommon.Resource
+	aggs      [][]*signalAggregation
+}
+
+// add adds the aggregations of the spans or logs of the resource, when any matched.
+func (b *signalBatch) add(resource pcommon.Resource, aggs []*signalAggregation) {
+	for _, agg := range aggs {
+		if len(agg.keys) > 0 {
+			b.resources = append(b.resources, resource)
+			b.aggs = append(b.aggs, aggs)
+			return
+		}
+	}
+}
+
+// metrics returns the metrics of the batch, covering the time from start to end.
+func (b *signalBatch) metrics(start, end pcommon.Timestamp) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for i, resource := range b.resources {
+		rm := md.ResourceMetrics().AppendEmpty()
+		resource.CopyTo(rm.Resource())
+		sm := rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(scopeName)
+		for _, agg := range b.aggs[i] {
+			if len(agg.keys) > 0 {
+				agg.appendMetric(sm.Metrics(), start, end)
+			}
+		}
+	}
+	return md
+}
+
+// signalAggregation aggregates the spans or logs of a resource following a rule.
+type signalAggregation struct {
+	rule *signalRule
+	// keys holds the keys of series in the order they were created.
+	keys   []string
+	series map[string]*signalSeries
+}
+
+// signalSeries is the aggregation of the spans or logs with the same dimensions.
+type signalSeries struct {
+	attributes   pcommon.Map
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+// add aggregates a span or log record with its attributes, and the ones of its resource,
+// elapsed is the duration of the span in milliseconds.
+func (a *signalAggregation) add(attributes, resourceAttributes pcommon.Map, elapsed float64) {
+	dims := pcommon.NewMap()
+	var key strings.Builder
+	for _, name := range a.rule.Attributes {
+		v, ok := attributes.Get(name)
+		if !ok {
+			v, ok = resourceAttributes.Get(name)
+		}
+		if !ok {
+			key.WriteString("\x00")
+			continue
+		}
+		v.CopyTo(dims.PutEmpty(name))
+		key.WriteString("\x01")
+		key.WriteString(v.AsString())
+		key.WriteString("\x00")
+	}
+
+	s, ok := a.series[key.String()]
+	if !ok {
+		s = &signalSeries{attributes: dims}
+		if a.rule.Type == duration {
+			s.bucketCounts = make([]uint64, len(a.rule.bounds)+1)
+		}
+		a.series[key.String()] = s
+		a.keys = append(a.keys, key.String())
+	}
+	s.count++
+	if a.rule.Type == duration {
+		s.sum += elapsed
+		s.bucketCounts[sort.SearchFloat64s(a.rule.bounds, elapsed)]++
+	}
+}
+
+func (a *signalAggregation) appendMetric(metrics pmetric.MetricSlice, start, end pcommon.Timestamp) {
+	m := metrics.AppendEmpty()
+	m.SetName(a.rule.Name)
+	m.SetDescription(a.rule.Description)
+	switch a.rule.Type {
+	case duration:
+		m.SetUnit("ms")
+		hist := m.SetEmptyHistogram()
+		hist.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		for _, key := range a.keys {
+			s := a.series[key]
+			dp := hist.DataPoints().AppendEmpty()
+			dp.SetStartTimestamp(start)
+			dp.SetTimestamp(end)
+			s.attributes.CopyTo(dp.Attributes())
+			dp.SetCount(s.count)
+			dp.SetSum(s.sum)
+			dp.ExplicitBounds().FromRaw(a.rule.bounds)
+			dp.BucketCounts().FromRaw(s.bucketCounts)
+		}
+	default:
+		m.SetUnit("1")
+		sum := m.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		for _, key := range a.keys {
+			s := a.series[key]
+			dp := sum.DataPoints().AppendEmpty()
+			dp.SetStartTimestamp(start)
+			dp.SetTimestamp(end)
+			s.attributes.CopyTo(dp.Attributes())
+			dp.SetIntValue(int64(s.count))
+		}
+	}
+}
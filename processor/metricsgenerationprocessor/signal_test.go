@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// mockMetricsExporter is a metrics exporter collecting the metrics it receives.
+type mockMetricsExporter struct {
+	consumertest.MetricsSink
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+// failingMetricsExporter is a metrics exporter failing to export the metrics.
+type failingMetricsExporter struct {
+	consumer.Metrics
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+// mockHost is a host with a metrics exporter.
+type mockHost struct {
+	component.Host
+	exporters map[component.DataType]map[component.ID]component.Exporter
+}
+
+func (h *mockHost) GetExporters() map[component.DataType]map[component.ID]component.Exporter {
+	return h.exporters
+}
+
+func newMockHost(exp component.Exporter) component.Host {
+	return &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[component.DataType]map[component.ID]component.Exporter{
+			component.DataTypeMetrics: {component.NewIDWithName("otlp", "metrics"): exp},
+		},
+	}
+}
+
+func newSignalConfig(rules ...SignalRule) *Config {
+	return &Config{
+		ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+		MetricsExporter:   "otlp/metrics",
+		SignalRules:       rules,
+	}
+}
+
+func TestSignalMetricsGeneratorStart(t *testing.T) {
+	cfg := newSignalConfig(SignalRule{Name: "span.count", Signal: spansSignal, Type: count})
+
+	g := newSignalMetricsGenerator(cfg, spansSignal, zap.NewNop())
+	assert.NoError(t, g.start(context.Background(), newMockHost(&mockMetricsExporter{})))
+	assert.NotNil(t, g.metricsExporter)
+
+	cfg.MetricsExporter = "prometheus"
+	g = newSignalMetricsGenerator(cfg, spansSignal, zap.NewNop())
+	assert.EqualError(t, g.start(context.Background(), newMockHost(&mockMetricsExporter{})),
+		`failed to find metrics exporter "prometheus"; please configure metrics_exporter from one of: ["otlp/metrics"]`)
+
+	// the exporter isn't needed without rule for the signal
+	g = newSignalMetricsGenerator(cfg, logsSignal, zap.NewNop())
+	assert.NoError(t, g.start(context.Background(), componenttest.NewNopHost()))
+}
+
+func TestSignalMetricsGeneratorTraces(t *testing.T) {
+	cfg := newSignalConfig(
+		SignalRule{
+			Name:       "span.count",
+			Signal:     spansSignal,
+			Type:       count,
+			Attributes: []string{"service.name", "http.method"},
+		},
+		SignalRule{
+			Name:       "span.duration",
+			Signal:     spansSignal,
+			Type:       duration,
+			Attributes: []string{"service.name"},
+			Buckets:    []time.Duration{10 * time.Millisecond, 100 * time.Millisecond},
+		},
+		SignalRule{Name: "log.count", Signal: logsSignal, Type: count},
+	)
+	exp := &mockMetricsExporter{}
+	g := newSignalMetricsGenerator(cfg, spansSignal, zap.NewNop())
+	require.NoError(t, g.start(context.Background(), newMockHost(exp)))
+	now := time.Unix(1000, 0)
+	g.now = func() time.Time { return now }
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	for _, s := range []struct {
+		method  string
+		elapsed time.Duration
+	}{
+		{"GET", 5 * time.Millisecond},
+		{"GET", 10 * time.Millisecond},
+		{"POST", 50 * time.Millisecond},
+		{"", time.Second},
+	} {
+		span := spans.AppendEmpty()
+		if s.method != "" {
+			span.Attributes().PutStr("http.method", s.method)
+		}
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(now))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(now.Add(s.elapsed)))
+	}
+	// a resource without spans doesn't generate metrics
+	td.ResourceSpans().AppendEmpty()
+
+	out, err := g.processTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, td, out)
+
+	require.Len(t, exp.AllMetrics(), 1)
+	md := exp.AllMetrics()[0]
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	rm := md.ResourceMetrics().At(0)
+	assert.Equal(t, map[string]interface{}{"service.name": "checkout"}, rm.Resource().Attributes().AsRaw())
+	sm := rm.ScopeMetrics().At(0)
+	assert.Equal(t, scopeName, sm.Scope().Name())
+	require.Equal(t, 2, sm.Metrics().Len())
+
+	counts := sm.Metrics().At(0)
+	assert.Equal(t, "span.count", counts.Name())
+	assert.Equal(t, "1", counts.Unit())
+	require.Equal(t, pmetric.MetricTypeSum, counts.Type())
+	assert.True(t, counts.Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, counts.Sum().AggregationTemporality())
+	dps := counts.Sum().DataPoints()
+	require.Equal(t, 3, dps.Len())
+	assert.Equal(t, map[string]interface{}{"service.name": "checkout", "http.method": "GET"}, dps.At(0).Attributes().AsRaw())
+	assert.Equal(t, int64(2), dps.At(0).IntValue())
+	assert.Equal(t, map[string]interface{}{"service.name": "checkout", "http.method": "POST"}, dps.At(1).Attributes().AsRaw())
+	assert.Equal(t, int64(1), dps.At(1).IntValue())
+	assert.Equal(t, map[string]interface{}{"service.name": "checkout"}, dps.At(2).Attributes().AsRaw())
+	assert.Equal(t, int64(1), dps.At(2).IntValue())
+	assert.Equal(t, pcommon.NewTimestampFromTime(now), dps.At(0).StartTimestamp())
+	assert.Equal(t, pcommon.NewTimestampFromTime(now), dps.At(0).Timestamp())
+
+	durations := sm.Metrics().At(1)
+	assert.Equal(t, "span.duration", durations.Name())
+	assert.Equal(t, "ms", durations.Unit())
+	require.Equal(t, pmetric.MetricTypeHistogram, durations.Type())
+	require.Equal(t, 1, durations.Histogram().DataPoints().Len())
+	dp := durations.Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(4), dp.Count())
+	assert.Equal(t, float64(1065), dp.Sum())
+	assert.Equal(t, []float64{10, 100}, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{2, 1, 1}, dp.BucketCounts().AsRaw())
+
+	// the next batch starts where the previous one ended
+	later := now.Add(time.Minute)
+	g.now = func() time.Time { return later }
+	_, err = g.processTraces(context.Background(), td)
+	require.NoError(t, err)
+	require.Len(t, exp.AllMetrics(), 2)
+	dp = exp.AllMetrics()[1].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(1).Histogram().DataPoints().At(0)
+	assert.Equal(t, pcommon.NewTimestampFromTime(now), dp.StartTimestamp())
+	assert.Equal(t, pcommon.NewTimestampFromTime(later), dp.Timestamp())
+}
+
+func TestSignalMetricsGeneratorLogs(t *testing.T) {
+	cfg := newSignalConfig(SignalRule{
+		Name:       "log.count",
+		Signal:     logsSignal,
+		Type:       count,
+		Attributes: []string{"severity"},
+	})
+	exp := &mockMetricsExporter{}
+	g := newSignalMetricsGenerator(cfg, logsSignal, zap.NewNop())
+	require.NoError(t, g.start(context.Background(), newMockHost(exp)))
+
+	ld := plog.NewLogs()
+	logs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, severity := range []string{"error", "info", "error"} {
+		logs.AppendEmpty().Attributes().PutStr("severity", severity)
+	}
+
+	out, err := g.processLogs(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, ld, out)
+
+	require.Len(t, exp.AllMetrics(), 1)
+	dps := exp.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, map[string]interface{}{"severity": "error"}, dps.At(0).Attributes().AsRaw())
+	assert.Equal(t, int64(2), dps.At(0).IntValue())
+	assert.Equal(t, map[string]interface{}{"severity": "info"}, dps.At(1).Attributes().AsRaw())
+	assert.Equal(t, int64(1), dps.At(1).IntValue())
+
+	// no metric is sent for an empty batch
+	_, err = g.processLogs(context.Background(), plog.NewLogs())
+	require.NoError(t, err)
+	assert.Len(t, exp.AllMetrics(), 1)
+}
+
+func TestSignalMetricsGeneratorExportFailure(t *testing.T) {
+	cfg := newSignalConfig(SignalRule{Name: "log.count", Signal: logsSignal, Type: count})
+	g := newSignalMetricsGenerator(cfg, logsSignal, zap.NewNop())
+	exp := &failingMetricsExporter{Metrics: consumertest.NewErr(errors.New("unavailable"))}
+	require.NoError(t, g.start(context.Background(), newMockHost(exp)))
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+
+	// the logs are passed on even if the generated metrics can't be exported
+	out, err := g.processLogs(context.Background(), ld)
+	assert.NoError(t, err)
+	assert.Equal(t, ld, out)
+}
@@ -15,6 +15,7 @@
 package metricsgenerationprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsgenerationprocessor"
 
 import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 )
@@ -64,13 +65,13 @@ func generateMetrics(rm pmetric.ResourceMetrics, operand2 float64, rule internal
 			if metric.Name() == rule.metric1 {
 				newMetric := appendMetric(ilm, rule.name, rule.unit)
 				newMetric.SetEmptyGauge()
-				addDoubleGaugeDataPoints(metric, newMetric, operand2, rule.operation, logger)
+				addDoubleGaugeDataPoints(rm.Resource(), metric, newMetric, operand2, rule, logger)
 			}
 		}
 	}
 }
 
-func addDoubleGaugeDataPoints(from pmetric.Metric, to pmetric.Metric, operand2 float64, operation string, logger *zap.Logger) {
+func addDoubleGaugeDataPoints(resource pcommon.Resource, from pmetric.Metric, to pmetric.Metric, operand2 float64, rule internalRule, logger *zap.Logger) {
 	dataPoints := from.Gauge().DataPoints()
 	for i := 0; i < dataPoints.Len(); i++ {
 		fromDataPoint := dataPoints.At(i)
@@ -81,10 +82,14 @@ func addDoubleGaugeDataPoints(from pmetric.Metric, to pmetric.Metric, operand2 f
 		case pmetric.NumberDataPointValueTypeInt:
 			operand1 = float64(fromDataPoint.IntValue())
 		}
+		if rule.metric1Window != nil {
+			key := seriesKey(resource.Attributes(), from.Name(), fromDataPoint.Attributes())
+			operand1 = rule.metric1Window.add(key, operand1)
+		}
 
 		neweDoubleDataPoint := to.Gauge().DataPoints().AppendEmpty()
 		fromDataPoint.CopyTo(neweDoubleDataPoint)
-		value := calculateValue(operand1, operand2, operation, logger, to.Name())
+		value := calculateValue(operand1, operand2, rule.operation, logger, to.Name())
 		neweDoubleDataPoint.SetDoubleValue(value)
 	}
 }
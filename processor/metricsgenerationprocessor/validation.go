@@ -0,0 +1,220 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsgenerationprocessor"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// validateRules checks the operands of the rules are in the sample of the validation config, and
+// that the rules generate the expected metrics from the sample when the expected file is set.
+// The returned error reports the differences line by line, as a diff.
+func validateRules(config *Config) error {
+	sample, err := readMetrics(config.Validation.SampleFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the validation sample: %w", err)
+	}
+
+	var report []string
+	names := metricNames(sample)
+	for _, rule := range config.Rules {
+		report = append(report, missingOperand(rule.Name, metric1FieldName, rule.Metric1, names)...)
+		if rule.Type == calculate {
+			report = append(report, missingOperand(rule.Name, metric2FieldName, rule.Metric2, names)...)
+		}
+	}
+	if len(report) > 0 {
+		return fmt.Errorf("rule operands missing from the validation sample %q:\n%s",
+			config.Validation.SampleFile, strings.Join(report, "\n"))
+	}
+
+	if config.Validation.ExpectedFile == "" {
+		return nil
+	}
+	expected, err := readMetrics(config.Validation.ExpectedFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the expected metrics: %w", err)
+	}
+	// the rules are applied to the sample with their own windows, not sharing them with the processor
+	generated, err := newMetricsGenerationProcessor(buildInternalConfig(config), zap.NewNop()).processMetrics(context.Background(), sample)
+	if err != nil {
+		return err
+	}
+	ruleNames := map[string]bool{}
+	for _, rule := range config.Rules {
+		ruleNames[rule.Name] = true
+	}
+	report = diffValues(dataPointValues(expected, nil), dataPointValues(generated, ruleNames))
+	if len(report) > 0 {
+		return fmt.Errorf("generated metrics differing from the expected metrics %q (-expected +generated):\n%s",
+			config.Validation.ExpectedFile, strings.Join(report, "\n"))
+	}
+	return nil
+}
+
+func readMetrics(path string) (pmetric.Metrics, error) {
+	if path == "" {
+		return pmetric.Metrics{}, errors.New("no file set")
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return pmetric.Metrics{}, err
+	}
+	unmarshaler := &pmetric.JSONUnmarshaler{}
+	return unmarshaler.UnmarshalMetrics(b)
+}
+
+func metricNames(md pmetric.Metrics) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		for name := range getNameToMetricMap(md.ResourceMetrics().At(i)) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// missingOperand reports the operand of a rule missing from the sample, with the closest name
+// of the sample, if any, as replacement.
+func missingOperand(ruleName, field, operand string, names map[string]bool) []string {
+	if names[operand] {
+		return nil
+	}
+	report := []string{
+		fmt.Sprintf("rule %q: %s", ruleName, field),
+		"- " + operand,
+	}
+	if closest := closestName(operand, names); closest != "" {
+		report = append(report, "+ "+closest)
+	}
+	return report
+}
+
+// closestName returns the name with the smallest edit distance to the given one, as long as the
+// distance is small enough to be a typo.
+func closestName(name string, names map[string]bool) string {
+	maxDistance := len(name) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	closest, closestDistance := "", maxDistance+1
+	for candidate := range names {
+		d := editDistance(name, candidate)
+		if d < closestDistance || (d == closestDistance && candidate < closest) {
+			closest, closestDistance = candidate, d
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// dataPointValues returns the values of the gauge data points of the metrics, by series. Only the
+// metrics with the given names are returned, unless names is nil.
+func dataPointValues(md pmetric.Metrics, names map[string]bool) map[string]float64 {
+	values := map[string]float64{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			metrics := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if (names != nil && !names[metric.Name()]) || metric.Type() != pmetric.MetricTypeGauge {
+					continue
+				}
+				dps := metric.Gauge().DataPoints()
+				for l := 0; l < dps.Len(); l++ {
+					var b strings.Builder
+					b.WriteString(metric.Name())
+					b.WriteByte('{')
+					writeAttributes(&b, rm.Resource().Attributes())
+					writeAttributes(&b, dps.At(l).Attributes())
+					b.WriteByte('}')
+					switch dps.At(l).ValueType() {
+					case pmetric.NumberDataPointValueTypeDouble:
+						values[b.String()] = dps.At(l).DoubleValue()
+					case pmetric.NumberDataPointValueTypeInt:
+						values[b.String()] = float64(dps.At(l).IntValue())
+					}
+				}
+			}
+		}
+	}
+	return values
+}
+
+// diffValues reports the series missing, unexpected or with a different value, sorted by series.
+func diffValues(expected, generated map[string]float64) []string {
+	series := make([]string, 0, len(expected)+len(generated))
+	for s := range expected {
+		series = append(series, s)
+	}
+	for s := range generated {
+		if _, ok := expected[s]; !ok {
+			series = append(series, s)
+		}
+	}
+	sort.Strings(series)
+
+	var report []string
+	for _, s := range series {
+		e, inExpected := expected[s]
+		g, inGenerated := generated[s]
+		if inExpected && inGenerated && e == g {
+			continue
+		}
+		if inExpected {
+			report = append(report, fmt.Sprintf("- %s %v", s, e))
+		}
+		if inGenerated {
+			report = append(report, fmt.Sprintf("+ %s %v", s, g))
+		}
+	}
+	return report
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func newValidationConfig(expectedFile string, rules ...Rule) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Rules = rules
+	cfg.Validation = ValidationConfig{
+		SampleFile:   filepath.Join("testdata", "validation", "sample.json"),
+		ExpectedFile: expectedFile,
+	}
+	return cfg
+}
+
+func TestValidateRules(t *testing.T) {
+	utilized := Rule{Name: "pod.cpu.utilized", Unit: "percent", Type: calculate, Metric1: "pod.cpu.usage", Metric2: "node.cpu.limit", Operation: percent}
+	expectedFile := filepath.Join("testdata", "validation", "expected.json")
+
+	assert.NoError(t, validateRules(newValidationConfig("", utilized)))
+	assert.NoError(t, validateRules(newValidationConfig(expectedFile, utilized)))
+
+	typo := utilized
+	typo.Metric1 = "pod.cpu.usge"
+	typo.Metric2 = "node.memory.limit"
+	assert.EqualError(t, validateRules(newValidationConfig(expectedFile, typo, Rule{Name: "pod.cpu.millis", Type: scale, Metric1: "pod.cpu.usage", ScaleBy: 1000, Operation: multiply})),
+		`rule operands missing from the validation sample "testdata/validation/sample.json":
+rule "pod.cpu.utilized": metric1
+- pod.cpu.usge
++ pod.cpu.usage
+rule "pod.cpu.utilized": metric2
+- node.memory.limit`)
+
+	different := utilized
+	different.Operation = divide
+	assert.EqualError(t, validateRules(newValidationConfig(expectedFile, different, Rule{Name: "pod.cpu.millis", Type: scale, Metric1: "pod.cpu.usage", ScaleBy: 1000, Operation: multiply})),
+		`generated metrics differing from the expected metrics "testdata/validation/expected.json" (-expected +generated):
++ pod.cpu.millis{k8s.pod.name=checkout-0;} 500
+- pod.cpu.utilized{k8s.pod.name=checkout-0;} 12.5
++ pod.cpu.utilized{k8s.pod.name=checkout-0;} 0.125`)
+
+	cfg := newValidationConfig("", utilized)
+	cfg.Validation.SampleFile = filepath.Join("testdata", "validation", "missing.json")
+	assert.ErrorContains(t, validateRules(cfg), "failed to read the validation sample")
+}
+
+func TestValidateRulesOnStart(t *testing.T) {
+	cfg := newValidationConfig("", Rule{Name: "pod.cpu.utilized", Type: calculate, Metric1: "pod.cpu.usge", Metric2: "node.cpu.limit", Operation: percent})
+	mp, err := NewFactory().CreateMetricsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.ErrorContains(t, mp.Start(context.Background(), componenttest.NewNopHost()), `rule "pod.cpu.utilized": metric1`)
+
+	cfg.Rules[0].Metric1 = "pod.cpu.usage"
+	mp, err = NewFactory().CreateMetricsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NoError(t, mp.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+func TestClosestName(t *testing.T) {
+	names := map[string]bool{"pod.cpu.usage": true, "pod.memory.usage": true, "node.cpu.limit": true}
+	assert.Equal(t, "pod.cpu.usage", closestName("pod.cpu.usge", names))
+	assert.Equal(t, "node.cpu.limit", closestName("node.cpu.limits", names))
+	assert.Equal(t, "", closestName("container.restarts", names))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricsgenerationprocessor"
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// seriesExpiry is the duration after which the window of a series that did not
+// receive any value is dropped.
+const seriesExpiry = 15 * time.Minute
+
+// slidingWindow keeps the last values of each series of an operand metric.
+type slidingWindow struct {
+	size        int
+	aggregation AggregationType
+
+	mu        sync.Mutex
+	series    map[string]*seriesValues
+	lastSweep time.Time
+}
+
+type seriesValues struct {
+	values   []float64
+	next     int
+	lastSeen time.Time
+}
+
+func newSlidingWindow(w *Window) *slidingWindow {
+	if w == nil {
+		return nil
+	}
+	return &slidingWindow{
+		size:        w.Size,
+		aggregation: w.Aggregation,
+		series:      map[string]*seriesValues{},
+	}
+}
+
+// add records the value of the series and returns the aggregation of the values
+// of its window, including the given one.
+func (sw *slidingWindow) add(key string, value float64) float64 {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	now := time.Now()
+	sw.sweep(now)
+
+	s, ok := sw.series[key]
+	if !ok {
+		s = &seriesValues{values: make([]float64, 0, sw.size)}
+		sw.series[key] = s
+	}
+	s.lastSeen = now
+	if len(s.values) < sw.size {
+		s.values = append(s.values, value)
+	} else {
+		s.values[s.next] = value
+		s.next = (s.next + 1) % sw.size
+	}
+	return aggregate(s.values, sw.aggregation)
+}
+
+// sweep drops the expired series, at most once per expiry period.
+func (sw *slidingWindow) sweep(now time.Time) {
+	if now.Sub(sw.lastSweep) < seriesExpiry {
+		return
+	}
+	sw.lastSweep = now
+	for key, s := range sw.series {
+		if now.Sub(s.lastSeen) >= seriesExpiry {
+			delete(sw.series, key)
+		}
+	}
+}
+
+func aggregate(values []float64, aggregation AggregationType) float64 {
+	switch aggregation {
+	case average:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values))
+	case minimum:
+		result := math.Inf(1)
+		for _, v := range values {
+			result = math.Min(result, v)
+		}
+		return result
+	case maximum:
+		result := math.Inf(-1)
+		for _, v := range values {
+			result = math.Max(result, v)
+		}
+		return result
+	}
+	return 0
+}
+
+// seriesKey identifies a series from its resource, metric name and data point attributes.
+func seriesKey(resourceAttrs pcommon.Map, metricName string, attrs pcommon.Map) string {
+	var b strings.Builder
+	writeAttributes(&b, resourceAttrs)
+	b.WriteByte('|')
+	b.WriteString(metricName)
+	b.WriteByte('|')
+	writeAttributes(&b, attrs)
+	return b.String()
+}
+
+func writeAttributes(b *strings.Builder, attrs pcommon.Map) {
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pcommon.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(v.AsString())
+		b.WriteByte(';')
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsgenerationprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestSlidingWindow(t *testing.T) {
+	tests := []struct {
+		aggregation AggregationType
+		expected    []float64
+	}{
+		{aggregation: average, expected: []float64{4, 3, 4, 5}},
+		{aggregation: minimum, expected: []float64{4, 2, 2, 2}},
+		{aggregation: maximum, expected: []float64{4, 4, 6, 7}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.aggregation), func(t *testing.T) {
+			sw := newSlidingWindow(&Window{Size: 3, Aggregation: tt.aggregation})
+			var actual []float64
+			for _, v := range []float64{4, 2, 6, 7} {
+				actual = append(actual, sw.add("series", v))
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestSlidingWindowSeries(t *testing.T) {
+	sw := newSlidingWindow(&Window{Size: 2, Aggregation: average})
+	assert.Equal(t, float64(10), sw.add("a", 10))
+	assert.Equal(t, float64(2), sw.add("b", 2))
+	assert.Equal(t, float64(15), sw.add("a", 20))
+	assert.Equal(t, float64(3), sw.add("b", 4))
+	assert.Len(t, sw.series, 2)
+}
+
+func TestSeriesKey(t *testing.T) {
+	res := pcommon.NewMap()
+	res.PutStr("host.name", "host-1")
+	attrs1 := pcommon.NewMap()
+	attrs1.PutStr("state", "used")
+	attrs1.PutStr("device", "sda")
+	attrs2 := pcommon.NewMap()
+	attrs2.PutStr("device", "sda")
+	attrs2.PutStr("state", "used")
+
+	assert.Equal(t, seriesKey(res, "disk.usage", attrs1), seriesKey(res, "disk.usage", attrs2))
+	assert.NotEqual(t, seriesKey(res, "disk.usage", attrs1), seriesKey(res, "disk.free", attrs1))
+	assert.NotEqual(t, seriesKey(res, "disk.usage", attrs1), seriesKey(pcommon.NewMap(), "disk.usage", attrs1))
+}
+
+func TestMetricsGenerationProcessorWindow(t *testing.T) {
+	next := new(consumertest.MetricsSink)
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+		Rules: []Rule{
+			{
+				Name:          "metric_1_smoothed",
+				Type:          "calculate",
+				Metric1:       "metric_1",
+				Metric2:       "metric_2",
+				Operation:     "divide",
+				Metric1Window: &Window{Size: 2, Aggregation: average},
+				Metric2Window: &Window{Size: 2, Aggregation: maximum},
+			},
+		},
+	}
+	mgp, err := NewFactory().CreateMetricsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, mgp.Start(context.Background(), componenttest.NewNopHost()))
+
+	for _, values := range [][]float64{{100, 4}, {300, 2}, {500, 1}} {
+		require.NoError(t, mgp.ConsumeMetrics(context.Background(), generateTestMetrics(testMetric{
+			metricNames:  []string{"metric_1", "metric_2"},
+			metricValues: [][]float64{{values[0]}, {values[1]}},
+		})))
+	}
+
+	// metric_1 is averaged over 2 intervals and metric_2 is the maximum of 2 intervals.
+	var actual []float64
+	for _, md := range next.AllMetrics() {
+		metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		require.Equal(t, 3, metrics.Len())
+		require.Equal(t, "metric_1_smoothed", metrics.At(2).Name())
+		actual = append(actual, metrics.At(2).Gauge().DataPoints().At(0).DoubleValue())
+	}
+	assert.Equal(t, []float64{100.0 / 4, 200.0 / 4, 400.0 / 2}, actual)
+	require.NoError(t, mgp.Shutdown(context.Background()))
+}
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	// recordProbability records the probability the spans were sampled with, it is the default.
+	recordProbability = "probability"
+	// recordAdjustedCount records the adjusted count of the spans, the inverse of their probability.
+	recordAdjustedCount = "adjusted_count"
+
+	// thresholdTraceStateField is the field of the OpenTelemetry entry of the tracestate recording the
+	// rejection threshold of the sampled spans, from which their adjusted count is derived.
+	thresholdTraceStateField = "th"
+	// thresholdBits is the number of bits of the rejection threshold of the tracestate.
+	thresholdBits = 56
+)
+
+// AdjustedCountConfig defines how the probability the spans were sampled with is recorded on the sampled spans,
+// so that the backends can re-weight the metrics derived from the sampled traces.
+type AdjustedCountConfig struct {
+	// Attribute is the span attribute recording the probability, or the adjusted count, of the sampled spans.
+	// It defaults to none, and to "sampling.probability" in the "target_rate" mode.
+	Attribute string `mapstructure:"attribute"`
+
+	// Record is what the attribute records: "probability", the default, or "adjusted_count", the inverse of
+	// the probability.
+	Record string `mapstructure:"record"`
+
+	// TraceState records the rejection threshold of the sampled spans in the "th" field of the OpenTelemetry
+	// entry of their tracestate. The spans sampled in the "consistent" mode already have their p-value instead.
+	TraceState bool `mapstructure:"tracestate"`
+}
+
+func (a *AdjustedCountConfig) validate() error {
+	switch a.Record {
+	case "", recordProbability, recordAdjustedCount:
+	default:
+		return fmt.Errorf("unsupported record %q, must be %q or %q", a.Record, recordProbability, recordAdjustedCount)
+	}
+	return nil
+}
+
+// thresholdProbability returns the probability of the spans sampled with the threshold.
+func thresholdProbability(threshold uint32) float64 {
+	if threshold >= numHashBuckets {
+		return 1
+	}
+	return float64(threshold) / numHashBuckets
+}
+
+// recordAdjustedCount records the probability the span was sampled with, the one of its p-value in the
+// consistent mode and the one of the threshold in the other modes, in the configured attribute and
+// tracestate.
+func (tsp *tracesamplerprocessor) recordAdjustedCount(s ptrace.Span, threshold uint32) {
+	if tsp.consistent {
+		if tsp.adjustedAttribute == "" {
+			return
+		}
+		p := parseOTelTraceState(s.TraceState().AsRaw()).p
+		if p < 0 || p == zeroPValue {
+			return
+		}
+		tsp.putAdjustedCount(s, math.Exp2(-float64(p)))
+		return
+	}
+	if tsp.adjustedAttribute != "" {
+		tsp.putAdjustedCount(s, thresholdProbability(threshold))
+	}
+	if tsp.adjustedTraceState {
+		ts := parseOTelTraceState(s.TraceState().AsRaw())
+		fields := []string{thresholdTraceStateField + ":" + rejectionThreshold(threshold)}
+		for _, field := range ts.fields {
+			if !strings.HasPrefix(field, thresholdTraceStateField+":") {
+				fields = append(fields, field)
+			}
+		}
+		ts.fields = fields
+		s.TraceState().FromRaw(ts.String())
+	}
+}
+
+func (tsp *tracesamplerprocessor) putAdjustedCount(s ptrace.Span, probability float64) {
+	if tsp.adjustedCount {
+		s.Attributes().PutDouble(tsp.adjustedAttribute, 1/probability)
+		return
+	}
+	s.Attributes().PutDouble(tsp.adjustedAttribute, probability)
+}
+
+// rejectionThreshold returns the rejection threshold of the tracestate for the threshold: the probability
+// of the spans not being sampled scaled to 56 bits, in hexadecimal without its trailing zeros.
+func rejectionThreshold(threshold uint32) string {
+	if threshold >= numHashBuckets {
+		return "0"
+	}
+	rejected := uint64(numHashBuckets-threshold) << (thresholdBits - 14)
+	s := strconv.FormatUint(rejected, 16)
+	s = strings.Repeat("0", thresholdBits/4-len(s)) + s
+	return strings.TrimRight(s, "0")
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestValidateAdjustedCount(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.AdjustedCount = AdjustedCountConfig{Attribute: "sampling.adjusted_count", Record: recordAdjustedCount, TraceState: true}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.AdjustedCount.Record = "weight"
+	assert.EqualError(t, cfg.Validate(), `invalid adjusted_count: unsupported record "weight", must be "probability" or "adjusted_count"`)
+
+	cfg.AdjustedCount.Record = ""
+	cfg.Deterministic = true
+	assert.EqualError(t, cfg.Validate(), "deterministic is not supported with adjusted_count")
+}
+
+func TestRejectionThreshold(t *testing.T) {
+	assert.Equal(t, "0", rejectionThreshold(numHashBuckets))
+	assert.Equal(t, "8", rejectionThreshold(numHashBuckets/2))
+	assert.Equal(t, "c", rejectionThreshold(numHashBuckets/4))
+	assert.Equal(t, "fffc", rejectionThreshold(1))
+}
+
+func Test_tracesamplerprocessor_AdjustedCount(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+		SamplingPercentage: 25,
+		AdjustedCount: AdjustedCountConfig{
+			Attribute:  "sampling.adjusted_count",
+			Record:     recordAdjustedCount,
+			TraceState: true,
+		},
+	}
+	sink := new(consumertest.TracesSink)
+	tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	td := genRandomTestData(1, 1000, "svc", 1)[0]
+	spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).TraceState().FromRaw("ot=r:3;x:1,vendor=value")
+	}
+	// the spans sampled because of their priority don't have an adjusted count
+	prioritized := spans.AppendEmpty()
+	prioritized.Attributes().PutInt("sampling.priority", 1)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, sink.AllTraces(), 1)
+	sampled := sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	require.Greater(t, sampled.Len(), 1)
+	for i := 0; i < sampled.Len()-1; i++ {
+		count, ok := sampled.At(i).Attributes().Get("sampling.adjusted_count")
+		require.True(t, ok)
+		assert.Equal(t, float64(4), count.Double())
+		assert.Equal(t, "ot=r:3;th:c;x:1,vendor=value", sampled.At(i).TraceState().AsRaw())
+	}
+	last := sampled.At(sampled.Len() - 1)
+	_, ok := last.Attributes().Get("sampling.adjusted_count")
+	assert.False(t, ok)
+	assert.Empty(t, last.TraceState().AsRaw())
+}
+
+func Test_tracesamplerprocessor_AdjustedCountConsistent(t *testing.T) {
+	cfg := newConsistentConfig(25)
+	cfg.AdjustedCount.Attribute = "sampling.probability"
+	sink := new(consumertest.TracesSink)
+	tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	for _, td := range genRandomTestData(1, 1000, "svc", 1) {
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+	}
+
+	// the probability is the one of the p-value of the span
+	require.Len(t, sink.AllTraces(), 1)
+	sampled := sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	require.Greater(t, sampled.Len(), 0)
+	for i := 0; i < sampled.Len(); i++ {
+		s := sampled.At(i)
+		probability, ok := s.Attributes().Get("sampling.probability")
+		require.True(t, ok)
+		assert.Equal(t, 0.25, probability.Double())
+		assert.Contains(t, s.TraceState().AsRaw(), "p:2")
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.uber.org/zap"
+)
+
+// adminPath is the path of the admin endpoint the sampling rules are read and changed at.
+const adminPath = "/sampling"
+
+// maxAdminBodySize is the maximum size of the sampling rules sent to the admin endpoint.
+const maxAdminBodySize = 1 << 20
+
+// adminRules are the sampling rules returned by the admin endpoint.
+type adminRules struct {
+	SamplingPercentage float64             `json:"sampling_percentage"`
+	Services           []adminServiceRules `json:"services,omitempty"`
+}
+
+type adminServiceRules struct {
+	Name               string  `json:"name"`
+	SamplingPercentage float32 `json:"sampling_percentage"`
+}
+
+// adminServer serves the endpoint reading and changing the sampling rules at runtime, so that the
+// sampling can be changed during an incident without restarting the collector.
+type adminServer struct {
+	settings  *confighttp.HTTPServerSettings
+	telemetry component.TelemetrySettings
+	// current returns the rates the spans are currently sampled with, apply replaces them.
+	current func() *samplingRates
+	apply   func(*samplingRates)
+
+	// mu serializes the changes, so that concurrent partial changes aren't lost.
+	mu      sync.Mutex
+	server  *http.Server
+	stopped chan struct{}
+}
+
+// newAdminServer returns the admin server of the sampling rules, nil when no admin endpoint is configured.
+func newAdminServer(cfg *Config, telemetry component.TelemetrySettings, current func() *samplingRates, apply func(*samplingRates)) (*adminServer, error) {
+	if cfg.Admin == nil {
+		return nil, nil
+	}
+	if !featuregate.GetRegistry().IsEnabled(dynamicConfigGateID) {
+		return nil, fmt.Errorf("the admin endpoint requires the %q feature gate", dynamicConfigGateID)
+	}
+	return &adminServer{
+		settings:  cfg.Admin,
+		telemetry: telemetry,
+		current:   current,
+		apply:     apply,
+	}, nil
+}
+
+func (a *adminServer) start(host component.Host) error {
+	ln, err := a.settings.ToListener()
+	if err != nil {
+		return fmt.Errorf("failed to bind to address %s: %w", a.settings.Endpoint, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(adminPath, a.handle)
+	if a.server, err = a.settings.ToServer(host, a.telemetry, mux); err != nil {
+		_ = ln.Close()
+		return err
+	}
+	a.stopped = make(chan struct{})
+	go func() {
+		defer close(a.stopped)
+		if errHTTP := a.server.Serve(ln); !errors.Is(errHTTP, http.ErrServerClosed) && errHTTP != nil {
+			host.ReportFatalError(errHTTP)
+		}
+	}()
+	return nil
+}
+
+func (a *adminServer) shutdown() error {
+	if a.server == nil {
+		return nil
+	}
+	err := a.server.Close()
+	<-a.stopped
+	return err
+}
+
+// handle returns the current sampling rules on GET, and changes them on PUT or POST with a YAML, or JSON,
+// document like the one of the reload source, the rules it doesn't set keeping their current value.
+func (a *adminServer) handle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.writeRules(w, a.current())
+	case http.MethodPut, http.MethodPost:
+		content, err := io.ReadAll(io.LimitReader(r.Body, maxAdminBodySize))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read the sampling rules: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		current := a.current()
+		rates, err := parseSamplingRules(content, float32(current.samplingPercentage), current.services)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		a.apply(rates)
+		a.telemetry.Logger.Info("Changed the sampling rules through the admin endpoint",
+			zap.Float64("sampling_percentage", rates.samplingPercentage), zap.Int("services", len(rates.services)))
+		a.writeRules(w, rates)
+	default:
+		w.Header().Set("Allow", "GET, PUT, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *adminServer) writeRules(w http.ResponseWriter, rates *samplingRates) {
+	rules := adminRules{SamplingPercentage: rates.samplingPercentage}
+	for _, service := range rates.services {
+		rules.Services = append(rules.Services, adminServiceRules{Name: service.Name, SamplingPercentage: service.SamplingPercentage})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(rules)
+}
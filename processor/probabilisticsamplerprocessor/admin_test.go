@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestValidateAdmin(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Admin = &confighttp.HTTPServerSettings{}
+	assert.EqualError(t, cfg.Validate(), "invalid admin: missing endpoint")
+
+	cfg.Admin.Endpoint = "localhost:55690"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Reload.Source = "sampling.yaml"
+	assert.EqualError(t, cfg.Validate(), "admin and reload are mutually exclusive")
+
+	cfg.Reload.Source = ""
+	cfg.Deterministic = true
+	assert.EqualError(t, cfg.Validate(), "deterministic is not supported with admin")
+}
+
+func TestNewAdminServerFeatureGate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	set := componenttest.NewNopTelemetrySettings()
+	a, err := newAdminServer(cfg, set, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	cfg.Admin = &confighttp.HTTPServerSettings{Endpoint: "localhost:0"}
+	_, err = newAdminServer(cfg, set, nil, nil)
+	assert.EqualError(t, err, `the admin endpoint requires the "processor.probabilisticsampler.DynamicConfig" feature gate`)
+
+	enableDynamicConfig(t)
+	a, err = newAdminServer(cfg, set, nil, nil)
+	require.NoError(t, err)
+	assert.NotNil(t, a)
+}
+
+func TestAdminServerHandle(t *testing.T) {
+	enableDynamicConfig(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Admin = &confighttp.HTTPServerSettings{Endpoint: "localhost:0"}
+	rates := newSamplingRates(10, []ServiceConfig{{Name: "checkout", SamplingPercentage: 100}})
+	a, err := newAdminServer(cfg, componenttest.NewNopTelemetrySettings(),
+		func() *samplingRates { return rates },
+		func(r *samplingRates) { rates = r })
+	require.NoError(t, err)
+
+	do := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		a.handle(rec, httptest.NewRequest(method, adminPath, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"sampling_percentage":10,"services":[{"name":"checkout","sampling_percentage":100}]}`, rec.Body.String())
+
+	// the services are kept when only the sampling percentage is changed
+	rec = do(http.MethodPut, `{"sampling_percentage": 1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"sampling_percentage":1,"services":[{"name":"checkout","sampling_percentage":100}]}`, rec.Body.String())
+	assert.Equal(t, newSamplingRates(1, []ServiceConfig{{Name: "checkout", SamplingPercentage: 100}}), rates)
+
+	rec = do(http.MethodPost, "services: []\n")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"sampling_percentage":1}`, rec.Body.String())
+	assert.Equal(t, newSamplingRates(1, nil), rates)
+
+	// the rules are kept when the change is invalid
+	rec = do(http.MethodPut, `{"sampling_percentage": -1}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "sampling_percentage must not be negative\n", rec.Body.String())
+	rec = do(http.MethodPut, `{"hash_seed": 1}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, newSamplingRates(1, nil), rates)
+
+	rec = do(http.MethodDelete, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, PUT, POST", rec.Header().Get("Allow"))
+}
+
+func TestAdminServerStartShutdown(t *testing.T) {
+	enableDynamicConfig(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Admin = &confighttp.HTTPServerSettings{Endpoint: "localhost:0"}
+	tp, err := createTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, tp.Shutdown(context.Background()))
+}
@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+const (
+	// keepUnmatched passes the spans not matching the condition through, it is the default.
+	keepUnmatched = "keep"
+	// dropUnmatched drops the spans not matching the condition.
+	dropUnmatched = "drop"
+
+	// conditionFunction is the no-op function of the statement the condition is evaluated with,
+	// since the conditions can't be parsed on their own yet.
+	conditionFunction = "sample"
+)
+
+func conditionFunctions() map[string]interface{} {
+	return map[string]interface{}{
+		"IsMatch": ottlfuncs.IsMatch[ottlspan.TransformContext],
+		conditionFunction: func() (ottl.ExprFunc[ottlspan.TransformContext], error) {
+			return func(context.Context, ottlspan.TransformContext) (interface{}, error) {
+				return nil, nil
+			}, nil
+		},
+	}
+}
+
+// parseCondition returns the statement evaluating the OTTL condition.
+func parseCondition(condition string, set component.TelemetrySettings) (*ottl.Statement[ottlspan.TransformContext], error) {
+	parser := ottlspan.NewParser(conditionFunctions(), set)
+	statements, err := parser.ParseStatements([]string{conditionFunction + "() where " + condition})
+	if err != nil {
+		return nil, err
+	}
+	return statements[0], nil
+}
+
+// matchesCondition returns whether the span is subjected to the sampling, which is the case of all the
+// spans without condition. The spans whose condition fails to be evaluated don't match it.
+func (tsp *tracesamplerprocessor) matchesCondition(ctx context.Context, s ptrace.Span, scope pcommon.InstrumentationScope, resource pcommon.Resource) bool {
+	if tsp.condition == nil {
+		return true
+	}
+	_, matched, err := tsp.condition.Execute(ctx, ottlspan.NewTransformContext(s, scope, resource))
+	if err != nil {
+		tsp.logger.Debug("Failed to evaluate the sampling condition",
+			zap.String("trace_id", s.TraceID().HexString()),
+			zap.String("span_id", s.SpanID().HexString()),
+			zap.Error(err))
+		return false
+	}
+	return matched
+}
@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestValidateCondition(t *testing.T) {
+	tests := []struct {
+		name          string
+		condition     string
+		unmatched     string
+		deterministic bool
+		err           string
+	}{
+		{
+			name:      "valid",
+			condition: `attributes["http.route"] == "/healthz"`,
+			unmatched: dropUnmatched,
+		},
+		{
+			name:      "function",
+			condition: `IsMatch(name, "^GET ") == true`,
+		},
+		{
+			name:      "invalid condition",
+			condition: `attributes["http.route"] ==`,
+			err:       "invalid condition: ",
+		},
+		{
+			name:      "unsupported unmatched",
+			condition: `name == "health"`,
+			unmatched: "sample",
+			err:       `unsupported unmatched "sample", must be "keep" or "drop"`,
+		},
+		{
+			name:          "deterministic",
+			condition:     `name == "health"`,
+			deterministic: true,
+			err:           "deterministic is not supported with a condition",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Condition = tt.condition
+			cfg.Unmatched = tt.unmatched
+			cfg.Deterministic = tt.deterministic
+			err := cfg.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func Test_tracesamplerprocessor_Condition(t *testing.T) {
+	for _, unmatched := range []string{keepUnmatched, dropUnmatched} {
+		t.Run(unmatched, func(t *testing.T) {
+			cfg := &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 0,
+				Condition:          `attributes["http.route"] == "/healthz"`,
+				Unmatched:          unmatched,
+			}
+			sink := new(consumertest.TracesSink)
+			tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+			require.NoError(t, err)
+
+			td := ptrace.NewTraces()
+			spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+			addSpan := func(name, route string) ptrace.Span {
+				span := spans.AppendEmpty()
+				span.SetName(name)
+				span.SetTraceID(pcommon.TraceID{byte(spans.Len())})
+				span.Attributes().PutStr("http.route", route)
+				return span
+			}
+			// the health checks are sampled at 0%
+			addSpan("health", "/healthz")
+			addSpan("checkout", "/checkout")
+			// the sampling priority still applies to the spans not matching the condition
+			addSpan("priority", "/pay").Attributes().PutInt("sampling.priority", 1)
+			addSpan("ignored", "/pay").Attributes().PutInt("sampling.priority", 0)
+
+			require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+			var names []string
+			for _, out := range sink.AllTraces() {
+				got := out.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+				for i := 0; i < got.Len(); i++ {
+					names = append(names, got.At(i).Name())
+				}
+			}
+			if unmatched == keepUnmatched {
+				assert.Equal(t, []string{"checkout", "priority"}, names)
+			} else {
+				assert.Equal(t, []string{"priority"}, names)
+			}
+		})
+	}
+}
+
+func TestNewTracesProcessorInvalidCondition(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Condition = "attributes["
+	_, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, consumertest.NewNop())
+	assert.Error(t, err)
+}
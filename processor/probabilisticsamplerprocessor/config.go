@@ -15,8 +15,13 @@
 package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
 
 import (
+	"fmt"
+	"regexp"
+
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.uber.org/zap"
 )
 
 // Config has the configuration guiding the trace sampler processor.
@@ -31,11 +36,397 @@ type Config struct {
 	// have different sampling rates: if they use the same seed all passing one layer may pass the other even if they have
 	// different sampling rates, configuring different seeds avoids that.
 	HashSeed uint32 `mapstructure:"hash_seed"`
+
+	// HashAlgorithm is the algorithm hashing the trace IDs, one of "murmur3", "fnv", "xxhash" or "sha256". It defaults to
+	// "murmur3", the algorithm used by the previous versions: the collectors of a tier must use the same algorithm
+	// and seed to make the same decisions, so changing the algorithm must be coordinated like changing the seed.
+	HashAlgorithm string `mapstructure:"hash_algorithm"`
+
+	// HashFromAttribute is the span attribute, or the resource attribute when the span doesn't have it, hashed
+	// instead of the trace ID, e.g. a tenant or session ID, so that all the spans with the same value are sampled
+	// together across traces. The spans without the attribute are hashed by trace ID.
+	HashFromAttribute string `mapstructure:"hash_from_attribute"`
+
+	// Mode is how the sampling decisions are made, "hash", "consistent" or "target_rate". It defaults to "hash",
+	// sampling the traces whose hash bucket is lower than the threshold of SamplingPercentage. "consistent" follows
+	// the OpenTelemetry consistent probability sampling: the traces are sampled with power-of-two probabilities
+	// recorded with their random value in the p-value and r-value of the tracestate, so that the tail samplers
+	// and the backends downstream can compute the adjusted count of the sampled spans. "target_rate" samples
+	// TargetRate instead of SamplingPercentage, the probability applied to the sampled spans is recorded in their
+	// "sampling.probability" attribute. "rate_limiting" samples the traces, up to RateLimiting traces per second,
+	// regardless of SamplingPercentage, to protect the pipeline downstream.
+	Mode string `mapstructure:"mode"`
+
+	// TargetRate is the number of spans, or traces, sampled per second in the "target_rate" mode.
+	TargetRate TargetRateConfig `mapstructure:"target_rate"`
+
+	// RateLimiting caps the number of traces sampled per second in the "rate_limiting" mode.
+	RateLimiting RateLimitingConfig `mapstructure:"rate_limiting"`
+
+	// PriorityAttributes are the span attributes overriding the sampling decision, following the semantics of the
+	// OpenTracing "sampling.priority" tag: a value of 0 drops the span, a positive value samples it. The first
+	// attribute the span has takes precedence. It defaults to "sampling.priority".
+	PriorityAttributes []string `mapstructure:"priority_attributes"`
+
+	// RemovePriorityAttributes removes the PriorityAttributes from the sampled spans once the decision is made,
+	// so that they don't override the decisions of the samplers downstream.
+	RemovePriorityAttributes bool `mapstructure:"remove_priority_attributes"`
+
+	// AdjustedCount records the probability the sampled spans were sampled with, or their adjusted count, in an
+	// attribute and in their tracestate, so that the backends can re-weight the metrics derived from the sampled
+	// traces. The spans sampled only because of their priority, or not subjected to the sampling, don't have it.
+	AdjustedCount AdjustedCountConfig `mapstructure:"adjusted_count"`
+
+	// Debug adds the hash bucket and the sampling threshold the decision was made with as attributes of the sampled
+	// spans, and logs the dropped ones at debug level.
+	Debug bool `mapstructure:"debug"`
+
+	// Deterministic samples SamplingPercentage of the distinct trace IDs of each batch, the ones with the lowest hash
+	// buckets given HashSeed, instead of each trace with a SamplingPercentage probability. The traces kept from a batch
+	// then only depend on the batch and the seed, so that integration tests can assert on the exact sampled traces.
+	// It must not be used in production since the sampling rate of the traces depends on how they are batched.
+	Deterministic bool `mapstructure:"deterministic"`
+
+	// Strata samples the traces having a span matching a stratum at the rate of the stratum instead of
+	// SamplingPercentage, so that the rare but important traces aren't drowned out by the uniform sampling.
+	Strata []StratumConfig `mapstructure:"strata"`
+
+	// Condition is an OTTL condition on the spans, e.g. `attributes["http.route"] == "/healthz"`, only the spans
+	// matching it are subjected to the sampling. It defaults to all the spans.
+	Condition string `mapstructure:"condition"`
+
+	// Unmatched is what happens to the spans not matching Condition: "keep" passes them through, "drop" drops them.
+	// It defaults to "keep". The sampling.priority of the spans still applies.
+	Unmatched string `mapstructure:"unmatched"`
+
+	// Services override SamplingPercentage for the spans of the resources of the given services, so that a single
+	// processor can sample the chatty services at a low rate and the critical ones at a high rate.
+	Services []ServiceConfig `mapstructure:"services"`
+
+	// SpanNames override SamplingPercentage, and the rate of the services, for the spans whose name matches,
+	// so that the health checks or the polling spans can be sampled at a lower rate than the business
+	// transactions of the same service. The first matching rule applies.
+	SpanNames []SpanNameConfig `mapstructure:"span_names"`
+
+	// Reload periodically reloads SamplingPercentage and Services from a file or an HTTP endpoint, so that the
+	// sampling can be changed without restarting the collector. It requires the
+	// "processor.probabilisticsampler.DynamicConfig" feature gate.
+	Reload ReloadConfig `mapstructure:"reload"`
+
+	// Admin is the HTTP endpoint reading and changing SamplingPercentage and Services at runtime, without
+	// restarting the collector. It requires the "processor.probabilisticsampler.DynamicConfig" feature gate.
+	Admin *confighttp.HTTPServerSettings `mapstructure:"admin"`
+
+	// Metrics defines how the data points are sampled at SamplingPercentage in the metrics pipelines, the other
+	// options only apply to the traces.
+	Metrics MetricsConfig `mapstructure:"metrics"`
+}
+
+// MetricsConfig defines what is sampled in the metrics pipelines and what the sampling decisions are keyed on.
+type MetricsConfig struct {
+	// Granularity is what is sampled, "data_point" or "resource". It defaults to "data_point".
+	Granularity string `mapstructure:"granularity"`
+
+	// HashAttributes are the attributes whose values are hashed to make the decisions, the data point attributes
+	// then the resource attributes in the "data_point" granularity, the resource attributes in the "resource"
+	// one, so that all the data points with the same values are sampled together, e.g. of the same user. They
+	// default to the identity of the stream, the metric name and all the attributes of the data point, or all
+	// the resource attributes, so that a stream is either fully sampled or fully dropped.
+	HashAttributes []string `mapstructure:"hash_attributes"`
+}
+
+// TargetRateConfig defines the rate sampled in the "target_rate" mode. The sampling probability is adjusted every
+// second from the rate of the spans, or traces, seen over the previous second, all of them are sampled until then.
+type TargetRateConfig struct {
+	// SpansPerSecond is the approximate number of spans sampled per second.
+	SpansPerSecond float64 `mapstructure:"spans_per_second"`
+
+	// TracesPerSecond is the approximate number of traces sampled per second.
+	TracesPerSecond float64 `mapstructure:"traces_per_second"`
+}
+
+// RateLimitingConfig defines the leaky bucket of the "rate_limiting" mode: the bucket is refilled with
+// TracesPerSecond tokens per second up to Burst tokens, and each sampled trace takes a token.
+type RateLimitingConfig struct {
+	// TracesPerSecond is the maximum number of traces sampled per second over time.
+	TracesPerSecond float64 `mapstructure:"traces_per_second"`
+
+	// Burst is the maximum number of traces sampled at once after a quiet period. It defaults to
+	// TracesPerSecond, rounded up.
+	Burst int `mapstructure:"burst"`
+}
+
+// ServiceConfig defines the sampling percentage of the spans of a service.
+type ServiceConfig struct {
+	// Name is the service.name resource attribute of the service.
+	Name string `mapstructure:"name"`
+
+	// SamplingPercentage is the percentage at which the spans of the service are sampled.
+	SamplingPercentage float32 `mapstructure:"sampling_percentage"`
+}
+
+// SpanNameConfig defines the sampling percentage of the spans whose name matches.
+type SpanNameConfig struct {
+	// Names are the exact names of the spans matching the rule.
+	Names []string `mapstructure:"names"`
+
+	// Pattern is a regular expression matching the names of the spans instead, e.g. "^GET /health".
+	Pattern string `mapstructure:"pattern"`
+
+	// SamplingPercentage is the percentage at which the matching spans are sampled.
+	SamplingPercentage float32 `mapstructure:"sampling_percentage"`
+}
+
+// StratumConfig defines a stratum of the traces and the rate at which they are sampled.
+type StratumConfig struct {
+	// Name identifies the stratum in the metrics.
+	Name string `mapstructure:"name"`
+
+	// Attribute is the span attribute, or the resource attribute when the span doesn't have it,
+	// the spans of the stratum are matched on.
+	Attribute string `mapstructure:"attribute"`
+
+	// Values are the values of the attribute matching the stratum. Any value matches when neither
+	// Values nor Pattern is set.
+	Values []string `mapstructure:"values"`
+
+	// Pattern is a regular expression matching the values of the attribute, e.g. "^5" to match the
+	// 5xx status codes.
+	Pattern string `mapstructure:"pattern"`
+
+	// SamplingPercentage is the percentage at which the traces of the stratum are sampled.
+	SamplingPercentage float32 `mapstructure:"sampling_percentage"`
+
+	// TracesPerSecond is the approximate number of traces of the stratum sampled per second, instead
+	// of a percentage. The sampled percentage is adjusted every second from the rate of the traces of
+	// the stratum seen over the previous second.
+	TracesPerSecond float64 `mapstructure:"traces_per_second"`
 }
 
 var _ component.ProcessorConfig = (*Config)(nil)
 
 // Validate checks if the processor configuration is valid
 func (cfg *Config) Validate() error {
+	if _, ok := hashFuncs[cfg.HashAlgorithm]; !ok && cfg.HashAlgorithm != "" {
+		return fmt.Errorf("unsupported hash_algorithm %q, must be one of %q, %q, %q or %q",
+			cfg.HashAlgorithm, murmur3HashAlgorithm, fnvHashAlgorithm, xxhashHashAlgorithm, sha256HashAlgorithm)
+	}
+	switch cfg.Mode {
+	case "", hashMode:
+	case consistentMode:
+		if cfg.Deterministic {
+			return fmt.Errorf("deterministic is not supported in the %q mode", consistentMode)
+		}
+		if cfg.HashFromAttribute != "" {
+			return fmt.Errorf("hash_from_attribute is not supported in the %q mode", consistentMode)
+		}
+	case targetRateMode:
+		if cfg.Deterministic {
+			return fmt.Errorf("deterministic is not supported in the %q mode", targetRateMode)
+		}
+		if err := cfg.TargetRate.validate(); err != nil {
+			return fmt.Errorf("invalid target_rate: %w", err)
+		}
+	case rateLimitingMode:
+		if err := cfg.RateLimiting.validate(); err != nil {
+			return fmt.Errorf("invalid rate_limiting: %w", err)
+		}
+		if err := cfg.validateRateLimitingMode(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported mode %q, must be one of %q, %q, %q or %q", cfg.Mode, hashMode, consistentMode, targetRateMode, rateLimitingMode)
+	}
+	if cfg.HashFromAttribute != "" && cfg.Deterministic {
+		return fmt.Errorf("deterministic is not supported with hash_from_attribute")
+	}
+	if err := cfg.AdjustedCount.validate(); err != nil {
+		return fmt.Errorf("invalid adjusted_count: %w", err)
+	}
+	if (cfg.AdjustedCount.Attribute != "" || cfg.AdjustedCount.TraceState) && cfg.Deterministic {
+		return fmt.Errorf("deterministic is not supported with adjusted_count")
+	}
+	priorityAttributes := map[string]bool{}
+	for _, attribute := range cfg.PriorityAttributes {
+		if attribute == "" {
+			return fmt.Errorf("priority_attributes must not contain an empty attribute")
+		}
+		if priorityAttributes[attribute] {
+			return fmt.Errorf("duplicate priority attribute %q", attribute)
+		}
+		priorityAttributes[attribute] = true
+	}
+	switch cfg.Unmatched {
+	case "", keepUnmatched, dropUnmatched:
+	default:
+		return fmt.Errorf("unsupported unmatched %q, must be %q or %q", cfg.Unmatched, keepUnmatched, dropUnmatched)
+	}
+	if cfg.Condition != "" {
+		if cfg.Deterministic {
+			return fmt.Errorf("deterministic is not supported with a condition")
+		}
+		if _, err := parseCondition(cfg.Condition, component.TelemetrySettings{Logger: zap.NewNop()}); err != nil {
+			return fmt.Errorf("invalid condition: %w", err)
+		}
+	}
+	if err := validateServices(cfg.Services); err != nil {
+		return err
+	}
+	if len(cfg.Services) > 0 && cfg.Deterministic {
+		return fmt.Errorf("deterministic is not supported with services")
+	}
+	for i, spanName := range cfg.SpanNames {
+		if err := spanName.validate(); err != nil {
+			return fmt.Errorf("invalid span name rule %d: %w", i, err)
+		}
+	}
+	if len(cfg.SpanNames) > 0 && cfg.Deterministic {
+		return fmt.Errorf("deterministic is not supported with span_names")
+	}
+	if err := cfg.Reload.validate(); err != nil {
+		return fmt.Errorf("invalid reload: %w", err)
+	}
+	if cfg.Reload.Source != "" && cfg.Deterministic {
+		return fmt.Errorf("deterministic is not supported with reload")
+	}
+	if cfg.Admin != nil {
+		if cfg.Admin.Endpoint == "" {
+			return fmt.Errorf("invalid admin: missing endpoint")
+		}
+		if cfg.Deterministic {
+			return fmt.Errorf("deterministic is not supported with admin")
+		}
+		if cfg.Reload.Source != "" {
+			return fmt.Errorf("admin and reload are mutually exclusive")
+		}
+	}
+	if err := cfg.Metrics.validate(); err != nil {
+		return fmt.Errorf("invalid metrics: %w", err)
+	}
+	names := map[string]bool{}
+	for i, stratum := range cfg.Strata {
+		if stratum.Name == "" {
+			return fmt.Errorf("missing name of the stratum %d", i)
+		}
+		if names[stratum.Name] {
+			return fmt.Errorf("duplicate stratum %q", stratum.Name)
+		}
+		names[stratum.Name] = true
+		if err := stratum.validate(); err != nil {
+			return fmt.Errorf("invalid stratum %q: %w", stratum.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateServices(cfgs []ServiceConfig) error {
+	services := map[string]bool{}
+	for i, service := range cfgs {
+		if service.Name == "" {
+			return fmt.Errorf("missing name of the service %d", i)
+		}
+		if services[service.Name] {
+			return fmt.Errorf("duplicate service %q", service.Name)
+		}
+		services[service.Name] = true
+		if service.SamplingPercentage < 0 {
+			return fmt.Errorf("invalid service %q: sampling_percentage must not be negative", service.Name)
+		}
+	}
+	return nil
+}
+
+// validateRateLimitingMode checks that the options based on the sampling percentage or on the hash of the
+// traces aren't used in the rate_limiting mode.
+func (cfg *Config) validateRateLimitingMode() error {
+	for _, option := range []struct {
+		name string
+		set  bool
+	}{
+		{"deterministic", cfg.Deterministic},
+		{"hash_from_attribute", cfg.HashFromAttribute != ""},
+		{"adjusted_count", cfg.AdjustedCount.Attribute != "" || cfg.AdjustedCount.TraceState},
+		{"strata", len(cfg.Strata) > 0},
+		{"services", len(cfg.Services) > 0},
+		{"span_names", len(cfg.SpanNames) > 0},
+		{"reload", cfg.Reload.Source != ""},
+		{"admin", cfg.Admin != nil},
+	} {
+		if option.set {
+			return fmt.Errorf("%s is not supported in the %q mode", option.name, rateLimitingMode)
+		}
+	}
+	return nil
+}
+
+func (m *MetricsConfig) validate() error {
+	switch m.Granularity {
+	case "", dataPointGranularity, resourceGranularity:
+	default:
+		return fmt.Errorf("unsupported granularity %q, must be %q or %q", m.Granularity, dataPointGranularity, resourceGranularity)
+	}
+	for _, attribute := range m.HashAttributes {
+		if attribute == "" {
+			return fmt.Errorf("hash_attributes must not contain an empty attribute")
+		}
+	}
+	return nil
+}
+
+func (r *RateLimitingConfig) validate() error {
+	if r.TracesPerSecond <= 0 {
+		return fmt.Errorf("traces_per_second must be positive")
+	}
+	if r.Burst < 0 {
+		return fmt.Errorf("burst must not be negative")
+	}
+	return nil
+}
+
+func (r *TargetRateConfig) validate() error {
+	if r.SpansPerSecond < 0 || r.TracesPerSecond < 0 {
+		return fmt.Errorf("spans_per_second and traces_per_second must not be negative")
+	}
+	if r.SpansPerSecond > 0 && r.TracesPerSecond > 0 {
+		return fmt.Errorf("spans_per_second and traces_per_second are mutually exclusive")
+	}
+	if r.SpansPerSecond == 0 && r.TracesPerSecond == 0 {
+		return fmt.Errorf("spans_per_second or traces_per_second is required")
+	}
+	return nil
+}
+
+func (s *SpanNameConfig) validate() error {
+	if len(s.Names) == 0 && s.Pattern == "" {
+		return fmt.Errorf("names or pattern is required")
+	}
+	if len(s.Names) > 0 && s.Pattern != "" {
+		return fmt.Errorf("names and pattern are mutually exclusive")
+	}
+	if _, err := regexp.Compile(s.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	if s.SamplingPercentage < 0 {
+		return fmt.Errorf("sampling_percentage must not be negative")
+	}
+	return nil
+}
+
+func (s *StratumConfig) validate() error {
+	if s.Attribute == "" {
+		return fmt.Errorf("missing attribute")
+	}
+	if len(s.Values) > 0 && s.Pattern != "" {
+		return fmt.Errorf("values and pattern are mutually exclusive")
+	}
+	if _, err := regexp.Compile(s.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	if s.SamplingPercentage < 0 || s.TracesPerSecond < 0 {
+		return fmt.Errorf("sampling_percentage and traces_per_second must not be negative")
+	}
+	if s.SamplingPercentage > 0 && s.TracesPerSecond > 0 {
+		return fmt.Errorf("sampling_percentage and traces_per_second are mutually exclusive")
+	}
 	return nil
 }
@@ -17,11 +17,13 @@ package probabilisticsamplerprocessor
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 )
 
@@ -38,6 +40,187 @@ func TestLoadConfig(t *testing.T) {
 				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
 				SamplingPercentage: 15.3,
 				HashSeed:           22,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               hashMode,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "debug"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 50,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               hashMode,
+				Debug:              true,
+				Deterministic:      true,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "xxhash"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 10,
+				HashSeed:           22,
+				HashAlgorithm:      xxhashHashAlgorithm,
+				Mode:               hashMode,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "strata"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 1,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               hashMode,
+				Strata: []StratumConfig{
+					{
+						Name:               "errors",
+						Attribute:          "http.status_code",
+						Pattern:            "^5",
+						SamplingPercentage: 100,
+					},
+					{
+						Name:            "checkout",
+						Attribute:       "http.route",
+						Values:          []string{"/checkout", "/pay"},
+						TracesPerSecond: 50,
+					},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "consistent"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 25,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               consistentMode,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "services"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 10,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               hashMode,
+				Services: []ServiceConfig{
+					{Name: "frontend", SamplingPercentage: 1},
+					{Name: "payment", SamplingPercentage: 100},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "tenants"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 10,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				HashFromAttribute:  "tenant.id",
+				Mode:               hashMode,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "condition"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 1,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               hashMode,
+				Condition:          `attributes["http.route"] == "/healthz"`,
+				Unmatched:          keepUnmatched,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "target_rate"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				HashAlgorithm:     murmur3HashAlgorithm,
+				Mode:              targetRateMode,
+				TargetRate:        TargetRateConfig{SpansPerSecond: 1000},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "priority"),
+			expected: &Config{
+				ProcessorSettings:        config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage:       10,
+				HashAlgorithm:            murmur3HashAlgorithm,
+				Mode:                     hashMode,
+				PriorityAttributes:       []string{"debug.keep", "sampling.priority"},
+				RemovePriorityAttributes: true,
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "adjusted_count"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 10,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               hashMode,
+				AdjustedCount: AdjustedCountConfig{
+					Attribute:  "sampling.adjusted_count",
+					Record:     recordAdjustedCount,
+					TraceState: true,
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "rate_limiting"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				HashAlgorithm:     murmur3HashAlgorithm,
+				Mode:              rateLimitingMode,
+				RateLimiting:      RateLimitingConfig{TracesPerSecond: 500, Burst: 1000},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "span_names"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 10,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               hashMode,
+				SpanNames: []SpanNameConfig{
+					{Names: []string{"GET /healthz", "GET /readyz"}, SamplingPercentage: 0.1},
+					{Pattern: "^poll", SamplingPercentage: 1},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "reload"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 10,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               hashMode,
+				Reload: ReloadConfig{
+					Source:   "https://config.example.com/sampling.yaml",
+					Interval: 30 * time.Second,
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "metrics"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 10,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               hashMode,
+				Metrics: MetricsConfig{
+					Granularity:    dataPointGranularity,
+					HashAttributes: []string{"user.id"},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "admin"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 10,
+				HashAlgorithm:      murmur3HashAlgorithm,
+				Mode:               hashMode,
+				Admin:              &confighttp.HTTPServerSettings{Endpoint: "localhost:55690"},
 			},
 		},
 		{
@@ -63,3 +246,42 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateUnsupportedHashAlgorithm(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.HashAlgorithm = "md5"
+	assert.EqualError(t, cfg.Validate(), `unsupported hash_algorithm "md5", must be one of "murmur3", "fnv", "xxhash" or "sha256"`)
+}
+
+func TestValidateMode(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Mode = "random"
+	assert.EqualError(t, cfg.Validate(), `unsupported mode "random", must be one of "hash", "consistent", "target_rate" or "rate_limiting"`)
+
+	cfg.Mode = consistentMode
+	assert.NoError(t, cfg.Validate())
+	cfg.Deterministic = true
+	assert.EqualError(t, cfg.Validate(), `deterministic is not supported in the "consistent" mode`)
+}
+
+func TestValidatePriorityAttributes(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.PriorityAttributes = []string{"debug.keep", ""}
+	assert.EqualError(t, cfg.Validate(), "priority_attributes must not contain an empty attribute")
+
+	cfg.PriorityAttributes = []string{"debug.keep", "sampling.priority", "debug.keep"}
+	assert.EqualError(t, cfg.Validate(), `duplicate priority attribute "debug.keep"`)
+}
+
+func TestValidateHashFromAttribute(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.HashFromAttribute = "tenant.id"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Deterministic = true
+	assert.EqualError(t, cfg.Validate(), "deterministic is not supported with hash_from_attribute")
+
+	cfg.Deterministic = false
+	cfg.Mode = consistentMode
+	assert.EqualError(t, cfg.Validate(), `hash_from_attribute is not supported in the "consistent" mode`)
+}
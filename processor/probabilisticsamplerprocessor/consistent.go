@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+const (
+	// hashMode samples the traces whose hash bucket is lower than the threshold, it is the default.
+	hashMode = "hash"
+	// consistentMode samples the traces following the OpenTelemetry consistent probability sampling,
+	// see https://opentelemetry.io/docs/reference/specification/trace/tracestate-probability-sampling/.
+	consistentMode = "consistent"
+
+	// otTraceStateKey is the key of the OpenTelemetry entry of the tracestate.
+	otTraceStateKey = "ot"
+	// maxRValue is the highest r-value, the r-value of the trace IDs whose 62 random bits are zero.
+	maxRValue = 62
+	// zeroPValue is the p-value of the zero probability, no span is sampled with it.
+	zeroPValue = 63
+)
+
+// otTraceState is the OpenTelemetry entry of a tracestate, with the other entries it is kept with.
+type otTraceState struct {
+	// p is the negative base-2 logarithm of the probability the span was sampled with, it is -1 when unknown.
+	p int
+	// r is the number of leading zeros of the random value of the trace, it is -1 when unknown.
+	r int
+	// fields are the fields of the OpenTelemetry entry other than p and r.
+	fields []string
+	// entries are the entries of the other vendors.
+	entries []string
+}
+
+// parseOTelTraceState parses the tracestate, the invalid p-values and r-values are ignored.
+func parseOTelTraceState(raw string) otTraceState {
+	ts := otTraceState{p: -1, r: -1}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.HasPrefix(entry, otTraceStateKey+"=") {
+			ts.entries = append(ts.entries, entry)
+			continue
+		}
+		for _, field := range strings.Split(strings.TrimPrefix(entry, otTraceStateKey+"="), ";") {
+			key, v, _ := strings.Cut(field, ":")
+			switch key {
+			case "p":
+				ts.p = parseTraceStateValue(v, zeroPValue)
+			case "r":
+				ts.r = parseTraceStateValue(v, maxRValue)
+			case "":
+			default:
+				ts.fields = append(ts.fields, field)
+			}
+		}
+	}
+	return ts
+}
+
+// parseTraceStateValue parses a p-value or r-value, it returns -1 when it isn't between 0 and max.
+func parseTraceStateValue(v string, max int) int {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 || n > max {
+		return -1
+	}
+	return n
+}
+
+// String returns the tracestate, the OpenTelemetry entry is first since it is the last updated one.
+func (ts otTraceState) String() string {
+	var fields []string
+	if ts.p >= 0 {
+		fields = append(fields, "p:"+strconv.Itoa(ts.p))
+	}
+	if ts.r >= 0 {
+		fields = append(fields, "r:"+strconv.Itoa(ts.r))
+	}
+	fields = append(fields, ts.fields...)
+	entries := ts.entries
+	if len(fields) > 0 {
+		entries = append([]string{otTraceStateKey + "=" + strings.Join(fields, ";")}, entries...)
+	}
+	return strings.Join(entries, ",")
+}
+
+// randomnessValue returns the r-value of a trace without one in its tracestate: the number of leading
+// zeros of the 62 lowest bits of the trace ID, which are random for the W3C trace IDs.
+func randomnessValue(traceID pcommon.TraceID) int {
+	random := binary.BigEndian.Uint64(traceID[8:]) & (1<<maxRValue - 1)
+	return bits.LeadingZeros64(random) - (64 - maxRValue)
+}
+
+// probabilityValue returns the p-value the trace is sampled with for the threshold. The probabilities
+// other than powers of two are sampled with one of the two adjacent p-values, chosen from the hash
+// bucket of the trace so that all its spans get the same one and the expected probability is the
+// threshold's one.
+func (tsp *tracesamplerprocessor) probabilityValue(traceID pcommon.TraceID, threshold uint32) int {
+	if threshold == 0 {
+		return zeroPValue
+	}
+	if threshold >= numHashBuckets {
+		return 0
+	}
+	probability := float64(threshold) / numHashBuckets
+	p := int(math.Floor(-math.Log2(probability)))
+	if p >= maxRValue {
+		return maxRValue
+	}
+	// the probability is between 2^-(p+1) and 2^-p, it is 2^-p with the probability q
+	q := probability*math.Exp2(float64(p+1)) - 1
+	if float64(tsp.hashBucket(traceID)) < q*numHashBuckets {
+		return p
+	}
+	return p + 1
+}
+
+// consistentDecision makes the decision of the span with the consistent probability sampling: it
+// is sampled when its r-value is higher or equal to the p-value, the highest of the one of the
+// threshold and of the one it was sampled with by a previous sampler. The p-value and r-value are
+// recorded in the tracestate of the sampled spans, the p-value is removed from the spans sampled
+// only because of their priority since their adjusted count isn't known.
+func (tsp *tracesamplerprocessor) consistentDecision(s ptrace.Span, threshold uint32, mustSample bool) bool {
+	ts := parseOTelTraceState(s.TraceState().AsRaw())
+	if ts.r < 0 {
+		ts.r = randomnessValue(s.TraceID())
+	}
+	if ts.p > ts.r {
+		// the span can't have been sampled with this p-value, it is inconsistent
+		ts.p = -1
+	}
+	p := tsp.probabilityValue(s.TraceID(), threshold)
+	if ts.p > p {
+		p = ts.p
+	}
+
+	sampled := p <= ts.r
+	switch {
+	case sampled:
+		ts.p = p
+	case mustSample:
+		sampled = true
+		ts.p = -1
+	}
+	if sampled {
+		s.TraceState().FromRaw(ts.String())
+	}
+	if tsp.debug && !sampled {
+		tsp.logger.Debug("Span dropped by the probabilistic sampler",
+			zap.String("trace_id", s.TraceID().HexString()),
+			zap.String("span_id", s.SpanID().HexString()),
+			zap.Int("p_value", p),
+			zap.Int("r_value", ts.r))
+	}
+	return sampled
+}
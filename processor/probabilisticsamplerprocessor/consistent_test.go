@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestParseOTelTraceState(t *testing.T) {
+	tests := []struct {
+		raw      string
+		p        int
+		r        int
+		expected string
+	}{
+		{raw: "", p: -1, r: -1, expected: ""},
+		{raw: "ot=p:2;r:10", p: 2, r: 10, expected: "ot=p:2;r:10"},
+		{raw: "vendor=abc, ot=r:10;x:y;p:2", p: 2, r: 10, expected: "ot=p:2;r:10;x:y,vendor=abc"},
+		{raw: "ot=p:64;r:63,vendor=abc", p: -1, r: -1, expected: "vendor=abc"},
+		{raw: "ot=p:a;r:5", p: -1, r: 5, expected: "ot=r:5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			ts := parseOTelTraceState(tt.raw)
+			assert.Equal(t, tt.p, ts.p)
+			assert.Equal(t, tt.r, ts.r)
+			assert.Equal(t, tt.expected, ts.String())
+		})
+	}
+}
+
+func TestRandomnessValue(t *testing.T) {
+	assert.Equal(t, 0, randomnessValue(pcommon.TraceID{8: 0x20}))
+	assert.Equal(t, 1, randomnessValue(pcommon.TraceID{8: 0x10}))
+	assert.Equal(t, 13, randomnessValue(pcommon.TraceID{9: 0x01}))
+	// the 2 highest bits of the random part aren't part of the 62 random bits
+	assert.Equal(t, 0, randomnessValue(pcommon.TraceID{0: 0xff, 8: 0xe0}))
+	assert.Equal(t, maxRValue, randomnessValue(pcommon.TraceID{0: 0xff, 8: 0xc0}))
+}
+
+func newConsistentConfig(percentage float32) *Config {
+	return &Config{
+		ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+		SamplingPercentage: percentage,
+		Mode:               consistentMode,
+	}
+}
+
+// sampledPValues returns the number of sampled spans per p-value, checking that their r-value is consistent.
+func sampledPValues(t *testing.T, sampled []ptrace.Traces) map[int]int {
+	pValues := map[int]int{}
+	for _, td := range sampled {
+		spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for i := 0; i < spans.Len(); i++ {
+			ts := parseOTelTraceState(spans.At(i).TraceState().AsRaw())
+			assert.Equal(t, randomnessValue(spans.At(i).TraceID()), ts.r)
+			assert.LessOrEqual(t, ts.p, ts.r)
+			pValues[ts.p]++
+		}
+	}
+	return pValues
+}
+
+func Test_tracesamplerprocessor_Consistent(t *testing.T) {
+	const spanCount = 8000
+	tests := []struct {
+		name       string
+		percentage float32
+		pValues    []int
+	}{
+		{name: "power of two", percentage: 25, pValues: []int{2}},
+		{name: "between powers of two", percentage: 37.5, pValues: []int{1, 2}},
+		{name: "all", percentage: 100, pValues: []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.TracesSink)
+			tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), newConsistentConfig(tt.percentage), sink)
+			require.NoError(t, err)
+			for _, td := range genRandomTestData(1, spanCount, "svc", 1) {
+				require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+			}
+
+			pValues := sampledPValues(t, sink.AllTraces())
+			var sampled int
+			for _, p := range tt.pValues {
+				assert.Greater(t, pValues[p], 0)
+				sampled += pValues[p]
+			}
+			assert.Len(t, pValues, len(tt.pValues))
+			assert.InDelta(t, float64(tt.percentage)/100, float64(sampled)/spanCount, 0.02)
+		})
+	}
+}
+
+func Test_tracesamplerprocessor_ConsistentTraceState(t *testing.T) {
+	tests := []struct {
+		name       string
+		percentage float32
+		traceState string
+		priority   int64
+		expected   string
+	}{
+		{
+			name:       "sampled upstream with a lower probability",
+			percentage: 50,
+			traceState: "ot=p:4;r:6,vendor=abc",
+			expected:   "ot=p:4;r:6,vendor=abc",
+		},
+		{
+			name:       "sampled upstream with a higher probability",
+			percentage: 12.5,
+			traceState: "vendor=abc,ot=p:1;r:6",
+			expected:   "ot=p:3;r:6,vendor=abc",
+		},
+		{
+			name:       "not sampled",
+			percentage: 12.5,
+			traceState: "ot=r:2",
+		},
+		{
+			name:       "inconsistent p-value",
+			percentage: 100,
+			traceState: "ot=p:5;r:2",
+			expected:   "ot=p:0;r:2",
+		},
+		{
+			name:       "sampling priority",
+			percentage: 12.5,
+			traceState: "ot=p:1;r:2",
+			priority:   1,
+			expected:   "ot=r:2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.TracesSink)
+			tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), newConsistentConfig(tt.percentage), sink)
+			require.NoError(t, err)
+
+			td := ptrace.NewTraces()
+			span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+			span.SetTraceID(pcommon.TraceID{1})
+			span.TraceState().FromRaw(tt.traceState)
+			if tt.priority > 0 {
+				span.Attributes().PutInt("sampling.priority", tt.priority)
+			}
+			require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+			if tt.expected == "" {
+				assert.Empty(t, sink.AllTraces())
+				return
+			}
+			require.Len(t, sink.AllTraces(), 1)
+			got := sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			assert.Equal(t, tt.expected, got.TraceState().AsRaw())
+		})
+	}
+}
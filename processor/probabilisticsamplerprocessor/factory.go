@@ -32,7 +32,10 @@ const (
 	stability = component.StabilityLevelBeta
 )
 
-var onceMetrics sync.Once
+var (
+	onceMetrics         sync.Once
+	onceDetailedMetrics sync.Once
+)
 
 // NewFactory returns a new factory for the Probabilistic sampler processor.
 func NewFactory() component.ProcessorFactory {
@@ -44,12 +47,15 @@ func NewFactory() component.ProcessorFactory {
 	return component.NewProcessorFactory(
 		typeStr,
 		createDefaultConfig,
-		component.WithTracesProcessor(createTracesProcessor, stability))
+		component.WithTracesProcessor(createTracesProcessor, stability),
+		component.WithMetricsProcessor(createMetricsProcessor, component.StabilityLevelAlpha))
 }
 
 func createDefaultConfig() component.ProcessorConfig {
 	return &Config{
 		ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+		HashAlgorithm:     murmur3HashAlgorithm,
+		Mode:              hashMode,
 	}
 }
 
@@ -60,5 +66,21 @@ func createTracesProcessor(
 	cfg component.ProcessorConfig,
 	nextConsumer consumer.Traces,
 ) (component.TracesProcessor, error) {
+	if set.MetricsLevel == configtelemetry.LevelDetailed {
+		onceDetailedMetrics.Do(func() {
+			// the views of the normal level are registered by the factory
+			_ = view.Register(detailedMetricViews()...)
+		})
+	}
 	return newTracesProcessor(ctx, set, cfg.(*Config), nextConsumer)
 }
+
+// createMetricsProcessor creates a metrics processor based on this config.
+func createMetricsProcessor(
+	ctx context.Context,
+	set component.ProcessorCreateSettings,
+	cfg component.ProcessorConfig,
+	nextConsumer consumer.Metrics,
+) (component.MetricsProcessor, error) {
+	return newMetricsProcessor(ctx, set, cfg.(*Config), nextConsumer)
+}
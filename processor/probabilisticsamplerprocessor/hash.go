@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	// murmur3HashAlgorithm is the algorithm the processor always used, it is the default
+	// so that upgraded collectors keep making the same sampling decisions.
+	murmur3HashAlgorithm = "murmur3"
+	fnvHashAlgorithm     = "fnv"
+	xxhashHashAlgorithm  = "xxhash"
+	sha256HashAlgorithm  = "sha256"
+)
+
+// hashFunc hashes the key with the seed, the sampling decision is made with its lowest bits.
+type hashFunc func(key []byte, seed uint32) uint32
+
+var hashFuncs = map[string]hashFunc{
+	murmur3HashAlgorithm: hash,
+	fnvHashAlgorithm:     fnvHash,
+	xxhashHashAlgorithm:  xxHash,
+	sha256HashAlgorithm:  sha256Hash,
+}
+
+// sum hashes the key with the seed and the algorithm of the processor. The hash functions are called
+// directly rather than through hashFuncs, so that the keys don't escape to the heap and hashing the
+// spans doesn't allocate.
+func (tsp *tracesamplerprocessor) sum(key []byte) uint32 {
+	switch tsp.hashAlgorithm {
+	case fnvHashAlgorithm:
+		return fnvHash(key, tsp.hashSeed)
+	case xxhashHashAlgorithm:
+		return xxHash(key, tsp.hashSeed)
+	case sha256HashAlgorithm:
+		return sha256Hash(key, tsp.hashSeed)
+	default:
+		return hash(key, tsp.hashSeed)
+	}
+}
+
+// fnvHash is the 32-bit FNV-1a hash of the seed, in little-endian order, followed by the key.
+func fnvHash(key []byte, seed uint32) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+
+	hash := uint32(offset32)
+	for i := 0; i < 4; i++ {
+		hash ^= (seed >> (8 * i)) & 0xff
+		hash *= prime32
+	}
+	for _, b := range key {
+		hash ^= uint32(b)
+		hash *= prime32
+	}
+	return hash
+}
+
+// xxHash is the 64-bit xxHash of the seed, in little-endian order, followed by the key,
+// folded to 32 bits.
+func xxHash(key []byte, seed uint32) uint32 {
+	// the buffer fits the trace IDs, so that hashing them doesn't allocate
+	var buf [4 + 16]byte
+	binary.LittleEndian.PutUint32(buf[:4], seed)
+	sum := xxhash.Sum64(append(buf[:4], key...))
+	return uint32(sum) ^ uint32(sum>>32)
+}
+
+// sha256Hash is the first 32 bits, in little-endian order, of the SHA-256 of the seed, in little-endian
+// order, followed by the key. It is the slowest algorithm, for the users requiring a cryptographic hash.
+func sha256Hash(key []byte, seed uint32) uint32 {
+	// the buffer fits the trace IDs, so that hashing them doesn't allocate
+	var buf [4 + 16]byte
+	binary.LittleEndian.PutUint32(buf[:4], seed)
+	sum := sha256.Sum256(append(buf[:4], key...))
+	return binary.LittleEndian.Uint32(sum[:4])
+}
@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/idutils"
+)
+
+func TestHashAlgorithmDefault(t *testing.T) {
+	// the buckets of the default algorithm must not change across versions, otherwise
+	// the collectors of a tier being upgraded make different sampling decisions
+	cfg := createDefaultConfig().(*Config)
+	cfg.HashSeed = 22
+	tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, tsp)
+
+	sampler := &tracesamplerprocessor{hashSeed: 22, hashAlgorithm: cfg.HashAlgorithm}
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	assert.Equal(t, hash(traceID[:], 22)&bitMaskHashBuckets, sampler.hashBucket(traceID))
+	assert.Equal(t, uint32(10172), sampler.hashBucket(traceID))
+}
+
+func TestFnvHash(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		traceID := idutils.UInt64ToTraceID(r.Uint64(), r.Uint64())
+		seed := r.Uint32()
+
+		h := fnv.New32a()
+		_ = binary.Write(h, binary.LittleEndian, seed)
+		_, _ = h.Write(traceID[:])
+		assert.Equal(t, h.Sum32(), fnvHash(traceID[:], seed))
+	}
+}
+
+func TestXxHash(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		traceID := idutils.UInt64ToTraceID(r.Uint64(), r.Uint64())
+		seed := r.Uint32()
+
+		d := xxhash.New()
+		_ = binary.Write(d, binary.LittleEndian, seed)
+		_, _ = d.Write(traceID[:])
+		sum := d.Sum64()
+		assert.Equal(t, uint32(sum)^uint32(sum>>32), xxHash(traceID[:], seed))
+	}
+
+	traceID := idutils.UInt64ToTraceID(r.Uint64(), r.Uint64())
+	assert.Zero(t, testing.AllocsPerRun(100, func() { xxHash(traceID[:], 22) }))
+}
+
+func TestSha256Hash(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		traceID := idutils.UInt64ToTraceID(r.Uint64(), r.Uint64())
+		seed := r.Uint32()
+
+		d := sha256.New()
+		_ = binary.Write(d, binary.LittleEndian, seed)
+		_, _ = d.Write(traceID[:])
+		assert.Equal(t, binary.LittleEndian.Uint32(d.Sum(nil)), sha256Hash(traceID[:], seed))
+	}
+}
+
+func TestHashBucketAllocs(t *testing.T) {
+	traceID := idutils.UInt64ToTraceID(1, 2)
+	span := ptrace.NewSpan()
+	span.SetTraceID(traceID)
+	span.Attributes().PutStr("tenant.id", "tenant-42")
+	resource := pcommon.NewResource()
+	for algorithm := range hashFuncs {
+		t.Run(algorithm, func(t *testing.T) {
+			tsp := &tracesamplerprocessor{hashSeed: 22, hashAlgorithm: algorithm}
+			assert.Equal(t, hashFuncs[algorithm](traceID[:], 22)&bitMaskHashBuckets, tsp.hashBucket(traceID))
+			assert.Zero(t, testing.AllocsPerRun(100, func() { tsp.hashBucket(traceID) }))
+
+			tsp.hashAttribute = "tenant.id"
+			assert.Equal(t, hashFuncs[algorithm]([]byte("tenant-42"), 22)&bitMaskHashBuckets, tsp.spanHashBucket(span, resource))
+			assert.Zero(t, testing.AllocsPerRun(100, func() { tsp.spanHashBucket(span, resource) }))
+		})
+	}
+}
+
+// TestHashAlgorithmsDistribution checks that the hash buckets of random trace IDs are
+// uniformly distributed, and that the decisions made with different seeds are independent.
+func TestHashAlgorithmsDistribution(t *testing.T) {
+	const (
+		numTraces = 1 << 17
+		numBins   = 16
+		// the chi-squared value with 15 degrees of freedom exceeded with a 0.1% probability
+		maxChiSquared = 37.7
+	)
+	for algorithm, hashFn := range hashFuncs {
+		t.Run(algorithm, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			bins := make([]int, numBins)
+			sampledBySeed1, sampledByBoth := 0, 0
+			// 10% of the hash buckets
+			threshold := uint32(numHashBuckets / 10)
+			for i := 0; i < numTraces; i++ {
+				traceID := idutils.UInt64ToTraceID(r.Uint64(), r.Uint64())
+				bucket := hashFn(traceID[:], 1) & bitMaskHashBuckets
+				bins[bucket*numBins/numHashBuckets]++
+
+				if bucket < threshold {
+					sampledBySeed1++
+					if hashFn(traceID[:], 2)&bitMaskHashBuckets < threshold {
+						sampledByBoth++
+					}
+				}
+			}
+
+			expected := float64(numTraces) / numBins
+			chiSquared := 0.0
+			for _, count := range bins {
+				chiSquared += (float64(count) - expected) * (float64(count) - expected) / expected
+			}
+			assert.Less(t, chiSquared, maxChiSquared, "the hash buckets aren't uniformly distributed: %v", bins)
+
+			assert.InDelta(t, 0.1, float64(sampledBySeed1)/numTraces, 0.005)
+			assert.InDelta(t, 0.1, float64(sampledByBoth)/float64(sampledBySeed1), 0.01,
+				"the traces sampled with a seed should be sampled at the same rate with another seed")
+		})
+	}
+}
+
+func BenchmarkHash(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	keys := make([][]byte, 1024)
+	for i := range keys {
+		traceID := idutils.UInt64ToTraceID(r.Uint64(), r.Uint64())
+		keys[i] = traceID[:]
+	}
+	for _, algorithm := range []string{murmur3HashAlgorithm, fnvHashAlgorithm, xxhashHashAlgorithm, sha256HashAlgorithm} {
+		hashFn := hashFuncs[algorithm]
+		b.Run(algorithm, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				hashFn(keys[i%len(keys)], 22)
+			}
+		})
+	}
+}
+
+// BenchmarkSpanHashBucket measures the hot path of the sampling decisions, which must not allocate.
+func BenchmarkSpanHashBucket(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	spans := ptrace.NewSpanSlice()
+	for i := 0; i < 1024; i++ {
+		span := spans.AppendEmpty()
+		span.SetTraceID(idutils.UInt64ToTraceID(r.Uint64(), r.Uint64()))
+		span.Attributes().PutStr("tenant.id", "tenant-"+strconv.Itoa(i%64))
+	}
+	resource := pcommon.NewResource()
+	for _, hashAttribute := range []string{"", "tenant.id"} {
+		for _, algorithm := range []string{murmur3HashAlgorithm, fnvHashAlgorithm, xxhashHashAlgorithm, sha256HashAlgorithm} {
+			tsp := &tracesamplerprocessor{hashSeed: 22, hashAlgorithm: algorithm, hashAttribute: hashAttribute}
+			name := algorithm + "/trace_id"
+			if hashAttribute != "" {
+				name = algorithm + "/attribute"
+			}
+			b.Run(name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					tsp.spanHashBucket(spans.At(i%spans.Len()), resource)
+				}
+			})
+		}
+	}
+}
@@ -26,17 +26,22 @@ import (
 var (
 	tagPolicyKey, _  = tag.NewKey("policy")
 	tagSampledKey, _ = tag.NewKey("sampled")
+	tagStratumKey, _ = tag.NewKey("stratum")
+	tagServiceKey, _ = tag.NewKey("service")
 
-	statCountTracesSampled = stats.Int64("count_traces_sampled", "Count of traces that were sampled or not", stats.UnitDimensionless)
+	statCountTracesSampled     = stats.Int64("count_traces_sampled", "Count of traces that were sampled or not", stats.UnitDimensionless)
+	statCountDataPointsSampled = stats.Int64("count_data_points_sampled", "Count of metric data points that were sampled or not", stats.UnitDimensionless)
+	statCountDecisions         = stats.Int64("count_sampling_decisions", "Count of the spans sampled or not, by the policy deciding it and by service", stats.UnitDimensionless)
 )
 
-// SamplingProcessorMetricViews return the metrics views according to given telemetry level.
+// SamplingProcessorMetricViews return the metrics views according to given telemetry level. The views of the
+// decisions by service are only returned at the detailed level, as there is a series per service.
 func SamplingProcessorMetricViews(level configtelemetry.Level) []*view.View {
 	if level == configtelemetry.LevelNone {
 		return nil
 	}
 
-	sampledTagKeys := []tag.Key{tagPolicyKey, tagSampledKey}
+	sampledTagKeys := []tag.Key{tagPolicyKey, tagSampledKey, tagStratumKey}
 	countTracesSampledView := &view.View{
 		Name:        obsreport.BuildProcessorCustomMetricName(typeStr, statCountTracesSampled.Name()),
 		Measure:     statCountTracesSampled,
@@ -45,7 +50,32 @@ func SamplingProcessorMetricViews(level configtelemetry.Level) []*view.View {
 		Aggregation: view.Sum(),
 	}
 
-	return []*view.View{
+	countDataPointsSampledView := &view.View{
+		Name:        obsreport.BuildProcessorCustomMetricName(typeStr, statCountDataPointsSampled.Name()),
+		Measure:     statCountDataPointsSampled,
+		Description: statCountDataPointsSampled.Description(),
+		TagKeys:     []tag.Key{tagSampledKey},
+		Aggregation: view.Sum(),
+	}
+
+	views := []*view.View{
 		countTracesSampledView,
+		countDataPointsSampledView,
+	}
+	if level < configtelemetry.LevelDetailed {
+		return views
+	}
+	return append(views, detailedMetricViews()...)
+}
+
+// detailedMetricViews returns the views only registered at the detailed telemetry level.
+func detailedMetricViews() []*view.View {
+	countDecisionsView := &view.View{
+		Name:        obsreport.BuildProcessorCustomMetricName(typeStr, statCountDecisions.Name()),
+		Measure:     statCountDecisions,
+		Description: statCountDecisions.Description(),
+		TagKeys:     []tag.Key{tagPolicyKey, tagSampledKey, tagServiceKey},
+		Aggregation: view.Sum(),
 	}
+	return []*view.View{countDecisionsView}
 }
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestSamplingProcessorMetricViews(t *testing.T) {
+	assert.Empty(t, SamplingProcessorMetricViews(configtelemetry.LevelNone))
+	assert.Len(t, SamplingProcessorMetricViews(configtelemetry.LevelNormal), 2)
+	assert.Len(t, SamplingProcessorMetricViews(configtelemetry.LevelDetailed), 3)
+}
+
+func Test_tracesamplerprocessor_DetailedMetrics(t *testing.T) {
+	// reset the data recorded by the other tests
+	views := detailedMetricViews()
+	view.Unregister(views...)
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+		Services:          []ServiceConfig{{Name: "checkout", SamplingPercentage: 100}},
+		Condition:         `name != "healthz"`,
+	}
+	set := componenttest.NewNopProcessorCreateSettings()
+	set.MetricsLevel = configtelemetry.LevelDetailed
+	tsp, err := createTracesProcessor(context.Background(), set, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	for _, service := range []string{"checkout", "cart"} {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		for i, priority := range []int64{-1, 0, 1} {
+			span := spans.AppendEmpty()
+			span.SetName(fmt.Sprintf("span-%d", i))
+			span.SetTraceID([16]byte{byte(i + 1)})
+			if priority >= 0 {
+				span.Attributes().PutInt("sampling.priority", priority)
+			}
+		}
+		spans.AppendEmpty().SetName("healthz")
+	}
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+	rows, err := view.RetrieveData(obsreport.BuildProcessorCustomMetricName(typeStr, statCountDecisions.Name()))
+	require.NoError(t, err)
+	decisions := map[string]int64{}
+	for _, row := range rows {
+		tags := map[string]string{}
+		for _, tg := range row.Tags {
+			tags[tg.Key.Name()] = tg.Value
+		}
+		decisions[fmt.Sprintf("%s/%s/%s", tags["service"], tags["policy"], tags["sampled"])] = int64(row.Data.(*view.SumData).Value)
+	}
+	assert.Equal(t, map[string]int64{
+		"checkout/trace_id_hash/true":      1,
+		"checkout/priority_override/false": 1,
+		"checkout/priority_override/true":  1,
+		"checkout/condition_bypass/true":   1,
+		"cart/trace_id_hash/false":         1,
+		"cart/priority_override/false":     1,
+		"cart/priority_override/true":      1,
+		"cart/condition_bypass/true":       1,
+	}, decisions)
+}
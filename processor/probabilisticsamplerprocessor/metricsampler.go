@@ -0,0 +1,230 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// dataPointGranularity samples each data point.
+	dataPointGranularity = "data_point"
+	// resourceGranularity samples the data points of a resource together.
+	resourceGranularity = "resource"
+)
+
+// metricsSamplerProcessor samples the data points, or the resources, whose hash bucket is lower
+// than the threshold of the sampling percentage.
+type metricsSamplerProcessor struct {
+	threshold       uint32
+	hashSeed        uint32
+	hash            hashFunc
+	attributes      []string
+	sampleResources bool
+}
+
+// newMetricsProcessor returns a processor.MetricsProcessor sampling the data points according to the given
+// configuration.
+func newMetricsProcessor(ctx context.Context, set component.ProcessorCreateSettings, cfg *Config, nextConsumer consumer.Metrics) (component.MetricsProcessor, error) {
+	hashAlgorithm := cfg.HashAlgorithm
+	if hashAlgorithm == "" {
+		hashAlgorithm = murmur3HashAlgorithm
+	}
+	msp := &metricsSamplerProcessor{
+		threshold:       percentageThreshold(cfg.SamplingPercentage),
+		hashSeed:        cfg.HashSeed,
+		hash:            hashFuncs[hashAlgorithm],
+		attributes:      cfg.Metrics.HashAttributes,
+		sampleResources: cfg.Metrics.Granularity == resourceGranularity,
+	}
+
+	return processorhelper.NewMetricsProcessor(
+		ctx,
+		set,
+		cfg,
+		nextConsumer,
+		msp.processMetrics,
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+}
+
+func (msp *metricsSamplerProcessor) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	var sampled, dropped int64
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		resource := rm.Resource()
+		if msp.sampleResources {
+			keep := msp.sampled(msp.resourceKey(resource))
+			points := int64(resourceDataPointCount(rm))
+			if keep {
+				sampled += points
+			} else {
+				dropped += points
+			}
+			return !keep
+		}
+
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				if dataPointCount(m) == 0 {
+					return false
+				}
+				removeDataPoints(m, func(attributes pcommon.Map) bool {
+					keep := msp.sampled(msp.dataPointKey(m.Name(), attributes, resource))
+					if keep {
+						sampled++
+					} else {
+						dropped++
+					}
+					return !keep
+				})
+				// Filter out the metrics whose data points were all dropped
+				return dataPointCount(m) == 0
+			})
+			// Filter out empty ScopeMetrics
+			return sm.Metrics().Len() == 0
+		})
+		// Filter out empty ResourceMetrics
+		return rm.ScopeMetrics().Len() == 0
+	})
+
+	msp.recordDecisions(ctx, true, sampled)
+	msp.recordDecisions(ctx, false, dropped)
+	if md.ResourceMetrics().Len() == 0 {
+		return md, processorhelper.ErrSkipProcessingData
+	}
+	return md, nil
+}
+
+func (msp *metricsSamplerProcessor) recordDecisions(ctx context.Context, sampled bool, points int64) {
+	if points == 0 {
+		return
+	}
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Upsert(tagSampledKey, strconv.FormatBool(sampled))},
+		statCountDataPointsSampled.M(points),
+	)
+}
+
+// sampled returns whether the hash bucket of the key is lower than the threshold.
+func (msp *metricsSamplerProcessor) sampled(key []byte) bool {
+	return msp.hash(key, msp.hashSeed)&bitMaskHashBuckets < msp.threshold
+}
+
+// dataPointKey returns the key of the data point: the values of the hashed attributes of the data point,
+// or of its resource, or the identity of its stream when it has none of them.
+func (msp *metricsSamplerProcessor) dataPointKey(name string, attributes pcommon.Map, resource pcommon.Resource) []byte {
+	if key, ok := attributesKey(msp.attributes, attributes, resource.Attributes()); ok {
+		return key
+	}
+	return append(append([]byte(name), 0), sortedAttributesKey(attributes)...)
+}
+
+// resourceKey returns the key of the resource: the values of its hashed attributes, or all its attributes
+// when it has none of them.
+func (msp *metricsSamplerProcessor) resourceKey(resource pcommon.Resource) []byte {
+	if key, ok := attributesKey(msp.attributes, resource.Attributes()); ok {
+		return key
+	}
+	return sortedAttributesKey(resource.Attributes())
+}
+
+// attributesKey returns the values of the attributes, each one taken from the first of the maps having it,
+// and whether any of them was found.
+func attributesKey(names []string, maps ...pcommon.Map) ([]byte, bool) {
+	var key []byte
+	found := false
+	for _, name := range names {
+		for _, m := range maps {
+			if v, ok := m.Get(name); ok {
+				key = append(key, v.AsString()...)
+				found = true
+				break
+			}
+		}
+		key = append(key, 0)
+	}
+	return key, found
+}
+
+// sortedAttributesKey returns the attributes of the map, sorted by name.
+func sortedAttributesKey(m pcommon.Map) []byte {
+	names := make([]string, 0, m.Len())
+	m.Range(func(k string, _ pcommon.Value) bool {
+		names = append(names, k)
+		return true
+	})
+	sort.Strings(names)
+
+	var key []byte
+	for _, name := range names {
+		v, _ := m.Get(name)
+		key = append(append(append(key, name...), '='), v.AsString()...)
+		key = append(key, 0)
+	}
+	return key
+}
+
+func resourceDataPointCount(rm pmetric.ResourceMetrics) int {
+	count := 0
+	for i := 0; i < rm.ScopeMetrics().Len(); i++ {
+		metrics := rm.ScopeMetrics().At(i).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			count += dataPointCount(metrics.At(j))
+		}
+	}
+	return count
+}
+
+func dataPointCount(m pmetric.Metric) int {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		return m.Gauge().DataPoints().Len()
+	case pmetric.MetricTypeSum:
+		return m.Sum().DataPoints().Len()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().DataPoints().Len()
+	case pmetric.MetricTypeSummary:
+		return m.Summary().DataPoints().Len()
+	}
+	return 0
+}
+
+// removeDataPoints removes the data points of the metric for which remove returns true.
+func removeDataPoints(m pmetric.Metric, remove func(pcommon.Map) bool) {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		m.Gauge().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool { return remove(dp.Attributes()) })
+	case pmetric.MetricTypeSum:
+		m.Sum().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool { return remove(dp.Attributes()) })
+	case pmetric.MetricTypeHistogram:
+		m.Histogram().DataPoints().RemoveIf(func(dp pmetric.HistogramDataPoint) bool { return remove(dp.Attributes()) })
+	case pmetric.MetricTypeExponentialHistogram:
+		m.ExponentialHistogram().DataPoints().RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool { return remove(dp.Attributes()) })
+	case pmetric.MetricTypeSummary:
+		m.Summary().DataPoints().RemoveIf(func(dp pmetric.SummaryDataPoint) bool { return remove(dp.Attributes()) })
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestValidateMetrics(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics = MetricsConfig{Granularity: resourceGranularity, HashAttributes: []string{"user.id"}}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Metrics.Granularity = "metric"
+	assert.EqualError(t, cfg.Validate(), `invalid metrics: unsupported granularity "metric", must be "data_point" or "resource"`)
+
+	cfg.Metrics = MetricsConfig{HashAttributes: []string{"user.id", ""}}
+	assert.EqualError(t, cfg.Validate(), "invalid metrics: hash_attributes must not contain an empty attribute")
+}
+
+// newSampledMetrics returns the metrics of 100 resources, each with a gauge and a histogram of 10 users.
+func newSampledMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for i := 0; i < 100; i++ {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("host.name", fmt.Sprintf("host-%d", i))
+		metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+		gauge := metrics.AppendEmpty()
+		gauge.SetName("sessions")
+		histogram := metrics.AppendEmpty()
+		histogram.SetName("latency")
+		histogram.SetEmptyHistogram()
+		gaugePoints := gauge.SetEmptyGauge().DataPoints()
+		for j := 0; j < 10; j++ {
+			gaugePoints.AppendEmpty().Attributes().PutStr("user.id", fmt.Sprintf("user-%d", j))
+			histogram.Histogram().DataPoints().AppendEmpty().Attributes().PutStr("user.id", fmt.Sprintf("user-%d", j))
+		}
+	}
+	return md
+}
+
+// sampledValues returns the values of the attribute of the sampled data points, from the data point or the resource.
+func sampledValues(md pmetric.Metrics, name string) map[string]int {
+	values := map[string]int{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		resourceValue, _ := rm.Resource().Attributes().Get(name)
+		metrics := rm.ScopeMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			// iterate over the data points without removing any
+			removeDataPoints(metrics.At(j), func(attributes pcommon.Map) bool {
+				v, ok := attributes.Get(name)
+				if !ok {
+					v = resourceValue
+				}
+				values[v.AsString()]++
+				return false
+			})
+		}
+	}
+	return values
+}
+
+func Test_metricssamplerprocessor(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics MetricsConfig
+		// attribute is the attribute whose data points must be sampled or dropped together.
+		attribute string
+		// points is the number of data points per value of the attribute.
+		points int
+	}{
+		{
+			name:      "data points by attribute",
+			metrics:   MetricsConfig{HashAttributes: []string{"user.id"}},
+			attribute: "user.id",
+			points:    200,
+		},
+		{
+			name:      "data points by resource attribute",
+			metrics:   MetricsConfig{HashAttributes: []string{"host.name"}},
+			attribute: "host.name",
+			points:    20,
+		},
+		{
+			name:      "resources",
+			metrics:   MetricsConfig{Granularity: resourceGranularity},
+			attribute: "host.name",
+			points:    20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 50,
+				HashSeed:           22,
+				Metrics:            tt.metrics,
+			}
+			sink := new(consumertest.MetricsSink)
+			mp, err := newMetricsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+			require.NoError(t, err)
+
+			require.NoError(t, mp.ConsumeMetrics(context.Background(), newSampledMetrics()))
+
+			require.Len(t, sink.AllMetrics(), 1)
+			values := sampledValues(sink.AllMetrics()[0], tt.attribute)
+			assert.NotEmpty(t, values)
+			for value, points := range values {
+				assert.Equal(t, tt.points, points, "all the data points of %q should be sampled", value)
+			}
+			sampled := sink.AllMetrics()[0].DataPointCount()
+			assert.Greater(t, sampled, 2000/4, "around half of the data points should be sampled")
+			assert.Less(t, sampled, 2000*3/4, "around half of the data points should be sampled")
+		})
+	}
+}
+
+func Test_metricssamplerprocessor_Streams(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+		SamplingPercentage: 30,
+	}
+	sink := new(consumertest.MetricsSink)
+	mp, err := newMetricsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	// the same streams are sampled in each batch
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), newSampledMetrics()))
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), newSampledMetrics()))
+
+	require.Len(t, sink.AllMetrics(), 2)
+	assert.Equal(t, sink.AllMetrics()[0], sink.AllMetrics()[1])
+	sampled := sink.AllMetrics()[0].DataPointCount()
+	assert.Greater(t, sampled, 2000/10)
+	assert.Less(t, sampled, 2000/2)
+	// the empty metrics are removed
+	for i := 0; i < sink.AllMetrics()[0].ResourceMetrics().Len(); i++ {
+		metrics := sink.AllMetrics()[0].ResourceMetrics().At(i).ScopeMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			assert.NotZero(t, dataPointCount(metrics.At(j)))
+		}
+	}
+}
+
+func Test_metricssamplerprocessor_DropAll(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+	}
+	sink := new(consumertest.MetricsSink)
+	mp, err := newMetricsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), newSampledMetrics()))
+	assert.Len(t, sink.AllMetrics(), 0)
+}
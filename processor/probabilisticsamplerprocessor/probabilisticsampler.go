@@ -16,16 +16,23 @@ package probabilisticsamplerprocessor // import "github.com/open-telemetry/opent
 
 import (
 	"context"
+	"math"
+	"sort"
 	"strconv"
+	"sync/atomic"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor/processorhelper"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
 // samplingPriority has the semantic result of parsing the "sampling.priority"
@@ -50,23 +57,93 @@ const (
 	numHashBuckets        = 0x4000 // Using a power of 2 to avoid division.
 	bitMaskHashBuckets    = numHashBuckets - 1
 	percentageScaleFactor = numHashBuckets / 100.0
+
+	// defaultPriorityAttribute is the attribute overriding the sampling decision when none is configured.
+	defaultPriorityAttribute = "sampling.priority"
+
+	// The attributes added to the sampled spans when debugging is enabled.
+	hashBucketAttribute = "sampling.hash_bucket"
+	thresholdAttribute  = "sampling.threshold"
 )
 
 type tracesamplerprocessor struct {
-	scaledSamplingRate uint32
+	// rates holds the *samplingRates, replaced when the sampling rules are reloaded.
+	rates              atomic.Value
+	reloader           *reloader
+	admin              *adminServer
 	hashSeed           uint32
-	logger             *zap.Logger
+	hashAlgorithm      string
+	hashAttribute      string
+	priorityAttributes []string
+	removePriority     bool
+	adjustedAttribute  string
+	adjustedCount      bool
+	adjustedTraceState bool
+	debug              bool
+	deterministic      bool
+	consistent         bool
+	strata             []*stratum
+	spanNameRules      []*spanNameRule
+	target             *quota
+	limiter            *rateLimiter
+	condition          *ottl.Statement[ottlspan.TransformContext]
+	dropUnmatched      bool
+	// detailedMetrics is set when the decisions are recorded by service, at the detailed telemetry level.
+	detailedMetrics bool
+	logger          *zap.Logger
 }
 
 // newTracesProcessor returns a processor.TracesProcessor that will perform head sampling according to the given
 // configuration.
 func newTracesProcessor(ctx context.Context, set component.ProcessorCreateSettings, cfg *Config, nextConsumer consumer.Traces) (component.TracesProcessor, error) {
+	hashAlgorithm := cfg.HashAlgorithm
+	if hashAlgorithm == "" {
+		hashAlgorithm = murmur3HashAlgorithm
+	}
+	priorityAttributes := cfg.PriorityAttributes
+	if len(priorityAttributes) == 0 {
+		priorityAttributes = []string{defaultPriorityAttribute}
+	}
+	adjustedAttribute := cfg.AdjustedCount.Attribute
+	if adjustedAttribute == "" && cfg.Mode == targetRateMode {
+		adjustedAttribute = probabilityAttribute
+	}
+	var condition *ottl.Statement[ottlspan.TransformContext]
+	if cfg.Condition != "" {
+		var err error
+		if condition, err = parseCondition(cfg.Condition, set.TelemetrySettings); err != nil {
+			return nil, err
+		}
+	}
 	tsp := &tracesamplerprocessor{
-		// Adjust sampling percentage on private so recalculations are avoided.
-		scaledSamplingRate: uint32(cfg.SamplingPercentage * percentageScaleFactor),
 		hashSeed:           cfg.HashSeed,
+		hashAlgorithm:      hashAlgorithm,
+		hashAttribute:      cfg.HashFromAttribute,
+		priorityAttributes: priorityAttributes,
+		removePriority:     cfg.RemovePriorityAttributes,
+		adjustedAttribute:  adjustedAttribute,
+		adjustedCount:      cfg.AdjustedCount.Record == recordAdjustedCount,
+		adjustedTraceState: cfg.AdjustedCount.TraceState,
+		debug:              cfg.Debug,
+		deterministic:      cfg.Deterministic,
+		consistent:         cfg.Mode == consistentMode,
+		strata:             newStrata(cfg.Strata),
+		spanNameRules:      newSpanNameRules(cfg.SpanNames),
+		target:             newTargetQuota(cfg),
+		limiter:            newRateLimiter(cfg),
+		condition:          condition,
+		dropUnmatched:      cfg.Unmatched == dropUnmatched,
+		detailedMetrics:    set.MetricsLevel == configtelemetry.LevelDetailed,
 		logger:             set.Logger,
 	}
+	tsp.rates.Store(newSamplingRates(cfg.SamplingPercentage, cfg.Services))
+	var err error
+	if tsp.reloader, err = newReloader(cfg, set.Logger, func(rates *samplingRates) { tsp.rates.Store(rates) }); err != nil {
+		return nil, err
+	}
+	if tsp.admin, err = newAdminServer(cfg, set.TelemetrySettings, tsp.currentRates, func(rates *samplingRates) { tsp.rates.Store(rates) }); err != nil {
+		return nil, err
+	}
 
 	return processorhelper.NewTracesProcessor(
 		ctx,
@@ -74,14 +151,67 @@ func newTracesProcessor(ctx context.Context, set component.ProcessorCreateSettin
 		cfg,
 		nextConsumer,
 		tsp.processTraces,
-		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}),
+		processorhelper.WithStart(tsp.start),
+		processorhelper.WithShutdown(tsp.shutdown))
+}
+
+func (tsp *tracesamplerprocessor) start(_ context.Context, host component.Host) error {
+	if tsp.reloader != nil {
+		tsp.reloader.start()
+	}
+	if tsp.admin != nil {
+		return tsp.admin.start(host)
+	}
+	return nil
+}
+
+func (tsp *tracesamplerprocessor) shutdown(context.Context) error {
+	if tsp.reloader != nil {
+		tsp.reloader.shutdown()
+	}
+	if tsp.admin != nil {
+		return tsp.admin.shutdown()
+	}
+	return nil
+}
+
+// currentRates returns the rates the spans are currently sampled with.
+func (tsp *tracesamplerprocessor) currentRates() *samplingRates {
+	if rates, ok := tsp.rates.Load().(*samplingRates); ok {
+		return rates
+	}
+	return &samplingRates{}
 }
 
 func (tsp *tracesamplerprocessor) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	var decisions map[pcommon.TraceID]stratumDecision
+	if len(tsp.strata) > 0 {
+		decisions = stratify(tsp.strata, td)
+	}
+	rates := tsp.currentRates()
+	threshold := rates.scaledSamplingRate
+	if tsp.deterministic {
+		threshold = tsp.batchThreshold(td, decisions, rates.samplingPercentage)
+	}
+
 	td.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		resource := rs.Resource()
+		serviceThreshold, hasServiceThreshold := rates.serviceThreshold(resource)
+		service := ""
+		if tsp.detailedMetrics {
+			service = serviceName(resource)
+		}
 		rs.ScopeSpans().RemoveIf(func(ils ptrace.ScopeSpans) bool {
-			ils.Spans().RemoveIf(func(s ptrace.Span) bool {
-				sp := parseSpanSamplingPriority(s)
+			ils.Spans().RemoveIf(func(s ptrace.Span) (dropped bool) {
+				sp := parseSpanSamplingPriority(s, tsp.priorityAttributes)
+				if tsp.removePriority {
+					defer func() {
+						if !dropped {
+							tsp.removePriorityAttributes(s)
+						}
+					}()
+				}
 				if sp == doNotSampleSpan {
 					// The OpenTelemetry mentions this as a "hint" we take a stronger
 					// approach and do not sample the span since some may use it to
@@ -91,6 +221,7 @@ func (tsp *tracesamplerprocessor) processTraces(ctx context.Context, td ptrace.T
 						[]tag.Mutator{tag.Upsert(tagPolicyKey, "sampling_priority"), tag.Upsert(tagSampledKey, "false")},
 						statCountTracesSampled.M(int64(1)),
 					)
+					tsp.recordDecision(ctx, "priority_override", service, false)
 					return true
 				}
 
@@ -100,26 +231,69 @@ func (tsp *tracesamplerprocessor) processTraces(ctx context.Context, td ptrace.T
 					statCountTracesSampled.M(int64(1)),
 				)
 
-				// If one assumes random trace ids hashing may seems avoidable, however, traces can be coming from sources
-				// with various different criteria to generate trace id and perhaps were already sampled without hashing.
-				// Hashing here prevents bias due to such systems.
-				tidBytes := s.TraceID()
-				sampled := sp == mustSampleSpan ||
-					hash(tidBytes[:], tsp.hashSeed)&bitMaskHashBuckets < tsp.scaledSamplingRate
-
-				if sampled {
-					_ = stats.RecordWithTags(
-						ctx,
-						[]tag.Mutator{tag.Upsert(tagPolicyKey, "trace_id_hash"), tag.Upsert(tagSampledKey, "true")},
-						statCountTracesSampled.M(int64(1)),
-					)
-				} else {
+				if !tsp.matchesCondition(ctx, s, ils.Scope(), resource) {
+					// the spans not matching the condition aren't subjected to the sampling
+					sampled := !tsp.dropUnmatched || sp == mustSampleSpan
 					_ = stats.RecordWithTags(
 						ctx,
-						[]tag.Mutator{tag.Upsert(tagPolicyKey, "trace_id_hash"), tag.Upsert(tagSampledKey, "false")},
+						[]tag.Mutator{tag.Upsert(tagPolicyKey, "condition_unmatched"), tag.Upsert(tagSampledKey, strconv.FormatBool(sampled))},
 						statCountTracesSampled.M(int64(1)),
 					)
+					tsp.recordDecision(ctx, "condition_bypass", service, sampled)
+					return !sampled
+				}
+
+				// If one assumes random trace ids hashing may seems avoidable, however, traces can be coming from sources
+				// with various different criteria to generate trace id and perhaps were already sampled without hashing.
+				// Hashing here prevents bias due to such systems.
+				spanThreshold := threshold
+				policy := "trace_id_hash"
+				switch {
+				case tsp.consistent:
+					policy = "consistent_probability"
+				case tsp.target != nil:
+					policy = "target_rate"
+				case tsp.limiter != nil:
+					policy = "rate_limiting"
+				}
+				mutators := []tag.Mutator{tag.Upsert(tagPolicyKey, policy)}
+				if decision, ok := decisions[s.TraceID()]; ok {
+					spanThreshold = decision.threshold
+					mutators = append(mutators, tag.Upsert(tagStratumKey, decision.stratum))
+				} else if spanNameThreshold, ok := tsp.spanNameThreshold(s); ok {
+					spanThreshold = spanNameThreshold
+				} else if hasServiceThreshold {
+					spanThreshold = serviceThreshold
+				} else if tsp.target != nil {
+					spanThreshold = tsp.target.threshold(s.TraceID())
+				}
+				sampled := sp == mustSampleSpan
+				if tsp.limiter != nil {
+					// the spans sampled because of their priority don't take tokens
+					sampled = sampled || tsp.limiter.sample(s.TraceID())
+				} else if tsp.consistent {
+					sampled = tsp.consistentDecision(s, spanThreshold, sampled)
+				} else if !sampled || tsp.debug {
+					bucket := tsp.spanHashBucket(s, resource)
+					sampled = sampled || bucket < spanThreshold
+					if tsp.debug {
+						tsp.debugDecision(s, bucket, spanThreshold, sampled)
+					}
 				}
+
+				if sampled && sp != mustSampleSpan {
+					tsp.recordAdjustedCount(s, spanThreshold)
+				}
+
+				_ = stats.RecordWithTags(
+					ctx,
+					append(mutators, tag.Upsert(tagSampledKey, strconv.FormatBool(sampled))),
+					statCountTracesSampled.M(int64(1)),
+				)
+				if sp == mustSampleSpan {
+					policy = "priority_override"
+				}
+				tsp.recordDecision(ctx, policy, service, sampled)
 				return !sampled
 			})
 			// Filter out empty ScopeMetrics
@@ -134,18 +308,123 @@ func (tsp *tracesamplerprocessor) processTraces(ctx context.Context, td ptrace.T
 	return td, nil
 }
 
-// parseSpanSamplingPriority checks if the span has the "sampling.priority" tag to
-// decide if the span should be sampled or not. The usage of the tag follows the
-// OpenTracing semantic tags:
+// recordDecision counts the decision made for a span of the service by the policy, at the detailed
+// telemetry level only.
+func (tsp *tracesamplerprocessor) recordDecision(ctx context.Context, policy string, service string, sampled bool) {
+	if !tsp.detailedMetrics {
+		return
+	}
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{
+			tag.Upsert(tagPolicyKey, policy),
+			tag.Upsert(tagSampledKey, strconv.FormatBool(sampled)),
+			tag.Upsert(tagServiceKey, service),
+		},
+		statCountDecisions.M(int64(1)),
+	)
+}
+
+// hashBucket returns the bucket of the trace ID, which is sampled if lower than the threshold.
+func (tsp *tracesamplerprocessor) hashBucket(traceID pcommon.TraceID) uint32 {
+	return tsp.sum(traceID[:]) & bitMaskHashBuckets
+}
+
+// spanHashBucket returns the bucket of the value of the hashed attribute of the span, or of its resource, and
+// the bucket of its trace ID when the attribute isn't configured or is missing.
+func (tsp *tracesamplerprocessor) spanHashBucket(s ptrace.Span, resource pcommon.Resource) uint32 {
+	if tsp.hashAttribute == "" {
+		return tsp.hashBucket(s.TraceID())
+	}
+	v, ok := s.Attributes().Get(tsp.hashAttribute)
+	if !ok {
+		if v, ok = resource.Attributes().Get(tsp.hashAttribute); !ok {
+			return tsp.hashBucket(s.TraceID())
+		}
+	}
+	return tsp.sum([]byte(v.AsString())) & bitMaskHashBuckets
+}
+
+// batchThreshold returns the threshold sampling the given percentage of the distinct trace IDs
+// of the batch whose decision is made by hashing, the ones with the lowest hash buckets. More
+// traces are sampled when several trace IDs share the hash bucket of the last sampled one. The
+// traces sampled at the rate of a stratum aren't counted.
+func (tsp *tracesamplerprocessor) batchThreshold(td ptrace.Traces, decisions map[pcommon.TraceID]stratumDecision, samplingPercentage float64) uint32 {
+	buckets := map[pcommon.TraceID]uint32{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				s := spans.At(k)
+				if _, stratified := decisions[s.TraceID()]; stratified {
+					continue
+				}
+				if parseSpanSamplingPriority(s, tsp.priorityAttributes) == deferDecision {
+					buckets[s.TraceID()] = tsp.hashBucket(s.TraceID())
+				}
+			}
+		}
+	}
+
+	sampledCount := int(math.Round(float64(len(buckets)) * samplingPercentage / 100))
+	if sampledCount <= 0 {
+		return 0
+	}
+	if sampledCount >= len(buckets) {
+		return numHashBuckets
+	}
+	sorted := make([]uint32, 0, len(buckets))
+	for _, bucket := range buckets {
+		sorted = append(sorted, bucket)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[sampledCount-1] + 1
+}
+
+// debugDecision records the hash bucket and the threshold of the sampling decision
+// on the sampled spans, the dropped spans are logged instead.
+func (tsp *tracesamplerprocessor) debugDecision(s ptrace.Span, bucket, threshold uint32, sampled bool) {
+	if !sampled {
+		tsp.logger.Debug("Span dropped by the probabilistic sampler",
+			zap.String("trace_id", s.TraceID().HexString()),
+			zap.String("span_id", s.SpanID().HexString()),
+			zap.Uint32("hash_bucket", bucket),
+			zap.Uint32("threshold", threshold))
+		return
+	}
+	s.Attributes().PutInt(hashBucketAttribute, int64(bucket))
+	s.Attributes().PutInt(thresholdAttribute, int64(threshold))
+}
+
+// removePriorityAttributes removes the attributes overriding the sampling decision from a sampled span,
+// so that they don't override the decisions of the samplers downstream.
+func (tsp *tracesamplerprocessor) removePriorityAttributes(s ptrace.Span) {
+	for _, attribute := range tsp.priorityAttributes {
+		s.Attributes().Remove(attribute)
+	}
+}
+
+// parseSpanSamplingPriority checks if the span has one of the given attributes, by
+// default the "sampling.priority" tag, to decide if the span should be sampled or
+// not. The first attribute the span has takes precedence. The usage of the tag follows
+// the OpenTracing semantic tags:
 // https://github.com/opentracing/specification/blob/main/semantic_conventions.md#span-tags-table
-func parseSpanSamplingPriority(span ptrace.Span) samplingPriority {
+func parseSpanSamplingPriority(span ptrace.Span, attributes []string) samplingPriority {
 	attribMap := span.Attributes()
 	if attribMap.Len() <= 0 {
 		return deferDecision
 	}
 
-	samplingPriorityAttrib, ok := attribMap.Get("sampling.priority")
-	if !ok {
+	var samplingPriorityAttrib pcommon.Value
+	found := false
+	for _, attribute := range attributes {
+		if samplingPriorityAttrib, found = attribMap.Get(attribute); found {
+			break
+		}
+	}
+	if !found {
 		return deferDecision
 	}
 
@@ -16,6 +16,7 @@ package probabilisticsamplerprocessor
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"math/rand"
 	"testing"
@@ -30,6 +31,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/idutils"
 )
@@ -341,6 +344,216 @@ func Test_tracesamplerprocessor_SpanSamplingPriority(t *testing.T) {
 	}
 }
 
+func Test_tracesamplerprocessor_Deterministic(t *testing.T) {
+	// newBatch returns a batch of 100 traces of 2 spans each
+	newBatch := func() ptrace.Traces {
+		td := ptrace.NewTraces()
+		spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+		for i := 0; i < 100; i++ {
+			for j := 0; j < 2; j++ {
+				span := spans.AppendEmpty()
+				span.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, byte(i)}))
+				span.SetSpanID(pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, byte(j)}))
+			}
+		}
+		return td
+	}
+	sampledTraces := func(td ptrace.Traces) map[pcommon.TraceID]int {
+		traces := map[pcommon.TraceID]int{}
+		spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for i := 0; i < spans.Len(); i++ {
+			traces[spans.At(i).TraceID()]++
+		}
+		return traces
+	}
+
+	var previous map[pcommon.TraceID]int
+	for _, seed := range []uint32{22, 22, 23} {
+		cfg := &Config{
+			ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+			SamplingPercentage: 10,
+			HashSeed:           seed,
+			Deterministic:      true,
+		}
+		sink := new(consumertest.TracesSink)
+		tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+		require.NoError(t, err)
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), newBatch()))
+
+		// exactly 10% of the traces are sampled, with all their spans
+		sampled := sampledTraces(sink.AllTraces()[0])
+		require.Len(t, sampled, 10)
+		for _, count := range sampled {
+			assert.Equal(t, 2, count)
+		}
+
+		// the sampled traces only depend on the batch and the seed
+		if previous != nil {
+			if seed == 22 {
+				assert.Equal(t, previous, sampled)
+			} else {
+				assert.NotEqual(t, previous, sampled)
+			}
+		}
+		previous = sampled
+	}
+}
+
+func Test_tracesamplerprocessor_Debug(t *testing.T) {
+	for _, deterministic := range []bool{false, true} {
+		t.Run(fmt.Sprintf("deterministic=%v", deterministic), func(t *testing.T) {
+			cfg := &Config{
+				ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+				SamplingPercentage: 50,
+				HashSeed:           22,
+				Debug:              true,
+				Deterministic:      deterministic,
+			}
+			core, observed := observer.New(zap.DebugLevel)
+			set := componenttest.NewNopProcessorCreateSettings()
+			set.Logger = zap.New(core)
+			sink := new(consumertest.TracesSink)
+			tsp, err := newTracesProcessor(context.Background(), set, cfg, sink)
+			require.NoError(t, err)
+			sampler := &tracesamplerprocessor{hashSeed: 22, hashAlgorithm: murmur3HashAlgorithm}
+
+			td := ptrace.NewTraces()
+			spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+			for i := 0; i < 20; i++ {
+				span := spans.AppendEmpty()
+				span.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, byte(i)}))
+				span.SetSpanID(pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+			}
+			require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+			// the sampled spans hold their hash bucket, lower than the threshold
+			sampled := sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+			var threshold int64
+			for i := 0; i < sampled.Len(); i++ {
+				bucket, ok := sampled.At(i).Attributes().Get(hashBucketAttribute)
+				require.True(t, ok)
+				assert.Equal(t, int64(sampler.hashBucket(sampled.At(i).TraceID())), bucket.Int())
+				spanThreshold, ok := sampled.At(i).Attributes().Get(thresholdAttribute)
+				require.True(t, ok)
+				assert.Less(t, bucket.Int(), spanThreshold.Int())
+				threshold = spanThreshold.Int()
+			}
+			if !deterministic {
+				assert.Equal(t, int64(0x2000), threshold)
+			}
+
+			// the dropped spans are logged with their hash bucket, not lower than the threshold
+			dropped := observed.FilterMessage("Span dropped by the probabilistic sampler").All()
+			require.Len(t, dropped, 20-sampled.Len())
+			for _, entry := range dropped {
+				assert.Equal(t, threshold, int64(entry.ContextMap()["threshold"].(uint32)))
+				assert.GreaterOrEqual(t, int64(entry.ContextMap()["hash_bucket"].(uint32)), threshold)
+			}
+			if deterministic {
+				assert.Equal(t, 10, sampled.Len())
+			}
+		})
+	}
+}
+
+func Test_tracesamplerprocessor_HashFromAttribute(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+		SamplingPercentage: 50,
+		HashFromAttribute:  "tenant.id",
+	}
+	sink := new(consumertest.TracesSink)
+	tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	for tenant := 0; tenant < 100; tenant++ {
+		rs := td.ResourceSpans().AppendEmpty()
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		// the attribute is taken from the resource when the span doesn't have it
+		if tenant%2 == 0 {
+			rs.Resource().Attributes().PutStr("tenant.id", fmt.Sprintf("tenant-%d", tenant))
+		}
+		for trace := 0; trace < 5; trace++ {
+			span := spans.AppendEmpty()
+			span.SetTraceID(idutils.UInt64ToTraceID(uint64(tenant), uint64(trace)))
+			if tenant%2 == 1 {
+				span.Attributes().PutStr("tenant.id", fmt.Sprintf("tenant-%d", tenant))
+			}
+		}
+	}
+	// the spans without the attribute are sampled by trace ID
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for trace := 0; trace < 100; trace++ {
+		spans.AppendEmpty().SetTraceID(idutils.UInt64ToTraceID(1000, uint64(trace)))
+	}
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, sink.AllTraces(), 1)
+	sampledTenants := map[string]int{}
+	sampledTraces := 0
+	rss := sink.AllTraces()[0].ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		spans := rss.At(i).ScopeSpans().At(0).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			tenant, ok := spans.At(j).Attributes().Get("tenant.id")
+			if !ok {
+				tenant, ok = rss.At(i).Resource().Attributes().Get("tenant.id")
+			}
+			if !ok {
+				sampledTraces++
+				continue
+			}
+			sampledTenants[tenant.Str()]++
+		}
+	}
+	// all the traces of a tenant get the same decision
+	for tenant, count := range sampledTenants {
+		assert.Equal(t, 5, count, tenant)
+	}
+	assert.InDelta(t, 50, len(sampledTenants), 20)
+	assert.InDelta(t, 50, sampledTraces, 20)
+}
+
+func Test_tracesamplerprocessor_PriorityAttributes(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:        config.NewProcessorSettings(component.NewID(typeStr)),
+		SamplingPercentage:       0,
+		PriorityAttributes:       []string{"debug.keep", "sampling.priority"},
+		RemovePriorityAttributes: true,
+	}
+	sink := new(consumertest.TracesSink)
+	tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	// the first attribute takes precedence over the second one
+	kept := spans.AppendEmpty()
+	kept.SetName("kept")
+	kept.Attributes().PutInt("debug.keep", 1)
+	kept.Attributes().PutInt("sampling.priority", 0)
+	dropped := spans.AppendEmpty()
+	dropped.SetName("dropped")
+	dropped.Attributes().PutInt("debug.keep", 0)
+	dropped.Attributes().PutInt("sampling.priority", 1)
+	fallback := spans.AppendEmpty()
+	fallback.SetName("fallback")
+	fallback.Attributes().PutInt("sampling.priority", 1)
+	fallback.Attributes().PutStr("http.method", "GET")
+	spans.AppendEmpty().SetName("hashed")
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, sink.AllTraces(), 1)
+	sampled := sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	require.Equal(t, 2, sampled.Len())
+	assert.Equal(t, "kept", sampled.At(0).Name())
+	assert.Equal(t, "fallback", sampled.At(1).Name())
+	// the priority attributes are removed once the decision is made
+	assert.Equal(t, map[string]interface{}{}, sampled.At(0).Attributes().AsRaw())
+	assert.Equal(t, map[string]interface{}{"http.method": "GET"}, sampled.At(1).Attributes().AsRaw())
+}
+
 // Test_parseSpanSamplingPriority ensures that the function parsing the attributes is taking "sampling.priority"
 // attribute correctly.
 func Test_parseSpanSamplingPriority(t *testing.T) {
@@ -417,7 +630,7 @@ func Test_parseSpanSamplingPriority(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.want, parseSpanSamplingPriority(tt.span))
+			assert.Equal(t, tt.want, parseSpanSamplingPriority(tt.span, []string{defaultPriorityAttribute}))
 		})
 	}
 }
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// rateLimitingMode caps the number of traces sampled per second with a leaky bucket, whatever
+// the sampling percentage.
+const rateLimitingMode = "rate_limiting"
+
+// rateLimiter samples the traces while its bucket has tokens. The bucket is refilled with
+// tracesPerSecond tokens per second, up to burst tokens, and each new trace takes a token.
+type rateLimiter struct {
+	tracesPerSecond float64
+	burst           float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	// current and previous hold the decisions of the traces seen over the current and the
+	// previous second, so that the spans of a trace received close together get the same
+	// decision without keeping the decisions of all the traces.
+	current, previous map[pcommon.TraceID]bool
+	rotated           time.Time
+	// now returns the current time, it is replaced by the tests.
+	now func() time.Time
+}
+
+// newRateLimiter returns the limiter of the rate_limiting mode, nil in the other modes.
+func newRateLimiter(cfg *Config) *rateLimiter {
+	if cfg.Mode != rateLimitingMode {
+		return nil
+	}
+	burst := float64(cfg.RateLimiting.Burst)
+	if burst == 0 {
+		burst = math.Max(1, math.Ceil(cfg.RateLimiting.TracesPerSecond))
+	}
+	return &rateLimiter{
+		tracesPerSecond: cfg.RateLimiting.TracesPerSecond,
+		burst:           burst,
+		tokens:          burst,
+		current:         map[pcommon.TraceID]bool{},
+		previous:        map[pcommon.TraceID]bool{},
+		now:             time.Now,
+	}
+}
+
+// sample returns whether the trace is sampled. The first span of a trace takes a token if there is
+// one, the next spans of the trace get the same decision.
+func (l *rateLimiter) sample(traceID pcommon.TraceID) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if l.last.IsZero() {
+		l.last, l.rotated = now, now
+	}
+	if elapsed := now.Sub(l.rotated); elapsed >= time.Second {
+		l.previous, l.current = l.current, map[pcommon.TraceID]bool{}
+		if elapsed >= 2*time.Second {
+			l.previous = map[pcommon.TraceID]bool{}
+		}
+		l.rotated = now
+	}
+	if sampled, ok := l.current[traceID]; ok {
+		return sampled
+	}
+	sampled, ok := l.previous[traceID]
+	if !ok {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.tracesPerSecond)
+		l.last = now
+		sampled = l.tokens >= 1
+		if sampled {
+			l.tokens--
+		}
+	}
+	l.current[traceID] = sampled
+	return sampled
+}
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+func TestValidateRateLimiting(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name: "valid",
+			modify: func(cfg *Config) {
+				cfg.RateLimiting = RateLimitingConfig{TracesPerSecond: 100, Burst: 200}
+			},
+		},
+		{
+			name: "missing traces per second",
+			err:  "invalid rate_limiting: traces_per_second must be positive",
+		},
+		{
+			name: "negative burst",
+			modify: func(cfg *Config) {
+				cfg.RateLimiting = RateLimitingConfig{TracesPerSecond: 100, Burst: -1}
+			},
+			err: "invalid rate_limiting: burst must not be negative",
+		},
+		{
+			name: "services",
+			modify: func(cfg *Config) {
+				cfg.RateLimiting.TracesPerSecond = 100
+				cfg.Services = []ServiceConfig{{Name: "checkout"}}
+			},
+			err: `services is not supported in the "rate_limiting" mode`,
+		},
+		{
+			name: "adjusted count",
+			modify: func(cfg *Config) {
+				cfg.RateLimiting.TracesPerSecond = 100
+				cfg.AdjustedCount.TraceState = true
+			},
+			err: `adjusted_count is not supported in the "rate_limiting" mode`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Mode = rateLimitingMode
+			if tt.modify != nil {
+				tt.modify(cfg)
+			}
+			if tt.err == "" {
+				assert.NoError(t, cfg.Validate())
+				return
+			}
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newRateLimiter(&Config{Mode: rateLimitingMode, RateLimiting: RateLimitingConfig{TracesPerSecond: 2, Burst: 3}})
+	l.now = func() time.Time { return now }
+
+	// the burst is sampled at once
+	assert.True(t, l.sample(pcommon.TraceID{1}))
+	assert.True(t, l.sample(pcommon.TraceID{2}))
+	assert.True(t, l.sample(pcommon.TraceID{3}))
+	assert.False(t, l.sample(pcommon.TraceID{4}))
+	// the next spans of a trace get the same decision without taking a token
+	assert.True(t, l.sample(pcommon.TraceID{1}))
+	assert.False(t, l.sample(pcommon.TraceID{4}))
+
+	// a token is added every half second
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, l.sample(pcommon.TraceID{5}))
+	assert.False(t, l.sample(pcommon.TraceID{6}))
+
+	// the decisions of the previous second are kept
+	now = now.Add(600 * time.Millisecond)
+	assert.True(t, l.sample(pcommon.TraceID{1}))
+	assert.False(t, l.sample(pcommon.TraceID{4}))
+	assert.True(t, l.sample(pcommon.TraceID{7}))
+	assert.False(t, l.sample(pcommon.TraceID{8}))
+
+	// the bucket doesn't fill beyond the burst, and the old decisions are forgotten
+	now = now.Add(time.Minute)
+	for i := byte(1); i <= 3; i++ {
+		assert.True(t, l.sample(pcommon.TraceID{10, i}))
+	}
+	assert.False(t, l.sample(pcommon.TraceID{4, 4}))
+	assert.Len(t, l.previous, 0)
+}
+
+func Test_tracesamplerprocessor_RateLimiting(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter := newRateLimiter(&Config{Mode: rateLimitingMode, RateLimiting: RateLimitingConfig{TracesPerSecond: 2}})
+	limiter.now = func() time.Time { return now }
+	tsp := &tracesamplerprocessor{
+		hashAlgorithm:      murmur3HashAlgorithm,
+		priorityAttributes: []string{defaultPriorityAttribute},
+		limiter:            limiter,
+		logger:             zap.NewNop(),
+	}
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for _, traceID := range []byte{1, 1, 2, 3, 3, 4} {
+		spans.AppendEmpty().SetTraceID(pcommon.TraceID{traceID})
+	}
+	// the spans sampled because of their priority don't take tokens
+	priority := spans.AppendEmpty()
+	priority.SetTraceID(pcommon.TraceID{5})
+	priority.Attributes().PutInt(defaultPriorityAttribute, 1)
+	spans.At(0).Attributes().PutInt(defaultPriorityAttribute, 1)
+
+	td, err := tsp.processTraces(context.Background(), td)
+	require.NoError(t, err)
+	var sampled []pcommon.TraceID
+	spans = td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		sampled = append(sampled, spans.At(i).TraceID())
+	}
+	assert.Equal(t, []pcommon.TraceID{{1}, {1}, {2}, {5}}, sampled)
+}
@@ -0,0 +1,235 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// dynamicConfigGateID is the feature gate enabling the reload of the sampling rules and the admin endpoint.
+	dynamicConfigGateID = "processor.probabilisticsampler.DynamicConfig"
+
+	defaultReloadInterval = time.Minute
+)
+
+func init() {
+	featuregate.GetRegistry().MustRegisterID(
+		dynamicConfigGateID,
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("Enables reloading the sampling percentage and the services of the probabilistic sampler from a file or an HTTP endpoint, and changing them through its admin endpoint"),
+	)
+}
+
+// ReloadConfig defines where the sampling rules are periodically reloaded from.
+type ReloadConfig struct {
+	// Source is the path of the file, or the http:// or https:// URL, the sampling rules are loaded from.
+	Source string `mapstructure:"source"`
+
+	// Interval is how often the sampling rules are reloaded. It defaults to 1m.
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+func (r *ReloadConfig) validate() error {
+	if r.Interval < 0 {
+		return fmt.Errorf("interval must not be negative")
+	}
+	if r.Interval > 0 && r.Source == "" {
+		return fmt.Errorf("missing source")
+	}
+	return nil
+}
+
+// samplingRules are the sampling rules loaded from the reload source. The rules the source doesn't
+// set keep their configured value.
+type samplingRules struct {
+	SamplingPercentage *float32        `mapstructure:"sampling_percentage"`
+	Services           []ServiceConfig `mapstructure:"services"`
+}
+
+// samplingRates are the thresholds the spans are sampled with, replaced as a whole when the sampling
+// rules are reloaded.
+type samplingRates struct {
+	samplingPercentage float64
+	scaledSamplingRate uint32
+	serviceThresholds  map[string]uint32
+	// services are the rules the service thresholds were computed from.
+	services []ServiceConfig
+}
+
+func newSamplingRates(samplingPercentage float32, services []ServiceConfig) *samplingRates {
+	if len(services) == 0 {
+		services = nil
+	}
+	return &samplingRates{
+		// Adjust sampling percentage on private so recalculations are avoided.
+		samplingPercentage: float64(samplingPercentage),
+		scaledSamplingRate: percentageThreshold(samplingPercentage),
+		serviceThresholds:  newServiceThresholds(services),
+		services:           services,
+	}
+}
+
+// reloader periodically reloads the sampling rules from their source.
+type reloader struct {
+	source             string
+	interval           time.Duration
+	samplingPercentage float32
+	services           []ServiceConfig
+	client             *http.Client
+	logger             *zap.Logger
+	// apply replaces the rates the spans are sampled with.
+	apply func(*samplingRates)
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newReloader returns the reloader of the sampling rules, nil when no source is configured.
+func newReloader(cfg *Config, logger *zap.Logger, apply func(*samplingRates)) (*reloader, error) {
+	if cfg.Reload.Source == "" {
+		return nil, nil
+	}
+	if !featuregate.GetRegistry().IsEnabled(dynamicConfigGateID) {
+		return nil, fmt.Errorf("reloading the sampling rules requires the %q feature gate", dynamicConfigGateID)
+	}
+	interval := cfg.Reload.Interval
+	if interval == 0 {
+		interval = defaultReloadInterval
+	}
+	return &reloader{
+		source:             cfg.Reload.Source,
+		interval:           interval,
+		samplingPercentage: cfg.SamplingPercentage,
+		services:           cfg.Services,
+		client:             &http.Client{Timeout: interval},
+		logger:             logger,
+		apply:              apply,
+	}, nil
+}
+
+// start loads the sampling rules and reloads them on each interval. The configured rules are kept
+// until the source can be loaded, so that the collector starts even if the source is unavailable.
+func (r *reloader) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.reload(ctx)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.reload(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (r *reloader) shutdown() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+}
+
+// reload applies the sampling rules of the source, the previous ones are kept if they can't be loaded.
+func (r *reloader) reload(ctx context.Context) {
+	rates, err := r.load(ctx)
+	if err != nil {
+		r.logger.Warn("Failed to reload the sampling rules, keeping the previous ones",
+			zap.String("source", r.source), zap.Error(err))
+		return
+	}
+	r.apply(rates)
+	r.logger.Debug("Reloaded the sampling rules", zap.String("source", r.source))
+}
+
+func (r *reloader) load(ctx context.Context) (*samplingRates, error) {
+	content, err := r.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseSamplingRules(content, r.samplingPercentage, r.services)
+}
+
+// parseSamplingRules returns the rates of the YAML, or JSON, sampling rules, the rules they don't set
+// keeping the given values.
+func parseSamplingRules(content []byte, samplingPercentage float32, services []ServiceConfig) (*samplingRates, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse the sampling rules: %w", err)
+	}
+	for key := range raw {
+		if key != "sampling_percentage" && key != "services" {
+			return nil, fmt.Errorf("unsupported key %q, only sampling_percentage and services can be reloaded", key)
+		}
+	}
+	conf := confmap.NewFromStringMap(raw)
+	var rules samplingRules
+	if err := conf.Unmarshal(&rules); err != nil {
+		return nil, fmt.Errorf("failed to parse the sampling rules: %w", err)
+	}
+
+	if rules.SamplingPercentage != nil {
+		if *rules.SamplingPercentage < 0 {
+			return nil, fmt.Errorf("sampling_percentage must not be negative")
+		}
+		samplingPercentage = *rules.SamplingPercentage
+	}
+	if conf.IsSet("services") {
+		if err := validateServices(rules.Services); err != nil {
+			return nil, err
+		}
+		services = rules.Services
+	}
+	return newSamplingRates(samplingPercentage, services), nil
+}
+
+// read returns the content of the file or of the response of the URL of the source.
+func (r *reloader) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(r.source, "http://") && !strings.HasPrefix(r.source, "https://") {
+		return os.ReadFile(r.source)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,211 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// enableDynamicConfig enables the feature gate of the reload for the duration of the test.
+func enableDynamicConfig(t *testing.T) {
+	require.NoError(t, featuregate.GetRegistry().Apply(map[string]bool{dynamicConfigGateID: true}))
+	t.Cleanup(func() {
+		require.NoError(t, featuregate.GetRegistry().Apply(map[string]bool{dynamicConfigGateID: false}))
+	})
+}
+
+func TestValidateReload(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Reload = ReloadConfig{Source: "sampling.yaml", Interval: -time.Second}
+	assert.EqualError(t, cfg.Validate(), "invalid reload: interval must not be negative")
+
+	cfg.Reload = ReloadConfig{Interval: time.Second}
+	assert.EqualError(t, cfg.Validate(), "invalid reload: missing source")
+
+	cfg.Reload = ReloadConfig{Source: "sampling.yaml"}
+	assert.NoError(t, cfg.Validate())
+	cfg.Deterministic = true
+	assert.EqualError(t, cfg.Validate(), "deterministic is not supported with reload")
+}
+
+func TestNewReloaderFeatureGate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	r, err := newReloader(cfg, zap.NewNop(), func(*samplingRates) {})
+	require.NoError(t, err)
+	assert.Nil(t, r)
+
+	cfg.Reload.Source = "sampling.yaml"
+	_, err = newReloader(cfg, zap.NewNop(), func(*samplingRates) {})
+	assert.EqualError(t, err, `reloading the sampling rules requires the "processor.probabilisticsampler.DynamicConfig" feature gate`)
+
+	enableDynamicConfig(t)
+	r, err = newReloader(cfg, zap.NewNop(), func(*samplingRates) {})
+	require.NoError(t, err)
+	assert.Equal(t, defaultReloadInterval, r.interval)
+}
+
+func TestReloaderLoad(t *testing.T) {
+	enableDynamicConfig(t)
+	source := filepath.Join(t.TempDir(), "sampling.yaml")
+	cfg := createDefaultConfig().(*Config)
+	cfg.SamplingPercentage = 10
+	cfg.Services = []ServiceConfig{{Name: "checkout", SamplingPercentage: 100}}
+	cfg.Reload.Source = source
+	r, err := newReloader(cfg, zap.NewNop(), func(*samplingRates) {})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		content  string
+		expected *samplingRates
+		err      string
+	}{
+		{
+			name:     "sampling percentage",
+			content:  "sampling_percentage: 50",
+			expected: newSamplingRates(50, cfg.Services),
+		},
+		{
+			name:     "services",
+			content:  "services:\n  - name: frontend\n    sampling_percentage: 1",
+			expected: newSamplingRates(10, []ServiceConfig{{Name: "frontend", SamplingPercentage: 1}}),
+		},
+		{
+			name:     "no services",
+			content:  "services: []",
+			expected: newSamplingRates(10, nil),
+		},
+		{
+			name:     "json",
+			content:  `{"sampling_percentage": 25}`,
+			expected: newSamplingRates(25, cfg.Services),
+		},
+		{
+			name:    "negative percentage",
+			content: "sampling_percentage: -1",
+			err:     "sampling_percentage must not be negative",
+		},
+		{
+			name:    "duplicate service",
+			content: "services: [{name: frontend}, {name: frontend}]",
+			err:     `duplicate service "frontend"`,
+		},
+		{
+			name:    "unsupported key",
+			content: "mode: consistent",
+			err:     `unsupported key "mode", only sampling_percentage and services can be reloaded`,
+		},
+		{
+			name:    "invalid",
+			content: "sampling_percentage: [",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(source, []byte(tt.content), 0600))
+			rates, err := r.load(context.Background())
+			if tt.expected == nil {
+				assert.Error(t, err)
+				if tt.err != "" {
+					assert.EqualError(t, err, tt.err)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rates)
+		})
+	}
+}
+
+func TestReloaderHTTP(t *testing.T) {
+	enableDynamicConfig(t)
+	content := "sampling_percentage: 100"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if content == "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Reload.Source = srv.URL
+	var applied []*samplingRates
+	r, err := newReloader(cfg, zap.NewNop(), func(rates *samplingRates) { applied = append(applied, rates) })
+	require.NoError(t, err)
+
+	r.reload(context.Background())
+	// the previous rules are kept while the source is unavailable
+	content = ""
+	r.reload(context.Background())
+	assert.Equal(t, []*samplingRates{newSamplingRates(100, nil)}, applied)
+
+	_, err = r.load(context.Background())
+	assert.EqualError(t, err, `unexpected status "503 Service Unavailable"`)
+}
+
+func Test_tracesamplerprocessor_Reload(t *testing.T) {
+	enableDynamicConfig(t)
+	source := filepath.Join(t.TempDir(), "sampling.yaml")
+	require.NoError(t, os.WriteFile(source, []byte("sampling_percentage: 0"), 0600))
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+		SamplingPercentage: 100,
+		Reload:             ReloadConfig{Source: source, Interval: 10 * time.Millisecond},
+	}
+	sink := new(consumertest.TracesSink)
+	tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	newTraces := func() ptrace.Traces {
+		td := ptrace.NewTraces()
+		spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+		for i := byte(1); i <= 10; i++ {
+			spans.AppendEmpty().SetTraceID(pcommon.TraceID{i})
+		}
+		return td
+	}
+
+	// the rules of the source are loaded on start
+	require.NoError(t, tsp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces()))
+	assert.Equal(t, 0, sink.SpanCount())
+
+	require.NoError(t, os.WriteFile(source, []byte("sampling_percentage: 100"), 0600))
+	assert.Eventually(t, func() bool {
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), newTraces()))
+		return sink.SpanCount() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 10, sink.SpanCount())
+	require.NoError(t, tsp.Shutdown(context.Background()))
+}
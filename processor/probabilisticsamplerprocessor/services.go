@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+)
+
+// newServiceThresholds returns the hash bucket thresholds of the services overriding the sampling percentage.
+func newServiceThresholds(cfgs []ServiceConfig) map[string]uint32 {
+	if len(cfgs) == 0 {
+		return nil
+	}
+	thresholds := make(map[string]uint32, len(cfgs))
+	for _, cfg := range cfgs {
+		thresholds[cfg.Name] = percentageThreshold(cfg.SamplingPercentage)
+	}
+	return thresholds
+}
+
+// serviceThreshold returns the threshold of the service of the resource, and whether its service overrides
+// the sampling percentage.
+func (r *samplingRates) serviceThreshold(resource pcommon.Resource) (uint32, bool) {
+	if r.serviceThresholds == nil {
+		return 0, false
+	}
+	service, ok := resource.Attributes().Get(conventions.AttributeServiceName)
+	if !ok {
+		return 0, false
+	}
+	threshold, ok := r.serviceThresholds[service.Str()]
+	return threshold, ok
+}
+
+// serviceName returns the service.name of the resource, or an empty string when it has none.
+func serviceName(resource pcommon.Resource) string {
+	service, ok := resource.Attributes().Get(conventions.AttributeServiceName)
+	if !ok {
+		return ""
+	}
+	return service.Str()
+}
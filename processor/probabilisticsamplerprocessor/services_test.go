@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestValidateServices(t *testing.T) {
+	tests := []struct {
+		name          string
+		services      []ServiceConfig
+		deterministic bool
+		err           string
+	}{
+		{
+			name:     "valid",
+			services: []ServiceConfig{{Name: "checkout", SamplingPercentage: 100}, {Name: "health"}},
+		},
+		{
+			name:     "missing name",
+			services: []ServiceConfig{{SamplingPercentage: 100}},
+			err:      "missing name of the service 0",
+		},
+		{
+			name:     "duplicate",
+			services: []ServiceConfig{{Name: "checkout"}, {Name: "checkout"}},
+			err:      `duplicate service "checkout"`,
+		},
+		{
+			name:     "negative percentage",
+			services: []ServiceConfig{{Name: "checkout", SamplingPercentage: -1}},
+			err:      `invalid service "checkout": sampling_percentage must not be negative`,
+		},
+		{
+			name:          "deterministic",
+			services:      []ServiceConfig{{Name: "checkout"}},
+			deterministic: true,
+			err:           "deterministic is not supported with services",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Services = tt.services
+			cfg.Deterministic = tt.deterministic
+			if tt.err == "" {
+				assert.NoError(t, cfg.Validate())
+				return
+			}
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
+
+func Test_tracesamplerprocessor_Services(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+		SamplingPercentage: 100,
+		Services: []ServiceConfig{
+			{Name: "chatty", SamplingPercentage: 0},
+			{Name: "critical", SamplingPercentage: 100},
+		},
+		Strata: []StratumConfig{
+			{Name: "errors", Attribute: "http.status_code", Pattern: "^5", SamplingPercentage: 100},
+		},
+	}
+	sink := new(consumertest.TracesSink)
+	tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	addSpans := func(service string, traceIDs ...byte) ptrace.SpanSlice {
+		rs := td.ResourceSpans().AppendEmpty()
+		if service != "" {
+			rs.Resource().Attributes().PutStr("service.name", service)
+		}
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		for _, traceID := range traceIDs {
+			spans.AppendEmpty().SetTraceID(pcommon.TraceID{traceID})
+		}
+		return spans
+	}
+	addSpans("chatty", 1, 2)
+	addSpans("critical", 3)
+	addSpans("other", 4)
+	addSpans("", 5)
+	// the strata take precedence over the services
+	addSpans("chatty", 6).At(0).Attributes().PutInt("http.status_code", 500)
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, sink.AllTraces(), 1)
+	sampled := map[pcommon.TraceID]string{}
+	rss := sink.AllTraces()[0].ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		service, _ := rss.At(i).Resource().Attributes().Get("service.name")
+		spans := rss.At(i).ScopeSpans().At(0).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			sampled[spans.At(j).TraceID()] = service.Str()
+		}
+	}
+	assert.Equal(t, map[pcommon.TraceID]string{{3}: "critical", {4}: "other", {5}: "", {6}: "chatty"}, sampled)
+}
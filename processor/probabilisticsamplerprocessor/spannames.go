@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"regexp"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// spanNameRule samples the spans whose name matches at its own rate.
+type spanNameRule struct {
+	names     map[string]bool
+	pattern   *regexp.Regexp
+	threshold uint32
+}
+
+func newSpanNameRules(cfgs []SpanNameConfig) []*spanNameRule {
+	rules := make([]*spanNameRule, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		r := &spanNameRule{threshold: percentageThreshold(cfg.SamplingPercentage)}
+		if len(cfg.Names) > 0 {
+			r.names = make(map[string]bool, len(cfg.Names))
+			for _, name := range cfg.Names {
+				r.names[name] = true
+			}
+		}
+		if cfg.Pattern != "" {
+			// the pattern is checked by the config validation
+			r.pattern = regexp.MustCompile(cfg.Pattern)
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+func (r *spanNameRule) matches(span ptrace.Span) bool {
+	if r.names != nil {
+		return r.names[span.Name()]
+	}
+	return r.pattern.MatchString(span.Name())
+}
+
+// spanNameThreshold returns the threshold of the first rule matching the name of the span, and whether
+// a rule overrides the sampling percentage.
+func (tsp *tracesamplerprocessor) spanNameThreshold(span ptrace.Span) (uint32, bool) {
+	for _, r := range tsp.spanNameRules {
+		if r.matches(span) {
+			return r.threshold, true
+		}
+	}
+	return 0, false
+}
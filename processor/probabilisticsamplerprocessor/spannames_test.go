@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestValidateSpanNames(t *testing.T) {
+	tests := []struct {
+		name          string
+		spanNames     []SpanNameConfig
+		deterministic bool
+		err           string
+	}{
+		{
+			name: "valid",
+			spanNames: []SpanNameConfig{
+				{Names: []string{"GET /healthz", "GET /readyz"}},
+				{Pattern: "^poll", SamplingPercentage: 1},
+			},
+		},
+		{
+			name:      "missing names",
+			spanNames: []SpanNameConfig{{SamplingPercentage: 1}},
+			err:       "invalid span name rule 0: names or pattern is required",
+		},
+		{
+			name:      "names and pattern",
+			spanNames: []SpanNameConfig{{Names: []string{"GET /healthz"}, Pattern: "^GET"}},
+			err:       "invalid span name rule 0: names and pattern are mutually exclusive",
+		},
+		{
+			name:      "invalid pattern",
+			spanNames: []SpanNameConfig{{Pattern: "^GET"}, {Pattern: "("}},
+			err:       "invalid span name rule 1: invalid pattern: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:      "negative percentage",
+			spanNames: []SpanNameConfig{{Pattern: "^GET", SamplingPercentage: -1}},
+			err:       "invalid span name rule 0: sampling_percentage must not be negative",
+		},
+		{
+			name:          "deterministic",
+			spanNames:     []SpanNameConfig{{Pattern: "^GET"}},
+			deterministic: true,
+			err:           "deterministic is not supported with span_names",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.SpanNames = tt.spanNames
+			cfg.Deterministic = tt.deterministic
+			if tt.err == "" {
+				assert.NoError(t, cfg.Validate())
+				return
+			}
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
+
+func Test_tracesamplerprocessor_SpanNames(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+		SamplingPercentage: 100,
+		SpanNames: []SpanNameConfig{
+			{Names: []string{"GET /healthz"}, SamplingPercentage: 0},
+			{Pattern: "^poll ", SamplingPercentage: 0},
+			{Pattern: "^poll", SamplingPercentage: 100},
+		},
+		Services: []ServiceConfig{
+			{Name: "chatty", SamplingPercentage: 0},
+		},
+		Strata: []StratumConfig{
+			{Name: "errors", Attribute: "http.status_code", Pattern: "^5", SamplingPercentage: 100},
+		},
+	}
+	sink := new(consumertest.TracesSink)
+	tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	addSpan := func(service, name string, traceID byte) ptrace.Span {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", service)
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetName(name)
+		span.SetTraceID(pcommon.TraceID{traceID})
+		return span
+	}
+	addSpan("checkout", "GET /healthz", 1)
+	addSpan("checkout", "GET /healthz/deep", 2)
+	// the first matching rule applies
+	addSpan("checkout", "poll queue", 3)
+	addSpan("checkout", "polling", 4)
+	// the span names take precedence over the services
+	addSpan("chatty", "polling", 5)
+	// the strata take precedence over the span names
+	addSpan("checkout", "GET /healthz", 6).Attributes().PutInt("http.status_code", 503)
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, sink.AllTraces(), 1)
+	var sampled []pcommon.TraceID
+	rss := sink.AllTraces()[0].ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		spans := rss.At(i).ScopeSpans().At(0).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			sampled = append(sampled, spans.At(j).TraceID())
+		}
+	}
+	assert.Equal(t, []pcommon.TraceID{{2}, {4}, {5}, {6}}, sampled)
+}
@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"math"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// stratum samples the traces having a span whose attribute matches at its own rate.
+type stratum struct {
+	name      string
+	attribute string
+	values    map[string]bool
+	pattern   *regexp.Regexp
+	// threshold is the threshold of the sampling percentage, when there is no quota.
+	threshold uint32
+	quota     *quota
+}
+
+func newStrata(cfgs []StratumConfig) []*stratum {
+	strata := make([]*stratum, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		s := &stratum{
+			name:      cfg.Name,
+			attribute: cfg.Attribute,
+			threshold: percentageThreshold(cfg.SamplingPercentage),
+		}
+		if len(cfg.Values) > 0 {
+			s.values = make(map[string]bool, len(cfg.Values))
+			for _, v := range cfg.Values {
+				s.values[v] = true
+			}
+		}
+		if cfg.Pattern != "" {
+			// the pattern is checked by the config validation
+			s.pattern = regexp.MustCompile(cfg.Pattern)
+		}
+		if cfg.TracesPerSecond > 0 {
+			s.quota = newQuota(cfg.TracesPerSecond)
+		}
+		strata = append(strata, s)
+	}
+	return strata
+}
+
+// percentageThreshold returns the hash bucket threshold sampling the given percentage.
+func percentageThreshold(percentage float32) uint32 {
+	return uint32(percentage * percentageScaleFactor)
+}
+
+// matches returns whether the span, or its resource, has a matching attribute.
+func (s *stratum) matches(span ptrace.Span, resource pcommon.Resource) bool {
+	v, ok := span.Attributes().Get(s.attribute)
+	if !ok {
+		if v, ok = resource.Attributes().Get(s.attribute); !ok {
+			return false
+		}
+	}
+	switch {
+	case s.values != nil:
+		return s.values[v.AsString()]
+	case s.pattern != nil:
+		return s.pattern.MatchString(v.AsString())
+	default:
+		return true
+	}
+}
+
+// stratumDecision is the stratum and the threshold a trace is sampled with.
+type stratumDecision struct {
+	stratum   string
+	threshold uint32
+}
+
+// stratify returns the decisions of the traces of the batch having a span matching a stratum. The
+// threshold of a trace is the highest of the strata matched by its spans, so that all its spans in
+// the batch get the same decision.
+func stratify(strata []*stratum, td ptrace.Traces) map[pcommon.TraceID]stratumDecision {
+	matched := map[pcommon.TraceID]map[*stratum]bool{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		resource := rss.At(i).Resource()
+		ilss := rss.At(i).ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				for _, s := range strata {
+					if !s.matches(span, resource) {
+						continue
+					}
+					if matched[span.TraceID()] == nil {
+						matched[span.TraceID()] = map[*stratum]bool{}
+					}
+					matched[span.TraceID()][s] = true
+				}
+			}
+		}
+	}
+
+	decisions := make(map[pcommon.TraceID]stratumDecision, len(matched))
+	for traceID, traceStrata := range matched {
+		var decision stratumDecision
+		// the strata are evaluated in the configuration order for the decisions to be stable
+		for _, s := range strata {
+			if !traceStrata[s] {
+				continue
+			}
+			threshold := s.threshold
+			if s.quota != nil {
+				threshold = s.quota.threshold(traceID)
+			}
+			if decision.stratum == "" || threshold > decision.threshold {
+				decision = stratumDecision{stratum: s.name, threshold: threshold}
+			}
+		}
+		decisions[traceID] = decision
+	}
+	return decisions
+}
+
+// quota adjusts a sampling threshold every second, so that the given number of traces, or spans, is
+// sampled per second given the rate of the traces, or spans, seen over the previous second.
+type quota struct {
+	perSecond float64
+	// spans counts the spans instead of the distinct traces.
+	spans bool
+
+	mu sync.Mutex
+	// current is the threshold of the current window, all the traces are sampled in the first one.
+	current     uint32
+	windowStart time.Time
+	seen        map[pcommon.TraceID]struct{}
+	seenSpans   int
+	// now returns the current time, it is replaced by the tests.
+	now func() time.Time
+}
+
+func newQuota(tracesPerSecond float64) *quota {
+	return &quota{
+		perSecond: tracesPerSecond,
+		current:   numHashBuckets,
+		seen:      map[pcommon.TraceID]struct{}{},
+		now:       time.Now,
+	}
+}
+
+func newSpanQuota(spansPerSecond float64) *quota {
+	q := newQuota(spansPerSecond)
+	q.spans = true
+	return q
+}
+
+// threshold records the span of the trace and returns the threshold it is sampled with.
+func (q *quota) threshold(traceID pcommon.TraceID) uint32 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	if q.windowStart.IsZero() {
+		q.windowStart = now
+	}
+	if elapsed := now.Sub(q.windowStart); elapsed >= time.Second {
+		seen := len(q.seen)
+		if q.spans {
+			seen = q.seenSpans
+		}
+		rate := float64(seen) / elapsed.Seconds()
+		q.current = numHashBuckets
+		if rate > q.perSecond {
+			q.current = uint32(math.Ceil(numHashBuckets * q.perSecond / rate))
+		}
+		q.windowStart = now
+		q.seen = map[pcommon.TraceID]struct{}{}
+		q.seenSpans = 0
+	}
+	if q.spans {
+		q.seenSpans++
+	} else {
+		q.seen[traceID] = struct{}{}
+	}
+	return q.current
+}
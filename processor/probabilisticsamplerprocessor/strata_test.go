@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestValidateStrata(t *testing.T) {
+	tests := []struct {
+		name   string
+		strata []StratumConfig
+		err    string
+	}{
+		{
+			name:   "missing name",
+			strata: []StratumConfig{{Attribute: "http.route"}},
+			err:    "missing name of the stratum 0",
+		},
+		{
+			name: "duplicate name",
+			strata: []StratumConfig{
+				{Name: "errors", Attribute: "http.status_code"},
+				{Name: "errors", Attribute: "error"},
+			},
+			err: `duplicate stratum "errors"`,
+		},
+		{
+			name:   "missing attribute",
+			strata: []StratumConfig{{Name: "errors"}},
+			err:    `invalid stratum "errors": missing attribute`,
+		},
+		{
+			name:   "values and pattern",
+			strata: []StratumConfig{{Name: "errors", Attribute: "http.status_code", Values: []string{"500"}, Pattern: "^5"}},
+			err:    `invalid stratum "errors": values and pattern are mutually exclusive`,
+		},
+		{
+			name:   "invalid pattern",
+			strata: []StratumConfig{{Name: "errors", Attribute: "http.status_code", Pattern: "^5("}},
+			err:    "invalid stratum \"errors\": invalid pattern: error parsing regexp: missing closing ): `^5(`",
+		},
+		{
+			name:   "negative percentage",
+			strata: []StratumConfig{{Name: "errors", Attribute: "http.status_code", SamplingPercentage: -1}},
+			err:    `invalid stratum "errors": sampling_percentage and traces_per_second must not be negative`,
+		},
+		{
+			name:   "percentage and quota",
+			strata: []StratumConfig{{Name: "errors", Attribute: "http.status_code", SamplingPercentage: 10, TracesPerSecond: 10}},
+			err:    `invalid stratum "errors": sampling_percentage and traces_per_second are mutually exclusive`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Strata = tt.strata
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
+
+func TestStratumMatches(t *testing.T) {
+	strata := newStrata([]StratumConfig{
+		{Name: "checkout", Attribute: "http.route", Values: []string{"/checkout", "/pay"}},
+		{Name: "errors", Attribute: "http.status_code", Pattern: "^5"},
+		{Name: "canary", Attribute: "deployment.canary"},
+	})
+
+	resource := pcommon.NewResource()
+	span := ptrace.NewSpan()
+	assert.False(t, strata[0].matches(span, resource))
+	assert.False(t, strata[1].matches(span, resource))
+	assert.False(t, strata[2].matches(span, resource))
+
+	span.Attributes().PutStr("http.route", "/pay")
+	span.Attributes().PutInt("http.status_code", 503)
+	assert.True(t, strata[0].matches(span, resource))
+	assert.True(t, strata[1].matches(span, resource))
+
+	span.Attributes().PutStr("http.route", "/cart")
+	span.Attributes().PutInt("http.status_code", 404)
+	assert.False(t, strata[0].matches(span, resource))
+	assert.False(t, strata[1].matches(span, resource))
+
+	// the resource attributes are looked up when the span doesn't have the attribute
+	resource.Attributes().PutBool("deployment.canary", false)
+	assert.True(t, strata[2].matches(span, resource))
+}
+
+func TestQuotaThreshold(t *testing.T) {
+	q := newQuota(10)
+	now := time.Unix(1000, 0)
+	q.now = func() time.Time { return now }
+
+	// all the traces are sampled until the rate is known
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, uint32(numHashBuckets), q.threshold(pcommon.TraceID{byte(i)}))
+	}
+
+	// 100 traces were seen over the first second, a tenth of them is sampled over the next one
+	now = now.Add(time.Second)
+	assert.Equal(t, uint32(1639), q.threshold(pcommon.TraceID{1}))
+
+	// the quota isn't reached over the next 2 seconds, all the traces are sampled again
+	now = now.Add(2 * time.Second)
+	assert.Equal(t, uint32(numHashBuckets), q.threshold(pcommon.TraceID{1}))
+}
+
+func Test_tracesamplerprocessor_Strata(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(component.NewID(typeStr)),
+		SamplingPercentage: 0,
+		Strata: []StratumConfig{
+			{Name: "errors", Attribute: "http.status_code", Pattern: "^5", SamplingPercentage: 100},
+			{Name: "health", Attribute: "http.route", Values: []string{"/health"}, SamplingPercentage: 0},
+		},
+	}
+	sink := new(consumertest.TracesSink)
+	tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	addSpan := func(traceID byte, key string, value int64) {
+		span := spans.AppendEmpty()
+		span.SetTraceID(pcommon.TraceID{traceID})
+		span.SetSpanID(pcommon.SpanID{byte(spans.Len())})
+		if key != "" {
+			span.Attributes().PutInt(key, value)
+		}
+	}
+	// the failed trace is kept entirely, with the span of its route
+	addSpan(1, "http.status_code", 200)
+	addSpan(1, "http.status_code", 500)
+	addSpan(1, "", 0)
+	// the successful trace is sampled at the default percentage
+	addSpan(2, "http.status_code", 200)
+	addSpan(2, "", 0)
+	// the failed health check is kept, as the highest rate of its strata applies
+	span := spans.AppendEmpty()
+	span.SetTraceID(pcommon.TraceID{3})
+	span.Attributes().PutStr("http.route", "/health")
+	span.Attributes().PutInt("http.status_code", 503)
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, sink.AllTraces(), 1)
+	sampled := map[pcommon.TraceID]int{}
+	got := sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	for i := 0; i < got.Len(); i++ {
+		sampled[got.At(i).TraceID()]++
+	}
+	assert.Equal(t, map[pcommon.TraceID]int{{1}: 3, {3}: 1}, sampled)
+}
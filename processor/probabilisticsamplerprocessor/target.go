@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+const (
+	// targetRateMode samples the traces whose hash bucket is lower than a threshold adjusted every second,
+	// so that the configured number of spans, or traces, is sampled per second.
+	targetRateMode = "target_rate"
+
+	// probabilityAttribute is the default attribute recording the probability of the spans sampled in the
+	// target_rate mode.
+	probabilityAttribute = "sampling.probability"
+)
+
+// newTargetQuota returns the quota adjusting the sampling threshold in the target_rate mode, nil in the other modes.
+func newTargetQuota(cfg *Config) *quota {
+	if cfg.Mode != targetRateMode {
+		return nil
+	}
+	if cfg.TargetRate.SpansPerSecond > 0 {
+		return newSpanQuota(cfg.TargetRate.SpansPerSecond)
+	}
+	return newQuota(cfg.TargetRate.TracesPerSecond)
+}
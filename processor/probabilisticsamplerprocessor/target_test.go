@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+func TestValidateTargetRate(t *testing.T) {
+	tests := []struct {
+		name          string
+		targetRate    TargetRateConfig
+		deterministic bool
+		err           string
+	}{
+		{
+			name:       "spans",
+			targetRate: TargetRateConfig{SpansPerSecond: 100},
+		},
+		{
+			name:       "traces",
+			targetRate: TargetRateConfig{TracesPerSecond: 10},
+		},
+		{
+			name: "missing rate",
+			err:  "invalid target_rate: spans_per_second or traces_per_second is required",
+		},
+		{
+			name:       "negative rate",
+			targetRate: TargetRateConfig{SpansPerSecond: -1},
+			err:        "invalid target_rate: spans_per_second and traces_per_second must not be negative",
+		},
+		{
+			name:       "both rates",
+			targetRate: TargetRateConfig{SpansPerSecond: 100, TracesPerSecond: 10},
+			err:        "invalid target_rate: spans_per_second and traces_per_second are mutually exclusive",
+		},
+		{
+			name:          "deterministic",
+			targetRate:    TargetRateConfig{SpansPerSecond: 100},
+			deterministic: true,
+			err:           `deterministic is not supported in the "target_rate" mode`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Mode = targetRateMode
+			cfg.TargetRate = tt.targetRate
+			cfg.Deterministic = tt.deterministic
+			if tt.err == "" {
+				assert.NoError(t, cfg.Validate())
+				return
+			}
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
+
+func TestSpanQuotaThreshold(t *testing.T) {
+	q := newSpanQuota(10)
+	now := time.Unix(1000, 0)
+	q.now = func() time.Time { return now }
+
+	// the spans of the same trace are all counted
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, uint32(numHashBuckets), q.threshold(pcommon.TraceID{1}))
+	}
+
+	// 50 spans were seen over the first second, a fifth of them is sampled over the next one
+	now = now.Add(time.Second)
+	assert.Equal(t, uint32(3277), q.threshold(pcommon.TraceID{1}))
+}
+
+func Test_tracesamplerprocessor_TargetRate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	target := newTargetQuota(&Config{Mode: targetRateMode, TargetRate: TargetRateConfig{SpansPerSecond: 100}})
+	target.now = func() time.Time { return now }
+	tsp := &tracesamplerprocessor{
+		hashAlgorithm:     murmur3HashAlgorithm,
+		target:            target,
+		adjustedAttribute: probabilityAttribute,
+		logger:            zap.NewNop(),
+	}
+
+	rnd := rand.New(rand.NewSource(42))
+	newBatch := func(spans int) ptrace.Traces {
+		td := ptrace.NewTraces()
+		ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+		for i := 0; i < spans; i++ {
+			var traceID pcommon.TraceID
+			rnd.Read(traceID[:])
+			ss.AppendEmpty().SetTraceID(traceID)
+		}
+		return td
+	}
+	probabilities := func(td ptrace.Traces) map[float64]int {
+		got := map[float64]int{}
+		spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for i := 0; i < spans.Len(); i++ {
+			p, ok := spans.At(i).Attributes().Get(probabilityAttribute)
+			require.True(t, ok)
+			got[p.Double()]++
+		}
+		return got
+	}
+
+	// all the spans are sampled until the rate is known
+	td, err := tsp.processTraces(context.Background(), newBatch(1000))
+	require.NoError(t, err)
+	assert.Equal(t, map[float64]int{1: 1000}, probabilities(td))
+
+	// 1000 spans were seen over the first second, a tenth of them is sampled over the next one
+	now = now.Add(time.Second)
+	td, err = tsp.processTraces(context.Background(), newBatch(1000))
+	require.NoError(t, err)
+	got := probabilities(td)
+	require.Len(t, got, 1)
+	assert.InDelta(t, 100, got[1639.0/numHashBuckets], 30)
+}
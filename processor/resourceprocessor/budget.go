@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourceprocessor"
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+// budgetProc trims the resource attributes exceeding an AttributeBudget.
+type budgetProc struct {
+	maxKeys       int
+	maxTotalBytes int
+	// priorities maps the priority keys to their rank.
+	priorities map[string]int
+}
+
+func newBudgetProc(cfg *AttributeBudget) *budgetProc {
+	if cfg == nil {
+		return nil
+	}
+	priorities := make(map[string]int, len(cfg.PriorityKeys))
+	for i, key := range cfg.PriorityKeys {
+		if _, ok := priorities[key]; !ok {
+			priorities[key] = i
+		}
+	}
+	return &budgetProc{
+		maxKeys:       cfg.MaxKeys,
+		maxTotalBytes: cfg.MaxTotalBytes,
+		priorities:    priorities,
+	}
+}
+
+// process removes the attributes exceeding the budget.
+func (p *budgetProc) process(logger *zap.Logger, attrs pcommon.Map) {
+	if p.maxTotalBytes == 0 && (p.maxKeys == 0 || attrs.Len() <= p.maxKeys) {
+		return
+	}
+
+	keys := make([]string, 0, attrs.Len())
+	sizes := make(map[string]int, attrs.Len())
+	total := 0
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		keys = append(keys, k)
+		sizes[k] = len(k) + len(v.AsString())
+		total += sizes[k]
+		return true
+	})
+	if (p.maxKeys == 0 || len(keys) <= p.maxKeys) && (p.maxTotalBytes == 0 || total <= p.maxTotalBytes) {
+		return
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		ri, iok := p.priorities[keys[i]]
+		rj, jok := p.priorities[keys[j]]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+
+	kept, bytes := 0, 0
+	for kept < len(keys) {
+		size := sizes[keys[kept]]
+		if (p.maxKeys > 0 && kept == p.maxKeys) || (p.maxTotalBytes > 0 && bytes+size > p.maxTotalBytes) {
+			break
+		}
+		kept++
+		bytes += size
+	}
+
+	trimmed := keys[kept:]
+	for _, k := range trimmed {
+		attrs.Remove(k)
+	}
+	logger.Debug("Resource attributes trimmed to the budget", zap.Strings("keys", trimmed))
+}
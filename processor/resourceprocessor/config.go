@@ -15,6 +15,10 @@
 package resourceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourceprocessor"
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 
@@ -28,11 +32,150 @@ type Config struct {
 	// AttributesActions specifies the list of actions to be applied on resource attributes.
 	// The set of actions are {INSERT, UPDATE, UPSERT, DELETE, HASH, EXTRACT}.
 	AttributesActions []attraction.ActionKeyValue `mapstructure:"attributes"`
+
+	// SchemaURL specifies the action to be applied on the schema URL of the resources.
+	SchemaURL *SchemaURLAction `mapstructure:"schema_url"`
+
+	// Budget limits the number and the size of the resource attributes, it is enforced
+	// after the attributes actions.
+	Budget *AttributeBudget `mapstructure:"budget"`
+
+	// Enrichment sets resource attributes from values read from local sources when the processor starts,
+	// such as the host name or the cloud instance metadata. It is applied before the attributes actions.
+	Enrichment *EnrichmentConfig `mapstructure:"enrichment"`
 }
 
+// EnrichmentConfig defines the resource attributes read from local sources.
+type EnrichmentConfig struct {
+	// Timeout is the timeout of reading each attribute, 2s by default.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Attributes are the resource attributes to set.
+	Attributes []EnrichmentAttribute `mapstructure:"attributes"`
+}
+
+// EnrichmentAttribute defines a resource attribute and the local source of its value.
+type EnrichmentAttribute struct {
+	// Key is the key of the resource attribute.
+	Key string `mapstructure:"key"`
+
+	// Source is where the value is read from, one of {hostname, fqdn, machine_id, aws_imds, gcp_metadata}.
+	Source string `mapstructure:"source"`
+
+	// Path is the path of the value in the metadata service, e.g. "placement/region" for aws_imds or
+	// "instance/id" for gcp_metadata. Required for these sources.
+	Path string `mapstructure:"path"`
+
+	// Override replaces the attribute of the resources already having it.
+	Override bool `mapstructure:"override"`
+}
+
+// AttributeBudget limits the resource attributes. The attributes exceeding the budget are
+// trimmed deterministically: the attributes are ordered by PriorityKeys, then by key, and
+// are kept in this order until one of them doesn't fit in the budget.
+type AttributeBudget struct {
+	// MaxKeys is the maximum number of attributes of a resource, unlimited if zero.
+	MaxKeys int `mapstructure:"max_keys"`
+
+	// MaxTotalBytes is the maximum total size of the keys and the values of the attributes
+	// of a resource, unlimited if zero. The size of a value is the size of its string form.
+	MaxTotalBytes int `mapstructure:"max_total_bytes"`
+
+	// PriorityKeys are the keys of the attributes kept first, in their order.
+	PriorityKeys []string `mapstructure:"priority_keys"`
+}
+
+// SchemaURLAction specifies how the schema URL of the resources is set.
+type SchemaURLAction struct {
+	// Value is the schema URL to set. Required unless the action is DELETE.
+	Value string `mapstructure:"value"`
+
+	// Action is one of {INSERT, UPDATE, UPSERT, DELETE}, UPSERT by default.
+	// INSERT sets the schema URL of the resources without one, UPDATE rewrites
+	// the existing schema URLs, UPSERT does both and DELETE removes them.
+	Action attraction.Action `mapstructure:"action"`
+
+	// OnConflict is one of {ignore, warn, fail}, ignore by default. It specifies
+	// what happens when UPDATE or UPSERT would replace a schema URL different from
+	// Value: ignore applies the action, warn logs a warning and applies the action,
+	// fail rejects the data without changing any of its resources.
+	OnConflict ConflictPolicy `mapstructure:"on_conflict"`
+}
+
+// ConflictPolicy specifies what happens on conflicting schema URLs.
+type ConflictPolicy string
+
+const (
+	conflictIgnore ConflictPolicy = "ignore"
+	conflictWarn   ConflictPolicy = "warn"
+	conflictFail   ConflictPolicy = "fail"
+)
+
 var _ component.ProcessorConfig = (*Config)(nil)
 
 // Validate checks if the processor configuration is valid
 func (cfg *Config) Validate() error {
+	if cfg.Budget != nil {
+		if err := cfg.Budget.validate(); err != nil {
+			return err
+		}
+	}
+	if cfg.Enrichment != nil {
+		if err := cfg.Enrichment.validate(); err != nil {
+			return err
+		}
+	}
+	if cfg.SchemaURL == nil {
+		return nil
+	}
+	switch attraction.Action(strings.ToLower(string(cfg.SchemaURL.Action))) {
+	case "", attraction.INSERT, attraction.UPDATE, attraction.UPSERT:
+		if cfg.SchemaURL.Value == "" {
+			return fmt.Errorf("missing schema URL value for action %q", cfg.SchemaURL.Action)
+		}
+	case attraction.DELETE:
+	default:
+		return fmt.Errorf("unsupported schema URL action %q", cfg.SchemaURL.Action)
+	}
+	switch ConflictPolicy(strings.ToLower(string(cfg.SchemaURL.OnConflict))) {
+	case "", conflictIgnore, conflictWarn, conflictFail:
+	default:
+		return fmt.Errorf("unsupported schema URL conflict policy %q", cfg.SchemaURL.OnConflict)
+	}
+	return nil
+}
+
+func (b *AttributeBudget) validate() error {
+	if b.MaxKeys < 0 {
+		return fmt.Errorf("budget max_keys must not be negative")
+	}
+	if b.MaxTotalBytes < 0 {
+		return fmt.Errorf("budget max_total_bytes must not be negative")
+	}
+	if b.MaxKeys == 0 && b.MaxTotalBytes == 0 {
+		return fmt.Errorf("budget requires max_keys or max_total_bytes")
+	}
+	return nil
+}
+
+func (e *EnrichmentConfig) validate() error {
+	if e.Timeout < 0 {
+		return fmt.Errorf("enrichment timeout must not be negative")
+	}
+	if len(e.Attributes) == 0 {
+		return fmt.Errorf("enrichment requires attributes")
+	}
+	for i, attribute := range e.Attributes {
+		if attribute.Key == "" {
+			return fmt.Errorf("missing key of the enrichment attribute %d", i)
+		}
+		requiresPath, ok := enrichmentSources[attribute.Source]
+		if !ok {
+			return fmt.Errorf("unsupported source %q of the enrichment attribute %q", attribute.Source, attribute.Key)
+		}
+		if requiresPath && attribute.Path == "" {
+			return fmt.Errorf("missing path of the enrichment attribute %q for the source %q", attribute.Key, attribute.Source)
+		}
+	}
 	return nil
 }
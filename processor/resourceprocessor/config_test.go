@@ -17,6 +17,7 @@ package resourceprocessor
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,8 +32,9 @@ func TestLoadConfig(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		id       component.ID
-		expected component.ProcessorConfig
+		id           component.ID
+		expected     component.ProcessorConfig
+		errorMessage string
 	}{
 		{
 			id: component.NewIDWithName(typeStr, ""),
@@ -45,6 +47,57 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			id: component.NewIDWithName(typeStr, "schema_url"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				SchemaURL: &SchemaURLAction{
+					Value:      "https://opentelemetry.io/schemas/1.9.0",
+					Action:     attraction.UPSERT,
+					OnConflict: conflictFail,
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "invalid_schema_url"),
+			errorMessage: `missing schema URL value for action "upsert"`,
+		},
+		{
+			id: component.NewIDWithName(typeStr, "budget"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				AttributesActions: []attraction.ActionKeyValue{
+					{RegexPattern: "^k8s\\.pod\\.label\\.", Action: attraction.DELETE},
+				},
+				Budget: &AttributeBudget{
+					MaxKeys:       32,
+					MaxTotalBytes: 4096,
+					PriorityKeys:  []string{"service.name", "service.namespace"},
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "invalid_budget"),
+			errorMessage: "budget requires max_keys or max_total_bytes",
+		},
+		{
+			id: component.NewIDWithName(typeStr, "enrichment"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				Enrichment: &EnrichmentConfig{
+					Timeout: time.Second,
+					Attributes: []EnrichmentAttribute{
+						{Key: "host.name", Source: fqdnSource, Override: true},
+						{Key: "host.id", Source: machineIDSource},
+						{Key: "cloud.region", Source: awsIMDSSource, Path: "placement/region"},
+					},
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "invalid_enrichment"),
+			errorMessage: `missing path of the enrichment attribute "cloud.region" for the source "aws_imds"`,
+		},
 		{
 			id:       component.NewIDWithName(typeStr, "invalid"),
 			expected: createDefaultConfig(),
@@ -63,6 +116,10 @@ func TestLoadConfig(t *testing.T) {
 			require.NoError(t, err)
 			require.NoError(t, component.UnmarshalProcessorConfig(sub, cfg))
 
+			if tt.expected == nil {
+				assert.EqualError(t, cfg.Validate(), tt.errorMessage)
+				return
+			}
 			assert.NoError(t, cfg.Validate())
 			assert.Equal(t, tt.expected, cfg)
 		})
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourceprocessor"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+const (
+	hostnameSource    = "hostname"
+	fqdnSource        = "fqdn"
+	machineIDSource   = "machine_id"
+	awsIMDSSource     = "aws_imds"
+	gcpMetadataSource = "gcp_metadata"
+
+	defaultEnrichmentTimeout = 2 * time.Second
+)
+
+// enrichmentSources are the supported sources, with whether they require a path.
+var enrichmentSources = map[string]bool{
+	hostnameSource:    false,
+	fqdnSource:        false,
+	machineIDSource:   false,
+	awsIMDSSource:     true,
+	gcpMetadataSource: true,
+}
+
+// enricher reads the values of the enrichment attributes from their local sources at start, and
+// sets them on the resources.
+type enricher struct {
+	attributes []EnrichmentAttribute
+	timeout    time.Duration
+	client     *http.Client
+	// endpoints are the endpoints of the metadata services, replaced by the tests.
+	endpoints map[string]string
+	// machineIDPaths are the files the machine ID is read from, the first existing one is used.
+	machineIDPaths []string
+	hostname       func() (string, error)
+
+	// values are the values read at start, by attribute key.
+	values pcommon.Map
+}
+
+func newEnricher(cfg *EnrichmentConfig) *enricher {
+	if cfg == nil {
+		return nil
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultEnrichmentTimeout
+	}
+	return &enricher{
+		attributes: cfg.Attributes,
+		timeout:    timeout,
+		client:     &http.Client{Timeout: timeout},
+		endpoints: map[string]string{
+			awsIMDSSource:     "http://169.254.169.254",
+			gcpMetadataSource: "http://metadata.google.internal",
+		},
+		machineIDPaths: []string{"/etc/machine-id", "/var/lib/dbus/machine-id"},
+		hostname:       os.Hostname,
+		values:         pcommon.NewMap(),
+	}
+}
+
+// start reads the values of the attributes. An attribute whose source can't be read is logged
+// and left out, so that the collector starts where a source, such as a metadata service, isn't
+// available.
+func (e *enricher) start(ctx context.Context, logger *zap.Logger) {
+	for _, attribute := range e.attributes {
+		value, err := e.read(ctx, attribute)
+		if err != nil {
+			logger.Warn("Failed to read the value of the resource attribute, it isn't set",
+				zap.String("key", attribute.Key), zap.String("source", attribute.Source), zap.Error(err))
+			continue
+		}
+		e.values.PutStr(attribute.Key, value)
+	}
+}
+
+func (e *enricher) read(ctx context.Context, attribute EnrichmentAttribute) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	switch attribute.Source {
+	case hostnameSource:
+		return e.hostname()
+	case fqdnSource:
+		return e.fqdn(ctx)
+	case machineIDSource:
+		return e.machineID()
+	case awsIMDSSource:
+		return e.awsIMDS(ctx, attribute.Path)
+	case gcpMetadataSource:
+		return e.get(ctx, e.endpoints[gcpMetadataSource]+"/computeMetadata/v1/"+attribute.Path, http.Header{"Metadata-Flavor": {"Google"}})
+	default:
+		return "", fmt.Errorf("unsupported source %q", attribute.Source)
+	}
+}
+
+// fqdn returns the canonical name of the host, as resolved by the resolver of the system.
+func (e *enricher) fqdn(ctx context.Context) (string, error) {
+	hostname, err := e.hostname()
+	if err != nil {
+		return "", err
+	}
+	cname, err := net.DefaultResolver.LookupCNAME(ctx, hostname)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(cname, "."), nil
+}
+
+func (e *enricher) machineID() (string, error) {
+	var err error
+	for _, path := range e.machineIDPaths {
+		var content []byte
+		if content, err = os.ReadFile(path); err == nil {
+			if id := strings.TrimSpace(string(content)); id != "" {
+				return id, nil
+			}
+			err = fmt.Errorf("empty machine ID in %s", path)
+		}
+	}
+	return "", err
+}
+
+// awsIMDS returns the metadata at the path from the EC2 instance metadata service, with a session
+// token of IMDSv2, or without token when the service only supports IMDSv1.
+func (e *enricher) awsIMDS(ctx context.Context, path string) (string, error) {
+	endpoint := e.endpoints[awsIMDSSource]
+	header := http.Header{}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	if token, err := e.do(req); err == nil {
+		header.Set("X-aws-ec2-metadata-token", token)
+	}
+	return e.get(ctx, endpoint+"/latest/meta-data/"+path, header)
+}
+
+func (e *enricher) get(ctx context.Context, url string, header http.Header) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header = header
+	return e.do(req)
+}
+
+func (e *enricher) do(req *http.Request) (string, error) {
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %q from %s", resp.Status, req.URL.Path)
+	}
+	return string(bytes.TrimSpace(body)), nil
+}
+
+// process sets the attributes read at start on the resource, the existing attributes are only
+// replaced for the attributes with override.
+func (e *enricher) process(attrs pcommon.Map) {
+	for _, attribute := range e.attributes {
+		value, ok := e.values.Get(attribute.Key)
+		if !ok {
+			continue
+		}
+		if _, exists := attrs.Get(attribute.Key); exists && !attribute.Override {
+			continue
+		}
+		attrs.PutStr(attribute.Key, value.Str())
+	}
+}
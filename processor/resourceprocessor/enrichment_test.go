@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourceprocessor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestValidateEnrichment(t *testing.T) {
+	tests := []struct {
+		name       string
+		enrichment EnrichmentConfig
+		err        string
+	}{
+		{
+			name:       "negative timeout",
+			enrichment: EnrichmentConfig{Timeout: -time.Second, Attributes: []EnrichmentAttribute{{Key: "host.name", Source: hostnameSource}}},
+			err:        "enrichment timeout must not be negative",
+		},
+		{
+			name: "missing attributes",
+			err:  "enrichment requires attributes",
+		},
+		{
+			name:       "missing key",
+			enrichment: EnrichmentConfig{Attributes: []EnrichmentAttribute{{Source: hostnameSource}}},
+			err:        "missing key of the enrichment attribute 0",
+		},
+		{
+			name:       "unsupported source",
+			enrichment: EnrichmentConfig{Attributes: []EnrichmentAttribute{{Key: "host.name", Source: "dns"}}},
+			err:        `unsupported source "dns" of the enrichment attribute "host.name"`,
+		},
+		{
+			name:       "missing path",
+			enrichment: EnrichmentConfig{Attributes: []EnrichmentAttribute{{Key: "cloud.region", Source: gcpMetadataSource}}},
+			err:        `missing path of the enrichment attribute "cloud.region" for the source "gcp_metadata"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Enrichment: &tt.enrichment}
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
+
+func TestEnricherSources(t *testing.T) {
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodPut && req.URL.Path == "/latest/api/token":
+			assert.Equal(t, "60", req.Header.Get("X-aws-ec2-metadata-token-ttl-seconds"))
+			_, _ = w.Write([]byte("token"))
+		case req.URL.Path == "/latest/meta-data/placement/region" && req.Header.Get("X-aws-ec2-metadata-token") == "token":
+			_, _ = w.Write([]byte("eu-west-1"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer imds.Close()
+	gcp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/computeMetadata/v1/instance/id" || req.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("1234\n"))
+	}))
+	defer gcp.Close()
+	machineID := filepath.Join(t.TempDir(), "machine-id")
+	require.NoError(t, os.WriteFile(machineID, []byte("0123456789abcdef\n"), 0600))
+
+	e := newEnricher(&EnrichmentConfig{Attributes: []EnrichmentAttribute{
+		{Key: "host.name", Source: hostnameSource},
+		{Key: "host.id", Source: machineIDSource},
+		{Key: "cloud.region", Source: awsIMDSSource, Path: "placement/region"},
+		{Key: "cloud.availability_zone", Source: awsIMDSSource, Path: "placement/availability-zone"},
+		{Key: "gcp.instance.id", Source: gcpMetadataSource, Path: "instance/id"},
+	}})
+	e.endpoints = map[string]string{awsIMDSSource: imds.URL, gcpMetadataSource: gcp.URL}
+	e.machineIDPaths = []string{filepath.Join(t.TempDir(), "missing"), machineID}
+	e.hostname = func() (string, error) { return "node-1", nil }
+
+	core, logs := observer.New(zapcore.WarnLevel)
+	e.start(context.Background(), zap.New(core))
+
+	assert.Equal(t, map[string]interface{}{
+		"host.name":       "node-1",
+		"host.id":         "0123456789abcdef",
+		"cloud.region":    "eu-west-1",
+		"gcp.instance.id": "1234",
+	}, e.values.AsRaw())
+	// the attribute whose source fails is left out
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "cloud.availability_zone", logs.All()[0].ContextMap()["key"])
+}
+
+func TestEnricherHostnameFailure(t *testing.T) {
+	e := newEnricher(&EnrichmentConfig{Attributes: []EnrichmentAttribute{
+		{Key: "host.name", Source: fqdnSource},
+	}})
+	e.hostname = func() (string, error) { return "", errors.New("no hostname") }
+	e.start(context.Background(), zap.NewNop())
+	assert.Equal(t, 0, e.values.Len())
+}
+
+func TestResourceProcessorEnrichment(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+		Enrichment: &EnrichmentConfig{Attributes: []EnrichmentAttribute{
+			{Key: "host.name", Source: hostnameSource},
+			{Key: "host.id", Source: hostnameSource, Override: true},
+		}},
+	}
+	sink := new(consumertest.MetricsSink)
+	factory := NewFactory()
+	mp, err := factory.CreateMetricsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, mp.Start(context.Background(), componenttest.NewNopHost()))
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty()
+	attrs := md.ResourceMetrics().AppendEmpty().Resource().Attributes()
+	attrs.PutStr("host.name", "from-the-agent")
+	attrs.PutStr("host.id", "from-the-agent")
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+
+	rms := sink.AllMetrics()[0].ResourceMetrics()
+	assert.Equal(t, map[string]interface{}{"host.name": hostname, "host.id": hostname}, rms.At(0).Resource().Attributes().AsRaw())
+	// the existing attributes are only replaced with override
+	assert.Equal(t, map[string]interface{}{"host.name": "from-the-agent", "host.id": hostname}, rms.At(1).Resource().Attributes().AsRaw())
+}
+
+func TestEnricherProcessWithoutValues(t *testing.T) {
+	e := newEnricher(&EnrichmentConfig{Attributes: []EnrichmentAttribute{{Key: "host.name", Source: hostnameSource}}})
+	attrs := pcommon.NewMap()
+	// nothing is set until the values are read
+	e.process(attrs)
+	assert.Equal(t, 0, attrs.Len())
+}
@@ -61,14 +61,15 @@ func createTracesProcessor(
 	if err != nil {
 		return nil, err
 	}
-	proc := &resourceProcessor{logger: set.Logger, attrProc: attrProc}
+	proc := newResourceProcessor(set.Logger, cfg.(*Config), attrProc)
 	return processorhelper.NewTracesProcessor(
 		ctx,
 		set,
 		cfg,
 		nextConsumer,
 		proc.processTraces,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(proc.start))
 }
 
 func createMetricsProcessor(
@@ -80,14 +81,15 @@ func createMetricsProcessor(
 	if err != nil {
 		return nil, err
 	}
-	proc := &resourceProcessor{logger: set.Logger, attrProc: attrProc}
+	proc := newResourceProcessor(set.Logger, cfg.(*Config), attrProc)
 	return processorhelper.NewMetricsProcessor(
 		ctx,
 		set,
 		cfg,
 		nextConsumer,
 		proc.processMetrics,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(proc.start))
 }
 
 func createLogsProcessor(
@@ -99,19 +101,24 @@ func createLogsProcessor(
 	if err != nil {
 		return nil, err
 	}
-	proc := &resourceProcessor{logger: set.Logger, attrProc: attrProc}
+	proc := newResourceProcessor(set.Logger, cfg.(*Config), attrProc)
 	return processorhelper.NewLogsProcessor(
 		ctx,
 		set,
 		cfg,
 		nextConsumer,
 		proc.processLogs,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(proc.start))
 }
 
 func createAttrProcessor(cfg *Config) (*attraction.AttrProc, error) {
 	if len(cfg.AttributesActions) == 0 {
-		return nil, fmt.Errorf("error creating \"%v\" processor due to missing required field \"attributes\"", cfg.ID())
+		if cfg.SchemaURL != nil || cfg.Budget != nil || cfg.Enrichment != nil {
+			// Only the schema URL, the budget or the enrichment of the resources is processed.
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error creating \"%v\" processor due to missing required field \"attributes\", \"schema_url\", \"budget\" or \"enrichment\"", cfg.ID())
 	}
 	attrProc, err := attraction.NewAttrProc(&attraction.Settings{Actions: cfg.AttributesActions})
 	if err != nil {
@@ -17,6 +17,8 @@ package resourceprocessor // import "github.com/open-telemetry/opentelemetry-col
 import (
 	"context"
 
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -26,30 +28,95 @@ import (
 )
 
 type resourceProcessor struct {
-	logger   *zap.Logger
-	attrProc *attraction.AttrProc
+	logger        *zap.Logger
+	attrProc      *attraction.AttrProc
+	schemaURLProc *schemaURLProc
+	budgetProc    *budgetProc
+	enricher      *enricher
+}
+
+func newResourceProcessor(logger *zap.Logger, cfg *Config, attrProc *attraction.AttrProc) *resourceProcessor {
+	return &resourceProcessor{
+		logger:        logger,
+		attrProc:      attrProc,
+		schemaURLProc: newSchemaURLProc(cfg.SchemaURL),
+		budgetProc:    newBudgetProc(cfg.Budget),
+		enricher:      newEnricher(cfg.Enrichment),
+	}
+}
+
+// start reads the values of the enrichment attributes.
+func (rp *resourceProcessor) start(ctx context.Context, _ component.Host) error {
+	if rp.enricher != nil {
+		rp.enricher.start(ctx, rp.logger)
+	}
+	return nil
 }
 
 func (rp *resourceProcessor) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
 	rss := td.ResourceSpans()
+	if err := rp.checkSchemaURLs(rss.Len(), func(i int) string { return rss.At(i).SchemaUrl() }); err != nil {
+		return td, err
+	}
 	for i := 0; i < rss.Len(); i++ {
-		rp.attrProc.Process(ctx, rp.logger, rss.At(i).Resource().Attributes())
+		rs := rss.At(i)
+		rs.SetSchemaUrl(rp.processResource(ctx, rs.Resource(), rs.SchemaUrl()))
 	}
 	return td, nil
 }
 
 func (rp *resourceProcessor) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
 	rms := md.ResourceMetrics()
+	if err := rp.checkSchemaURLs(rms.Len(), func(i int) string { return rms.At(i).SchemaUrl() }); err != nil {
+		return md, err
+	}
 	for i := 0; i < rms.Len(); i++ {
-		rp.attrProc.Process(ctx, rp.logger, rms.At(i).Resource().Attributes())
+		rm := rms.At(i)
+		rm.SetSchemaUrl(rp.processResource(ctx, rm.Resource(), rm.SchemaUrl()))
 	}
 	return md, nil
 }
 
 func (rp *resourceProcessor) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
 	rls := ld.ResourceLogs()
+	if err := rp.checkSchemaURLs(rls.Len(), func(i int) string { return rls.At(i).SchemaUrl() }); err != nil {
+		return ld, err
+	}
 	for i := 0; i < rls.Len(); i++ {
-		rp.attrProc.Process(ctx, rp.logger, rls.At(i).Resource().Attributes())
+		rl := rls.At(i)
+		rl.SetSchemaUrl(rp.processResource(ctx, rl.Resource(), rl.SchemaUrl()))
 	}
 	return ld, nil
 }
+
+// checkSchemaURLs rejects the data when one of its n resources has a conflicting schema URL
+// and the policy is to fail, before any resource is changed.
+func (rp *resourceProcessor) checkSchemaURLs(n int, schemaURL func(i int) string) error {
+	if rp.schemaURLProc == nil {
+		return nil
+	}
+	for i := 0; i < n; i++ {
+		if err := rp.schemaURLProc.check(schemaURL(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processResource applies the enrichment, the attributes actions and the budget on the resource and
+// returns its new schema URL.
+func (rp *resourceProcessor) processResource(ctx context.Context, resource pcommon.Resource, schemaURL string) string {
+	if rp.schemaURLProc != nil {
+		schemaURL = rp.schemaURLProc.process(rp.logger, schemaURL)
+	}
+	if rp.enricher != nil {
+		rp.enricher.process(resource.Attributes())
+	}
+	if rp.attrProc != nil {
+		rp.attrProc.Process(ctx, rp.logger, resource.Attributes())
+	}
+	if rp.budgetProc != nil {
+		rp.budgetProc.process(rp.logger, resource.Attributes())
+	}
+	return schemaURL
+}
@@ -23,10 +23,13 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/attraction"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
@@ -96,6 +99,74 @@ func TestResourceProcessorAttributesUpsert(t *testing.T) {
 				"k8s.cluster.name": "test-cluster",
 			},
 		},
+		{
+			name: "config_attributes_delete_by_pattern",
+			config: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				AttributesActions: []attraction.ActionKeyValue{
+					{RegexPattern: "^k8s\\.pod\\.label\\.", Action: attraction.DELETE},
+				},
+			},
+			sourceAttributes: map[string]string{
+				"k8s.pod.name":           "checkout-1",
+				"k8s.pod.label.app":      "checkout",
+				"k8s.pod.label.pod-hash": "5d4f",
+			},
+			wantAttributes: map[string]string{
+				"k8s.pod.name": "checkout-1",
+			},
+		},
+		{
+			name: "budget_max_keys",
+			config: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				Budget:            &AttributeBudget{MaxKeys: 2, PriorityKeys: []string{"service.name"}},
+			},
+			sourceAttributes: map[string]string{
+				"a":            "1",
+				"b":            "2",
+				"service.name": "checkout",
+			},
+			wantAttributes: map[string]string{
+				"a":            "1",
+				"service.name": "checkout",
+			},
+		},
+		{
+			name: "budget_max_total_bytes",
+			config: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				AttributesActions: []attraction.ActionKeyValue{
+					{Key: "host.name", Value: "host-1", Action: attraction.UPSERT},
+				},
+				// "host.name" and "host-1" take 15 bytes, "a" and "1" take 2 bytes
+				Budget: &AttributeBudget{MaxTotalBytes: 20, PriorityKeys: []string{"host.name"}},
+			},
+			sourceAttributes: map[string]string{
+				"a": "1",
+				"b": "2222",
+				"c": "3",
+			},
+			wantAttributes: map[string]string{
+				"host.name": "host-1",
+				"a":         "1",
+			},
+		},
+		{
+			name: "budget_not_exceeded",
+			config: &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				Budget:            &AttributeBudget{MaxKeys: 2, MaxTotalBytes: 100},
+			},
+			sourceAttributes: map[string]string{
+				"a": "1",
+				"b": "2",
+			},
+			wantAttributes: map[string]string{
+				"a": "1",
+				"b": "2",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -173,6 +244,142 @@ func TestResourceProcessorError(t *testing.T) {
 	require.Nil(t, rlp)
 }
 
+func TestResourceProcessorSchemaURL(t *testing.T) {
+	const (
+		schemaURL      = "https://opentelemetry.io/schemas/1.9.0"
+		otherSchemaURL = "https://opentelemetry.io/schemas/1.6.1"
+	)
+	tests := []struct {
+		name          string
+		schemaURL     SchemaURLAction
+		source        string
+		want          string
+		wantErr       bool
+		wantWarnCount int
+	}{
+		{name: "upsert_empty", schemaURL: SchemaURLAction{Value: schemaURL}, source: "", want: schemaURL},
+		{name: "upsert_existing", schemaURL: SchemaURLAction{Value: schemaURL}, source: otherSchemaURL, want: schemaURL},
+		{name: "insert_empty", schemaURL: SchemaURLAction{Value: schemaURL, Action: attraction.INSERT}, source: "", want: schemaURL},
+		{name: "insert_existing", schemaURL: SchemaURLAction{Value: schemaURL, Action: attraction.INSERT}, source: otherSchemaURL, want: otherSchemaURL},
+		{name: "update_empty", schemaURL: SchemaURLAction{Value: schemaURL, Action: attraction.UPDATE}, source: "", want: ""},
+		{name: "update_existing", schemaURL: SchemaURLAction{Value: schemaURL, Action: attraction.UPDATE}, source: otherSchemaURL, want: schemaURL},
+		{name: "delete", schemaURL: SchemaURLAction{Action: attraction.DELETE}, source: otherSchemaURL, want: ""},
+		{
+			name:          "warn_on_conflict",
+			schemaURL:     SchemaURLAction{Value: schemaURL, OnConflict: conflictWarn},
+			source:        otherSchemaURL,
+			want:          schemaURL,
+			wantWarnCount: 1,
+		},
+		{
+			name:      "insert_never_conflicts",
+			schemaURL: SchemaURLAction{Value: schemaURL, Action: attraction.INSERT, OnConflict: conflictFail},
+			source:    otherSchemaURL,
+			want:      otherSchemaURL,
+		},
+		{
+			name:      "fail_on_conflict",
+			schemaURL: SchemaURLAction{Value: schemaURL, OnConflict: conflictFail},
+			source:    otherSchemaURL,
+			wantErr:   true,
+		},
+		{
+			name:      "no_conflict_on_same_schema_url",
+			schemaURL: SchemaURLAction{Value: schemaURL, OnConflict: conflictFail},
+			source:    schemaURL,
+			want:      schemaURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaURLAction := tt.schemaURL
+			cfg := &Config{
+				ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+				SchemaURL:         &schemaURLAction,
+			}
+			require.NoError(t, cfg.Validate())
+
+			core, observed := observer.New(zap.WarnLevel)
+			set := componenttest.NewNopProcessorCreateSettings()
+			set.Logger = zap.New(core)
+
+			factory := NewFactory()
+			ttn := new(consumertest.TracesSink)
+			rtp, err := factory.CreateTracesProcessor(context.Background(), set, cfg, ttn)
+			require.NoError(t, err)
+			td := generateTraceData(nil)
+			td.ResourceSpans().At(0).SetSchemaUrl(tt.source)
+			err = rtp.ConsumeTraces(context.Background(), td)
+			if tt.wantErr {
+				assert.True(t, consumererror.IsPermanent(err))
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, ttn.AllTraces()[0].ResourceSpans().At(0).SchemaUrl())
+			}
+
+			tmn := new(consumertest.MetricsSink)
+			rmp, err := factory.CreateMetricsProcessor(context.Background(), set, cfg, tmn)
+			require.NoError(t, err)
+			md := generateMetricData(nil)
+			md.ResourceMetrics().At(0).SetSchemaUrl(tt.source)
+			err = rmp.ConsumeMetrics(context.Background(), md)
+			if tt.wantErr {
+				assert.True(t, consumererror.IsPermanent(err))
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, tmn.AllMetrics()[0].ResourceMetrics().At(0).SchemaUrl())
+			}
+
+			tln := new(consumertest.LogsSink)
+			rlp, err := factory.CreateLogsProcessor(context.Background(), set, cfg, tln)
+			require.NoError(t, err)
+			ld := generateLogData(nil)
+			ld.ResourceLogs().At(0).SetSchemaUrl(tt.source)
+			err = rlp.ConsumeLogs(context.Background(), ld)
+			if tt.wantErr {
+				assert.True(t, consumererror.IsPermanent(err))
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, tln.AllLogs()[0].ResourceLogs().At(0).SchemaUrl())
+			}
+
+			// One warning per signal.
+			assert.Equal(t, tt.wantWarnCount*3, observed.Len())
+		})
+	}
+}
+
+func TestResourceProcessorSchemaURLFailLeavesDataUnchanged(t *testing.T) {
+	const (
+		schemaURL      = "https://opentelemetry.io/schemas/1.9.0"
+		otherSchemaURL = "https://opentelemetry.io/schemas/1.6.1"
+	)
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(component.NewID(typeStr)),
+		AttributesActions: []attraction.ActionKeyValue{
+			{Key: "cloud.availability_zone", Value: "zone-1", Action: attraction.UPSERT},
+		},
+		SchemaURL: &SchemaURLAction{Value: schemaURL, OnConflict: conflictFail},
+	}
+	require.NoError(t, cfg.Validate())
+
+	rtp, err := NewFactory().CreateTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	// the first resource would be changed, the second one conflicts
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty()
+	td.ResourceSpans().AppendEmpty().SetSchemaUrl(otherSchemaURL)
+	err = rtp.ConsumeTraces(context.Background(), td)
+	assert.True(t, consumererror.IsPermanent(err))
+
+	assert.Equal(t, "", td.ResourceSpans().At(0).SchemaUrl())
+	assert.Equal(t, 0, td.ResourceSpans().At(0).Resource().Attributes().Len())
+	assert.Equal(t, otherSchemaURL, td.ResourceSpans().At(1).SchemaUrl())
+	assert.Equal(t, 0, td.ResourceSpans().At(1).Resource().Attributes().Len())
+}
+
 func generateTraceData(attributes map[string]string) ptrace.Traces {
 	td := testdata.GenerateTracesOneSpanNoResource()
 	if attributes == nil {
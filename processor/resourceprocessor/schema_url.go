@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourceprocessor"
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/attraction"
+)
+
+// schemaURLProc applies a SchemaURLAction on the schema URL of the resources.
+type schemaURLProc struct {
+	value      string
+	action     attraction.Action
+	onConflict ConflictPolicy
+}
+
+func newSchemaURLProc(cfg *SchemaURLAction) *schemaURLProc {
+	if cfg == nil {
+		return nil
+	}
+	proc := &schemaURLProc{
+		value:      cfg.Value,
+		action:     attraction.Action(strings.ToLower(string(cfg.Action))),
+		onConflict: ConflictPolicy(strings.ToLower(string(cfg.OnConflict))),
+	}
+	if proc.action == "" {
+		proc.action = attraction.UPSERT
+	}
+	if proc.onConflict == "" {
+		proc.onConflict = conflictIgnore
+	}
+	return proc
+}
+
+// conflicts returns whether the action replaces the given schema URL with a different one.
+// INSERT and DELETE never conflict, the former keeps the existing schema URLs.
+func (p *schemaURLProc) conflicts(schemaURL string) bool {
+	return (p.action == attraction.UPDATE || p.action == attraction.UPSERT) && schemaURL != "" && schemaURL != p.value
+}
+
+// check returns a permanent error if the given schema URL conflicts and the policy is to fail.
+func (p *schemaURLProc) check(schemaURL string) error {
+	if p.onConflict == conflictFail && p.conflicts(schemaURL) {
+		return consumererror.NewPermanent(fmt.Errorf("resource schema URL %q conflicts with %q", schemaURL, p.value))
+	}
+	return nil
+}
+
+// process returns the schema URL to set on a resource having the given schema URL.
+// The schema URLs are expected to be checked beforehand.
+func (p *schemaURLProc) process(logger *zap.Logger, schemaURL string) string {
+	if p.action == attraction.DELETE {
+		return ""
+	}
+
+	if p.onConflict == conflictWarn && p.conflicts(schemaURL) {
+		logger.Warn("Conflicting resource schema URL",
+			zap.String("schema_url", schemaURL),
+			zap.String("expected_schema_url", p.value),
+			zap.String("action", string(p.action)))
+	}
+
+	switch p.action {
+	case attraction.INSERT:
+		if schemaURL == "" {
+			return p.value
+		}
+	case attraction.UPDATE:
+		if schemaURL != "" {
+			return p.value
+		}
+	case attraction.UPSERT:
+		return p.value
+	}
+	return schemaURL
+}
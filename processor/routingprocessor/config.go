@@ -20,6 +20,8 @@ import (
 	"strings"
 
 	"go.opentelemetry.io/collector/config"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/routingprocessor/internal/common"
 )
 
 var (
@@ -27,6 +29,7 @@ var (
 	errNoExporters            = errors.New("no exporters defined for the route")
 	errNoTableItems           = errors.New("the routing table is empty")
 	errNoMissingFromAttribute = errors.New("the FromAttribute property is empty")
+	errNoOversizedLimits      = errors.New("neither max_size_kib nor max_items is set")
 )
 
 // Config defines configuration for the Routing processor.
@@ -41,6 +44,8 @@ type Config struct {
 	// The allowed values are:
 	// - "context" - the attribute must exist in the incoming context
 	// - "resource" - the attribute must exist in resource attributes
+	// - "auth" - the attribute must exist in the auth data of the client, set by the server
+	//   authenticator of the receiver
 	// The default value is "context".
 	// Optional.
 	AttributeSource AttributeSource `mapstructure:"attribute_source"`
@@ -61,6 +66,21 @@ type Config struct {
 	// Table contains the routing table for this processor.
 	// Required.
 	Table []RoutingTableItem `mapstructure:"table"`
+
+	// Oversized diverts the resources exceeding the configured limits to dedicated exporters,
+	// before the routing table is applied.
+	// Optional.
+	Oversized *OversizedRoute `mapstructure:"oversized"`
+
+	// DryRun sends all the data to the default exporters, the routes the data would have
+	// taken are only recorded in the metrics and the debug logs.
+	// Optional.
+	DryRun bool `mapstructure:"dry_run"`
+
+	// Normalize normalizes the values of the attribute and of the routing table before they are
+	// compared, so that near-miss values such as "Acme" and "acme" take the same route.
+	// Optional.
+	Normalize *NormalizeSettings `mapstructure:"normalize"`
 }
 
 // Validate checks if the processor configuration is valid.
@@ -94,10 +114,32 @@ func (c *Config) Validate() error {
 		)
 	}
 
+	switch c.AttributeSource {
+	case "", contextAttributeSource, resourceAttributeSource, authAttributeSource:
+	default:
+		return fmt.Errorf("unsupported attribute_source %q", c.AttributeSource)
+	}
+
 	if c.AttributeSource != resourceAttributeSource && c.DropRoutingResourceAttribute {
 		return errors.New("using a different attribute source than 'attribute' and drop_resource_routing_attribute is set to true")
 	}
 
+	if c.Oversized != nil {
+		if err := c.Oversized.validate(); err != nil {
+			return fmt.Errorf("invalid oversized route: %w", err)
+		}
+	}
+
+	if c.Normalize != nil {
+		if err := c.Normalize.validate(); err != nil {
+			return fmt.Errorf("invalid normalize: %w", err)
+		}
+	}
+
+	if c.DryRun && len(c.DefaultExporters) == 0 {
+		return errors.New("dry_run requires default_exporters, as all the data is sent to them")
+	}
+
 	return nil
 }
 
@@ -106,6 +148,7 @@ type AttributeSource string
 const (
 	contextAttributeSource  = AttributeSource("context")
 	resourceAttributeSource = AttributeSource("resource")
+	authAttributeSource     = AttributeSource("auth")
 
 	defaultAttributeSource = contextAttributeSource
 )
@@ -127,6 +170,50 @@ type RoutingTableItem struct {
 	Exporters []string `mapstructure:"exporters"`
 }
 
+// OversizedRoute specifies the limits above which the data of a resource is diverted to dedicated exporters
+type OversizedRoute struct {
+	// MaxSizeKiB is the maximum size, in KiB, of a resource and its data serialized as OTLP protobuf.
+	// Zero disables the check.
+	MaxSizeKiB int `mapstructure:"max_size_kib"`
+
+	// MaxItems is the maximum number of spans, data points or log records of a resource.
+	// Zero disables the check.
+	MaxItems int `mapstructure:"max_items"`
+
+	// Exporters contains the list of exporters receiving the oversized resources.
+	// Required.
+	Exporters []string `mapstructure:"exporters"`
+}
+
+func (o *OversizedRoute) validate() error {
+	if o.MaxSizeKiB < 0 || o.MaxItems < 0 {
+		return errors.New("max_size_kib and max_items must not be negative")
+	}
+	if o.MaxSizeKiB == 0 && o.MaxItems == 0 {
+		return errNoOversizedLimits
+	}
+	if len(o.Exporters) == 0 {
+		return errNoExporters
+	}
+	return nil
+}
+
+// exceeds reports whether a resource with the given number of items and, when needed,
+// the serialized size returned by size is above the limits.
+func (o *OversizedRoute) exceeds(items int, size func() int) bool {
+	if o.MaxItems > 0 && items > o.MaxItems {
+		return true
+	}
+	return o.MaxSizeKiB > 0 && size() > o.MaxSizeKiB*1024
+}
+
+func (c *Config) oversizedExporters() []string {
+	if c.Oversized == nil {
+		return nil
+	}
+	return c.Oversized.Exporters
+}
+
 // rewriteRoutingEntriesToOTTL translates the attributes-based routing into OTTL
 func rewriteRoutingEntriesToOTTL(cfg *Config) *Config {
 	if cfg.AttributeSource != resourceAttributeSource {
@@ -149,13 +236,24 @@ func rewriteRoutingEntriesToOTTL(cfg *Config) *Config {
 		} else {
 			s.WriteString("route()")
 		}
-		s.WriteString(
-			fmt.Sprintf(
-				" where resource.attributes[\"%s\"] == \"%s\"",
-				cfg.FromAttribute,
-				e.Value,
-			),
-		)
+		if cfg.Normalize != nil {
+			s.WriteString(
+				fmt.Sprintf(
+					" where %s(resource.attributes[\"%s\"]) == \"%s\"",
+					common.NormalizeRoute,
+					cfg.FromAttribute,
+					e.Value,
+				),
+			)
+		} else {
+			s.WriteString(
+				fmt.Sprintf(
+					" where resource.attributes[\"%s\"] == \"%s\"",
+					cfg.FromAttribute,
+					e.Value,
+				),
+			)
+		}
 		table = append(table, RoutingTableItem{
 			Statement: s.String(),
 			Exporters: e.Exporters,
@@ -164,5 +262,8 @@ func rewriteRoutingEntriesToOTTL(cfg *Config) *Config {
 	return &Config{
 		DefaultExporters: cfg.DefaultExporters,
 		Table:            table,
+		Oversized:        cfg.Oversized,
+		DryRun:           cfg.DryRun,
+		Normalize:        cfg.Normalize,
 	}
 }
@@ -47,6 +47,11 @@ func TestLoadConfig(t *testing.T) {
 						Exporters: []string{"otlp/globex"},
 					},
 				},
+				Oversized: &OversizedRoute{
+					MaxSizeKiB: 512,
+					MaxItems:   10000,
+					Exporters:  []string{"otlp/quarantine"},
+				},
 			},
 		},
 		{
@@ -156,6 +161,66 @@ func TestValidateConfig(t *testing.T) {
 			},
 			error: "using a different attribute source than 'attribute' and drop_resource_routing_attribute is set to true",
 		},
+		{
+			name: "oversized route without limits",
+			config: &Config{
+				FromAttribute: "attr",
+				Table: []RoutingTableItem{
+					{
+						Exporters: []string{"otlp"},
+						Value:     "test",
+					},
+				},
+				Oversized: &OversizedRoute{
+					Exporters: []string{"otlp/quarantine"},
+				},
+			},
+			error: "invalid oversized route: neither max_size_kib nor max_items is set",
+		},
+		{
+			name: "oversized route without exporters",
+			config: &Config{
+				FromAttribute: "attr",
+				Table: []RoutingTableItem{
+					{
+						Exporters: []string{"otlp"},
+						Value:     "test",
+					},
+				},
+				Oversized: &OversizedRoute{
+					MaxItems: 1000,
+				},
+			},
+			error: "invalid oversized route: no exporters defined for the route",
+		},
+		{
+			name: "dry run without default exporters",
+			config: &Config{
+				FromAttribute: "attr",
+				DryRun:        true,
+				Table: []RoutingTableItem{
+					{
+						Exporters: []string{"otlp"},
+						Value:     "test",
+					},
+				},
+			},
+			error: "dry_run requires default_exporters, as all the data is sent to them",
+		},
+		{
+			name: "unsupported attribute source",
+			config: &Config{
+				FromAttribute:   "attr",
+				AttributeSource: "header",
+				Table: []RoutingTableItem{
+					{
+						Exporters: []string{"otlp"},
+						Value:     "test",
+					},
+				},
+			},
+			error: `unsupported attribute_source "header"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -295,6 +360,29 @@ func TestRewriteLegacyConfigToOTTL(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "rewrite routing by resource attribute with normalization",
+			config: Config{
+				FromAttribute:   "attr",
+				AttributeSource: resourceAttributeSource,
+				Normalize:       &NormalizeSettings{Lowercase: true},
+				Table: []RoutingTableItem{
+					{
+						Exporters: []string{"otlp"},
+						Value:     "acme",
+					},
+				},
+			},
+			want: Config{
+				Normalize: &NormalizeSettings{Lowercase: true},
+				Table: []RoutingTableItem{
+					{
+						Exporters: []string{"otlp"},
+						Statement: `route() where normalize_route(resource.attributes["attr"]) == "acme"`,
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
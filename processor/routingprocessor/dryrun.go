@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/routingprocessor"
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+)
+
+// defaultRoute is the route reported in dry-run mode for the data matching no route of the table.
+const defaultRoute = "default"
+
+var (
+	tagRouteKey, _ = tag.NewKey("route")
+
+	mDryRunResources = stats.Int64("processor_routing_dry_run_resources", "Resources that would have been routed through the route in dry-run mode", stats.UnitDimensionless)
+)
+
+// dryRunRoutes counts the resources of a batch per route they would have been routed through.
+type dryRunRoutes map[string]int
+
+// add counts a resource for the route of the table item with the given key, or for
+// the default route when the key is empty.
+func (r dryRunRoutes) add(key string, resources int) {
+	if key == "" {
+		key = defaultRoute
+	}
+	r[key] += resources
+}
+
+// recordDryRun records the routes the batch would have taken.
+func recordDryRun(ctx context.Context, logger *zap.Logger, signal string, routes dryRunRoutes) {
+	for route, resources := range routes {
+		_ = stats.RecordWithTags(
+			ctx,
+			[]tag.Mutator{tag.Upsert(tagSignalKey, signal), tag.Upsert(tagRouteKey, route)},
+			mDryRunResources.M(int64(resources)),
+		)
+	}
+	logger.Debug("Dry run: the data is sent to the default exporters instead of the routes",
+		zap.String("signal", signal), zap.Any("routes", map[string]int(routes)))
+}
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc/metadata"
+)
+
+// dryRunResources returns the resources recorded per route in dry-run mode for the signal.
+func dryRunResources(t *testing.T, signal string) map[string]int64 {
+	rows, err := view.RetrieveData(obsreport.BuildProcessorCustomMetricName(typeStr, mDryRunResources.Name()))
+	require.NoError(t, err)
+	resources := map[string]int64{}
+	for _, row := range rows {
+		var route string
+		matches := false
+		for _, tg := range row.Tags {
+			switch tg {
+			case tag.Tag{Key: tagSignalKey, Value: signal}:
+				matches = true
+			default:
+				if tg.Key == tagRouteKey {
+					route = tg.Value
+				}
+			}
+		}
+		if matches {
+			resources[route] = int64(row.Data.(*view.SumData).Value)
+		}
+	}
+	return resources
+}
+
+func TestTraces_DryRun_ResourceAttribute(t *testing.T) {
+	// reset the data recorded by the other tests
+	view.Unregister(MetricViews()...)
+	require.NoError(t, view.Register(MetricViews()...))
+
+	defaultExp := &mockTracesExporter{}
+	tExp := &mockTracesExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[component.DataType]map[component.ID]component.Exporter {
+			return map[component.DataType]map[component.ID]component.Exporter{
+				component.DataTypeTraces: {
+					component.NewID("otlp"):              defaultExp,
+					component.NewIDWithName("otlp", "2"): tExp,
+				},
+			}
+		},
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	exp := newTracesProcessor(component.TelemetrySettings{Logger: zap.New(core)}, &Config{
+		FromAttribute:                "X-Tenant",
+		AttributeSource:              resourceAttributeSource,
+		DropRoutingResourceAttribute: true,
+		DefaultExporters:             []string{"otlp"},
+		DryRun:                       true,
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/2"},
+			},
+		},
+	})
+
+	tr := ptrace.NewTraces()
+	tr.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("X-Tenant", "acme")
+	tr.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("X-Tenant", "acme")
+	tr.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("X-Tenant", "something-else")
+
+	require.NoError(t, exp.Start(context.Background(), host))
+	require.NoError(t, exp.ConsumeTraces(context.Background(), tr))
+
+	require.Len(t, defaultExp.AllTraces(), 1, "all the traces should be sent to the default exporter")
+	assert.Equal(t, tr, defaultExp.AllTraces()[0])
+	v, ok := defaultExp.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().Get("X-Tenant")
+	require.True(t, ok, "the routing attribute should not be dropped")
+	assert.Equal(t, "acme", v.Str())
+	assert.Len(t, tExp.AllTraces(), 0, "no trace should be routed")
+
+	assert.Equal(t, map[string]int64{
+		`delete_key(resource.attributes, "X-Tenant") where resource.attributes["X-Tenant"] == "acme"`: 2,
+		defaultRoute: 1,
+	}, dryRunResources(t, "traces"))
+	assert.Len(t, logs.FilterMessageSnippet("Dry run").All(), 1)
+}
+
+func TestLogs_DryRun_Context(t *testing.T) {
+	// reset the data recorded by the other tests
+	view.Unregister(MetricViews()...)
+	require.NoError(t, view.Register(MetricViews()...))
+
+	defaultExp := &mockLogsExporter{}
+	lExp := &mockLogsExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[component.DataType]map[component.ID]component.Exporter {
+			return map[component.DataType]map[component.ID]component.Exporter{
+				component.DataTypeLogs: {
+					component.NewID("otlp"):              defaultExp,
+					component.NewIDWithName("otlp", "2"): lExp,
+				},
+			}
+		},
+	}
+
+	exp := newLogProcessor(component.TelemetrySettings{Logger: zap.NewNop()}, &Config{
+		FromAttribute:    "X-Tenant",
+		AttributeSource:  contextAttributeSource,
+		DefaultExporters: []string{"otlp"},
+		DryRun:           true,
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/2"},
+			},
+		},
+	})
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	l := plog.NewLogs()
+	l.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+
+	for _, tenant := range []string{"acme", "acme", "some-custom-value1"} {
+		require.NoError(t, exp.ConsumeLogs(
+			metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+				"X-Tenant": tenant,
+			})),
+			l,
+		))
+	}
+
+	assert.Len(t, defaultExp.AllLogs(), 3, "all the logs should be sent to the default exporter")
+	assert.Len(t, lExp.AllLogs(), 0, "no log should be routed")
+	assert.Equal(t, map[string]int64{"acme": 2, defaultRoute: 1}, dryRunResources(t, "logs"))
+}
@@ -16,30 +16,46 @@ package routingprocessor // import "github.com/open-telemetry/opentelemetry-coll
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
+	"go.opentelemetry.io/collector/client"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/metadata"
 )
 
 // extractor is responsible for extracting configured attributes from the processed data.
-// Currently, it can only extract the attributes from context.
+// Currently, it can only extract the attributes from context, either from the request metadata
+// or from the auth data of the client.
 type extractor struct {
-	fromAttr string
-	logger   *zap.Logger
+	fromAttr  string
+	fromAuth  bool
+	normalize *normalizer
+	logger    *zap.Logger
 }
 
 // newExtractor creates new extractor which can extract attributes from logs,
 // metrics and traces from requested attribute source and from the provided
 // attribute name.
-func newExtractor(fromAttr string, logger *zap.Logger) extractor {
+func newExtractor(fromAttr string, source AttributeSource, normalize *normalizer, logger *zap.Logger) extractor {
 	return extractor{
-		fromAttr: fromAttr,
-		logger:   logger,
+		fromAttr:  fromAttr,
+		fromAuth:  source == authAttributeSource,
+		normalize: normalize,
+		logger:    logger,
 	}
 }
 
+// extractFromContext returns the normalized route value of the context.
 func (e extractor) extractFromContext(ctx context.Context) string {
+	return e.normalize.apply(e.extractValue(ctx))
+}
+
+func (e extractor) extractValue(ctx context.Context) string {
+	if e.fromAuth {
+		return e.extractFromAuth(ctx)
+	}
+
 	// right now, we only support looking up attributes from requests that have
 	// gone through the gRPC server in that case, it will add the HTTP headers
 	// as context metadata
@@ -63,3 +79,53 @@ func (e extractor) extractFromContext(ctx context.Context) string {
 
 	return values[0]
 }
+
+// extractFromAuth looks up the attribute in the auth data of the client, as validated by the
+// server authenticator of the receiver rather than supplied by the client. An attribute name
+// with dots, such as "claims.tenant", is looked up in the nested maps of the auth data when the
+// auth data has no attribute with the full name.
+func (e extractor) extractFromAuth(ctx context.Context) string {
+	auth := client.FromContext(ctx).Auth
+	if auth == nil {
+		return ""
+	}
+
+	value := auth.GetAttribute(e.fromAttr)
+	if value == nil {
+		path := strings.Split(e.fromAttr, ".")
+		value = auth.GetAttribute(path[0])
+		for _, key := range path[1:] {
+			m, ok := value.(map[string]interface{})
+			if !ok {
+				return ""
+			}
+			value = m[key]
+		}
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		if len(v) == 0 {
+			return ""
+		}
+		if len(v) > 1 {
+			e.logger.Debug("more than one value found for the attribute, using only the first",
+				zap.Strings("values", v),
+				zap.String("attribute", e.fromAttr),
+			)
+		}
+		return v[0]
+	case fmt.Stringer:
+		return v.String()
+	default:
+		e.logger.Debug("unsupported type of the auth attribute",
+			zap.String("type", fmt.Sprintf("%T", value)),
+			zap.String("attribute", e.fromAttr),
+		)
+		return ""
+	}
+}
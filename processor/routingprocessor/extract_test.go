@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/client"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/metadata"
 )
@@ -70,7 +71,7 @@ func TestExtractorForTraces_FromContext(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			e := newExtractor(tc.fromAttr, zap.NewNop())
+			e := newExtractor(tc.fromAttr, contextAttributeSource, nil, zap.NewNop())
 
 			assert.Equal(t,
 				tc.expectedValue,
@@ -79,3 +80,105 @@ func TestExtractorForTraces_FromContext(t *testing.T) {
 		})
 	}
 }
+
+// authData is the auth data set by a server authenticator.
+type authData map[string]interface{}
+
+func (a authData) GetAttribute(name string) interface{} {
+	return a[name]
+}
+
+func (a authData) GetAttributeNames() []string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestExtractor_FromAuth(t *testing.T) {
+	auth := authData{
+		"subject":    "acme",
+		"membership": []string{"globex", "acme"},
+		"groups":     []string{},
+		"claims": map[string]interface{}{
+			"tenant": "initech",
+			"org":    map[string]interface{}{"id": "umbrella"},
+			"level":  3,
+		},
+	}
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		fromAttr      string
+		expectedValue string
+	}{
+		{
+			name:          "value from the subject",
+			ctx:           client.NewContext(context.Background(), client.Info{Auth: auth}),
+			fromAttr:      "subject",
+			expectedValue: "acme",
+		},
+		{
+			name:          "first value from a list",
+			ctx:           client.NewContext(context.Background(), client.Info{Auth: auth}),
+			fromAttr:      "membership",
+			expectedValue: "globex",
+		},
+		{
+			name:          "no value from an empty list",
+			ctx:           client.NewContext(context.Background(), client.Info{Auth: auth}),
+			fromAttr:      "groups",
+			expectedValue: "",
+		},
+		{
+			name:          "value from a claim",
+			ctx:           client.NewContext(context.Background(), client.Info{Auth: auth}),
+			fromAttr:      "claims.tenant",
+			expectedValue: "initech",
+		},
+		{
+			name:          "value from a nested claim",
+			ctx:           client.NewContext(context.Background(), client.Info{Auth: auth}),
+			fromAttr:      "claims.org.id",
+			expectedValue: "umbrella",
+		},
+		{
+			name:          "no value from a missing claim",
+			ctx:           client.NewContext(context.Background(), client.Info{Auth: auth}),
+			fromAttr:      "claims.subject.id",
+			expectedValue: "",
+		},
+		{
+			name:          "no value from an unsupported type",
+			ctx:           client.NewContext(context.Background(), client.Info{Auth: auth}),
+			fromAttr:      "claims.level",
+			expectedValue: "",
+		},
+		{
+			name:          "no value without auth data",
+			ctx:           client.NewContext(context.Background(), client.Info{}),
+			fromAttr:      "subject",
+			expectedValue: "",
+		},
+		{
+			name: "the request metadata is ignored",
+			ctx: metadata.NewIncomingContext(context.Background(),
+				metadata.Pairs("subject", "acme"),
+			),
+			fromAttr:      "subject",
+			expectedValue: "",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newExtractor(tc.fromAttr, authAttributeSource, nil, zap.NewNop())
+
+			assert.Equal(t,
+				tc.expectedValue,
+				e.extractFromContext(tc.ctx),
+			)
+		})
+	}
+}
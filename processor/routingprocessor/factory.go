@@ -17,6 +17,7 @@ package routingprocessor // import "github.com/open-telemetry/opentelemetry-coll
 import (
 	"context"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
@@ -32,6 +33,9 @@ const (
 
 // NewFactory creates a factory for the routing processor.
 func NewFactory() component.ProcessorFactory {
+	// TODO: find a more appropriate way to get this done, as we are swallowing the error here
+	_ = view.Register(MetricViews()...)
+
 	return component.NewProcessorFactory(
 		typeStr,
 		createDefaultConfig,
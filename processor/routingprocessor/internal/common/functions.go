@@ -21,8 +21,26 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
-func Functions[K any]() map[string]interface{} {
+// NormalizeRoute is the function normalizing the route values in the statements
+// the attributes-based routing is translated into.
+const NormalizeRoute = "normalize_route"
+
+// Functions returns the OTTL functions of the routing statements, NormalizeRoute applying normalize
+// to the string values, the values of the other types are returned as is.
+func Functions[K any](normalize func(string) string) map[string]interface{} {
 	return map[string]interface{}{
+		NormalizeRoute: func(target ottl.Getter[K]) (ottl.ExprFunc[K], error) {
+			return func(ctx context.Context, tCtx K) (interface{}, error) {
+				val, err := target.Get(ctx, tCtx)
+				if err != nil {
+					return nil, err
+				}
+				if s, ok := val.(string); ok {
+					return normalize(s), nil
+				}
+				return val, nil
+			}, nil
+		},
 		"IsMatch":              ottlfuncs.IsMatch[K],
 		"delete_key":           ottlfuncs.DeleteKey[K],
 		"delete_matching_keys": ottlfuncs.DeleteMatchingKeys[K],
@@ -39,7 +39,8 @@ type logProcessor struct {
 }
 
 func newLogProcessor(settings component.TelemetrySettings, config component.ProcessorConfig) *logProcessor {
-	cfg := rewriteRoutingEntriesToOTTL(config.(*Config))
+	cfg := rewriteRoutingEntriesToOTTL(normalizeRoutingTable(config.(*Config)))
+	normalize := newNormalizer(cfg.Normalize)
 
 	return &logProcessor{
 		logger: settings.Logger,
@@ -47,10 +48,11 @@ func newLogProcessor(settings component.TelemetrySettings, config component.Proc
 		router: newRouter[component.LogsExporter, ottllog.TransformContext](
 			cfg.Table,
 			cfg.DefaultExporters,
+			cfg.oversizedExporters(),
 			settings,
-			ottllog.NewParser(common.Functions[ottllog.TransformContext](), settings),
+			ottllog.NewParser(common.Functions[ottllog.TransformContext](normalize.apply), settings),
 		),
-		extractor: newExtractor(cfg.FromAttribute, settings.Logger),
+		extractor: newExtractor(cfg.FromAttribute, cfg.AttributeSource, normalize, settings.Logger),
 	}
 }
 
@@ -63,6 +65,26 @@ func (p *logProcessor) Start(_ context.Context, host component.Host) error {
 }
 
 func (p *logProcessor) ConsumeLogs(ctx context.Context, l plog.Logs) error {
+	if p.config.DryRun {
+		return p.dryRun(ctx, l)
+	}
+	if p.config.Oversized != nil && len(p.router.oversizedExporters) > 0 {
+		regular, oversized := splitOversizedLogs(ctx, p.config.Oversized, l)
+		if oversized.ResourceLogs().Len() > 0 {
+			var errs error
+			for _, e := range p.router.oversizedExporters {
+				errs = multierr.Append(errs, e.ConsumeLogs(ctx, oversized))
+			}
+			if regular.ResourceLogs().Len() > 0 {
+				errs = multierr.Append(errs, p.consumeLogs(ctx, regular))
+			}
+			return errs
+		}
+	}
+	return p.consumeLogs(ctx, l)
+}
+
+func (p *logProcessor) consumeLogs(ctx context.Context, l plog.Logs) error {
 	if p.config.FromAttribute == "" {
 		err := p.route(ctx, l)
 		if err != nil {
@@ -150,6 +172,53 @@ func (p *logProcessor) routeForContext(ctx context.Context, l plog.Logs) error {
 	return errs
 }
 
+// dryRun sends the logs to the default exporters, recording the routes they would have taken.
+func (p *logProcessor) dryRun(ctx context.Context, l plog.Logs) error {
+	routes := dryRunRoutes{}
+	if p.config.FromAttribute == "" {
+		for i := 0; i < l.ResourceLogs().Len(); i++ {
+			// the statements are executed on a copy, so that the resources sent to the
+			// default exporters aren't changed
+			resource := pcommon.NewResource()
+			l.ResourceLogs().At(i).Resource().CopyTo(resource)
+			ltx := ottllog.NewTransformContext(
+				plog.LogRecord{},
+				pcommon.InstrumentationScope{},
+				resource,
+			)
+
+			matched := false
+			for key, route := range p.router.routes {
+				_, isMatch, err := route.statement.Execute(ctx, ltx)
+				if err != nil {
+					p.logger.Debug("Dry run: failed to evaluate the route", zap.String("route", key), zap.Error(err))
+					continue
+				}
+				if isMatch {
+					matched = true
+					routes.add(key, 1)
+				}
+			}
+			if !matched {
+				routes.add("", 1)
+			}
+		}
+	} else {
+		value := p.extractor.extractFromContext(ctx)
+		if _, ok := p.router.routes[value]; !ok {
+			value = ""
+		}
+		routes.add(value, l.ResourceLogs().Len())
+	}
+	recordDryRun(ctx, p.logger, "logs", routes)
+
+	var errs error
+	for _, e := range p.router.defaultExporters {
+		errs = multierr.Append(errs, e.ConsumeLogs(ctx, l))
+	}
+	return errs
+}
+
 func (p *logProcessor) Shutdown(context.Context) error {
 	return nil
 }
@@ -39,7 +39,8 @@ type metricsProcessor struct {
 }
 
 func newMetricProcessor(settings component.TelemetrySettings, config component.ProcessorConfig) *metricsProcessor {
-	cfg := rewriteRoutingEntriesToOTTL(config.(*Config))
+	cfg := rewriteRoutingEntriesToOTTL(normalizeRoutingTable(config.(*Config)))
+	normalize := newNormalizer(cfg.Normalize)
 
 	return &metricsProcessor{
 		logger: settings.Logger,
@@ -47,10 +48,11 @@ func newMetricProcessor(settings component.TelemetrySettings, config component.P
 		router: newRouter[component.MetricsExporter](
 			cfg.Table,
 			cfg.DefaultExporters,
+			cfg.oversizedExporters(),
 			settings,
-			ottldatapoint.NewParser(common.Functions[ottldatapoint.TransformContext](), settings),
+			ottldatapoint.NewParser(common.Functions[ottldatapoint.TransformContext](normalize.apply), settings),
 		),
-		extractor: newExtractor(cfg.FromAttribute, settings.Logger),
+		extractor: newExtractor(cfg.FromAttribute, cfg.AttributeSource, normalize, settings.Logger),
 	}
 }
 
@@ -63,6 +65,26 @@ func (p *metricsProcessor) Start(_ context.Context, host component.Host) error {
 }
 
 func (p *metricsProcessor) ConsumeMetrics(ctx context.Context, m pmetric.Metrics) error {
+	if p.config.DryRun {
+		return p.dryRun(ctx, m)
+	}
+	if p.config.Oversized != nil && len(p.router.oversizedExporters) > 0 {
+		regular, oversized := splitOversizedMetrics(ctx, p.config.Oversized, m)
+		if oversized.ResourceMetrics().Len() > 0 {
+			var errs error
+			for _, e := range p.router.oversizedExporters {
+				errs = multierr.Append(errs, e.ConsumeMetrics(ctx, oversized))
+			}
+			if regular.ResourceMetrics().Len() > 0 {
+				errs = multierr.Append(errs, p.consumeMetrics(ctx, regular))
+			}
+			return errs
+		}
+	}
+	return p.consumeMetrics(ctx, m)
+}
+
+func (p *metricsProcessor) consumeMetrics(ctx context.Context, m pmetric.Metrics) error {
 	if p.config.FromAttribute == "" {
 		err := p.route(ctx, m)
 		if err != nil {
@@ -153,6 +175,55 @@ func (p *metricsProcessor) routeForContext(ctx context.Context, m pmetric.Metric
 	return errs
 }
 
+// dryRun sends the metrics to the default exporters, recording the routes they would have taken.
+func (p *metricsProcessor) dryRun(ctx context.Context, m pmetric.Metrics) error {
+	routes := dryRunRoutes{}
+	if p.config.FromAttribute == "" {
+		for i := 0; i < m.ResourceMetrics().Len(); i++ {
+			// the statements are executed on a copy, so that the resources sent to the
+			// default exporters aren't changed
+			resource := pcommon.NewResource()
+			m.ResourceMetrics().At(i).Resource().CopyTo(resource)
+			mtx := ottldatapoint.NewTransformContext(
+				nil,
+				pmetric.Metric{},
+				pmetric.MetricSlice{},
+				pcommon.InstrumentationScope{},
+				resource,
+			)
+
+			matched := false
+			for key, route := range p.router.routes {
+				_, isMatch, err := route.statement.Execute(ctx, mtx)
+				if err != nil {
+					p.logger.Debug("Dry run: failed to evaluate the route", zap.String("route", key), zap.Error(err))
+					continue
+				}
+				if isMatch {
+					matched = true
+					routes.add(key, 1)
+				}
+			}
+			if !matched {
+				routes.add("", 1)
+			}
+		}
+	} else {
+		value := p.extractor.extractFromContext(ctx)
+		if _, ok := p.router.routes[value]; !ok {
+			value = ""
+		}
+		routes.add(value, m.ResourceMetrics().Len())
+	}
+	recordDryRun(ctx, p.logger, "metrics", routes)
+
+	var errs error
+	for _, e := range p.router.defaultExporters {
+		errs = multierr.Append(errs, e.ConsumeMetrics(ctx, m))
+	}
+	return errs
+}
+
 func (p *metricsProcessor) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/routingprocessor"
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// NormalizeSettings defines how the route values are normalized before being looked up in the routing table,
+// the functions are applied in the order of the fields.
+type NormalizeSettings struct {
+	// Trim removes the leading and trailing white spaces.
+	Trim bool `mapstructure:"trim"`
+
+	// StripPort removes the port of the values in the "host:port" form, e.g. the Host header.
+	StripPort bool `mapstructure:"strip_port"`
+
+	// Lowercase converts the values to lower case.
+	Lowercase bool `mapstructure:"lowercase"`
+
+	// RegexCapture is a regular expression with a capturing group, the values matching it are replaced
+	// by the text of the first group, e.g. "^tenant-(.+)$". The values not matching it are kept as is.
+	RegexCapture string `mapstructure:"regex_capture"`
+}
+
+func (n *NormalizeSettings) validate() error {
+	if n.RegexCapture == "" {
+		return nil
+	}
+	re, err := regexp.Compile(n.RegexCapture)
+	if err != nil {
+		return fmt.Errorf("invalid regex_capture: %w", err)
+	}
+	if re.NumSubexp() == 0 {
+		return errors.New("regex_capture must have a capturing group")
+	}
+	return nil
+}
+
+// normalizer normalizes the route values, a nil normalizer keeps the values as is.
+type normalizer struct {
+	trim      bool
+	stripPort bool
+	lowercase bool
+	capture   *regexp.Regexp
+}
+
+func newNormalizer(cfg *NormalizeSettings) *normalizer {
+	if cfg == nil {
+		return nil
+	}
+	n := &normalizer{
+		trim:      cfg.Trim,
+		stripPort: cfg.StripPort,
+		lowercase: cfg.Lowercase,
+	}
+	if cfg.RegexCapture != "" {
+		// the expression is validated with the config
+		n.capture = regexp.MustCompile(cfg.RegexCapture)
+	}
+	return n
+}
+
+// apply returns the normalized value.
+func (n *normalizer) apply(value string) string {
+	if n == nil {
+		return value
+	}
+	if n.trim {
+		value = strings.TrimSpace(value)
+	}
+	if n.stripPort {
+		if host, _, err := net.SplitHostPort(value); err == nil {
+			value = host
+		}
+	}
+	if n.lowercase {
+		value = strings.ToLower(value)
+	}
+	if n.capture != nil {
+		if groups := n.capture.FindStringSubmatch(value); groups != nil {
+			value = groups[1]
+		}
+	}
+	return value
+}
+
+// normalizeRoutingTable returns the config with the values of the routing table normalized like the
+// route values, so that the values of the table don't need to be written in the normalized form.
+func normalizeRoutingTable(cfg *Config) *Config {
+	if cfg.Normalize == nil {
+		return cfg
+	}
+	n := newNormalizer(cfg.Normalize)
+	normalized := *cfg
+	normalized.Table = make([]RoutingTableItem, len(cfg.Table))
+	for i, item := range cfg.Table {
+		if item.Value != "" {
+			item.Value = n.apply(item.Value)
+		}
+		normalized.Table[i] = item
+	}
+	return &normalized
+}
@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNormalizerApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings *NormalizeSettings
+		value    string
+		want     string
+	}{
+		{
+			name:  "no normalization",
+			value: " Acme ",
+			want:  " Acme ",
+		},
+		{
+			name:     "trim and lowercase",
+			settings: &NormalizeSettings{Trim: true, Lowercase: true},
+			value:    " Acme\t",
+			want:     "acme",
+		},
+		{
+			name:     "strip port",
+			settings: &NormalizeSettings{StripPort: true},
+			value:    "acme.example.com:8080",
+			want:     "acme.example.com",
+		},
+		{
+			name:     "strip port of an IPv6 address",
+			settings: &NormalizeSettings{StripPort: true},
+			value:    "[::1]:4317",
+			want:     "::1",
+		},
+		{
+			name:     "no port to strip",
+			settings: &NormalizeSettings{StripPort: true},
+			value:    "acme.example.com",
+			want:     "acme.example.com",
+		},
+		{
+			name:     "regex capture",
+			settings: &NormalizeSettings{Lowercase: true, RegexCapture: `^tenant-(\w+)$`},
+			value:    "Tenant-Acme",
+			want:     "acme",
+		},
+		{
+			name:     "regex not matching",
+			settings: &NormalizeSettings{RegexCapture: `^tenant-(\w+)$`},
+			value:    "acme",
+			want:     "acme",
+		},
+		{
+			name:     "all",
+			settings: &NormalizeSettings{Trim: true, StripPort: true, Lowercase: true, RegexCapture: `^([^.]+)\.`},
+			value:    " Acme.Example.com:443 ",
+			want:     "acme",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newNormalizer(tt.settings).apply(tt.value))
+		})
+	}
+}
+
+func TestValidateNormalize(t *testing.T) {
+	cfg := &Config{
+		FromAttribute: "X-Tenant",
+		Table:         []RoutingTableItem{{Value: "acme", Exporters: []string{"otlp"}}},
+		Normalize:     &NormalizeSettings{RegexCapture: `^tenant-(\w+)$`},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Normalize.RegexCapture = `^tenant-\w+$`
+	assert.EqualError(t, cfg.Validate(), "invalid normalize: regex_capture must have a capturing group")
+
+	cfg.Normalize.RegexCapture = `(`
+	assert.EqualError(t, cfg.Validate(), "invalid normalize: invalid regex_capture: error parsing regexp: missing closing ): `(`")
+}
+
+func TestTraces_RoutingWorks_NormalizedResourceAttribute(t *testing.T) {
+	defaultExp := &mockTracesExporter{}
+	tExp := &mockTracesExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[component.DataType]map[component.ID]component.Exporter {
+			return map[component.DataType]map[component.ID]component.Exporter{
+				component.DataTypeTraces: {
+					component.NewID("otlp"):              defaultExp,
+					component.NewIDWithName("otlp", "2"): tExp,
+				},
+			}
+		},
+	}
+
+	exp := newTracesProcessor(component.TelemetrySettings{Logger: zap.NewNop()}, &Config{
+		FromAttribute:    "X-Tenant",
+		AttributeSource:  resourceAttributeSource,
+		DefaultExporters: []string{"otlp"},
+		Normalize:        &NormalizeSettings{Trim: true, Lowercase: true},
+		Table: []RoutingTableItem{
+			{
+				// the values of the table are normalized too
+				Value:     "ACME",
+				Exporters: []string{"otlp/2"},
+			},
+		},
+	})
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	tr := ptrace.NewTraces()
+	tr.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("X-Tenant", "acme")
+	tr.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("X-Tenant", " Acme ")
+	tr.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("X-Tenant", "acme-corp")
+	tr.ResourceSpans().AppendEmpty().Resource().Attributes().PutInt("X-Tenant", 1)
+
+	require.NoError(t, exp.ConsumeTraces(context.Background(), tr))
+
+	require.Len(t, tExp.AllTraces(), 1)
+	routed := tExp.AllTraces()[0].ResourceSpans()
+	require.Equal(t, 2, routed.Len())
+	// the attribute itself isn't changed
+	v, _ := routed.At(1).Resource().Attributes().Get("X-Tenant")
+	assert.Equal(t, " Acme ", v.Str())
+	require.Len(t, defaultExp.AllTraces(), 1)
+	assert.Equal(t, 2, defaultExp.AllTraces()[0].ResourceSpans().Len())
+}
+
+func TestLogs_RoutingWorks_NormalizedContext(t *testing.T) {
+	defaultExp := &mockLogsExporter{}
+	lExp := &mockLogsExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[component.DataType]map[component.ID]component.Exporter {
+			return map[component.DataType]map[component.ID]component.Exporter{
+				component.DataTypeLogs: {
+					component.NewID("otlp"):              defaultExp,
+					component.NewIDWithName("otlp", "2"): lExp,
+				},
+			}
+		},
+	}
+
+	exp := newLogProcessor(component.TelemetrySettings{Logger: zap.NewNop()}, &Config{
+		FromAttribute:    "Host",
+		AttributeSource:  contextAttributeSource,
+		DefaultExporters: []string{"otlp"},
+		Normalize:        &NormalizeSettings{StripPort: true, Lowercase: true, RegexCapture: `^([^.]+)\.`},
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/2"},
+			},
+		},
+	})
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	l := plog.NewLogs()
+	l.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+
+	for _, h := range []string{"acme.example.com:4318", "ACME.example.com", "initech.example.com"} {
+		require.NoError(t, exp.ConsumeLogs(
+			metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+				"Host": h,
+			})),
+			l,
+		))
+	}
+
+	assert.Len(t, lExp.AllLogs(), 2)
+	assert.Len(t, defaultExp.AllLogs(), 1)
+}
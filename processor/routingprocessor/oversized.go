@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/routingprocessor"
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var (
+	tagSignalKey, _ = tag.NewKey("signal")
+
+	mOversizedResources = stats.Int64("processor_routing_oversized_resources", "Resources diverted to the oversized exporters", stats.UnitDimensionless)
+	mOversizedItems     = stats.Int64("processor_routing_oversized_items", "Spans, data points or log records diverted to the oversized exporters", stats.UnitDimensionless)
+
+	tracesSizer  = &ptrace.ProtoMarshaler{}
+	metricsSizer = &pmetric.ProtoMarshaler{}
+	logsSizer    = &plog.ProtoMarshaler{}
+)
+
+// MetricViews returns the metrics views related to the routing processor.
+func MetricViews() []*view.View {
+	return []*view.View{
+		{
+			Name:        obsreport.BuildProcessorCustomMetricName(typeStr, mOversizedResources.Name()),
+			Measure:     mOversizedResources,
+			Description: mOversizedResources.Description(),
+			TagKeys:     []tag.Key{tagSignalKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        obsreport.BuildProcessorCustomMetricName(typeStr, mOversizedItems.Name()),
+			Measure:     mOversizedItems,
+			Description: mOversizedItems.Description(),
+			TagKeys:     []tag.Key{tagSignalKey},
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        obsreport.BuildProcessorCustomMetricName(typeStr, mDryRunResources.Name()),
+			Measure:     mDryRunResources,
+			Description: mDryRunResources.Description(),
+			TagKeys:     []tag.Key{tagSignalKey, tagRouteKey},
+			Aggregation: view.Sum(),
+		},
+	}
+}
+
+func recordOversized(ctx context.Context, signal string, resources, items int) {
+	_ = stats.RecordWithTags(
+		ctx,
+		[]tag.Mutator{tag.Upsert(tagSignalKey, signal)},
+		mOversizedResources.M(int64(resources)),
+		mOversizedItems.M(int64(items)),
+	)
+}
+
+// splitOversizedTraces separates the resource spans exceeding the limits from the
+// other ones. The traces are returned as is when no resource exceeds the limits.
+func splitOversizedTraces(ctx context.Context, limits *OversizedRoute, t ptrace.Traces) (ptrace.Traces, ptrace.Traces) {
+	rss := t.ResourceSpans()
+	exceeding := make([]bool, rss.Len())
+	found := false
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		spanCount := 0
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spanCount += rs.ScopeSpans().At(j).Spans().Len()
+		}
+		exceeding[i] = limits.exceeds(spanCount, func() int {
+			single := ptrace.NewTraces()
+			rs.CopyTo(single.ResourceSpans().AppendEmpty())
+			return tracesSizer.TracesSize(single)
+		})
+		found = found || exceeding[i]
+	}
+	if !found {
+		return t, ptrace.NewTraces()
+	}
+
+	regular, oversized := ptrace.NewTraces(), ptrace.NewTraces()
+	for i := 0; i < rss.Len(); i++ {
+		if exceeding[i] {
+			rss.At(i).CopyTo(oversized.ResourceSpans().AppendEmpty())
+		} else {
+			rss.At(i).CopyTo(regular.ResourceSpans().AppendEmpty())
+		}
+	}
+	recordOversized(ctx, "traces", oversized.ResourceSpans().Len(), oversized.SpanCount())
+	return regular, oversized
+}
+
+// splitOversizedMetrics separates the resource metrics exceeding the limits from the
+// other ones. The metrics are returned as is when no resource exceeds the limits.
+func splitOversizedMetrics(ctx context.Context, limits *OversizedRoute, m pmetric.Metrics) (pmetric.Metrics, pmetric.Metrics) {
+	rms := m.ResourceMetrics()
+	exceeding := make([]bool, rms.Len())
+	found := false
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		dataPointCount := 0
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			metrics := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				dataPointCount += countDataPoints(metrics.At(k))
+			}
+		}
+		exceeding[i] = limits.exceeds(dataPointCount, func() int {
+			single := pmetric.NewMetrics()
+			rm.CopyTo(single.ResourceMetrics().AppendEmpty())
+			return metricsSizer.MetricsSize(single)
+		})
+		found = found || exceeding[i]
+	}
+	if !found {
+		return m, pmetric.NewMetrics()
+	}
+
+	regular, oversized := pmetric.NewMetrics(), pmetric.NewMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		if exceeding[i] {
+			rms.At(i).CopyTo(oversized.ResourceMetrics().AppendEmpty())
+		} else {
+			rms.At(i).CopyTo(regular.ResourceMetrics().AppendEmpty())
+		}
+	}
+	recordOversized(ctx, "metrics", oversized.ResourceMetrics().Len(), oversized.DataPointCount())
+	return regular, oversized
+}
+
+func countDataPoints(metric pmetric.Metric) int {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		return metric.Gauge().DataPoints().Len()
+	case pmetric.MetricTypeSum:
+		return metric.Sum().DataPoints().Len()
+	case pmetric.MetricTypeHistogram:
+		return metric.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return metric.ExponentialHistogram().DataPoints().Len()
+	case pmetric.MetricTypeSummary:
+		return metric.Summary().DataPoints().Len()
+	}
+	return 0
+}
+
+// splitOversizedLogs separates the resource logs exceeding the limits from the
+// other ones. The logs are returned as is when no resource exceeds the limits.
+func splitOversizedLogs(ctx context.Context, limits *OversizedRoute, l plog.Logs) (plog.Logs, plog.Logs) {
+	rls := l.ResourceLogs()
+	exceeding := make([]bool, rls.Len())
+	found := false
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		recordCount := 0
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			recordCount += rl.ScopeLogs().At(j).LogRecords().Len()
+		}
+		exceeding[i] = limits.exceeds(recordCount, func() int {
+			single := plog.NewLogs()
+			rl.CopyTo(single.ResourceLogs().AppendEmpty())
+			return logsSizer.LogsSize(single)
+		})
+		found = found || exceeding[i]
+	}
+	if !found {
+		return l, plog.NewLogs()
+	}
+
+	regular, oversized := plog.NewLogs(), plog.NewLogs()
+	for i := 0; i < rls.Len(); i++ {
+		if exceeding[i] {
+			rls.At(i).CopyTo(oversized.ResourceLogs().AppendEmpty())
+		} else {
+			rls.At(i).CopyTo(regular.ResourceLogs().AppendEmpty())
+		}
+	}
+	recordOversized(ctx, "logs", oversized.ResourceLogs().Len(), oversized.LogRecordCount())
+	return regular, oversized
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestOversizedRouteExceeds(t *testing.T) {
+	sizeCalled := false
+	size := func() int {
+		sizeCalled = true
+		return 2048
+	}
+
+	limits := &OversizedRoute{MaxItems: 10}
+	assert.True(t, limits.exceeds(11, size))
+	assert.False(t, limits.exceeds(10, size))
+	assert.False(t, sizeCalled, "the size must not be computed without max_size_kib")
+
+	limits = &OversizedRoute{MaxSizeKiB: 2}
+	assert.False(t, limits.exceeds(1000, size))
+	limits = &OversizedRoute{MaxSizeKiB: 1}
+	assert.True(t, limits.exceeds(1, size))
+}
+
+func TestSplitOversizedTraces(t *testing.T) {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("small")
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName(strings.Repeat("x", 2048))
+
+	regular, oversized := splitOversizedTraces(context.Background(), &OversizedRoute{MaxSizeKiB: 1}, td)
+	assert.Equal(t, 1, regular.ResourceSpans().Len())
+	assert.Equal(t, "small", regular.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, 1, oversized.ResourceSpans().Len())
+
+	regular, oversized = splitOversizedTraces(context.Background(), &OversizedRoute{MaxSizeKiB: 4}, td)
+	assert.Equal(t, td, regular)
+	assert.Equal(t, 0, oversized.ResourceSpans().Len())
+}
+
+func TestSplitOversizedMetrics(t *testing.T) {
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+	dps := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptySum().DataPoints()
+	dps.AppendEmpty()
+	dps.AppendEmpty()
+
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	metrics.AppendEmpty().SetEmptyHistogram().DataPoints().AppendEmpty()
+	metrics.AppendEmpty().SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	metrics.AppendEmpty().SetEmptySummary().DataPoints().AppendEmpty()
+
+	regular, oversized := splitOversizedMetrics(context.Background(), &OversizedRoute{MaxItems: 1}, md)
+	assert.Equal(t, 1, regular.DataPointCount())
+	assert.Equal(t, 5, oversized.DataPointCount())
+
+	regular, oversized = splitOversizedMetrics(context.Background(), &OversizedRoute{MaxItems: 2}, md)
+	assert.Equal(t, 3, regular.DataPointCount())
+	assert.Equal(t, 3, oversized.DataPointCount())
+
+	regular, oversized = splitOversizedMetrics(context.Background(), &OversizedRoute{MaxItems: 3, MaxSizeKiB: 1}, md)
+	assert.Equal(t, md, regular)
+	assert.Equal(t, 0, oversized.ResourceMetrics().Len())
+}
+
+func TestSplitOversizedLogs(t *testing.T) {
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty()
+	records.AppendEmpty()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+
+	regular, oversized := splitOversizedLogs(context.Background(), &OversizedRoute{MaxItems: 1}, ld)
+	assert.Equal(t, 1, regular.LogRecordCount())
+	assert.Equal(t, 2, oversized.LogRecordCount())
+}
@@ -33,11 +33,13 @@ type router[E component.Exporter, K any] struct {
 	logger *zap.Logger
 	parser ottl.Parser[K]
 
-	defaultExporterIDs []string
-	table              []RoutingTableItem
+	defaultExporterIDs   []string
+	oversizedExporterIDs []string
+	table                []RoutingTableItem
 
-	defaultExporters []E
-	routes           map[string]routingItem[E, K]
+	defaultExporters   []E
+	oversizedExporters []E
+	routes             map[string]routingItem[E, K]
 }
 
 // newRouter creates a new router instance with its type parameter constrained
@@ -45,6 +47,7 @@ type router[E component.Exporter, K any] struct {
 func newRouter[E component.Exporter, K any](
 	table []RoutingTableItem,
 	defaultExporterIDs []string,
+	oversizedExporterIDs []string,
 	settings component.TelemetrySettings,
 	parser ottl.Parser[K],
 ) router[E, K] {
@@ -52,8 +55,9 @@ func newRouter[E component.Exporter, K any](
 		logger: settings.Logger,
 		parser: parser,
 
-		table:              table,
-		defaultExporterIDs: defaultExporterIDs,
+		table:                table,
+		defaultExporterIDs:   defaultExporterIDs,
+		oversizedExporterIDs: oversizedExporterIDs,
 
 		routes: make(map[string]routingItem[E, K]),
 	}
@@ -77,6 +81,12 @@ func (r *router[E, K]) registerExporters(available map[component.ID]component.Ex
 		return err
 	}
 
+	// register exporters for the oversized resources
+	err = r.registerOversizedExporters(available)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -97,6 +107,23 @@ func (r *router[E, K]) registerDefaultExporters(available map[component.ID]compo
 	return nil
 }
 
+// registerOversizedExporters registers the configured exporters for the
+// oversized resources using the provided available exporters map.
+func (r *router[E, K]) registerOversizedExporters(available map[component.ID]component.Exporter) error {
+	for _, name := range r.oversizedExporterIDs {
+		e, err := r.extractExporter(name, available)
+		if errors.Is(err, errExporterNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		r.oversizedExporters = append(r.oversizedExporters, e)
+	}
+
+	return nil
+}
+
 // registerRouteExporters registers route exporters using the provided
 // available exporters map to check if they were available.
 func (r *router[E, K]) registerRouteExporters(available map[component.ID]component.Exporter) error {
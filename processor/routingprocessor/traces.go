@@ -39,7 +39,8 @@ type tracesProcessor struct {
 }
 
 func newTracesProcessor(settings component.TelemetrySettings, config component.ProcessorConfig) *tracesProcessor {
-	cfg := rewriteRoutingEntriesToOTTL(config.(*Config))
+	cfg := rewriteRoutingEntriesToOTTL(normalizeRoutingTable(config.(*Config)))
+	normalize := newNormalizer(cfg.Normalize)
 
 	return &tracesProcessor{
 		logger: settings.Logger,
@@ -47,10 +48,11 @@ func newTracesProcessor(settings component.TelemetrySettings, config component.P
 		router: newRouter[component.TracesExporter, ottlspan.TransformContext](
 			cfg.Table,
 			cfg.DefaultExporters,
+			cfg.oversizedExporters(),
 			settings,
-			ottlspan.NewParser(common.Functions[ottlspan.TransformContext](), settings),
+			ottlspan.NewParser(common.Functions[ottlspan.TransformContext](normalize.apply), settings),
 		),
-		extractor: newExtractor(cfg.FromAttribute, settings.Logger),
+		extractor: newExtractor(cfg.FromAttribute, cfg.AttributeSource, normalize, settings.Logger),
 	}
 }
 
@@ -63,6 +65,26 @@ func (p *tracesProcessor) Start(_ context.Context, host component.Host) error {
 }
 
 func (p *tracesProcessor) ConsumeTraces(ctx context.Context, t ptrace.Traces) error {
+	if p.config.DryRun {
+		return p.dryRun(ctx, t)
+	}
+	if p.config.Oversized != nil && len(p.router.oversizedExporters) > 0 {
+		regular, oversized := splitOversizedTraces(ctx, p.config.Oversized, t)
+		if oversized.ResourceSpans().Len() > 0 {
+			var errs error
+			for _, e := range p.router.oversizedExporters {
+				errs = multierr.Append(errs, e.ConsumeTraces(ctx, oversized))
+			}
+			if regular.ResourceSpans().Len() > 0 {
+				errs = multierr.Append(errs, p.consumeTraces(ctx, regular))
+			}
+			return errs
+		}
+	}
+	return p.consumeTraces(ctx, t)
+}
+
+func (p *tracesProcessor) consumeTraces(ctx context.Context, t ptrace.Traces) error {
 	// TODO: determine the proper action when errors happen
 	if p.config.FromAttribute == "" {
 		err := p.route(ctx, t)
@@ -146,6 +168,53 @@ func (p *tracesProcessor) routeForContext(ctx context.Context, t ptrace.Traces)
 	return errs
 }
 
+// dryRun sends the traces to the default exporters, recording the routes they would have taken.
+func (p *tracesProcessor) dryRun(ctx context.Context, t ptrace.Traces) error {
+	routes := dryRunRoutes{}
+	if p.config.FromAttribute == "" {
+		for i := 0; i < t.ResourceSpans().Len(); i++ {
+			// the statements are executed on a copy, so that the resources sent to the
+			// default exporters aren't changed
+			resource := pcommon.NewResource()
+			t.ResourceSpans().At(i).Resource().CopyTo(resource)
+			stx := ottlspan.NewTransformContext(
+				ptrace.Span{},
+				pcommon.InstrumentationScope{},
+				resource,
+			)
+
+			matched := false
+			for key, route := range p.router.routes {
+				_, isMatch, err := route.statement.Execute(ctx, stx)
+				if err != nil {
+					p.logger.Debug("Dry run: failed to evaluate the route", zap.String("route", key), zap.Error(err))
+					continue
+				}
+				if isMatch {
+					matched = true
+					routes.add(key, 1)
+				}
+			}
+			if !matched {
+				routes.add("", 1)
+			}
+		}
+	} else {
+		value := p.extractor.extractFromContext(ctx)
+		if _, ok := p.router.routes[value]; !ok {
+			value = ""
+		}
+		routes.add(value, t.ResourceSpans().Len())
+	}
+	recordDryRun(ctx, p.logger, "traces", routes)
+
+	var errs error
+	for _, e := range p.router.defaultExporters {
+		errs = multierr.Append(errs, e.ConsumeTraces(ctx, t))
+	}
+	return errs
+}
+
 func (p *tracesProcessor) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
@@ -227,6 +228,56 @@ func TestTraces_RoutingWorks_Context(t *testing.T) {
 	})
 }
 
+func TestTraces_RoutingWorks_Auth(t *testing.T) {
+	defaultExp := &mockTracesExporter{}
+	tExp := &mockTracesExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[component.DataType]map[component.ID]component.Exporter {
+			return map[component.DataType]map[component.ID]component.Exporter{
+				component.DataTypeTraces: {
+					component.NewID("otlp"):              defaultExp,
+					component.NewIDWithName("otlp", "2"): tExp,
+				},
+			}
+		},
+	}
+
+	exp := newTracesProcessor(component.TelemetrySettings{Logger: zap.NewNop()}, &Config{
+		FromAttribute:    "subject",
+		AttributeSource:  authAttributeSource,
+		DefaultExporters: []string{"otlp"},
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/2"},
+			},
+		},
+	})
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	tr := ptrace.NewTraces()
+	tr.ResourceSpans().AppendEmpty()
+
+	// a header claiming the tenant isn't trusted
+	require.NoError(t, exp.ConsumeTraces(
+		metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+			"subject": "acme",
+		})),
+		tr,
+	))
+	assert.Len(t, defaultExp.AllTraces(), 1)
+	assert.Len(t, tExp.AllTraces(), 0)
+
+	require.NoError(t, exp.ConsumeTraces(
+		client.NewContext(context.Background(), client.Info{Auth: authData{"subject": "acme"}}),
+		tr,
+	))
+	assert.Len(t, defaultExp.AllTraces(), 1)
+	assert.Len(t, tExp.AllTraces(), 1)
+}
+
 func TestTraces_RoutingWorks_ResourceAttribute(t *testing.T) {
 	defaultExp := &mockTracesExporter{}
 	tExp := &mockTracesExporter{}
@@ -479,6 +530,64 @@ func TestTraceProcessorCapabilities(t *testing.T) {
 	assert.Equal(t, false, p.Capabilities().MutatesData)
 }
 
+func TestTraces_OversizedResourcesAreDiverted(t *testing.T) {
+	defaultExp := &mockTracesExporter{}
+	tExp := &mockTracesExporter{}
+	oversizedExp := &mockTracesExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[component.DataType]map[component.ID]component.Exporter {
+			return map[component.DataType]map[component.ID]component.Exporter{
+				component.DataTypeTraces: {
+					component.NewID("otlp"):                       defaultExp,
+					component.NewIDWithName("otlp", "2"):          tExp,
+					component.NewIDWithName("otlp", "quarantine"): oversizedExp,
+				},
+			}
+		},
+	}
+
+	exp := newTracesProcessor(component.TelemetrySettings{Logger: zap.NewNop()}, &Config{
+		FromAttribute:    "X-Tenant",
+		AttributeSource:  resourceAttributeSource,
+		DefaultExporters: []string{"otlp"},
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/2"},
+			},
+		},
+		Oversized: &OversizedRoute{
+			MaxItems:  2,
+			Exporters: []string{"otlp/quarantine"},
+		},
+	})
+
+	tr := ptrace.NewTraces()
+
+	rs := tr.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("X-Tenant", "acme")
+	rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("span")
+
+	rs = tr.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("X-Tenant", "acme")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	for i := 0; i < 3; i++ {
+		spans.AppendEmpty().SetName("abusive")
+	}
+
+	ctx := context.Background()
+	require.NoError(t, exp.Start(ctx, host))
+	require.NoError(t, exp.ConsumeTraces(ctx, tr))
+
+	require.Len(t, oversizedExp.AllTraces(), 1)
+	assert.Equal(t, 3, oversizedExp.AllTraces()[0].SpanCount())
+	require.Len(t, tExp.AllTraces(), 1)
+	assert.Equal(t, 1, tExp.AllTraces()[0].SpanCount())
+	assert.Len(t, defaultExp.AllTraces(), 0)
+}
+
 type mockTracesExporter struct {
 	mockComponent
 	consumertest.TracesSink
@@ -15,8 +15,10 @@
 package apachereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/apachereceiver"
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
+	"time"
 
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
@@ -28,6 +30,25 @@ type Config struct {
 	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
 	confighttp.HTTPClientSettings           `mapstructure:",squash"`
 	Metrics                                 metadata.MetricsSettings `mapstructure:"metrics"`
+	// UnixSocket is the path of the unix domain socket the status is requested through, the endpoint
+	// giving the path of the status and the Host header of the requests.
+	UnixSocket string `mapstructure:"unix_socket"`
+	// StatusFile is the path of a file the status is read from instead of requesting it, kept up to
+	// date by another process.
+	StatusFile string `mapstructure:"status_file"`
+	// ScrapeTimeout is the maximum duration of a scrape, its retries included, 0 leaving the scrapes
+	// only bounded by the timeout of the client.
+	ScrapeTimeout time.Duration `mapstructure:"scrape_timeout"`
+	// Retry defines the retries of the failed status requests within a scrape.
+	Retry RetrySettings `mapstructure:"retry"`
+}
+
+// RetrySettings defines the retries of the failed status requests within a scrape.
+type RetrySettings struct {
+	// MaxAttempts is the maximum number of attempts to collect the status per scrape, 1 disabling the retries.
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// Backoff is the duration waited between two attempts.
+	Backoff time.Duration `mapstructure:"backoff"`
 }
 
 var (
@@ -39,6 +60,23 @@ var (
 )
 
 func (cfg *Config) Validate() error {
+	if cfg.UnixSocket != "" && cfg.StatusFile != "" {
+		return errors.New("unix_socket and status_file can't be both set")
+	}
+
+	if cfg.ScrapeTimeout < 0 {
+		return errors.New("scrape_timeout must not be negative")
+	}
+	if cfg.CollectionInterval > 0 && cfg.ScrapeTimeout > cfg.CollectionInterval {
+		return errors.New("scrape_timeout must not be greater than collection_interval")
+	}
+	if cfg.Retry.MaxAttempts < 1 {
+		return errors.New("retry.max_attempts must be at least 1")
+	}
+	if cfg.Retry.Backoff < 0 {
+		return errors.New("retry.backoff must not be negative")
+	}
+
 	u, err := url.Parse(cfg.Endpoint)
 	if err != nil {
 		return fmt.Errorf("invalid endpoint: '%s': %w", cfg.Endpoint, err)
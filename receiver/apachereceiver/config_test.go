@@ -28,6 +28,8 @@ func TestValidate(t *testing.T) {
 	testCases := []struct {
 		desc        string
 		endpoint    string
+		unixSocket  string
+		statusFile  string
 		errExpected bool
 		errText     string
 	}{
@@ -75,11 +77,31 @@ func TestValidate(t *testing.T) {
 			errExpected: true,
 			errText:     "query must be 'auto': 'http://localhost:8080/server-status?nonsense'",
 		},
+		{
+			desc:       "unix_socket",
+			endpoint:   "http://localhost/server-status?auto",
+			unixSocket: "/var/run/apache2/status.sock",
+		},
+		{
+			desc:       "status_file",
+			endpoint:   "http://localhost/server-status?auto",
+			statusFile: "/var/lib/apache2/server-status",
+		},
+		{
+			desc:        "unix_socket_and_status_file",
+			endpoint:    "http://localhost/server-status?auto",
+			unixSocket:  "/var/run/apache2/status.sock",
+			statusFile:  "/var/lib/apache2/server-status",
+			errExpected: true,
+			errText:     "unix_socket and status_file can't be both set",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
 			cfg := NewFactory().CreateDefaultConfig().(*Config)
 			cfg.Endpoint = tc.endpoint
+			cfg.UnixSocket = tc.unixSocket
+			cfg.StatusFile = tc.statusFile
 			err := cfg.Validate()
 			if tc.errExpected {
 				require.EqualError(t, err, tc.errText)
@@ -90,6 +112,54 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateScrapeControls(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		configure func(*Config)
+		errText   string
+	}{
+		{
+			desc: "retries",
+			configure: func(cfg *Config) {
+				cfg.ScrapeTimeout = 5 * time.Second
+				cfg.Retry.MaxAttempts = 3
+			},
+		},
+		{
+			desc:      "negative_scrape_timeout",
+			configure: func(cfg *Config) { cfg.ScrapeTimeout = -time.Second },
+			errText:   "scrape_timeout must not be negative",
+		},
+		{
+			desc:      "scrape_timeout_above_collection_interval",
+			configure: func(cfg *Config) { cfg.ScrapeTimeout = time.Minute },
+			errText:   "scrape_timeout must not be greater than collection_interval",
+		},
+		{
+			desc:      "no_attempt",
+			configure: func(cfg *Config) { cfg.Retry.MaxAttempts = 0 },
+			errText:   "retry.max_attempts must be at least 1",
+		},
+		{
+			desc:      "negative_backoff",
+			configure: func(cfg *Config) { cfg.Retry.Backoff = -time.Second },
+			errText:   "retry.backoff must not be negative",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			tc.configure(cfg)
+			err := cfg.Validate()
+			if tc.errText != "" {
+				require.EqualError(t, err, tc.errText)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestLoadConfig(t *testing.T) {
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
 	require.NoError(t, err)
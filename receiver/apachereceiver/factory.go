@@ -55,6 +55,10 @@ func createDefaultConfig() component.ReceiverConfig {
 			Timeout:  10 * time.Second,
 		},
 		Metrics: metadata.DefaultMetricsSettings(),
+		Retry: RetrySettings{
+			MaxAttempts: 1,
+			Backoff:     time.Second,
+		},
 	}
 }
 
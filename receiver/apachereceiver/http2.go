@@ -0,0 +1,61 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apachereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/apachereceiver"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+)
+
+// The entries added to the server status by mod_http2, only present when the module is loaded.
+const (
+	http2ConnectionsKey = "H2Connections"
+	http2StreamsKey     = "H2Streams"
+	http2PushesKey      = "H2Pushes"
+)
+
+// recordHTTP2Metrics records the HTTP/2 metrics when mod_http2 reports its status. The
+// support is detected on each scrape, as the module can be loaded or unloaded on a
+// server reload.
+func (r *apacheScraper) recordHTTP2Metrics(now pcommon.Timestamp, stats map[string]string, errs *scrapererror.ScrapeErrors) {
+	_, available := stats[http2ConnectionsKey]
+	if available != r.http2Available {
+		r.http2Available = available
+		if available {
+			r.settings.Logger.Info("mod_http2 status detected, reporting the HTTP/2 metrics")
+		} else {
+			r.settings.Logger.Info("mod_http2 status no longer reported, the HTTP/2 metrics are not recorded anymore")
+		}
+	}
+	if !available {
+		return
+	}
+
+	record := func(key string, recordDataPoint func(pcommon.Timestamp, string) error,
+		recordDataPointWithServerName func(pcommon.Timestamp, string, string) error) {
+		value, ok := stats[key]
+		if !ok {
+			return
+		}
+		if r.emitMetricsWithServerNameAsResourceAttribute {
+			addPartialIfError(errs, recordDataPoint(now, value))
+		} else {
+			addPartialIfError(errs, recordDataPointWithServerName(now, value, r.serverName))
+		}
+	}
+	record(http2ConnectionsKey, r.mb.RecordApacheHTTP2ConnectionsDataPoint, r.mb.RecordApacheHTTP2ConnectionsDataPointWithServerName)
+	record(http2StreamsKey, r.mb.RecordApacheHTTP2StreamsDataPoint, r.mb.RecordApacheHTTP2StreamsDataPointWithServerName)
+	record(http2PushesKey, r.mb.RecordApacheHTTP2PushesDataPoint, r.mb.RecordApacheHTTP2PushesDataPointWithServerName)
+}
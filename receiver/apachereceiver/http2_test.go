@@ -0,0 +1,96 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apachereceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const http2Stats = `H2Connections: 12
+H2Streams: 31
+H2Pushes: 4
+`
+
+// http2Metrics returns the values of the HTTP/2 metrics recorded by the scraper.
+func http2Metrics(t *testing.T, scraper *apacheScraper) map[string]int64 {
+	values := map[string]int64{}
+	metrics := scraper.mb.Emit()
+	if metrics.ResourceMetrics().Len() == 0 {
+		return values
+	}
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		dps := ms.At(i).Sum().DataPoints()
+		require.Equal(t, 1, dps.Len())
+		values[ms.At(i).Name()] = dps.At(0).IntValue()
+	}
+	return values
+}
+
+func TestRecordHTTP2Metrics(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	settings := componenttest.NewNopReceiverCreateSettings()
+	settings.Logger = zap.New(core)
+	scraper := newApacheScraper(settings, createDefaultConfig().(*Config), "localhost", "8080")
+	scraper.emitMetricsWithServerNameAsResourceAttribute = true
+	now := pcommon.NewTimestampFromTime(time.Now())
+
+	errs := &scrapererror.ScrapeErrors{}
+	scraper.recordHTTP2Metrics(now, parseStats("ServerUptimeSeconds: 410\n"), errs)
+	require.NoError(t, errs.Combine())
+	assert.Empty(t, http2Metrics(t, scraper), "nothing is recorded without mod_http2")
+	assert.False(t, scraper.http2Available)
+	assert.Equal(t, 0, logs.FilterMessageSnippet("mod_http2").Len())
+
+	scraper.recordHTTP2Metrics(now, parseStats(http2Stats), errs)
+	require.NoError(t, errs.Combine())
+	assert.Equal(t, map[string]int64{
+		"apache.http2.connections": 12,
+		"apache.http2.streams":     31,
+		"apache.http2.pushes":      4,
+	}, http2Metrics(t, scraper))
+	assert.True(t, scraper.http2Available)
+	assert.Equal(t, 1, logs.FilterMessageSnippet("mod_http2 status detected").Len())
+
+	scraper.recordHTTP2Metrics(now, parseStats("H2Connections: 1\nH2Streams: invalid\n"), errs)
+	assert.Error(t, errs.Combine())
+	assert.Equal(t, map[string]int64{"apache.http2.connections": 1}, http2Metrics(t, scraper))
+
+	scraper.recordHTTP2Metrics(now, parseStats("ServerUptimeSeconds: 420\n"), errs)
+	assert.Empty(t, http2Metrics(t, scraper))
+	assert.False(t, scraper.http2Available)
+	assert.Equal(t, 1, logs.FilterMessageSnippet("mod_http2 status no longer reported").Len())
+}
+
+func TestRecordHTTP2MetricsWithServerName(t *testing.T) {
+	scraper := newApacheScraper(componenttest.NewNopReceiverCreateSettings(), createDefaultConfig().(*Config), "localhost", "8080")
+	scraper.emitMetricsWithServerNameAsResourceAttribute = false
+
+	require.NoError(t, scraper.scrapeWithServerNameAttr(http2Stats))
+	ms := scraper.mb.Emit().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 3, ms.Len())
+	for i := 0; i < ms.Len(); i++ {
+		assert.Equal(t, map[string]interface{}{"server_name": "localhost"}, ms.At(i).Sum().DataPoints().At(0).Attributes().AsRaw())
+	}
+}
@@ -44,6 +44,39 @@ func (m *metricApacheCurrentConnections) recordDataPointWithServerName(start pco
 	dp.Attributes().PutStr("server_name", serverNameAttributeValue)
 }
 
+func (m *metricApacheHTTP2Connections) recordDataPointWithServerName(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, serverNameAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("server_name", serverNameAttributeValue)
+}
+
+func (m *metricApacheHTTP2Pushes) recordDataPointWithServerName(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, serverNameAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("server_name", serverNameAttributeValue)
+}
+
+func (m *metricApacheHTTP2Streams) recordDataPointWithServerName(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, serverNameAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("server_name", serverNameAttributeValue)
+}
+
 func (m *metricApacheLoad1) recordDataPointWithServerName(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, serverNameAttributeValue string) {
 	if !m.settings.Enabled {
 		return
@@ -77,6 +110,17 @@ func (m *metricApacheLoad5) recordDataPointWithServerName(start pcommon.Timestam
 	dp.Attributes().PutStr("server_name", serverNameAttributeValue)
 }
 
+func (m *metricApacheRequestAvgDuration) recordDataPointWithServerName(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, serverNameAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("server_name", serverNameAttributeValue)
+}
+
 func (m *metricApacheRequestTime) recordDataPointWithServerName(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, serverNameAttributeValue string) {
 	if !m.settings.Enabled {
 		return
@@ -99,6 +143,17 @@ func (m *metricApacheRequests) recordDataPointWithServerName(start pcommon.Times
 	dp.Attributes().PutStr("server_name", serverNameAttributeValue)
 }
 
+func (m *metricApacheRequestsRate) recordDataPointWithServerName(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, serverNameAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("server_name", serverNameAttributeValue)
+}
+
 func (m *metricApacheScoreboard) recordDataPointWithServerName(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, serverNameAttributeValue string, scoreboardStateAttributeValue string) {
 	if !m.settings.Enabled {
 		return
@@ -133,6 +188,18 @@ func (m *metricApacheUptime) recordDataPointWithServerName(start pcommon.Timesta
 	dp.Attributes().PutStr("server_name", serverNameAttributeValue)
 }
 
+func (m *metricApacheUp) recordDataPointWithServerName(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, serverNameAttributeValue string, upReasonAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("server_name", serverNameAttributeValue)
+	dp.Attributes().PutStr("reason", upReasonAttributeValue)
+}
+
 func (m *metricApacheWorkers) recordDataPointWithServerName(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, serverNameAttributeValue string, workersStateAttributeValue string) {
 	if !m.settings.Enabled {
 		return
@@ -175,6 +242,36 @@ func (mb *MetricsBuilder) RecordApacheCurrentConnectionsDataPointWithServerName(
 	return nil
 }
 
+// RecordApacheHTTP2ConnectionsDataPoint adds a data point to apache.http2.connections metric.
+func (mb *MetricsBuilder) RecordApacheHTTP2ConnectionsDataPointWithServerName(ts pcommon.Timestamp, inputVal string, serverNameAttributeValue string) error {
+	val, err := strconv.ParseInt(inputVal, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse int64 for ApacheHTTP2Connections, value was %s: %w", inputVal, err)
+	}
+	mb.metricApacheHTTP2Connections.recordDataPointWithServerName(mb.startTime, ts, val, serverNameAttributeValue)
+	return nil
+}
+
+// RecordApacheHTTP2PushesDataPoint adds a data point to apache.http2.pushes metric.
+func (mb *MetricsBuilder) RecordApacheHTTP2PushesDataPointWithServerName(ts pcommon.Timestamp, inputVal string, serverNameAttributeValue string) error {
+	val, err := strconv.ParseInt(inputVal, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse int64 for ApacheHTTP2Pushes, value was %s: %w", inputVal, err)
+	}
+	mb.metricApacheHTTP2Pushes.recordDataPointWithServerName(mb.startTime, ts, val, serverNameAttributeValue)
+	return nil
+}
+
+// RecordApacheHTTP2StreamsDataPoint adds a data point to apache.http2.streams metric.
+func (mb *MetricsBuilder) RecordApacheHTTP2StreamsDataPointWithServerName(ts pcommon.Timestamp, inputVal string, serverNameAttributeValue string) error {
+	val, err := strconv.ParseInt(inputVal, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse int64 for ApacheHTTP2Streams, value was %s: %w", inputVal, err)
+	}
+	mb.metricApacheHTTP2Streams.recordDataPointWithServerName(mb.startTime, ts, val, serverNameAttributeValue)
+	return nil
+}
+
 // RecordApacheLoad1DataPoint adds a data point to apache.load.1 metric.
 func (mb *MetricsBuilder) RecordApacheLoad1DataPointWithServerName(ts pcommon.Timestamp, inputVal string, serverNameAttributeValue string) error {
 	val, err := strconv.ParseFloat(inputVal, 64)
@@ -205,6 +302,11 @@ func (mb *MetricsBuilder) RecordApacheLoad5DataPointWithServerName(ts pcommon.Ti
 	return nil
 }
 
+// RecordApacheRequestAvgDurationDataPoint adds a data point to apache.request.avg_duration metric.
+func (mb *MetricsBuilder) RecordApacheRequestAvgDurationDataPointWithServerName(ts pcommon.Timestamp, val float64, serverNameAttributeValue string) {
+	mb.metricApacheRequestAvgDuration.recordDataPointWithServerName(mb.startTime, ts, val, serverNameAttributeValue)
+}
+
 // RecordApacheRequestTimeDataPoint adds a data point to apache.request.time metric.
 func (mb *MetricsBuilder) RecordApacheRequestTimeDataPointWithServerName(ts pcommon.Timestamp, inputVal string, serverNameAttributeValue string) error {
 	val, err := strconv.ParseInt(inputVal, 10, 64)
@@ -225,6 +327,11 @@ func (mb *MetricsBuilder) RecordApacheRequestsDataPointWithServerName(ts pcommon
 	return nil
 }
 
+// RecordApacheRequestsRateDataPoint adds a data point to apache.requests.rate metric.
+func (mb *MetricsBuilder) RecordApacheRequestsRateDataPointWithServerName(ts pcommon.Timestamp, val float64, serverNameAttributeValue string) {
+	mb.metricApacheRequestsRate.recordDataPointWithServerName(mb.startTime, ts, val, serverNameAttributeValue)
+}
+
 // RecordApacheScoreboardDataPoint adds a data point to apache.scoreboard metric.
 func (mb *MetricsBuilder) RecordApacheScoreboardDataPointWithServerName(ts pcommon.Timestamp, val int64, serverNameAttributeValue string, scoreboardStateAttributeValue AttributeScoreboardState) {
 	mb.metricApacheScoreboard.recordDataPointWithServerName(mb.startTime, ts, val, serverNameAttributeValue, scoreboardStateAttributeValue.String())
@@ -245,6 +352,11 @@ func (mb *MetricsBuilder) RecordApacheUptimeDataPointWithServerName(ts pcommon.T
 	return nil
 }
 
+// RecordApacheUpDataPoint adds a data point to apache.up metric.
+func (mb *MetricsBuilder) RecordApacheUpDataPointWithServerName(ts pcommon.Timestamp, val int64, serverNameAttributeValue string, upReasonAttributeValue AttributeUpReason) {
+	mb.metricApacheUp.recordDataPointWithServerName(mb.startTime, ts, val, serverNameAttributeValue, upReasonAttributeValue.String())
+}
+
 // RecordApacheWorkersDataPoint adds a data point to apache.workers metric.
 func (mb *MetricsBuilder) RecordApacheWorkersDataPointWithServerName(ts pcommon.Timestamp, inputVal string, serverNameAttributeValue string, workersStateAttributeValue AttributeWorkersState) error {
 	val, err := strconv.ParseInt(inputVal, 10, 64)
@@ -42,13 +42,19 @@ type MetricsSettings struct {
 	ApacheCPULoad            MetricSettings `mapstructure:"apache.cpu.load"`
 	ApacheCPUTime            MetricSettings `mapstructure:"apache.cpu.time"`
 	ApacheCurrentConnections MetricSettings `mapstructure:"apache.current_connections"`
+	ApacheHTTP2Connections   MetricSettings `mapstructure:"apache.http2.connections"`
+	ApacheHTTP2Pushes        MetricSettings `mapstructure:"apache.http2.pushes"`
+	ApacheHTTP2Streams       MetricSettings `mapstructure:"apache.http2.streams"`
 	ApacheLoad1              MetricSettings `mapstructure:"apache.load.1"`
 	ApacheLoad15             MetricSettings `mapstructure:"apache.load.15"`
 	ApacheLoad5              MetricSettings `mapstructure:"apache.load.5"`
+	ApacheRequestAvgDuration MetricSettings `mapstructure:"apache.request.avg_duration"`
 	ApacheRequestTime        MetricSettings `mapstructure:"apache.request.time"`
 	ApacheRequests           MetricSettings `mapstructure:"apache.requests"`
+	ApacheRequestsRate       MetricSettings `mapstructure:"apache.requests.rate"`
 	ApacheScoreboard         MetricSettings `mapstructure:"apache.scoreboard"`
 	ApacheTraffic            MetricSettings `mapstructure:"apache.traffic"`
+	ApacheUp                 MetricSettings `mapstructure:"apache.up"`
 	ApacheUptime             MetricSettings `mapstructure:"apache.uptime"`
 	ApacheWorkers            MetricSettings `mapstructure:"apache.workers"`
 }
@@ -64,6 +70,15 @@ func DefaultMetricsSettings() MetricsSettings {
 		ApacheCurrentConnections: MetricSettings{
 			Enabled: true,
 		},
+		ApacheHTTP2Connections: MetricSettings{
+			Enabled: true,
+		},
+		ApacheHTTP2Pushes: MetricSettings{
+			Enabled: true,
+		},
+		ApacheHTTP2Streams: MetricSettings{
+			Enabled: true,
+		},
 		ApacheLoad1: MetricSettings{
 			Enabled: true,
 		},
@@ -73,18 +88,27 @@ func DefaultMetricsSettings() MetricsSettings {
 		ApacheLoad5: MetricSettings{
 			Enabled: true,
 		},
+		ApacheRequestAvgDuration: MetricSettings{
+			Enabled: false,
+		},
 		ApacheRequestTime: MetricSettings{
 			Enabled: true,
 		},
 		ApacheRequests: MetricSettings{
 			Enabled: true,
 		},
+		ApacheRequestsRate: MetricSettings{
+			Enabled: false,
+		},
 		ApacheScoreboard: MetricSettings{
 			Enabled: true,
 		},
 		ApacheTraffic: MetricSettings{
 			Enabled: true,
 		},
+		ApacheUp: MetricSettings{
+			Enabled: false,
+		},
 		ApacheUptime: MetricSettings{
 			Enabled: true,
 		},
@@ -212,6 +236,44 @@ var MapAttributeScoreboardState = map[string]AttributeScoreboardState{
 	"unknown":      AttributeScoreboardStateUnknown,
 }
 
+// AttributeUpReason specifies the a value up_reason attribute.
+type AttributeUpReason int
+
+const (
+	_ AttributeUpReason = iota
+	AttributeUpReasonOk
+	AttributeUpReasonTimeout
+	AttributeUpReasonConnectionError
+	AttributeUpReasonHTTPError
+	AttributeUpReasonReadError
+)
+
+// String returns the string representation of the AttributeUpReason.
+func (av AttributeUpReason) String() string {
+	switch av {
+	case AttributeUpReasonOk:
+		return "ok"
+	case AttributeUpReasonTimeout:
+		return "timeout"
+	case AttributeUpReasonConnectionError:
+		return "connection_error"
+	case AttributeUpReasonHTTPError:
+		return "http_error"
+	case AttributeUpReasonReadError:
+		return "read_error"
+	}
+	return ""
+}
+
+// MapAttributeUpReason is a helper map of string to AttributeUpReason attribute value.
+var MapAttributeUpReason = map[string]AttributeUpReason{
+	"ok":               AttributeUpReasonOk,
+	"timeout":          AttributeUpReasonTimeout,
+	"connection_error": AttributeUpReasonConnectionError,
+	"http_error":       AttributeUpReasonHTTPError,
+	"read_error":       AttributeUpReasonReadError,
+}
+
 // AttributeWorkersState specifies the a value workers_state attribute.
 type AttributeWorkersState int
 
@@ -392,6 +454,159 @@ func newMetricApacheCurrentConnections(settings MetricSettings) metricApacheCurr
 	return m
 }
 
+type metricApacheHTTP2Connections struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills apache.http2.connections metric with initial data.
+func (m *metricApacheHTTP2Connections) init() {
+	m.data.SetName("apache.http2.connections")
+	m.data.SetDescription("The number of HTTP/2 connections currently open. Only reported when mod_http2 is loaded.")
+	m.data.SetUnit("{connections}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+}
+
+func (m *metricApacheHTTP2Connections) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricApacheHTTP2Connections) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricApacheHTTP2Connections) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricApacheHTTP2Connections(settings MetricSettings) metricApacheHTTP2Connections {
+	m := metricApacheHTTP2Connections{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricApacheHTTP2Pushes struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills apache.http2.pushes metric with initial data.
+func (m *metricApacheHTTP2Pushes) init() {
+	m.data.SetName("apache.http2.pushes")
+	m.data.SetDescription("The number of resources pushed to the HTTP/2 clients. Only reported when mod_http2 is loaded.")
+	m.data.SetUnit("{pushes}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+}
+
+func (m *metricApacheHTTP2Pushes) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricApacheHTTP2Pushes) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricApacheHTTP2Pushes) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricApacheHTTP2Pushes(settings MetricSettings) metricApacheHTTP2Pushes {
+	m := metricApacheHTTP2Pushes{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricApacheHTTP2Streams struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills apache.http2.streams metric with initial data.
+func (m *metricApacheHTTP2Streams) init() {
+	m.data.SetName("apache.http2.streams")
+	m.data.SetDescription("The number of HTTP/2 streams currently open. Only reported when mod_http2 is loaded.")
+	m.data.SetUnit("{streams}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(false)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+}
+
+func (m *metricApacheHTTP2Streams) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricApacheHTTP2Streams) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricApacheHTTP2Streams) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricApacheHTTP2Streams(settings MetricSettings) metricApacheHTTP2Streams {
+	m := metricApacheHTTP2Streams{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricApacheLoad1 struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -539,6 +754,55 @@ func newMetricApacheLoad5(settings MetricSettings) metricApacheLoad5 {
 	return m
 }
 
+type metricApacheRequestAvgDuration struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills apache.request.avg_duration metric with initial data.
+func (m *metricApacheRequestAvgDuration) init() {
+	m.data.SetName("apache.request.avg_duration")
+	m.data.SetDescription("Average time spent on handling the requests completed since the previous scrape.")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+}
+
+func (m *metricApacheRequestAvgDuration) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricApacheRequestAvgDuration) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricApacheRequestAvgDuration) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricApacheRequestAvgDuration(settings MetricSettings) metricApacheRequestAvgDuration {
+	m := metricApacheRequestAvgDuration{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricApacheRequestTime struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -641,6 +905,55 @@ func newMetricApacheRequests(settings MetricSettings) metricApacheRequests {
 	return m
 }
 
+type metricApacheRequestsRate struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills apache.requests.rate metric with initial data.
+func (m *metricApacheRequestsRate) init() {
+	m.data.SetName("apache.requests.rate")
+	m.data.SetDescription("The number of requests serviced by the HTTP server per second since the previous scrape.")
+	m.data.SetUnit("{requests}/s")
+	m.data.SetEmptyGauge()
+}
+
+func (m *metricApacheRequestsRate) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricApacheRequestsRate) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricApacheRequestsRate) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricApacheRequestsRate(settings MetricSettings) metricApacheRequestsRate {
+	m := metricApacheRequestsRate{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricApacheScoreboard struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -745,6 +1058,57 @@ func newMetricApacheTraffic(settings MetricSettings) metricApacheTraffic {
 	return m
 }
 
+type metricApacheUp struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills apache.up metric with initial data.
+func (m *metricApacheUp) init() {
+	m.data.SetName("apache.up")
+	m.data.SetDescription("Whether the status of the server could be collected, 1 if it could and 0 otherwise.")
+	m.data.SetUnit("1")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricApacheUp) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, upReasonAttributeValue string) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("reason", upReasonAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricApacheUp) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricApacheUp) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricApacheUp(settings MetricSettings) metricApacheUp {
+	m := metricApacheUp{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricApacheUptime struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -860,13 +1224,19 @@ type MetricsBuilder struct {
 	metricApacheCPULoad            metricApacheCPULoad
 	metricApacheCPUTime            metricApacheCPUTime
 	metricApacheCurrentConnections metricApacheCurrentConnections
+	metricApacheHTTP2Connections   metricApacheHTTP2Connections
+	metricApacheHTTP2Pushes        metricApacheHTTP2Pushes
+	metricApacheHTTP2Streams       metricApacheHTTP2Streams
 	metricApacheLoad1              metricApacheLoad1
 	metricApacheLoad15             metricApacheLoad15
 	metricApacheLoad5              metricApacheLoad5
+	metricApacheRequestAvgDuration metricApacheRequestAvgDuration
 	metricApacheRequestTime        metricApacheRequestTime
 	metricApacheRequests           metricApacheRequests
+	metricApacheRequestsRate       metricApacheRequestsRate
 	metricApacheScoreboard         metricApacheScoreboard
 	metricApacheTraffic            metricApacheTraffic
+	metricApacheUp                 metricApacheUp
 	metricApacheUptime             metricApacheUptime
 	metricApacheWorkers            metricApacheWorkers
 }
@@ -889,13 +1259,19 @@ func NewMetricsBuilder(settings MetricsSettings, buildInfo component.BuildInfo,
 		metricApacheCPULoad:            newMetricApacheCPULoad(settings.ApacheCPULoad),
 		metricApacheCPUTime:            newMetricApacheCPUTime(settings.ApacheCPUTime),
 		metricApacheCurrentConnections: newMetricApacheCurrentConnections(settings.ApacheCurrentConnections),
+		metricApacheHTTP2Connections:   newMetricApacheHTTP2Connections(settings.ApacheHTTP2Connections),
+		metricApacheHTTP2Pushes:        newMetricApacheHTTP2Pushes(settings.ApacheHTTP2Pushes),
+		metricApacheHTTP2Streams:       newMetricApacheHTTP2Streams(settings.ApacheHTTP2Streams),
 		metricApacheLoad1:              newMetricApacheLoad1(settings.ApacheLoad1),
 		metricApacheLoad15:             newMetricApacheLoad15(settings.ApacheLoad15),
 		metricApacheLoad5:              newMetricApacheLoad5(settings.ApacheLoad5),
+		metricApacheRequestAvgDuration: newMetricApacheRequestAvgDuration(settings.ApacheRequestAvgDuration),
 		metricApacheRequestTime:        newMetricApacheRequestTime(settings.ApacheRequestTime),
 		metricApacheRequests:           newMetricApacheRequests(settings.ApacheRequests),
+		metricApacheRequestsRate:       newMetricApacheRequestsRate(settings.ApacheRequestsRate),
 		metricApacheScoreboard:         newMetricApacheScoreboard(settings.ApacheScoreboard),
 		metricApacheTraffic:            newMetricApacheTraffic(settings.ApacheTraffic),
+		metricApacheUp:                 newMetricApacheUp(settings.ApacheUp),
 		metricApacheUptime:             newMetricApacheUptime(settings.ApacheUptime),
 		metricApacheWorkers:            newMetricApacheWorkers(settings.ApacheWorkers),
 	}
@@ -967,13 +1343,19 @@ func (mb *MetricsBuilder) EmitForResource(rmo ...ResourceMetricsOption) {
 	mb.metricApacheCPULoad.emit(ils.Metrics())
 	mb.metricApacheCPUTime.emit(ils.Metrics())
 	mb.metricApacheCurrentConnections.emit(ils.Metrics())
+	mb.metricApacheHTTP2Connections.emit(ils.Metrics())
+	mb.metricApacheHTTP2Pushes.emit(ils.Metrics())
+	mb.metricApacheHTTP2Streams.emit(ils.Metrics())
 	mb.metricApacheLoad1.emit(ils.Metrics())
 	mb.metricApacheLoad15.emit(ils.Metrics())
 	mb.metricApacheLoad5.emit(ils.Metrics())
+	mb.metricApacheRequestAvgDuration.emit(ils.Metrics())
 	mb.metricApacheRequestTime.emit(ils.Metrics())
 	mb.metricApacheRequests.emit(ils.Metrics())
+	mb.metricApacheRequestsRate.emit(ils.Metrics())
 	mb.metricApacheScoreboard.emit(ils.Metrics())
 	mb.metricApacheTraffic.emit(ils.Metrics())
+	mb.metricApacheUp.emit(ils.Metrics())
 	mb.metricApacheUptime.emit(ils.Metrics())
 	mb.metricApacheWorkers.emit(ils.Metrics())
 	for _, op := range rmo {
@@ -1025,6 +1407,36 @@ func (mb *MetricsBuilder) RecordApacheCurrentConnectionsDataPoint(ts pcommon.Tim
 	return nil
 }
 
+// RecordApacheHTTP2ConnectionsDataPoint adds a data point to apache.http2.connections metric.
+func (mb *MetricsBuilder) RecordApacheHTTP2ConnectionsDataPoint(ts pcommon.Timestamp, inputVal string) error {
+	val, err := strconv.ParseInt(inputVal, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse int64 for ApacheHTTP2Connections, value was %s: %w", inputVal, err)
+	}
+	mb.metricApacheHTTP2Connections.recordDataPoint(mb.startTime, ts, val)
+	return nil
+}
+
+// RecordApacheHTTP2PushesDataPoint adds a data point to apache.http2.pushes metric.
+func (mb *MetricsBuilder) RecordApacheHTTP2PushesDataPoint(ts pcommon.Timestamp, inputVal string) error {
+	val, err := strconv.ParseInt(inputVal, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse int64 for ApacheHTTP2Pushes, value was %s: %w", inputVal, err)
+	}
+	mb.metricApacheHTTP2Pushes.recordDataPoint(mb.startTime, ts, val)
+	return nil
+}
+
+// RecordApacheHTTP2StreamsDataPoint adds a data point to apache.http2.streams metric.
+func (mb *MetricsBuilder) RecordApacheHTTP2StreamsDataPoint(ts pcommon.Timestamp, inputVal string) error {
+	val, err := strconv.ParseInt(inputVal, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse int64 for ApacheHTTP2Streams, value was %s: %w", inputVal, err)
+	}
+	mb.metricApacheHTTP2Streams.recordDataPoint(mb.startTime, ts, val)
+	return nil
+}
+
 // RecordApacheLoad1DataPoint adds a data point to apache.load.1 metric.
 func (mb *MetricsBuilder) RecordApacheLoad1DataPoint(ts pcommon.Timestamp, inputVal string) error {
 	val, err := strconv.ParseFloat(inputVal, 64)
@@ -1055,6 +1467,11 @@ func (mb *MetricsBuilder) RecordApacheLoad5DataPoint(ts pcommon.Timestamp, input
 	return nil
 }
 
+// RecordApacheRequestAvgDurationDataPoint adds a data point to apache.request.avg_duration metric.
+func (mb *MetricsBuilder) RecordApacheRequestAvgDurationDataPoint(ts pcommon.Timestamp, val float64) {
+	mb.metricApacheRequestAvgDuration.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordApacheRequestTimeDataPoint adds a data point to apache.request.time metric.
 func (mb *MetricsBuilder) RecordApacheRequestTimeDataPoint(ts pcommon.Timestamp, inputVal string) error {
 	val, err := strconv.ParseInt(inputVal, 10, 64)
@@ -1075,6 +1492,11 @@ func (mb *MetricsBuilder) RecordApacheRequestsDataPoint(ts pcommon.Timestamp, in
 	return nil
 }
 
+// RecordApacheRequestsRateDataPoint adds a data point to apache.requests.rate metric.
+func (mb *MetricsBuilder) RecordApacheRequestsRateDataPoint(ts pcommon.Timestamp, val float64) {
+	mb.metricApacheRequestsRate.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordApacheScoreboardDataPoint adds a data point to apache.scoreboard metric.
 func (mb *MetricsBuilder) RecordApacheScoreboardDataPoint(ts pcommon.Timestamp, val int64, scoreboardStateAttributeValue AttributeScoreboardState) {
 	mb.metricApacheScoreboard.recordDataPoint(mb.startTime, ts, val, scoreboardStateAttributeValue.String())
@@ -1085,6 +1507,11 @@ func (mb *MetricsBuilder) RecordApacheTrafficDataPoint(ts pcommon.Timestamp, val
 	mb.metricApacheTraffic.recordDataPoint(mb.startTime, ts, val)
 }
 
+// RecordApacheUpDataPoint adds a data point to apache.up metric.
+func (mb *MetricsBuilder) RecordApacheUpDataPoint(ts pcommon.Timestamp, val int64, upReasonAttributeValue AttributeUpReason) {
+	mb.metricApacheUp.recordDataPoint(mb.startTime, ts, val, upReasonAttributeValue.String())
+}
+
 // RecordApacheUptimeDataPoint adds a data point to apache.uptime metric.
 func (mb *MetricsBuilder) RecordApacheUptimeDataPoint(ts pcommon.Timestamp, inputVal string) error {
 	val, err := strconv.ParseInt(inputVal, 10, 64)
@@ -0,0 +1,89 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apachereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/apachereceiver"
+
+import (
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// staleIntervals is the number of collection intervals after which the counters of the
+// previous scrape are too old to derive meaningful rates from.
+const staleIntervals = 5
+
+// requestCounters holds the cumulative request counters reported by the server at a given time.
+type requestCounters struct {
+	time        time.Time
+	accesses    int64
+	duration    int64
+	hasDuration bool
+}
+
+// recordDerivedMetrics records the request rate and the average request duration
+// since the previous scrape, for backends that can't compute them from the
+// cumulative counters. Nothing is recorded on the first scrape, after a counter
+// reset (e.g. a server restart) or when the previous scrape is stale. The counters
+// missing from the server status, or invalid, are skipped: the invalid ones are
+// already reported when recording the cumulative metrics.
+func (r *apacheScraper) recordDerivedMetrics(now time.Time, stats map[string]string) {
+	if !r.cfg.Metrics.ApacheRequestsRate.Enabled && !r.cfg.Metrics.ApacheRequestAvgDuration.Enabled {
+		return
+	}
+
+	accesses, err := strconv.ParseInt(stats["Total Accesses"], 10, 64)
+	if err != nil {
+		r.previous = nil
+		return
+	}
+	current := &requestCounters{time: now, accesses: accesses}
+	if duration, err := strconv.ParseInt(stats["Total Duration"], 10, 64); err == nil {
+		current.duration, current.hasDuration = duration, true
+	}
+
+	previous := r.previous
+	r.previous = current
+	if previous == nil {
+		return
+	}
+	elapsed := current.time.Sub(previous.time)
+	if elapsed <= 0 || (r.cfg.CollectionInterval > 0 && elapsed > staleIntervals*r.cfg.CollectionInterval) {
+		return
+	}
+	if current.accesses < previous.accesses {
+		// the counters were reset, the next scrape starts over from the current values
+		return
+	}
+
+	ts := pcommon.NewTimestampFromTime(now)
+	requests := current.accesses - previous.accesses
+	rate := float64(requests) / elapsed.Seconds()
+	if r.emitMetricsWithServerNameAsResourceAttribute {
+		r.mb.RecordApacheRequestsRateDataPoint(ts, rate)
+	} else {
+		r.mb.RecordApacheRequestsRateDataPointWithServerName(ts, rate, r.serverName)
+	}
+
+	if requests == 0 || !current.hasDuration || !previous.hasDuration || current.duration < previous.duration {
+		return
+	}
+	avgDuration := float64(current.duration-previous.duration) / float64(requests)
+	if r.emitMetricsWithServerNameAsResourceAttribute {
+		r.mb.RecordApacheRequestAvgDurationDataPoint(ts, avgDuration)
+	} else {
+		r.mb.RecordApacheRequestAvgDurationDataPointWithServerName(ts, avgDuration, r.serverName)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apachereceiver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+)
+
+func TestRecordDerivedMetrics(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics.ApacheRequestsRate.Enabled = true
+	cfg.Metrics.ApacheRequestAvgDuration.Enabled = true
+	scraper := newApacheScraper(componenttest.NewNopReceiverCreateSettings(), cfg, "localhost", "8080")
+	scraper.emitMetricsWithServerNameAsResourceAttribute = true
+
+	derived := func(now time.Time, accesses, duration string) map[string]float64 {
+		scraper.recordDerivedMetrics(now, map[string]string{"Total Accesses": accesses, "Total Duration": duration})
+
+		values := map[string]float64{}
+		metrics := scraper.mb.Emit()
+		if metrics.ResourceMetrics().Len() == 0 {
+			return values
+		}
+		ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		for i := 0; i < ms.Len(); i++ {
+			require.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+			values[ms.At(i).Name()] = ms.At(i).Gauge().DataPoints().At(0).DoubleValue()
+		}
+		return values
+	}
+
+	start := time.Now()
+	assert.Empty(t, derived(start, "100", "2000"), "nothing is derived from the first scrape")
+	assert.Equal(t, map[string]float64{
+		"apache.requests.rate":        5,
+		"apache.request.avg_duration": 20,
+	}, derived(start.Add(10*time.Second), "150", "3000"))
+	assert.Equal(t, map[string]float64{
+		"apache.requests.rate": 0,
+	}, derived(start.Add(20*time.Second), "150", "3000"))
+	assert.Empty(t, derived(start.Add(30*time.Second), "10", "200"), "nothing is derived after a counter reset")
+	assert.Empty(t, derived(start.Add(10*time.Minute), "20", "400"), "nothing is derived from a stale scrape")
+	assert.Equal(t, map[string]float64{
+		"apache.requests.rate":        1,
+		"apache.request.avg_duration": 15,
+	}, derived(start.Add(10*time.Minute+10*time.Second), "30", "550"))
+}
+
+func TestRecordDerivedMetricsMissingCounters(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics.ApacheRequestsRate.Enabled = true
+	cfg.Metrics.ApacheRequestAvgDuration.Enabled = true
+	scraper := newApacheScraper(componenttest.NewNopReceiverCreateSettings(), cfg, "localhost", "8080")
+	scraper.emitMetricsWithServerNameAsResourceAttribute = true
+
+	// the servers without ExtendedStatus don't report the counters
+	start := time.Now()
+	require.NoError(t, scraper.scrapeWithoutServerNameAttr("ServerUptimeSeconds: 410\n"))
+	require.NoError(t, scraper.scrapeWithoutServerNameAttr("ServerUptimeSeconds: 420\n"))
+	assert.Nil(t, scraper.previous)
+
+	// without duration, only the request rate is derived
+	scraper.recordDerivedMetrics(start, map[string]string{"Total Accesses": "100"})
+	scraper.mb.Emit()
+	scraper.recordDerivedMetrics(start.Add(10*time.Second), map[string]string{"Total Accesses": "200"})
+	ms := scraper.mb.Emit().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, ms.Len())
+	assert.Equal(t, "apache.requests.rate", ms.At(0).Name())
+	assert.Equal(t, float64(10), ms.At(0).Gauge().DataPoints().At(0).DoubleValue())
+}
+
+func TestRecordDerivedMetricsInvalidCounters(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Metrics.ApacheRequestsRate.Enabled = true
+	cfg.Metrics.ApacheRequestAvgDuration.Enabled = true
+	scraper := newApacheScraper(componenttest.NewNopReceiverCreateSettings(), cfg, "localhost", "8080")
+	scraper.emitMetricsWithServerNameAsResourceAttribute = true
+
+	// the invalid counters are reported once, by the cumulative metrics
+	err := scraper.scrapeWithoutServerNameAttr("Total Accesses: invalid\nTotal Duration: invalid\n")
+	var partialErr scrapererror.PartialScrapeError
+	require.True(t, errors.As(err, &partialErr))
+	assert.Equal(t, 2, partialErr.Failed)
+	assert.Nil(t, scraper.previous)
+}
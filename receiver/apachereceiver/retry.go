@@ -0,0 +1,107 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apachereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/apachereceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/apachereceiver/internal/metadata"
+)
+
+// httpStatusError is returned when the server responds to the status request with an error status code.
+type httpStatusError struct {
+	statusCode int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d", e.statusCode)
+}
+
+// readError is returned when the status can't be read from the response or from the status file.
+type readError struct {
+	err error
+}
+
+func (e *readError) Error() string {
+	return fmt.Sprintf("failed to read the status: %v", e.err)
+}
+
+func (e *readError) Unwrap() error {
+	return e.err
+}
+
+// getStatsWithRetries collects the status, retrying the failed attempts after the backoff until
+// the maximum number of attempts is reached or the scrape times out.
+func (r *apacheScraper) getStatsWithRetries(ctx context.Context) (string, error) {
+	if r.cfg.ScrapeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.cfg.ScrapeTimeout)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		stats, err := r.GetStats(ctx)
+		if err == nil || attempt >= r.cfg.Retry.MaxAttempts || !isRetryable(err) {
+			return stats, err
+		}
+		r.settings.Logger.Debug("failed to fetch Apache Httpd stats, retrying",
+			zap.Int("attempt", attempt), zap.Error(err))
+
+		timer := time.NewTimer(r.cfg.Retry.Backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return "", fmt.Errorf("%w after %d attempts, last error: %v", ctx.Err(), attempt, err)
+		}
+	}
+}
+
+// isRetryable returns whether the request may succeed when retried, which isn't the case of the
+// requests rejected by the server.
+func isRetryable(err error) bool {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode >= 500
+	}
+	return true
+}
+
+// upReason returns the reason of the failure to collect the status reported with apache.up.
+func upReason(err error) metadata.AttributeUpReason {
+	var (
+		netErr    net.Error
+		statusErr *httpStatusError
+		readErr   *readError
+	)
+	switch {
+	case err == nil:
+		return metadata.AttributeUpReasonOk
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return metadata.AttributeUpReasonTimeout
+	case errors.As(err, &statusErr):
+		return metadata.AttributeUpReasonHTTPError
+	case errors.As(err, &readErr):
+		return metadata.AttributeUpReasonReadError
+	default:
+		return metadata.AttributeUpReasonConnectionError
+	}
+}
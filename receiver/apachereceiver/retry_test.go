@@ -0,0 +1,193 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apachereceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/atomic"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/apachereceiver/internal/metadata"
+)
+
+// newFlakyServer returns a server responding with the status code to the first failures requests,
+// and with the status afterwards.
+func newFlakyServer(t *testing.T, statusCode int, failures int64, requests *atomic.Int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if requests.Inc() <= failures {
+			rw.WriteHeader(statusCode)
+			return
+		}
+		_, err := rw.Write([]byte(mockServerStatus))
+		require.NoError(t, err)
+	}))
+}
+
+func newRetryTestScraper(t *testing.T, endpoint string, configure func(*Config)) *apacheScraper {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint + "/server-status?auto"
+	cfg.Retry.Backoff = time.Millisecond
+	configure(cfg)
+	require.NoError(t, cfg.Validate())
+
+	scraper := newApacheScraper(componenttest.NewNopReceiverCreateSettings(), cfg, "localhost", "8080")
+	scraper.emitMetricsWithServerNameAsResourceAttribute = true
+	scraper.emitMetricsWithPortAsResourceAttribute = false
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+	return scraper
+}
+
+func TestScraperRetries(t *testing.T) {
+	requests := atomic.NewInt64(0)
+	server := newFlakyServer(t, http.StatusServiceUnavailable, 2, requests)
+	defer server.Close()
+
+	scraper := newRetryTestScraper(t, server.URL, func(cfg *Config) {
+		cfg.Retry.MaxAttempts = 3
+	})
+
+	md, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Greater(t, md.MetricCount(), 0)
+	assert.Equal(t, int64(3), requests.Load())
+}
+
+func TestScraperRetriesExhausted(t *testing.T) {
+	requests := atomic.NewInt64(0)
+	server := newFlakyServer(t, http.StatusServiceUnavailable, 5, requests)
+	defer server.Close()
+
+	scraper := newRetryTestScraper(t, server.URL, func(cfg *Config) {
+		cfg.Retry.MaxAttempts = 3
+	})
+
+	_, err := scraper.scrape(context.Background())
+	assert.EqualError(t, err, "unexpected status code 503")
+	assert.Equal(t, int64(3), requests.Load())
+}
+
+func TestScraperClientErrorNotRetried(t *testing.T) {
+	requests := atomic.NewInt64(0)
+	server := newFlakyServer(t, http.StatusForbidden, 5, requests)
+	defer server.Close()
+
+	scraper := newRetryTestScraper(t, server.URL, func(cfg *Config) {
+		cfg.Retry.MaxAttempts = 3
+	})
+
+	_, err := scraper.scrape(context.Background())
+	assert.EqualError(t, err, "unexpected status code 403")
+	assert.Equal(t, int64(1), requests.Load())
+}
+
+func TestScraperScrapeTimeout(t *testing.T) {
+	requests := atomic.NewInt64(0)
+	server := newFlakyServer(t, http.StatusServiceUnavailable, 100, requests)
+	defer server.Close()
+
+	scraper := newRetryTestScraper(t, server.URL, func(cfg *Config) {
+		cfg.ScrapeTimeout = 50 * time.Millisecond
+		cfg.Retry.MaxAttempts = 100
+		cfg.Retry.Backoff = 20 * time.Millisecond
+	})
+
+	_, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, requests.Load(), int64(100))
+}
+
+func TestScraperUp(t *testing.T) {
+	requests := atomic.NewInt64(0)
+	server := newFlakyServer(t, http.StatusInternalServerError, 1, requests)
+	defer server.Close()
+
+	scraper := newRetryTestScraper(t, server.URL, func(cfg *Config) {
+		cfg.Metrics.ApacheUp.Enabled = true
+	})
+
+	// the failure is reported through apache.up instead of a scrape error
+	md, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, md.MetricCount())
+	up := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "apache.up", up.Name())
+	dp := up.Gauge().DataPoints().At(0)
+	assert.Equal(t, int64(0), dp.IntValue())
+	assert.Equal(t, map[string]interface{}{"reason": "http_error"}, dp.Attributes().AsRaw())
+
+	md, err = scraper.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Greater(t, md.MetricCount(), 1)
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() != "apache.up" {
+			continue
+		}
+		dp = metrics.At(i).Gauge().DataPoints().At(0)
+		assert.Equal(t, int64(1), dp.IntValue())
+		assert.Equal(t, map[string]interface{}{"reason": "ok"}, dp.Attributes().AsRaw())
+		return
+	}
+	t.Fatal("apache.up not found")
+}
+
+func TestUpReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected metadata.AttributeUpReason
+	}{
+		{
+			name:     "ok",
+			expected: metadata.AttributeUpReasonOk,
+		},
+		{
+			name:     "scrape timeout",
+			err:      fmt.Errorf("%w after 2 attempts, last error: unexpected status code 503", context.DeadlineExceeded),
+			expected: metadata.AttributeUpReasonTimeout,
+		},
+		{
+			name:     "http error",
+			err:      &httpStatusError{statusCode: http.StatusNotFound},
+			expected: metadata.AttributeUpReasonHTTPError,
+		},
+		{
+			name:     "status file",
+			err:      &readError{err: os.ErrNotExist},
+			expected: metadata.AttributeUpReasonReadError,
+		},
+		{
+			name:     "connection refused",
+			err:      errors.New("dial tcp 127.0.0.1:8080: connect: connection refused"),
+			expected: metadata.AttributeUpReasonConnectionError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, upReason(tt.err))
+		})
+	}
+}
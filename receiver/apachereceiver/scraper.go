@@ -61,6 +61,11 @@ type apacheScraper struct {
 	serverName string
 	port       string
 
+	// previous holds the request counters of the previous scrape to derive the request rate metrics
+	previous *requestCounters
+	// http2Available is whether mod_http2 reported its status on the previous scrape
+	http2Available bool
+
 	// Feature gates regarding resource attributes
 	emitMetricsWithServerNameAsResourceAttribute bool
 	emitMetricsWithPortAsResourceAttribute       bool
@@ -98,6 +103,15 @@ func newApacheScraper(
 }
 
 func (r *apacheScraper) start(_ context.Context, host component.Host) error {
+	switch {
+	case r.cfg.StatusFile != "":
+		// the status is read from the file, no client is needed
+		return nil
+	case r.cfg.UnixSocket != "":
+		r.httpClient = newUnixSocketClient(r.cfg.UnixSocket, r.cfg.Timeout)
+		return nil
+	}
+
 	httpClient, err := r.cfg.ToClient(host, r.settings)
 	if err != nil {
 		return err
@@ -106,37 +120,60 @@ func (r *apacheScraper) start(_ context.Context, host component.Host) error {
 	return nil
 }
 
-func (r *apacheScraper) scrape(context.Context) (pmetric.Metrics, error) {
-	if r.httpClient == nil {
+func (r *apacheScraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
+	if r.httpClient == nil && r.cfg.StatusFile == "" {
 		return pmetric.Metrics{}, errors.New("failed to connect to Apache HTTPd")
 	}
 
-	stats, err := r.GetStats()
+	stats, err := r.getStatsWithRetries(ctx)
 	if err != nil {
-		r.settings.Logger.Error("failed to fetch Apache Httpd stats", zap.Error(err))
-		return pmetric.Metrics{}, err
+		if !r.cfg.Metrics.ApacheUp.Enabled {
+			r.settings.Logger.Error("failed to fetch Apache Httpd stats", zap.Error(err))
+			return pmetric.Metrics{}, err
+		}
+		// the failure is reported through apache.up instead of a scrape error
+		r.settings.Logger.Debug("failed to fetch Apache Httpd stats", zap.Error(err))
+		r.recordUp(pcommon.NewTimestampFromTime(time.Now()), 0, upReason(err))
+		return r.mb.Emit(r.resourceOptions()...), nil
 	}
 
-	emitWith := []metadata.ResourceMetricsOption{}
-
+	r.recordUp(pcommon.NewTimestampFromTime(time.Now()), 1, metadata.AttributeUpReasonOk)
 	if r.emitMetricsWithServerNameAsResourceAttribute {
 		err = r.scrapeWithoutServerNameAttr(stats)
-		emitWith = append(emitWith, metadata.WithApacheServerName(r.serverName))
 	} else {
 		err = r.scrapeWithServerNameAttr(stats)
 	}
 
+	return r.mb.Emit(r.resourceOptions()...), err
+}
+
+// resourceOptions returns the resource attributes of the metrics enabled by the feature gates.
+func (r *apacheScraper) resourceOptions() []metadata.ResourceMetricsOption {
+	emitWith := []metadata.ResourceMetricsOption{}
+	if r.emitMetricsWithServerNameAsResourceAttribute {
+		emitWith = append(emitWith, metadata.WithApacheServerName(r.serverName))
+	}
 	if r.emitMetricsWithPortAsResourceAttribute {
 		emitWith = append(emitWith, metadata.WithApacheServerPort(r.port))
 	}
+	return emitWith
+}
 
-	return r.mb.Emit(emitWith...), err
+// recordUp records whether the status could be collected, and why it couldn't.
+func (r *apacheScraper) recordUp(now pcommon.Timestamp, up int64, reason metadata.AttributeUpReason) {
+	if r.emitMetricsWithServerNameAsResourceAttribute {
+		r.mb.RecordApacheUpDataPoint(now, up, reason)
+	} else {
+		r.mb.RecordApacheUpDataPointWithServerName(now, up, r.serverName, reason)
+	}
 }
 
 func (r *apacheScraper) scrapeWithServerNameAttr(stats string) error {
 	errs := &scrapererror.ScrapeErrors{}
-	now := pcommon.NewTimestampFromTime(time.Now())
-	for metricKey, metricValue := range parseStats(stats) {
+	scrapeTime := time.Now()
+	now := pcommon.NewTimestampFromTime(scrapeTime)
+	parsedStats := parseStats(stats)
+	for metricKey, metricValue := range parsedStats {
 		switch metricKey {
 		case "ServerUptimeSeconds":
 			addPartialIfError(errs, r.mb.RecordApacheUptimeDataPointWithServerName(now, metricValue, r.serverName))
@@ -195,13 +232,18 @@ func (r *apacheScraper) scrapeWithServerNameAttr(stats string) error {
 		}
 	}
 
+	r.recordHTTP2Metrics(now, parsedStats, errs)
+	r.recordDerivedMetrics(scrapeTime, parsedStats)
+
 	return errs.Combine()
 }
 
 func (r *apacheScraper) scrapeWithoutServerNameAttr(stats string) error {
 	errs := &scrapererror.ScrapeErrors{}
-	now := pcommon.NewTimestampFromTime(time.Now())
-	for metricKey, metricValue := range parseStats(stats) {
+	scrapeTime := time.Now()
+	now := pcommon.NewTimestampFromTime(scrapeTime)
+	parsedStats := parseStats(stats)
+	for metricKey, metricValue := range parsedStats {
 		switch metricKey {
 		case "ServerUptimeSeconds":
 			addPartialIfError(errs, r.mb.RecordApacheUptimeDataPoint(now, metricValue))
@@ -258,6 +300,9 @@ func (r *apacheScraper) scrapeWithoutServerNameAttr(stats string) error {
 		}
 	}
 
+	r.recordHTTP2Metrics(now, parsedStats, errs)
+	r.recordDerivedMetrics(scrapeTime, parsedStats)
+
 	return errs.Combine()
 }
 
@@ -267,18 +312,34 @@ func addPartialIfError(errs *scrapererror.ScrapeErrors, err error) {
 	}
 }
 
-// GetStats collects metric stats by making a get request at an endpoint.
-func (r *apacheScraper) GetStats() (string, error) {
-	resp, err := r.httpClient.Get(r.cfg.Endpoint)
+// GetStats collects metric stats by making a get request at an endpoint, or by reading the status file.
+func (r *apacheScraper) GetStats(ctx context.Context) (string, error) {
+	if r.cfg.StatusFile != "" {
+		stats, err := readStatusFile(r.cfg.StatusFile)
+		if err != nil {
+			return "", &readError{err: err}
+		}
+		return stats, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.cfg.Endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := r.httpClient.Do(req)
 	if err != nil {
 		return "", err
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", &httpStatusError{statusCode: resp.StatusCode}
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return "", &readError{err: err}
 	}
 	return string(body), nil
 }
@@ -179,11 +179,8 @@ func TestScraperError(t *testing.T) {
 	})
 }
 
-func newMockServer(t *testing.T) *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		if req.URL.String() == "/server-status?auto" {
-			rw.WriteHeader(200)
-			_, err := rw.Write([]byte(`ServerUptimeSeconds: 410
+// mockServerStatus is the status reported by the mock server.
+const mockServerStatus = `ServerUptimeSeconds: 410
 Total Accesses: 14169
 Total kBytes: 20910
 BusyWorkers: 13
@@ -199,10 +196,20 @@ Load5: 0.4
 Load15: 0.3
 Total Duration: 1501
 Scoreboard: S_DD_L_GGG_____W__IIII_C________________W__________________________________.........................____WR______W____W________________________C______________________________________W_W____W______________R_________R________C_________WK_W________K_____W__C__________W___R______.............................................................................................................................
-`))
+`
+
+func newMockServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(newMockHandler(t))
+}
+
+func newMockHandler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.String() == "/server-status?auto" {
+			rw.WriteHeader(200)
+			_, err := rw.Write([]byte(mockServerStatus))
 			require.NoError(t, err)
 			return
 		}
 		rw.WriteHeader(404)
-	}))
+	})
 }
@@ -0,0 +1,88 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apachereceiver
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+// scrapeRequests scrapes the status with the config and returns the number of requests reported.
+func scrapeRequests(t *testing.T, cfg *Config) (int64, error) {
+	require.NoError(t, cfg.Validate())
+	serverName, port, err := parseResourseAttributes(cfg.Endpoint)
+	require.NoError(t, err)
+	scraper := newApacheScraper(componenttest.NewNopReceiverCreateSettings(), cfg, serverName, port)
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	md, err := scraper.scrape(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() == "apache.requests" {
+			return metrics.At(i).Sum().DataPoints().At(0).IntValue(), nil
+		}
+	}
+	require.Fail(t, "apache.requests not found")
+	return 0, nil
+}
+
+func TestScraperUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "apache.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	apacheMock := httptest.NewUnstartedServer(newMockHandler(t))
+	apacheMock.Listener = listener
+	apacheMock.Start()
+	defer apacheMock.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "http://localhost/server-status?auto"
+	cfg.UnixSocket = socket
+
+	requests, err := scrapeRequests(t, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(14169), requests)
+
+	cfg.UnixSocket = filepath.Join(t.TempDir(), "missing.sock")
+	_, err = scrapeRequests(t, cfg)
+	assert.Error(t, err)
+}
+
+func TestScraperStatusFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "server-status")
+	require.NoError(t, os.WriteFile(file, []byte(mockServerStatus), 0600))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.StatusFile = file
+
+	requests, err := scrapeRequests(t, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(14169), requests)
+
+	cfg.StatusFile = filepath.Join(t.TempDir(), "missing")
+	_, err = scrapeRequests(t, cfg)
+	assert.Error(t, err)
+}
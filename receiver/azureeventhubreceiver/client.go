@@ -15,13 +15,17 @@
 package azureeventhubreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/azureeventhubreceiver"
 import (
 	"context"
+	"time"
 
+	"github.com/Azure/azure-amqp-common-go/v3/conn"
 	eventhub "github.com/Azure/azure-event-hubs-go/v3"
+	"github.com/Azure/azure-event-hubs-go/v3/persist"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/adapter"
@@ -30,13 +34,42 @@ import (
 type client struct {
 	logger   *zap.Logger
 	consumer consumer.Logs
-	config   *Config
-	obsrecv  *obsreport.Receiver
-	hub      hubWrapper
+	// tracesConsumer, when the receiver is part of a traces pipeline, receives a span per event.
+	tracesConsumer consumer.Traces
+	config         *Config
+	obsrecv        *obsreport.Receiver
+	newHub         func(connection string, persister persist.CheckpointPersister) (hubWrapper, error)
+	host           *processorHost
+}
+
+// processorHost hosts the receivers of all the event hubs of the client. The hubs share the
+// checkpoint persister, and are started and closed together.
+type processorHost struct {
+	client    *client
+	persister persist.CheckpointPersister
+	hubs      []*hubReceiver
+	monitor   *partitionMonitor
+	// decoder decodes and delivers the events when decoding workers are configured.
+	decoder *decoder
+	// now returns the current time, it is replaced by the tests.
+	now func() time.Time
+}
+
+// hubReceiver receives the events of one of the event hubs of the client.
+type hubReceiver struct {
+	client *client
+	host   *processorHost
+	config HubConfig
+	hub    hubWrapper
+	// name is the name of the event hub, as reported in the partition metrics.
+	name string
+	// partitions holds the status of the partitions received from.
+	partitions []*partitionStatus
 }
 
 type hubWrapper interface {
 	GetRuntimeInformation(ctx context.Context) (*eventhub.HubRuntimeInformation, error)
+	GetPartitionInformation(ctx context.Context, partitionID string) (*eventhub.HubPartitionRuntimeInformation, error)
 	Receive(ctx context.Context, partitionID string, handler eventhub.Handler, opts ...eventhub.ReceiveOption) (listerHandleWrapper, error)
 	Close(ctx context.Context) error
 }
@@ -54,6 +87,10 @@ func (h *hubWrapperImpl) GetRuntimeInformation(ctx context.Context) (*eventhub.H
 	return h.hub.GetRuntimeInformation(ctx)
 }
 
+func (h *hubWrapperImpl) GetPartitionInformation(ctx context.Context, partitionID string) (*eventhub.HubPartitionRuntimeInformation, error) {
+	return h.hub.GetPartitionInformation(ctx, partitionID)
+}
+
 func (h *hubWrapperImpl) Receive(ctx context.Context, partitionID string, handler eventhub.Handler, opts ...eventhub.ReceiveOption) (listerHandleWrapper, error) {
 	l, err := h.hub.Receive(ctx, partitionID, handler, opts...)
 	return l, err
@@ -63,83 +100,175 @@ func (h *hubWrapperImpl) Close(ctx context.Context) error {
 	return h.hub.Close(ctx)
 }
 
+func newHubWrapper(connection string, persister persist.CheckpointPersister) (hubWrapper, error) {
+	hub, err := eventhub.NewHubFromConnectionString(connection, eventhub.HubWithOffsetPersistence(persister))
+	if err != nil {
+		return nil, err
+	}
+	return &hubWrapperImpl{
+		hub: hub,
+	}, nil
+}
+
 func (c *client) Start(ctx context.Context, host component.Host) error {
 	storageClient, err := adapter.GetStorageClient(ctx, host, c.config.StorageID, c.config.ID())
 	if err != nil {
 		return err
 	}
-	if c.hub == nil { // set manually for testing.
-		hub, newHubErr := eventhub.NewHubFromConnectionString(c.config.Connection, eventhub.HubWithOffsetPersistence(&storageCheckpointPersister{storageClient: storageClient}))
-		if newHubErr != nil {
-			return newHubErr
+	hubConfigs, err := c.config.hubConfigs()
+	if err != nil {
+		return err
+	}
+	// The hubs share the storage client, the checkpoints are keyed by namespace and hub name.
+	c.host = &processorHost{
+		client:    c,
+		persister: &storageCheckpointPersister{storageClient: storageClient},
+		now:       time.Now,
+	}
+	return c.host.start(ctx, hubConfigs)
+}
+
+// start starts receiving from every event hub, and the monitoring of their partitions.
+// If one of them fails to start, the ones already started are closed before returning
+// the error.
+func (p *processorHost) start(ctx context.Context, hubConfigs []HubConfig) error {
+	p.decoder = newDecoder(p.client.config.Decode, p.client.logger)
+	for _, hubConfig := range hubConfigs {
+		parsed, err := conn.ParsedConnectionFromStr(hubConfig.Connection)
+		if err != nil {
+			return multierr.Append(err, p.close(ctx))
 		}
-		c.hub = &hubWrapperImpl{
-			hub: hub,
+		hub, err := p.client.newHub(hubConfig.Connection, p.persister)
+		if err != nil {
+			return multierr.Append(err, p.close(ctx))
+		}
+		h := &hubReceiver{client: p.client, host: p, config: hubConfig, hub: hub, name: parsed.HubName}
+		p.hubs = append(p.hubs, h)
+		if err = h.start(ctx); err != nil {
+			return multierr.Append(err, p.close(ctx))
 		}
 	}
+	if p.client.config.PartitionMonitoring.Interval > 0 {
+		p.monitor = &partitionMonitor{
+			host:   p,
+			config: p.client.config.PartitionMonitoring,
+			stopCh: make(chan struct{}),
+		}
+		p.monitor.start()
+	}
+	return nil
+}
+
+// close stops the monitoring and closes every event hub of the host.
+func (p *processorHost) close(ctx context.Context) error {
+	if p.monitor != nil {
+		p.monitor.stop()
+		p.monitor = nil
+	}
+	var errs error
+	for _, h := range p.hubs {
+		errs = multierr.Append(errs, h.hub.Close(ctx))
+	}
+	p.hubs = nil
+	// the hubs are closed first so that no event is queued while the decoder stops
+	if p.decoder != nil {
+		p.decoder.stop()
+		p.decoder = nil
+	}
+	return errs
+}
 
-	if c.config.Partition == "" {
+func (h *hubReceiver) start(ctx context.Context) error {
+	if h.config.Partition == "" {
 		// listen to each partition of the Event Hub
-		var runtimeInfo *eventhub.HubRuntimeInformation
-		runtimeInfo, err = c.hub.GetRuntimeInformation(ctx)
+		runtimeInfo, err := h.hub.GetRuntimeInformation(ctx)
 		if err != nil {
 			return err
 		}
 
 		for _, partitionID := range runtimeInfo.PartitionIDs {
-			err = c.setUpOnePartition(ctx, partitionID, false)
+			err = h.setUpOnePartition(ctx, partitionID, false)
 			if err != nil {
 				return err
 			}
 		}
-	} else {
-		err = c.setUpOnePartition(ctx, c.config.Partition, true)
-		if err != nil {
-			return err
-		}
+		return nil
 	}
-	return nil
+	return h.setUpOnePartition(ctx, h.config.Partition, true)
 }
 
-func (c *client) setUpOnePartition(ctx context.Context, partitionID string, applyOffset bool) error {
+func (h *hubReceiver) setUpOnePartition(ctx context.Context, partitionID string, applyOffset bool) error {
 	offsetOption := eventhub.ReceiveWithLatestOffset()
-	if applyOffset && c.config.Offset != "" {
-		offsetOption = eventhub.ReceiveWithStartingOffset(c.config.Offset)
+	if applyOffset && h.config.Offset != "" {
+		offsetOption = eventhub.ReceiveWithStartingOffset(h.config.Offset)
 	}
 
-	handle, err := c.hub.Receive(ctx, partitionID, c.handle, offsetOption)
+	status := newPartitionStatus(partitionID, h.host.now())
+	handler := func(ctx context.Context, event *eventhub.Event) error {
+		status.record(event, h.host.now())
+		if h.host.decoder != nil {
+			return h.host.decoder.enqueue(ctx, h, partitionID, event)
+		}
+		return h.handle(ctx, partitionID, event)
+	}
+	handle, err := h.hub.Receive(ctx, partitionID, handler, offsetOption)
 	if err != nil {
 		return err
 	}
+	h.partitions = append(h.partitions, status)
 	go func() {
 		<-handle.Done()
 		err := handle.Err()
 		if err != nil {
-			c.logger.Error("Error reported by event hub", zap.Error(err))
+			h.client.logger.Error("Error reported by event hub", zap.Error(err))
 		}
 	}()
 
 	return nil
 }
 
-func (c *client) handle(ctx context.Context, event *eventhub.Event) error {
-	c.obsrecv.StartLogsOp(ctx)
+func (h *hubReceiver) handle(ctx context.Context, partitionID string, event *eventhub.Event) error {
+	if h.client.tracesConsumer != nil {
+		h.handleTraces(ctx, partitionID, event)
+	}
+	if h.client.consumer == nil {
+		return nil
+	}
+
+	h.client.obsrecv.StartLogsOp(ctx)
 	l := plog.NewLogs()
-	lr := l.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	rl := l.ResourceLogs().AppendEmpty()
+	for k, v := range h.config.ResourceAttributes {
+		rl.Resource().Attributes().PutStr(k, v)
+	}
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
 	slice := lr.Body().SetEmptyBytes()
 	slice.Append(event.Data...)
 	lr.Attributes().FromRaw(event.Properties)
 	if event.SystemProperties.EnqueuedTime != nil {
 		lr.SetTimestamp(pcommon.NewTimestampFromTime(*event.SystemProperties.EnqueuedTime))
 	}
-	consumerErr := c.consumer.ConsumeLogs(ctx, l)
-	c.obsrecv.EndLogsOp(ctx, "azureeventhub", 1, consumerErr)
+	consumerErr := h.client.consumer.ConsumeLogs(ctx, l)
+	h.client.obsrecv.EndLogsOp(ctx, "azureeventhub", 1, consumerErr)
 	return consumerErr
 }
 
+// handleTraces emits the span of the event. The failures are logged without failing the
+// reception of the event.
+func (h *hubReceiver) handleTraces(ctx context.Context, partitionID string, event *eventhub.Event) {
+	td := newEventTraces(h.name, partitionID, h.config.ResourceAttributes, event, h.host.now())
+	ctx = h.client.obsrecv.StartTracesOp(ctx)
+	err := h.client.tracesConsumer.ConsumeTraces(ctx, td)
+	h.client.obsrecv.EndTracesOp(ctx, "azureeventhub", 1, err)
+	if err != nil {
+		h.client.logger.Warn("Failed to consume the span of an event",
+			zap.String("hub", h.name), zap.String("partition", partitionID), zap.Error(err))
+	}
+}
+
 func (c *client) Shutdown(ctx context.Context) error {
-	if c.hub == nil {
+	if c.host == nil {
 		return nil
 	}
-	return c.hub.Close(ctx)
+	return c.host.close(ctx)
 }
@@ -16,15 +16,18 @@ package azureeventhubreceiver
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	eventhub "github.com/Azure/azure-event-hubs-go/v3"
+	"github.com/Azure/azure-event-hubs-go/v3/persist"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 )
 
@@ -40,6 +43,10 @@ func (m mockHubWrapper) GetRuntimeInformation(ctx context.Context) (*eventhub.Hu
 	}, nil
 }
 
+func (m mockHubWrapper) GetPartitionInformation(ctx context.Context, partitionID string) (*eventhub.HubPartitionRuntimeInformation, error) {
+	return &eventhub.HubPartitionRuntimeInformation{PartitionID: partitionID}, nil
+}
+
 func (m mockHubWrapper) Receive(ctx context.Context, partitionID string, handler eventhub.Handler, opts ...eventhub.ReceiveOption) (listerHandleWrapper, error) {
 	return &mockListenerHandleWrapper{
 		ctx: context.Background(),
@@ -50,10 +57,33 @@ func (m mockHubWrapper) Close(_ context.Context) error {
 	return nil
 }
 
+// closeCountingHubWrapper is a mock hub counting its closes, failing to receive when receiveErr is set.
+type closeCountingHubWrapper struct {
+	mockHubWrapper
+	receiveErr error
+	closed     int
+}
+
+func (m *closeCountingHubWrapper) Receive(ctx context.Context, partitionID string, handler eventhub.Handler, opts ...eventhub.ReceiveOption) (listerHandleWrapper, error) {
+	if m.receiveErr != nil {
+		return nil, m.receiveErr
+	}
+	return m.mockHubWrapper.Receive(ctx, partitionID, handler, opts...)
+}
+
+func (m *closeCountingHubWrapper) Close(_ context.Context) error {
+	m.closed++
+	return nil
+}
+
 type mockListenerHandleWrapper struct {
 	ctx context.Context
 }
 
+func newMockHub(connection string, persister persist.CheckpointPersister) (hubWrapper, error) {
+	return &mockHubWrapper{}, nil
+}
+
 func (m *mockListenerHandleWrapper) Done() <-chan struct{} {
 	return m.ctx.Done()
 }
@@ -70,8 +100,8 @@ func TestClient_Start(t *testing.T) {
 		logger:   zap.NewNop(),
 		consumer: consumertest.NewNop(),
 		config:   config.(*Config),
+		newHub:   newMockHub,
 	}
-	c.hub = &mockHubWrapper{}
 	err := c.Start(context.Background(), componenttest.NewNopHost())
 	assert.NoError(t, err)
 	err = c.Shutdown(context.Background())
@@ -95,12 +125,13 @@ func TestClient_handle(t *testing.T) {
 		consumer: sink,
 		config:   config.(*Config),
 		obsrecv:  obsrecv,
+		newHub:   newMockHub,
 	}
-	c.hub = &mockHubWrapper{}
 	err = c.Start(context.Background(), componenttest.NewNopHost())
 	assert.NoError(t, err)
+	require.Len(t, c.host.hubs, 1)
 	now := time.Now()
-	err = c.handle(context.Background(), &eventhub.Event{
+	err = c.host.hubs[0].handle(context.Background(), "0", &eventhub.Event{
 		Data:         []byte("hello"),
 		PartitionKey: nil,
 		Properties:   map[string]interface{}{"foo": "bar"},
@@ -122,3 +153,211 @@ func TestClient_handle(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "bar", read.AsString())
 }
+
+func TestClient_multipleHubs(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Connection = "Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName"
+	config.Hubs = []HubConfig{
+		{
+			EntityPath:         "westeurope",
+			ResourceAttributes: map[string]string{"cloud.region": "westeurope"},
+		},
+		{
+			EntityPath:         "eastus",
+			ResourceAttributes: map[string]string{"cloud.region": "eastus"},
+		},
+	}
+
+	sink := new(consumertest.LogsSink)
+	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
+		ReceiverID:             config.ID(),
+		ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings(),
+	})
+	require.NoError(t, err)
+	var connections []string
+	c := &client{
+		logger:   zap.NewNop(),
+		consumer: sink,
+		config:   config,
+		obsrecv:  obsrecv,
+		newHub: func(connection string, persister persist.CheckpointPersister) (hubWrapper, error) {
+			connections = append(connections, connection)
+			return &mockHubWrapper{}, nil
+		},
+	}
+	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, []string{
+		"Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName",
+		"Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=westeurope",
+		"Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=eastus",
+	}, connections)
+	require.Len(t, c.host.hubs, 3)
+
+	event := &eventhub.Event{
+		Data:             []byte("hello"),
+		SystemProperties: &eventhub.SystemProperties{},
+	}
+	require.NoError(t, c.host.hubs[0].handle(context.Background(), "0", event))
+	require.NoError(t, c.host.hubs[2].handle(context.Background(), "0", event))
+	require.Len(t, sink.AllLogs(), 2)
+	assert.Equal(t, 0, sink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().Len())
+	region, ok := sink.AllLogs()[1].ResourceLogs().At(0).Resource().Attributes().Get("cloud.region")
+	assert.True(t, ok)
+	assert.Equal(t, "eastus", region.Str())
+
+	assert.NoError(t, c.Shutdown(context.Background()))
+	assert.Empty(t, c.host.hubs)
+}
+
+func TestClient_StartClosesHubsOnFailure(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Connection = "Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName"
+	config.Hubs = []HubConfig{
+		{EntityPath: "westeurope"},
+		{EntityPath: "eastus"},
+	}
+
+	tests := []struct {
+		name    string
+		newHub  func(hubs []*closeCountingHubWrapper) (hubWrapper, error)
+		wantErr string
+		// wantHubs is the number of hubs created, all of them are closed
+		wantHubs int
+	}{
+		{
+			name: "new hub failure",
+			newHub: func(hubs []*closeCountingHubWrapper) (hubWrapper, error) {
+				if len(hubs) == 2 {
+					return nil, errors.New("invalid hub")
+				}
+				return &closeCountingHubWrapper{}, nil
+			},
+			wantErr:  "invalid hub",
+			wantHubs: 2,
+		},
+		{
+			name: "receive failure",
+			newHub: func(hubs []*closeCountingHubWrapper) (hubWrapper, error) {
+				if len(hubs) == 1 {
+					return &closeCountingHubWrapper{receiveErr: errors.New("unauthorized")}, nil
+				}
+				return &closeCountingHubWrapper{}, nil
+			},
+			wantErr:  "unauthorized",
+			wantHubs: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hubs []*closeCountingHubWrapper
+			c := &client{
+				logger:   zap.NewNop(),
+				consumer: consumertest.NewNop(),
+				config:   config,
+				newHub: func(connection string, persister persist.CheckpointPersister) (hubWrapper, error) {
+					hub, err := tt.newHub(hubs)
+					if err != nil {
+						return nil, err
+					}
+					hubs = append(hubs, hub.(*closeCountingHubWrapper))
+					return hub, nil
+				},
+			}
+			assert.EqualError(t, c.Start(context.Background(), componenttest.NewNopHost()), tt.wantErr)
+			require.Len(t, hubs, tt.wantHubs)
+			for _, hub := range hubs {
+				assert.Equal(t, 1, hub.closed)
+			}
+			assert.Empty(t, c.host.hubs)
+			assert.NoError(t, c.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestClient_handleTraces(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Connection = "Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName"
+	config.Hubs = []HubConfig{{EntityPath: "eastus", ResourceAttributes: map[string]string{"cloud.region": "eastus"}}}
+
+	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
+		ReceiverID:             config.ID(),
+		ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings(),
+	})
+	require.NoError(t, err)
+	traces := new(consumertest.TracesSink)
+	c := &client{
+		logger:         zap.NewNop(),
+		tracesConsumer: traces,
+		config:         config,
+		obsrecv:        obsrecv,
+		newHub:         newMockHub,
+	}
+	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, c.Shutdown(context.Background())) }()
+	now := time.Now()
+	c.host.now = func() time.Time { return now }
+
+	enqueued := now.Add(-2 * time.Second)
+	sequenceNumber := int64(42)
+	offset := int64(1024)
+	require.NoError(t, c.host.hubs[1].handle(context.Background(), "3", &eventhub.Event{
+		Data:       []byte("hello"),
+		ID:         "11234",
+		Properties: map[string]interface{}{"Diagnostic-Id": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+		SystemProperties: &eventhub.SystemProperties{
+			SequenceNumber: &sequenceNumber,
+			EnqueuedTime:   &enqueued,
+			Offset:         &offset,
+		},
+	}))
+	// the events without trace context start a new trace
+	require.NoError(t, c.host.hubs[0].handle(context.Background(), "0", &eventhub.Event{Data: []byte("hello")}))
+
+	require.Len(t, traces.AllTraces(), 2)
+	rs := traces.AllTraces()[0].ResourceSpans().At(0)
+	assert.Equal(t, map[string]interface{}{"cloud.region": "eastus"}, rs.Resource().Attributes().AsRaw())
+	assert.Equal(t, scopeName, rs.ScopeSpans().At(0).Scope().Name())
+	span := rs.ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, "eastus process", span.Name())
+	assert.Equal(t, ptrace.SpanKindConsumer, span.Kind())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span.TraceID().HexString())
+	assert.Equal(t, "b7ad6b7169203331", span.ParentSpanID().HexString())
+	assert.False(t, span.SpanID().IsEmpty())
+	assert.Equal(t, 2*time.Second, span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime()))
+	assert.Equal(t, map[string]interface{}{
+		"messaging.system":                    "eventhubs",
+		"messaging.destination":               "eastus",
+		"messaging.destination_kind":          "topic",
+		"messaging.operation":                 "process",
+		"messaging.message_id":                "11234",
+		"messaging.eventhubs.partition_id":    "3",
+		"messaging.eventhubs.sequence_number": int64(42),
+		"messaging.eventhubs.offset":          int64(1024),
+	}, span.Attributes().AsRaw())
+
+	span = traces.AllTraces()[1].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	assert.Equal(t, "hubName process", span.Name())
+	assert.False(t, span.TraceID().IsEmpty())
+	assert.True(t, span.ParentSpanID().IsEmpty())
+	assert.Equal(t, span.StartTimestamp(), span.EndTimestamp())
+}
+
+func TestParseTraceParent(t *testing.T) {
+	traceID, spanID, ok := parseTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	require.True(t, ok)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", traceID.HexString())
+	assert.Equal(t, "b7ad6b7169203331", spanID.HexString())
+
+	for _, value := range []string{
+		"",
+		"|0af7651916cd43dd8448eb211c80319c.b7ad6b7169203331.",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c00-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b71692033-01",
+		"00-zzf7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+	} {
+		_, _, ok = parseTraceParent(value)
+		assert.False(t, ok, value)
+	}
+}
@@ -15,6 +15,9 @@
 package azureeventhubreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/azureeventhubreceiver"
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-amqp-common-go/v3/conn"
 	"go.opentelemetry.io/collector/component"
@@ -25,21 +28,111 @@ var (
 	errMissingConnection = errors.New("missing connection")
 )
 
+const entityPathKey = "EntityPath="
+
 type Config struct {
 	config.ReceiverSettings `mapstructure:",squash"`
 	Connection              string        `mapstructure:"connection"`
 	Partition               string        `mapstructure:"partition"`
 	Offset                  string        `mapstructure:"offset"`
 	StorageID               *component.ID `mapstructure:"storage"`
+	// Hubs lists additional event hubs to consume from. They share the storage
+	// used for checkpointing and default to the connection of the receiver.
+	Hubs []HubConfig `mapstructure:"hubs"`
+	// PartitionMonitoring configures the monitoring of the partitions received from.
+	PartitionMonitoring PartitionMonitoringConfig `mapstructure:"partition_monitoring"`
+	// Decode configures the workers decoding the received events and delivering them to the pipeline.
+	Decode DecodeConfig `mapstructure:"decode"`
+}
+
+// PartitionMonitoringConfig defines how the received partitions are compared to the
+// latest events of their event hub to detect the partitions that stopped receiving.
+type PartitionMonitoringConfig struct {
+	// Interval is how often the partitions are checked, 0 disables the monitoring.
+	Interval time.Duration `mapstructure:"interval"`
+	// StallThreshold is how long a partition can go without receiving an event while
+	// newer events are enqueued before it is reported as stalled.
+	StallThreshold time.Duration `mapstructure:"stall_threshold"`
+}
+
+// HubConfig defines one of the event hubs a receiver consumes from.
+type HubConfig struct {
+	Connection string `mapstructure:"connection"`
+	// EntityPath is the name of the event hub, it overrides the one of the connection.
+	EntityPath string `mapstructure:"entity_path"`
+	Partition  string `mapstructure:"partition"`
+	Offset     string `mapstructure:"offset"`
+	// ResourceAttributes are set on the resource of the logs received from the event hub.
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
 }
 
 // Validate config
 func (config *Config) Validate() error {
-	if config.Connection == "" {
+	if config.Connection == "" && len(config.Hubs) == 0 {
 		return errMissingConnection
 	}
-	if _, err := conn.ParsedConnectionFromStr(config.Connection); err != nil {
+	hubs, err := config.hubConfigs()
+	if err != nil {
 		return err
 	}
-	return nil
+	// hubs starts with the receiver connection, if any, followed by the ones of Hubs.
+	offset := len(hubs) - len(config.Hubs)
+	seen := map[string]bool{}
+	for i, hub := range hubs {
+		parsed, err := conn.ParsedConnectionFromStr(hub.Connection)
+		if err != nil {
+			return err
+		}
+		key := parsed.Namespace + "/" + parsed.HubName
+		if seen[key] {
+			return fmt.Errorf("hubs[%d]: event hub %q is configured more than once", i-offset, key)
+		}
+		seen[key] = true
+	}
+	if config.PartitionMonitoring.Interval < 0 {
+		return errors.New("partition_monitoring.interval must not be negative")
+	}
+	if config.PartitionMonitoring.Interval > 0 && config.PartitionMonitoring.StallThreshold <= 0 {
+		return errors.New("partition_monitoring.stall_threshold must be positive")
+	}
+	return config.Decode.validate()
+}
+
+// hubConfigs returns the configuration of every event hub to consume from,
+// starting with the one of the receiver connection when set. The returned
+// connections hold the entity path of their hub.
+func (config *Config) hubConfigs() ([]HubConfig, error) {
+	var hubs []HubConfig
+	if config.Connection != "" {
+		hubs = append(hubs, HubConfig{
+			Connection: config.Connection,
+			Partition:  config.Partition,
+			Offset:     config.Offset,
+		})
+	}
+	for i, hub := range config.Hubs {
+		if hub.Connection == "" {
+			hub.Connection = config.Connection
+		}
+		if hub.Connection == "" {
+			return nil, fmt.Errorf("hubs[%d]: %w", i, errMissingConnection)
+		}
+		if hub.EntityPath != "" {
+			hub.Connection = withEntityPath(hub.Connection, hub.EntityPath)
+		}
+		hubs = append(hubs, hub)
+	}
+	return hubs, nil
+}
+
+// withEntityPath replaces the entity path of the connection string.
+func withEntityPath(connection, entityPath string) string {
+	parts := strings.Split(strings.TrimSuffix(connection, ";"), ";")
+	kept := make([]string, 0, len(parts)+1)
+	for _, part := range parts {
+		if !strings.HasPrefix(part, entityPathKey) {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(append(kept, entityPathKey+entityPath), ";")
 }
@@ -17,6 +17,7 @@ package azureeventhubreceiver
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -36,7 +37,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 2)
+	assert.Equal(t, len(cfg.Receivers), 3)
 
 	r0 := cfg.Receivers[component.NewID(typeStr)]
 	assert.Equal(t, "Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName", r0.(*Config).Connection)
@@ -47,6 +48,21 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, "Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName", r1.(*Config).Connection)
 	assert.Equal(t, "1234-5566", r1.(*Config).Offset)
 	assert.Equal(t, "foo", r1.(*Config).Partition)
+	assert.Equal(t, PartitionMonitoringConfig{Interval: 30 * time.Second, StallThreshold: 2 * time.Minute}, r1.(*Config).PartitionMonitoring)
+	assert.Equal(t, DecodeConfig{Workers: 8, Ordering: noOrdering, QueueSize: 50}, r1.(*Config).Decode)
+
+	r2 := cfg.Receivers[component.NewIDWithName(typeStr, "hubs")]
+	assert.Equal(t, []HubConfig{
+		{
+			EntityPath:         "westeurope",
+			ResourceAttributes: map[string]string{"cloud.region": "westeurope"},
+		},
+		{
+			Connection:         "Endpoint=sb://other.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=eastus",
+			Partition:          "bar",
+			ResourceAttributes: map[string]string{"cloud.region": "eastus"},
+		},
+	}, r2.(*Config).Hubs)
 }
 
 func TestMissingConnection(t *testing.T) {
@@ -63,3 +79,56 @@ func TestInvalidConnectionString(t *testing.T) {
 	err := cfg.Validate()
 	assert.EqualError(t, err, "failed parsing connection string due to unmatched key value separated by '='")
 }
+
+func TestHubsWithoutConnection(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	cfg.(*Config).Hubs = []HubConfig{{EntityPath: "hubName"}}
+	err := cfg.Validate()
+	assert.EqualError(t, err, "hubs[0]: missing connection")
+}
+
+func TestDuplicateHub(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	cfg.(*Config).Connection = "Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName"
+	cfg.(*Config).Hubs = []HubConfig{{EntityPath: "other"}, {EntityPath: "hubName"}}
+	err := cfg.Validate()
+	assert.EqualError(t, err, `hubs[1]: event hub "namespace/hubName" is configured more than once`)
+}
+
+func TestInvalidPartitionMonitoring(t *testing.T) {
+	tests := []struct {
+		name       string
+		monitoring PartitionMonitoringConfig
+		wantErr    string
+	}{
+		{
+			name:       "negative interval",
+			monitoring: PartitionMonitoringConfig{Interval: -time.Second, StallThreshold: time.Minute},
+			wantErr:    "partition_monitoring.interval must not be negative",
+		},
+		{
+			name:       "missing stall threshold",
+			monitoring: PartitionMonitoringConfig{Interval: time.Second},
+			wantErr:    "partition_monitoring.stall_threshold must be positive",
+		},
+		{
+			name:       "disabled",
+			monitoring: PartitionMonitoringConfig{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.Connection = "Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName"
+			cfg.PartitionMonitoring = tt.monitoring
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
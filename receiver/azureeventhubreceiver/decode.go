@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azureeventhubreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/azureeventhubreceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	eventhub "github.com/Azure/azure-event-hubs-go/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// partitionOrdering delivers the events of a partition in order, the partitions being spread across the workers.
+	partitionOrdering = "partition"
+	// noOrdering delivers the events with any available worker, in any order.
+	noOrdering = "none"
+
+	defaultDecodeQueueSize = 100
+)
+
+var errDecoderStopped = errors.New("the receiver is shutting down")
+
+// DecodeConfig defines how the received events are decoded and delivered to the pipeline.
+type DecodeConfig struct {
+	// Workers is the number of goroutines decoding and delivering the events of all the partitions. 0, the default,
+	// decodes and delivers each event in the goroutine receiving its partition before receiving the next one.
+	Workers int `mapstructure:"workers"`
+	// Ordering is "partition" to deliver the events of a partition in order, or "none" to deliver them with any
+	// available worker, so that a busy partition can use all the workers. It defaults to "partition".
+	Ordering string `mapstructure:"ordering"`
+	// QueueSize is the number of events queued per worker, the reception of the partitions blocks when it is full.
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+func (d *DecodeConfig) validate() error {
+	if d.Workers < 0 {
+		return errors.New("decode.workers must not be negative")
+	}
+	if d.QueueSize < 0 {
+		return errors.New("decode.queue_size must not be negative")
+	}
+	switch d.Ordering {
+	case "", partitionOrdering, noOrdering:
+	default:
+		return fmt.Errorf("decode.ordering must be %q or %q", partitionOrdering, noOrdering)
+	}
+	return nil
+}
+
+// decodeJob is a received event waiting to be decoded and delivered.
+type decodeJob struct {
+	hub         *hubReceiver
+	partitionID string
+	event       *eventhub.Event
+}
+
+// decoder decodes and delivers the events of all the event hubs of a receiver with a pool of workers.
+// With the partition ordering, each worker has its own queue and the partitions are assigned to the
+// workers by hash, so that the events of a partition are delivered in order by the same worker. Without
+// ordering, the workers share a single queue.
+type decoder struct {
+	logger  *zap.Logger
+	ordered bool
+	queues  []chan decodeJob
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newDecoder returns the decoder of the configuration, nil when the events are decoded by the goroutines
+// receiving them.
+func newDecoder(cfg DecodeConfig, logger *zap.Logger) *decoder {
+	if cfg.Workers == 0 {
+		return nil
+	}
+	queueSize := cfg.QueueSize
+	if queueSize == 0 {
+		queueSize = defaultDecodeQueueSize
+	}
+	d := &decoder{
+		logger:  logger,
+		ordered: cfg.Ordering != noOrdering,
+		stopCh:  make(chan struct{}),
+	}
+	if d.ordered {
+		for i := 0; i < cfg.Workers; i++ {
+			d.queues = append(d.queues, make(chan decodeJob, queueSize))
+		}
+	} else {
+		d.queues = []chan decodeJob{make(chan decodeJob, queueSize*cfg.Workers)}
+	}
+	d.wg.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go d.work(d.queues[i%len(d.queues)])
+	}
+	return d
+}
+
+// enqueue queues the event for decoding, it blocks while the queue is full.
+func (d *decoder) enqueue(ctx context.Context, h *hubReceiver, partitionID string, event *eventhub.Event) error {
+	select {
+	case <-d.stopCh:
+		return errDecoderStopped
+	default:
+	}
+	queue := d.queues[0]
+	if d.ordered {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(h.name + "/" + partitionID))
+		queue = d.queues[hash.Sum32()%uint32(len(d.queues))]
+	}
+	select {
+	case queue <- decodeJob{hub: h, partitionID: partitionID, event: event}:
+		return nil
+	case <-d.stopCh:
+		return errDecoderStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work decodes and delivers the events of the queue until the decoder stops, the events already
+// queued are delivered before returning.
+func (d *decoder) work(queue chan decodeJob) {
+	defer d.wg.Done()
+	for {
+		select {
+		case job := <-queue:
+			d.deliver(job)
+		case <-d.stopCh:
+			for {
+				select {
+				case job := <-queue:
+					d.deliver(job)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver decodes and delivers the event. The event is already checkpointed, the failures are logged.
+func (d *decoder) deliver(job decodeJob) {
+	if err := job.hub.handle(context.Background(), job.partitionID, job.event); err != nil {
+		d.logger.Warn("Failed to consume an event",
+			zap.String("hub", job.hub.name), zap.String("partition", job.partitionID), zap.Error(err))
+	}
+}
+
+// stop stops the workers once the events already queued are delivered.
+func (d *decoder) stop() {
+	close(d.stopCh)
+	d.wg.Wait()
+}
@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azureeventhubreceiver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	eventhub "github.com/Azure/azure-event-hubs-go/v3"
+	"github.com/Azure/azure-event-hubs-go/v3/persist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+)
+
+// handlersHubWrapper is a mock hub with 4 partitions keeping the handlers of the partitions.
+type handlersHubWrapper struct {
+	mockHubWrapper
+	mu       sync.Mutex
+	handlers map[string]eventhub.Handler
+}
+
+func (m *handlersHubWrapper) GetRuntimeInformation(context.Context) (*eventhub.HubRuntimeInformation, error) {
+	return &eventhub.HubRuntimeInformation{PartitionIDs: []string{"0", "1", "2", "3"}}, nil
+}
+
+func (m *handlersHubWrapper) Receive(ctx context.Context, partitionID string, handler eventhub.Handler, opts ...eventhub.ReceiveOption) (listerHandleWrapper, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[partitionID] = handler
+	return m.mockHubWrapper.Receive(ctx, partitionID, handler, opts...)
+}
+
+func TestValidateDecode(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Connection = "Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName"
+	config.Decode = DecodeConfig{Workers: 4, Ordering: noOrdering, QueueSize: 10}
+	assert.NoError(t, config.Validate())
+
+	config.Decode = DecodeConfig{Workers: -1}
+	assert.EqualError(t, config.Validate(), "decode.workers must not be negative")
+	config.Decode = DecodeConfig{QueueSize: -1}
+	assert.EqualError(t, config.Validate(), "decode.queue_size must not be negative")
+	config.Decode = DecodeConfig{Ordering: "strict"}
+	assert.EqualError(t, config.Validate(), `decode.ordering must be "partition" or "none"`)
+}
+
+func TestClient_decodeWorkers(t *testing.T) {
+	for _, ordering := range []string{partitionOrdering, noOrdering} {
+		t.Run(ordering, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.Connection = "Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName"
+			config.Decode = DecodeConfig{Workers: 3, Ordering: ordering, QueueSize: 2}
+
+			sink := new(consumertest.LogsSink)
+			obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
+				ReceiverID:             config.ID(),
+				ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings(),
+			})
+			require.NoError(t, err)
+			hub := &handlersHubWrapper{handlers: map[string]eventhub.Handler{}}
+			c := &client{
+				logger:   zap.NewNop(),
+				consumer: sink,
+				config:   config,
+				obsrecv:  obsrecv,
+				newHub: func(string, persist.CheckpointPersister) (hubWrapper, error) {
+					return hub, nil
+				},
+			}
+			require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+			require.Len(t, hub.handlers, 4)
+
+			// the partitions are received concurrently, each one in order
+			const events = 50
+			var wg sync.WaitGroup
+			for partitionID, handler := range hub.handlers {
+				wg.Add(1)
+				go func(partitionID string, handler eventhub.Handler) {
+					defer wg.Done()
+					for i := 0; i < events; i++ {
+						assert.NoError(t, handler(context.Background(), &eventhub.Event{
+							Data:             []byte(fmt.Sprintf("%s-%d", partitionID, i)),
+							Properties:       map[string]interface{}{"partition": partitionID, "sequence": int64(i)},
+							SystemProperties: &eventhub.SystemProperties{},
+						}))
+					}
+				}(partitionID, handler)
+			}
+			wg.Wait()
+			// the queued events are delivered before shutting down
+			require.NoError(t, c.Shutdown(context.Background()))
+			require.Len(t, sink.AllLogs(), 4*events)
+
+			sequences := map[string][]int64{}
+			for _, l := range sink.AllLogs() {
+				attrs := l.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes()
+				partition, _ := attrs.Get("partition")
+				sequence, _ := attrs.Get("sequence")
+				sequences[partition.Str()] = append(sequences[partition.Str()], sequence.Int())
+			}
+			require.Len(t, sequences, 4)
+			if ordering == partitionOrdering {
+				for partitionID, got := range sequences {
+					for i, sequence := range got {
+						require.Equal(t, int64(i), sequence, partitionID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecoderStopped(t *testing.T) {
+	d := newDecoder(DecodeConfig{Workers: 1, QueueSize: 1}, zap.NewNop())
+	d.stop()
+	assert.ErrorIs(t, d.enqueue(context.Background(), &hubReceiver{name: "hub"}, "0", &eventhub.Event{}), errDecoderStopped)
+
+	assert.Nil(t, newDecoder(DecodeConfig{}, zap.NewNop()), "the events are decoded by the receiving goroutines by default")
+}
@@ -16,11 +16,16 @@ package azureeventhubreceiver // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 )
 
 const (
@@ -28,6 +33,9 @@ const (
 	typeStr = "azureeventhub"
 	// The stability level of the exporter.
 	stability = component.StabilityLevelAlpha
+
+	defaultMonitoringInterval = time.Minute
+	defaultStallThreshold     = 5 * time.Minute
 )
 
 // NewFactory creates a factory for the Azure Event Hub receiver.
@@ -35,28 +43,71 @@ func NewFactory() component.ReceiverFactory {
 	return component.NewReceiverFactory(
 		typeStr,
 		createDefaultConfig,
-		component.WithLogsReceiver(createLogsReceiver, stability))
+		component.WithLogsReceiver(createLogsReceiver, stability),
+		component.WithTracesReceiver(createTracesReceiver, stability))
 }
 
 func createDefaultConfig() component.ReceiverConfig {
-	return &Config{ReceiverSettings: config.NewReceiverSettings(component.NewID(typeStr))}
+	return &Config{
+		ReceiverSettings: config.NewReceiverSettings(component.NewID(typeStr)),
+		PartitionMonitoring: PartitionMonitoringConfig{
+			Interval:       defaultMonitoringInterval,
+			StallThreshold: defaultStallThreshold,
+		},
+	}
 }
 
 func createLogsReceiver(_ context.Context, settings component.ReceiverCreateSettings, receiver component.ReceiverConfig, logs consumer.Logs) (component.LogsReceiver, error) {
+	r, err := getOrAddClient(settings, receiver)
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*client).consumer = logs
+	return r, nil
+}
 
-	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
-		ReceiverID:             receiver.ID(),
-		Transport:              "azureeventhub",
-		ReceiverCreateSettings: settings,
-	})
+// createTracesReceiver creates a receiver emitting a span per received event, it shares the
+// event hubs of the logs receiver with the same configuration.
+func createTracesReceiver(_ context.Context, settings component.ReceiverCreateSettings, receiver component.ReceiverConfig, traces consumer.Traces) (component.TracesReceiver, error) {
+	r, err := getOrAddClient(settings, receiver)
 	if err != nil {
 		return nil, err
 	}
+	r.Unwrap().(*client).tracesConsumer = traces
+	return r, nil
+}
+
+func getOrAddClient(settings component.ReceiverCreateSettings, receiver component.ReceiverConfig) (*sharedcomponent.SharedComponent, error) {
+	var err error
+	r := clients.GetOrAdd(receiver, func() component.Component {
+		if err = view.Register(partitionViews...); err != nil {
+			err = fmt.Errorf("failed to register the azure event hub receiver views: %w", err)
+			return nil
+		}
 
-	return &client{
-		logger:   settings.Logger,
-		consumer: logs,
-		config:   receiver.(*Config),
-		obsrecv:  obsrecv,
-	}, nil
+		var obsrecv *obsreport.Receiver
+		obsrecv, err = obsreport.NewReceiver(obsreport.ReceiverSettings{
+			ReceiverID:             receiver.ID(),
+			Transport:              "azureeventhub",
+			ReceiverCreateSettings: settings,
+		})
+		if err != nil {
+			return nil
+		}
+
+		return &client{
+			logger:  settings.Logger,
+			config:  receiver.(*Config),
+			obsrecv: obsrecv,
+			newHub:  newHubWrapper,
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
 }
+
+// clients holds the clients created per configuration. The logs and traces receivers of the same
+// configuration share a single client, so that the events are received once.
+var clients = sharedcomponent.NewSharedComponents()
@@ -17,6 +17,7 @@ package azureeventhubreceiver
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component"
@@ -28,7 +29,13 @@ import (
 func TestNewFactory(t *testing.T) {
 	f := NewFactory()
 	assert.Equal(t, component.Type("azureeventhub"), f.Type())
-	assert.Equal(t, &Config{ReceiverSettings: config.NewReceiverSettings(component.NewID(typeStr))}, f.CreateDefaultConfig())
+	assert.Equal(t, &Config{
+		ReceiverSettings: config.NewReceiverSettings(component.NewID(typeStr)),
+		PartitionMonitoring: PartitionMonitoringConfig{
+			Interval:       time.Minute,
+			StallThreshold: 5 * time.Minute,
+		},
+	}, f.CreateDefaultConfig())
 }
 
 func TestNewLogsReceiver(t *testing.T) {
@@ -37,3 +44,16 @@ func TestNewLogsReceiver(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, receiver)
 }
+
+func TestNewTracesReceiver(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig()
+	traces, err := f.CreateTracesReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), cfg, consumertest.NewNop())
+	assert.NoError(t, err)
+	assert.NotNil(t, traces)
+
+	// the logs and traces receivers of a configuration share the event hubs
+	logs, err := f.CreateLogsReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), cfg, consumertest.NewNop())
+	assert.NoError(t, err)
+	assert.Same(t, traces, logs)
+}
@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azureeventhubreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/azureeventhubreceiver"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	eventhub "github.com/Azure/azure-event-hubs-go/v3"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+)
+
+var (
+	tagReceiverKey  = tag.MustNewKey("receiver")
+	tagEventHubKey  = tag.MustNewKey("event_hub")
+	tagPartitionKey = tag.MustNewKey("partition")
+
+	mLastSequenceNumber = stats.Int64("otelcol/azureeventhub/partition_last_sequence_number", "Sequence number of the last event received from the partition", stats.UnitDimensionless)
+	mPartitionLag       = stats.Int64("otelcol/azureeventhub/partition_lag", "Number of events enqueued in the partition after the last received one", stats.UnitDimensionless)
+	mTimeSinceLastEvent = stats.Float64("otelcol/azureeventhub/partition_time_since_last_event", "Time since the last event was received from the partition", "s")
+
+	partitionViews = []*view.View{
+		partitionView(mLastSequenceNumber),
+		partitionView(mPartitionLag),
+		partitionView(mTimeSinceLastEvent),
+	}
+)
+
+func partitionView(m stats.Measure) *view.View {
+	return &view.View{
+		Name:        m.Name(),
+		Description: m.Description(),
+		Measure:     m,
+		TagKeys:     []tag.Key{tagReceiverKey, tagEventHubKey, tagPartitionKey},
+		Aggregation: view.LastValue(),
+	}
+}
+
+// partitionStatus tracks the events received from a partition.
+type partitionStatus struct {
+	id string
+
+	mu sync.Mutex
+	// received is set once an event with a sequence number was received.
+	received     bool
+	lastSequence int64
+	// lastEvent is the time the last event was received, or the time the receiving
+	// started when none was.
+	lastEvent time.Time
+	stalled   bool
+}
+
+func newPartitionStatus(id string, now time.Time) *partitionStatus {
+	return &partitionStatus{id: id, lastEvent: now}
+}
+
+// record updates the status with an event received at now.
+func (s *partitionStatus) record(event *eventhub.Event, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastEvent = now
+	if event.SystemProperties != nil && event.SystemProperties.SequenceNumber != nil {
+		s.received = true
+		s.lastSequence = *event.SystemProperties.SequenceNumber
+	}
+}
+
+// partitionMonitor periodically compares the received partitions of the event hubs
+// with their runtime information, records the partition metrics and warns about the
+// partitions that stalled: newer events are enqueued, but none was received for longer
+// than the stall threshold.
+type partitionMonitor struct {
+	host   *processorHost
+	config PartitionMonitoringConfig
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func (m *partitionMonitor) start() {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), m.config.Interval)
+				m.check(ctx)
+				cancel()
+			case <-m.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+func (m *partitionMonitor) stop() {
+	close(m.stopCh)
+	m.wg.Wait()
+}
+
+// check checks every partition of the event hubs once.
+func (m *partitionMonitor) check(ctx context.Context) {
+	for _, h := range m.host.hubs {
+		for _, status := range h.partitions {
+			info, err := h.hub.GetPartitionInformation(ctx, status.id)
+			if err != nil {
+				h.client.logger.Debug("Failed to get the event hub partition information",
+					zap.String("event_hub", h.name), zap.String("partition", status.id), zap.Error(err))
+				continue
+			}
+			m.checkPartition(h, status, info)
+		}
+	}
+}
+
+func (m *partitionMonitor) checkPartition(h *hubReceiver, status *partitionStatus, info *eventhub.HubPartitionRuntimeInformation) {
+	status.mu.Lock()
+	defer status.mu.Unlock()
+
+	sinceLastEvent := m.host.now().Sub(status.lastEvent)
+	var pending bool
+	measurements := []stats.Measurement{mTimeSinceLastEvent.M(sinceLastEvent.Seconds())}
+	if status.received {
+		lag := info.LastSequenceNumber - status.lastSequence
+		if lag < 0 {
+			lag = 0
+		}
+		pending = lag > 0
+		measurements = append(measurements, mLastSequenceNumber.M(status.lastSequence), mPartitionLag.M(lag))
+	} else {
+		// the sequence numbers aren't known until an event is received, the partition is
+		// behind if events were enqueued since the receiving started
+		pending = info.LastEnqueuedTimeUtc.After(status.lastEvent)
+	}
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{
+			tag.Upsert(tagReceiverKey, h.client.config.ID().String()),
+			tag.Upsert(tagEventHubKey, h.name),
+			tag.Upsert(tagPartitionKey, status.id),
+		},
+		measurements...,
+	)
+
+	// an idle partition, without newer events, isn't stalled
+	stalled := pending && sinceLastEvent >= m.config.StallThreshold
+	switch {
+	case stalled && !status.stalled:
+		h.client.logger.Warn("Event hub partition stalled, newer events are enqueued but none was received",
+			zap.String("event_hub", h.name),
+			zap.String("partition", status.id),
+			zap.Duration("since_last_event", sinceLastEvent),
+			zap.Int64("last_enqueued_sequence_number", info.LastSequenceNumber))
+	case !stalled && status.stalled:
+		h.client.logger.Info("Event hub partition recovered",
+			zap.String("event_hub", h.name), zap.String("partition", status.id))
+	}
+	status.stalled = stalled
+}
@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azureeventhubreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	eventhub "github.com/Azure/azure-event-hubs-go/v3"
+	"github.com/Azure/azure-event-hubs-go/v3/persist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// partitionInfoHubWrapper is a mock hub returning the partition information of info,
+// and keeping the handlers of the received partitions.
+type partitionInfoHubWrapper struct {
+	mockHubWrapper
+	info     eventhub.HubPartitionRuntimeInformation
+	handlers map[string]eventhub.Handler
+}
+
+func (m *partitionInfoHubWrapper) GetPartitionInformation(_ context.Context, partitionID string) (*eventhub.HubPartitionRuntimeInformation, error) {
+	info := m.info
+	info.PartitionID = partitionID
+	return &info, nil
+}
+
+func (m *partitionInfoHubWrapper) Receive(ctx context.Context, partitionID string, handler eventhub.Handler, opts ...eventhub.ReceiveOption) (listerHandleWrapper, error) {
+	m.handlers[partitionID] = handler
+	return m.mockHubWrapper.Receive(ctx, partitionID, handler, opts...)
+}
+
+func newMonitoredClient(t *testing.T, hub *partitionInfoHubWrapper, logger *zap.Logger) *client {
+	config := createDefaultConfig().(*Config)
+	config.Connection = "Endpoint=sb://namespace.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=superSecret1234=;EntityPath=hubName"
+	config.PartitionMonitoring = PartitionMonitoringConfig{Interval: time.Hour, StallThreshold: 5 * time.Minute}
+	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
+		ReceiverID:             config.ID(),
+		ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings(),
+	})
+	require.NoError(t, err)
+	return &client{
+		logger:   logger,
+		consumer: consumertest.NewNop(),
+		config:   config,
+		obsrecv:  obsrecv,
+		newHub: func(connection string, persister persist.CheckpointPersister) (hubWrapper, error) {
+			return hub, nil
+		},
+	}
+}
+
+func sequenceEvent(sequence int64) *eventhub.Event {
+	return &eventhub.Event{
+		Data:             []byte("hello"),
+		SystemProperties: &eventhub.SystemProperties{SequenceNumber: &sequence},
+	}
+}
+
+func TestPartitionMonitorDetectsStall(t *testing.T) {
+	// reset the data recorded by the other tests
+	view.Unregister(partitionViews...)
+	require.NoError(t, view.Register(partitionViews...))
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	hub := &partitionInfoHubWrapper{handlers: map[string]eventhub.Handler{}}
+	c := newMonitoredClient(t, hub, zap.New(core))
+	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, c.Shutdown(context.Background())) }()
+
+	now := time.Now()
+	c.host.now = func() time.Time { return now }
+	monitor := c.host.monitor
+	require.NotNil(t, monitor)
+
+	require.NoError(t, hub.handlers["foo"](context.Background(), sequenceEvent(10)))
+
+	// no newer event is enqueued, the partition is idle
+	hub.info.LastSequenceNumber = 10
+	now = now.Add(10 * time.Minute)
+	monitor.check(context.Background())
+	assert.Zero(t, logs.Len())
+
+	// newer events are enqueued but none is received, the partition stalled
+	hub.info.LastSequenceNumber = 15
+	monitor.check(context.Background())
+	monitor.check(context.Background())
+	stalled := logs.FilterMessageSnippet("stalled").All()
+	require.Len(t, stalled, 1, "the stall is only reported once")
+	assert.Equal(t, zapcore.WarnLevel, stalled[0].Level)
+	assert.Equal(t, "hubName", stalled[0].ContextMap()["event_hub"])
+	assert.Equal(t, "foo", stalled[0].ContextMap()["partition"])
+
+	rows, err := view.RetrieveData(mPartitionLag.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(5), rows[0].Data.(*view.LastValueData).Value)
+	rows, err = view.RetrieveData(mTimeSinceLastEvent.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(600), rows[0].Data.(*view.LastValueData).Value)
+
+	// the partition catches up
+	require.NoError(t, hub.handlers["foo"](context.Background(), sequenceEvent(15)))
+	monitor.check(context.Background())
+	assert.Len(t, logs.FilterMessage("Event hub partition recovered").All(), 1)
+	rows, err = view.RetrieveData(mLastSequenceNumber.Name())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, float64(15), rows[0].Data.(*view.LastValueData).Value)
+}
+
+func TestPartitionMonitorWithoutReceivedEvents(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	hub := &partitionInfoHubWrapper{handlers: map[string]eventhub.Handler{}}
+	c := newMonitoredClient(t, hub, zap.New(core))
+	start := time.Now()
+	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, c.Shutdown(context.Background())) }()
+
+	now := start.Add(10 * time.Minute)
+	c.host.now = func() time.Time { return now }
+
+	// the last event was enqueued before the receiving started
+	hub.info.LastEnqueuedTimeUtc = start.Add(-time.Minute)
+	c.host.monitor.check(context.Background())
+	assert.Zero(t, logs.Len())
+
+	hub.info.LastEnqueuedTimeUtc = start.Add(time.Minute)
+	c.host.monitor.check(context.Background())
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestPartitionMonitorDisabled(t *testing.T) {
+	hub := &partitionInfoHubWrapper{handlers: map[string]eventhub.Handler{}}
+	c := newMonitoredClient(t, hub, zap.NewNop())
+	c.config.PartitionMonitoring.Interval = 0
+	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Nil(t, c.host.monitor)
+	assert.NoError(t, c.Shutdown(context.Background()))
+}
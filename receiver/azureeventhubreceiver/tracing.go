@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azureeventhubreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/azureeventhubreceiver"
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	eventhub "github.com/Azure/azure-event-hubs-go/v3"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+)
+
+const (
+	// scopeName is the name of the instrumentation scope of the spans of the received events.
+	scopeName = "otelcol/azureeventhubreceiver"
+
+	messagingSystem = "eventhubs"
+
+	attributePartitionID    = "messaging.eventhubs.partition_id"
+	attributeSequenceNumber = "messaging.eventhubs.sequence_number"
+	attributeOffset         = "messaging.eventhubs.offset"
+)
+
+// traceContextProperties are the application properties of an event holding the W3C trace
+// context of its producer: Diagnostic-Id is set by the Azure SDKs.
+var traceContextProperties = []string{"traceparent", "Diagnostic-Id"}
+
+// newEventTraces wraps an event received from a partition of an event hub in a span lasting
+// from the enqueue of the event to its processing. The span is a child of the span of the
+// producer of the event when the event holds its trace context.
+func newEventTraces(hubName string, partitionID string, resourceAttributes map[string]string, event *eventhub.Event, now time.Time) ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	for k, v := range resourceAttributes {
+		rs.Resource().Attributes().PutStr(k, v)
+	}
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName(scopeName)
+	span := ss.Spans().AppendEmpty()
+
+	if traceID, parentID, ok := eventTraceContext(event); ok {
+		span.SetTraceID(traceID)
+		span.SetParentSpanID(parentID)
+	} else {
+		span.SetTraceID(newTraceID())
+	}
+	span.SetSpanID(newSpanID())
+	span.SetName(hubName + " process")
+	span.SetKind(ptrace.SpanKindConsumer)
+	start := now
+	if event.SystemProperties != nil && event.SystemProperties.EnqueuedTime != nil && event.SystemProperties.EnqueuedTime.Before(now) {
+		start = *event.SystemProperties.EnqueuedTime
+	}
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(now))
+
+	attrs := span.Attributes()
+	attrs.PutStr(conventions.AttributeMessagingSystem, messagingSystem)
+	attrs.PutStr(conventions.AttributeMessagingDestination, hubName)
+	attrs.PutStr(conventions.AttributeMessagingDestinationKind, conventions.AttributeMessagingDestinationKindTopic)
+	attrs.PutStr(conventions.AttributeMessagingOperation, conventions.AttributeMessagingOperationProcess)
+	if event.ID != "" {
+		attrs.PutStr(conventions.AttributeMessagingMessageID, event.ID)
+	}
+	attrs.PutStr(attributePartitionID, partitionID)
+	if event.SystemProperties != nil {
+		if event.SystemProperties.SequenceNumber != nil {
+			attrs.PutInt(attributeSequenceNumber, *event.SystemProperties.SequenceNumber)
+		}
+		if event.SystemProperties.Offset != nil {
+			attrs.PutInt(attributeOffset, *event.SystemProperties.Offset)
+		}
+	}
+	return td
+}
+
+// eventTraceContext returns the trace and span IDs of the W3C traceparent held by the
+// application properties of the event, if any.
+func eventTraceContext(event *eventhub.Event) (pcommon.TraceID, pcommon.SpanID, bool) {
+	for _, key := range traceContextProperties {
+		value, ok := event.Properties[key].(string)
+		if !ok {
+			continue
+		}
+		if traceID, spanID, ok := parseTraceParent(value); ok {
+			return traceID, spanID, true
+		}
+	}
+	return pcommon.TraceID{}, pcommon.SpanID{}, false
+}
+
+// parseTraceParent parses a W3C traceparent: "<version>-<trace-id>-<parent-id>-<trace-flags>".
+func parseTraceParent(value string) (pcommon.TraceID, pcommon.SpanID, bool) {
+	var traceID pcommon.TraceID
+	var spanID pcommon.SpanID
+	parts := strings.Split(value, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return traceID, spanID, false
+	}
+	if len(parts[1]) != hex.EncodedLen(len(traceID)) || len(parts[2]) != hex.EncodedLen(len(spanID)) {
+		return traceID, spanID, false
+	}
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil {
+		return traceID, spanID, false
+	}
+	if _, err := hex.Decode(spanID[:], []byte(parts[2])); err != nil {
+		return traceID, spanID, false
+	}
+	if traceID.IsEmpty() || spanID.IsEmpty() {
+		return traceID, spanID, false
+	}
+	return traceID, spanID, true
+}
+
+func newTraceID() pcommon.TraceID {
+	var traceID pcommon.TraceID
+	_, _ = rand.Read(traceID[:])
+	return traceID
+}
+
+func newSpanID() pcommon.SpanID {
+	var spanID pcommon.SpanID
+	_, _ = rand.Read(spanID[:])
+	return spanID
+}
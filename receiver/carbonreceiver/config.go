@@ -40,13 +40,26 @@ type Config struct {
 
 	confignet.NetAddr `mapstructure:",squash"`
 
-	// TCPIdleTimeout is the timout for idle TCP connections, it is ignored
-	// if transport being used is UDP.
+	// TCPIdleTimeout is the timout for idle TCP and unix stream connections,
+	// it is ignored if the transport being used is UDP or unixgram.
 	TCPIdleTimeout time.Duration `mapstructure:"tcp_idle_timeout"`
 
 	// Parser specifies a parser and the respective configuration to be used
 	// by the receiver.
 	Parser *protocol.Config `mapstructure:"parser"`
+
+	// SanitizeLines removes the control characters and collapses the whitespace
+	// of the received lines before parsing them.
+	SanitizeLines bool `mapstructure:"sanitize_lines"`
+
+	// Timestamp configures the handling of the timestamps too far from the
+	// receive time, e.g. sent by hosts with a skewed clock.
+	Timestamp TimestampConfig `mapstructure:"timestamp"`
+}
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	return cfg.Timestamp.Validate()
 }
 
 func (cfg *Config) Unmarshal(componentParser *confmap.Conf) error {
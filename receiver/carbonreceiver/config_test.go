@@ -56,6 +56,12 @@ func TestLoadConfig(t *testing.T) {
 					Type:   "plaintext",
 					Config: &protocol.PlaintextConfig{},
 				},
+				SanitizeLines: true,
+				Timestamp: TimestampConfig{
+					MaxPastSkew:   time.Hour,
+					MaxFutureSkew: 10 * time.Minute,
+					SkewAction:    skewActionAnnotate,
+				},
 			},
 		},
 		{
@@ -87,6 +93,29 @@ func TestLoadConfig(t *testing.T) {
 						MetricNameSeparator: "_",
 					},
 				},
+				Timestamp: TimestampConfig{
+					SkewAction: skewActionReplace,
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "preset"),
+			expected: &Config{
+				ReceiverSettings: config.NewReceiverSettings(component.NewID(typeStr)),
+				NetAddr: confignet.NetAddr{
+					Endpoint:  "localhost:2003",
+					Transport: "tcp",
+				},
+				TCPIdleTimeout: 30 * time.Second,
+				Parser: &protocol.Config{
+					Type: "regex",
+					Config: &protocol.RegexParserConfig{
+						Preset: protocol.StatsdGraphitePreset,
+					},
+				},
+				Timestamp: TimestampConfig{
+					SkewAction: skewActionReplace,
+				},
 			},
 		},
 	}
@@ -22,6 +22,7 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
 )
@@ -33,6 +34,8 @@ const (
 	typeStr = "carbon"
 	// The stability level of the receiver.
 	stability = component.StabilityLevelStable
+	// The stability level of the logs pipeline receiving the invalid lines.
+	logsStability = component.StabilityLevelAlpha
 )
 
 // NewFactory creates a factory for Carbon receiver.
@@ -40,7 +43,8 @@ func NewFactory() component.ReceiverFactory {
 	return component.NewReceiverFactory(
 		typeStr,
 		createDefaultConfig,
-		component.WithMetricsReceiver(createMetricsReceiver, stability))
+		component.WithMetricsReceiver(createMetricsReceiver, stability),
+		component.WithLogsReceiver(createLogsReceiver, logsStability))
 }
 
 func createDefaultConfig() component.ReceiverConfig {
@@ -55,6 +59,9 @@ func createDefaultConfig() component.ReceiverConfig {
 			Type:   "plaintext",
 			Config: &protocol.PlaintextConfig{},
 		},
+		Timestamp: TimestampConfig{
+			SkewAction: skewActionReplace,
+		},
 	}
 }
 
@@ -65,6 +72,51 @@ func createMetricsReceiver(
 	consumer consumer.Metrics,
 ) (component.MetricsReceiver, error) {
 
-	rCfg := cfg.(*Config)
-	return New(params, *rCfg, consumer)
+	if consumer == nil {
+		return nil, component.ErrNilNextConsumer
+	}
+
+	var err error
+	r := receivers.GetOrAdd(cfg, func() component.Component {
+		var recv *carbonReceiver
+		recv, err = newCarbonReceiver(params, *cfg.(*Config))
+		return recv
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*carbonReceiver).nextConsumer = consumer
+
+	return r, nil
 }
+
+// createLogsReceiver creates a logs receiver that emits the lines that failed parsing,
+// it must share its configuration with a metrics receiver.
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateSettings,
+	cfg component.ReceiverConfig,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	if consumer == nil {
+		return nil, component.ErrNilNextConsumer
+	}
+
+	var err error
+	r := receivers.GetOrAdd(cfg, func() component.Component {
+		var recv *carbonReceiver
+		recv, err = newCarbonReceiver(params, *cfg.(*Config))
+		return recv
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*carbonReceiver).invalidLinesConsumer = consumer
+
+	return r, nil
+}
+
+// This is the map of already created carbon receivers for particular configurations.
+// The metrics and logs receivers of the same configuration share a single carbonReceiver
+// since they listen on the same endpoint.
+var receivers = sharedcomponent.NewSharedComponents()
@@ -19,8 +19,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -39,3 +43,22 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 }
+
+func TestCreateLogsReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0" // Endpoint is required, not going to be used here.
+
+	params := componenttest.NewNopReceiverCreateSettings()
+	lReceiver, err := createLogsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.ErrorIs(t, lReceiver.Start(context.Background(), componenttest.NewNopHost()), errMissingMetricsPipeline)
+
+	mReceiver, err := createMetricsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.Same(t, lReceiver.(*sharedcomponent.SharedComponent).Unwrap(), mReceiver.(*sharedcomponent.SharedComponent).Unwrap())
+	assert.NoError(t, mReceiver.Shutdown(context.Background()))
+	assert.NoError(t, lReceiver.Shutdown(context.Background()))
+
+	_, err = createLogsReceiver(context.Background(), params, cfg, nil)
+	assert.ErrorIs(t, err, component.ErrNilNextConsumer)
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver"
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
+)
+
+const (
+	// errorAttribute is the log record attribute holding the parsing error of an invalid line.
+	errorAttribute = "error.message"
+)
+
+// lineParser wraps the configured parser to sanitize the received lines, to handle
+// the skewed timestamps and to forward the lines that failed parsing to the logs pipeline.
+// The invalid lines are kept until the server flushes the parser, so that all the invalid
+// lines of a read are sent together.
+type lineParser struct {
+	parser               protocol.Parser
+	sanitize             bool
+	timestamps           *timestampHandler
+	invalidLinesConsumer consumer.Logs
+	logger               *zap.Logger
+
+	mu           sync.Mutex
+	invalidLines []invalidLine
+}
+
+// invalidLine is a line that failed parsing, waiting for the next flush.
+type invalidLine struct {
+	line       string
+	err        string
+	observedAt time.Time
+}
+
+var (
+	_ protocol.Parser   = (*lineParser)(nil)
+	_ transport.Flusher = (*lineParser)(nil)
+)
+
+func (p *lineParser) Parse(line string) (*metricspb.Metric, error) {
+	parsed := line
+	if p.sanitize {
+		parsed = sanitizeLine(line)
+	}
+
+	metric, err := p.parser.Parse(parsed)
+	if err == nil && p.timestamps != nil {
+		if err = p.timestamps.handle(metric); err != nil {
+			metric = nil
+		}
+	}
+	if err != nil && p.invalidLinesConsumer != nil {
+		p.addInvalidLine(line, err)
+	}
+	return metric, err
+}
+
+// addInvalidLine keeps the raw line and its parsing error until the next flush.
+func (p *lineParser) addInvalidLine(line string, parseErr error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.invalidLines = append(p.invalidLines, invalidLine{line: line, err: parseErr.Error(), observedAt: time.Now()})
+}
+
+// Flush sends the invalid lines parsed since the previous flush to the logs pipeline,
+// each line as a log record holding its parsing error.
+func (p *lineParser) Flush(ctx context.Context) {
+	p.mu.Lock()
+	lines := p.invalidLines
+	p.invalidLines = nil
+	p.mu.Unlock()
+	if len(lines) == 0 {
+		return
+	}
+
+	ld := plog.NewLogs()
+	lrs := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	lrs.EnsureCapacity(len(lines))
+	for _, l := range lines {
+		lr := lrs.AppendEmpty()
+		lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(l.observedAt))
+		lr.SetSeverityNumber(plog.SeverityNumberWarn)
+		lr.Body().SetStr(l.line)
+		lr.Attributes().PutStr(errorAttribute, l.err)
+	}
+
+	if err := p.invalidLinesConsumer.ConsumeLogs(ctx, ld); err != nil {
+		p.logger.Debug("Carbon receiver failed to push invalid lines into logs pipeline", zap.Error(err), zap.Int("lines", len(lines)))
+	}
+}
+
+// sanitizeLine drops the invalid UTF-8 sequences and the control characters of
+// the line, and collapses runs of whitespace into a single space so that lines
+// using e.g. tabs or several spaces between their fields can still be parsed.
+func sanitizeLine(line string) string {
+	line = strings.ToValidUTF8(line, "")
+	line = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, line)
+	return strings.Join(strings.Fields(line), " ")
+}
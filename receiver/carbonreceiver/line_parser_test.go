@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+func Test_sanitizeLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "valid_line",
+			line: "tst_int 1 1666000000",
+			want: "tst_int 1 1666000000",
+		},
+		{
+			name: "tabs_and_spaces",
+			line: " tst_int\t1  1666000000\r",
+			want: "tst_int 1 1666000000",
+		},
+		{
+			name: "control_characters",
+			line: "tst\x00_int\x1b 1 1666000000",
+			want: "tst_int 1 1666000000",
+		},
+		{
+			name: "invalid_utf8",
+			line: "tst_int\xff 1 1666000000",
+			want: "tst_int 1 1666000000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitizeLine(tt.line))
+		})
+	}
+}
+
+func Test_lineParser(t *testing.T) {
+	plaintext, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+
+	sink := new(consumertest.LogsSink)
+	p := &lineParser{
+		parser:               plaintext,
+		sanitize:             true,
+		invalidLinesConsumer: sink,
+		logger:               zap.NewNop(),
+	}
+
+	metric, err := p.Parse("tst_int\t1  1666000000")
+	require.NoError(t, err)
+	assert.Equal(t, "tst_int", metric.GetMetricDescriptor().GetName())
+	p.Flush(context.Background())
+	assert.Equal(t, 0, sink.LogRecordCount())
+
+	_, err = p.Parse("tst_int one 1666000000")
+	require.Error(t, err)
+	_, err2 := p.Parse("tst_int")
+	require.Error(t, err2)
+	assert.Equal(t, 0, sink.LogRecordCount())
+
+	p.Flush(context.Background())
+	require.Len(t, sink.AllLogs(), 1)
+	lrs := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, lrs.Len())
+	assert.Equal(t, "tst_int one 1666000000", lrs.At(0).Body().Str())
+	errMsg, ok := lrs.At(0).Attributes().Get(errorAttribute)
+	require.True(t, ok)
+	assert.Equal(t, err.Error(), errMsg.Str())
+	assert.Equal(t, "tst_int", lrs.At(1).Body().Str())
+	errMsg, ok = lrs.At(1).Attributes().Get(errorAttribute)
+	require.True(t, ok)
+	assert.Equal(t, err2.Error(), errMsg.Str())
+
+	p.Flush(context.Background())
+	assert.Len(t, sink.AllLogs(), 1)
+}
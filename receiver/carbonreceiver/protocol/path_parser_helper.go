@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 )
 
 // PathParser implements the code needed to handle only the <metric_path> part of
@@ -53,6 +54,9 @@ type ParsedPath struct {
 	// MetricType instructs the helper to generate the metric as the specified
 	// TargetMetricType.
 	MetricType TargetMetricType
+	// ResourceAttributes extracted by the parser, they are set on the resource
+	// of the metric.
+	ResourceAttributes map[string]string
 }
 
 type TargetMetricType string
@@ -151,5 +155,8 @@ func (pph *PathParserHelper) Parse(line string) (*metricspb.Metric, error) {
 		parsedPath.LabelKeys,
 		parsedPath.LabelValues,
 		&point)
+	if len(parsedPath.ResourceAttributes) > 0 {
+		metric.Resource = &resourcepb.Resource{Labels: parsedPath.ResourceAttributes}
+	}
 	return metric, nil
 }
@@ -0,0 +1,86 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+
+import (
+	"fmt"
+	"sort"
+
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+)
+
+const (
+	// CollectdGraphitePreset is the name of the preset handling the metric
+	// paths written by the collectd write_graphite plugin:
+	//
+	//	[collectd.]<host>.<plugin>[-<plugin_instance>].<type>[-<type_instance>]
+	CollectdGraphitePreset = "collectd-graphite"
+
+	// StatsdGraphitePreset is the name of the preset handling the metric paths
+	// written by the statsd graphite backend, with the service as first
+	// component of the metric name and the host optionally used as global prefix:
+	//
+	//	[<host>.]stats.<counters|timers|gauges|sets>.<service>.<metric>
+	StatsdGraphitePreset = "statsd-graphite"
+
+	// presetNameSeparator is used to join the name captures of the preset rules.
+	presetNameSeparator = "."
+)
+
+// presets has the rules of the built-in presets, they are built on each call
+// since the rules are modified by their compilation.
+var presets = map[string]func() []*RegexRule{
+	CollectdGraphitePreset: collectdGraphiteRules,
+	StatsdGraphitePreset:   statsdGraphiteRules,
+}
+
+func collectdGraphiteRules() []*RegexRule {
+	return []*RegexRule{
+		{
+			Regexp: `^(?:collectd\.)?(?P<key_host>[^.]+)\.(?P<name_0>[^.-]+)(?:-(?P<key_plugin_instance>[^.]+))?` +
+				`\.(?P<name_1>[^.-]+)(?:-(?P<key_type_instance>[^.]+))?$`,
+			resourceAttributes: map[string]string{"key_host": conventions.AttributeHostName},
+			preset:             true,
+		},
+	}
+}
+
+func statsdGraphiteRules() []*RegexRule {
+	return []*RegexRule{
+		{
+			Regexp: `^(?:(?P<key_host>[^.]+)\.)?stats\.(?P<key_type>counters|timers|gauges|sets)` +
+				`\.(?P<key_service>[^.]+)\.(?P<name_0>.+)$`,
+			resourceAttributes: map[string]string{
+				"key_host":    conventions.AttributeHostName,
+				"key_service": conventions.AttributeServiceName,
+			},
+			preset: true,
+		},
+	}
+}
+
+// presetRules returns the rules of the preset with the given name.
+func presetRules(name string) ([]*RegexRule, error) {
+	rulesFn, ok := presets[name]
+	if !ok {
+		valid := make([]string, 0, len(presets))
+		for k := range presets {
+			valid = append(valid, k)
+		}
+		sort.Strings(valid)
+		return nil, fmt.Errorf("unknown preset %q, valid presets: %v", name, valid)
+	}
+	return rulesFn(), nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresets(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       *RegexParserConfig
+		line         string
+		wantName     string
+		wantKeys     []*metricspb.LabelKey
+		wantValues   []*metricspb.LabelValue
+		wantResource *resourcepb.Resource
+	}{
+		{
+			name:     "collectd",
+			config:   &RegexParserConfig{Preset: CollectdGraphitePreset},
+			line:     "collectd.host01.cpu-0.cpu-idle 98.5 1668000000",
+			wantName: "cpu.cpu",
+			wantKeys: []*metricspb.LabelKey{
+				{Key: "plugin_instance"},
+				{Key: "type_instance"},
+			},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "0", HasValue: true},
+				{Value: "idle", HasValue: true},
+			},
+			wantResource: &resourcepb.Resource{Labels: map[string]string{"host.name": "host01"}},
+		},
+		{
+			name:         "collectd_without_instances",
+			config:       &RegexParserConfig{Preset: CollectdGraphitePreset},
+			line:         "host02.load.load 0.42 1668000000",
+			wantName:     "load.load",
+			wantKeys:     []*metricspb.LabelKey{},
+			wantValues:   []*metricspb.LabelValue{},
+			wantResource: &resourcepb.Resource{Labels: map[string]string{"host.name": "host02"}},
+		},
+		{
+			name:       "statsd",
+			config:     &RegexParserConfig{Preset: StatsdGraphitePreset},
+			line:       "stats.timers.checkout.http.request.mean_90 12 1668000000",
+			wantName:   "http.request.mean_90",
+			wantKeys:   []*metricspb.LabelKey{{Key: "type"}},
+			wantValues: []*metricspb.LabelValue{{Value: "timers", HasValue: true}},
+			wantResource: &resourcepb.Resource{Labels: map[string]string{
+				"service.name": "checkout",
+			}},
+		},
+		{
+			name:       "statsd_with_host",
+			config:     &RegexParserConfig{Preset: StatsdGraphitePreset},
+			line:       "host03.stats.counters.cart.items.count 3 1668000000",
+			wantName:   "items.count",
+			wantKeys:   []*metricspb.LabelKey{{Key: "type"}},
+			wantValues: []*metricspb.LabelValue{{Value: "counters", HasValue: true}},
+			wantResource: &resourcepb.Resource{Labels: map[string]string{
+				"host.name":    "host03",
+				"service.name": "cart",
+			}},
+		},
+		{
+			name: "rules_before_preset",
+			config: &RegexParserConfig{
+				Rules:  []*RegexRule{{Regexp: `^stats\.gauges\.(?P<key_app>[^.]+)\.`, NamePrefix: "gauge"}},
+				Preset: StatsdGraphitePreset,
+			},
+			line:       "stats.gauges.cart.size 3 1668000000",
+			wantName:   "gauge",
+			wantKeys:   []*metricspb.LabelKey{{Key: "app"}},
+			wantValues: []*metricspb.LabelValue{{Value: "cart", HasValue: true}},
+		},
+		{
+			name:     "no_match",
+			config:   &RegexParserConfig{Preset: StatsdGraphitePreset},
+			line:     "statsd.numStats 3 1668000000",
+			wantName: "statsd.numStats",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := tt.config.BuildParser()
+			require.NoError(t, err)
+
+			got, err := p.Parse(tt.line)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, got.MetricDescriptor.Name)
+			assert.Equal(t, tt.wantKeys, got.MetricDescriptor.LabelKeys)
+			assert.Equal(t, tt.wantValues, got.Timeseries[0].LabelValues)
+			assert.Equal(t, tt.wantResource, got.Resource)
+		})
+	}
+}
@@ -67,6 +67,12 @@ type RegexParserConfig struct {
 	// rule and the respective named captures that start with the prefix
 	// "name_" (see RegexRule for more information).
 	MetricNameSeparator string `mapstructure:"name_separator"`
+
+	// Preset selects built-in rules, applied after the ones in Rules, for a
+	// well-known naming hierarchy: "collectd-graphite" or "statsd-graphite".
+	// The host and service found in the metric path are set as the "host.name"
+	// and "service.name" resource attributes instead of metric labels.
+	Preset string `mapstructure:"preset"`
 }
 
 // RegexRule describes how parts of the name of metric are going to be mapped
@@ -92,6 +98,13 @@ type RegexRule struct {
 	// Some fields cached after the compilation of the regular expression.
 	compRegexp      *regexp.Regexp
 	metricNameParts []string
+
+	// resourceAttributes maps the "key_" captures of the preset rules to the
+	// resource attributes they are set as.
+	resourceAttributes map[string]string
+	// preset rules skip the captures that didn't match and join the name
+	// captures with presetNameSeparator.
+	preset bool
 }
 
 var _ (ParserConfig) = (*RegexParserConfig)(nil)
@@ -102,12 +115,21 @@ func (rpc *RegexParserConfig) BuildParser() (Parser, error) {
 		return nil, errors.New("nil receiver on RegexParserConfig.BuildParser")
 	}
 
-	if err := compileRegexRules(rpc.Rules); err != nil {
+	rules := rpc.Rules
+	if rpc.Preset != "" {
+		preset, err := presetRules(rpc.Preset)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(append([]*RegexRule{}, rules...), preset...)
+	}
+
+	if err := compileRegexRules(rules); err != nil {
 		return nil, err
 	}
 
 	rpp := &regexPathParser{
-		rules:               rpc.Rules,
+		rules:               rules,
 		metricNameSeparator: rpc.MetricNameSeparator,
 	}
 
@@ -176,12 +198,21 @@ func (rpp *regexPathParser) ParsePath(path string, parsedPath *ParsedPath) error
 			ms := rule.compRegexp.FindStringSubmatch(path)
 			nms := rule.compRegexp.SubexpNames() // regexp pre-computes this slice.
 			metricNameLookup := map[string]string{}
+			var resourceAttributes map[string]string
 
 			keys := make([]*metricspb.LabelKey, 0, len(nms)+len(rule.Labels))
 			values := make([]*metricspb.LabelValue, 0, len(nms)+len(rule.Labels))
 			for i := 1; i < len(ms); i++ {
+				if rule.preset && ms[i] == "" {
+					continue
+				}
 				if strings.HasPrefix(nms[i], metricNameCapturePrefix) {
 					metricNameLookup[nms[i]] = ms[i]
+				} else if attr, ok := rule.resourceAttributes[nms[i]]; ok {
+					if resourceAttributes == nil {
+						resourceAttributes = map[string]string{}
+					}
+					resourceAttributes[attr] = ms[i]
 				} else {
 					keys = append(keys, &metricspb.LabelKey{Key: nms[i][len(keyCapturePrefix):]})
 					values = append(values, &metricspb.LabelValue{
@@ -200,7 +231,13 @@ func (rpp *regexPathParser) ParsePath(path string, parsedPath *ParsedPath) error
 			}
 
 			var actualMetricName string
-			if len(rule.metricNameParts) == 0 {
+			if rule.preset {
+				parts := make([]string, 0, len(rule.metricNameParts))
+				for _, mnp := range rule.metricNameParts {
+					parts = append(parts, metricNameLookup[mnp])
+				}
+				actualMetricName = strings.Join(parts, presetNameSeparator)
+			} else if len(rule.metricNameParts) == 0 {
 				actualMetricName = rule.NamePrefix
 			} else {
 				var sb strings.Builder
@@ -220,6 +257,7 @@ func (rpp *regexPathParser) ParsePath(path string, parsedPath *ParsedPath) error
 			parsedPath.LabelKeys = keys
 			parsedPath.LabelValues = values
 			parsedPath.MetricType = TargetMetricType(rule.MetricType)
+			parsedPath.ResourceAttributes = resourceAttributes
 			return nil
 		}
 	}
@@ -69,6 +69,15 @@ func TestRegexParserConfigBuildParser(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:    "unknown_preset",
+			config:  &RegexParserConfig{Preset: "unknown"},
+			wantErr: true,
+		},
+		{
+			name:   "preset_only",
+			config: &RegexParserConfig{Preset: CollectdGraphitePreset},
+		},
 		{
 			name: "valid_rules",
 			config: &RegexParserConfig{
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 
@@ -28,7 +29,8 @@ import (
 )
 
 var (
-	errEmptyEndpoint = errors.New("empty endpoint")
+	errEmptyEndpoint          = errors.New("empty endpoint")
+	errMissingMetricsPipeline = errors.New("carbon receiver must be part of a metrics pipeline, a logs pipeline only receives the invalid lines")
 )
 
 // carbonreceiver implements a component.MetricsReceiver for Carbon plaintext, aka "line", protocol.
@@ -41,6 +43,9 @@ type carbonReceiver struct {
 	reporter     transport.Reporter
 	parser       protocol.Parser
 	nextConsumer consumer.Metrics
+	// invalidLinesConsumer receives the lines that failed parsing as log records, it is nil
+	// unless the receiver is also part of a logs pipeline.
+	invalidLinesConsumer consumer.Logs
 }
 
 var _ component.MetricsReceiver = (*carbonReceiver)(nil)
@@ -56,6 +61,17 @@ func New(
 		return nil, component.ErrNilNextConsumer
 	}
 
+	r, err := newCarbonReceiver(set, config)
+	if err != nil {
+		return nil, err
+	}
+	r.nextConsumer = nextConsumer
+	return r, nil
+}
+
+// newCarbonReceiver creates the receiver without its consumers, which are set
+// by the factory for each of the pipelines the receiver is part of.
+func newCarbonReceiver(set component.ReceiverCreateSettings, config Config) (*carbonReceiver, error) {
 	if config.Endpoint == "" {
 		return nil, errEmptyEndpoint
 	}
@@ -73,6 +89,10 @@ func New(
 		return nil, err
 	}
 
+	if err = view.Register(skewedTimestampsView); err != nil {
+		return nil, fmt.Errorf("failed to register the carbon receiver views: %w", err)
+	}
+
 	// This should be the last one built, or if any other error is raised after
 	// it, the server should be closed.
 	server, err := buildTransportServer(config)
@@ -86,12 +106,11 @@ func New(
 	}
 
 	r := carbonReceiver{
-		settings:     set,
-		config:       &config,
-		nextConsumer: nextConsumer,
-		server:       server,
-		reporter:     rep,
-		parser:       parser,
+		settings: set,
+		config:   &config,
+		server:   server,
+		reporter: rep,
+		parser:   parser,
 	}
 
 	return &r, nil
@@ -103,6 +122,10 @@ func buildTransportServer(config Config) (transport.Server, error) {
 		return transport.NewTCPServer(config.Endpoint, config.TCPIdleTimeout)
 	case "udp":
 		return transport.NewUDPServer(config.Endpoint)
+	case "unix":
+		return transport.NewUnixServer(config.Endpoint, config.TCPIdleTimeout)
+	case "unixgram":
+		return transport.NewUnixgramServer(config.Endpoint)
 	}
 
 	return nil, fmt.Errorf("unsupported transport %q for receiver %v", config.Transport, config.ID())
@@ -112,8 +135,25 @@ func buildTransportServer(config Config) (transport.Server, error) {
 // By convention the consumer of the received data is set when the receiver
 // instance is created.
 func (r *carbonReceiver) Start(_ context.Context, host component.Host) error {
+	if r.nextConsumer == nil {
+		return errMissingMetricsPipeline
+	}
+	parser := r.parser
+	var timestamps *timestampHandler
+	if r.config.Timestamp.enabled() {
+		timestamps = newTimestampHandler(r.config.Timestamp, r.config.ID().String())
+	}
+	if r.config.SanitizeLines || timestamps != nil || r.invalidLinesConsumer != nil {
+		parser = &lineParser{
+			parser:               r.parser,
+			sanitize:             r.config.SanitizeLines,
+			timestamps:           timestamps,
+			invalidLinesConsumer: r.invalidLinesConsumer,
+			logger:               r.settings.Logger,
+		}
+	}
 	go func() {
-		if err := r.server.ListenAndServe(r.parser, r.nextConsumer, r.reporter); err != nil {
+		if err := r.server.ListenAndServe(parser, r.nextConsumer, r.reporter); err != nil {
 			host.ReportFatalError(err)
 		}
 	}()
@@ -17,6 +17,7 @@ package carbonreceiver
 import (
 	"context"
 	"errors"
+	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
@@ -163,6 +164,7 @@ func Test_carbonreceiver_New(t *testing.T) {
 
 func Test_carbonreceiver_EndToEnd(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
+	socket := filepath.Join(t.TempDir(), "carbon.sock")
 	tests := []struct {
 		name     string
 		configFn func() *Config
@@ -192,11 +194,30 @@ func Test_carbonreceiver_EndToEnd(t *testing.T) {
 				return c
 			},
 		},
+		{
+			name: "unix_socket",
+			configFn: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.Transport = "unix"
+				return cfg
+			},
+			clientFn: func(t *testing.T) *client.Graphite {
+				c, err := client.NewGraphite(client.Unix, socket)
+				require.NoError(t, err)
+				return c
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := tt.configFn()
 			cfg.Endpoint = addr
+			if cfg.Transport == "unix" {
+				if runtime.GOOS == "windows" {
+					t.Skip("the unix domain sockets are not supported on Windows")
+				}
+				cfg.Endpoint = socket
+			}
 			sink := new(consumertest.MetricsSink)
 			rcv, err := New(componenttest.NewNopReceiverCreateSettings(), *cfg, sink)
 			require.NoError(t, err)
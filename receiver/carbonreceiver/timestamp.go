@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// skewActionReplace replaces the skewed timestamps with the receive time.
+	skewActionReplace = "replace"
+	// skewActionReject rejects the metrics with a skewed timestamp, as invalid lines.
+	skewActionReject = "reject"
+	// skewActionAnnotate keeps the skewed timestamps and adds the skewedTimestampLabel to the metrics.
+	skewActionAnnotate = "annotate"
+
+	// skewedTimestampLabel is the label added to the metrics whose skewed timestamp was kept.
+	skewedTimestampLabel = "timestamp_skewed"
+)
+
+// TimestampConfig defines how the timestamps sent by the clients are handled.
+type TimestampConfig struct {
+	// MaxPastSkew is how far in the past of the receive time a timestamp can be, 0 means no limit.
+	MaxPastSkew time.Duration `mapstructure:"max_past_skew"`
+	// MaxFutureSkew is how far in the future of the receive time a timestamp can be, 0 means no limit.
+	MaxFutureSkew time.Duration `mapstructure:"max_future_skew"`
+	// SkewAction is applied to the metrics whose timestamp exceeds the skew limits,
+	// one of replace, reject or annotate.
+	SkewAction string `mapstructure:"skew_action"`
+}
+
+func (cfg *TimestampConfig) enabled() bool {
+	return cfg.MaxPastSkew > 0 || cfg.MaxFutureSkew > 0
+}
+
+// Validate checks the timestamp configuration is valid.
+func (cfg *TimestampConfig) Validate() error {
+	if cfg.MaxPastSkew < 0 {
+		return fmt.Errorf("timestamp.max_past_skew must not be negative")
+	}
+	if cfg.MaxFutureSkew < 0 {
+		return fmt.Errorf("timestamp.max_future_skew must not be negative")
+	}
+	if !cfg.enabled() {
+		// the skew action is only applied when a skew limit is set
+		return nil
+	}
+	switch cfg.SkewAction {
+	case skewActionReplace, skewActionReject, skewActionAnnotate:
+		return nil
+	default:
+		return fmt.Errorf("unsupported timestamp.skew_action %q, must be one of replace, reject or annotate", cfg.SkewAction)
+	}
+}
+
+var (
+	tagReceiverKey   = tag.MustNewKey("receiver")
+	tagSkewActionKey = tag.MustNewKey("action")
+
+	mSkewedTimestamps = stats.Int64("otelcol/carbon/skewed_timestamps", "Number of metrics received with a skewed timestamp, by action applied", stats.UnitDimensionless)
+
+	skewedTimestampsView = &view.View{
+		Name:        mSkewedTimestamps.Name(),
+		Description: mSkewedTimestamps.Description(),
+		Measure:     mSkewedTimestamps,
+		TagKeys:     []tag.Key{tagReceiverKey, tagSkewActionKey},
+		Aggregation: view.Sum(),
+	}
+)
+
+// timestampHandler applies the skew action to the metrics whose timestamp is too far from the receive time.
+type timestampHandler struct {
+	cfg      TimestampConfig
+	receiver string
+	// now returns the receive time, it is replaced by the tests.
+	now func() time.Time
+}
+
+func newTimestampHandler(cfg TimestampConfig, receiver string) *timestampHandler {
+	return &timestampHandler{
+		cfg:      cfg,
+		receiver: receiver,
+		now:      time.Now,
+	}
+}
+
+// handle applies the skew action to the metric, it returns an error if the metric is rejected.
+func (h *timestampHandler) handle(metric *metricspb.Metric) error {
+	now := h.now()
+	for _, ts := range metric.GetTimeseries() {
+		for _, point := range ts.GetPoints() {
+			skew := point.GetTimestamp().AsTime().Sub(now)
+			if !h.skewed(skew) {
+				continue
+			}
+			h.record(h.cfg.SkewAction)
+			switch h.cfg.SkewAction {
+			case skewActionReject:
+				return fmt.Errorf("timestamp of carbon metric %q is skewed by %v", metric.GetMetricDescriptor().GetName(), skew)
+			case skewActionAnnotate:
+				annotateSkewedTimestamp(metric)
+				return nil
+			default:
+				point.Timestamp = timestamppb.New(now)
+			}
+		}
+	}
+	return nil
+}
+
+func (h *timestampHandler) skewed(skew time.Duration) bool {
+	if h.cfg.MaxPastSkew > 0 && skew < -h.cfg.MaxPastSkew {
+		return true
+	}
+	return h.cfg.MaxFutureSkew > 0 && skew > h.cfg.MaxFutureSkew
+}
+
+func (h *timestampHandler) record(action string) {
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{tag.Upsert(tagReceiverKey, h.receiver), tag.Upsert(tagSkewActionKey, action)},
+		mSkewedTimestamps.M(1))
+}
+
+// annotateSkewedTimestamp adds the skewedTimestampLabel to all the time series of the metric.
+func annotateSkewedTimestamp(metric *metricspb.Metric) {
+	descriptor := metric.GetMetricDescriptor()
+	descriptor.LabelKeys = append(descriptor.LabelKeys, &metricspb.LabelKey{Key: skewedTimestampLabel})
+	for _, ts := range metric.GetTimeseries() {
+		ts.LabelValues = append(ts.LabelValues, &metricspb.LabelValue{Value: "true", HasValue: true})
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+func TestTimestampConfigValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+	assert.False(t, cfg.Timestamp.enabled())
+
+	// the skew action is only validated when a skew limit is set
+	cfg.Timestamp.SkewAction = ""
+	assert.NoError(t, cfg.Validate())
+	cfg.Timestamp.SkewAction = "drop"
+	assert.NoError(t, cfg.Validate())
+	cfg.Timestamp.MaxPastSkew = time.Hour
+	assert.True(t, cfg.Timestamp.enabled())
+	assert.EqualError(t, cfg.Validate(), `unsupported timestamp.skew_action "drop", must be one of replace, reject or annotate`)
+
+	cfg.Timestamp.SkewAction = skewActionReject
+	cfg.Timestamp.MaxFutureSkew = -time.Minute
+	assert.EqualError(t, cfg.Validate(), "timestamp.max_future_skew must not be negative")
+}
+
+func TestTimestampHandler(t *testing.T) {
+	now := time.Unix(1666000000, 0)
+	line := func(offset time.Duration) string {
+		return "tst_int 1 " + strconv.FormatInt(now.Add(offset).Unix(), 10)
+	}
+
+	tests := []struct {
+		name          string
+		action        string
+		line          string
+		wantErr       string
+		wantTimestamp time.Time
+		wantLabel     bool
+	}{
+		{
+			name:          "within_limits",
+			action:        skewActionReject,
+			line:          line(-30 * time.Minute),
+			wantTimestamp: now.Add(-30 * time.Minute),
+		},
+		{
+			name:          "replace_past",
+			action:        skewActionReplace,
+			line:          line(-2 * time.Hour),
+			wantTimestamp: now,
+		},
+		{
+			name:          "replace_future",
+			action:        skewActionReplace,
+			line:          line(time.Hour),
+			wantTimestamp: now,
+		},
+		{
+			name:    "reject",
+			action:  skewActionReject,
+			line:    line(-2 * time.Hour),
+			wantErr: `timestamp of carbon metric "tst_int" is skewed by -2h0m0s`,
+		},
+		{
+			name:          "annotate",
+			action:        skewActionAnnotate,
+			line:          line(time.Hour),
+			wantTimestamp: now.Add(time.Hour),
+			wantLabel:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext, err := (&protocol.PlaintextConfig{}).BuildParser()
+			require.NoError(t, err)
+			timestamps := newTimestampHandler(TimestampConfig{
+				MaxPastSkew:   time.Hour,
+				MaxFutureSkew: 10 * time.Minute,
+				SkewAction:    tt.action,
+			}, "carbon")
+			timestamps.now = func() time.Time { return now }
+
+			sink := new(consumertest.LogsSink)
+			p := &lineParser{
+				parser:               plaintext,
+				timestamps:           timestamps,
+				invalidLinesConsumer: sink,
+				logger:               zap.NewNop(),
+			}
+
+			metric, err := p.Parse(tt.line)
+			p.Flush(context.Background())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.Nil(t, metric)
+				assert.Equal(t, 1, sink.LogRecordCount())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 0, sink.LogRecordCount())
+			ts := metric.GetTimeseries()[0]
+			assert.Equal(t, tt.wantTimestamp.UTC(), ts.GetPoints()[0].GetTimestamp().AsTime())
+			if tt.wantLabel {
+				require.Len(t, metric.GetMetricDescriptor().GetLabelKeys(), 1)
+				assert.Equal(t, skewedTimestampLabel, metric.GetMetricDescriptor().GetLabelKeys()[0].GetKey())
+				assert.Equal(t, "true", ts.GetLabelValues()[0].GetValue())
+			} else {
+				assert.Empty(t, metric.GetMetricDescriptor().GetLabelKeys())
+			}
+		})
+	}
+}
+
+func TestTimestampHandlerRecordsSkewedTimestamps(t *testing.T) {
+	// the view is registered by the receivers created in the other tests, reset its data
+	view.Unregister(skewedTimestampsView)
+	require.NoError(t, view.Register(skewedTimestampsView))
+
+	now := time.Unix(1666000000, 0)
+	plaintext, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	timestamps := newTimestampHandler(TimestampConfig{
+		MaxPastSkew: time.Hour,
+		SkewAction:  skewActionReplace,
+	}, "carbon")
+	timestamps.now = func() time.Time { return now }
+
+	for _, offset := range []time.Duration{-2 * time.Hour, -time.Minute, -3 * time.Hour} {
+		metric, err := plaintext.Parse("tst_int 1 " + strconv.FormatInt(now.Add(offset).Unix(), 10))
+		require.NoError(t, err)
+		require.NoError(t, timestamps.handle(metric))
+	}
+
+	rows, err := view.RetrieveData(skewedTimestampsView.Name)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.ElementsMatch(t, []tag.Tag{
+		{Key: tagReceiverKey, Value: "carbon"},
+		{Key: tagSkewActionKey, Value: skewActionReplace},
+	}, rows[0].Tags)
+	assert.Equal(t, float64(2), rows[0].Data.(*view.SumData).Value)
+}
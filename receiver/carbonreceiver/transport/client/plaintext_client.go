@@ -38,10 +38,12 @@ type Graphite struct {
 // Transport is used as an enum to select the type of transport to be used.
 type Transport int
 
-// Available transport options: TCP and UDP.
+// Available transport options: TCP, UDP and the unix domain stream and datagram sockets.
 const (
 	TCP Transport = iota
 	UDP
+	Unix
+	Unixgram
 )
 
 const defaultTimeout = 5
@@ -85,6 +87,10 @@ func (g *Graphite) connect(transport Transport) error {
 		if err != nil {
 			return err
 		}
+	case Unix:
+		g.Conn, err = net.DialTimeout("unix", g.Endpoint, g.Timeout)
+	case Unixgram:
+		g.Conn, err = net.DialTimeout("unixgram", g.Endpoint, g.Timeout)
 	default:
 		return fmt.Errorf("unknown transport %d", transport)
 	}
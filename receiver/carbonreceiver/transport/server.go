@@ -45,6 +45,21 @@ type Server interface {
 	Close() error
 }
 
+// Flusher is implemented by the parsers keeping data derived from the parsed lines,
+// such as the lines that failed parsing, until all the lines read at once are parsed.
+type Flusher interface {
+	// Flush is called once the lines read from a connection, or a packet, are parsed.
+	// The context is the one returned by Reporter.OnDataReceived.
+	Flush(ctx context.Context)
+}
+
+// flush flushes the parser if it implements Flusher.
+func flush(ctx context.Context, p protocol.Parser) {
+	if f, ok := p.(Flusher); ok {
+		f.Flush(ctx)
+	}
+}
+
 // Reporter is used to report (via zPages, logs, metrics, etc) the events
 // happening when the Server is receiving and processing data.
 type Reporter interface {
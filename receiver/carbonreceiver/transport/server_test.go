@@ -15,7 +15,10 @@
 package transport
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -52,10 +55,34 @@ func Test_Server_ListenAndServe(t *testing.T) {
 				return client.NewGraphite(client.UDP, addr)
 			},
 		},
+		{
+			name: "unix",
+			buildServerFn: func(addr string) (Server, error) {
+				return NewUnixServer(addr, 1*time.Second)
+			},
+			buildClientFn: func(addr string) (*client.Graphite, error) {
+				return client.NewGraphite(client.Unix, addr)
+			},
+		},
+		{
+			name:          "unixgram",
+			buildServerFn: NewUnixgramServer,
+			buildClientFn: func(addr string) (*client.Graphite, error) {
+				return client.NewGraphite(client.Unixgram, addr)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			addr := testutil.GetAvailableLocalNetworkAddress(t, tt.name)
+			var addr string
+			if strings.HasPrefix(tt.name, "unix") {
+				if runtime.GOOS == "windows" {
+					t.Skip("the unix domain sockets are not supported on Windows")
+				}
+				addr = filepath.Join(t.TempDir(), "carbon.sock")
+			} else {
+				addr = testutil.GetAvailableLocalNetworkAddress(t, tt.name)
+			}
 
 			svr, err := tt.buildServerFn(addr)
 			require.NoError(t, err)
@@ -101,6 +128,11 @@ func Test_Server_ListenAndServe(t *testing.T) {
 
 			wgListenAndServe.Wait()
 
+			if strings.HasPrefix(tt.name, "unix") {
+				// the socket file is removed on close
+				assert.NoFileExists(t, addr)
+			}
+
 			mdd := mc.AllMetrics()
 			require.Len(t, mdd, 1)
 			_, _, metrics := internaldata.ResourceMetricsToOC(mdd[0].ResourceMetrics().At(0))
@@ -109,3 +141,26 @@ func Test_Server_ListenAndServe(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoveStaleSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the unix domain sockets are not supported on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "carbon.sock")
+
+	// the socket left by a previous server is replaced
+	svr, err := NewUnixgramServer(path)
+	require.NoError(t, err)
+	svr.(*udpServer).socketPath = ""
+	require.NoError(t, svr.Close())
+	require.FileExists(t, path)
+	svr, err = NewUnixgramServer(path)
+	require.NoError(t, err)
+	require.NoError(t, svr.Close())
+
+	// any other file is kept
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0600))
+	_, err = NewUnixServer(path, time.Second)
+	assert.EqualError(t, err, path+" already exists and is not a unix socket")
+	assert.FileExists(t, path)
+}
@@ -52,6 +52,11 @@ func NewTCPServer(
 	addr string,
 	idleTimeout time.Duration,
 ) (Server, error) {
+	return newStreamServer("tcp", addr, idleTimeout)
+}
+
+// newStreamServer creates the server accepting the connections of the stream network, "tcp" or "unix".
+func newStreamServer(network, addr string, idleTimeout time.Duration) (*tcpServer, error) {
 	if idleTimeout < 0 {
 		return nil, fmt.Errorf("invalid idle timeout: %v", idleTimeout)
 	}
@@ -60,7 +65,7 @@ func NewTCPServer(
 		idleTimeout = TCPIdleTimeoutDefault
 	}
 
-	ln, err := net.Listen("tcp", addr)
+	ln, err := net.Listen(network, addr)
 	if err != nil {
 		return nil, err
 	}
@@ -183,11 +188,18 @@ func (t *tcpServer) handleConnection(
 			metric, err = p.Parse(line)
 			if err != nil {
 				t.reporter.OnTranslationError(ctx, err)
+				if reader.Buffered() == 0 {
+					flush(ctx, p)
+				}
 				continue
 			}
 
 			err = nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(nil, nil, []*metricspb.Metric{metric}))
 			t.reporter.OnMetricsProcessed(ctx, numReceivedMetricPoints, err)
+			if err != nil || reader.Buffered() == 0 {
+				// the lines read at once have all been parsed
+				flush(ctx, p)
+			}
 			if err != nil {
 				// The protocol doesn't account for returning errors.
 				// Since this is a TCP connection it seems reasonable to close the
@@ -19,7 +19,9 @@ import (
 	"context"
 	"errors"
 	"io"
+	"io/fs"
 	"net"
+	"os"
 	"strings"
 	"sync"
 
@@ -34,13 +36,20 @@ type udpServer struct {
 	wg         sync.WaitGroup
 	packetConn net.PacketConn
 	reporter   Reporter
+	// socketPath is the path of the unix datagram socket, removed on close.
+	socketPath string
 }
 
 var _ Server = (*udpServer)(nil)
 
 // NewUDPServer creates a transport.Server using UDP as its transport.
 func NewUDPServer(addr string) (Server, error) {
-	packetConn, err := net.ListenPacket("udp", addr)
+	return newPacketServer("udp", addr)
+}
+
+// newPacketServer creates the server reading the packets of the datagram network, "udp" or "unixgram".
+func newPacketServer(network, addr string) (*udpServer, error) {
+	packetConn, err := net.ListenPacket(network, addr)
 	if err != nil {
 		return nil, err
 	}
@@ -93,6 +102,11 @@ func (u *udpServer) ListenAndServe(
 func (u *udpServer) Close() error {
 	err := u.packetConn.Close()
 	u.wg.Wait()
+	if u.socketPath != "" {
+		if rmErr := os.Remove(u.socketPath); rmErr != nil && !errors.Is(rmErr, fs.ErrNotExist) && err == nil {
+			err = rmErr
+		}
+	}
 	return err
 }
 
@@ -126,6 +140,8 @@ func (u *udpServer) handlePacket(
 		}
 	}
 
+	flush(ctx, p)
+
 	err := nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(nil, nil, metrics))
 	u.reporter.OnMetricsProcessed(ctx, numReceivedMetricPoints, err)
 }
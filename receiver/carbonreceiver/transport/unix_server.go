@@ -0,0 +1,62 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// NewUnixServer creates a transport.Server listening on a unix domain stream socket at the given path.
+func NewUnixServer(path string, idleTimeout time.Duration) (Server, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	// the socket file is removed when the listener is closed
+	return newStreamServer("unix", path, idleTimeout)
+}
+
+// NewUnixgramServer creates a transport.Server listening on a unix domain datagram socket at the given path.
+func NewUnixgramServer(path string) (Server, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	u, err := newPacketServer("unixgram", path)
+	if err != nil {
+		return nil, err
+	}
+	// unlike the listeners, the datagram sockets don't remove their file when closed
+	u.socketPath = path
+	return u, nil
+}
+
+// removeStaleSocket removes the socket left at the path, e.g. by a collector that crashed, since
+// binding to an existing file fails. Any other kind of file is kept and reported.
+func removeStaleSocket(path string) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s already exists and is not a unix socket", path)
+	}
+	return os.Remove(path)
+}
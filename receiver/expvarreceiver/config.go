@@ -29,12 +29,63 @@ type Config struct {
 	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
 	confighttp.HTTPClientSettings           `mapstructure:",squash"`
 	MetricsConfig                           metadata.MetricsSettings `mapstructure:"metrics"`
+	// Histograms configures the expvar variables that are interpreted as histograms.
+	Histograms []HistogramConfig `mapstructure:"histograms"`
+	// RuntimeMetrics configures the histograms built from the runtime/metrics samples of the process.
+	RuntimeMetrics RuntimeMetricsConfig `mapstructure:"runtime_metrics"`
+}
+
+// HistogramConfig describes how an expvar variable holding a sum, a count and
+// a set of buckets is converted to an OTLP histogram.
+type HistogramConfig struct {
+	// Variable is the name of the top-level expvar variable holding the histogram.
+	Variable string `mapstructure:"variable"`
+	// Name is the name of the emitted metric, defaults to the variable name.
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	Unit        string `mapstructure:"unit"`
+	// Attribute, when set, indicates that the variable is a map of histograms,
+	// one data point is emitted per entry with its key recorded in this attribute.
+	Attribute string `mapstructure:"attribute"`
+	// SumKey, CountKey and BucketsKey are the keys of the histogram map holding
+	// the sum, the count and the buckets of the histogram.
+	SumKey     string `mapstructure:"sum_key"`
+	CountKey   string `mapstructure:"count_key"`
+	BucketsKey string `mapstructure:"buckets_key"`
+	// CumulativeBuckets indicates that bucket counts include the counts of all the
+	// buckets with a lower upper bound, as in Prometheus histograms.
+	CumulativeBuckets bool `mapstructure:"cumulative_buckets"`
 }
 
 var _ component.ReceiverConfig = (*Config)(nil)
 
 func (c *Config) Validate() error {
-	u, err := url.Parse(c.Endpoint)
+	if err := validateEndpoint(c.Endpoint); err != nil {
+		return err
+	}
+	if c.RuntimeMetrics.Endpoint != "" {
+		if err := validateEndpoint(c.RuntimeMetrics.Endpoint); err != nil {
+			return fmt.Errorf("runtime_metrics: %w", err)
+		}
+	} else if c.RuntimeMetrics.Variable == "" && len(enabledRuntimeHistograms(c.MetricsConfig)) > 0 {
+		return fmt.Errorf("runtime_metrics: variable or endpoint must be specified")
+	}
+	names := map[string]bool{}
+	for i, h := range c.Histograms {
+		if h.Variable == "" {
+			return fmt.Errorf("histograms[%d]: variable must be specified", i)
+		}
+		name := h.metricName()
+		if names[name] {
+			return fmt.Errorf("histograms[%d]: duplicate metric name '%s'", i, name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+func validateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
 	if err != nil {
 		return fmt.Errorf("endpoint is not a valid URL: %w", err)
 	}
@@ -46,3 +97,10 @@ func (c *Config) Validate() error {
 	}
 	return nil
 }
+
+func (h HistogramConfig) metricName() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.Variable
+}
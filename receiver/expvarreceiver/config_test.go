@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/receiver/scraperhelper"
@@ -39,6 +40,12 @@ func TestLoadConfig(t *testing.T) {
 	metricCfg := metadata.DefaultMetricsSettings()
 	metricCfg.ProcessRuntimeMemstatsTotalAlloc.Enabled = true
 	metricCfg.ProcessRuntimeMemstatsMallocs.Enabled = false
+	metricCfg.ProcessRuntimeGcPauses.Enabled = false
+
+	runtimeMetricsDisabledCfg := factory.CreateDefaultConfig().(*Config)
+	runtimeMetricsDisabledCfg.RuntimeMetrics.Variable = ""
+	runtimeMetricsDisabledCfg.MetricsConfig.ProcessRuntimeSchedLatencies.Enabled = false
+	runtimeMetricsDisabledCfg.MetricsConfig.ProcessRuntimeGcPauses.Enabled = false
 
 	tests := []struct {
 		id           component.ID
@@ -61,6 +68,42 @@ func TestLoadConfig(t *testing.T) {
 					Timeout:  time.Second * 5,
 				},
 				MetricsConfig: metricCfg,
+				Histograms: []HistogramConfig{
+					{
+						Variable: "request_latency",
+						Unit:     "s",
+					},
+					{
+						Variable:          "handler_latency",
+						Name:              "http.server.duration",
+						Description:       "Duration of the HTTP requests per route.",
+						Unit:              "s",
+						Attribute:         "http.route",
+						SumKey:            "total",
+						CountKey:          "n",
+						BucketsKey:        "le",
+						CumulativeBuckets: true,
+					},
+				},
+				RuntimeMetrics: RuntimeMetricsConfig{
+					Variable: defaultRuntimeMetricsVariable,
+					Endpoint: "http://localhost:8000/debug/runtime_metrics",
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "auth"),
+			expected: &Config{
+				ScraperControllerSettings: scraperhelper.NewDefaultScraperControllerSettings(typeStr),
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Endpoint: "https://proxy.example.com/debug/vars",
+					Timeout:  defaultTimeout,
+					Auth:     &configauth.Authentication{AuthenticatorID: component.NewIDWithName("bearertokenauth", "expvar")},
+				},
+				MetricsConfig: metadata.DefaultMetricsSettings(),
+				RuntimeMetrics: RuntimeMetricsConfig{
+					Variable: defaultRuntimeMetricsVariable,
+				},
 			},
 		},
 		{
@@ -75,6 +118,26 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(typeStr, "bad_invalid_url"),
 			errorMessage: "endpoint is not a valid URL: parse \"#$%^&*()_\": invalid URL escape \"%^&\"",
 		},
+		{
+			id:           component.NewIDWithName(typeStr, "bad_histogram_variable"),
+			errorMessage: "histograms[0]: variable must be specified",
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "bad_duplicate_histogram"),
+			errorMessage: "histograms[1]: duplicate metric name 'request_latency'",
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "bad_runtime_metrics_endpoint"),
+			errorMessage: "runtime_metrics: scheme must be 'http' or 'https', but was 'localhost'",
+		},
+		{
+			id:           component.NewIDWithName(typeStr, "bad_runtime_metrics_source"),
+			errorMessage: "runtime_metrics: variable or endpoint must be specified",
+		},
+		{
+			id:       component.NewIDWithName(typeStr, "runtime_metrics_disabled"),
+			expected: runtimeMetricsDisabledCfg,
+		},
 	}
 
 	for _, tt := range tests {
@@ -94,7 +157,7 @@ func TestLoadConfig(t *testing.T) {
 				return
 			}
 			assert.NoError(t, cfg.Validate())
-			if diff := cmp.Diff(tt.expected, cfg, cmpopts.IgnoreUnexported(config.ReceiverSettings{}, metadata.MetricSettings{})); diff != "" {
+			if diff := cmp.Diff(tt.expected, cfg, cmpopts.IgnoreUnexported(config.ReceiverSettings{}, metadata.MetricSettings{}), cmp.AllowUnexported(component.ID{})); diff != "" {
 				t.Errorf("Config mismatch (-expected +actual):\n%s", diff)
 			}
 		})
@@ -74,6 +74,7 @@ func newDefaultConfig() component.ReceiverConfig {
 			Endpoint: defaultEndpoint,
 			Timeout:  defaultTimeout,
 		},
-		MetricsConfig: metadata.DefaultMetricsSettings(),
+		MetricsConfig:  metadata.DefaultMetricsSettings(),
+		RuntimeMetrics: defaultRuntimeMetricsConfig(),
 	}
 }
@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvarreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/expvarreceiver"
+
+import (
+	"runtime"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// pauseBufferSize is the size of the PauseNs circular buffer of the memstats.
+const pauseBufferSize = uint32(len(runtime.MemStats{}.PauseNs))
+
+// recentPauses returns the number of GC cycles completed since the cycle prevNumGC and the pauses
+// of these cycles still held by the PauseNs circular buffer, at most its 256 most recent pauses.
+// All the cycles are counted when NumGC is lower than prevNumGC, i.e. the process restarted.
+func recentPauses(memStats *runtime.MemStats, prevNumGC uint32) (uint32, []uint64) {
+	count := memStats.NumGC - prevNumGC
+	if memStats.NumGC < prevNumGC {
+		count = memStats.NumGC
+	}
+	held := count
+	if held > pauseBufferSize {
+		held = pauseBufferSize
+	}
+	pauses := make([]uint64, 0, held)
+	for gc := memStats.NumGC - held + 1; gc <= memStats.NumGC; gc++ {
+		// The pause of the GC cycle n is at PauseNs[(n+255)%256].
+		pauses = append(pauses, memStats.PauseNs[(gc+pauseBufferSize-1)%pauseBufferSize])
+	}
+	return count, pauses
+}
+
+// recordRecentPauses records the count, max and average of the GC pauses since the previous
+// scrape, nothing is recorded on the first scrape.
+func (e *expVarScraper) recordRecentPauses(now pcommon.Timestamp, memStats *runtime.MemStats) {
+	prevNumGC, scraped := e.prevNumGC, e.scrapedNumGC
+	e.prevNumGC, e.scrapedNumGC = memStats.NumGC, true
+	if !scraped {
+		return
+	}
+
+	count, pauses := recentPauses(memStats, prevNumGC)
+	e.mb.RecordProcessRuntimeMemstatsRecentPauseCountDataPoint(now, int64(count))
+	if len(pauses) == 0 {
+		return
+	}
+	var max, total uint64
+	for _, pause := range pauses {
+		total += pause
+		if pause > max {
+			max = pause
+		}
+	}
+	e.mb.RecordProcessRuntimeMemstatsRecentPauseMaxDataPoint(now, int64(max))
+	e.mb.RecordProcessRuntimeMemstatsRecentPauseAvgDataPoint(now, float64(total)/float64(len(pauses)))
+}
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvarreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/expvarreceiver/internal/metadata"
+)
+
+// newMemStats returns the memstats after numGC cycles, the pause of the cycle n being n microseconds.
+func newMemStats(numGC uint32) *runtime.MemStats {
+	memStats := &runtime.MemStats{NumGC: numGC}
+	for gc := uint32(1); gc <= numGC; gc++ {
+		memStats.PauseNs[(gc+255)%256] = uint64(gc) * 1000
+	}
+	return memStats
+}
+
+func TestRecentPauses(t *testing.T) {
+	count, pauses := recentPauses(newMemStats(10), 7)
+	assert.Equal(t, uint32(3), count)
+	assert.Equal(t, []uint64{8000, 9000, 10000}, pauses)
+
+	count, pauses = recentPauses(newMemStats(10), 10)
+	assert.Equal(t, uint32(0), count)
+	assert.Empty(t, pauses)
+
+	// only the 256 most recent pauses are held by the buffer
+	count, pauses = recentPauses(newMemStats(1000), 500)
+	assert.Equal(t, uint32(500), count)
+	require.Len(t, pauses, 256)
+	assert.Equal(t, uint64(745000), pauses[0])
+	assert.Equal(t, uint64(1000000), pauses[255])
+
+	// the process restarted
+	count, pauses = recentPauses(newMemStats(2), 10)
+	assert.Equal(t, uint32(2), count)
+	assert.Equal(t, []uint64{1000, 2000}, pauses)
+}
+
+func TestScrapeRecentPauses(t *testing.T) {
+	var memStats *runtime.MemStats
+	ms := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.NoError(t, json.NewEncoder(rw).Encode(expVar{MemStats: memStats}))
+	}))
+	defer ms.Close()
+	cfg := newDefaultConfig().(*Config)
+	cfg.Endpoint = ms.URL + defaultPath
+	cfg.MetricsConfig = allMetricsDisabled
+	cfg.MetricsConfig.ProcessRuntimeMemstatsRecentPauseAvg = metadata.MetricSettings{Enabled: true}
+	cfg.MetricsConfig.ProcessRuntimeMemstatsRecentPauseCount = metadata.MetricSettings{Enabled: true}
+	cfg.MetricsConfig.ProcessRuntimeMemstatsRecentPauseMax = metadata.MetricSettings{Enabled: true}
+	scraper := newExpVarScraper(cfg, componenttest.NewNopReceiverCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	// nothing is recorded on the first scrape
+	memStats = newMemStats(5)
+	md, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, md.MetricCount())
+
+	memStats = newMemStats(8)
+	md, err = scraper.scrape(context.Background())
+	require.NoError(t, err)
+	values := gaugeValues(md)
+	assert.Equal(t, map[string]float64{
+		"process.runtime.memstats.recent_pause.avg":   7000,
+		"process.runtime.memstats.recent_pause.count": 3,
+		"process.runtime.memstats.recent_pause.max":   8000,
+	}, values)
+
+	// only the count is recorded without GC since the previous scrape
+	md, err = scraper.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"process.runtime.memstats.recent_pause.count": 0}, gaugeValues(md))
+}
+
+// gaugeValues returns the value of the single data point of each gauge.
+func gaugeValues(md pmetric.Metrics) map[string]float64 {
+	values := map[string]float64{}
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		dp := metrics.At(i).Gauge().DataPoints().At(0)
+		if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+			values[metrics.At(i).Name()] = float64(dp.IntValue())
+		} else {
+			values[metrics.At(i).Name()] = dp.DoubleValue()
+		}
+	}
+	return values
+}
@@ -0,0 +1,185 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvarreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/expvarreceiver"
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	defaultSumKey     = "sum"
+	defaultCountKey   = "count"
+	defaultBucketsKey = "buckets"
+)
+
+// histogramLayout is the decoded content of an expvar histogram, the buckets
+// are keyed by their upper bound.
+type histogramLayout struct {
+	sum     float64
+	count   uint64
+	buckets map[string]float64
+}
+
+// recordHistogram appends the histogram described by cfg, read from the raw
+// value of its expvar variable, to metrics.
+func recordHistogram(metrics pmetric.MetricSlice, cfg HistogramConfig, raw json.RawMessage, start, now pcommon.Timestamp) error {
+	var points map[string]json.RawMessage
+	if cfg.Attribute == "" {
+		points = map[string]json.RawMessage{"": raw}
+	} else if err := json.Unmarshal(raw, &points); err != nil {
+		return fmt.Errorf("expvar variable '%s' is not a map of histograms: %w", cfg.Variable, err)
+	}
+
+	m := pmetric.NewMetric()
+	m.SetName(cfg.metricName())
+	m.SetDescription(cfg.Description)
+	m.SetUnit(cfg.Unit)
+	hist := m.SetEmptyHistogram()
+	hist.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+	// Sort the entries of maps of histograms to emit the data points in a stable order.
+	keys := make([]string, 0, len(points))
+	for k := range points {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		layout, err := decodeHistogramLayout(cfg, points[k])
+		if err != nil {
+			return fmt.Errorf("expvar variable '%s': %w", cfg.Variable, err)
+		}
+		dp := hist.DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(start)
+		dp.SetTimestamp(now)
+		if cfg.Attribute != "" {
+			dp.Attributes().PutStr(cfg.Attribute, k)
+		}
+		if err := fillHistogramDataPoint(dp, layout, cfg.CumulativeBuckets); err != nil {
+			return fmt.Errorf("expvar variable '%s': %w", cfg.Variable, err)
+		}
+	}
+
+	m.MoveTo(metrics.AppendEmpty())
+	return nil
+}
+
+func decodeHistogramLayout(cfg HistogramConfig, raw json.RawMessage) (*histogramLayout, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("histogram is not a map: %w", err)
+	}
+
+	sumKey := keyOrDefault(cfg.SumKey, defaultSumKey)
+	countKey := keyOrDefault(cfg.CountKey, defaultCountKey)
+	bucketsKey := keyOrDefault(cfg.BucketsKey, defaultBucketsKey)
+
+	layout := &histogramLayout{}
+	if err := unmarshalField(fields, sumKey, &layout.sum); err != nil {
+		return nil, err
+	}
+	var count float64
+	if err := unmarshalField(fields, countKey, &count); err != nil {
+		return nil, err
+	}
+	if count < 0 || count != math.Trunc(count) {
+		return nil, fmt.Errorf("invalid histogram count %v", count)
+	}
+	layout.count = uint64(count)
+	if err := unmarshalField(fields, bucketsKey, &layout.buckets); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+func unmarshalField(fields map[string]json.RawMessage, key string, v interface{}) error {
+	raw, ok := fields[key]
+	if !ok {
+		return fmt.Errorf("histogram key '%s' not found", key)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid histogram key '%s': %w", key, err)
+	}
+	return nil
+}
+
+// fillHistogramDataPoint sets the sum, count, explicit bounds and bucket counts
+// of dp. A +Inf bucket is used as the overflow bucket, when missing the overflow
+// bucket holds the observations of count not falling in any bucket.
+func fillHistogramDataPoint(dp pmetric.HistogramDataPoint, layout *histogramLayout, cumulative bool) error {
+	type bucket struct {
+		bound float64
+		count float64
+	}
+	buckets := make([]bucket, 0, len(layout.buckets))
+	for k, v := range layout.buckets {
+		bound, err := strconv.ParseFloat(k, 64)
+		if err != nil || math.IsNaN(bound) {
+			return fmt.Errorf("invalid histogram bucket bound '%s'", k)
+		}
+		if v < 0 {
+			return fmt.Errorf("invalid histogram bucket count %v for bound '%s'", v, k)
+		}
+		buckets = append(buckets, bucket{bound: bound, count: v})
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].bound < buckets[j].bound })
+
+	bounds := make([]float64, 0, len(buckets))
+	counts := make([]uint64, 0, len(buckets)+1)
+	var total, previous float64
+	for _, b := range buckets {
+		c := b.count
+		if cumulative {
+			if c < previous {
+				return fmt.Errorf("cumulative histogram bucket count decreases at bound %v", b.bound)
+			}
+			c, previous = c-previous, c
+		}
+		total += c
+		if math.IsInf(b.bound, 1) {
+			// The +Inf bucket is the implicit overflow bucket of OTLP histograms.
+			counts = append(counts, uint64(c))
+			break
+		}
+		bounds = append(bounds, b.bound)
+		counts = append(counts, uint64(c))
+	}
+	if len(counts) == len(bounds) {
+		if total > float64(layout.count) {
+			return fmt.Errorf("histogram buckets hold %v observations but count is %d", total, layout.count)
+		}
+		counts = append(counts, layout.count-uint64(total))
+	}
+
+	dp.SetSum(layout.sum)
+	dp.SetCount(layout.count)
+	dp.ExplicitBounds().FromRaw(bounds)
+	dp.BucketCounts().FromRaw(counts)
+	return nil
+}
+
+func keyOrDefault(key, def string) string {
+	if key == "" {
+		return def
+	}
+	return key
+}
@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvarreceiver
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+)
+
+func TestFillHistogramDataPoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		layout     histogramLayout
+		cumulative bool
+		bounds     []float64
+		counts     []uint64
+		errMsg     string
+	}{
+		{
+			name:   "overflow bucket",
+			layout: histogramLayout{sum: 4, count: 6, buckets: map[string]float64{"1": 1, "0.5": 2, "+Inf": 3}},
+			bounds: []float64{0.5, 1},
+			counts: []uint64{2, 1, 3},
+		},
+		{
+			name:   "overflow bucket from count",
+			layout: histogramLayout{sum: 4, count: 6, buckets: map[string]float64{"0.5": 2, "1": 1}},
+			bounds: []float64{0.5, 1},
+			counts: []uint64{2, 1, 3},
+		},
+		{
+			name:       "cumulative buckets",
+			layout:     histogramLayout{sum: 4, count: 6, buckets: map[string]float64{"0.5": 2, "1": 3, "+Inf": 6}},
+			cumulative: true,
+			bounds:     []float64{0.5, 1},
+			counts:     []uint64{2, 1, 3},
+		},
+		{
+			name:       "decreasing cumulative buckets",
+			layout:     histogramLayout{count: 6, buckets: map[string]float64{"0.5": 4, "1": 3}},
+			cumulative: true,
+			errMsg:     "cumulative histogram bucket count decreases at bound 1",
+		},
+		{
+			name:   "buckets exceed count",
+			layout: histogramLayout{count: 2, buckets: map[string]float64{"0.5": 2, "1": 1}},
+			errMsg: "histogram buckets hold 3 observations but count is 2",
+		},
+		{
+			name:   "invalid bound",
+			layout: histogramLayout{count: 2, buckets: map[string]float64{"fast": 2}},
+			errMsg: "invalid histogram bucket bound 'fast'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dp := pmetric.NewHistogramDataPoint()
+			err := fillHistogramDataPoint(dp, &tt.layout, tt.cumulative)
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.layout.sum, dp.Sum())
+			assert.Equal(t, tt.layout.count, dp.Count())
+			assert.Equal(t, tt.bounds, dp.ExplicitBounds().AsRaw())
+			assert.Equal(t, tt.counts, dp.BucketCounts().AsRaw())
+		})
+	}
+}
+
+func TestScrapeHistograms(t *testing.T) {
+	ms := newMockServer(t, filepath.Join("testdata", "response", "histogram_response.json"))
+	defer ms.Close()
+	cfg := newDefaultConfig().(*Config)
+	cfg.Endpoint = ms.URL + defaultPath
+	cfg.MetricsConfig = allMetricsDisabled
+	cfg.Histograms = []HistogramConfig{
+		{
+			Variable: "request_latency",
+			Unit:     "s",
+		},
+		{
+			Variable:          "handler_latency",
+			Name:              "http.server.duration",
+			Attribute:         "http.route",
+			SumKey:            "total",
+			CountKey:          "n",
+			BucketsKey:        "le",
+			Unit:              "s",
+			CumulativeBuckets: true,
+		},
+	}
+
+	scraper := newExpVarScraper(cfg, componenttest.NewNopReceiverCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	md, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	requestLatency := metrics.At(0)
+	assert.Equal(t, "request_latency", requestLatency.Name())
+	assert.Equal(t, "s", requestLatency.Unit())
+	require.Equal(t, pmetric.MetricTypeHistogram, requestLatency.Type())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, requestLatency.Histogram().AggregationTemporality())
+	dp := requestLatency.Histogram().DataPoints().At(0)
+	assert.Equal(t, 12.5, dp.Sum())
+	assert.Equal(t, uint64(10), dp.Count())
+	assert.Equal(t, []float64{0.1, 1}, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{2, 5, 3}, dp.BucketCounts().AsRaw())
+
+	handlerLatency := metrics.At(1)
+	assert.Equal(t, "http.server.duration", handlerLatency.Name())
+	dps := handlerLatency.Histogram().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	route, _ := dps.At(0).Attributes().Get("http.route")
+	assert.Equal(t, "/api", route.Str())
+	assert.Equal(t, []uint64{3, 1, 0}, dps.At(0).BucketCounts().AsRaw())
+	route, _ = dps.At(1).Attributes().Get("http.route")
+	assert.Equal(t, "/health", route.Str())
+	assert.Equal(t, []uint64{2, 0, 0}, dps.At(1).BucketCounts().AsRaw())
+}
+
+func TestScrapeHistogramsPartialError(t *testing.T) {
+	ms := newMockServer(t, filepath.Join("testdata", "response", "histogram_response.json"))
+	defer ms.Close()
+	cfg := newDefaultConfig().(*Config)
+	cfg.Endpoint = ms.URL + defaultPath
+	cfg.MetricsConfig = allMetricsDisabled
+	cfg.Histograms = []HistogramConfig{
+		{Variable: "request_latency"},
+		{Variable: "missing_latency"},
+	}
+
+	scraper := newExpVarScraper(cfg, componenttest.NewNopReceiverCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	md, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	assert.Contains(t, err.Error(), "expvar variable 'missing_latency' not found")
+	assert.Equal(t, 1, md.MetricCount())
+}
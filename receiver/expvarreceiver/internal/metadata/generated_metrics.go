@@ -37,36 +37,44 @@ func (ms *MetricSettings) Unmarshal(parser *confmap.Conf) error {
 
 // MetricsSettings provides settings for expvarreceiver metrics.
 type MetricsSettings struct {
-	ProcessRuntimeMemstatsBuckHashSys   MetricSettings `mapstructure:"process.runtime.memstats.buck_hash_sys"`
-	ProcessRuntimeMemstatsFrees         MetricSettings `mapstructure:"process.runtime.memstats.frees"`
-	ProcessRuntimeMemstatsGcCPUFraction MetricSettings `mapstructure:"process.runtime.memstats.gc_cpu_fraction"`
-	ProcessRuntimeMemstatsGcSys         MetricSettings `mapstructure:"process.runtime.memstats.gc_sys"`
-	ProcessRuntimeMemstatsHeapAlloc     MetricSettings `mapstructure:"process.runtime.memstats.heap_alloc"`
-	ProcessRuntimeMemstatsHeapIdle      MetricSettings `mapstructure:"process.runtime.memstats.heap_idle"`
-	ProcessRuntimeMemstatsHeapInuse     MetricSettings `mapstructure:"process.runtime.memstats.heap_inuse"`
-	ProcessRuntimeMemstatsHeapObjects   MetricSettings `mapstructure:"process.runtime.memstats.heap_objects"`
-	ProcessRuntimeMemstatsHeapReleased  MetricSettings `mapstructure:"process.runtime.memstats.heap_released"`
-	ProcessRuntimeMemstatsHeapSys       MetricSettings `mapstructure:"process.runtime.memstats.heap_sys"`
-	ProcessRuntimeMemstatsLastPause     MetricSettings `mapstructure:"process.runtime.memstats.last_pause"`
-	ProcessRuntimeMemstatsLookups       MetricSettings `mapstructure:"process.runtime.memstats.lookups"`
-	ProcessRuntimeMemstatsMallocs       MetricSettings `mapstructure:"process.runtime.memstats.mallocs"`
-	ProcessRuntimeMemstatsMcacheInuse   MetricSettings `mapstructure:"process.runtime.memstats.mcache_inuse"`
-	ProcessRuntimeMemstatsMcacheSys     MetricSettings `mapstructure:"process.runtime.memstats.mcache_sys"`
-	ProcessRuntimeMemstatsMspanInuse    MetricSettings `mapstructure:"process.runtime.memstats.mspan_inuse"`
-	ProcessRuntimeMemstatsMspanSys      MetricSettings `mapstructure:"process.runtime.memstats.mspan_sys"`
-	ProcessRuntimeMemstatsNextGc        MetricSettings `mapstructure:"process.runtime.memstats.next_gc"`
-	ProcessRuntimeMemstatsNumForcedGc   MetricSettings `mapstructure:"process.runtime.memstats.num_forced_gc"`
-	ProcessRuntimeMemstatsNumGc         MetricSettings `mapstructure:"process.runtime.memstats.num_gc"`
-	ProcessRuntimeMemstatsOtherSys      MetricSettings `mapstructure:"process.runtime.memstats.other_sys"`
-	ProcessRuntimeMemstatsPauseTotal    MetricSettings `mapstructure:"process.runtime.memstats.pause_total"`
-	ProcessRuntimeMemstatsStackInuse    MetricSettings `mapstructure:"process.runtime.memstats.stack_inuse"`
-	ProcessRuntimeMemstatsStackSys      MetricSettings `mapstructure:"process.runtime.memstats.stack_sys"`
-	ProcessRuntimeMemstatsSys           MetricSettings `mapstructure:"process.runtime.memstats.sys"`
-	ProcessRuntimeMemstatsTotalAlloc    MetricSettings `mapstructure:"process.runtime.memstats.total_alloc"`
+	ProcessRuntimeGcPauses                 MetricSettings `mapstructure:"process.runtime.gc.pauses"`
+	ProcessRuntimeMemstatsBuckHashSys      MetricSettings `mapstructure:"process.runtime.memstats.buck_hash_sys"`
+	ProcessRuntimeMemstatsFrees            MetricSettings `mapstructure:"process.runtime.memstats.frees"`
+	ProcessRuntimeMemstatsGcCPUFraction    MetricSettings `mapstructure:"process.runtime.memstats.gc_cpu_fraction"`
+	ProcessRuntimeMemstatsGcSys            MetricSettings `mapstructure:"process.runtime.memstats.gc_sys"`
+	ProcessRuntimeMemstatsHeapAlloc        MetricSettings `mapstructure:"process.runtime.memstats.heap_alloc"`
+	ProcessRuntimeMemstatsHeapIdle         MetricSettings `mapstructure:"process.runtime.memstats.heap_idle"`
+	ProcessRuntimeMemstatsHeapInuse        MetricSettings `mapstructure:"process.runtime.memstats.heap_inuse"`
+	ProcessRuntimeMemstatsHeapObjects      MetricSettings `mapstructure:"process.runtime.memstats.heap_objects"`
+	ProcessRuntimeMemstatsHeapReleased     MetricSettings `mapstructure:"process.runtime.memstats.heap_released"`
+	ProcessRuntimeMemstatsHeapSys          MetricSettings `mapstructure:"process.runtime.memstats.heap_sys"`
+	ProcessRuntimeMemstatsLastPause        MetricSettings `mapstructure:"process.runtime.memstats.last_pause"`
+	ProcessRuntimeMemstatsLookups          MetricSettings `mapstructure:"process.runtime.memstats.lookups"`
+	ProcessRuntimeMemstatsMallocs          MetricSettings `mapstructure:"process.runtime.memstats.mallocs"`
+	ProcessRuntimeMemstatsMcacheInuse      MetricSettings `mapstructure:"process.runtime.memstats.mcache_inuse"`
+	ProcessRuntimeMemstatsMcacheSys        MetricSettings `mapstructure:"process.runtime.memstats.mcache_sys"`
+	ProcessRuntimeMemstatsMspanInuse       MetricSettings `mapstructure:"process.runtime.memstats.mspan_inuse"`
+	ProcessRuntimeMemstatsMspanSys         MetricSettings `mapstructure:"process.runtime.memstats.mspan_sys"`
+	ProcessRuntimeMemstatsNextGc           MetricSettings `mapstructure:"process.runtime.memstats.next_gc"`
+	ProcessRuntimeMemstatsNumForcedGc      MetricSettings `mapstructure:"process.runtime.memstats.num_forced_gc"`
+	ProcessRuntimeMemstatsNumGc            MetricSettings `mapstructure:"process.runtime.memstats.num_gc"`
+	ProcessRuntimeMemstatsOtherSys         MetricSettings `mapstructure:"process.runtime.memstats.other_sys"`
+	ProcessRuntimeMemstatsPauseTotal       MetricSettings `mapstructure:"process.runtime.memstats.pause_total"`
+	ProcessRuntimeMemstatsRecentPauseAvg   MetricSettings `mapstructure:"process.runtime.memstats.recent_pause.avg"`
+	ProcessRuntimeMemstatsRecentPauseCount MetricSettings `mapstructure:"process.runtime.memstats.recent_pause.count"`
+	ProcessRuntimeMemstatsRecentPauseMax   MetricSettings `mapstructure:"process.runtime.memstats.recent_pause.max"`
+	ProcessRuntimeMemstatsStackInuse       MetricSettings `mapstructure:"process.runtime.memstats.stack_inuse"`
+	ProcessRuntimeMemstatsStackSys         MetricSettings `mapstructure:"process.runtime.memstats.stack_sys"`
+	ProcessRuntimeMemstatsSys              MetricSettings `mapstructure:"process.runtime.memstats.sys"`
+	ProcessRuntimeMemstatsTotalAlloc       MetricSettings `mapstructure:"process.runtime.memstats.total_alloc"`
+	ProcessRuntimeSchedLatencies           MetricSettings `mapstructure:"process.runtime.sched.latencies"`
 }
 
 func DefaultMetricsSettings() MetricsSettings {
 	return MetricsSettings{
+		ProcessRuntimeGcPauses: MetricSettings{
+			Enabled: true,
+		},
 		ProcessRuntimeMemstatsBuckHashSys: MetricSettings{
 			Enabled: true,
 		},
@@ -133,6 +141,15 @@ func DefaultMetricsSettings() MetricsSettings {
 		ProcessRuntimeMemstatsPauseTotal: MetricSettings{
 			Enabled: true,
 		},
+		ProcessRuntimeMemstatsRecentPauseAvg: MetricSettings{
+			Enabled: false,
+		},
+		ProcessRuntimeMemstatsRecentPauseCount: MetricSettings{
+			Enabled: false,
+		},
+		ProcessRuntimeMemstatsRecentPauseMax: MetricSettings{
+			Enabled: false,
+		},
 		ProcessRuntimeMemstatsStackInuse: MetricSettings{
 			Enabled: true,
 		},
@@ -145,9 +162,62 @@ func DefaultMetricsSettings() MetricsSettings {
 		ProcessRuntimeMemstatsTotalAlloc: MetricSettings{
 			Enabled: false,
 		},
+		ProcessRuntimeSchedLatencies: MetricSettings{
+			Enabled: true,
+		},
+	}
+}
+
+type metricProcessRuntimeGcPauses struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills process.runtime.gc.pauses metric with initial data.
+func (m *metricProcessRuntimeGcPauses) init() {
+	m.data.SetName("process.runtime.gc.pauses")
+	m.data.SetDescription("Distribution of individual GC-related stop-the-world pause latencies.")
+	m.data.SetUnit("s")
+	m.data.SetEmptyHistogram()
+	m.data.Histogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+}
+
+func (m *metricProcessRuntimeGcPauses) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val pmetric.HistogramDataPoint) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Histogram().DataPoints().AppendEmpty()
+	val.CopyTo(dp)
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricProcessRuntimeGcPauses) updateCapacity() {
+	if m.data.Histogram().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Histogram().DataPoints().Len()
 	}
 }
 
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricProcessRuntimeGcPauses) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Histogram().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricProcessRuntimeGcPauses(settings MetricSettings) metricProcessRuntimeGcPauses {
+	m := metricProcessRuntimeGcPauses{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricProcessRuntimeMemstatsBuckHashSys struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -1266,6 +1336,153 @@ func newMetricProcessRuntimeMemstatsPauseTotal(settings MetricSettings) metricPr
 	return m
 }
 
+type metricProcessRuntimeMemstatsRecentPauseAvg struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills process.runtime.memstats.recent_pause.avg metric with initial data.
+func (m *metricProcessRuntimeMemstatsRecentPauseAvg) init() {
+	m.data.SetName("process.runtime.memstats.recent_pause.avg")
+	m.data.SetDescription("The average GC stop-the-world pause since the previous scrape.")
+	m.data.SetUnit("ns")
+	m.data.SetEmptyGauge()
+}
+
+func (m *metricProcessRuntimeMemstatsRecentPauseAvg) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricProcessRuntimeMemstatsRecentPauseAvg) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricProcessRuntimeMemstatsRecentPauseAvg) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricProcessRuntimeMemstatsRecentPauseAvg(settings MetricSettings) metricProcessRuntimeMemstatsRecentPauseAvg {
+	m := metricProcessRuntimeMemstatsRecentPauseAvg{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricProcessRuntimeMemstatsRecentPauseCount struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills process.runtime.memstats.recent_pause.count metric with initial data.
+func (m *metricProcessRuntimeMemstatsRecentPauseCount) init() {
+	m.data.SetName("process.runtime.memstats.recent_pause.count")
+	m.data.SetDescription("Number of GC stop-the-world pauses since the previous scrape.")
+	m.data.SetUnit("{pauses}")
+	m.data.SetEmptyGauge()
+}
+
+func (m *metricProcessRuntimeMemstatsRecentPauseCount) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricProcessRuntimeMemstatsRecentPauseCount) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricProcessRuntimeMemstatsRecentPauseCount) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricProcessRuntimeMemstatsRecentPauseCount(settings MetricSettings) metricProcessRuntimeMemstatsRecentPauseCount {
+	m := metricProcessRuntimeMemstatsRecentPauseCount{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricProcessRuntimeMemstatsRecentPauseMax struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills process.runtime.memstats.recent_pause.max metric with initial data.
+func (m *metricProcessRuntimeMemstatsRecentPauseMax) init() {
+	m.data.SetName("process.runtime.memstats.recent_pause.max")
+	m.data.SetDescription("The longest GC stop-the-world pause since the previous scrape.")
+	m.data.SetUnit("ns")
+	m.data.SetEmptyGauge()
+}
+
+func (m *metricProcessRuntimeMemstatsRecentPauseMax) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricProcessRuntimeMemstatsRecentPauseMax) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricProcessRuntimeMemstatsRecentPauseMax) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricProcessRuntimeMemstatsRecentPauseMax(settings MetricSettings) metricProcessRuntimeMemstatsRecentPauseMax {
+	m := metricProcessRuntimeMemstatsRecentPauseMax{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricProcessRuntimeMemstatsStackInuse struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -1470,40 +1687,95 @@ func newMetricProcessRuntimeMemstatsTotalAlloc(settings MetricSettings) metricPr
 	return m
 }
 
+type metricProcessRuntimeSchedLatencies struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills process.runtime.sched.latencies metric with initial data.
+func (m *metricProcessRuntimeSchedLatencies) init() {
+	m.data.SetName("process.runtime.sched.latencies")
+	m.data.SetDescription("Distribution of the time goroutines have spent in the scheduler in a runnable state before actually running.")
+	m.data.SetUnit("s")
+	m.data.SetEmptyHistogram()
+	m.data.Histogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+}
+
+func (m *metricProcessRuntimeSchedLatencies) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val pmetric.HistogramDataPoint) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Histogram().DataPoints().AppendEmpty()
+	val.CopyTo(dp)
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricProcessRuntimeSchedLatencies) updateCapacity() {
+	if m.data.Histogram().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Histogram().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricProcessRuntimeSchedLatencies) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Histogram().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricProcessRuntimeSchedLatencies(settings MetricSettings) metricProcessRuntimeSchedLatencies {
+	m := metricProcessRuntimeSchedLatencies{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 // MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
 // required to produce metric representation defined in metadata and user settings.
 type MetricsBuilder struct {
-	startTime                                 pcommon.Timestamp   // start time that will be applied to all recorded data points.
-	metricsCapacity                           int                 // maximum observed number of metrics per resource.
-	resourceCapacity                          int                 // maximum observed number of resource attributes.
-	metricsBuffer                             pmetric.Metrics     // accumulates metrics data before emitting.
-	buildInfo                                 component.BuildInfo // contains version information
-	metricProcessRuntimeMemstatsBuckHashSys   metricProcessRuntimeMemstatsBuckHashSys
-	metricProcessRuntimeMemstatsFrees         metricProcessRuntimeMemstatsFrees
-	metricProcessRuntimeMemstatsGcCPUFraction metricProcessRuntimeMemstatsGcCPUFraction
-	metricProcessRuntimeMemstatsGcSys         metricProcessRuntimeMemstatsGcSys
-	metricProcessRuntimeMemstatsHeapAlloc     metricProcessRuntimeMemstatsHeapAlloc
-	metricProcessRuntimeMemstatsHeapIdle      metricProcessRuntimeMemstatsHeapIdle
-	metricProcessRuntimeMemstatsHeapInuse     metricProcessRuntimeMemstatsHeapInuse
-	metricProcessRuntimeMemstatsHeapObjects   metricProcessRuntimeMemstatsHeapObjects
-	metricProcessRuntimeMemstatsHeapReleased  metricProcessRuntimeMemstatsHeapReleased
-	metricProcessRuntimeMemstatsHeapSys       metricProcessRuntimeMemstatsHeapSys
-	metricProcessRuntimeMemstatsLastPause     metricProcessRuntimeMemstatsLastPause
-	metricProcessRuntimeMemstatsLookups       metricProcessRuntimeMemstatsLookups
-	metricProcessRuntimeMemstatsMallocs       metricProcessRuntimeMemstatsMallocs
-	metricProcessRuntimeMemstatsMcacheInuse   metricProcessRuntimeMemstatsMcacheInuse
-	metricProcessRuntimeMemstatsMcacheSys     metricProcessRuntimeMemstatsMcacheSys
-	metricProcessRuntimeMemstatsMspanInuse    metricProcessRuntimeMemstatsMspanInuse
-	metricProcessRuntimeMemstatsMspanSys      metricProcessRuntimeMemstatsMspanSys
-	metricProcessRuntimeMemstatsNextGc        metricProcessRuntimeMemstatsNextGc
-	metricProcessRuntimeMemstatsNumForcedGc   metricProcessRuntimeMemstatsNumForcedGc
-	metricProcessRuntimeMemstatsNumGc         metricProcessRuntimeMemstatsNumGc
-	metricProcessRuntimeMemstatsOtherSys      metricProcessRuntimeMemstatsOtherSys
-	metricProcessRuntimeMemstatsPauseTotal    metricProcessRuntimeMemstatsPauseTotal
-	metricProcessRuntimeMemstatsStackInuse    metricProcessRuntimeMemstatsStackInuse
-	metricProcessRuntimeMemstatsStackSys      metricProcessRuntimeMemstatsStackSys
-	metricProcessRuntimeMemstatsSys           metricProcessRuntimeMemstatsSys
-	metricProcessRuntimeMemstatsTotalAlloc    metricProcessRuntimeMemstatsTotalAlloc
+	startTime                                    pcommon.Timestamp   // start time that will be applied to all recorded data points.
+	metricsCapacity                              int                 // maximum observed number of metrics per resource.
+	resourceCapacity                             int                 // maximum observed number of resource attributes.
+	metricsBuffer                                pmetric.Metrics     // accumulates metrics data before emitting.
+	buildInfo                                    component.BuildInfo // contains version information
+	metricProcessRuntimeGcPauses                 metricProcessRuntimeGcPauses
+	metricProcessRuntimeMemstatsBuckHashSys      metricProcessRuntimeMemstatsBuckHashSys
+	metricProcessRuntimeMemstatsFrees            metricProcessRuntimeMemstatsFrees
+	metricProcessRuntimeMemstatsGcCPUFraction    metricProcessRuntimeMemstatsGcCPUFraction
+	metricProcessRuntimeMemstatsGcSys            metricProcessRuntimeMemstatsGcSys
+	metricProcessRuntimeMemstatsHeapAlloc        metricProcessRuntimeMemstatsHeapAlloc
+	metricProcessRuntimeMemstatsHeapIdle         metricProcessRuntimeMemstatsHeapIdle
+	metricProcessRuntimeMemstatsHeapInuse        metricProcessRuntimeMemstatsHeapInuse
+	metricProcessRuntimeMemstatsHeapObjects      metricProcessRuntimeMemstatsHeapObjects
+	metricProcessRuntimeMemstatsHeapReleased     metricProcessRuntimeMemstatsHeapReleased
+	metricProcessRuntimeMemstatsHeapSys          metricProcessRuntimeMemstatsHeapSys
+	metricProcessRuntimeMemstatsLastPause        metricProcessRuntimeMemstatsLastPause
+	metricProcessRuntimeMemstatsLookups          metricProcessRuntimeMemstatsLookups
+	metricProcessRuntimeMemstatsMallocs          metricProcessRuntimeMemstatsMallocs
+	metricProcessRuntimeMemstatsMcacheInuse      metricProcessRuntimeMemstatsMcacheInuse
+	metricProcessRuntimeMemstatsMcacheSys        metricProcessRuntimeMemstatsMcacheSys
+	metricProcessRuntimeMemstatsMspanInuse       metricProcessRuntimeMemstatsMspanInuse
+	metricProcessRuntimeMemstatsMspanSys         metricProcessRuntimeMemstatsMspanSys
+	metricProcessRuntimeMemstatsNextGc           metricProcessRuntimeMemstatsNextGc
+	metricProcessRuntimeMemstatsNumForcedGc      metricProcessRuntimeMemstatsNumForcedGc
+	metricProcessRuntimeMemstatsNumGc            metricProcessRuntimeMemstatsNumGc
+	metricProcessRuntimeMemstatsOtherSys         metricProcessRuntimeMemstatsOtherSys
+	metricProcessRuntimeMemstatsPauseTotal       metricProcessRuntimeMemstatsPauseTotal
+	metricProcessRuntimeMemstatsRecentPauseAvg   metricProcessRuntimeMemstatsRecentPauseAvg
+	metricProcessRuntimeMemstatsRecentPauseCount metricProcessRuntimeMemstatsRecentPauseCount
+	metricProcessRuntimeMemstatsRecentPauseMax   metricProcessRuntimeMemstatsRecentPauseMax
+	metricProcessRuntimeMemstatsStackInuse       metricProcessRuntimeMemstatsStackInuse
+	metricProcessRuntimeMemstatsStackSys         metricProcessRuntimeMemstatsStackSys
+	metricProcessRuntimeMemstatsSys              metricProcessRuntimeMemstatsSys
+	metricProcessRuntimeMemstatsTotalAlloc       metricProcessRuntimeMemstatsTotalAlloc
+	metricProcessRuntimeSchedLatencies           metricProcessRuntimeSchedLatencies
 }
 
 // metricBuilderOption applies changes to default metrics builder.
@@ -1518,35 +1790,40 @@ func WithStartTime(startTime pcommon.Timestamp) metricBuilderOption {
 
 func NewMetricsBuilder(settings MetricsSettings, buildInfo component.BuildInfo, options ...metricBuilderOption) *MetricsBuilder {
 	mb := &MetricsBuilder{
-		startTime:                                 pcommon.NewTimestampFromTime(time.Now()),
-		metricsBuffer:                             pmetric.NewMetrics(),
-		buildInfo:                                 buildInfo,
-		metricProcessRuntimeMemstatsBuckHashSys:   newMetricProcessRuntimeMemstatsBuckHashSys(settings.ProcessRuntimeMemstatsBuckHashSys),
-		metricProcessRuntimeMemstatsFrees:         newMetricProcessRuntimeMemstatsFrees(settings.ProcessRuntimeMemstatsFrees),
-		metricProcessRuntimeMemstatsGcCPUFraction: newMetricProcessRuntimeMemstatsGcCPUFraction(settings.ProcessRuntimeMemstatsGcCPUFraction),
-		metricProcessRuntimeMemstatsGcSys:         newMetricProcessRuntimeMemstatsGcSys(settings.ProcessRuntimeMemstatsGcSys),
-		metricProcessRuntimeMemstatsHeapAlloc:     newMetricProcessRuntimeMemstatsHeapAlloc(settings.ProcessRuntimeMemstatsHeapAlloc),
-		metricProcessRuntimeMemstatsHeapIdle:      newMetricProcessRuntimeMemstatsHeapIdle(settings.ProcessRuntimeMemstatsHeapIdle),
-		metricProcessRuntimeMemstatsHeapInuse:     newMetricProcessRuntimeMemstatsHeapInuse(settings.ProcessRuntimeMemstatsHeapInuse),
-		metricProcessRuntimeMemstatsHeapObjects:   newMetricProcessRuntimeMemstatsHeapObjects(settings.ProcessRuntimeMemstatsHeapObjects),
-		metricProcessRuntimeMemstatsHeapReleased:  newMetricProcessRuntimeMemstatsHeapReleased(settings.ProcessRuntimeMemstatsHeapReleased),
-		metricProcessRuntimeMemstatsHeapSys:       newMetricProcessRuntimeMemstatsHeapSys(settings.ProcessRuntimeMemstatsHeapSys),
-		metricProcessRuntimeMemstatsLastPause:     newMetricProcessRuntimeMemstatsLastPause(settings.ProcessRuntimeMemstatsLastPause),
-		metricProcessRuntimeMemstatsLookups:       newMetricProcessRuntimeMemstatsLookups(settings.ProcessRuntimeMemstatsLookups),
-		metricProcessRuntimeMemstatsMallocs:       newMetricProcessRuntimeMemstatsMallocs(settings.ProcessRuntimeMemstatsMallocs),
-		metricProcessRuntimeMemstatsMcacheInuse:   newMetricProcessRuntimeMemstatsMcacheInuse(settings.ProcessRuntimeMemstatsMcacheInuse),
-		metricProcessRuntimeMemstatsMcacheSys:     newMetricProcessRuntimeMemstatsMcacheSys(settings.ProcessRuntimeMemstatsMcacheSys),
-		metricProcessRuntimeMemstatsMspanInuse:    newMetricProcessRuntimeMemstatsMspanInuse(settings.ProcessRuntimeMemstatsMspanInuse),
-		metricProcessRuntimeMemstatsMspanSys:      newMetricProcessRuntimeMemstatsMspanSys(settings.ProcessRuntimeMemstatsMspanSys),
-		metricProcessRuntimeMemstatsNextGc:        newMetricProcessRuntimeMemstatsNextGc(settings.ProcessRuntimeMemstatsNextGc),
-		metricProcessRuntimeMemstatsNumForcedGc:   newMetricProcessRuntimeMemstatsNumForcedGc(settings.ProcessRuntimeMemstatsNumForcedGc),
-		metricProcessRuntimeMemstatsNumGc:         newMetricProcessRuntimeMemstatsNumGc(settings.ProcessRuntimeMemstatsNumGc),
-		metricProcessRuntimeMemstatsOtherSys:      newMetricProcessRuntimeMemstatsOtherSys(settings.ProcessRuntimeMemstatsOtherSys),
-		metricProcessRuntimeMemstatsPauseTotal:    newMetricProcessRuntimeMemstatsPauseTotal(settings.ProcessRuntimeMemstatsPauseTotal),
-		metricProcessRuntimeMemstatsStackInuse:    newMetricProcessRuntimeMemstatsStackInuse(settings.ProcessRuntimeMemstatsStackInuse),
-		metricProcessRuntimeMemstatsStackSys:      newMetricProcessRuntimeMemstatsStackSys(settings.ProcessRuntimeMemstatsStackSys),
-		metricProcessRuntimeMemstatsSys:           newMetricProcessRuntimeMemstatsSys(settings.ProcessRuntimeMemstatsSys),
-		metricProcessRuntimeMemstatsTotalAlloc:    newMetricProcessRuntimeMemstatsTotalAlloc(settings.ProcessRuntimeMemstatsTotalAlloc),
+		startTime:                                    pcommon.NewTimestampFromTime(time.Now()),
+		metricsBuffer:                                pmetric.NewMetrics(),
+		buildInfo:                                    buildInfo,
+		metricProcessRuntimeGcPauses:                 newMetricProcessRuntimeGcPauses(settings.ProcessRuntimeGcPauses),
+		metricProcessRuntimeMemstatsBuckHashSys:      newMetricProcessRuntimeMemstatsBuckHashSys(settings.ProcessRuntimeMemstatsBuckHashSys),
+		metricProcessRuntimeMemstatsFrees:            newMetricProcessRuntimeMemstatsFrees(settings.ProcessRuntimeMemstatsFrees),
+		metricProcessRuntimeMemstatsGcCPUFraction:    newMetricProcessRuntimeMemstatsGcCPUFraction(settings.ProcessRuntimeMemstatsGcCPUFraction),
+		metricProcessRuntimeMemstatsGcSys:            newMetricProcessRuntimeMemstatsGcSys(settings.ProcessRuntimeMemstatsGcSys),
+		metricProcessRuntimeMemstatsHeapAlloc:        newMetricProcessRuntimeMemstatsHeapAlloc(settings.ProcessRuntimeMemstatsHeapAlloc),
+		metricProcessRuntimeMemstatsHeapIdle:         newMetricProcessRuntimeMemstatsHeapIdle(settings.ProcessRuntimeMemstatsHeapIdle),
+		metricProcessRuntimeMemstatsHeapInuse:        newMetricProcessRuntimeMemstatsHeapInuse(settings.ProcessRuntimeMemstatsHeapInuse),
+		metricProcessRuntimeMemstatsHeapObjects:      newMetricProcessRuntimeMemstatsHeapObjects(settings.ProcessRuntimeMemstatsHeapObjects),
+		metricProcessRuntimeMemstatsHeapReleased:     newMetricProcessRuntimeMemstatsHeapReleased(settings.ProcessRuntimeMemstatsHeapReleased),
+		metricProcessRuntimeMemstatsHeapSys:          newMetricProcessRuntimeMemstatsHeapSys(settings.ProcessRuntimeMemstatsHeapSys),
+		metricProcessRuntimeMemstatsLastPause:        newMetricProcessRuntimeMemstatsLastPause(settings.ProcessRuntimeMemstatsLastPause),
+		metricProcessRuntimeMemstatsLookups:          newMetricProcessRuntimeMemstatsLookups(settings.ProcessRuntimeMemstatsLookups),
+		metricProcessRuntimeMemstatsMallocs:          newMetricProcessRuntimeMemstatsMallocs(settings.ProcessRuntimeMemstatsMallocs),
+		metricProcessRuntimeMemstatsMcacheInuse:      newMetricProcessRuntimeMemstatsMcacheInuse(settings.ProcessRuntimeMemstatsMcacheInuse),
+		metricProcessRuntimeMemstatsMcacheSys:        newMetricProcessRuntimeMemstatsMcacheSys(settings.ProcessRuntimeMemstatsMcacheSys),
+		metricProcessRuntimeMemstatsMspanInuse:       newMetricProcessRuntimeMemstatsMspanInuse(settings.ProcessRuntimeMemstatsMspanInuse),
+		metricProcessRuntimeMemstatsMspanSys:         newMetricProcessRuntimeMemstatsMspanSys(settings.ProcessRuntimeMemstatsMspanSys),
+		metricProcessRuntimeMemstatsNextGc:           newMetricProcessRuntimeMemstatsNextGc(settings.ProcessRuntimeMemstatsNextGc),
+		metricProcessRuntimeMemstatsNumForcedGc:      newMetricProcessRuntimeMemstatsNumForcedGc(settings.ProcessRuntimeMemstatsNumForcedGc),
+		metricProcessRuntimeMemstatsNumGc:            newMetricProcessRuntimeMemstatsNumGc(settings.ProcessRuntimeMemstatsNumGc),
+		metricProcessRuntimeMemstatsOtherSys:         newMetricProcessRuntimeMemstatsOtherSys(settings.ProcessRuntimeMemstatsOtherSys),
+		metricProcessRuntimeMemstatsPauseTotal:       newMetricProcessRuntimeMemstatsPauseTotal(settings.ProcessRuntimeMemstatsPauseTotal),
+		metricProcessRuntimeMemstatsRecentPauseAvg:   newMetricProcessRuntimeMemstatsRecentPauseAvg(settings.ProcessRuntimeMemstatsRecentPauseAvg),
+		metricProcessRuntimeMemstatsRecentPauseCount: newMetricProcessRuntimeMemstatsRecentPauseCount(settings.ProcessRuntimeMemstatsRecentPauseCount),
+		metricProcessRuntimeMemstatsRecentPauseMax:   newMetricProcessRuntimeMemstatsRecentPauseMax(settings.ProcessRuntimeMemstatsRecentPauseMax),
+		metricProcessRuntimeMemstatsStackInuse:       newMetricProcessRuntimeMemstatsStackInuse(settings.ProcessRuntimeMemstatsStackInuse),
+		metricProcessRuntimeMemstatsStackSys:         newMetricProcessRuntimeMemstatsStackSys(settings.ProcessRuntimeMemstatsStackSys),
+		metricProcessRuntimeMemstatsSys:              newMetricProcessRuntimeMemstatsSys(settings.ProcessRuntimeMemstatsSys),
+		metricProcessRuntimeMemstatsTotalAlloc:       newMetricProcessRuntimeMemstatsTotalAlloc(settings.ProcessRuntimeMemstatsTotalAlloc),
+		metricProcessRuntimeSchedLatencies:           newMetricProcessRuntimeSchedLatencies(settings.ProcessRuntimeSchedLatencies),
 	}
 	for _, op := range options {
 		op(mb)
@@ -1579,6 +1856,12 @@ func WithStartTimeOverride(start pcommon.Timestamp) ResourceMetricsOption {
 				dps = metrics.At(i).Gauge().DataPoints()
 			case pmetric.MetricTypeSum:
 				dps = metrics.At(i).Sum().DataPoints()
+			case pmetric.MetricTypeHistogram:
+				hdps := metrics.At(i).Histogram().DataPoints()
+				for j := 0; j < hdps.Len(); j++ {
+					hdps.At(j).SetStartTimestamp(start)
+				}
+				continue
 			}
 			for j := 0; j < dps.Len(); j++ {
 				dps.At(j).SetStartTimestamp(start)
@@ -1599,6 +1882,7 @@ func (mb *MetricsBuilder) EmitForResource(rmo ...ResourceMetricsOption) {
 	ils.Scope().SetName("otelcol/expvarreceiver")
 	ils.Scope().SetVersion(mb.buildInfo.Version)
 	ils.Metrics().EnsureCapacity(mb.metricsCapacity)
+	mb.metricProcessRuntimeGcPauses.emit(ils.Metrics())
 	mb.metricProcessRuntimeMemstatsBuckHashSys.emit(ils.Metrics())
 	mb.metricProcessRuntimeMemstatsFrees.emit(ils.Metrics())
 	mb.metricProcessRuntimeMemstatsGcCPUFraction.emit(ils.Metrics())
@@ -1621,10 +1905,14 @@ func (mb *MetricsBuilder) EmitForResource(rmo ...ResourceMetricsOption) {
 	mb.metricProcessRuntimeMemstatsNumGc.emit(ils.Metrics())
 	mb.metricProcessRuntimeMemstatsOtherSys.emit(ils.Metrics())
 	mb.metricProcessRuntimeMemstatsPauseTotal.emit(ils.Metrics())
+	mb.metricProcessRuntimeMemstatsRecentPauseAvg.emit(ils.Metrics())
+	mb.metricProcessRuntimeMemstatsRecentPauseCount.emit(ils.Metrics())
+	mb.metricProcessRuntimeMemstatsRecentPauseMax.emit(ils.Metrics())
 	mb.metricProcessRuntimeMemstatsStackInuse.emit(ils.Metrics())
 	mb.metricProcessRuntimeMemstatsStackSys.emit(ils.Metrics())
 	mb.metricProcessRuntimeMemstatsSys.emit(ils.Metrics())
 	mb.metricProcessRuntimeMemstatsTotalAlloc.emit(ils.Metrics())
+	mb.metricProcessRuntimeSchedLatencies.emit(ils.Metrics())
 	for _, op := range rmo {
 		op(rm)
 	}
@@ -1644,6 +1932,11 @@ func (mb *MetricsBuilder) Emit(rmo ...ResourceMetricsOption) pmetric.Metrics {
 	return metrics
 }
 
+// RecordProcessRuntimeGcPausesDataPoint adds a data point to process.runtime.gc.pauses metric.
+func (mb *MetricsBuilder) RecordProcessRuntimeGcPausesDataPoint(ts pcommon.Timestamp, val pmetric.HistogramDataPoint) {
+	mb.metricProcessRuntimeGcPauses.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordProcessRuntimeMemstatsBuckHashSysDataPoint adds a data point to process.runtime.memstats.buck_hash_sys metric.
 func (mb *MetricsBuilder) RecordProcessRuntimeMemstatsBuckHashSysDataPoint(ts pcommon.Timestamp, val int64) {
 	mb.metricProcessRuntimeMemstatsBuckHashSys.recordDataPoint(mb.startTime, ts, val)
@@ -1754,6 +2047,21 @@ func (mb *MetricsBuilder) RecordProcessRuntimeMemstatsPauseTotalDataPoint(ts pco
 	mb.metricProcessRuntimeMemstatsPauseTotal.recordDataPoint(mb.startTime, ts, val)
 }
 
+// RecordProcessRuntimeMemstatsRecentPauseAvgDataPoint adds a data point to process.runtime.memstats.recent_pause.avg metric.
+func (mb *MetricsBuilder) RecordProcessRuntimeMemstatsRecentPauseAvgDataPoint(ts pcommon.Timestamp, val float64) {
+	mb.metricProcessRuntimeMemstatsRecentPauseAvg.recordDataPoint(mb.startTime, ts, val)
+}
+
+// RecordProcessRuntimeMemstatsRecentPauseCountDataPoint adds a data point to process.runtime.memstats.recent_pause.count metric.
+func (mb *MetricsBuilder) RecordProcessRuntimeMemstatsRecentPauseCountDataPoint(ts pcommon.Timestamp, val int64) {
+	mb.metricProcessRuntimeMemstatsRecentPauseCount.recordDataPoint(mb.startTime, ts, val)
+}
+
+// RecordProcessRuntimeMemstatsRecentPauseMaxDataPoint adds a data point to process.runtime.memstats.recent_pause.max metric.
+func (mb *MetricsBuilder) RecordProcessRuntimeMemstatsRecentPauseMaxDataPoint(ts pcommon.Timestamp, val int64) {
+	mb.metricProcessRuntimeMemstatsRecentPauseMax.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordProcessRuntimeMemstatsStackInuseDataPoint adds a data point to process.runtime.memstats.stack_inuse metric.
 func (mb *MetricsBuilder) RecordProcessRuntimeMemstatsStackInuseDataPoint(ts pcommon.Timestamp, val int64) {
 	mb.metricProcessRuntimeMemstatsStackInuse.recordDataPoint(mb.startTime, ts, val)
@@ -1774,6 +2082,11 @@ func (mb *MetricsBuilder) RecordProcessRuntimeMemstatsTotalAllocDataPoint(ts pco
 	mb.metricProcessRuntimeMemstatsTotalAlloc.recordDataPoint(mb.startTime, ts, val)
 }
 
+// RecordProcessRuntimeSchedLatenciesDataPoint adds a data point to process.runtime.sched.latencies metric.
+func (mb *MetricsBuilder) RecordProcessRuntimeSchedLatenciesDataPoint(ts pcommon.Timestamp, val pmetric.HistogramDataPoint) {
+	mb.metricProcessRuntimeSchedLatencies.recordDataPoint(mb.startTime, ts, val)
+}
+
 // Reset resets metrics builder to its initial state. It should be used when external metrics source is restarted,
 // and metrics builder should update its startTime and reset it's internal state accordingly.
 func (mb *MetricsBuilder) Reset(options ...metricBuilderOption) {
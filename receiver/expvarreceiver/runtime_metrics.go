@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvarreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/expvarreceiver"
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/expvarreceiver/internal/metadata"
+)
+
+const defaultRuntimeMetricsVariable = "runtime_metrics"
+
+// RuntimeMetricsConfig configures where the samples of the runtime/metrics package (Go 1.16+),
+// see https://pkg.go.dev/runtime/metrics, are read from. The histograms built from them are
+// enabled or disabled in the metrics settings like any other metric.
+type RuntimeMetricsConfig struct {
+	// Variable is the top-level expvar variable holding the samples, keyed by runtime/metrics name.
+	// The samples are only collected when the variable is present.
+	Variable string `mapstructure:"variable"`
+	// Endpoint is the URL of a sibling handler serving the samples, used instead of Variable when set.
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+func defaultRuntimeMetricsConfig() RuntimeMetricsConfig {
+	return RuntimeMetricsConfig{
+		Variable: defaultRuntimeMetricsVariable,
+	}
+}
+
+// runtimeHistogram associates a runtime/metrics histogram sample with the metric it is recorded in.
+type runtimeHistogram struct {
+	sample string
+	record func(mb *metadata.MetricsBuilder, ts pcommon.Timestamp, dp pmetric.HistogramDataPoint)
+}
+
+var (
+	schedLatencies = runtimeHistogram{
+		sample: "/sched/latencies:seconds",
+		record: (*metadata.MetricsBuilder).RecordProcessRuntimeSchedLatenciesDataPoint,
+	}
+	gcPauses = runtimeHistogram{
+		sample: "/gc/pauses:seconds",
+		record: (*metadata.MetricsBuilder).RecordProcessRuntimeGcPausesDataPoint,
+	}
+)
+
+// enabledRuntimeHistograms returns the runtime/metrics histograms enabled in the settings.
+func enabledRuntimeHistograms(settings metadata.MetricsSettings) []runtimeHistogram {
+	var histograms []runtimeHistogram
+	if settings.ProcessRuntimeSchedLatencies.Enabled {
+		histograms = append(histograms, schedLatencies)
+	}
+	if settings.ProcessRuntimeGcPauses.Enabled {
+		histograms = append(histograms, gcPauses)
+	}
+	return histograms
+}
+
+// float64Histogram is the JSON form of a runtime/metrics Float64Histogram. The bucket
+// boundaries may be -Inf and +Inf, they are then encoded as strings.
+type float64Histogram struct {
+	Counts  []uint64       `json:"counts"`
+	Buckets []runtimeBound `json:"buckets"`
+}
+
+type runtimeBound float64
+
+func (b *runtimeBound) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var f float64
+		if err = json.Unmarshal(data, &f); err != nil {
+			return err
+		}
+		*b = runtimeBound(f)
+		return nil
+	}
+	switch strings.ToLower(s) {
+	case "-inf":
+		*b = runtimeBound(math.Inf(-1))
+	case "+inf", "inf":
+		*b = runtimeBound(math.Inf(1))
+	default:
+		return fmt.Errorf("invalid bucket boundary '%s'", s)
+	}
+	return nil
+}
+
+// recordRuntimeHistogram records the histogram h, read from its runtime/metrics sample, in mb.
+func recordRuntimeHistogram(mb *metadata.MetricsBuilder, h runtimeHistogram, raw json.RawMessage, now pcommon.Timestamp) error {
+	var sample float64Histogram
+	if err := json.Unmarshal(raw, &sample); err != nil {
+		return fmt.Errorf("runtime/metrics sample '%s' is not a histogram: %w", h.sample, err)
+	}
+	if len(sample.Counts) == 0 || len(sample.Buckets) != len(sample.Counts)+1 {
+		return fmt.Errorf("runtime/metrics sample '%s' has %d bucket boundaries for %d counts", h.sample, len(sample.Buckets), len(sample.Counts))
+	}
+
+	dp := pmetric.NewHistogramDataPoint()
+	fillRuntimeHistogramDataPoint(dp, &sample)
+	h.record(mb, now, dp)
+	return nil
+}
+
+// fillRuntimeHistogramDataPoint sets the count, explicit bounds and bucket counts of dp. The
+// runtime/metrics buckets include their lower boundary, the OTLP ones their upper bound, the
+// inner boundaries are kept as is. The sum is unknown and left unset.
+func fillRuntimeHistogramDataPoint(dp pmetric.HistogramDataPoint, sample *float64Histogram) {
+	bounds := make([]float64, 0, len(sample.Counts))
+	for _, b := range sample.Buckets[1 : len(sample.Buckets)-1] {
+		bounds = append(bounds, float64(b))
+	}
+	var count uint64
+	for _, c := range sample.Counts {
+		count += c
+	}
+	dp.SetCount(count)
+	dp.ExplicitBounds().FromRaw(bounds)
+	dp.BucketCounts().FromRaw(sample.Counts)
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvarreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+)
+
+func TestScrapeRuntimeMetrics(t *testing.T) {
+	ms := newMockServer(t, filepath.Join("testdata", "response", "runtime_metrics_response.json"))
+	defer ms.Close()
+	cfg := newDefaultConfig().(*Config)
+	cfg.Endpoint = ms.URL + defaultPath
+	cfg.MetricsConfig = allMetricsDisabled
+	cfg.MetricsConfig.ProcessRuntimeSchedLatencies = metricEnabled
+	cfg.MetricsConfig.ProcessRuntimeGcPauses = metricEnabled
+
+	scraper := newExpVarScraper(cfg, componenttest.NewNopReceiverCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	md, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	latencies := metrics.At(1)
+	assert.Equal(t, "process.runtime.sched.latencies", latencies.Name())
+	assert.Equal(t, "s", latencies.Unit())
+	require.Equal(t, pmetric.MetricTypeHistogram, latencies.Type())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, latencies.Histogram().AggregationTemporality())
+	dp := latencies.Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(48), dp.Count())
+	assert.False(t, dp.HasSum())
+	assert.Equal(t, []float64{1e-06, 1e-05, 0.0001}, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{12, 30, 5, 1}, dp.BucketCounts().AsRaw())
+
+	pauses := metrics.At(0)
+	assert.Equal(t, "process.runtime.gc.pauses", pauses.Name())
+	dp = pauses.Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(5), dp.Count())
+	assert.Equal(t, []float64{1e-05, 0.0001}, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{0, 3, 2}, dp.BucketCounts().AsRaw())
+}
+
+func TestScrapeRuntimeMetricsDisabled(t *testing.T) {
+	ms := newMockServer(t, filepath.Join("testdata", "response", "runtime_metrics_response.json"))
+	defer ms.Close()
+	cfg := newDefaultConfig().(*Config)
+	cfg.Endpoint = ms.URL + defaultPath
+	cfg.MetricsConfig = allMetricsDisabled
+	cfg.MetricsConfig.ProcessRuntimeGcPauses = metricEnabled
+
+	scraper := newExpVarScraper(cfg, componenttest.NewNopReceiverCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	md, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, md.MetricCount())
+	assert.Equal(t, "process.runtime.gc.pauses", md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+}
+
+func TestScrapeRuntimeMetricsEndpoint(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case defaultPath:
+			_, _ = rw.Write([]byte(`{"memstats": {"Alloc": 1266984}}`))
+		case "/debug/runtime_metrics":
+			_, _ = rw.Write([]byte(`{"/sched/latencies:seconds": {"counts": [1, 2], "buckets": [0, 1e-06, "+Inf"]}, "/gc/pauses:seconds": {"counts": [1], "buckets": [0]}}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	cfg := newDefaultConfig().(*Config)
+	cfg.Endpoint = ts.URL + defaultPath
+	cfg.MetricsConfig = allMetricsDisabled
+	cfg.MetricsConfig.ProcessRuntimeSchedLatencies = metricEnabled
+	cfg.MetricsConfig.ProcessRuntimeGcPauses = metricEnabled
+	cfg.RuntimeMetrics.Endpoint = ts.URL + "/debug/runtime_metrics"
+
+	scraper := newExpVarScraper(cfg, componenttest.NewNopReceiverCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	md, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	assert.True(t, scrapererror.IsPartialScrapeError(err))
+	assert.EqualError(t, err, "runtime/metrics sample '/gc/pauses:seconds' has 1 bucket boundaries for 1 counts")
+	require.Equal(t, 1, md.MetricCount())
+	dp := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	assert.Equal(t, []float64{1e-06}, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 2}, dp.BucketCounts().AsRaw())
+
+	cfg.RuntimeMetrics.Endpoint = ts.URL + "/nonexistent/path"
+	md, err = scraper.scrape(context.Background())
+	assert.EqualError(t, err, "failed to scrape runtime/metrics endpoint: expected 200 but received 404 status code")
+	assert.Equal(t, 0, md.MetricCount())
+}
@@ -15,6 +15,7 @@
 package expvarreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/expvarreceiver"
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -26,6 +27,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/expvarreceiver/internal/metadata"
 )
@@ -36,10 +38,16 @@ type expVar struct {
 }
 
 type expVarScraper struct {
-	cfg    *Config
-	set    *component.ReceiverCreateSettings
-	client *http.Client
-	mb     *metadata.MetricsBuilder
+	cfg       *Config
+	set       *component.ReceiverCreateSettings
+	client    *http.Client
+	mb        *metadata.MetricsBuilder
+	startTime pcommon.Timestamp
+
+	// prevNumGC is the number of GC cycles at the previous scrape, scrapedNumGC tells
+	// whether it was scraped already.
+	prevNumGC    uint32
+	scrapedNumGC bool
 }
 
 func newExpVarScraper(cfg *Config, set component.ReceiverCreateSettings) *expVarScraper {
@@ -56,25 +64,17 @@ func (e *expVarScraper) start(_ context.Context, host component.Host) error {
 		return err
 	}
 	e.client = client
+	e.startTime = pcommon.NewTimestampFromTime(time.Now())
 	return nil
 }
 
 func (e *expVarScraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
 	emptyMetrics := pmetric.NewMetrics()
-	req, err := http.NewRequestWithContext(ctx, "GET", e.cfg.Endpoint, nil)
-	if err != nil {
-		return emptyMetrics, err
-	}
-	resp, err := e.client.Do(req)
+	body, err := e.get(ctx, e.cfg.Endpoint)
 	if err != nil {
 		return emptyMetrics, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return emptyMetrics, fmt.Errorf("expected 200 but received %d status code", resp.StatusCode)
-	}
-
-	result, err := decodeResponseBody(resp.Body)
+	result, err := decodeResponseBody(bytes.NewReader(body))
 	if err != nil {
 		return emptyMetrics, fmt.Errorf("could not decode response body to JSON: %w", err)
 	}
@@ -113,11 +113,112 @@ func (e *expVarScraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
 	// Memstats exposes a circular buffer of recent GC stop-the-world pause times.
 	// The most recent pause is at PauseNs[(NumGC+255)%256].
 	e.mb.RecordProcessRuntimeMemstatsLastPauseDataPoint(now, int64(memStats.PauseNs[(memStats.NumGC+255)%256]))
+	e.recordRecentPauses(now, memStats)
+
+	errs := &scrapererror.ScrapeErrors{}
+	runtimeHistograms := enabledRuntimeHistograms(e.cfg.MetricsConfig)
+	var vars map[string]json.RawMessage
+	if len(e.cfg.Histograms) > 0 || len(runtimeHistograms) > 0 {
+		if err = json.Unmarshal(body, &vars); err != nil {
+			errs.AddPartial(len(e.cfg.Histograms)+len(runtimeHistograms), err)
+		} else {
+			e.scrapeRuntimeMetrics(ctx, vars, runtimeHistograms, now, errs)
+		}
+	}
+
+	md := e.mb.Emit()
+	if len(e.cfg.Histograms) == 0 || vars == nil {
+		return md, errs.Combine()
+	}
+
+	var metrics pmetric.MetricSlice
+	if md.ResourceMetrics().Len() == 0 {
+		sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName("otelcol/expvarreceiver")
+		sm.Scope().SetVersion(e.set.BuildInfo.Version)
+		metrics = sm.Metrics()
+	} else {
+		metrics = md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	}
+
+	e.scrapeHistograms(metrics, vars, now, errs)
+	if metrics.Len() == 0 {
+		md.ResourceMetrics().RemoveIf(func(pmetric.ResourceMetrics) bool { return true })
+	}
+	return md, errs.Combine()
+}
+
+// get returns the body of the response to a GET request of endpoint.
+func (e *expVarScraper) get(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("expected 200 but received %d status code", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
 
-	return e.mb.Emit(), nil
+// scrapeHistograms appends the configured histograms to metrics.
+func (e *expVarScraper) scrapeHistograms(metrics pmetric.MetricSlice, vars map[string]json.RawMessage, now pcommon.Timestamp, errs *scrapererror.ScrapeErrors) {
+	for _, h := range e.cfg.Histograms {
+		raw, ok := vars[h.Variable]
+		if !ok {
+			errs.AddPartial(1, fmt.Errorf("expvar variable '%s' not found", h.Variable))
+			continue
+		}
+		if err := recordHistogram(metrics, h, raw, e.startTime, now); err != nil {
+			errs.AddPartial(1, err)
+		}
+	}
+}
+
+// scrapeRuntimeMetrics records the enabled runtime/metrics histograms, the samples are read
+// from the runtime/metrics endpoint when configured, from the expvar variable otherwise.
+func (e *expVarScraper) scrapeRuntimeMetrics(ctx context.Context, vars map[string]json.RawMessage, histograms []runtimeHistogram, now pcommon.Timestamp, errs *scrapererror.ScrapeErrors) {
+	if len(histograms) == 0 {
+		return
+	}
+	var raw json.RawMessage
+	if e.cfg.RuntimeMetrics.Endpoint != "" {
+		body, err := e.get(ctx, e.cfg.RuntimeMetrics.Endpoint)
+		if err != nil {
+			errs.AddPartial(len(histograms), fmt.Errorf("failed to scrape runtime/metrics endpoint: %w", err))
+			return
+		}
+		raw = body
+	} else {
+		var ok bool
+		if raw, ok = vars[e.cfg.RuntimeMetrics.Variable]; !ok {
+			// The process doesn't publish its runtime/metrics samples.
+			return
+		}
+	}
+
+	var samples map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &samples); err != nil {
+		errs.AddPartial(len(histograms), fmt.Errorf("runtime/metrics samples are not a map: %w", err))
+		return
+	}
+	for _, h := range histograms {
+		sample, ok := samples[h.sample]
+		if !ok {
+			// The sample isn't supported by the Go version of the process.
+			continue
+		}
+		if err := recordRuntimeHistogram(e.mb, h, sample, now); err != nil {
+			errs.AddPartial(1, err)
+		}
+	}
 }
 
-func decodeResponseBody(body io.ReadCloser) (*expVar, error) {
+func decodeResponseBody(body io.Reader) (*expVar, error) {
 	var result expVar
 	if err := json.NewDecoder(body).Decode(&result); err != nil {
 		return nil, err
@@ -23,7 +23,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configauth"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/scrapertest"
@@ -35,60 +37,70 @@ var (
 	metricEnabled     = metadata.MetricSettings{Enabled: true}
 	metricDisabled    = metadata.MetricSettings{Enabled: false}
 	allMetricsEnabled = metadata.MetricsSettings{
-		ProcessRuntimeMemstatsBuckHashSys:   metricEnabled,
-		ProcessRuntimeMemstatsFrees:         metricEnabled,
-		ProcessRuntimeMemstatsGcCPUFraction: metricEnabled,
-		ProcessRuntimeMemstatsGcSys:         metricEnabled,
-		ProcessRuntimeMemstatsHeapAlloc:     metricEnabled,
-		ProcessRuntimeMemstatsHeapIdle:      metricEnabled,
-		ProcessRuntimeMemstatsHeapInuse:     metricEnabled,
-		ProcessRuntimeMemstatsHeapObjects:   metricEnabled,
-		ProcessRuntimeMemstatsHeapReleased:  metricEnabled,
-		ProcessRuntimeMemstatsHeapSys:       metricEnabled,
-		ProcessRuntimeMemstatsLastPause:     metricEnabled,
-		ProcessRuntimeMemstatsLookups:       metricEnabled,
-		ProcessRuntimeMemstatsMallocs:       metricEnabled,
-		ProcessRuntimeMemstatsMcacheInuse:   metricEnabled,
-		ProcessRuntimeMemstatsMcacheSys:     metricEnabled,
-		ProcessRuntimeMemstatsMspanInuse:    metricEnabled,
-		ProcessRuntimeMemstatsMspanSys:      metricEnabled,
-		ProcessRuntimeMemstatsNextGc:        metricEnabled,
-		ProcessRuntimeMemstatsNumForcedGc:   metricEnabled,
-		ProcessRuntimeMemstatsNumGc:         metricEnabled,
-		ProcessRuntimeMemstatsOtherSys:      metricEnabled,
-		ProcessRuntimeMemstatsPauseTotal:    metricEnabled,
-		ProcessRuntimeMemstatsStackInuse:    metricEnabled,
-		ProcessRuntimeMemstatsStackSys:      metricEnabled,
-		ProcessRuntimeMemstatsSys:           metricEnabled,
-		ProcessRuntimeMemstatsTotalAlloc:    metricEnabled,
+		ProcessRuntimeMemstatsBuckHashSys:      metricEnabled,
+		ProcessRuntimeMemstatsFrees:            metricEnabled,
+		ProcessRuntimeMemstatsGcCPUFraction:    metricEnabled,
+		ProcessRuntimeMemstatsGcSys:            metricEnabled,
+		ProcessRuntimeMemstatsHeapAlloc:        metricEnabled,
+		ProcessRuntimeMemstatsHeapIdle:         metricEnabled,
+		ProcessRuntimeMemstatsHeapInuse:        metricEnabled,
+		ProcessRuntimeMemstatsHeapObjects:      metricEnabled,
+		ProcessRuntimeMemstatsHeapReleased:     metricEnabled,
+		ProcessRuntimeMemstatsHeapSys:          metricEnabled,
+		ProcessRuntimeMemstatsLastPause:        metricEnabled,
+		ProcessRuntimeMemstatsLookups:          metricEnabled,
+		ProcessRuntimeMemstatsMallocs:          metricEnabled,
+		ProcessRuntimeMemstatsMcacheInuse:      metricEnabled,
+		ProcessRuntimeMemstatsMcacheSys:        metricEnabled,
+		ProcessRuntimeMemstatsMspanInuse:       metricEnabled,
+		ProcessRuntimeMemstatsMspanSys:         metricEnabled,
+		ProcessRuntimeMemstatsNextGc:           metricEnabled,
+		ProcessRuntimeMemstatsNumForcedGc:      metricEnabled,
+		ProcessRuntimeMemstatsNumGc:            metricEnabled,
+		ProcessRuntimeMemstatsOtherSys:         metricEnabled,
+		ProcessRuntimeMemstatsPauseTotal:       metricEnabled,
+		ProcessRuntimeMemstatsRecentPauseAvg:   metricEnabled,
+		ProcessRuntimeMemstatsRecentPauseCount: metricEnabled,
+		ProcessRuntimeMemstatsRecentPauseMax:   metricEnabled,
+		ProcessRuntimeMemstatsStackInuse:       metricEnabled,
+		ProcessRuntimeMemstatsStackSys:         metricEnabled,
+		ProcessRuntimeMemstatsSys:              metricEnabled,
+		ProcessRuntimeMemstatsTotalAlloc:       metricEnabled,
+		ProcessRuntimeSchedLatencies:           metricEnabled,
+		ProcessRuntimeGcPauses:                 metricEnabled,
 	}
 	allMetricsDisabled = metadata.MetricsSettings{
-		ProcessRuntimeMemstatsBuckHashSys:   metricDisabled,
-		ProcessRuntimeMemstatsFrees:         metricDisabled,
-		ProcessRuntimeMemstatsGcCPUFraction: metricDisabled,
-		ProcessRuntimeMemstatsGcSys:         metricDisabled,
-		ProcessRuntimeMemstatsHeapAlloc:     metricDisabled,
-		ProcessRuntimeMemstatsHeapIdle:      metricDisabled,
-		ProcessRuntimeMemstatsHeapInuse:     metricDisabled,
-		ProcessRuntimeMemstatsHeapObjects:   metricDisabled,
-		ProcessRuntimeMemstatsHeapReleased:  metricDisabled,
-		ProcessRuntimeMemstatsHeapSys:       metricDisabled,
-		ProcessRuntimeMemstatsLastPause:     metricDisabled,
-		ProcessRuntimeMemstatsLookups:       metricDisabled,
-		ProcessRuntimeMemstatsMallocs:       metricDisabled,
-		ProcessRuntimeMemstatsMcacheInuse:   metricDisabled,
-		ProcessRuntimeMemstatsMcacheSys:     metricDisabled,
-		ProcessRuntimeMemstatsMspanInuse:    metricDisabled,
-		ProcessRuntimeMemstatsMspanSys:      metricDisabled,
-		ProcessRuntimeMemstatsNextGc:        metricDisabled,
-		ProcessRuntimeMemstatsNumForcedGc:   metricDisabled,
-		ProcessRuntimeMemstatsNumGc:         metricDisabled,
-		ProcessRuntimeMemstatsOtherSys:      metricDisabled,
-		ProcessRuntimeMemstatsPauseTotal:    metricDisabled,
-		ProcessRuntimeMemstatsStackInuse:    metricDisabled,
-		ProcessRuntimeMemstatsStackSys:      metricDisabled,
-		ProcessRuntimeMemstatsSys:           metricDisabled,
-		ProcessRuntimeMemstatsTotalAlloc:    metricDisabled,
+		ProcessRuntimeMemstatsBuckHashSys:      metricDisabled,
+		ProcessRuntimeMemstatsFrees:            metricDisabled,
+		ProcessRuntimeMemstatsGcCPUFraction:    metricDisabled,
+		ProcessRuntimeMemstatsGcSys:            metricDisabled,
+		ProcessRuntimeMemstatsHeapAlloc:        metricDisabled,
+		ProcessRuntimeMemstatsHeapIdle:         metricDisabled,
+		ProcessRuntimeMemstatsHeapInuse:        metricDisabled,
+		ProcessRuntimeMemstatsHeapObjects:      metricDisabled,
+		ProcessRuntimeMemstatsHeapReleased:     metricDisabled,
+		ProcessRuntimeMemstatsHeapSys:          metricDisabled,
+		ProcessRuntimeMemstatsLastPause:        metricDisabled,
+		ProcessRuntimeMemstatsLookups:          metricDisabled,
+		ProcessRuntimeMemstatsMallocs:          metricDisabled,
+		ProcessRuntimeMemstatsMcacheInuse:      metricDisabled,
+		ProcessRuntimeMemstatsMcacheSys:        metricDisabled,
+		ProcessRuntimeMemstatsMspanInuse:       metricDisabled,
+		ProcessRuntimeMemstatsMspanSys:         metricDisabled,
+		ProcessRuntimeMemstatsNextGc:           metricDisabled,
+		ProcessRuntimeMemstatsNumForcedGc:      metricDisabled,
+		ProcessRuntimeMemstatsNumGc:            metricDisabled,
+		ProcessRuntimeMemstatsOtherSys:         metricDisabled,
+		ProcessRuntimeMemstatsPauseTotal:       metricDisabled,
+		ProcessRuntimeMemstatsRecentPauseAvg:   metricDisabled,
+		ProcessRuntimeMemstatsRecentPauseCount: metricDisabled,
+		ProcessRuntimeMemstatsRecentPauseMax:   metricDisabled,
+		ProcessRuntimeMemstatsStackInuse:       metricDisabled,
+		ProcessRuntimeMemstatsStackSys:         metricDisabled,
+		ProcessRuntimeMemstatsSys:              metricDisabled,
+		ProcessRuntimeMemstatsTotalAlloc:       metricDisabled,
+		ProcessRuntimeSchedLatencies:           metricDisabled,
+		ProcessRuntimeGcPauses:                 metricDisabled,
 	}
 )
 
@@ -193,3 +205,61 @@ func TestEmptyResponseBodyError(t *testing.T) {
 	require.EqualError(t, err, "could not decode response body to JSON: EOF")
 	require.NoError(t, scrapertest.CompareMetrics(expectedMetrics, actualMetrics))
 }
+
+// authHost is a host with a client authenticator setting a bearer token.
+type authHost struct {
+	component.Host
+}
+
+func (h *authHost) GetExtensions() map[component.ID]component.Extension {
+	return map[component.ID]component.Extension{
+		component.NewID("bearertokenauth"): configauth.NewClientAuthenticator(
+			configauth.WithClientRoundTripper(func(base http.RoundTripper) (http.RoundTripper, error) {
+				return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					req.Header.Set("Authorization", "Bearer token")
+					return base.RoundTrip(req)
+				}), nil
+			}),
+		),
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestAuthenticator(t *testing.T) {
+	ms := newMockServer(t, filepath.Join("testdata", "response", "expvar_response.json"))
+	defer ms.Close()
+	proxy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		ms.Config.Handler.ServeHTTP(rw, req)
+	}))
+	defer proxy.Close()
+
+	cfg := newDefaultConfig().(*Config)
+	cfg.Endpoint = proxy.URL + defaultPath
+	cfg.MetricsConfig = allMetricsEnabled
+
+	// the requests are rejected without authenticator
+	scraper := newExpVarScraper(cfg, componenttest.NewNopReceiverCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+	_, err := scraper.scrape(context.Background())
+	require.EqualError(t, err, "expected 200 but received 401 status code")
+
+	cfg.Auth = &configauth.Authentication{AuthenticatorID: component.NewID("bearertokenauth")}
+	scraper = newExpVarScraper(cfg, componenttest.NewNopReceiverCreateSettings())
+	require.NoError(t, scraper.start(context.Background(), &authHost{Host: componenttest.NewNopHost()}))
+	actualMetrics, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	require.Greater(t, actualMetrics.MetricCount(), 0)
+
+	// the authenticator extension must be configured
+	scraper = newExpVarScraper(cfg, componenttest.NewNopReceiverCreateSettings())
+	require.Error(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+}
@@ -17,6 +17,7 @@ package kubeletstatsreceiver // import "github.com/open-telemetry/opentelemetry-
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confignet"
@@ -48,11 +49,29 @@ type Config struct {
 	// "container", "pod", "node" and "volume" are the only valid groups.
 	MetricGroupsToCollect []kubelet.MetricGroup `mapstructure:"metric_groups"`
 
+	// MetricGroupIntervals allows collecting some metric groups less often than the others, e.g.
+	// the volume stats that are expensive on nodes with many persistent volume claims. The groups
+	// not listed are collected on every collection interval.
+	MetricGroupIntervals map[kubelet.MetricGroup]time.Duration `mapstructure:"metric_group_intervals"`
+
 	// Configuration of the Kubernetes API client.
 	K8sAPIConfig *k8sconfig.APIConfig `mapstructure:"k8s_api_config"`
 
 	// Metrics allows customizing scraped metrics representation.
 	Metrics metadata.MetricsSettings `mapstructure:"metrics"`
+
+	// SkipUnchangedGauges allows skipping the gauge data points whose value didn't change
+	// since they were last emitted, to reduce the number of data points on large nodes.
+	SkipUnchangedGauges SkipUnchangedGaugesConfig `mapstructure:"skip_unchanged_gauges"`
+}
+
+type SkipUnchangedGaugesConfig struct {
+	// Enabled enables skipping the unchanged gauge data points. Disabled by default.
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxInterval is the maximum interval between two emissions of an unchanged gauge series,
+	// so that backends don't consider it stale. Zero means unchanged series are never emitted again.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
 }
 
 func (cfg *Config) Validate() error {
@@ -64,6 +83,20 @@ func (cfg *Config) Validate() error {
 			return err
 		}
 	}
+	if cfg.SkipUnchangedGauges.MaxInterval < 0 {
+		return errors.New("skip_unchanged_gauges.max_interval must not be negative")
+	}
+	for group, interval := range cfg.MetricGroupIntervals {
+		if !kubelet.ValidMetricGroups[group] {
+			return fmt.Errorf("invalid metric group %q in metric_group_intervals", group)
+		}
+		if !containsMetricGroup(cfg.MetricGroupsToCollect, group) {
+			return fmt.Errorf("metric_group_intervals.%s is set but the group is not in metric_groups", group)
+		}
+		if interval < cfg.CollectionInterval {
+			return fmt.Errorf("metric_group_intervals.%s must not be shorter than the collection_interval", group)
+		}
+	}
 	return nil
 }
 
@@ -94,6 +127,8 @@ func (cfg *Config) getReceiverOptions() (*scraperOptions, error) {
 		extraMetadataLabels:   cfg.ExtraMetadataLabels,
 		metricGroupsToCollect: mgs,
 		k8sAPIClient:          k8sAPIClient,
+		skipUnchangedGauges:   cfg.SkipUnchangedGauges,
+		metricGroupIntervals:  cfg.MetricGroupIntervals,
 	}, nil
 }
 
@@ -111,6 +146,15 @@ func getMapFromSlice(collect []kubelet.MetricGroup) (map[kubelet.MetricGroup]boo
 	return out, nil
 }
 
+func containsMetricGroup(groups []kubelet.MetricGroup, group kubelet.MetricGroup) bool {
+	for _, g := range groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
 func (cfg *Config) Unmarshal(componentParser *confmap.Conf) error {
 	if componentParser == nil {
 		// Nothing to do if there is no config given.
@@ -65,7 +65,8 @@ func TestLoadConfig(t *testing.T) {
 					kubelet.PodMetricGroup,
 					kubelet.NodeMetricGroup,
 				},
-				Metrics: metadata.DefaultMetricsSettings(),
+				Metrics:             metadata.DefaultMetricsSettings(),
+				SkipUnchangedGauges: SkipUnchangedGaugesConfig{MaxInterval: defaultUnchangedGaugesMaxInterval},
 			},
 		},
 		{
@@ -94,7 +95,8 @@ func TestLoadConfig(t *testing.T) {
 					kubelet.PodMetricGroup,
 					kubelet.NodeMetricGroup,
 				},
-				Metrics: metadata.DefaultMetricsSettings(),
+				Metrics:             metadata.DefaultMetricsSettings(),
+				SkipUnchangedGauges: SkipUnchangedGaugesConfig{MaxInterval: defaultUnchangedGaugesMaxInterval},
 			},
 		},
 		{
@@ -115,7 +117,8 @@ func TestLoadConfig(t *testing.T) {
 					kubelet.PodMetricGroup,
 					kubelet.NodeMetricGroup,
 				},
-				Metrics: metadata.DefaultMetricsSettings(),
+				Metrics:             metadata.DefaultMetricsSettings(),
+				SkipUnchangedGauges: SkipUnchangedGaugesConfig{MaxInterval: defaultUnchangedGaugesMaxInterval},
 			},
 		},
 		{
@@ -139,7 +142,8 @@ func TestLoadConfig(t *testing.T) {
 					kubelet.PodMetricGroup,
 					kubelet.NodeMetricGroup,
 				},
-				Metrics: metadata.DefaultMetricsSettings(),
+				Metrics:             metadata.DefaultMetricsSettings(),
+				SkipUnchangedGauges: SkipUnchangedGaugesConfig{MaxInterval: defaultUnchangedGaugesMaxInterval},
 			},
 		},
 		{
@@ -159,7 +163,55 @@ func TestLoadConfig(t *testing.T) {
 					kubelet.NodeMetricGroup,
 					kubelet.VolumeMetricGroup,
 				},
+				Metrics:             metadata.DefaultMetricsSettings(),
+				SkipUnchangedGauges: SkipUnchangedGaugesConfig{MaxInterval: defaultUnchangedGaugesMaxInterval},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "skip_unchanged_gauges"),
+			expected: &Config{
+				ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+					ReceiverSettings:   config.NewReceiverSettings(component.NewID(typeStr)),
+					CollectionInterval: duration,
+				},
+				ClientConfig: kube.ClientConfig{
+					APIConfig: k8sconfig.APIConfig{
+						AuthType: "serviceAccount",
+					},
+				},
+				MetricGroupsToCollect: []kubelet.MetricGroup{
+					kubelet.ContainerMetricGroup,
+					kubelet.PodMetricGroup,
+					kubelet.NodeMetricGroup,
+				},
 				Metrics: metadata.DefaultMetricsSettings(),
+				SkipUnchangedGauges: SkipUnchangedGaugesConfig{
+					Enabled:     true,
+					MaxInterval: time.Minute,
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(typeStr, "metric_group_intervals"),
+			expected: &Config{
+				ScraperControllerSettings: scraperhelper.ScraperControllerSettings{
+					ReceiverSettings:   config.NewReceiverSettings(component.NewID(typeStr)),
+					CollectionInterval: duration,
+				},
+				ClientConfig: kube.ClientConfig{
+					APIConfig: k8sconfig.APIConfig{
+						AuthType: "serviceAccount",
+					},
+				},
+				MetricGroupsToCollect: []kubelet.MetricGroup{
+					kubelet.NodeMetricGroup,
+					kubelet.VolumeMetricGroup,
+				},
+				MetricGroupIntervals: map[kubelet.MetricGroup]time.Duration{
+					kubelet.VolumeMetricGroup: 5 * time.Minute,
+				},
+				Metrics:             metadata.DefaultMetricsSettings(),
+				SkipUnchangedGauges: SkipUnchangedGaugesConfig{MaxInterval: defaultUnchangedGaugesMaxInterval},
 			},
 		},
 		{
@@ -182,8 +234,9 @@ func TestLoadConfig(t *testing.T) {
 					kubelet.PodMetricGroup,
 					kubelet.NodeMetricGroup,
 				},
-				K8sAPIConfig: &k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
-				Metrics:      metadata.DefaultMetricsSettings(),
+				K8sAPIConfig:        &k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
+				Metrics:             metadata.DefaultMetricsSettings(),
+				SkipUnchangedGauges: SkipUnchangedGaugesConfig{MaxInterval: defaultUnchangedGaugesMaxInterval},
 			},
 		},
 	}
@@ -203,6 +256,38 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+func TestValidateMetricGroupIntervals(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals map[kubelet.MetricGroup]time.Duration
+		err       string
+	}{
+		{
+			name:      "invalid group",
+			intervals: map[kubelet.MetricGroup]time.Duration{"unsupported": time.Minute},
+			err:       `invalid metric group "unsupported" in metric_group_intervals`,
+		},
+		{
+			name:      "group not collected",
+			intervals: map[kubelet.MetricGroup]time.Duration{kubelet.PodMetricGroup: time.Minute},
+			err:       "metric_group_intervals.pod is set but the group is not in metric_groups",
+		},
+		{
+			name:      "shorter than the collection interval",
+			intervals: map[kubelet.MetricGroup]time.Duration{kubelet.VolumeMetricGroup: time.Second},
+			err:       "metric_group_intervals.volume must not be shorter than the collection_interval",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.MetricGroupsToCollect = []kubelet.MetricGroup{kubelet.NodeMetricGroup, kubelet.VolumeMetricGroup}
+			cfg.MetricGroupIntervals = tt.intervals
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
+
 func TestGetReceiverOptions(t *testing.T) {
 	type fields struct {
 		extraMetadataLabels   []kubelet.MetadataLabel
@@ -49,6 +49,8 @@ func NewFactory() component.ReceiverFactory {
 		component.WithMetricsReceiver(createMetricsReceiver, stability))
 }
 
+const defaultUnchangedGaugesMaxInterval = 5 * time.Minute
+
 func createDefaultConfig() component.ReceiverConfig {
 	scs := scraperhelper.NewDefaultScraperControllerSettings(typeStr)
 	scs.CollectionInterval = 10 * time.Second
@@ -61,6 +63,9 @@ func createDefaultConfig() component.ReceiverConfig {
 			},
 		},
 		Metrics: metadata.DefaultMetricsSettings(),
+		SkipUnchangedGauges: SkipUnchangedGaugesConfig{
+			MaxInterval: defaultUnchangedGaugesMaxInterval,
+		},
 	}
 }
 
@@ -0,0 +1,85 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/internal/kubelet"
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	stats "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+// containerInfo is the subset of the cadvisor v1 container info returned by the
+// legacy /stats/ kubelet endpoints, the samples being ordered by time.
+type containerInfo struct {
+	Stats []containerInfoStats `json:"stats"`
+}
+
+type containerInfoStats struct {
+	Timestamp time.Time `json:"timestamp"`
+	CPU       struct {
+		Usage struct {
+			Total uint64 `json:"total"`
+		} `json:"usage"`
+	} `json:"cpu"`
+	Memory struct {
+		Usage         uint64 `json:"usage"`
+		WorkingSet    uint64 `json:"working_set"`
+		RSS           uint64 `json:"rss"`
+		ContainerData struct {
+			Pgfault    uint64 `json:"pgfault"`
+			Pgmajfault uint64 `json:"pgmajfault"`
+		} `json:"container_data"`
+	} `json:"memory"`
+}
+
+// cpuStats returns the cpu stats of the last sample, the usage in nano cores being
+// derived from the previous sample.
+func (ci *containerInfo) cpuStats() *stats.CPUStats {
+	if len(ci.Stats) == 0 {
+		return nil
+	}
+	last := ci.Stats[len(ci.Stats)-1]
+	total := last.CPU.Usage.Total
+	out := &stats.CPUStats{
+		Time:                 metav1.NewTime(last.Timestamp),
+		UsageCoreNanoSeconds: &total,
+	}
+	if len(ci.Stats) > 1 {
+		prev := ci.Stats[len(ci.Stats)-2]
+		elapsed := last.Timestamp.Sub(prev.Timestamp)
+		if elapsed > 0 && total >= prev.CPU.Usage.Total {
+			nanoCores := uint64(float64(total-prev.CPU.Usage.Total) / elapsed.Seconds())
+			out.UsageNanoCores = &nanoCores
+		}
+	}
+	return out
+}
+
+// memoryStats returns the memory stats of the last sample.
+func (ci *containerInfo) memoryStats() *stats.MemoryStats {
+	if len(ci.Stats) == 0 {
+		return nil
+	}
+	last := ci.Stats[len(ci.Stats)-1]
+	return &stats.MemoryStats{
+		Time:            metav1.NewTime(last.Timestamp),
+		UsageBytes:      &last.Memory.Usage,
+		WorkingSetBytes: &last.Memory.WorkingSet,
+		RSSBytes:        &last.Memory.RSS,
+		PageFaults:      &last.Memory.ContainerData.Pgfault,
+		MajorPageFaults: &last.Memory.ContainerData.Pgmajfault,
+	}
+}
@@ -43,6 +43,14 @@ func (f testRestClient) Pods() ([]byte, error) {
 	return os.ReadFile("../../testdata/pods.json")
 }
 
+func (f testRestClient) NodeStats() ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (f testRestClient) ContainerStats(string, string, string, string) ([]byte, error) {
+	return []byte{}, nil
+}
+
 func TestPods(t *testing.T) {
 	tests := []struct {
 		name      string
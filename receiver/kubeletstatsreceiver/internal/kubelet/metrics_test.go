@@ -38,9 +38,17 @@ func (f fakeRestClient) Pods() ([]byte, error) {
 	return os.ReadFile("../../testdata/pods.json")
 }
 
+func (f fakeRestClient) NodeStats() ([]byte, error) {
+	return os.ReadFile("../../testdata/legacy-node-stats.json")
+}
+
+func (f fakeRestClient) ContainerStats(string, string, string, string) ([]byte, error) {
+	return os.ReadFile("../../testdata/legacy-container-stats.json")
+}
+
 func TestMetricAccumulator(t *testing.T) {
 	rc := &fakeRestClient{}
-	statsProvider := NewStatsProvider(rc)
+	statsProvider := NewStatsProvider(rc, zap.NewNop())
 	summary, _ := statsProvider.StatsSummary()
 	metadataProvider := NewMetadataProvider(rc)
 	podsMetadata, _ := metadataProvider.Pods()
@@ -188,7 +196,7 @@ func indexedFakeMetrics() map[string][]pmetric.Metric {
 
 func fakeMetrics() []pmetric.Metrics {
 	rc := &fakeRestClient{}
-	statsProvider := NewStatsProvider(rc)
+	statsProvider := NewStatsProvider(rc, zap.NewNop())
 	summary, _ := statsProvider.StatsSummary()
 	mgs := map[MetricGroup]bool{
 		ContainerMetricGroup: true,
@@ -15,6 +15,8 @@
 package kubelet // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/internal/kubelet"
 
 import (
+	"fmt"
+
 	kube "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kubelet"
 )
 
@@ -22,12 +24,15 @@ import (
 type RestClient interface {
 	StatsSummary() ([]byte, error)
 	Pods() ([]byte, error)
+	NodeStats() ([]byte, error)
+	ContainerStats(namespace, podName, podUID, containerName string) ([]byte, error)
 }
 
 // HTTPRestClient is a thin wrapper around a kubelet client, encapsulating endpoints
-// and their corresponding http methods. The endpoints /stats/container /spec/
-// are excluded because they require cadvisor. The /metrics endpoint is excluded
-// because it returns Prometheus data.
+// and their corresponding http methods. The legacy /stats/ endpoints, returning the
+// cadvisor container info, are only used for the older kubelets lacking stats in
+// their summary. The endpoints /stats/container /spec/ are excluded because they
+// require cadvisor. The /metrics endpoint is excluded because it returns Prometheus data.
 type HTTPRestClient struct {
 	client kube.Client
 }
@@ -43,3 +48,15 @@ func (c *HTTPRestClient) StatsSummary() ([]byte, error) {
 func (c *HTTPRestClient) Pods() ([]byte, error) {
 	return c.client.Get("/pods")
 }
+
+// NodeStats calls the legacy /stats/ kubelet endpoint returning the cadvisor container
+// info of the root container.
+func (c *HTTPRestClient) NodeStats() ([]byte, error) {
+	return c.client.Get("/stats/")
+}
+
+// ContainerStats calls the legacy /stats/{namespace}/{podName}/{uid}/{containerName}
+// kubelet endpoint returning the cadvisor container info of the container.
+func (c *HTTPRestClient) ContainerStats(namespace, podName, podUID, containerName string) ([]byte, error) {
+	return c.client.Get(fmt.Sprintf("/stats/%s/%s/%s/%s", namespace, podName, podUID, containerName))
+}
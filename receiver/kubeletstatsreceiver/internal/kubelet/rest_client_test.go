@@ -28,6 +28,10 @@ func TestRestClient(t *testing.T) {
 	require.Equal(t, "/stats/summary", string(resp))
 	resp, _ = rest.Pods()
 	require.Equal(t, "/pods", string(resp))
+	resp, _ = rest.NodeStats()
+	require.Equal(t, "/stats/", string(resp))
+	resp, _ = rest.ContainerStats("default", "web", "uid-1", "server")
+	require.Equal(t, "/stats/default/web/uid-1/server", string(resp))
 }
 
 var _ kube.Client = (*fakeClient)(nil)
@@ -17,30 +17,234 @@ package kubelet // import "github.com/open-telemetry/opentelemetry-collector-con
 import (
 	"encoding/json"
 
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
 	stats "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
 )
 
 // StatsProvider wraps a RestClient, returning an unmarshaled
 // stats.Summary struct from the kubelet API.
+//
+// The older kubelets lack some stats in their summary, or don't serve it at all,
+// so the missing cpu and memory stats are read from the legacy /stats/ endpoints
+// returning the cadvisor container info instead.
 type StatsProvider struct {
-	rc RestClient
+	rc     RestClient
+	logger *zap.Logger
+	// logged are the capabilities of the kubelet already logged.
+	logged map[capabilities]bool
+}
+
+// capabilities are the stats the kubelet provides through the summary API.
+type capabilities struct {
+	summary        bool
+	nodeStats      bool
+	containerStats bool
 }
 
-func NewStatsProvider(rc RestClient) *StatsProvider {
-	return &StatsProvider{rc: rc}
+func NewStatsProvider(rc RestClient, logger *zap.Logger) *StatsProvider {
+	return &StatsProvider{
+		rc:     rc,
+		logger: logger,
+		logged: map[capabilities]bool{},
+	}
 }
 
 // StatsSummary calls the /stats/summary kubelet endpoint and unmarshals the
-// results into a stats.Summary struct.
+// results into a stats.Summary struct, falling back to the legacy endpoints
+// when the summary is unavailable or lacks the cpu and memory stats.
 func (p *StatsProvider) StatsSummary() (*stats.Summary, error) {
 	summary, err := p.rc.StatsSummary()
 	if err != nil {
-		return nil, err
+		// the summary API may be removed or gated by the kubelet
+		out, legacyErr := p.legacySummary()
+		if legacyErr != nil {
+			return nil, err
+		}
+		p.detected(capabilities{})
+		return out, nil
 	}
 	var out stats.Summary
 	err = json.Unmarshal(summary, &out)
 	if err != nil {
 		return nil, err
 	}
+	p.detected(p.fillMissingStats(&out))
 	return &out, nil
 }
+
+// detected logs the capabilities of the kubelet the first time they are detected.
+func (p *StatsProvider) detected(c capabilities) {
+	if p.logged[c] {
+		return
+	}
+	p.logged[c] = true
+	if c.summary && c.nodeStats && c.containerStats {
+		p.logger.Debug("The kubelet summary provides all the stats")
+		return
+	}
+	p.logger.Info("The kubelet summary lacks stats, reading them from the legacy /stats/ endpoints",
+		zap.Bool("summary", c.summary),
+		zap.Bool("node_stats", c.nodeStats),
+		zap.Bool("container_stats", c.containerStats))
+}
+
+// fillMissingStats reads the cpu and memory stats lacking in the summary from the
+// legacy endpoints, and returns the stats the summary provided.
+func (p *StatsProvider) fillMissingStats(summary *stats.Summary) capabilities {
+	c := capabilities{summary: true, nodeStats: true, containerStats: true}
+	if summary.Node.CPU == nil || summary.Node.Memory == nil {
+		c.nodeStats = false
+		p.fillNodeStats(&summary.Node)
+	}
+	for i := range summary.Pods {
+		pod := &summary.Pods[i]
+		for j := range pod.Containers {
+			container := &pod.Containers[j]
+			if container.CPU == nil || container.Memory == nil {
+				c.containerStats = false
+				p.fillContainerStats(pod.PodRef, container)
+			}
+		}
+		aggregatePodStats(pod)
+	}
+	return c
+}
+
+func (p *StatsProvider) fillNodeStats(node *stats.NodeStats) {
+	info, err := p.containerInfo(p.rc.NodeStats())
+	if err != nil {
+		p.logger.Debug("call to /stats/ endpoint failed", zap.Error(err))
+		return
+	}
+	if node.CPU == nil {
+		node.CPU = info.cpuStats()
+	}
+	if node.Memory == nil {
+		node.Memory = info.memoryStats()
+	}
+}
+
+func (p *StatsProvider) fillContainerStats(ref stats.PodReference, container *stats.ContainerStats) {
+	info, err := p.containerInfo(p.rc.ContainerStats(ref.Namespace, ref.Name, ref.UID, container.Name))
+	if err != nil {
+		p.logger.Debug("call to /stats/ container endpoint failed",
+			zap.String("pod", ref.Name), zap.String("container", container.Name), zap.Error(err))
+		return
+	}
+	if container.CPU == nil {
+		container.CPU = info.cpuStats()
+	}
+	if container.Memory == nil {
+		container.Memory = info.memoryStats()
+	}
+}
+
+func (p *StatsProvider) containerInfo(body []byte, err error) (*containerInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	var info containerInfo
+	if err = json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// legacySummary builds the summary of the running pods, listed by the /pods
+// endpoint, from the legacy endpoints. Only the cpu and memory stats are available.
+func (p *StatsProvider) legacySummary() (*stats.Summary, error) {
+	body, err := p.rc.Pods()
+	if err != nil {
+		return nil, err
+	}
+	var pods v1.PodList
+	if err = json.Unmarshal(body, &pods); err != nil {
+		return nil, err
+	}
+	node, err := p.containerInfo(p.rc.NodeStats())
+	if err != nil {
+		return nil, err
+	}
+
+	out := &stats.Summary{}
+	out.Node.CPU = node.cpuStats()
+	out.Node.Memory = node.memoryStats()
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		out.Node.NodeName = pod.Spec.NodeName
+		ps := stats.PodStats{
+			PodRef: stats.PodReference{Name: pod.Name, Namespace: pod.Namespace, UID: string(pod.UID)},
+		}
+		if pod.Status.StartTime != nil {
+			ps.StartTime = *pod.Status.StartTime
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Running == nil {
+				continue
+			}
+			container := stats.ContainerStats{Name: status.Name, StartTime: status.State.Running.StartedAt}
+			p.fillContainerStats(ps.PodRef, &container)
+			ps.Containers = append(ps.Containers, container)
+		}
+		aggregatePodStats(&ps)
+		out.Pods = append(out.Pods, ps)
+	}
+	return out, nil
+}
+
+// aggregatePodStats sums the cpu and memory stats of the containers of the pod
+// when the pod lacks them.
+func aggregatePodStats(pod *stats.PodStats) {
+	if pod.CPU == nil {
+		var cpu *stats.CPUStats
+		for _, container := range pod.Containers {
+			if container.CPU == nil {
+				continue
+			}
+			if cpu == nil {
+				cpu = &stats.CPUStats{}
+			}
+			if cpu.Time.Before(&container.CPU.Time) {
+				cpu.Time = container.CPU.Time
+			}
+			cpu.UsageNanoCores = addUint64(cpu.UsageNanoCores, container.CPU.UsageNanoCores)
+			cpu.UsageCoreNanoSeconds = addUint64(cpu.UsageCoreNanoSeconds, container.CPU.UsageCoreNanoSeconds)
+		}
+		pod.CPU = cpu
+	}
+	if pod.Memory == nil {
+		var memory *stats.MemoryStats
+		for _, container := range pod.Containers {
+			if container.Memory == nil {
+				continue
+			}
+			if memory == nil {
+				memory = &stats.MemoryStats{}
+			}
+			if memory.Time.Before(&container.Memory.Time) {
+				memory.Time = container.Memory.Time
+			}
+			memory.UsageBytes = addUint64(memory.UsageBytes, container.Memory.UsageBytes)
+			memory.WorkingSetBytes = addUint64(memory.WorkingSetBytes, container.Memory.WorkingSetBytes)
+			memory.RSSBytes = addUint64(memory.RSSBytes, container.Memory.RSSBytes)
+			memory.PageFaults = addUint64(memory.PageFaults, container.Memory.PageFaults)
+			memory.MajorPageFaults = addUint64(memory.MajorPageFaults, container.Memory.MajorPageFaults)
+		}
+		pod.Memory = memory
+	}
+}
+
+// addUint64 returns the sum of the values, nil if both are missing.
+func addUint64(sum, v *uint64) *uint64 {
+	if v == nil {
+		return sum
+	}
+	out := *v
+	if sum != nil {
+		out += *sum
+	}
+	return &out
+}
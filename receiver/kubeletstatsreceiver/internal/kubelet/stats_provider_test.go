@@ -0,0 +1,161 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	stats "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+// legacyRestClient is a client of a kubelet whose summary is read from a file,
+// and fails when the file is empty.
+type legacyRestClient struct {
+	summaryFile string
+	pods        string
+}
+
+func (c *legacyRestClient) StatsSummary() ([]byte, error) {
+	if c.summaryFile == "" {
+		return nil, errors.New("404 Not Found")
+	}
+	return os.ReadFile(c.summaryFile)
+}
+
+func (c *legacyRestClient) Pods() ([]byte, error) {
+	return []byte(c.pods), nil
+}
+
+func (c *legacyRestClient) NodeStats() ([]byte, error) {
+	return os.ReadFile("../../testdata/legacy-node-stats.json")
+}
+
+func (c *legacyRestClient) ContainerStats(namespace, podName, podUID, containerName string) ([]byte, error) {
+	if containerName != "server" {
+		return nil, errors.New("404 Not Found")
+	}
+	return os.ReadFile("../../testdata/legacy-container-stats.json")
+}
+
+func uint64Value(v *uint64) uint64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func TestStatsSummaryMissingStats(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	provider := NewStatsProvider(&legacyRestClient{summaryFile: "../../testdata/stats-summary-partial.json"}, zap.New(core))
+
+	summary, err := provider.StatsSummary()
+	require.NoError(t, err)
+
+	// the node stats are read from the root container
+	require.NotNil(t, summary.Node.CPU)
+	assert.Equal(t, uint64(1020000000000), uint64Value(summary.Node.CPU.UsageCoreNanoSeconds))
+	assert.Equal(t, uint64(2000000000), uint64Value(summary.Node.CPU.UsageNanoCores))
+	require.NotNil(t, summary.Node.Memory)
+	assert.Equal(t, uint64(3000000000), uint64Value(summary.Node.Memory.WorkingSetBytes))
+
+	require.Len(t, summary.Pods, 1)
+	pod := summary.Pods[0]
+	server := pod.Containers[0]
+	require.NotNil(t, server.CPU)
+	assert.Equal(t, uint64(100000000), uint64Value(server.CPU.UsageNanoCores))
+	require.NotNil(t, server.Memory)
+	assert.Equal(t, uint64(200000000), uint64Value(server.Memory.UsageBytes))
+	assert.Equal(t, uint64(1), uint64Value(server.Memory.MajorPageFaults))
+
+	// the stats of the pod are the sums of the stats of its containers
+	require.NotNil(t, pod.CPU)
+	assert.Equal(t, uint64(100001000), uint64Value(pod.CPU.UsageNanoCores))
+	assert.Equal(t, uint64(6000002000), uint64Value(pod.CPU.UsageCoreNanoSeconds))
+	require.NotNil(t, pod.Memory)
+	assert.Equal(t, uint64(200000100), uint64Value(pod.Memory.UsageBytes))
+	assert.Equal(t, uint64(150000050), uint64Value(pod.Memory.WorkingSetBytes))
+	assert.Equal(t, uint64(100000000), uint64Value(pod.Memory.RSSBytes))
+
+	// the capabilities are logged once
+	_, err = provider.StatsSummary()
+	require.NoError(t, err)
+	require.Len(t, logs.All(), 1)
+	assert.Equal(t, map[string]interface{}{"summary": true, "node_stats": false, "container_stats": false}, logs.All()[0].ContextMap())
+}
+
+func TestStatsSummaryLegacy(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	provider := NewStatsProvider(&legacyRestClient{pods: `{"items": [
+		{
+			"metadata": {"name": "web", "namespace": "default", "uid": "uid-1"},
+			"spec": {"nodeName": "node-1"},
+			"status": {
+				"phase": "Running",
+				"startTime": "2022-11-16T09:00:00Z",
+				"containerStatuses": [
+					{"name": "server", "state": {"running": {"startedAt": "2022-11-16T09:00:05Z"}}},
+					{"name": "init", "state": {"terminated": {"exitCode": 0}}}
+				]
+			}
+		},
+		{
+			"metadata": {"name": "done", "namespace": "default", "uid": "uid-2"},
+			"spec": {"nodeName": "node-1"},
+			"status": {"phase": "Succeeded"}
+		}
+	]}`}, zap.New(core))
+
+	summary, err := provider.StatsSummary()
+	require.NoError(t, err)
+	assert.Equal(t, "node-1", summary.Node.NodeName)
+	require.NotNil(t, summary.Node.CPU)
+	assert.Equal(t, uint64(2000000000), uint64Value(summary.Node.CPU.UsageNanoCores))
+
+	require.Len(t, summary.Pods, 1)
+	pod := summary.Pods[0]
+	assert.Equal(t, stats.PodReference{Name: "web", Namespace: "default", UID: "uid-1"}, pod.PodRef)
+	require.Len(t, pod.Containers, 1)
+	assert.Equal(t, "server", pod.Containers[0].Name)
+	require.NotNil(t, pod.Containers[0].Memory)
+	assert.Equal(t, uint64(150000000), uint64Value(pod.Containers[0].Memory.WorkingSetBytes))
+	require.NotNil(t, pod.Memory)
+	assert.Equal(t, uint64(150000000), uint64Value(pod.Memory.WorkingSetBytes))
+
+	require.Len(t, logs.All(), 1)
+	assert.Equal(t, map[string]interface{}{"summary": false, "node_stats": false, "container_stats": false}, logs.All()[0].ContextMap())
+}
+
+func TestStatsSummaryUnavailable(t *testing.T) {
+	provider := NewStatsProvider(&legacyRestClient{pods: "not json"}, zap.NewNop())
+	// the error of the summary is returned when the legacy endpoints fail too
+	_, err := provider.StatsSummary()
+	assert.EqualError(t, err, "404 Not Found")
+}
+
+func TestStatsSummaryCompleteStats(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	provider := NewStatsProvider(&legacyRestClient{summaryFile: "../../testdata/stats-summary.json"}, zap.New(core))
+	summary, err := provider.StatsSummary()
+	require.NoError(t, err)
+	assert.Len(t, summary.Pods, 9)
+	assert.Empty(t, logs.All())
+}
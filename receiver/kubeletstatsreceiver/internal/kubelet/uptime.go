@@ -0,0 +1,40 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/internal/kubelet"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/internal/metadata"
+)
+
+// addUptimeMetrics records the seconds elapsed since the start time and the start time
+// itself, a drop of the uptime or a new start time meaning that the pod or container restarted.
+func addUptimeMetrics(mb *metadata.MetricsBuilder, uptimeMetrics metadata.UptimeMetrics, startTime metav1.Time, now time.Time) {
+	if startTime.IsZero() {
+		return
+	}
+
+	currentTime := pcommon.NewTimestampFromTime(now)
+	if uptimeMetrics.Uptime != nil {
+		uptimeMetrics.Uptime(mb, currentTime, int64(now.Sub(startTime.Time).Seconds()))
+	}
+	if uptimeMetrics.StartTime != nil {
+		uptimeMetrics.StartTime(mb, currentTime, startTime.Unix())
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	stats "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/internal/metadata"
+)
+
+func TestUptimeMetrics(t *testing.T) {
+	settings := metadata.DefaultMetricsSettings()
+	settings.K8sPodUptime.Enabled = true
+	settings.K8sContainerUptime.Enabled = true
+	settings.K8sContainerStartTime.Enabled = true
+	newBuilder := func() *metadata.MetricsBuilder {
+		return metadata.NewMetricsBuilder(settings, componenttest.NewNopReceiverCreateSettings().BuildInfo)
+	}
+
+	now := time.Unix(10000, 0)
+	podStart := metav1.NewTime(now.Add(-time.Hour))
+	containerStart := metav1.NewTime(now.Add(-90 * time.Second))
+	acc := metricDataAccumulator{
+		metricGroupsToCollect: map[MetricGroup]bool{
+			PodMetricGroup:       true,
+			ContainerMetricGroup: true,
+		},
+		metadata: NewMetadata([]MetadataLabel{}, nil, nil),
+		mbs: &metadata.MetricsBuilders{
+			PodMetricsBuilder:       newBuilder(),
+			ContainerMetricsBuilder: newBuilder(),
+		},
+		time: now,
+	}
+
+	pod := stats.PodStats{
+		PodRef:    stats.PodReference{UID: "uid", Name: "pod", Namespace: "ns"},
+		StartTime: podStart,
+	}
+	acc.podStats(pod)
+	acc.containerStats(pod, stats.ContainerStats{Name: "container", StartTime: containerStart})
+	// a container without start time doesn't emit the uptime metrics
+	acc.containerStats(pod, stats.ContainerStats{Name: "pending"})
+
+	values := map[string][]int64{}
+	for _, md := range acc.m {
+		rms := md.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			ms := rms.At(i).ScopeMetrics().At(0).Metrics()
+			for j := 0; j < ms.Len(); j++ {
+				m := ms.At(j)
+				var dp pmetric.NumberDataPoint
+				switch m.Type() {
+				case pmetric.MetricTypeSum:
+					dp = m.Sum().DataPoints().At(0)
+				case pmetric.MetricTypeGauge:
+					dp = m.Gauge().DataPoints().At(0)
+				}
+				values[m.Name()] = append(values[m.Name()], dp.IntValue())
+			}
+		}
+	}
+
+	require.Len(t, values, 3)
+	assert.Equal(t, []int64{3600}, values["k8s.pod.uptime"])
+	assert.Equal(t, []int64{90}, values["k8s.container.uptime"])
+	assert.Equal(t, []int64{containerStart.Unix()}, values["k8s.container.start_time"])
+}
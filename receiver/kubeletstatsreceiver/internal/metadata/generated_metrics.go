@@ -48,6 +48,8 @@ type MetricsSettings struct {
 	ContainerMemoryRss             MetricSettings `mapstructure:"container.memory.rss"`
 	ContainerMemoryUsage           MetricSettings `mapstructure:"container.memory.usage"`
 	ContainerMemoryWorkingSet      MetricSettings `mapstructure:"container.memory.working_set"`
+	K8sContainerStartTime          MetricSettings `mapstructure:"k8s.container.start_time"`
+	K8sContainerUptime             MetricSettings `mapstructure:"k8s.container.uptime"`
 	K8sNodeCPUTime                 MetricSettings `mapstructure:"k8s.node.cpu.time"`
 	K8sNodeCPUUtilization          MetricSettings `mapstructure:"k8s.node.cpu.utilization"`
 	K8sNodeFilesystemAvailable     MetricSettings `mapstructure:"k8s.node.filesystem.available"`
@@ -74,6 +76,7 @@ type MetricsSettings struct {
 	K8sPodMemoryWorkingSet         MetricSettings `mapstructure:"k8s.pod.memory.working_set"`
 	K8sPodNetworkErrors            MetricSettings `mapstructure:"k8s.pod.network.errors"`
 	K8sPodNetworkIo                MetricSettings `mapstructure:"k8s.pod.network.io"`
+	K8sPodUptime                   MetricSettings `mapstructure:"k8s.pod.uptime"`
 	K8sVolumeAvailable             MetricSettings `mapstructure:"k8s.volume.available"`
 	K8sVolumeCapacity              MetricSettings `mapstructure:"k8s.volume.capacity"`
 	K8sVolumeInodes                MetricSettings `mapstructure:"k8s.volume.inodes"`
@@ -116,6 +119,12 @@ func DefaultMetricsSettings() MetricsSettings {
 		ContainerMemoryWorkingSet: MetricSettings{
 			Enabled: true,
 		},
+		K8sContainerStartTime: MetricSettings{
+			Enabled: false,
+		},
+		K8sContainerUptime: MetricSettings{
+			Enabled: false,
+		},
 		K8sNodeCPUTime: MetricSettings{
 			Enabled: true,
 		},
@@ -194,6 +203,9 @@ func DefaultMetricsSettings() MetricsSettings {
 		K8sPodNetworkIo: MetricSettings{
 			Enabled: true,
 		},
+		K8sPodUptime: MetricSettings{
+			Enabled: false,
+		},
 		K8sVolumeAvailable: MetricSettings{
 			Enabled: true,
 		},
@@ -779,6 +791,106 @@ func newMetricContainerMemoryWorkingSet(settings MetricSettings) metricContainer
 	return m
 }
 
+type metricK8sContainerStartTime struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills k8s.container.start_time metric with initial data.
+func (m *metricK8sContainerStartTime) init() {
+	m.data.SetName("k8s.container.start_time")
+	m.data.SetDescription("The time the container started, in seconds since the epoch, changing when the container is restarted")
+	m.data.SetUnit("s")
+	m.data.SetEmptyGauge()
+}
+
+func (m *metricK8sContainerStartTime) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricK8sContainerStartTime) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricK8sContainerStartTime) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricK8sContainerStartTime(settings MetricSettings) metricK8sContainerStartTime {
+	m := metricK8sContainerStartTime{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricK8sContainerUptime struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills k8s.container.uptime metric with initial data.
+func (m *metricK8sContainerUptime) init() {
+	m.data.SetName("k8s.container.uptime")
+	m.data.SetDescription("The time since the container started, reset when the container is restarted")
+	m.data.SetUnit("s")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+}
+
+func (m *metricK8sContainerUptime) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricK8sContainerUptime) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricK8sContainerUptime) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricK8sContainerUptime(settings MetricSettings) metricK8sContainerUptime {
+	m := metricK8sContainerUptime{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricK8sNodeCPUTime struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -2077,6 +2189,57 @@ func newMetricK8sPodNetworkIo(settings MetricSettings) metricK8sPodNetworkIo {
 	return m
 }
 
+type metricK8sPodUptime struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	settings MetricSettings // metric settings provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills k8s.pod.uptime metric with initial data.
+func (m *metricK8sPodUptime) init() {
+	m.data.SetName("k8s.pod.uptime")
+	m.data.SetDescription("The time since the pod started, reset when the pod is recreated")
+	m.data.SetUnit("s")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+}
+
+func (m *metricK8sPodUptime) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
+	if !m.settings.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricK8sPodUptime) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricK8sPodUptime) emit(metrics pmetric.MetricSlice) {
+	if m.settings.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricK8sPodUptime(settings MetricSettings) metricK8sPodUptime {
+	m := metricK8sPodUptime{settings: settings}
+	if settings.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 type metricK8sVolumeAvailable struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	settings MetricSettings // metric settings provided by user.
@@ -2341,6 +2504,8 @@ type MetricsBuilder struct {
 	metricContainerMemoryRss             metricContainerMemoryRss
 	metricContainerMemoryUsage           metricContainerMemoryUsage
 	metricContainerMemoryWorkingSet      metricContainerMemoryWorkingSet
+	metricK8sContainerStartTime          metricK8sContainerStartTime
+	metricK8sContainerUptime             metricK8sContainerUptime
 	metricK8sNodeCPUTime                 metricK8sNodeCPUTime
 	metricK8sNodeCPUUtilization          metricK8sNodeCPUUtilization
 	metricK8sNodeFilesystemAvailable     metricK8sNodeFilesystemAvailable
@@ -2367,6 +2532,7 @@ type MetricsBuilder struct {
 	metricK8sPodMemoryWorkingSet         metricK8sPodMemoryWorkingSet
 	metricK8sPodNetworkErrors            metricK8sPodNetworkErrors
 	metricK8sPodNetworkIo                metricK8sPodNetworkIo
+	metricK8sPodUptime                   metricK8sPodUptime
 	metricK8sVolumeAvailable             metricK8sVolumeAvailable
 	metricK8sVolumeCapacity              metricK8sVolumeCapacity
 	metricK8sVolumeInodes                metricK8sVolumeInodes
@@ -2400,6 +2566,8 @@ func NewMetricsBuilder(settings MetricsSettings, buildInfo component.BuildInfo,
 		metricContainerMemoryRss:             newMetricContainerMemoryRss(settings.ContainerMemoryRss),
 		metricContainerMemoryUsage:           newMetricContainerMemoryUsage(settings.ContainerMemoryUsage),
 		metricContainerMemoryWorkingSet:      newMetricContainerMemoryWorkingSet(settings.ContainerMemoryWorkingSet),
+		metricK8sContainerStartTime:          newMetricK8sContainerStartTime(settings.K8sContainerStartTime),
+		metricK8sContainerUptime:             newMetricK8sContainerUptime(settings.K8sContainerUptime),
 		metricK8sNodeCPUTime:                 newMetricK8sNodeCPUTime(settings.K8sNodeCPUTime),
 		metricK8sNodeCPUUtilization:          newMetricK8sNodeCPUUtilization(settings.K8sNodeCPUUtilization),
 		metricK8sNodeFilesystemAvailable:     newMetricK8sNodeFilesystemAvailable(settings.K8sNodeFilesystemAvailable),
@@ -2426,6 +2594,7 @@ func NewMetricsBuilder(settings MetricsSettings, buildInfo component.BuildInfo,
 		metricK8sPodMemoryWorkingSet:         newMetricK8sPodMemoryWorkingSet(settings.K8sPodMemoryWorkingSet),
 		metricK8sPodNetworkErrors:            newMetricK8sPodNetworkErrors(settings.K8sPodNetworkErrors),
 		metricK8sPodNetworkIo:                newMetricK8sPodNetworkIo(settings.K8sPodNetworkIo),
+		metricK8sPodUptime:                   newMetricK8sPodUptime(settings.K8sPodUptime),
 		metricK8sVolumeAvailable:             newMetricK8sVolumeAvailable(settings.K8sVolumeAvailable),
 		metricK8sVolumeCapacity:              newMetricK8sVolumeCapacity(settings.K8sVolumeCapacity),
 		metricK8sVolumeInodes:                newMetricK8sVolumeInodes(settings.K8sVolumeInodes),
@@ -2599,6 +2768,8 @@ func (mb *MetricsBuilder) EmitForResource(rmo ...ResourceMetricsOption) {
 	mb.metricContainerMemoryRss.emit(ils.Metrics())
 	mb.metricContainerMemoryUsage.emit(ils.Metrics())
 	mb.metricContainerMemoryWorkingSet.emit(ils.Metrics())
+	mb.metricK8sContainerStartTime.emit(ils.Metrics())
+	mb.metricK8sContainerUptime.emit(ils.Metrics())
 	mb.metricK8sNodeCPUTime.emit(ils.Metrics())
 	mb.metricK8sNodeCPUUtilization.emit(ils.Metrics())
 	mb.metricK8sNodeFilesystemAvailable.emit(ils.Metrics())
@@ -2625,6 +2796,7 @@ func (mb *MetricsBuilder) EmitForResource(rmo ...ResourceMetricsOption) {
 	mb.metricK8sPodMemoryWorkingSet.emit(ils.Metrics())
 	mb.metricK8sPodNetworkErrors.emit(ils.Metrics())
 	mb.metricK8sPodNetworkIo.emit(ils.Metrics())
+	mb.metricK8sPodUptime.emit(ils.Metrics())
 	mb.metricK8sVolumeAvailable.emit(ils.Metrics())
 	mb.metricK8sVolumeCapacity.emit(ils.Metrics())
 	mb.metricK8sVolumeInodes.emit(ils.Metrics())
@@ -2704,6 +2876,16 @@ func (mb *MetricsBuilder) RecordContainerMemoryWorkingSetDataPoint(ts pcommon.Ti
 	mb.metricContainerMemoryWorkingSet.recordDataPoint(mb.startTime, ts, val)
 }
 
+// RecordK8sContainerStartTimeDataPoint adds a data point to k8s.container.start_time metric.
+func (mb *MetricsBuilder) RecordK8sContainerStartTimeDataPoint(ts pcommon.Timestamp, val int64) {
+	mb.metricK8sContainerStartTime.recordDataPoint(mb.startTime, ts, val)
+}
+
+// RecordK8sContainerUptimeDataPoint adds a data point to k8s.container.uptime metric.
+func (mb *MetricsBuilder) RecordK8sContainerUptimeDataPoint(ts pcommon.Timestamp, val int64) {
+	mb.metricK8sContainerUptime.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordK8sNodeCPUTimeDataPoint adds a data point to k8s.node.cpu.time metric.
 func (mb *MetricsBuilder) RecordK8sNodeCPUTimeDataPoint(ts pcommon.Timestamp, val float64) {
 	mb.metricK8sNodeCPUTime.recordDataPoint(mb.startTime, ts, val)
@@ -2834,6 +3016,11 @@ func (mb *MetricsBuilder) RecordK8sPodNetworkIoDataPoint(ts pcommon.Timestamp, v
 	mb.metricK8sPodNetworkIo.recordDataPoint(mb.startTime, ts, val, interfaceAttributeValue, directionAttributeValue.String())
 }
 
+// RecordK8sPodUptimeDataPoint adds a data point to k8s.pod.uptime metric.
+func (mb *MetricsBuilder) RecordK8sPodUptimeDataPoint(ts pcommon.Timestamp, val int64) {
+	mb.metricK8sPodUptime.recordDataPoint(mb.startTime, ts, val)
+}
+
 // RecordK8sVolumeAvailableDataPoint adds a data point to k8s.volume.available metric.
 func (mb *MetricsBuilder) RecordK8sVolumeAvailableDataPoint(ts pcommon.Timestamp, val int64) {
 	mb.metricK8sVolumeAvailable.recordDataPoint(mb.startTime, ts, val)
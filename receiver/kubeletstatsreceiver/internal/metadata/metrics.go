@@ -139,3 +139,17 @@ var K8sVolumeMetrics = VolumeMetrics{
 	InodesFree: (*MetricsBuilder).RecordK8sVolumeInodesFreeDataPoint,
 	InodesUsed: (*MetricsBuilder).RecordK8sVolumeInodesUsedDataPoint,
 }
+
+type UptimeMetrics struct {
+	Uptime    RecordIntDataPointFunc
+	StartTime RecordIntDataPointFunc
+}
+
+var PodUptimeMetrics = UptimeMetrics{
+	Uptime: (*MetricsBuilder).RecordK8sPodUptimeDataPoint,
+}
+
+var ContainerUptimeMetrics = UptimeMetrics{
+	Uptime:    (*MetricsBuilder).RecordK8sContainerUptimeDataPoint,
+	StartTime: (*MetricsBuilder).RecordK8sContainerStartTimeDataPoint,
+}
@@ -0,0 +1,65 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeletstatsreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver"
+
+import (
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/internal/kubelet"
+)
+
+// metricGroupIntervals keeps track of the metric groups collected less often than on
+// every collection interval.
+type metricGroupIntervals struct {
+	collectionInterval time.Duration
+	intervals          map[kubelet.MetricGroup]time.Duration
+	lastCollected      map[kubelet.MetricGroup]time.Time
+}
+
+func newMetricGroupIntervals(collectionInterval time.Duration, intervals map[kubelet.MetricGroup]time.Duration) *metricGroupIntervals {
+	return &metricGroupIntervals{
+		collectionInterval: collectionInterval,
+		intervals:          intervals,
+		lastCollected:      map[kubelet.MetricGroup]time.Time{},
+	}
+}
+
+// due returns the groups to collect at now. A group with an interval is collected on
+// the first scrape after its interval elapsed since it was last collected, less half a
+// collection interval so that the jitter of the scrapes doesn't delay it by a whole one.
+func (g *metricGroupIntervals) due(groups map[kubelet.MetricGroup]bool, now time.Time) map[kubelet.MetricGroup]bool {
+	due := make(map[kubelet.MetricGroup]bool, len(groups))
+	for group, collect := range groups {
+		if !collect {
+			continue
+		}
+		if interval, ok := g.intervals[group]; ok {
+			if last, ok := g.lastCollected[group]; ok && now.Sub(last) < interval-g.collectionInterval/2 {
+				continue
+			}
+		}
+		due[group] = true
+	}
+	return due
+}
+
+// collected records that the groups were successfully collected at now.
+func (g *metricGroupIntervals) collected(groups map[kubelet.MetricGroup]bool, now time.Time) {
+	for group := range groups {
+		if _, ok := g.intervals[group]; ok {
+			g.lastCollected[group] = now
+		}
+	}
+}
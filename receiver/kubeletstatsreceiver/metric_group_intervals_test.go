@@ -0,0 +1,50 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeletstatsreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/internal/kubelet"
+)
+
+func TestMetricGroupIntervals(t *testing.T) {
+	g := newMetricGroupIntervals(10*time.Second, map[kubelet.MetricGroup]time.Duration{
+		kubelet.VolumeMetricGroup: 5 * time.Minute,
+	})
+	groups := map[kubelet.MetricGroup]bool{
+		kubelet.NodeMetricGroup:   true,
+		kubelet.VolumeMetricGroup: true,
+	}
+
+	start := time.Now()
+	assert.Equal(t, groups, g.due(groups, start), "all the groups are collected on the first scrape")
+	g.collected(groups, start)
+
+	nodeOnly := map[kubelet.MetricGroup]bool{kubelet.NodeMetricGroup: true}
+	assert.Equal(t, nodeOnly, g.due(groups, start.Add(10*time.Second)))
+	g.collected(nodeOnly, start.Add(10*time.Second))
+	assert.Equal(t, nodeOnly, g.due(groups, start.Add(4*time.Minute+50*time.Second)))
+	// the scrapes are slightly early or late
+	assert.Equal(t, groups, g.due(groups, start.Add(5*time.Minute-time.Second)))
+
+	// the groups that failed to be collected are collected again on the next scrape
+	assert.Equal(t, groups, g.due(groups, start.Add(5*time.Minute+9*time.Second)))
+	g.collected(groups, start.Add(5*time.Minute+9*time.Second))
+	assert.Equal(t, nodeOnly, g.due(groups, start.Add(5*time.Minute+19*time.Second)))
+}
@@ -37,6 +37,8 @@ type scraperOptions struct {
 	extraMetadataLabels   []kubelet.MetadataLabel
 	metricGroupsToCollect map[kubelet.MetricGroup]bool
 	k8sAPIClient          kubernetes.Interface
+	skipUnchangedGauges   SkipUnchangedGaugesConfig
+	metricGroupIntervals  map[kubelet.MetricGroup]time.Duration
 }
 
 type kubletScraper struct {
@@ -48,6 +50,8 @@ type kubletScraper struct {
 	k8sAPIClient          kubernetes.Interface
 	cachedVolumeLabels    map[string][]metadata.ResourceMetricsOption
 	mbs                   *metadata.MetricsBuilders
+	unchangedGauges       *unchangedGaugesFilter
+	groupIntervals        *metricGroupIntervals
 }
 
 func newKubletScraper(
@@ -57,7 +61,7 @@ func newKubletScraper(
 	metricsConfig metadata.MetricsSettings,
 ) (scraperhelper.Scraper, error) {
 	ks := &kubletScraper{
-		statsProvider:         kubelet.NewStatsProvider(restClient),
+		statsProvider:         kubelet.NewStatsProvider(restClient, set.Logger),
 		metadataProvider:      kubelet.NewMetadataProvider(restClient),
 		logger:                set.Logger,
 		extraMetadataLabels:   rOptions.extraMetadataLabels,
@@ -71,10 +75,25 @@ func newKubletScraper(
 			OtherMetricsBuilder:     metadata.NewMetricsBuilder(metricsConfig, set.BuildInfo),
 		},
 	}
+	if rOptions.skipUnchangedGauges.Enabled {
+		ks.unchangedGauges = newUnchangedGaugesFilter(rOptions.skipUnchangedGauges.MaxInterval)
+	}
+	if len(rOptions.metricGroupIntervals) > 0 {
+		ks.groupIntervals = newMetricGroupIntervals(rOptions.collectionInterval, rOptions.metricGroupIntervals)
+	}
 	return scraperhelper.NewScraper(typeStr, ks.scrape)
 }
 
 func (r *kubletScraper) scrape(context.Context) (pmetric.Metrics, error) {
+	now := time.Now()
+	metricGroups := r.metricGroupsToCollect
+	if r.groupIntervals != nil {
+		metricGroups = r.groupIntervals.due(metricGroups, now)
+		if len(metricGroups) == 0 {
+			return pmetric.NewMetrics(), nil
+		}
+	}
+
 	summary, err := r.statsProvider.StatsSummary()
 	if err != nil {
 		r.logger.Error("call to /stats/summary endpoint failed", zap.Error(err))
@@ -92,11 +111,17 @@ func (r *kubletScraper) scrape(context.Context) (pmetric.Metrics, error) {
 	}
 
 	metadata := kubelet.NewMetadata(r.extraMetadataLabels, podsMetadata, r.detailedPVCLabelsSetter())
-	mds := kubelet.MetricsData(r.logger, summary, metadata, r.metricGroupsToCollect, r.mbs)
+	mds := kubelet.MetricsData(r.logger, summary, metadata, metricGroups, r.mbs)
 	md := pmetric.NewMetrics()
 	for i := range mds {
 		mds[i].ResourceMetrics().MoveAndAppendTo(md.ResourceMetrics())
 	}
+	if r.groupIntervals != nil {
+		r.groupIntervals.collected(metricGroups, now)
+	}
+	if r.unchangedGauges != nil {
+		r.unchangedGauges.filter(md, now)
+	}
 	return md, nil
 }
 
@@ -20,10 +20,13 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 	"k8s.io/client-go/kubernetes"
@@ -73,6 +76,40 @@ func TestScraper(t *testing.T) {
 	require.Equal(t, dataLen, md.DataPointCount())
 }
 
+func TestScraperSkipUnchangedGauges(t *testing.T) {
+	options := &scraperOptions{
+		metricGroupsToCollect: allMetricGroups,
+		skipUnchangedGauges: SkipUnchangedGaugesConfig{
+			Enabled:     true,
+			MaxInterval: time.Hour,
+		},
+	}
+	r, err := newKubletScraper(
+		&fakeRestClient{},
+		componenttest.NewNopReceiverCreateSettings(),
+		options,
+		metadata.DefaultMetricsSettings(),
+	)
+	require.NoError(t, err)
+
+	md, err := r.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, dataLen, md.DataPointCount())
+
+	// the stats are the same on the second scrape, only the non gauge series are emitted
+	md, err = r.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Greater(t, md.DataPointCount(), 0)
+	require.Less(t, md.DataPointCount(), dataLen)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ms := rms.At(i).ScopeMetrics().At(0).Metrics()
+		for j := 0; j < ms.Len(); j++ {
+			assert.NotEqual(t, pmetric.MetricTypeGauge, ms.At(j).Type(), ms.At(j).Name())
+		}
+	}
+}
+
 func TestScraperWithMetadata(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -141,6 +178,32 @@ func TestScraperWithMetadata(t *testing.T) {
 	}
 }
 
+func TestScraperWithMetricGroupIntervals(t *testing.T) {
+	options := &scraperOptions{
+		collectionInterval:    10 * time.Second,
+		metricGroupsToCollect: allMetricGroups,
+		metricGroupIntervals: map[kubelet.MetricGroup]time.Duration{
+			kubelet.VolumeMetricGroup: time.Hour,
+		},
+	}
+	r, err := newKubletScraper(
+		&fakeRestClient{},
+		componenttest.NewNopReceiverCreateSettings(),
+		options,
+		metadata.DefaultMetricsSettings(),
+	)
+	require.NoError(t, err)
+
+	md, err := r.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, dataLen, md.DataPointCount())
+
+	// the volume stats aren't collected again before their interval elapsed
+	md, err = r.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, dataLen-numVolumes*volumeMetrics, md.DataPointCount())
+}
+
 func TestScraperWithMetricGroups(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -496,3 +559,11 @@ func (f *fakeRestClient) Pods() ([]byte, error) {
 	}
 	return os.ReadFile("testdata/pods.json")
 }
+
+func (f *fakeRestClient) NodeStats() ([]byte, error) {
+	return nil, errors.New("")
+}
+
+func (f *fakeRestClient) ContainerStats(string, string, string, string) ([]byte, error) {
+	return nil, errors.New("")
+}
@@ -0,0 +1,111 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeletstatsreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver"
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// gaugeSample is the last emitted value of a gauge series.
+type gaugeSample struct {
+	valueType   pmetric.NumberDataPointValueType
+	intValue    int64
+	doubleValue float64
+	emitted     time.Time
+	// seen is the scrape number the series was last seen at.
+	seen uint64
+}
+
+// unchangedGaugesFilter drops the gauge data points whose value didn't change since
+// they were last emitted, unless they were emitted more than maxInterval ago.
+type unchangedGaugesFilter struct {
+	maxInterval time.Duration
+	samples     map[string]*gaugeSample
+	scrapes     uint64
+}
+
+func newUnchangedGaugesFilter(maxInterval time.Duration) *unchangedGaugesFilter {
+	return &unchangedGaugesFilter{
+		maxInterval: maxInterval,
+		samples:     map[string]*gaugeSample{},
+	}
+}
+
+// filter removes the unchanged gauge data points of md, as well as the metrics and the
+// resources left empty. The series missing from md, e.g. of deleted pods, are forgotten.
+func (f *unchangedGaugesFilter) filter(md pmetric.Metrics, now time.Time) {
+	f.scrapes++
+	md.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		resourceKey := attributesKey(rm.Resource().Attributes())
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				if m.Type() != pmetric.MetricTypeGauge {
+					return false
+				}
+				m.Gauge().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+					key := resourceKey + "|" + m.Name() + "|" + attributesKey(dp.Attributes())
+					return f.unchanged(key, dp, now)
+				})
+				return m.Gauge().DataPoints().Len() == 0
+			})
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+
+	for key, sample := range f.samples {
+		if sample.seen != f.scrapes {
+			delete(f.samples, key)
+		}
+	}
+}
+
+// unchanged reports whether the data point can be skipped, and records it otherwise.
+func (f *unchangedGaugesFilter) unchanged(key string, dp pmetric.NumberDataPoint, now time.Time) bool {
+	sample, ok := f.samples[key]
+	if !ok {
+		sample = &gaugeSample{}
+		f.samples[key] = sample
+	}
+	sample.seen = f.scrapes
+
+	if ok && sample.valueType == dp.ValueType() &&
+		sample.intValue == dp.IntValue() && sample.doubleValue == dp.DoubleValue() &&
+		(f.maxInterval == 0 || now.Sub(sample.emitted) < f.maxInterval) {
+		return true
+	}
+
+	sample.valueType = dp.ValueType()
+	sample.intValue = dp.IntValue()
+	sample.doubleValue = dp.DoubleValue()
+	sample.emitted = now
+	return false
+}
+
+// attributesKey returns a string identifying the attributes regardless of their order.
+func attributesKey(attrs pcommon.Map) string {
+	pairs := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		pairs = append(pairs, k+"="+v.AsString())
+		return true
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,79 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubeletstatsreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func gaugeMetrics(pods map[string]float64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for pod, value := range pods {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("k8s.pod.name", pod)
+		ms := rm.ScopeMetrics().AppendEmpty().Metrics()
+		gauge := ms.AppendEmpty()
+		gauge.SetName("k8s.pod.memory.usage")
+		gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(value)
+		sum := ms.AppendEmpty()
+		sum.SetName("k8s.pod.cpu.time")
+		sum.SetEmptySum().DataPoints().AppendEmpty().SetDoubleValue(value)
+	}
+	return md
+}
+
+func TestUnchangedGaugesFilter(t *testing.T) {
+	f := newUnchangedGaugesFilter(time.Minute)
+	now := time.Now()
+
+	md := gaugeMetrics(map[string]float64{"a": 1, "b": 2})
+	f.filter(md, now)
+	assert.Equal(t, 4, md.DataPointCount())
+
+	// the unchanged gauges are skipped, the sums are always emitted
+	md = gaugeMetrics(map[string]float64{"a": 1, "b": 3})
+	f.filter(md, now.Add(10*time.Second))
+	assert.Equal(t, 3, md.DataPointCount())
+	assert.Equal(t, 2, md.ResourceMetrics().Len())
+
+	// the unchanged gauges are emitted again after the max interval
+	md = gaugeMetrics(map[string]float64{"a": 1, "b": 3})
+	f.filter(md, now.Add(time.Minute))
+	assert.Equal(t, 3, md.DataPointCount())
+
+	// the series of the resources that are gone are forgotten
+	md = gaugeMetrics(map[string]float64{"b": 3})
+	f.filter(md, now.Add(70*time.Second))
+	assert.Len(t, f.samples, 1)
+}
+
+func TestUnchangedGaugesFilterRemovesEmptyResources(t *testing.T) {
+	f := newUnchangedGaugesFilter(0)
+	now := time.Now()
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(1)
+	f.filter(md, now)
+	assert.Equal(t, 1, md.DataPointCount())
+
+	md = pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(1)
+	f.filter(md, now.Add(time.Hour))
+	assert.Equal(t, 0, md.ResourceMetrics().Len())
+}
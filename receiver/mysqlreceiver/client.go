@@ -31,6 +31,10 @@ type client interface {
 	getIndexIoWaitsStats() ([]IndexIoWaitsStats, error)
 	getStatementEventsStats() ([]StatementEventStats, error)
 	getTableLockWaitEventStats() ([]tableLockWaitEventStats, error)
+	getLongTransactions() ([]longTransaction, error)
+	getLockWaits() ([]lockWait, error)
+	getCustomQueryRows(query string) ([]map[string]string, error)
+	getServerVariables() (map[string]string, error)
 	Close() error
 }
 
@@ -40,6 +44,9 @@ type mySQLClient struct {
 	statementEventsDigestTextLimit int
 	statementEventsLimit           int
 	statementEventsTimeLimit       time.Duration
+	transactionsThreshold          time.Duration
+	transactionsLimit              int
+	transactionsQueryTextLimit     int
 }
 
 type IoWaitsStats struct {
@@ -106,6 +113,32 @@ type tableLockWaitEventStats struct {
 	sumTimerWriteExternal         int64
 }
 
+// longTransaction is a transaction running for longer than the threshold.
+type longTransaction struct {
+	id           string
+	state        string
+	threadID     int64
+	seconds      int64
+	query        string
+	rowsLocked   int64
+	rowsModified int64
+}
+
+// lockWait is a transaction waiting for longer than the threshold for a lock held by another one.
+type lockWait struct {
+	seconds          int64
+	waitingID        string
+	waitingThreadID  int64
+	waitingQuery     string
+	blockingID       string
+	blockingThreadID int64
+	blockingQuery    string
+	schema           string
+	table            string
+	index            string
+	lockMode         string
+}
+
 var _ client = (*mySQLClient)(nil)
 
 func newMySQLClient(conf *Config) client {
@@ -124,6 +157,9 @@ func newMySQLClient(conf *Config) client {
 		statementEventsDigestTextLimit: conf.StatementEvents.DigestTextLimit,
 		statementEventsLimit:           conf.StatementEvents.Limit,
 		statementEventsTimeLimit:       conf.StatementEvents.TimeLimit,
+		transactionsThreshold:          conf.TransactionSnapshots.Threshold,
+		transactionsLimit:              conf.TransactionSnapshots.Limit,
+		transactionsQueryTextLimit:     conf.TransactionSnapshots.QueryTextLimit,
 	}
 }
 
@@ -274,6 +310,120 @@ func (c *mySQLClient) getTableLockWaitEventStats() ([]tableLockWaitEventStats, e
 	return stats, nil
 }
 
+// getLongTransactions queries the db for the transactions running for longer than the threshold, the oldest first.
+func (c *mySQLClient) getLongTransactions() ([]longTransaction, error) {
+	query := fmt.Sprintf("SELECT trx_id, trx_state, trx_mysql_thread_id,"+
+		"TIMESTAMPDIFF(SECOND, trx_started, NOW()) as SECONDS,"+
+		"LEFT(ifnull(trx_query, ''), %d) as QUERY, trx_rows_locked, trx_rows_modified "+
+		"FROM information_schema.innodb_trx "+
+		"WHERE trx_started <= DATE_SUB(NOW(), INTERVAL %d SECOND) "+
+		"ORDER BY trx_started "+
+		"LIMIT %d",
+		c.transactionsQueryTextLimit,
+		int64(c.transactionsThreshold.Seconds()),
+		c.transactionsLimit)
+
+	rows, err := c.client.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []longTransaction
+	for rows.Next() {
+		var t longTransaction
+		err := rows.Scan(&t.id, &t.state, &t.threadID, &t.seconds, &t.query, &t.rowsLocked, &t.rowsModified)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+
+	return transactions, nil
+}
+
+// getLockWaits queries the db for the transactions waiting for longer than the threshold for a lock,
+// with the transactions blocking them, the longest waits first. It requires MySQL 8.0.
+func (c *mySQLClient) getLockWaits() ([]lockWait, error) {
+	query := fmt.Sprintf("SELECT TIMESTAMPDIFF(SECOND, r.trx_wait_started, NOW()) as SECONDS,"+
+		"r.trx_id, r.trx_mysql_thread_id, LEFT(ifnull(r.trx_query, ''), %[1]d) as WAITING_QUERY,"+
+		"b.trx_id, b.trx_mysql_thread_id, LEFT(ifnull(b.trx_query, ''), %[1]d) as BLOCKING_QUERY,"+
+		"ifnull(l.OBJECT_SCHEMA, ''), ifnull(l.OBJECT_NAME, ''), ifnull(l.INDEX_NAME, ''), l.LOCK_MODE "+
+		"FROM performance_schema.data_lock_waits w "+
+		"JOIN information_schema.innodb_trx r ON r.trx_id = w.REQUESTING_ENGINE_TRANSACTION_ID "+
+		"JOIN information_schema.innodb_trx b ON b.trx_id = w.BLOCKING_ENGINE_TRANSACTION_ID "+
+		"JOIN performance_schema.data_locks l ON l.ENGINE_LOCK_ID = w.REQUESTING_ENGINE_LOCK_ID "+
+		"WHERE r.trx_wait_started <= DATE_SUB(NOW(), INTERVAL %[2]d SECOND) "+
+		"ORDER BY r.trx_wait_started "+
+		"LIMIT %[3]d",
+		c.transactionsQueryTextLimit,
+		int64(c.transactionsThreshold.Seconds()),
+		c.transactionsLimit)
+
+	rows, err := c.client.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var waits []lockWait
+	for rows.Next() {
+		var w lockWait
+		err := rows.Scan(&w.seconds,
+			&w.waitingID, &w.waitingThreadID, &w.waitingQuery,
+			&w.blockingID, &w.blockingThreadID, &w.blockingQuery,
+			&w.schema, &w.table, &w.index, &w.lockMode)
+		if err != nil {
+			return nil, err
+		}
+		waits = append(waits, w)
+	}
+
+	return waits, nil
+}
+
+// getCustomQueryRows runs a user defined query and returns its rows as the values of their
+// columns by name, the NULL values are left out.
+func (c *mySQLClient) getCustomQueryRows(query string) ([]map[string]string, error) {
+	rows, err := c.client.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var out []map[string]string
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]string, len(columns))
+		for i, column := range columns {
+			if values[i].Valid {
+				row[column] = values[i].String
+			}
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+// getServerVariables queries the db for the global variables used to detect its version.
+func (c *mySQLClient) getServerVariables() (map[string]string, error) {
+	query := "SHOW GLOBAL VARIABLES WHERE Variable_name IN " +
+		"('version', 'version_comment', 'performance_schema', 'aurora_version');"
+	return Query(*c, query)
+}
+
 func Query(c mySQLClient, query string) (map[string]string, error) {
 	rows, err := c.client.Query(query)
 	if err != nil {
@@ -15,6 +15,8 @@
 package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/config/confignet"
@@ -27,6 +29,9 @@ const (
 	defaultStatementEventsDigestTextLimit = 120
 	defaultStatementEventsLimit           = 250
 	defaultStatementEventsTimeLimit       = 24 * time.Hour
+	defaultTransactionsThreshold          = 30 * time.Second
+	defaultTransactionsLimit              = 100
+	defaultTransactionsQueryTextLimit     = 1024
 )
 
 type Config struct {
@@ -36,12 +41,120 @@ type Config struct {
 	Database                                string `mapstructure:"database,omitempty"`
 	AllowNativePasswords                    bool   `mapstructure:"allow_native_passwords,omitempty"`
 	confignet.NetAddr                       `mapstructure:",squash"`
-	Metrics                                 metadata.MetricsSettings `mapstructure:"metrics"`
-	StatementEvents                         StatementEventsConfig    `mapstructure:"statement_events"`
+	Metrics                                 metadata.MetricsSettings   `mapstructure:"metrics"`
+	StatementEvents                         StatementEventsConfig      `mapstructure:"statement_events"`
+	TransactionSnapshots                    TransactionSnapshotsConfig `mapstructure:"transaction_snapshots"`
+	CustomQueries                           []CustomQuery              `mapstructure:"custom_queries"`
 }
 
 type StatementEventsConfig struct {
 	DigestTextLimit int           `mapstructure:"digest_text_limit"`
 	Limit           int           `mapstructure:"limit"`
 	TimeLimit       time.Duration `mapstructure:"time_limit"`
+	// ObfuscateDigestText replaces the literals left in the digest text with placeholders and
+	// normalizes it, so that the statement metrics don't carry sensitive values.
+	ObfuscateDigestText bool `mapstructure:"obfuscate_digest_text"`
+}
+
+// TransactionSnapshotsConfig configures the logs of the long running transactions and of the
+// lock waits, emitted by the logs receiver on each collection interval.
+type TransactionSnapshotsConfig struct {
+	// Threshold is the duration above which a running transaction or a lock wait is emitted.
+	Threshold time.Duration `mapstructure:"threshold"`
+	// Limit is the maximum number of transactions, and of lock waits, emitted per collection.
+	Limit int `mapstructure:"limit"`
+	// QueryTextLimit is the maximum length of the statements of the transactions.
+	QueryTextLimit int `mapstructure:"query_text_limit"`
+	// ObfuscateQueries replaces the literals of the statements with placeholders, so that the logs
+	// don't carry sensitive values.
+	ObfuscateQueries bool `mapstructure:"obfuscate_queries"`
+}
+
+// CustomQuery is a user defined query run with the connection of the receiver on each scrape,
+// its rows are turned into data points of the metrics.
+type CustomQuery struct {
+	SQL     string         `mapstructure:"sql"`
+	Metrics []CustomMetric `mapstructure:"metrics"`
+}
+
+// CustomMetric maps the columns of the rows of a custom query to a metric: each row is a data point
+// with the value of ValueColumn and the attributes of AttributeColumns.
+type CustomMetric struct {
+	MetricName       string            `mapstructure:"metric_name"`
+	ValueColumn      string            `mapstructure:"value_column"`
+	AttributeColumns []string          `mapstructure:"attribute_columns"`
+	StaticAttributes map[string]string `mapstructure:"static_attributes"`
+	// DataType is the type of the metric, gauge or sum, gauge by default.
+	DataType string `mapstructure:"data_type"`
+	// ValueType is the type of the values of the data points, int or double, int by default.
+	ValueType string `mapstructure:"value_type"`
+	// Monotonic marks the sums as monotonic.
+	Monotonic   bool   `mapstructure:"monotonic"`
+	Unit        string `mapstructure:"unit"`
+	Description string `mapstructure:"description"`
+}
+
+const (
+	customDataTypeGauge = "gauge"
+	customDataTypeSum   = "sum"
+
+	customValueTypeInt    = "int"
+	customValueTypeDouble = "double"
+)
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.TransactionSnapshots.Threshold < 0 {
+		return errors.New("transaction_snapshots.threshold must not be negative")
+	}
+	if cfg.TransactionSnapshots.Limit <= 0 {
+		return errors.New("transaction_snapshots.limit must be positive")
+	}
+	if cfg.TransactionSnapshots.QueryTextLimit <= 0 {
+		return errors.New("transaction_snapshots.query_text_limit must be positive")
+	}
+	for i, q := range cfg.CustomQueries {
+		if err := q.validate(); err != nil {
+			return fmt.Errorf("custom_queries[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (q CustomQuery) validate() error {
+	if q.SQL == "" {
+		return errors.New("sql is required")
+	}
+	if len(q.Metrics) == 0 {
+		return errors.New("metrics are required")
+	}
+	for i, m := range q.Metrics {
+		if err := m.validate(); err != nil {
+			return fmt.Errorf("metrics[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (m CustomMetric) validate() error {
+	if m.MetricName == "" {
+		return errors.New("metric_name is required")
+	}
+	if m.ValueColumn == "" {
+		return errors.New("value_column is required")
+	}
+	switch m.DataType {
+	case "", customDataTypeGauge, customDataTypeSum:
+	default:
+		return fmt.Errorf("unsupported data_type %q, must be %q or %q", m.DataType, customDataTypeGauge, customDataTypeSum)
+	}
+	switch m.ValueType {
+	case "", customValueTypeInt, customValueTypeDouble:
+	default:
+		return fmt.Errorf("unsupported value_type %q, must be %q or %q", m.ValueType, customValueTypeInt, customValueTypeDouble)
+	}
+	if m.Monotonic && m.DataType != customDataTypeSum {
+		return errors.New("monotonic is only supported by the sums")
+	}
+	return nil
 }
@@ -41,6 +41,57 @@ func TestLoadConfig(t *testing.T) {
 	expected.Password = "$MYSQL_PASSWORD"
 	expected.Database = "otel"
 	expected.CollectionInterval = 10 * time.Second
+	expected.StatementEvents.ObfuscateDigestText = true
+	expected.TransactionSnapshots.Threshold = time.Minute
+	expected.TransactionSnapshots.ObfuscateQueries = true
+	expected.CustomQueries = []CustomQuery{{
+		SQL: "SELECT status, COUNT(*) AS orders FROM shop.orders GROUP BY status",
+		Metrics: []CustomMetric{{
+			MetricName:       "shop.orders",
+			ValueColumn:      "orders",
+			AttributeColumns: []string{"status"},
+			DataType:         "sum",
+			Monotonic:        true,
+		}},
+	}}
 
 	require.Equal(t, expected, cfg)
 }
+
+func TestValidate(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.TransactionSnapshots.Threshold = -time.Second
+	require.EqualError(t, cfg.Validate(), "transaction_snapshots.threshold must not be negative")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.TransactionSnapshots.Limit = 0
+	require.EqualError(t, cfg.Validate(), "transaction_snapshots.limit must be positive")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.TransactionSnapshots.QueryTextLimit = 0
+	require.EqualError(t, cfg.Validate(), "transaction_snapshots.query_text_limit must be positive")
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.CustomQueries = []CustomQuery{{Metrics: []CustomMetric{{MetricName: "a", ValueColumn: "b"}}}}
+	require.EqualError(t, cfg.Validate(), "custom_queries[0]: sql is required")
+
+	cfg.CustomQueries = []CustomQuery{{SQL: "SELECT 1"}}
+	require.EqualError(t, cfg.Validate(), "custom_queries[0]: metrics are required")
+
+	cfg.CustomQueries = []CustomQuery{{SQL: "SELECT 1", Metrics: []CustomMetric{{ValueColumn: "b"}}}}
+	require.EqualError(t, cfg.Validate(), "custom_queries[0]: metrics[0]: metric_name is required")
+
+	cfg.CustomQueries = []CustomQuery{{SQL: "SELECT 1", Metrics: []CustomMetric{{MetricName: "a"}}}}
+	require.EqualError(t, cfg.Validate(), "custom_queries[0]: metrics[0]: value_column is required")
+
+	cfg.CustomQueries = []CustomQuery{{SQL: "SELECT 1", Metrics: []CustomMetric{{MetricName: "a", ValueColumn: "b", DataType: "histogram"}}}}
+	require.EqualError(t, cfg.Validate(), `custom_queries[0]: metrics[0]: unsupported data_type "histogram", must be "gauge" or "sum"`)
+
+	cfg.CustomQueries = []CustomQuery{{SQL: "SELECT 1", Metrics: []CustomMetric{{MetricName: "a", ValueColumn: "b", ValueType: "string"}}}}
+	require.EqualError(t, cfg.Validate(), `custom_queries[0]: metrics[0]: unsupported value_type "string", must be "int" or "double"`)
+
+	cfg.CustomQueries = []CustomQuery{{SQL: "SELECT 1", Metrics: []CustomMetric{{MetricName: "a", ValueColumn: "b", Monotonic: true}}}}
+	require.EqualError(t, cfg.Validate(), "custom_queries[0]: metrics[0]: monotonic is only supported by the sums")
+}
@@ -0,0 +1,123 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+	"go.uber.org/zap"
+)
+
+// scrapeCustomQueries runs the user defined queries and adds their metrics to the scope of the
+// instance, each row of a query being a data point of each of its metrics.
+func (m *mySQLScraper) scrapeCustomQueries(now pcommon.Timestamp, md pmetric.Metrics, errs *scrapererror.ScrapeErrors) {
+	var metrics pmetric.MetricSlice
+	for _, q := range m.config.CustomQueries {
+		rows, err := m.sqlclient.getCustomQueryRows(q.SQL)
+		if err != nil {
+			m.logger.Error("Failed to run the custom query", zap.String("query", q.SQL), zap.Error(err))
+			errs.AddPartial(len(q.Metrics), err)
+			continue
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		if metrics == (pmetric.MetricSlice{}) {
+			metrics = m.customMetricsSlice(md)
+		}
+		for _, cfg := range q.Metrics {
+			m.addCustomMetric(metrics, cfg, rows, now, errs)
+		}
+	}
+}
+
+// customMetricsSlice returns the metrics of the scope of the instance, creating it when no other
+// metric was scraped.
+func (m *mySQLScraper) customMetricsSlice(md pmetric.Metrics) pmetric.MetricSlice {
+	if md.ResourceMetrics().Len() > 0 {
+		return md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	}
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("mysql.instance.endpoint", m.config.Endpoint)
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(scopeName)
+	return sm.Metrics()
+}
+
+func (m *mySQLScraper) addCustomMetric(metrics pmetric.MetricSlice, cfg CustomMetric, rows []map[string]string, now pcommon.Timestamp, errs *scrapererror.ScrapeErrors) {
+	metric := pmetric.NewMetric()
+	metric.SetName(cfg.MetricName)
+	metric.SetUnit(cfg.Unit)
+	metric.SetDescription(cfg.Description)
+	var dps pmetric.NumberDataPointSlice
+	if cfg.DataType == customDataTypeSum {
+		sum := metric.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		sum.SetIsMonotonic(cfg.Monotonic)
+		dps = sum.DataPoints()
+	} else {
+		dps = metric.SetEmptyGauge().DataPoints()
+	}
+
+	for _, row := range rows {
+		value, ok := row[cfg.ValueColumn]
+		if !ok {
+			errs.AddPartial(1, fmt.Errorf("metric %s: no value in column %q", cfg.MetricName, cfg.ValueColumn))
+			continue
+		}
+		dp := pmetric.NewNumberDataPoint()
+		if err := setCustomValue(dp, cfg.ValueType, value); err != nil {
+			errs.AddPartial(1, fmt.Errorf("metric %s: %w", cfg.MetricName, err))
+			continue
+		}
+		dp.SetTimestamp(now)
+		if cfg.DataType == customDataTypeSum {
+			dp.SetStartTimestamp(m.startTime)
+		}
+		attrs := dp.Attributes()
+		for k, v := range cfg.StaticAttributes {
+			attrs.PutStr(k, v)
+		}
+		for _, column := range cfg.AttributeColumns {
+			attrs.PutStr(column, row[column])
+		}
+		dp.MoveTo(dps.AppendEmpty())
+	}
+
+	if dps.Len() > 0 {
+		metric.MoveTo(metrics.AppendEmpty())
+	}
+}
+
+func setCustomValue(dp pmetric.NumberDataPoint, valueType, value string) error {
+	if valueType == customValueTypeDouble {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		dp.SetDoubleValue(f)
+		return nil
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return err
+	}
+	dp.SetIntValue(i)
+	return nil
+}
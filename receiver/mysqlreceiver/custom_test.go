@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/scrapererror"
+)
+
+func newCustomQueriesConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr = confignet.NetAddr{Endpoint: "localhost:3306"}
+	cfg.CustomQueries = []CustomQuery{
+		{
+			SQL: "custom_orders",
+			Metrics: []CustomMetric{
+				{
+					MetricName:       "shop.orders",
+					ValueColumn:      "orders",
+					AttributeColumns: []string{"status"},
+					StaticAttributes: map[string]string{"shop": "eu"},
+					DataType:         customDataTypeSum,
+					Monotonic:        true,
+					Unit:             "{orders}",
+					Description:      "Orders per status.",
+				},
+				{
+					MetricName:       "shop.amount",
+					ValueColumn:      "amount",
+					AttributeColumns: []string{"status", "currency"},
+					ValueType:        customValueTypeDouble,
+				},
+			},
+		},
+		{
+			SQL:     "custom_empty",
+			Metrics: []CustomMetric{{MetricName: "shop.empty", ValueColumn: "count"}},
+		},
+		{
+			SQL:     "missing",
+			Metrics: []CustomMetric{{MetricName: "shop.missing", ValueColumn: "count"}},
+		},
+	}
+	return cfg
+}
+
+func findMetric(t *testing.T, metrics pmetric.MetricSlice, name string) pmetric.Metric {
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() == name {
+			return metrics.At(i)
+		}
+	}
+	require.Failf(t, "metric not found", "metric %s", name)
+	return pmetric.Metric{}
+}
+
+func TestScrapeCustomQueries(t *testing.T) {
+	scraper := newMySQLScraper(componenttest.NewNopReceiverCreateSettings(), newCustomQueriesConfig())
+	scraper.sqlclient = &mockClient{
+		globalStatsFile:             "global_stats",
+		innodbStatsFile:             "innodb_stats",
+		tableIoWaitsFile:            "table_io_waits_stats",
+		indexIoWaitsFile:            "index_io_waits_stats",
+		statementEventsFile:         "statement_events",
+		tableLockWaitEventStatsFile: "table_lock_wait_event_stats",
+	}
+
+	md, err := scraper.scrape(context.Background())
+	var partialError scrapererror.PartialScrapeError
+	require.True(t, errors.As(err, &partialError), "returned error was not PartialScrapeError")
+	// the missing query, the NULL amount and the order count failing to parse
+	assert.Equal(t, 3, partialError.Failed)
+
+	// the custom metrics are added to the metrics of the instance
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	rm := md.ResourceMetrics().At(0)
+	endpoint, ok := rm.Resource().Attributes().Get("mysql.instance.endpoint")
+	require.True(t, ok)
+	assert.Equal(t, "localhost:3306", endpoint.Str())
+	metrics := rm.ScopeMetrics().At(0).Metrics()
+
+	orders := findMetric(t, metrics, "shop.orders")
+	assert.Equal(t, "{orders}", orders.Unit())
+	assert.Equal(t, "Orders per status.", orders.Description())
+	require.Equal(t, pmetric.MetricTypeSum, orders.Type())
+	assert.True(t, orders.Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, orders.Sum().AggregationTemporality())
+	dps := orders.Sum().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, int64(12), dps.At(0).IntValue())
+	assert.Equal(t, map[string]interface{}{"shop": "eu", "status": "paid"}, dps.At(0).Attributes().AsRaw())
+	assert.Equal(t, scraper.startTime, dps.At(0).StartTimestamp())
+	assert.Equal(t, int64(3), dps.At(1).IntValue())
+	assert.Equal(t, map[string]interface{}{"shop": "eu", "status": "pending"}, dps.At(1).Attributes().AsRaw())
+
+	amount := findMetric(t, metrics, "shop.amount")
+	require.Equal(t, pmetric.MetricTypeGauge, amount.Type())
+	dps = amount.Gauge().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, 340.5, dps.At(0).DoubleValue())
+	assert.Equal(t, map[string]interface{}{"status": "paid", "currency": "EUR"}, dps.At(0).Attributes().AsRaw())
+	assert.Equal(t, 12.25, dps.At(1).DoubleValue())
+	assert.Equal(t, map[string]interface{}{"status": "refunded", "currency": "EUR"}, dps.At(1).Attributes().AsRaw())
+	assert.Zero(t, dps.At(1).StartTimestamp())
+}
+
+func TestScrapeCustomQueriesOnly(t *testing.T) {
+	cfg := newCustomQueriesConfig()
+	cfg.CustomQueries = cfg.CustomQueries[:1]
+	scraper := newMySQLScraper(componenttest.NewNopReceiverCreateSettings(), cfg)
+	// none of the stats of the instance is available
+	scraper.sqlclient = &mockClient{
+		globalStatsFile:             "missing",
+		innodbStatsFile:             "missing",
+		tableIoWaitsFile:            "missing",
+		indexIoWaitsFile:            "missing",
+		statementEventsFile:         "missing",
+		tableLockWaitEventStatsFile: "missing",
+	}
+
+	md, err := scraper.scrape(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	rm := md.ResourceMetrics().At(0)
+	assert.Equal(t, map[string]interface{}{"mysql.instance.endpoint": "localhost:3306"}, rm.Resource().Attributes().AsRaw())
+	sm := rm.ScopeMetrics().At(0)
+	assert.Equal(t, scopeName, sm.Scope().Name())
+	require.Equal(t, 2, sm.Metrics().Len())
+	assert.Equal(t, "shop.orders", sm.Metrics().At(0).Name())
+	assert.Equal(t, "shop.amount", sm.Metrics().At(1).Name())
+}
@@ -28,15 +28,17 @@ import (
 )
 
 const (
-	typeStr   = "mysql"
-	stability = component.StabilityLevelBeta
+	typeStr       = "mysql"
+	stability     = component.StabilityLevelBeta
+	logsStability = component.StabilityLevelAlpha
 )
 
 func NewFactory() component.ReceiverFactory {
 	return component.NewReceiverFactory(
 		typeStr,
 		createDefaultConfig,
-		component.WithMetricsReceiver(createMetricsReceiver, stability))
+		component.WithMetricsReceiver(createMetricsReceiver, stability),
+		component.WithLogsReceiver(createLogsReceiver, logsStability))
 }
 
 func createDefaultConfig() component.ReceiverConfig {
@@ -57,6 +59,11 @@ func createDefaultConfig() component.ReceiverConfig {
 			Limit:           defaultStatementEventsLimit,
 			TimeLimit:       defaultStatementEventsTimeLimit,
 		},
+		TransactionSnapshots: TransactionSnapshotsConfig{
+			Threshold:      defaultTransactionsThreshold,
+			Limit:          defaultTransactionsLimit,
+			QueryTextLimit: defaultTransactionsQueryTextLimit,
+		},
 	}
 }
 
@@ -81,3 +88,12 @@ func createMetricsReceiver(
 		scraperhelper.AddScraper(scraper),
 	)
 }
+
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateSettings,
+	rConf component.ReceiverConfig,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	return newTransactionLogsReceiver(params, rConf.(*Config), consumer), nil
+}
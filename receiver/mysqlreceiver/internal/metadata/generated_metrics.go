@@ -3334,6 +3334,13 @@ func WithMysqlInstanceEndpoint(val string) ResourceMetricsOption {
 	}
 }
 
+// WithMysqlInstanceFlavor sets provided value as "mysql.instance.flavor" attribute for current resource.
+func WithMysqlInstanceFlavor(val string) ResourceMetricsOption {
+	return func(rm pmetric.ResourceMetrics) {
+		rm.Resource().Attributes().PutStr("mysql.instance.flavor", val)
+	}
+}
+
 // WithStartTimeOverride overrides start time for all the resource metrics data points.
 // This option should be only used if different start time has to be set on metrics coming from different resources.
 func WithStartTimeOverride(start pcommon.Timestamp) ResourceMetricsOption {
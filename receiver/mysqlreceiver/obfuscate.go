@@ -0,0 +1,165 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// inListRegex matches the IN lists left with placeholders only once the literals are replaced.
+var inListRegex = regexp.MustCompile(`(?i)\bIN ?\( ?\?(?: ?, ?\?)* ?\)`)
+
+// obfuscateDigestText replaces the string and numeric literals of a statement digest with
+// placeholders, drops its comments, collapses its whitespaces and IN lists, and truncates
+// the result to limit characters.
+func obfuscateDigestText(text string, limit int) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	// prev is the previous byte of the input, used to tell numeric literals from
+	// digits being part of an identifier. Whitespaces and comments are a space.
+	var prev byte
+	space := false
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			space, prev = true, ' '
+			continue
+		case c == '#' || (c == '-' && strings.HasPrefix(text[i:], "-- ")):
+			i = skipUntil(text, i, "\n")
+			space, prev = true, ' '
+			continue
+		case c == '/' && strings.HasPrefix(text[i:], "/*"):
+			i = skipUntil(text, i+2, "*/")
+			space, prev = true, ' '
+			continue
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+
+		switch {
+		case c == '\'' || c == '"':
+			i = skipQuoted(text, i)
+			b.WriteByte('?')
+			prev = '?'
+		case c == '`':
+			end := skipQuoted(text, i)
+			b.WriteString(text[i:end])
+			i, prev = end, '`'
+		case isDigit(c) && !isIdentifierByte(prev):
+			i = skipNumber(text, i)
+			b.WriteByte('?')
+			prev = '?'
+		default:
+			b.WriteByte(c)
+			i, prev = i+1, c
+		}
+	}
+
+	obfuscated := inListRegex.ReplaceAllString(b.String(), "IN (...)")
+	return truncate(obfuscated, limit)
+}
+
+// skipUntil returns the index following the first occurrence of end in text from i,
+// or the length of text when end isn't found.
+func skipUntil(text string, i int, end string) int {
+	idx := strings.Index(text[i:], end)
+	if idx < 0 {
+		return len(text)
+	}
+	return i + idx + len(end)
+}
+
+// skipQuoted returns the index following the quoted literal starting at i. Doubled
+// quotes and backslash escapes are part of the literal, an unterminated literal,
+// e.g. cut by the digest text limit, spans until the end of text.
+func skipQuoted(text string, i int) int {
+	quote := text[i]
+	for j := i + 1; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case quote:
+			if j+1 < len(text) && text[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(text)
+}
+
+// skipNumber returns the index following the decimal, hexadecimal or scientific
+// numeric literal starting at i.
+func skipNumber(text string, i int) int {
+	if strings.HasPrefix(text[i:], "0x") || strings.HasPrefix(text[i:], "0X") {
+		i += 2
+		for i < len(text) && isHexDigit(text[i]) {
+			i++
+		}
+		return i
+	}
+	for i < len(text) && (isDigit(text[i]) || text[i] == '.') {
+		i++
+	}
+	if i < len(text) && (text[i] == 'e' || text[i] == 'E') {
+		j := i + 1
+		if j < len(text) && (text[j] == '+' || text[j] == '-') {
+			j++
+		}
+		if j < len(text) && isDigit(text[j]) {
+			i = j
+			for i < len(text) && isDigit(text[i]) {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+// truncate cuts text to limit characters without splitting a multi-byte character.
+func truncate(text string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := 0
+	for i := range text {
+		if runes == limit {
+			return text[:i]
+		}
+		runes++
+	}
+	return text
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isIdentifierByte(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c == '$' || c >= utf8.RuneSelf
+}
@@ -0,0 +1,102 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObfuscateDigestText(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		text     string
+		limit    int
+		expected string
+	}{
+		{
+			desc:     "already normalized digest",
+			text:     "SELECT * FROM `users` WHERE `id` = ? AND `name` IN (...)",
+			expected: "SELECT * FROM `users` WHERE `id` = ? AND `name` IN (...)",
+		},
+		{
+			desc:     "string literals",
+			text:     `SELECT * FROM users WHERE email = 'john''s@example.com' OR email = "j\"d@example.com"`,
+			expected: "SELECT * FROM users WHERE email = ? OR email = ?",
+		},
+		{
+			desc:     "numeric literals",
+			text:     "UPDATE t2 SET c1 = 42, c2 = -3.14, c3 = 1e-10, c4 = 0xFF WHERE id = 7",
+			expected: "UPDATE t2 SET c1 = ?, c2 = -?, c3 = ?, c4 = ? WHERE id = ?",
+		},
+		{
+			desc:     "numeric literal after a keyword",
+			text:     "SELECT * FROM t LIMIT 10",
+			expected: "SELECT * FROM t LIMIT ?",
+		},
+		{
+			desc:     "numeric literal after an operator and a keyword",
+			text:     "SELECT * FROM t WHERE a = 1 AND 42 = b",
+			expected: "SELECT * FROM t WHERE a = ? AND ? = b",
+		},
+		{
+			desc:     "numeric literal only",
+			text:     "select 1234567",
+			expected: "select ?",
+		},
+		{
+			desc:     "numeric literal after a comment",
+			text:     "SELECT/* hint */42, a1/**/7 FROM t",
+			expected: "SELECT ?, a1 ? FROM t",
+		},
+		{
+			desc:     "digits of identifiers",
+			text:     "SELECT c1, t2.c3 FROM t2",
+			expected: "SELECT c1, t2.c3 FROM t2",
+		},
+		{
+			desc:     "IN lists without space",
+			text:     "SELECT a FROM t WHERE x IN(1,2,3) OR y in( 'a' )",
+			expected: "SELECT a FROM t WHERE x IN (...) OR y IN (...)",
+		},
+		{
+			desc:     "IN lists",
+			text:     "SELECT a FROM t WHERE b IN (1, 2, 3) AND c in ('x','y')",
+			expected: "SELECT a FROM t WHERE b IN (...) AND c IN (...)",
+		},
+		{
+			desc:     "comments and whitespaces",
+			text:     "SELECT /* secret */ a\n\tFROM t -- trailing\nWHERE b = 'c' # end",
+			expected: "SELECT a FROM t WHERE b = ?",
+		},
+		{
+			desc:     "unterminated literal cut by the digest text limit",
+			text:     "INSERT INTO t VALUES ('4111 1111 1111",
+			expected: "INSERT INTO t VALUES (?",
+		},
+		{
+			desc:     "truncated",
+			text:     "SELECT é FROM t WHERE a = 1",
+			limit:    8,
+			expected: "SELECT é",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, obfuscateDigestText(tc.text, tc.limit))
+		})
+	}
+}
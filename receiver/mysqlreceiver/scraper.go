@@ -38,6 +38,10 @@ type mySQLScraper struct {
 	logger    *zap.Logger
 	config    *Config
 	mb        *metadata.MetricsBuilder
+	// startTime is the start time of the sums of the custom queries.
+	startTime pcommon.Timestamp
+	// version is the version of the server, nil until it is detected.
+	version *serverVersion
 }
 
 func newMySQLScraper(
@@ -45,9 +49,10 @@ func newMySQLScraper(
 	config *Config,
 ) *mySQLScraper {
 	return &mySQLScraper{
-		logger: settings.Logger,
-		config: config,
-		mb:     metadata.NewMetricsBuilder(config.Metrics, settings.BuildInfo),
+		logger:    settings.Logger,
+		config:    config,
+		mb:        metadata.NewMetricsBuilder(config.Metrics, settings.BuildInfo),
+		startTime: pcommon.NewTimestampFromTime(time.Now()),
 	}
 }
 
@@ -78,6 +83,10 @@ func (m *mySQLScraper) scrape(context.Context) (pmetric.Metrics, error) {
 		return pmetric.Metrics{}, errors.New("failed to connect to http client")
 	}
 
+	if m.version == nil {
+		m.version = detectServerVersion(m.logger, m.sqlclient)
+	}
+
 	now := pcommon.NewTimestampFromTime(time.Now())
 
 	// collect innodb metrics.
@@ -94,21 +103,31 @@ func (m *mySQLScraper) scrape(context.Context) (pmetric.Metrics, error) {
 		addPartialIfError(errs, m.mb.RecordMysqlBufferPoolLimitDataPoint(now, v))
 	}
 
-	// collect io_waits metrics.
-	m.scrapeTableIoWaitsStats(now, errs)
-	m.scrapeIndexIoWaitsStats(now, errs)
+	if m.version.supportsPerformanceSchema() {
+		// collect io_waits metrics.
+		m.scrapeTableIoWaitsStats(now, errs)
+		m.scrapeIndexIoWaitsStats(now, errs)
 
-	// collect performance event statements metrics.
-	m.scrapeStatementEventsStats(now, errs)
-	// collect lock table events metrics
-	m.scrapeTableLockWaitEventStats(now, errs)
+		// collect performance event statements metrics.
+		m.scrapeStatementEventsStats(now, errs)
+		// collect lock table events metrics
+		m.scrapeTableLockWaitEventStats(now, errs)
+	}
 
 	// collect global status metrics.
 	m.scrapeGlobalStats(now, errs)
 
-	m.mb.EmitForResource(metadata.WithMysqlInstanceEndpoint(m.config.Endpoint))
+	options := []metadata.ResourceMetricsOption{metadata.WithMysqlInstanceEndpoint(m.config.Endpoint)}
+	if flavor := m.version.flavorName(); flavor != "" {
+		options = append(options, metadata.WithMysqlInstanceFlavor(flavor))
+	}
+	m.mb.EmitForResource(options...)
+	md := m.mb.Emit()
+
+	// collect the metrics of the custom queries.
+	m.scrapeCustomQueries(now, md, errs)
 
-	return m.mb.Emit(), errs.Combine()
+	return md, errs.Combine()
 }
 
 func (m *mySQLScraper) scrapeGlobalStats(now pcommon.Timestamp, errs *scrapererror.ScrapeErrors) {
@@ -456,6 +475,9 @@ func (m *mySQLScraper) scrapeStatementEventsStats(now pcommon.Timestamp, errs *s
 
 	for i := 0; i < len(statementEventsStats); i++ {
 		s := statementEventsStats[i]
+		if m.config.StatementEvents.ObfuscateDigestText {
+			s.digestText = obfuscateDigestText(s.digestText, m.config.StatementEvents.DigestTextLimit)
+		}
 		m.mb.RecordMysqlStatementEventCountDataPoint(now, s.countCreatedTmpDiskTables, s.schema, s.digest, s.digestText, metadata.AttributeEventStateCreatedTmpDiskTables)
 		m.mb.RecordMysqlStatementEventCountDataPoint(now, s.countCreatedTmpTables, s.schema, s.digest, s.digestText, metadata.AttributeEventStateCreatedTmpTables)
 		m.mb.RecordMysqlStatementEventCountDataPoint(now, s.countErrors, s.schema, s.digest, s.digestText, metadata.AttributeEventStateErrors)
@@ -112,6 +112,32 @@ func TestScrape(t *testing.T) {
 		require.Equal(t, partialError.Failed, 5, "Expected partial error count to be 5")
 	})
 
+	t.Run("performance schema disabled", func(t *testing.T) {
+		cfg := createDefaultConfig().(*Config)
+		cfg.NetAddr = confignet.NetAddr{Endpoint: "localhost:3306"}
+
+		scraper := newMySQLScraper(componenttest.NewNopReceiverCreateSettings(), cfg)
+		scraper.sqlclient = &mockClient{
+			globalStatsFile: "global_stats",
+			innodbStatsFile: "innodb_stats",
+			// the performance schema tables are missing, they must not be queried
+			tableIoWaitsFile:            "missing",
+			indexIoWaitsFile:            "missing",
+			statementEventsFile:         "missing",
+			tableLockWaitEventStatsFile: "missing",
+			variablesFile:               "variables_mariadb",
+		}
+
+		actualMetrics, err := scraper.scrape(context.Background())
+		require.NoError(t, err)
+
+		require.Equal(t, 1, actualMetrics.ResourceMetrics().Len())
+		assert.Equal(t, map[string]interface{}{
+			"mysql.instance.endpoint": "localhost:3306",
+			"mysql.instance.flavor":   mariadbFlavor,
+		}, actualMetrics.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+	})
+
 }
 
 var _ client = (*mockClient)(nil)
@@ -123,6 +149,9 @@ type mockClient struct {
 	indexIoWaitsFile            string
 	statementEventsFile         string
 	tableLockWaitEventStatsFile string
+	longTransactionsFile        string
+	lockWaitsFile               string
+	variablesFile               string
 }
 
 func readFile(fname string) (map[string]string, error) {
@@ -286,6 +315,95 @@ func (c *mockClient) getTableLockWaitEventStats() ([]tableLockWaitEventStats, er
 	return stats, nil
 }
 
+func (c *mockClient) getLongTransactions() ([]longTransaction, error) {
+	var transactions []longTransaction
+	file, err := os.Open(filepath.Join("testdata", "scraper", c.longTransactionsFile+".txt"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var t longTransaction
+		text := strings.Split(scanner.Text(), "\t")
+
+		t.id = text[0]
+		t.state = text[1]
+		t.threadID, _ = parseInt(text[2])
+		t.seconds, _ = parseInt(text[3])
+		t.query = text[4]
+		t.rowsLocked, _ = parseInt(text[5])
+		t.rowsModified, _ = parseInt(text[6])
+
+		transactions = append(transactions, t)
+	}
+	return transactions, nil
+}
+
+func (c *mockClient) getLockWaits() ([]lockWait, error) {
+	var waits []lockWait
+	file, err := os.Open(filepath.Join("testdata", "scraper", c.lockWaitsFile+".txt"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var w lockWait
+		text := strings.Split(scanner.Text(), "\t")
+
+		w.seconds, _ = parseInt(text[0])
+		w.waitingID = text[1]
+		w.waitingThreadID, _ = parseInt(text[2])
+		w.waitingQuery = text[3]
+		w.blockingID = text[4]
+		w.blockingThreadID, _ = parseInt(text[5])
+		w.blockingQuery = text[6]
+		w.schema = text[7]
+		w.table = text[8]
+		w.index = text[9]
+		w.lockMode = text[10]
+
+		waits = append(waits, w)
+	}
+	return waits, nil
+}
+
+// getCustomQueryRows reads the rows of the query from the file named after the query, its first
+// line holding the names of the columns and the NULL values being left empty.
+func (c *mockClient) getCustomQueryRows(query string) ([]map[string]string, error) {
+	var rows []map[string]string
+	file, err := os.Open(filepath.Join("testdata", "scraper", query+".txt"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	var columns []string
+	for scanner.Scan() {
+		text := strings.Split(scanner.Text(), "\t")
+		if columns == nil {
+			columns = text
+			continue
+		}
+		row := map[string]string{}
+		for i, column := range columns {
+			if text[i] != "" {
+				row[column] = text[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (c *mockClient) getServerVariables() (map[string]string, error) {
+	return readFile(c.variablesFile)
+}
+
 func (c *mockClient) Close() error {
 	return nil
 }
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+)
+
+const (
+	// scopeName is the name of the instrumentation scope of the transaction logs and of the
+	// metrics of the custom queries.
+	scopeName = "otelcol/mysqlreceiver"
+
+	longTransactionEvent = "long_transaction"
+	lockWaitEvent        = "lock_wait"
+)
+
+// transactionLogsReceiver emits the transactions running for longer than the threshold and
+// the lock waits, with the transactions blocking them, as logs on each collection interval.
+type transactionLogsReceiver struct {
+	logger   *zap.Logger
+	config   *Config
+	consumer consumer.Logs
+
+	// newClient creates the db client, it is replaced by the tests.
+	newClient func(*Config) client
+	sqlclient client
+	// version is the version of the server, nil until it is detected.
+	version *serverVersion
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func newTransactionLogsReceiver(settings component.ReceiverCreateSettings, config *Config, consumer consumer.Logs) *transactionLogsReceiver {
+	return &transactionLogsReceiver{
+		logger:    settings.Logger,
+		config:    config,
+		consumer:  consumer,
+		newClient: newMySQLClient,
+	}
+}
+
+// Start connects to the db and starts collecting the transactions.
+func (r *transactionLogsReceiver) Start(_ context.Context, _ component.Host) error {
+	sqlclient := r.newClient(r.config)
+	if err := sqlclient.Connect(); err != nil {
+		return err
+	}
+	r.sqlclient = sqlclient
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+		for {
+			r.collect(ctx)
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops collecting the transactions and closes the db connection.
+func (r *transactionLogsReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	if r.sqlclient == nil {
+		return nil
+	}
+	return r.sqlclient.Close()
+}
+
+// collect emits the long transactions and the lock waits currently found, if any.
+func (r *transactionLogsReceiver) collect(ctx context.Context) {
+	if r.version == nil {
+		r.version = detectServerVersion(r.logger, r.sqlclient)
+	}
+
+	now := pcommon.NewTimestampFromTime(time.Now())
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("mysql.instance.endpoint", r.config.Endpoint)
+	if flavor := r.version.flavorName(); flavor != "" {
+		rl.Resource().Attributes().PutStr("mysql.instance.flavor", flavor)
+	}
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(scopeName)
+	records := sl.LogRecords()
+
+	transactions, err := r.sqlclient.getLongTransactions()
+	if err != nil {
+		r.logger.Error("Failed to fetch the long transactions", zap.Error(err))
+	}
+	for _, t := range transactions {
+		lr := newTransactionLogRecord(records, now, longTransactionEvent,
+			fmt.Sprintf("Transaction %s running for %ds", t.id, t.seconds))
+		attrs := lr.Attributes()
+		attrs.PutStr("mysql.trx.id", t.id)
+		attrs.PutStr("mysql.trx.state", t.state)
+		attrs.PutInt("mysql.trx.duration", t.seconds)
+		attrs.PutInt("mysql.trx.rows_locked", t.rowsLocked)
+		attrs.PutInt("mysql.trx.rows_modified", t.rowsModified)
+		attrs.PutInt("mysql.thread.id", t.threadID)
+		r.putStatement(attrs, "db.statement", t.query)
+	}
+
+	var waits []lockWait
+	if r.version.supportsDataLocks() {
+		waits, err = r.sqlclient.getLockWaits()
+		if err != nil {
+			r.logger.Error("Failed to fetch the lock waits", zap.Error(err))
+		}
+	}
+	for _, w := range waits {
+		lr := newTransactionLogRecord(records, now, lockWaitEvent,
+			fmt.Sprintf("Transaction %s waiting for %ds for a lock held by transaction %s", w.waitingID, w.seconds, w.blockingID))
+		attrs := lr.Attributes()
+		attrs.PutInt("mysql.lock_wait.duration", w.seconds)
+		attrs.PutStr("mysql.lock.schema", w.schema)
+		attrs.PutStr("mysql.lock.table", w.table)
+		if w.index != "" {
+			attrs.PutStr("mysql.lock.index", w.index)
+		}
+		attrs.PutStr("mysql.lock.mode", w.lockMode)
+		attrs.PutStr("mysql.waiting.trx.id", w.waitingID)
+		attrs.PutInt("mysql.waiting.thread.id", w.waitingThreadID)
+		r.putStatement(attrs, "mysql.waiting.statement", w.waitingQuery)
+		attrs.PutStr("mysql.blocking.trx.id", w.blockingID)
+		attrs.PutInt("mysql.blocking.thread.id", w.blockingThreadID)
+		r.putStatement(attrs, "mysql.blocking.statement", w.blockingQuery)
+	}
+
+	if records.Len() == 0 {
+		return
+	}
+	if err := r.consumer.ConsumeLogs(ctx, ld); err != nil {
+		r.logger.Error("Failed to consume the transaction logs", zap.Error(err))
+	}
+}
+
+func newTransactionLogRecord(records plog.LogRecordSlice, now pcommon.Timestamp, event, body string) plog.LogRecord {
+	lr := records.AppendEmpty()
+	lr.SetTimestamp(now)
+	lr.SetObservedTimestamp(now)
+	lr.SetSeverityNumber(plog.SeverityNumberWarn)
+	lr.SetSeverityText("WARN")
+	lr.Body().SetStr(body)
+	lr.Attributes().PutStr("mysql.event.name", event)
+	return lr
+}
+
+// putStatement adds the statement of a transaction, obfuscated when configured, if the transaction
+// is running one.
+func (r *transactionLogsReceiver) putStatement(attrs pcommon.Map, key, query string) {
+	if query == "" {
+		return
+	}
+	if r.config.TransactionSnapshots.ObfuscateQueries {
+		query = obfuscateDigestText(query, r.config.TransactionSnapshots.QueryTextLimit)
+	}
+	attrs.PutStr(key, query)
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func newTestTransactionLogsReceiver(sink *consumertest.LogsSink, sqlclient client) *transactionLogsReceiver {
+	cfg := createDefaultConfig().(*Config)
+	r := newTransactionLogsReceiver(componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	r.newClient = func(*Config) client { return sqlclient }
+	return r
+}
+
+func TestTransactionLogs(t *testing.T) {
+	sink := &consumertest.LogsSink{}
+	r := newTestTransactionLogsReceiver(sink, &mockClient{
+		longTransactionsFile: "long_transactions",
+		lockWaitsFile:        "lock_waits",
+	})
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	// the transactions are collected once when starting
+	require.Len(t, sink.AllLogs(), 1)
+	rl := sink.AllLogs()[0].ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{"mysql.instance.endpoint": "localhost:3306"}, rl.Resource().Attributes().AsRaw())
+	sl := rl.ScopeLogs().At(0)
+	assert.Equal(t, scopeName, sl.Scope().Name())
+	records := sl.LogRecords()
+	require.Equal(t, 3, records.Len())
+
+	lr := records.At(0)
+	assert.Equal(t, plog.SeverityNumberWarn, lr.SeverityNumber())
+	assert.Equal(t, "Transaction 1234 running for 125s", lr.Body().Str())
+	assert.Equal(t, map[string]interface{}{
+		"mysql.event.name":        longTransactionEvent,
+		"mysql.trx.id":            "1234",
+		"mysql.trx.state":         "RUNNING",
+		"mysql.trx.duration":      int64(125),
+		"mysql.trx.rows_locked":   int64(3),
+		"mysql.trx.rows_modified": int64(1),
+		"mysql.thread.id":         int64(42),
+		"db.statement":            "UPDATE accounts SET balance = 10 WHERE id = 7",
+	}, lr.Attributes().AsRaw())
+
+	// a transaction waiting for a lock isn't running a statement
+	_, ok := records.At(1).Attributes().Get("db.statement")
+	assert.False(t, ok)
+
+	lr = records.At(2)
+	assert.Equal(t, "Transaction 5678 waiting for 40s for a lock held by transaction 1234", lr.Body().Str())
+	assert.Equal(t, map[string]interface{}{
+		"mysql.event.name":         lockWaitEvent,
+		"mysql.lock_wait.duration": int64(40),
+		"mysql.lock.schema":        "bank",
+		"mysql.lock.table":         "accounts",
+		"mysql.lock.index":         "PRIMARY",
+		"mysql.lock.mode":          "X,REC_NOT_GAP",
+		"mysql.waiting.trx.id":     "5678",
+		"mysql.waiting.thread.id":  int64(43),
+		"mysql.waiting.statement":  "UPDATE accounts SET balance = 20 WHERE id = 7",
+		"mysql.blocking.trx.id":    "1234",
+		"mysql.blocking.thread.id": int64(42),
+	}, lr.Attributes().AsRaw())
+}
+
+func TestTransactionLogsObfuscated(t *testing.T) {
+	sink := &consumertest.LogsSink{}
+	r := newTestTransactionLogsReceiver(sink, &mockClient{
+		longTransactionsFile: "long_transactions",
+		lockWaitsFile:        "lock_waits",
+	})
+	r.config.TransactionSnapshots.ObfuscateQueries = true
+	r.sqlclient = r.newClient(r.config)
+
+	r.collect(context.Background())
+
+	require.Len(t, sink.AllLogs(), 1)
+	records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	v, ok := records.At(0).Attributes().Get("db.statement")
+	require.True(t, ok)
+	assert.Equal(t, "UPDATE accounts SET balance = ? WHERE id = ?", v.Str())
+}
+
+func TestTransactionLogsNothingFound(t *testing.T) {
+	sink := &consumertest.LogsSink{}
+	r := newTestTransactionLogsReceiver(sink, &mockClient{
+		// the lock waits aren't available before MySQL 8.0, the long transactions are still emitted
+		longTransactionsFile: "long_transactions",
+		lockWaitsFile:        "missing",
+	})
+	r.sqlclient = r.newClient(r.config)
+
+	r.collect(context.Background())
+	require.Len(t, sink.AllLogs(), 1)
+	assert.Equal(t, 2, sink.AllLogs()[0].LogRecordCount())
+
+	// no log is sent when there is no long transaction
+	sink.Reset()
+	r.sqlclient = &mockClient{longTransactionsFile: "missing", lockWaitsFile: "missing"}
+	r.collect(context.Background())
+	assert.Empty(t, sink.AllLogs())
+}
+
+func TestTransactionLogsWithoutDataLocks(t *testing.T) {
+	sink := &consumertest.LogsSink{}
+	r := newTestTransactionLogsReceiver(sink, &mockClient{
+		longTransactionsFile: "long_transactions",
+		// the lock waits must not be queried from MySQL 5.7
+		lockWaitsFile: "lock_waits",
+		variablesFile: "variables_mysql57",
+	})
+	r.sqlclient = r.newClient(r.config)
+
+	r.collect(context.Background())
+	require.Len(t, sink.AllLogs(), 1)
+	rl := sink.AllLogs()[0].ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		"mysql.instance.endpoint": "localhost:3306",
+		"mysql.instance.flavor":   mysqlFlavor,
+	}, rl.Resource().Attributes().AsRaw())
+	assert.Equal(t, 2, rl.ScopeLogs().At(0).LogRecords().Len())
+}
+
+func TestTransactionLogsInterval(t *testing.T) {
+	sink := &consumertest.LogsSink{}
+	r := newTestTransactionLogsReceiver(sink, &mockClient{
+		longTransactionsFile: "long_transactions",
+		lockWaitsFile:        "lock_waits",
+	})
+	r.config.CollectionInterval = 10 * time.Millisecond
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { assert.NoError(t, r.Shutdown(context.Background())) }()
+
+	assert.Eventually(t, func() bool { return len(sink.AllLogs()) > 1 }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,110 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mysqlreceiver"
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	mysqlFlavor   = "mysql"
+	mariadbFlavor = "mariadb"
+	auroraFlavor  = "aurora"
+)
+
+// serverVersion is the version of the server, detected on the first collection, and the features it supports.
+type serverVersion struct {
+	flavor            string
+	version           string
+	major             int
+	performanceSchema bool
+}
+
+// detectServerVersion returns the version of the server, or nil when it can't be detected, in which
+// case all the queries are run as if the server supported them.
+func detectServerVersion(logger *zap.Logger, c client) *serverVersion {
+	variables, err := c.getServerVariables()
+	if err != nil {
+		logger.Warn("Failed to detect the server version, all the queries are run", zap.Error(err))
+		return nil
+	}
+	v := parseServerVersion(variables)
+	logger.Info("Detected the server version",
+		zap.String("flavor", v.flavor),
+		zap.String("version", v.version),
+		zap.Bool("performance_schema", v.performanceSchema))
+	if !v.performanceSchema {
+		logger.Warn("The performance schema is disabled, the metrics and the lock waits based on it aren't collected")
+	}
+	return v
+}
+
+// parseServerVersion parses the version, version_comment, performance_schema and aurora_version
+// global variables of the server.
+func parseServerVersion(variables map[string]string) *serverVersion {
+	v := &serverVersion{
+		flavor:            mysqlFlavor,
+		version:           variables["version"],
+		performanceSchema: isEnabled(variables["performance_schema"]),
+	}
+	switch {
+	case variables["aurora_version"] != "":
+		v.flavor = auroraFlavor
+	case strings.Contains(strings.ToLower(v.version), "mariadb"),
+		strings.Contains(strings.ToLower(variables["version_comment"]), "mariadb"):
+		v.flavor = mariadbFlavor
+	}
+
+	// the MariaDB servers may prefix their version with the replication compatible one
+	version := strings.TrimPrefix(v.version, "5.5.5-")
+	if i := strings.Index(version, "."); i > 0 {
+		v.major, _ = strconv.Atoi(version[:i])
+	}
+	return v
+}
+
+func isEnabled(value string) bool {
+	switch strings.ToUpper(value) {
+	case "ON", "1", "TRUE", "YES":
+		return true
+	}
+	return false
+}
+
+// supportsPerformanceSchema returns whether the performance_schema tables can be queried, assuming
+// they can when the version is unknown.
+func (v *serverVersion) supportsPerformanceSchema() bool {
+	return v == nil || v.performanceSchema
+}
+
+// supportsDataLocks returns whether the lock waits can be queried from the data_lock_waits table of
+// the performance schema, which is only available since MySQL 8.0.
+func (v *serverVersion) supportsDataLocks() bool {
+	if v == nil {
+		return true
+	}
+	return v.performanceSchema && v.flavor != mariadbFlavor && v.major >= 8
+}
+
+// flavorName returns the flavor of the server, or an empty string when the version is unknown.
+func (v *serverVersion) flavorName() string {
+	if v == nil {
+		return ""
+	}
+	return v.flavor
+}
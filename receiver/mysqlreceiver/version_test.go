@@ -0,0 +1,98 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		name              string
+		variables         map[string]string
+		expected          *serverVersion
+		performanceSchema bool
+		dataLocks         bool
+	}{
+		{
+			name: "mysql 8",
+			variables: map[string]string{
+				"version":            "8.0.32",
+				"version_comment":    "MySQL Community Server - GPL",
+				"performance_schema": "ON",
+			},
+			expected:          &serverVersion{flavor: mysqlFlavor, version: "8.0.32", major: 8, performanceSchema: true},
+			performanceSchema: true,
+			dataLocks:         true,
+		},
+		{
+			name: "mysql 5.7",
+			variables: map[string]string{
+				"version":            "5.7.41-log",
+				"performance_schema": "ON",
+			},
+			expected:          &serverVersion{flavor: mysqlFlavor, version: "5.7.41-log", major: 5, performanceSchema: true},
+			performanceSchema: true,
+		},
+		{
+			name: "mariadb",
+			variables: map[string]string{
+				"version":            "5.5.5-10.6.12-MariaDB",
+				"performance_schema": "OFF",
+			},
+			expected: &serverVersion{flavor: mariadbFlavor, version: "5.5.5-10.6.12-MariaDB", major: 10},
+		},
+		{
+			name: "mariadb from the comment",
+			variables: map[string]string{
+				"version":            "10.11.2",
+				"version_comment":    "mariadb.org binary distribution",
+				"performance_schema": "ON",
+			},
+			expected:          &serverVersion{flavor: mariadbFlavor, version: "10.11.2", major: 10, performanceSchema: true},
+			performanceSchema: true,
+		},
+		{
+			name: "aurora",
+			variables: map[string]string{
+				"version":            "8.0.23",
+				"aurora_version":     "3.04.0",
+				"performance_schema": "1",
+			},
+			expected:          &serverVersion{flavor: auroraFlavor, version: "8.0.23", major: 8, performanceSchema: true},
+			performanceSchema: true,
+			dataLocks:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := parseServerVersion(tt.variables)
+			assert.Equal(t, tt.expected, v)
+			assert.Equal(t, tt.performanceSchema, v.supportsPerformanceSchema())
+			assert.Equal(t, tt.dataLocks, v.supportsDataLocks())
+			assert.Equal(t, tt.expected.flavor, v.flavorName())
+		})
+	}
+}
+
+func TestUnknownServerVersion(t *testing.T) {
+	var v *serverVersion
+	// all the queries are run when the version is unknown
+	assert.True(t, v.supportsPerformanceSchema())
+	assert.True(t, v.supportsDataLocks())
+	assert.Empty(t, v.flavorName())
+}
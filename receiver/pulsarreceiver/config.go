@@ -16,6 +16,7 @@ package pulsarreceiver // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"errors"
+	"time"
 
 	"github.com/apache/pulsar-client-go/pulsar"
 	"go.opentelemetry.io/collector/component"
@@ -40,6 +41,27 @@ type Config struct {
 	// Configure whether the Pulsar client accept untrusted TLS certificate from broker (default: false)
 	TLSAllowInsecureConnection bool           `mapstructure:"tls_allow_insecure_connection"`
 	Authentication             Authentication `mapstructure:"auth"`
+	// Chunking configures the reassembly of the messages split into chunks by the producers.
+	Chunking Chunking `mapstructure:"chunking"`
+	// ShutdownTimeout is the maximum time the receiver waits on shutdown for the message it is
+	// consuming to be consumed downstream and acknowledged, before closing the consumer. Zero
+	// closes the consumer right away. (default 10s)
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+}
+
+type Chunking struct {
+	// MaxPendingChunkedMessages is the maximum number of chunked messages being reassembled at once,
+	// the oldest one is discarded when it is reached. (default 100)
+	MaxPendingChunkedMessages int `mapstructure:"max_pending_chunked_messages"`
+	// ExpireTimeOfIncompleteChunk is the time after which a chunked message still missing chunks
+	// is discarded. (default 1m)
+	ExpireTimeOfIncompleteChunk time.Duration `mapstructure:"expire_time_of_incomplete_chunk"`
+	// AutoAckIncompleteChunk acknowledges the chunks of the discarded chunked messages, so that
+	// they are not redelivered. (default false)
+	AutoAckIncompleteChunk bool `mapstructure:"auto_ack_incomplete_chunk"`
+	// MaxMessageSize is the maximum size in bytes of a payload, once reassembled. Larger messages
+	// are acknowledged and dropped without being unmarshaled. Zero means no limit. (default 0)
+	MaxMessageSize int `mapstructure:"max_message_size"`
 }
 
 type Authentication struct {
@@ -78,6 +100,18 @@ var _ component.ReceiverConfig = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	if cfg.Chunking.MaxPendingChunkedMessages < 0 {
+		return errors.New("chunking.max_pending_chunked_messages must not be negative")
+	}
+	if cfg.Chunking.ExpireTimeOfIncompleteChunk < 0 {
+		return errors.New("chunking.expire_time_of_incomplete_chunk must not be negative")
+	}
+	if cfg.Chunking.MaxMessageSize < 0 {
+		return errors.New("chunking.max_message_size must not be negative")
+	}
+	if cfg.ShutdownTimeout < 0 {
+		return errors.New("shutdown_timeout must not be negative")
+	}
 	return nil
 }
 
@@ -132,9 +166,12 @@ func (cfg *Config) clientOptions() pulsar.ClientOptions {
 
 func (cfg *Config) consumerOptions() (pulsar.ConsumerOptions, error) {
 	options := pulsar.ConsumerOptions{
-		Type:             pulsar.Failover,
-		Topic:            cfg.Topic,
-		SubscriptionName: cfg.Subscription,
+		Type:                        pulsar.Failover,
+		Topic:                       cfg.Topic,
+		SubscriptionName:            cfg.Subscription,
+		MaxPendingChunkedMessage:    cfg.Chunking.MaxPendingChunkedMessages,
+		ExpireTimeOfIncompleteChunk: cfg.Chunking.ExpireTimeOfIncompleteChunk,
+		AutoAckIncompleteChunk:      cfg.Chunking.AutoAckIncompleteChunk,
 	}
 
 	if len(cfg.ConsumerName) > 0 {
@@ -17,6 +17,7 @@ package pulsarreceiver // import "github.com/open-telemetry/opentelemetry-collec
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -44,7 +45,51 @@ func TestLoadConfig(t *testing.T) {
 		Encoding:              defaultEncoding,
 		TLSTrustCertsFilePath: "ca.pem",
 		Authentication:        Authentication{TLS: &TLS{CertFile: "cert.pem", KeyFile: "key.pem"}},
+		Chunking: Chunking{
+			MaxPendingChunkedMessages:   10,
+			ExpireTimeOfIncompleteChunk: 30 * time.Second,
+			AutoAckIncompleteChunk:      true,
+			MaxMessageSize:              16777216,
+		},
+		ShutdownTimeout: 30 * time.Second,
 	},
 		cfg,
 	)
 }
+
+func TestValidateChunking(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Chunking.MaxMessageSize = -1
+	assert.EqualError(t, cfg.Validate(), "chunking.max_message_size must not be negative")
+
+	cfg.Chunking.ExpireTimeOfIncompleteChunk = -time.Second
+	assert.EqualError(t, cfg.Validate(), "chunking.expire_time_of_incomplete_chunk must not be negative")
+
+	cfg.Chunking.MaxPendingChunkedMessages = -1
+	assert.EqualError(t, cfg.Validate(), "chunking.max_pending_chunked_messages must not be negative")
+}
+
+func TestValidateShutdownTimeout(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, defaultShutdownTimeout, cfg.ShutdownTimeout)
+
+	cfg.ShutdownTimeout = 0
+	assert.NoError(t, cfg.Validate())
+
+	cfg.ShutdownTimeout = -time.Second
+	assert.EqualError(t, cfg.Validate(), "shutdown_timeout must not be negative")
+}
+
+func TestConsumerOptionsChunking(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Topic = "otlp_spans"
+	cfg.Chunking.AutoAckIncompleteChunk = true
+
+	options, err := cfg.consumerOptions()
+	require.NoError(t, err)
+	assert.Equal(t, defaultMaxPendingChunkedMessages, options.MaxPendingChunkedMessage)
+	assert.Equal(t, defaultExpireTimeOfIncompleteChunk, options.ExpireTimeOfIncompleteChunk)
+	assert.True(t, options.AutoAckIncompleteChunk)
+}
@@ -16,6 +16,7 @@ package pulsarreceiver // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -32,6 +33,10 @@ const (
 	defaultConsumerName = ""
 	defaultSubscription = "otlp_subscription"
 	defaultServiceURL   = "pulsar://localhost:6650"
+
+	defaultMaxPendingChunkedMessages   = 100
+	defaultExpireTimeOfIncompleteChunk = time.Minute
+	defaultShutdownTimeout             = 10 * time.Second
 )
 
 // FactoryOption applies changes to PulsarExporterFactory.
@@ -148,5 +153,10 @@ func createDefaultConfig() component.ReceiverConfig {
 		ConsumerName:     defaultConsumerName,
 		Subscription:     defaultSubscription,
 		Endpoint:         defaultServiceURL,
+		Chunking: Chunking{
+			MaxPendingChunkedMessages:   defaultMaxPendingChunkedMessages,
+			ExpireTimeOfIncompleteChunk: defaultExpireTimeOfIncompleteChunk,
+		},
+		ShutdownTimeout: defaultShutdownTimeout,
 	}
 }
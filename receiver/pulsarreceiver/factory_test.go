@@ -35,6 +35,11 @@ func TestCreateDefaultConfig(t *testing.T) {
 		Subscription:     defaultSubscription,
 		Endpoint:         defaultServiceURL,
 		Authentication:   Authentication{},
+		Chunking: Chunking{
+			MaxPendingChunkedMessages:   defaultMaxPendingChunkedMessages,
+			ExpireTimeOfIncompleteChunk: defaultExpireTimeOfIncompleteChunk,
+		},
+		ShutdownTimeout: defaultShutdownTimeout,
 	}, cfg)
 }
 
@@ -23,12 +23,35 @@ import (
 	"github.com/apache/pulsar-client-go/pulsar"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
 )
 
-var errUnrecognizedEncoding = errors.New("unrecognized encoding")
+var (
+	errUnrecognizedEncoding = errors.New("unrecognized encoding")
+	errMessageTooLarge      = errors.New("message larger than the maximum message size")
+)
+
+const (
+	alreadyClosedError = "AlreadyClosedError"
+	transport          = "pulsar"
+)
 
-const alreadyClosedError = "AlreadyClosedError"
+// exceedsMaxMessageSize reports whether the payload of the message, reassembled
+// by the consumer when it was split into chunks, is larger than maxSize.
+// The client only hands the message over once all of its chunks are received,
+// so the limit bounds what is passed down the pipeline, not the memory used by
+// the reassembly, which is bounded by max_pending_chunked_messages instead.
+func exceedsMaxMessageSize(logger *zap.Logger, maxSize int, message pulsar.Message) bool {
+	if maxSize <= 0 || len(message.Payload()) <= maxSize {
+		return false
+	}
+	logger.Warn("dropping "+errMessageTooLarge.Error(),
+		zap.String("topic", message.Topic()),
+		zap.Int("size", len(message.Payload())),
+		zap.Int("max_message_size", maxSize))
+	return true
+}
 
 type pulsarTracesConsumer struct {
 	id              component.ID
@@ -40,6 +63,10 @@ type pulsarTracesConsumer struct {
 	unmarshaler     TracesUnmarshaler
 	settings        component.ReceiverCreateSettings
 	consumerOptions pulsar.ConsumerOptions
+	maxMessageSize  int
+	shutdownTimeout time.Duration
+	inFlight        *inFlight
+	obsrecv         *obsreport.Receiver
 }
 
 func newTracesReceiver(config Config, set component.ReceiverCreateSettings, unmarshalers map[string]TracesUnmarshaler, nextConsumer consumer.Traces) (*pulsarTracesConsumer, error) {
@@ -59,6 +86,15 @@ func newTracesReceiver(config Config, set component.ReceiverCreateSettings, unma
 		return nil, err
 	}
 
+	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
+		ReceiverID:             config.ID(),
+		Transport:              transport,
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &pulsarTracesConsumer{
 		id:              config.ID(),
 		tracesConsumer:  nextConsumer,
@@ -67,6 +103,9 @@ func newTracesReceiver(config Config, set component.ReceiverCreateSettings, unma
 		settings:        set,
 		client:          client,
 		consumerOptions: consumerOptions,
+		maxMessageSize:  config.Chunking.MaxMessageSize,
+		shutdownTimeout: config.ShutdownTimeout,
+		obsrecv:         obsrecv,
 	}, nil
 }
 
@@ -77,7 +116,9 @@ func (c *pulsarTracesConsumer) Start(context.Context, component.Host) error {
 	_consumer, err := c.client.Subscribe(c.consumerOptions)
 	if err == nil {
 		c.consumer = _consumer
+		c.inFlight = newInFlight()
 		go func() {
+			defer close(c.inFlight.done)
 			if e := consumerTracesLoop(ctx, c); e != nil {
 				c.settings.Logger.Error("consume traces loop occurs an error", zap.Error(e))
 			}
@@ -92,7 +133,12 @@ func consumerTracesLoop(ctx context.Context, c *pulsarTracesConsumer) error {
 	traceConsumer := c.tracesConsumer
 
 	for {
+		// Receive may still return a buffered message once canceled
 		message, err := c.consumer.Receive(ctx)
+		if err == nil && ctx.Err() != nil {
+			// the buffered messages aren't acknowledged, they are redelivered once the consumer is closed
+			err = ctx.Err()
+		}
 		if err != nil {
 			if strings.Contains(err.Error(), alreadyClosedError) {
 				return err
@@ -106,6 +152,13 @@ func consumerTracesLoop(ctx context.Context, c *pulsarTracesConsumer) error {
 			continue
 		}
 
+		if exceedsMaxMessageSize(c.settings.Logger, c.maxMessageSize, message) {
+			// the payload isn't unmarshaled, the number of refused items is unknown
+			c.obsrecv.EndTracesOp(c.obsrecv.StartTracesOp(ctx), unmarshaler.Encoding(), 0, errMessageTooLarge)
+			c.consumer.Ack(message)
+			continue
+		}
+
 		traces, err := unmarshaler.Unmarshal(message.Payload())
 		if err != nil {
 			c.settings.Logger.Error("failed to unmarshaler traces message", zap.Error(err))
@@ -113,15 +166,17 @@ func consumerTracesLoop(ctx context.Context, c *pulsarTracesConsumer) error {
 			return err
 		}
 
-		if err := traceConsumer.ConsumeTraces(context.Background(), traces); err != nil {
+		err = traceConsumer.ConsumeTraces(c.inFlight.ctx, traces)
+		if err != nil {
 			c.settings.Logger.Error("consume traces failed", zap.Error(err))
 		}
-		c.consumer.Ack(message)
+		c.inFlight.settle(c.consumer, message, err)
 	}
 }
 
-func (c *pulsarTracesConsumer) Shutdown(context.Context) error {
+func (c *pulsarTracesConsumer) Shutdown(ctx context.Context) error {
 	c.cancel()
+	c.inFlight.drain(ctx, c.shutdownTimeout, c.settings.Logger)
 	c.consumer.Close()
 	c.client.Close()
 	return nil
@@ -137,6 +192,10 @@ type pulsarMetricsConsumer struct {
 	cancel          context.CancelFunc
 	settings        component.ReceiverCreateSettings
 	consumerOptions pulsar.ConsumerOptions
+	maxMessageSize  int
+	shutdownTimeout time.Duration
+	inFlight        *inFlight
+	obsrecv         *obsreport.Receiver
 }
 
 func newMetricsReceiver(config Config, set component.ReceiverCreateSettings, unmarshalers map[string]MetricsUnmarshaler, nextConsumer consumer.Metrics) (*pulsarMetricsConsumer, error) {
@@ -156,6 +215,15 @@ func newMetricsReceiver(config Config, set component.ReceiverCreateSettings, unm
 		return nil, err
 	}
 
+	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
+		ReceiverID:             config.ID(),
+		Transport:              transport,
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &pulsarMetricsConsumer{
 		id:              config.ID(),
 		metricsConsumer: nextConsumer,
@@ -164,6 +232,9 @@ func newMetricsReceiver(config Config, set component.ReceiverCreateSettings, unm
 		settings:        set,
 		client:          client,
 		consumerOptions: consumerOptions,
+		maxMessageSize:  config.Chunking.MaxMessageSize,
+		shutdownTimeout: config.ShutdownTimeout,
+		obsrecv:         obsrecv,
 	}, nil
 }
 
@@ -174,8 +245,9 @@ func (c *pulsarMetricsConsumer) Start(context.Context, component.Host) error {
 	_consumer, err := c.client.Subscribe(c.consumerOptions)
 	if err == nil {
 		c.consumer = _consumer
-
+		c.inFlight = newInFlight()
 		go func() {
+			defer close(c.inFlight.done)
 			if e := consumeMetricsLoop(ctx, c); e != nil {
 				c.settings.Logger.Error("consume metrics loop occurs an error", zap.Error(e))
 			}
@@ -190,7 +262,12 @@ func consumeMetricsLoop(ctx context.Context, c *pulsarMetricsConsumer) error {
 	metricsConsumer := c.metricsConsumer
 
 	for {
+		// Receive may still return a buffered message once canceled
 		message, err := c.consumer.Receive(ctx)
+		if err == nil && ctx.Err() != nil {
+			// the buffered messages aren't acknowledged, they are redelivered once the consumer is closed
+			err = ctx.Err()
+		}
 		if err != nil {
 			if strings.Contains(err.Error(), alreadyClosedError) {
 				return err
@@ -205,6 +282,13 @@ func consumeMetricsLoop(ctx context.Context, c *pulsarMetricsConsumer) error {
 			continue
 		}
 
+		if exceedsMaxMessageSize(c.settings.Logger, c.maxMessageSize, message) {
+			// the payload isn't unmarshaled, the number of refused items is unknown
+			c.obsrecv.EndMetricsOp(c.obsrecv.StartMetricsOp(ctx), unmarshaler.Encoding(), 0, errMessageTooLarge)
+			c.consumer.Ack(message)
+			continue
+		}
+
 		metrics, err := unmarshaler.Unmarshal(message.Payload())
 		if err != nil {
 			c.settings.Logger.Error("failed to unmarshaler metrics message", zap.Error(err))
@@ -212,16 +296,18 @@ func consumeMetricsLoop(ctx context.Context, c *pulsarMetricsConsumer) error {
 			return err
 		}
 
-		if err := metricsConsumer.ConsumeMetrics(context.Background(), metrics); err != nil {
+		err = metricsConsumer.ConsumeMetrics(c.inFlight.ctx, metrics)
+		if err != nil {
 			c.settings.Logger.Error("consume traces failed", zap.Error(err))
 		}
 
-		c.consumer.Ack(message)
+		c.inFlight.settle(c.consumer, message, err)
 	}
 }
 
-func (c *pulsarMetricsConsumer) Shutdown(context.Context) error {
+func (c *pulsarMetricsConsumer) Shutdown(ctx context.Context) error {
 	c.cancel()
+	c.inFlight.drain(ctx, c.shutdownTimeout, c.settings.Logger)
 	c.consumer.Close()
 	c.client.Close()
 	return nil
@@ -237,6 +323,10 @@ type pulsarLogsConsumer struct {
 	cancel          context.CancelFunc
 	settings        component.ReceiverCreateSettings
 	consumerOptions pulsar.ConsumerOptions
+	maxMessageSize  int
+	shutdownTimeout time.Duration
+	inFlight        *inFlight
+	obsrecv         *obsreport.Receiver
 }
 
 func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmarshalers map[string]LogsUnmarshaler, nextConsumer consumer.Logs) (*pulsarLogsConsumer, error) {
@@ -256,6 +346,15 @@ func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmars
 		return nil, err
 	}
 
+	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
+		ReceiverID:             config.ID(),
+		Transport:              transport,
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &pulsarLogsConsumer{
 		id:              config.ID(),
 		logsConsumer:    nextConsumer,
@@ -265,6 +364,9 @@ func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmars
 		settings:        set,
 		client:          client,
 		consumerOptions: consumerOptions,
+		maxMessageSize:  config.Chunking.MaxMessageSize,
+		shutdownTimeout: config.ShutdownTimeout,
+		obsrecv:         obsrecv,
 	}, nil
 }
 
@@ -275,7 +377,9 @@ func (c *pulsarLogsConsumer) Start(context.Context, component.Host) error {
 	_consumer, err := c.client.Subscribe(c.consumerOptions)
 	if err == nil {
 		c.consumer = _consumer
+		c.inFlight = newInFlight()
 		go func() {
+			defer close(c.inFlight.done)
 			if e := consumeLogsLoop(ctx, c); e != nil {
 				c.settings.Logger.Error("consume logs loop occurs an error", zap.Error(e))
 			}
@@ -290,7 +394,12 @@ func consumeLogsLoop(ctx context.Context, c *pulsarLogsConsumer) error {
 	logsConsumer := c.logsConsumer
 
 	for {
+		// Receive may still return a buffered message once canceled
 		message, err := c.consumer.Receive(ctx)
+		if err == nil && ctx.Err() != nil {
+			// the buffered messages aren't acknowledged, they are redelivered once the consumer is closed
+			err = ctx.Err()
+		}
 		if err != nil {
 			if strings.Contains(err.Error(), alreadyClosedError) {
 				return err
@@ -304,6 +413,13 @@ func consumeLogsLoop(ctx context.Context, c *pulsarLogsConsumer) error {
 			continue
 		}
 
+		if exceedsMaxMessageSize(c.settings.Logger, c.maxMessageSize, message) {
+			// the payload isn't unmarshaled, the number of refused items is unknown
+			c.obsrecv.EndLogsOp(c.obsrecv.StartLogsOp(ctx), unmarshaler.Encoding(), 0, errMessageTooLarge)
+			c.consumer.Ack(message)
+			continue
+		}
+
 		logs, err := unmarshaler.Unmarshal(message.Payload())
 		if err != nil {
 			c.settings.Logger.Error("failed to unmarshaler logs message", zap.Error(err))
@@ -311,16 +427,18 @@ func consumeLogsLoop(ctx context.Context, c *pulsarLogsConsumer) error {
 			return err
 		}
 
-		if err := logsConsumer.ConsumeLogs(context.Background(), logs); err != nil {
+		err = logsConsumer.ConsumeLogs(c.inFlight.ctx, logs)
+		if err != nil {
 			c.settings.Logger.Error("consume traces failed", zap.Error(err))
 		}
 
-		c.consumer.Ack(message)
+		c.inFlight.settle(c.consumer, message, err)
 	}
 }
 
-func (c *pulsarLogsConsumer) Shutdown(context.Context) error {
+func (c *pulsarLogsConsumer) Shutdown(ctx context.Context) error {
 	c.cancel()
+	c.inFlight.drain(ctx, c.shutdownTimeout, c.settings.Logger)
 	c.consumer.Close()
 	c.client.Close()
 	return nil
@@ -17,9 +17,11 @@ package pulsarreceiver // import "github.com/open-telemetry/opentelemetry-collec
 import (
 	"testing"
 
+	"github.com/apache/pulsar-client-go/pulsar"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.uber.org/zap"
 )
 
 func Test_newTracesReceiver_err(t *testing.T) {
@@ -29,3 +31,24 @@ func Test_newTracesReceiver_err(t *testing.T) {
 	_, err := newTracesReceiver(c, componenttest.NewNopReceiverCreateSettings(), defaultTracesUnmarshalers(), consumertest.NewNop())
 	assert.Error(t, err)
 }
+
+type payloadMessage struct {
+	pulsar.Message
+	payload []byte
+}
+
+func (m *payloadMessage) Payload() []byte {
+	return m.payload
+}
+
+func (m *payloadMessage) Topic() string {
+	return "otlp_spans"
+}
+
+func Test_exceedsMaxMessageSize(t *testing.T) {
+	message := &payloadMessage{payload: make([]byte, 10)}
+
+	assert.False(t, exceedsMaxMessageSize(zap.NewNop(), 0, message))
+	assert.False(t, exceedsMaxMessageSize(zap.NewNop(), 10, message))
+	assert.True(t, exceedsMaxMessageSize(zap.NewNop(), 9, message))
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/pulsarreceiver"
+
+import (
+	"context"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"go.uber.org/zap"
+)
+
+// inFlight tracks the message being consumed by a receive loop. On shutdown the loop stops
+// receiving, and the message it already received is given the time to be consumed downstream
+// and acknowledged before the consumer is closed, instead of being dropped half-way.
+type inFlight struct {
+	// done is closed once the receive loop exited.
+	done chan struct{}
+	// ctx is passed to the next consumer, it is canceled when the shutdown times out.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newInFlight() *inFlight {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &inFlight{
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// drain waits for the receive loop to exit once canceled, at most for the timeout or until ctx
+// is done. The message still being consumed then is canceled, and negatively acknowledged.
+func (f *inFlight) drain(ctx context.Context, timeout time.Duration, logger *zap.Logger) {
+	if f == nil {
+		return
+	}
+	defer f.cancel()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-f.done:
+		return
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	logger.Warn("the message being consumed wasn't consumed before the shutdown timeout, it will be redelivered")
+}
+
+// settle acknowledges the consumed message, unless its consumption failed because the shutdown
+// timed out: it is negatively acknowledged then, so that it is redelivered.
+func (f *inFlight) settle(consumer pulsar.Consumer, message pulsar.Message, err error) {
+	if err != nil && f.ctx.Err() != nil {
+		consumer.Nack(message)
+		return
+	}
+	consumer.Ack(message)
+}
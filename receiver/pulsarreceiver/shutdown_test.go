@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarreceiver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// mockConsumer is a Pulsar consumer delivering the messages of its channel.
+type mockConsumer struct {
+	pulsar.Consumer
+	messages chan pulsar.Message
+
+	mu     sync.Mutex
+	acked  int
+	nacked int
+	closed bool
+}
+
+func (c *mockConsumer) Receive(ctx context.Context) (pulsar.Message, error) {
+	select {
+	case m := <-c.messages:
+		return m, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *mockConsumer) Ack(pulsar.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.acked++
+	return nil
+}
+
+func (c *mockConsumer) Nack(pulsar.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nacked++
+}
+
+func (c *mockConsumer) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+}
+
+func (c *mockConsumer) counts() (int, int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.acked, c.nacked, c.closed
+}
+
+type mockClient struct {
+	pulsar.Client
+	consumer *mockConsumer
+}
+
+func (c *mockClient) Subscribe(pulsar.ConsumerOptions) (pulsar.Consumer, error) {
+	return c.consumer, nil
+}
+
+func (c *mockClient) Close() {}
+
+// startTracesReceiver starts a traces receiver consuming a single message with the next consumer.
+func startTracesReceiver(t *testing.T, next consumer.Traces, shutdownTimeout time.Duration) (*pulsarTracesConsumer, *mockConsumer) {
+	payload, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(ptrace.NewTraces())
+	require.NoError(t, err)
+	mc := &mockConsumer{messages: make(chan pulsar.Message, 1)}
+	mc.messages <- &payloadMessage{payload: payload}
+
+	r := &pulsarTracesConsumer{
+		tracesConsumer:  next,
+		unmarshaler:     defaultTracesUnmarshalers()[defaultEncoding],
+		settings:        componenttest.NewNopReceiverCreateSettings(),
+		client:          &mockClient{consumer: mc},
+		shutdownTimeout: shutdownTimeout,
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	return r, mc
+}
+
+// blockingTraces is a traces consumer blocking until released, or until its context is done.
+type blockingTraces struct {
+	consumer.Traces
+	received chan struct{}
+	release  chan struct{}
+}
+
+func (b *blockingTraces) ConsumeTraces(ctx context.Context, _ ptrace.Traces) error {
+	close(b.received)
+	select {
+	case <-b.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestShutdown_drainsInFlightMessage(t *testing.T) {
+	next := &blockingTraces{received: make(chan struct{}), release: make(chan struct{})}
+	r, mc := startTracesReceiver(t, next, time.Minute)
+	<-next.received
+
+	shutdown := make(chan error)
+	go func() {
+		shutdown <- r.Shutdown(context.Background())
+	}()
+
+	// the consumer isn't closed while the message is being consumed
+	select {
+	case <-shutdown:
+		t.Fatal("the shutdown didn't wait for the message to be consumed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	_, _, closed := mc.counts()
+	assert.False(t, closed)
+
+	close(next.release)
+	require.NoError(t, <-shutdown)
+	acked, nacked, closed := mc.counts()
+	assert.Equal(t, 1, acked)
+	assert.Equal(t, 0, nacked)
+	assert.True(t, closed)
+}
+
+func TestShutdown_timeout(t *testing.T) {
+	next := &blockingTraces{received: make(chan struct{}), release: make(chan struct{})}
+	r, mc := startTracesReceiver(t, next, 10*time.Millisecond)
+	<-next.received
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	_, _, closed := mc.counts()
+	assert.True(t, closed)
+
+	// the consumption is canceled, and the message is redelivered
+	<-r.inFlight.done
+	acked, nacked, _ := mc.counts()
+	assert.Equal(t, 0, acked)
+	assert.Equal(t, 1, nacked)
+}
+
+func TestShutdown_idle(t *testing.T) {
+	next := &blockingTraces{received: make(chan struct{}), release: make(chan struct{})}
+	close(next.release)
+	r, mc := startTracesReceiver(t, next, time.Minute)
+	<-next.received
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	acked, nacked, closed := mc.counts()
+	assert.Equal(t, 1, acked)
+	assert.Equal(t, 0, nacked)
+	assert.True(t, closed)
+}
@@ -32,6 +32,16 @@ var (
 	errMissingQueueName       = errors.New("queue definition is required, queue definition has format queue://<queuename>")
 	errMissingPlainTextParams = errors.New("missing plain text auth params: Username, Password")
 	errMissingXauth2Params    = errors.New("missing xauth2 text auth params: Username, Bearer")
+	errInvalidWorkerCount     = errors.New("workers count must be at least 1")
+	errInvalidWorkerQueueSize = errors.New("workers queue_size must not be negative")
+	errInvalidWorkerOrdering  = errors.New("workers ordering must be one of none or topic")
+)
+
+const (
+	// orderingNone lets any worker process any message
+	orderingNone = "none"
+	// orderingTopic has the messages of a topic processed in the order they are received by the same worker
+	orderingTopic = "topic"
 )
 
 // Config defines configuration for Solace receiver.
@@ -46,9 +56,28 @@ type Config struct {
 	// The maximum number of unacknowledged messages the Solace broker can transmit, to configure AMQP Link
 	MaxUnacked uint32 `mapstructure:"max_unacknowledged"`
 
+	// The name of the solace queue that messages which can't be unmarshalled are republished to.
+	// If empty, such messages are acknowledged and dropped.
+	DeadMessageQueue string `mapstructure:"dead_message_queue"`
+
 	TLS configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
 
 	Auth Authentication `mapstructure:"auth"`
+
+	// Workers defines the workers unmarshalling and forwarding the received messages
+	Workers WorkersConfig `mapstructure:"workers"`
+}
+
+// WorkersConfig defines the pool of workers unmarshalling and forwarding the received messages.
+// With a single worker, the messages are processed in the receive loop.
+type WorkersConfig struct {
+	// Count is the number of workers
+	Count int `mapstructure:"count"`
+	// QueueSize is the number of received messages waiting for a worker
+	QueueSize int `mapstructure:"queue_size"`
+	// Ordering is either none, any worker processing any message, or topic, the messages of
+	// a topic being processed in the order they are received by the same worker
+	Ordering string `mapstructure:"ordering"`
 }
 
 // Validate checks the receiver configuration is valid
@@ -59,6 +88,19 @@ func (cfg *Config) Validate() error {
 	if len(strings.TrimSpace(cfg.Queue)) == 0 {
 		return errMissingQueueName
 	}
+	return cfg.Workers.validate()
+}
+
+func (cfg *WorkersConfig) validate() error {
+	if cfg.Count < 1 {
+		return errInvalidWorkerCount
+	}
+	if cfg.QueueSize < 0 {
+		return errInvalidWorkerQueueSize
+	}
+	if cfg.Ordering != orderingNone && cfg.Ordering != orderingTopic {
+		return errInvalidWorkerOrdering
+	}
 	return nil
 }
 
@@ -48,12 +48,18 @@ func TestLoadConfig(t *testing.T) {
 						Password: "otel01$",
 					},
 				},
-				Queue:      "queue://#trace-profile123",
-				MaxUnacked: 1234,
+				Queue:            "queue://#trace-profile123",
+				MaxUnacked:       1234,
+				DeadMessageQueue: "queue://#trace-profile123-dmq",
 				TLS: configtls.TLSClientSetting{
 					Insecure:           false,
 					InsecureSkipVerify: false,
 				},
+				Workers: WorkersConfig{
+					Count:     4,
+					QueueSize: 100,
+					Ordering:  orderingTopic,
+				},
 			},
 		},
 		{
@@ -99,6 +105,36 @@ func TestConfigValidateMissingQueue(t *testing.T) {
 	assert.Equal(t, errMissingQueueName, err)
 }
 
+func TestConfigValidateWorkers(t *testing.T) {
+	cases := map[string]struct {
+		configure   func(*WorkersConfig)
+		expectedErr error
+	}{
+		"No Worker": {
+			configure:   func(w *WorkersConfig) { w.Count = 0 },
+			expectedErr: errInvalidWorkerCount,
+		},
+		"Negative Queue Size": {
+			configure:   func(w *WorkersConfig) { w.QueueSize = -1 },
+			expectedErr: errInvalidWorkerQueueSize,
+		},
+		"Unknown Ordering": {
+			configure:   func(w *WorkersConfig) { w.Ordering = "partition" },
+			expectedErr: errInvalidWorkerOrdering,
+		},
+	}
+
+	for caseName, testCase := range cases {
+		t.Run(caseName, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Queue = "someQueue"
+			cfg.Auth.PlainText = &SaslPlainTextConfig{"Username", "Password"}
+			testCase.configure(&cfg.Workers)
+			assert.Equal(t, testCase.expectedErr, cfg.Validate())
+		})
+	}
+}
+
 func TestConfigValidateSuccess(t *testing.T) {
 	successCases := map[string]func(*Config){
 		"With Plaintext Auth": func(c *Config) {
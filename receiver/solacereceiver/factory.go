@@ -50,6 +50,10 @@ func createDefaultConfig() component.ReceiverConfig {
 		Broker:           []string{defaultHost},
 		MaxUnacked:       defaultMaxUnaked,
 		Auth:             Authentication{},
+		Workers: WorkersConfig{
+			Count:    1,
+			Ordering: orderingNone,
+		},
 		TLS: configtls.TLSClientSetting{
 			InsecureSkipVerify: false,
 			Insecure:           false,
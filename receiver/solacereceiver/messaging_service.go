@@ -16,6 +16,7 @@ package solacereceiver // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Azure/go-amqp"
@@ -32,8 +33,20 @@ type messagingService interface {
 	receiveMessage(ctx context.Context) (*inboundMessage, error)
 	accept(ctx context.Context, msg *inboundMessage) error
 	failed(ctx context.Context, msg *inboundMessage) error
+	reject(ctx context.Context, msg *inboundMessage) error
+	deadMessage(ctx context.Context, msg *inboundMessage, reason string, cause error) error
 }
 
+// errNoDeadMessageQueue is returned when a message is dead lettered without a dead message queue configured
+var errNoDeadMessageQueue = errors.New("no dead message queue configured")
+
+const (
+	// deadMessageReasonAnnotation is the message annotation containing the reason a message was dead lettered
+	deadMessageReasonAnnotation = "x-opt-otel-dead-message-reason"
+	// deadMessageErrorAnnotation is the message annotation containing the error encountered when processing the message
+	deadMessageErrorAnnotation = "x-opt-otel-dead-message-error"
+)
+
 // messagingServiceFactory is a factory to create new messagingService instances
 type messagingServiceFactory func() messagingService
 
@@ -72,8 +85,9 @@ func newAMQPMessagingServiceFactory(cfg *Config, logger *zap.Logger) (messagingS
 	}
 
 	receiverConfig := &amqpReceiverConfig{
-		queue:      cfg.Queue,
-		maxUnacked: cfg.MaxUnacked,
+		queue:            cfg.Queue,
+		maxUnacked:       cfg.MaxUnacked,
+		deadMessageQueue: cfg.DeadMessageQueue,
 	}
 
 	return func() messagingService {
@@ -94,8 +108,9 @@ type amqpConnectConfig struct {
 }
 
 type amqpReceiverConfig struct {
-	queue      string
-	maxUnacked uint32
+	queue            string
+	maxUnacked       uint32
+	deadMessageQueue string
 }
 
 type amqpMessagingService struct {
@@ -108,6 +123,8 @@ type amqpMessagingService struct {
 	client   *amqp.Client
 	session  *amqp.Session
 	receiver *amqp.Receiver
+	// sender publishes to the dead message queue, nil if no dead message queue is configured
+	sender *amqp.Sender
 }
 
 // dialFunc is abstracted out into a variable in order for substitutions
@@ -117,6 +134,9 @@ var dialFunc = amqp.Dial
 // Mainly useful for testing to mock amqp frames.
 const telemetryLinkName = "rx"
 
+// deadMessageLinkName will be used to create the sender link to the dead message queue.
+const deadMessageLinkName = "dmq"
+
 func (m *amqpMessagingService) dial() (err error) {
 	opts := []amqp.ConnOption{m.connectConfig.saslConfig}
 	if m.connectConfig.tlsConfig != nil {
@@ -144,10 +164,28 @@ func (m *amqpMessagingService) dial() (err error) {
 		m.logger.Debug("Create AMQP Receiver Link failure", zap.Error(err))
 		return err
 	}
+	if m.receiverConfig.deadMessageQueue != "" {
+		m.logger.Debug("Creating new AMQP Sender Link", zap.String("target", m.receiverConfig.deadMessageQueue))
+		m.sender, err = m.session.NewSender(
+			amqp.LinkTargetAddress(m.receiverConfig.deadMessageQueue),
+			amqp.LinkName(deadMessageLinkName),
+		)
+		if err != nil {
+			m.logger.Debug("Create AMQP Sender Link failure", zap.Error(err))
+			return err
+		}
+	}
 	return nil
 }
 
 func (m *amqpMessagingService) close(ctx context.Context) {
+	if m.sender != nil {
+		m.logger.Debug("Closing AMQP Sender")
+		err := m.sender.Close(ctx)
+		if err != nil {
+			m.logger.Debug("Sender close failed", zap.Error(err))
+		}
+	}
 	if m.receiver != nil {
 		m.logger.Debug("Closing AMQP Receiver")
 		err := m.receiver.Close(ctx)
@@ -183,6 +221,36 @@ func (m *amqpMessagingService) failed(ctx context.Context, msg *inboundMessage)
 	return m.receiver.ModifyMessage(ctx, msg, true, false, nil)
 }
 
+// reject settles the message as rejected, the broker will not redeliver it
+func (m *amqpMessagingService) reject(ctx context.Context, msg *inboundMessage) error {
+	return m.receiver.RejectMessage(ctx, msg, nil)
+}
+
+// deadMessage republishes the message to the dead message queue, annotated with the reason and the error
+// encountered while processing it. The original message still needs to be settled by the caller.
+func (m *amqpMessagingService) deadMessage(ctx context.Context, msg *inboundMessage, reason string, cause error) error {
+	if m.sender == nil {
+		return errNoDeadMessageQueue
+	}
+	annotations := amqp.Annotations{}
+	for k, v := range msg.Annotations {
+		annotations[k] = v
+	}
+	annotations[deadMessageReasonAnnotation] = reason
+	if cause != nil {
+		annotations[deadMessageErrorAnnotation] = cause.Error()
+	}
+	return m.sender.Send(ctx, &amqp.Message{
+		Header:                msg.Header,
+		Annotations:           annotations,
+		Properties:            msg.Properties,
+		ApplicationProperties: msg.ApplicationProperties,
+		Data:                  msg.Data,
+		Value:                 msg.Value,
+		Footer:                msg.Footer,
+	})
+}
+
 // Allow for substitution in testing to assert correct data is passed to AMQP
 // Due to the way that AMQP authentication is configured in Azure/amqp, we
 // need to monkey substitute here since ConnSASL<auth> returns a function that
@@ -396,6 +396,25 @@ func TestAMQPModifyMessage(t *testing.T) {
 	closeMockedAMQPService(t, service, conn)
 }
 
+func TestAMQPRejectMessage(t *testing.T) {
+	service, conn := startMockedService(t)
+	conn.nextData <- []byte(amqpHelloWorldMsg)
+	msg, err := service.receiveMessage(context.Background())
+	assert.NoError(t, err)
+	writeCalled := make(chan struct{})
+	conn.writeHandle = func(b []byte) (n int, err error) {
+		// assert that a disposition is written
+		assert.Equal(t, byte(0x15), b[10])
+		assert.Equal(t, byte(0x25), b[26]) // 0x25 at the 27th byte in this case means reject
+		close(writeCalled)
+		return len(b), nil
+	}
+	err = service.reject(context.Background(), msg)
+	assert.NoError(t, err)
+	assertChannelClosed(t, writeCalled)
+	closeMockedAMQPService(t, service, conn)
+}
+
 func startMockedService(t *testing.T) (*amqpMessagingService, *connMock) {
 	conn := &connMock{
 		nextData: make(chan []byte, 100),
@@ -0,0 +1,651 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: receive_v2.proto
+
+package v2
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+
+	v1 "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/solacereceiver/model/v1"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Version 2.0
+//
+// Messages with a topic of matching the following topic contain a v2.x
+// specification of this message.
+// #telemetry/broker/trace/receive/v2[/<additional/topic/levels]
+// Note that the specification allows for additional topic levels to be added
+// in the future. Receiving clients must not assume there are no additional
+// topic levels.
+//
+// The versioning strategy follows semantic versioning such that all v2.x
+// specifications are compatible with each other.
+//
+// The v2 specification describes the same receive span as the v1
+// specification, with the following incompatible change:
+//   - The topic of the received message (field 5) is replaced by the
+//     destination the message was published to, which is either a topic or,
+//     for the messages published directly to a queue, the queue.
+//
+// The nested messages and enums are unchanged and are shared with the v1
+// specification. Refer to receive_v1.proto for the description of the fields.
+//
+// Next available field ID: 41
+type SpanData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TraceId                   []byte  `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	SpanId                    []byte  `protobuf:"bytes,2,opt,name=span_id,json=spanId,proto3" json:"span_id,omitempty"`
+	ParentSpanId              []byte  `protobuf:"bytes,16,opt,name=parent_span_id,json=parentSpanId,proto3,oneof" json:"parent_span_id,omitempty"`
+	TraceState                *string `protobuf:"bytes,17,opt,name=trace_state,json=traceState,proto3,oneof" json:"trace_state,omitempty"`
+	StartTimeUnixNano         int64   `protobuf:"fixed64,3,opt,name=start_time_unix_nano,json=startTimeUnixNano,proto3" json:"start_time_unix_nano,omitempty"`
+	EndTimeUnixNano           int64   `protobuf:"fixed64,4,opt,name=end_time_unix_nano,json=endTimeUnixNano,proto3" json:"end_time_unix_nano,omitempty"`
+	BrokerReceiveTimeUnixNano int64   `protobuf:"fixed64,12,opt,name=broker_receive_time_unix_nano,json=brokerReceiveTimeUnixNano,proto3" json:"broker_receive_time_unix_nano,omitempty"`
+	// The destination the message was published to.
+	//
+	// Types that are assignable to Destination:
+	//	*SpanData_Topic
+	//	*SpanData_QueueName
+	Destination                         isSpanData_Destination                    `protobuf_oneof:"destination"`
+	ReplyToTopic                        *string                                   `protobuf:"bytes,18,opt,name=reply_to_topic,json=replyToTopic,proto3,oneof" json:"reply_to_topic,omitempty"`
+	DeliveryMode                        v1.SpanData_DeliveryMode                  `protobuf:"varint,19,opt,name=delivery_mode,json=deliveryMode,proto3,enum=solace.messaging.proto.broker.trace.receive.v1.SpanData_DeliveryMode" json:"delivery_mode,omitempty"`
+	RouterName                          string                                    `protobuf:"bytes,20,opt,name=router_name,json=routerName,proto3" json:"router_name,omitempty"`
+	MessageVpnName                      *string                                   `protobuf:"bytes,21,opt,name=message_vpn_name,json=messageVpnName,proto3,oneof" json:"message_vpn_name,omitempty"`
+	SolosVersion                        string                                    `protobuf:"bytes,38,opt,name=solos_version,json=solosVersion,proto3" json:"solos_version,omitempty"`
+	ClientName                          string                                    `protobuf:"bytes,6,opt,name=client_name,json=clientName,proto3" json:"client_name,omitempty"`
+	ClientUsername                      string                                    `protobuf:"bytes,7,opt,name=client_username,json=clientUsername,proto3" json:"client_username,omitempty"`
+	HostIp                              []byte                                    `protobuf:"bytes,8,opt,name=host_ip,json=hostIp,proto3" json:"host_ip,omitempty"`
+	HostPort                            uint32                                    `protobuf:"varint,9,opt,name=host_port,json=hostPort,proto3" json:"host_port,omitempty"`
+	PeerIp                              []byte                                    `protobuf:"bytes,10,opt,name=peer_ip,json=peerIp,proto3" json:"peer_ip,omitempty"`
+	PeerPort                            uint32                                    `protobuf:"varint,11,opt,name=peer_port,json=peerPort,proto3" json:"peer_port,omitempty"`
+	ReplicationGroupMessageId           []byte                                    `protobuf:"bytes,22,opt,name=replication_group_message_id,json=replicationGroupMessageId,proto3,oneof" json:"replication_group_message_id,omitempty"`
+	Protocol                            string                                    `protobuf:"bytes,23,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	ProtocolVersion                     *string                                   `protobuf:"bytes,24,opt,name=protocol_version,json=protocolVersion,proto3,oneof" json:"protocol_version,omitempty"`
+	DmqEligible                         bool                                      `protobuf:"varint,25,opt,name=dmq_eligible,json=dmqEligible,proto3" json:"dmq_eligible,omitempty"`
+	Priority                            *uint32                                   `protobuf:"varint,26,opt,name=priority,proto3,oneof" json:"priority,omitempty"`
+	Ttl                                 *int64                                    `protobuf:"varint,27,opt,name=ttl,proto3,oneof" json:"ttl,omitempty"`
+	BinaryAttachmentSize                uint32                                    `protobuf:"varint,28,opt,name=binary_attachment_size,json=binaryAttachmentSize,proto3" json:"binary_attachment_size,omitempty"`
+	XmlAttachmentSize                   uint32                                    `protobuf:"varint,29,opt,name=xml_attachment_size,json=xmlAttachmentSize,proto3" json:"xml_attachment_size,omitempty"`
+	MetadataSize                        uint32                                    `protobuf:"varint,30,opt,name=metadata_size,json=metadataSize,proto3" json:"metadata_size,omitempty"`
+	ApplicationMessageId                *string                                   `protobuf:"bytes,31,opt,name=application_message_id,json=applicationMessageId,proto3,oneof" json:"application_message_id,omitempty"`
+	CorrelationId                       *string                                   `protobuf:"bytes,32,opt,name=correlation_id,json=correlationId,proto3,oneof" json:"correlation_id,omitempty"`
+	UserProperties                      map[string]*v1.SpanData_UserPropertyValue `protobuf:"bytes,14,rep,name=user_properties,json=userProperties,proto3" json:"user_properties,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	DroppedApplicationMessageProperties bool                                      `protobuf:"varint,37,opt,name=dropped_application_message_properties,json=droppedApplicationMessageProperties,proto3" json:"dropped_application_message_properties,omitempty"`
+	ErrorDescription                    string                                    `protobuf:"bytes,33,opt,name=error_description,json=errorDescription,proto3" json:"error_description,omitempty"`
+	TransactionEvent                    *v1.SpanData_TransactionEvent             `protobuf:"bytes,34,opt,name=transaction_event,json=transactionEvent,proto3,oneof" json:"transaction_event,omitempty"`
+	EnqueueEvents                       []*v1.SpanData_EnqueueEvent               `protobuf:"bytes,15,rep,name=enqueue_events,json=enqueueEvents,proto3" json:"enqueue_events,omitempty"`
+	DroppedEnqueueEventsSuccess         uint32                                    `protobuf:"varint,35,opt,name=dropped_enqueue_events_success,json=droppedEnqueueEventsSuccess,proto3" json:"dropped_enqueue_events_success,omitempty"`
+	DroppedEnqueueEventsFailed          uint32                                    `protobuf:"varint,36,opt,name=dropped_enqueue_events_failed,json=droppedEnqueueEventsFailed,proto3" json:"dropped_enqueue_events_failed,omitempty"`
+}
+
+func (x *SpanData) Reset() {
+	*x = SpanData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_receive_v2_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpanData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpanData) ProtoMessage() {}
+
+func (x *SpanData) ProtoReflect() protoreflect.Message {
+	mi := &file_receive_v2_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpanData.ProtoReflect.Descriptor instead.
+func (*SpanData) Descriptor() ([]byte, []int) {
+	return file_receive_v2_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SpanData) GetTraceId() []byte {
+	if x != nil {
+		return x.TraceId
+	}
+	return nil
+}
+
+func (x *SpanData) GetSpanId() []byte {
+	if x != nil {
+		return x.SpanId
+	}
+	return nil
+}
+
+func (x *SpanData) GetParentSpanId() []byte {
+	if x != nil {
+		return x.ParentSpanId
+	}
+	return nil
+}
+
+func (x *SpanData) GetTraceState() string {
+	if x != nil && x.TraceState != nil {
+		return *x.TraceState
+	}
+	return ""
+}
+
+func (x *SpanData) GetStartTimeUnixNano() int64 {
+	if x != nil {
+		return x.StartTimeUnixNano
+	}
+	return 0
+}
+
+func (x *SpanData) GetEndTimeUnixNano() int64 {
+	if x != nil {
+		return x.EndTimeUnixNano
+	}
+	return 0
+}
+
+func (x *SpanData) GetBrokerReceiveTimeUnixNano() int64 {
+	if x != nil {
+		return x.BrokerReceiveTimeUnixNano
+	}
+	return 0
+}
+
+func (m *SpanData) GetDestination() isSpanData_Destination {
+	if m != nil {
+		return m.Destination
+	}
+	return nil
+}
+
+func (x *SpanData) GetTopic() string {
+	if x, ok := x.GetDestination().(*SpanData_Topic); ok {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *SpanData) GetQueueName() string {
+	if x, ok := x.GetDestination().(*SpanData_QueueName); ok {
+		return x.QueueName
+	}
+	return ""
+}
+
+func (x *SpanData) GetReplyToTopic() string {
+	if x != nil && x.ReplyToTopic != nil {
+		return *x.ReplyToTopic
+	}
+	return ""
+}
+
+func (x *SpanData) GetDeliveryMode() v1.SpanData_DeliveryMode {
+	if x != nil {
+		return x.DeliveryMode
+	}
+	return v1.SpanData_DeliveryMode(0)
+}
+
+func (x *SpanData) GetRouterName() string {
+	if x != nil {
+		return x.RouterName
+	}
+	return ""
+}
+
+func (x *SpanData) GetMessageVpnName() string {
+	if x != nil && x.MessageVpnName != nil {
+		return *x.MessageVpnName
+	}
+	return ""
+}
+
+func (x *SpanData) GetSolosVersion() string {
+	if x != nil {
+		return x.SolosVersion
+	}
+	return ""
+}
+
+func (x *SpanData) GetClientName() string {
+	if x != nil {
+		return x.ClientName
+	}
+	return ""
+}
+
+func (x *SpanData) GetClientUsername() string {
+	if x != nil {
+		return x.ClientUsername
+	}
+	return ""
+}
+
+func (x *SpanData) GetHostIp() []byte {
+	if x != nil {
+		return x.HostIp
+	}
+	return nil
+}
+
+func (x *SpanData) GetHostPort() uint32 {
+	if x != nil {
+		return x.HostPort
+	}
+	return 0
+}
+
+func (x *SpanData) GetPeerIp() []byte {
+	if x != nil {
+		return x.PeerIp
+	}
+	return nil
+}
+
+func (x *SpanData) GetPeerPort() uint32 {
+	if x != nil {
+		return x.PeerPort
+	}
+	return 0
+}
+
+func (x *SpanData) GetReplicationGroupMessageId() []byte {
+	if x != nil {
+		return x.ReplicationGroupMessageId
+	}
+	return nil
+}
+
+func (x *SpanData) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *SpanData) GetProtocolVersion() string {
+	if x != nil && x.ProtocolVersion != nil {
+		return *x.ProtocolVersion
+	}
+	return ""
+}
+
+func (x *SpanData) GetDmqEligible() bool {
+	if x != nil {
+		return x.DmqEligible
+	}
+	return false
+}
+
+func (x *SpanData) GetPriority() uint32 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return 0
+}
+
+func (x *SpanData) GetTtl() int64 {
+	if x != nil && x.Ttl != nil {
+		return *x.Ttl
+	}
+	return 0
+}
+
+func (x *SpanData) GetBinaryAttachmentSize() uint32 {
+	if x != nil {
+		return x.BinaryAttachmentSize
+	}
+	return 0
+}
+
+func (x *SpanData) GetXmlAttachmentSize() uint32 {
+	if x != nil {
+		return x.XmlAttachmentSize
+	}
+	return 0
+}
+
+func (x *SpanData) GetMetadataSize() uint32 {
+	if x != nil {
+		return x.MetadataSize
+	}
+	return 0
+}
+
+func (x *SpanData) GetApplicationMessageId() string {
+	if x != nil && x.ApplicationMessageId != nil {
+		return *x.ApplicationMessageId
+	}
+	return ""
+}
+
+func (x *SpanData) GetCorrelationId() string {
+	if x != nil && x.CorrelationId != nil {
+		return *x.CorrelationId
+	}
+	return ""
+}
+
+func (x *SpanData) GetUserProperties() map[string]*v1.SpanData_UserPropertyValue {
+	if x != nil {
+		return x.UserProperties
+	}
+	return nil
+}
+
+func (x *SpanData) GetDroppedApplicationMessageProperties() bool {
+	if x != nil {
+		return x.DroppedApplicationMessageProperties
+	}
+	return false
+}
+
+func (x *SpanData) GetErrorDescription() string {
+	if x != nil {
+		return x.ErrorDescription
+	}
+	return ""
+}
+
+func (x *SpanData) GetTransactionEvent() *v1.SpanData_TransactionEvent {
+	if x != nil {
+		return x.TransactionEvent
+	}
+	return nil
+}
+
+func (x *SpanData) GetEnqueueEvents() []*v1.SpanData_EnqueueEvent {
+	if x != nil {
+		return x.EnqueueEvents
+	}
+	return nil
+}
+
+func (x *SpanData) GetDroppedEnqueueEventsSuccess() uint32 {
+	if x != nil {
+		return x.DroppedEnqueueEventsSuccess
+	}
+	return 0
+}
+
+func (x *SpanData) GetDroppedEnqueueEventsFailed() uint32 {
+	if x != nil {
+		return x.DroppedEnqueueEventsFailed
+	}
+	return 0
+}
+
+type isSpanData_Destination interface {
+	isSpanData_Destination()
+}
+
+type SpanData_Topic struct {
+	// The topic of the received message, used to determine where to enqueue
+	// the message.
+	Topic string `protobuf:"bytes,39,opt,name=topic,proto3,oneof"`
+}
+
+type SpanData_QueueName struct {
+	// The queue the message was published to.
+	QueueName string `protobuf:"bytes,40,opt,name=queue_name,json=queueName,proto3,oneof"`
+}
+
+func (*SpanData_Topic) isSpanData_Destination() {}
+
+func (*SpanData_QueueName) isSpanData_Destination() {}
+
+var File_receive_v2_proto protoreflect.FileDescriptor
+
+var file_receive_v2_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x5f, 0x76, 0x32, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x2e, 0x73, 0x6f, 0x6c, 0x61, 0x63, 0x65, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65,
+	0x72, 0x2e, 0x74, 0x72, 0x61, 0x63, 0x65, 0x2e, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x2e,
+	0x76, 0x32, 0x1a, 0x10, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x5f, 0x76, 0x31, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf4, 0x11, 0x0a, 0x08, 0x53, 0x70, 0x61, 0x6e, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x74, 0x72, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07,
+	0x73, 0x70, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73,
+	0x70, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x0e, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f,
+	0x73, 0x70, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x01, 0x52,
+	0x0c, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x53, 0x70, 0x61, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x24, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x11, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0a, 0x74, 0x72, 0x61, 0x63, 0x65, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x14, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x10, 0x52, 0x11, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x55,
+	0x6e, 0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x2b, 0x0a, 0x12, 0x65, 0x6e, 0x64, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x10, 0x52, 0x0f, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78,
+	0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x40, 0x0a, 0x1d, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x5f, 0x72,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78,
+	0x5f, 0x6e, 0x61, 0x6e, 0x6f, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x10, 0x52, 0x19, 0x62, 0x72, 0x6f,
+	0x6b, 0x65, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e,
+	0x69, 0x78, 0x4e, 0x61, 0x6e, 0x6f, 0x12, 0x16, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x18,
+	0x27, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x1f,
+	0x0a, 0x0a, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x28, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x71, 0x75, 0x65, 0x75, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x29, 0x0a, 0x0e, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x5f, 0x74, 0x6f, 0x5f, 0x74, 0x6f, 0x70, 0x69,
+	0x63, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0c, 0x72, 0x65, 0x70, 0x6c, 0x79,
+	0x54, 0x6f, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x88, 0x01, 0x01, 0x12, 0x6a, 0x0a, 0x0d, 0x64, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x45, 0x2e, 0x73, 0x6f, 0x6c, 0x61, 0x63, 0x65, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65,
+	0x72, 0x2e, 0x74, 0x72, 0x61, 0x63, 0x65, 0x2e, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0c, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65,
+	0x72, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x10, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x5f, 0x76, 0x70, 0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x04, 0x52, 0x0e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x56, 0x70, 0x6e, 0x4e,
+	0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6f, 0x6c, 0x6f, 0x73, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x26, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73,
+	0x6f, 0x6c, 0x6f, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x27, 0x0a, 0x0f,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x55, 0x73, 0x65,
+	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x69, 0x70,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x68, 0x6f, 0x73, 0x74, 0x49, 0x70, 0x12, 0x1b,
+	0x0a, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x70,
+	0x65, 0x65, 0x72, 0x5f, 0x69, 0x70, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x65,
+	0x65, 0x72, 0x49, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x72,
+	0x74, 0x12, 0x44, 0x0a, 0x1c, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x05, 0x52, 0x19, 0x72, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x18, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x12, 0x2e, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x18, 0x20, 0x01, 0x28, 0x09, 0x48, 0x06, 0x52,
+	0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x6d, 0x71, 0x5f, 0x65, 0x6c, 0x69, 0x67, 0x69,
+	0x62, 0x6c, 0x65, 0x18, 0x19, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x64, 0x6d, 0x71, 0x45, 0x6c,
+	0x69, 0x67, 0x69, 0x62, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x07, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f,
+	0x72, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03, 0x74, 0x74, 0x6c, 0x18, 0x1b,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x08, 0x52, 0x03, 0x74, 0x74, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x34,
+	0x0a, 0x16, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x5f, 0x61, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d,
+	0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14,
+	0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x2e, 0x0a, 0x13, 0x78, 0x6d, 0x6c, 0x5f, 0x61, 0x74, 0x74, 0x61,
+	0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x1d, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x11, 0x78, 0x6d, 0x6c, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x39, 0x0a, 0x16, 0x61, 0x70, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x09, 0x48, 0x09, 0x52, 0x14, 0x61, 0x70, 0x70,
+	0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49,
+	0x64, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x20, 0x20, 0x01, 0x28, 0x09, 0x48, 0x0a, 0x52, 0x0d,
+	0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x75, 0x0a, 0x0f, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74,
+	0x69, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x4c, 0x2e, 0x73, 0x6f, 0x6c, 0x61,
+	0x63, 0x65, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x74, 0x72, 0x61, 0x63, 0x65, 0x2e,
+	0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x44,
+	0x61, 0x74, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69,
+	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x50, 0x72, 0x6f,
+	0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x12, 0x53, 0x0a, 0x26, 0x64, 0x72, 0x6f, 0x70, 0x70,
+	0x65, 0x64, 0x5f, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65,
+	0x73, 0x18, 0x25, 0x20, 0x01, 0x28, 0x08, 0x52, 0x23, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64,
+	0x41, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x11,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x21, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x44, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x7b, 0x0a, 0x11, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x22,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x49, 0x2e, 0x73, 0x6f, 0x6c, 0x61, 0x63, 0x65, 0x2e, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x62, 0x72,
+	0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x74, 0x72, 0x61, 0x63, 0x65, 0x2e, 0x72, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48,
+	0x0b, 0x52, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x6c, 0x0a, 0x0e, 0x65, 0x6e, 0x71, 0x75, 0x65, 0x75,
+	0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x45,
+	0x2e, 0x73, 0x6f, 0x6c, 0x61, 0x63, 0x65, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x69, 0x6e,
+	0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x74,
+	0x72, 0x61, 0x63, 0x65, 0x2e, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x70, 0x61, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x45, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x0d, 0x65, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x43, 0x0a, 0x1e, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x5f,
+	0x65, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x5f, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x23, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x1b, 0x64, 0x72,
+	0x6f, 0x70, 0x70, 0x65, 0x64, 0x45, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x73, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x41, 0x0a, 0x1d, 0x64, 0x72, 0x6f,
+	0x70, 0x70, 0x65, 0x64, 0x5f, 0x65, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x73, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x24, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x1a, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x45, 0x6e, 0x71, 0x75, 0x65, 0x75, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x1a, 0x8d, 0x01, 0x0a,
+	0x13, 0x55, 0x73, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x60, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x4a, 0x2e, 0x73, 0x6f, 0x6c, 0x61, 0x63, 0x65, 0x2e, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x62,
+	0x72, 0x6f, 0x6b, 0x65, 0x72, 0x2e, 0x74, 0x72, 0x61, 0x63, 0x65, 0x2e, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e, 0x44, 0x61, 0x74, 0x61, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0d, 0x0a, 0x0b,
+	0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x11, 0x0a, 0x0f, 0x5f,
+	0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x70, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x42, 0x0e,
+	0x0a, 0x0c, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x42, 0x11,
+	0x0a, 0x0f, 0x5f, 0x72, 0x65, 0x70, 0x6c, 0x79, 0x5f, 0x74, 0x6f, 0x5f, 0x74, 0x6f, 0x70, 0x69,
+	0x63, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x76, 0x70,
+	0x6e, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x1f, 0x0a, 0x1d, 0x5f, 0x72, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x0b, 0x0a, 0x09,
+	0x5f, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x74, 0x74,
+	0x6c, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x42, 0x11, 0x0a, 0x0f,
+	0x5f, 0x63, 0x6f, 0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x42,
+	0x14, 0x0a, 0x12, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x65, 0x76, 0x65, 0x6e, 0x74, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_receive_v2_proto_rawDescOnce sync.Once
+	file_receive_v2_proto_rawDescData = file_receive_v2_proto_rawDesc
+)
+
+func file_receive_v2_proto_rawDescGZIP() []byte {
+	file_receive_v2_proto_rawDescOnce.Do(func() {
+		file_receive_v2_proto_rawDescData = protoimpl.X.CompressGZIP(file_receive_v2_proto_rawDescData)
+	})
+	return file_receive_v2_proto_rawDescData
+}
+
+var file_receive_v2_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_receive_v2_proto_goTypes = []interface{}{
+	(*SpanData)(nil),                      // 0: solace.messaging.proto.broker.trace.receive.v2.SpanData
+	nil,                                   // 1: solace.messaging.proto.broker.trace.receive.v2.SpanData.UserPropertiesEntry
+	(v1.SpanData_DeliveryMode)(0),         // 2: solace.messaging.proto.broker.trace.receive.v1.SpanData.DeliveryMode
+	(*v1.SpanData_TransactionEvent)(nil),  // 3: solace.messaging.proto.broker.trace.receive.v1.SpanData.TransactionEvent
+	(*v1.SpanData_EnqueueEvent)(nil),      // 4: solace.messaging.proto.broker.trace.receive.v1.SpanData.EnqueueEvent
+	(*v1.SpanData_UserPropertyValue)(nil), // 5: solace.messaging.proto.broker.trace.receive.v1.SpanData.UserPropertyValue
+}
+var file_receive_v2_proto_depIdxs = []int32{
+	2, // 0: solace.messaging.proto.broker.trace.receive.v2.SpanData.delivery_mode:type_name -> solace.messaging.proto.broker.trace.receive.v1.SpanData.DeliveryMode
+	1, // 1: solace.messaging.proto.broker.trace.receive.v2.SpanData.user_properties:type_name -> solace.messaging.proto.broker.trace.receive.v2.SpanData.UserPropertiesEntry
+	3, // 2: solace.messaging.proto.broker.trace.receive.v2.SpanData.transaction_event:type_name -> solace.messaging.proto.broker.trace.receive.v1.SpanData.TransactionEvent
+	4, // 3: solace.messaging.proto.broker.trace.receive.v2.SpanData.enqueue_events:type_name -> solace.messaging.proto.broker.trace.receive.v1.SpanData.EnqueueEvent
+	5, // 4: solace.messaging.proto.broker.trace.receive.v2.SpanData.UserPropertiesEntry.value:type_name -> solace.messaging.proto.broker.trace.receive.v1.SpanData.UserPropertyValue
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_receive_v2_proto_init() }
+func file_receive_v2_proto_init() {
+	if File_receive_v2_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_receive_v2_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SpanData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_receive_v2_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*SpanData_Topic)(nil),
+		(*SpanData_QueueName)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_receive_v2_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_receive_v2_proto_goTypes,
+		DependencyIndexes: file_receive_v2_proto_depIdxs,
+		MessageInfos:      file_receive_v2_proto_msgTypes,
+	}.Build()
+	File_receive_v2_proto = out.File
+	file_receive_v2_proto_rawDesc = nil
+	file_receive_v2_proto_goTypes = nil
+	file_receive_v2_proto_depIdxs = nil
+}
@@ -16,9 +16,20 @@ package solacereceiver // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"context"
+	"time"
 
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.uber.org/atomic"
+	"go.uber.org/multierr"
 )
 
 const (
@@ -27,6 +38,14 @@ const (
 	// metricPrefix used to prefix solace specific metrics
 	metricPrefix = "solacereceiver"
 	nameSep      = "/"
+	// reasonKey is the attribute of the dead message metrics holding the reason the message could not be processed.
+	reasonKey = "reason"
+
+	// useOtelForInternalMetricsGateID is the collector feature gate reporting its own metrics with
+	// OpenTelemetry instead of OpenCensus, the receiver metrics follow it.
+	useOtelForInternalMetricsGateID = "telemetry.useOtelForInternalMetrics"
+	// meterScope is the instrumentation scope of the receiver metrics reported with OpenTelemetry.
+	meterScope = "go.opentelemetry.io/collector/receiver/solacereceiver"
 )
 
 type receiverState uint8
@@ -40,8 +59,13 @@ const (
 	receiverStateTerminated
 )
 
-type opencensusMetrics struct {
-	stats struct {
+// receiverMetrics records the receiver metrics with OpenTelemetry through the MeterProvider of the collector
+// when the telemetry.useOtelForInternalMetrics feature gate is enabled, and with OpenCensus otherwise. The
+// OpenTelemetry metrics are named after the component type and hold the receiver ID as the receiver
+// attribute, while the OpenCensus ones keep the instance name in their name.
+type receiverMetrics struct {
+	useOtel bool
+	stats   struct {
 		failedReconnections            *stats.Int64Measure
 		recoverableUnmarshallingErrors *stats.Int64Measure
 		fatalUnmarshallingErrors       *stats.Int64Measure
@@ -50,6 +74,11 @@ type opencensusMetrics struct {
 		reportedSpans                  *stats.Int64Measure
 		receiverStatus                 *stats.Int64Measure
 		needUpgrade                    *stats.Int64Measure
+		deadMessages                   *stats.Int64Measure
+		failedDeadMessages             *stats.Int64Measure
+		queueDepth                     *stats.Int64Measure
+		ackLatency                     *stats.Float64Measure
+		connectionState                *stats.Int64Measure
 	}
 	views struct {
 		failedReconnections            *view.View
@@ -60,25 +89,99 @@ type opencensusMetrics struct {
 		reportedSpans                  *view.View
 		receiverStatus                 *view.View
 		needUpgrade                    *view.View
+		deadMessages                   *view.View
+		failedDeadMessages             *view.View
+		queueDepth                     *view.View
+		ackLatency                     *view.View
+		connectionState                *view.View
+	}
+	// reasonKey is used to tag dead message metrics with the reason the message could not be processed.
+	reasonKey tag.Key
+
+	otel struct {
+		attrs                          []attribute.KeyValue
+		failedReconnections            syncint64.Counter
+		recoverableUnmarshallingErrors syncint64.Counter
+		fatalUnmarshallingErrors       syncint64.Counter
+		droppedSpanMessages            syncint64.Counter
+		receivedSpanMessages           syncint64.Counter
+		reportedSpans                  syncint64.Counter
+		deadMessages                   syncint64.Counter
+		failedDeadMessages             syncint64.Counter
+		queueDepth                     syncint64.UpDownCounter
+		ackLatency                     syncfloat64.Histogram
+		receiverStatus                 asyncint64.Gauge
+		needUpgrade                    asyncint64.Gauge
+		connectionState                asyncint64.Gauge
+	}
+	// the values of the gauges observed when the OpenTelemetry metrics are collected
+	status    *atomic.Int64
+	upgrade   *atomic.Int64
+	connected *atomic.Int64
+}
+
+// newReceiverMetrics creates the metrics of the receiver, it registers the OpenCensus views unless useOtel is set.
+func newReceiverMetrics(id component.ID, set component.TelemetrySettings, useOtel bool) (*receiverMetrics, error) {
+	m := &receiverMetrics{
+		useOtel:   useOtel,
+		status:    atomic.NewInt64(int64(receiverStateStarting)),
+		upgrade:   atomic.NewInt64(0),
+		connected: atomic.NewInt64(0),
 	}
+	if useOtel {
+		if err := m.createOtelMetrics(id, set); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	if err := m.registerOpenCensusViews(id.Name()); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
+const (
+	failedReconnectionsDescription            = "Number of failed broker reconnections"
+	recoverableUnmarshallingErrorsDescription = "Number of recoverable message unmarshalling errors"
+	fatalUnmarshallingErrorsDescription       = "Number of fatal message unmarshalling errors"
+	droppedSpanMessagesDescription            = "Number of dropped span messages"
+	receivedSpanMessagesDescription           = "Number of received span messages"
+	reportedSpansDescription                  = "Number of reported spans"
+	receiverStatusDescription                 = "Indicates the status of the receiver as an enum. 0 = starting, 1 = connecting, 2 = connected, 3 = disabled (often paired with needs_upgrade), 4 = terminating, 5 = terminated"
+	needUpgradeDescription                    = "Indicates with value 1 that receiver requires an upgrade and is not compatible with messages received from a broker"
+	deadMessagesDescription                   = "Number of unprocessable messages republished to the dead message queue"
+	failedDeadMessagesDescription             = "Number of unprocessable messages that failed to be republished to the dead message queue"
+	queueDepthDescription                     = "Number of received messages waiting for a worker"
+	ackLatencyDescription                     = "Time in milliseconds between the start of the processing of a message and its settlement"
+	connectionStateDescription                = "Indicates with value 1 that the receiver is connected to the broker"
+)
+
 // receiver will register internal telemetry views
-func newOpenCensusMetrics(instanceName string) (*opencensusMetrics, error) {
-	m := &opencensusMetrics{}
+func (m *receiverMetrics) registerOpenCensusViews(instanceName string) error {
+	var err error
+	if m.reasonKey, err = tag.NewKey(reasonKey); err != nil {
+		return err
+	}
 	prefix := metricPrefix + nameSep
 	if instanceName != "" {
 		prefix += instanceName + nameSep
 	}
 
-	m.stats.failedReconnections = stats.Int64(prefix+"failed_reconnections", "Number of failed broker reconnections", stats.UnitDimensionless)
-	m.stats.recoverableUnmarshallingErrors = stats.Int64(prefix+"recoverable_unmarshalling_errors", "Number of recoverable message unmarshalling errors", stats.UnitDimensionless)
-	m.stats.fatalUnmarshallingErrors = stats.Int64(prefix+"fatal_unmarshalling_errors", "Number of fatal message unmarshalling errors", stats.UnitDimensionless)
-	m.stats.droppedSpanMessages = stats.Int64(prefix+"dropped_span_messages", "Number of dropped span messages", stats.UnitDimensionless)
-	m.stats.receivedSpanMessages = stats.Int64(prefix+"received_span_messages", "Number of received span messages", stats.UnitDimensionless)
-	m.stats.reportedSpans = stats.Int64(prefix+"reported_spans", "Number of reported spans", stats.UnitDimensionless)
-	m.stats.receiverStatus = stats.Int64(prefix+"receiver_status", "Indicates the status of the receiver as an enum. 0 = starting, 1 = connecting, 2 = connected, 3 = disabled (often paired with needs_upgrade), 4 = terminating, 5 = terminated", stats.UnitDimensionless)
-	m.stats.needUpgrade = stats.Int64(prefix+"need_upgrade", "Indicates with value 1 that receiver requires an upgrade and is not compatible with messages received from a broker", stats.UnitDimensionless)
+	m.stats.failedReconnections = stats.Int64(prefix+"failed_reconnections", failedReconnectionsDescription, stats.UnitDimensionless)
+	m.stats.recoverableUnmarshallingErrors = stats.Int64(prefix+"recoverable_unmarshalling_errors", recoverableUnmarshallingErrorsDescription, stats.UnitDimensionless)
+	m.stats.fatalUnmarshallingErrors = stats.Int64(prefix+"fatal_unmarshalling_errors", fatalUnmarshallingErrorsDescription, stats.UnitDimensionless)
+	m.stats.droppedSpanMessages = stats.Int64(prefix+"dropped_span_messages", droppedSpanMessagesDescription, stats.UnitDimensionless)
+	m.stats.receivedSpanMessages = stats.Int64(prefix+"received_span_messages", receivedSpanMessagesDescription, stats.UnitDimensionless)
+	m.stats.reportedSpans = stats.Int64(prefix+"reported_spans", reportedSpansDescription, stats.UnitDimensionless)
+	m.stats.receiverStatus = stats.Int64(prefix+"receiver_status", receiverStatusDescription, stats.UnitDimensionless)
+	m.stats.needUpgrade = stats.Int64(prefix+"need_upgrade", needUpgradeDescription, stats.UnitDimensionless)
+
+	m.stats.deadMessages = stats.Int64(prefix+"dead_messages", deadMessagesDescription, stats.UnitDimensionless)
+	m.stats.failedDeadMessages = stats.Int64(prefix+"failed_dead_messages", failedDeadMessagesDescription, stats.UnitDimensionless)
+
+	m.stats.queueDepth = stats.Int64(prefix+"queue_depth", queueDepthDescription, stats.UnitDimensionless)
+	m.stats.ackLatency = stats.Float64(prefix+"ack_latency", ackLatencyDescription, stats.UnitMilliseconds)
+	m.stats.connectionState = stats.Int64(prefix+"connection_state", connectionStateDescription, stats.UnitDimensionless)
 
 	m.views.failedReconnections = fromMeasure(m.stats.failedReconnections, view.Count())
 	m.views.recoverableUnmarshallingErrors = fromMeasure(m.stats.recoverableUnmarshallingErrors, view.Count())
@@ -88,8 +191,13 @@ func newOpenCensusMetrics(instanceName string) (*opencensusMetrics, error) {
 	m.views.reportedSpans = fromMeasure(m.stats.reportedSpans, view.Sum())
 	m.views.receiverStatus = fromMeasure(m.stats.receiverStatus, view.LastValue())
 	m.views.needUpgrade = fromMeasure(m.stats.needUpgrade, view.LastValue())
+	m.views.deadMessages = fromMeasure(m.stats.deadMessages, view.Count(), m.reasonKey)
+	m.views.failedDeadMessages = fromMeasure(m.stats.failedDeadMessages, view.Count(), m.reasonKey)
+	m.views.queueDepth = fromMeasure(m.stats.queueDepth, view.Sum())
+	m.views.ackLatency = fromMeasure(m.stats.ackLatency, view.Distribution(1, 5, 10, 50, 100, 500, 1000, 5000))
+	m.views.connectionState = fromMeasure(m.stats.connectionState, view.LastValue())
 
-	err := view.Register(
+	return view.Register(
 		m.views.failedReconnections,
 		m.views.recoverableUnmarshallingErrors,
 		m.views.fatalUnmarshallingErrors,
@@ -98,19 +206,21 @@ func newOpenCensusMetrics(instanceName string) (*opencensusMetrics, error) {
 		m.views.reportedSpans,
 		m.views.receiverStatus,
 		m.views.needUpgrade,
+		m.views.deadMessages,
+		m.views.failedDeadMessages,
+		m.views.queueDepth,
+		m.views.ackLatency,
+		m.views.connectionState,
 	)
-	if err != nil {
-		return nil, err
-	}
-	return m, nil
 }
 
-func fromMeasure(measure stats.Measure, agg *view.Aggregation) *view.View {
+func fromMeasure(measure stats.Measure, agg *view.Aggregation, tagKeys ...tag.Key) *view.View {
 	return &view.View{
 		Name:        buildReceiverCustomMetricName(measure.Name()),
 		Description: measure.Description(),
 		Measure:     measure,
 		Aggregation: agg,
+		TagKeys:     tagKeys,
 	}
 }
 
@@ -118,42 +228,154 @@ func buildReceiverCustomMetricName(metric string) string {
 	return receiverKey + nameSep + string(componentType) + nameSep + metric
 }
 
+// createOtelMetrics creates the instruments of the metrics reported with OpenTelemetry.
+func (m *receiverMetrics) createOtelMetrics(id component.ID, set component.TelemetrySettings) error {
+	meter := set.MeterProvider.Meter(meterScope)
+	m.otel.attrs = []attribute.KeyValue{attribute.String(receiverKey, id.String())}
+
+	var errs, err error
+	counter := func(name, description string) syncint64.Counter {
+		var c syncint64.Counter
+		c, err = meter.SyncInt64().Counter(buildReceiverCustomMetricName(name),
+			instrument.WithDescription(description), instrument.WithUnit(unit.Dimensionless))
+		errs = multierr.Append(errs, err)
+		return c
+	}
+	gauge := func(name, description string) asyncint64.Gauge {
+		var g asyncint64.Gauge
+		g, err = meter.AsyncInt64().Gauge(buildReceiverCustomMetricName(name),
+			instrument.WithDescription(description), instrument.WithUnit(unit.Dimensionless))
+		errs = multierr.Append(errs, err)
+		return g
+	}
+
+	m.otel.failedReconnections = counter("failed_reconnections", failedReconnectionsDescription)
+	m.otel.recoverableUnmarshallingErrors = counter("recoverable_unmarshalling_errors", recoverableUnmarshallingErrorsDescription)
+	m.otel.fatalUnmarshallingErrors = counter("fatal_unmarshalling_errors", fatalUnmarshallingErrorsDescription)
+	m.otel.droppedSpanMessages = counter("dropped_span_messages", droppedSpanMessagesDescription)
+	m.otel.receivedSpanMessages = counter("received_span_messages", receivedSpanMessagesDescription)
+	m.otel.reportedSpans = counter("reported_spans", reportedSpansDescription)
+	m.otel.deadMessages = counter("dead_messages", deadMessagesDescription)
+	m.otel.failedDeadMessages = counter("failed_dead_messages", failedDeadMessagesDescription)
+
+	m.otel.queueDepth, err = meter.SyncInt64().UpDownCounter(buildReceiverCustomMetricName("queue_depth"),
+		instrument.WithDescription(queueDepthDescription), instrument.WithUnit(unit.Dimensionless))
+	errs = multierr.Append(errs, err)
+	m.otel.ackLatency, err = meter.SyncFloat64().Histogram(buildReceiverCustomMetricName("ack_latency"),
+		instrument.WithDescription(ackLatencyDescription), instrument.WithUnit(unit.Milliseconds))
+	errs = multierr.Append(errs, err)
+
+	m.otel.receiverStatus = gauge("receiver_status", receiverStatusDescription)
+	m.otel.needUpgrade = gauge("need_upgrade", needUpgradeDescription)
+	m.otel.connectionState = gauge("connection_state", connectionStateDescription)
+	if errs != nil {
+		return errs
+	}
+	return meter.RegisterCallback(
+		[]instrument.Asynchronous{m.otel.receiverStatus, m.otel.needUpgrade, m.otel.connectionState},
+		func(ctx context.Context) {
+			m.otel.receiverStatus.Observe(ctx, m.status.Load(), m.otel.attrs...)
+			m.otel.needUpgrade.Observe(ctx, m.upgrade.Load(), m.otel.attrs...)
+			m.otel.connectionState.Observe(ctx, m.connected.Load(), m.otel.attrs...)
+		},
+	)
+}
+
+// count adds the value to the counter, or records it for the measure when the metrics are reported with OpenCensus.
+func (m *receiverMetrics) count(counter syncint64.Counter, measure *stats.Int64Measure, value int64) {
+	if m.useOtel {
+		counter.Add(context.Background(), value, m.otel.attrs...)
+		return
+	}
+	stats.Record(context.Background(), measure.M(value))
+}
+
+// countWithReason is count for the metrics tagged with the reason of the dead messages.
+func (m *receiverMetrics) countWithReason(counter syncint64.Counter, measure *stats.Int64Measure, reason string) {
+	if m.useOtel {
+		counter.Add(context.Background(), 1, append([]attribute.KeyValue{attribute.String(reasonKey, reason)}, m.otel.attrs...)...)
+		return
+	}
+	_ = stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Upsert(m.reasonKey, reason)}, measure.M(1))
+}
+
 // recordFailedReconnection increments the metric that records failed reconnection event.
-func (m *opencensusMetrics) recordFailedReconnection() {
-	stats.Record(context.Background(), m.stats.failedReconnections.M(1))
+func (m *receiverMetrics) recordFailedReconnection() {
+	m.count(m.otel.failedReconnections, m.stats.failedReconnections, 1)
 }
 
 // recordRecoverableUnmarshallingError increments the metric that records a recoverable error by trace message unmarshalling.
-func (m *opencensusMetrics) recordRecoverableUnmarshallingError() {
-	stats.Record(context.Background(), m.stats.recoverableUnmarshallingErrors.M(1))
+func (m *receiverMetrics) recordRecoverableUnmarshallingError() {
+	m.count(m.otel.recoverableUnmarshallingErrors, m.stats.recoverableUnmarshallingErrors, 1)
 }
 
 // recordFatalUnmarshallingError increments the metric that records a fatal arrow by trace message unmarshalling.
-func (m *opencensusMetrics) recordFatalUnmarshallingError() {
-	stats.Record(context.Background(), m.stats.fatalUnmarshallingErrors.M(1))
+func (m *receiverMetrics) recordFatalUnmarshallingError() {
+	m.count(m.otel.fatalUnmarshallingErrors, m.stats.fatalUnmarshallingErrors, 1)
 }
 
 // recordDroppedSpanMessages increments the metric that records a dropped span message
-func (m *opencensusMetrics) recordDroppedSpanMessages() {
-	stats.Record(context.Background(), m.stats.droppedSpanMessages.M(1))
+func (m *receiverMetrics) recordDroppedSpanMessages() {
+	m.count(m.otel.droppedSpanMessages, m.stats.droppedSpanMessages, 1)
 }
 
 // recordReceivedSpanMessages increments the metric that records a received span message
-func (m *opencensusMetrics) recordReceivedSpanMessages() {
-	stats.Record(context.Background(), m.stats.receivedSpanMessages.M(1))
+func (m *receiverMetrics) recordReceivedSpanMessages() {
+	m.count(m.otel.receivedSpanMessages, m.stats.receivedSpanMessages, 1)
 }
 
 // recordReportedSpans increments the metric that records the number of spans reported to the next consumer
-func (m *opencensusMetrics) recordReportedSpans() {
-	stats.Record(context.Background(), m.stats.reportedSpans.M(1))
+func (m *receiverMetrics) recordReportedSpans() {
+	m.count(m.otel.reportedSpans, m.stats.reportedSpans, 1)
 }
 
-// recordReceiverStatus sets the metric that records the current state of the receiver to the given state
-func (m *opencensusMetrics) recordReceiverStatus(status receiverState) {
-	stats.Record(context.Background(), m.stats.receiverStatus.M(int64(status)))
+// recordReceiverStatus sets the metric that records the current state of the receiver to the given state,
+// and the connection state accordingly
+func (m *receiverMetrics) recordReceiverStatus(status receiverState) {
+	var connected int64
+	if status == receiverStateConnected {
+		connected = 1
+	}
+	m.status.Store(int64(status))
+	m.connected.Store(connected)
+	if !m.useOtel {
+		stats.Record(context.Background(), m.stats.receiverStatus.M(int64(status)), m.stats.connectionState.M(connected))
+	}
 }
 
 // RecordNeedRestart turns a need restart flag on
-func (m *opencensusMetrics) recordNeedUpgrade() {
-	stats.Record(context.Background(), m.stats.needUpgrade.M(1))
+func (m *receiverMetrics) recordNeedUpgrade() {
+	m.upgrade.Store(1)
+	if !m.useOtel {
+		stats.Record(context.Background(), m.stats.needUpgrade.M(1))
+	}
+}
+
+// recordDeadMessage increments the metric that records a message republished to the dead message queue for the given reason
+func (m *receiverMetrics) recordDeadMessage(reason string) {
+	m.countWithReason(m.otel.deadMessages, m.stats.deadMessages, reason)
+}
+
+// recordFailedDeadMessage increments the metric that records a message that could not be republished to the dead message queue
+func (m *receiverMetrics) recordFailedDeadMessage(reason string) {
+	m.countWithReason(m.otel.failedDeadMessages, m.stats.failedDeadMessages, reason)
+}
+
+// recordQueueDepth adds delta to the metric that records the number of messages waiting for a worker
+func (m *receiverMetrics) recordQueueDepth(delta int64) {
+	if m.useOtel {
+		m.otel.queueDepth.Add(context.Background(), delta, m.otel.attrs...)
+		return
+	}
+	stats.Record(context.Background(), m.stats.queueDepth.M(delta))
+}
+
+// recordAckLatency records the time between the start of the processing of a message and its settlement
+func (m *receiverMetrics) recordAckLatency(latency time.Duration) {
+	ms := float64(latency) / float64(time.Millisecond)
+	if m.useOtel {
+		m.otel.ackLatency.Record(context.Background(), ms, m.otel.attrs...)
+		return
+	}
+	stats.Record(context.Background(), m.stats.ackLatency.M(ms))
 }
@@ -15,13 +15,20 @@
 package solacereceiver
 
 import (
+	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 type metricsTestCase struct {
@@ -45,6 +52,18 @@ func TestRecordMetrics(t *testing.T) {
 			metrics.recordReceiverStatus(receiverStateTerminated)
 		}, metrics.views.receiverStatus, metrics.stats.receiverStatus, 3, int(receiverStateTerminated)},
 		{metrics.recordNeedUpgrade, metrics.views.needUpgrade, metrics.stats.needUpgrade, 3, 1},
+		{func() {
+			metrics.recordDeadMessage(deadMessageReasonBadMessage)
+		}, metrics.views.deadMessages, metrics.stats.deadMessages, 3, 3},
+		{func() {
+			metrics.recordFailedDeadMessage(deadMessageReasonBadMessage)
+		}, metrics.views.failedDeadMessages, metrics.stats.failedDeadMessages, 3, 3},
+		{func() {
+			metrics.recordQueueDepth(1)
+		}, metrics.views.queueDepth, metrics.stats.queueDepth, 3, 3},
+		{func() {
+			metrics.recordReceiverStatus(receiverStateConnected)
+		}, metrics.views.connectionState, metrics.stats.connectionState, 3, 1},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.m.Name(), func(t *testing.T) {
@@ -56,6 +75,91 @@ func TestRecordMetrics(t *testing.T) {
 	}
 }
 
+func TestRecordAckLatency(t *testing.T) {
+	metrics := newTestMetrics(t)
+	metrics.recordAckLatency(2 * time.Millisecond)
+	metrics.recordAckLatency(20 * time.Millisecond)
+	rows, err := view.RetrieveData(metrics.views.ackLatency.Name)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	data := rows[0].Data.(*view.DistributionData)
+	assert.EqualValues(t, 2, data.Count)
+	assert.Equal(t, 22.0, data.Sum())
+}
+
+func TestRecordMetricsWithOtel(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	set := componenttest.NewNopTelemetrySettings()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	metrics, err := newReceiverMetrics(component.NewIDWithName(componentType, "otel"), set, true)
+	require.NoError(t, err)
+
+	metrics.recordFailedReconnection()
+	metrics.recordRecoverableUnmarshallingError()
+	metrics.recordFatalUnmarshallingError()
+	metrics.recordDroppedSpanMessages()
+	metrics.recordReceivedSpanMessages()
+	metrics.recordReceivedSpanMessages()
+	metrics.recordReportedSpans()
+	metrics.recordReceiverStatus(receiverStateConnected)
+	metrics.recordNeedUpgrade()
+	metrics.recordDeadMessage(deadMessageReasonBadMessage)
+	metrics.recordFailedDeadMessage(deadMessageReasonEmptyPayload)
+	metrics.recordQueueDepth(1)
+	metrics.recordQueueDepth(1)
+	metrics.recordQueueDepth(-1)
+	metrics.recordAckLatency(5 * time.Millisecond)
+
+	rm, err := reader.Collect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, meterScope, rm.ScopeMetrics[0].Scope.Name)
+
+	receiver := attribute.String("receiver", "solace/otel")
+	values := map[string]int64{}
+	for _, metric := range rm.ScopeMetrics[0].Metrics {
+		switch data := metric.Data.(type) {
+		case metricdata.Sum[int64]:
+			require.Len(t, data.DataPoints, 1, metric.Name)
+			dp := data.DataPoints[0]
+			if reason, ok := dp.Attributes.Value("reason"); ok {
+				assert.Equal(t, attribute.NewSet(receiver, attribute.String("reason", reason.AsString())), dp.Attributes)
+			} else {
+				assert.Equal(t, attribute.NewSet(receiver), dp.Attributes)
+			}
+			values[metric.Name] = dp.Value
+		case metricdata.Gauge[int64]:
+			require.Len(t, data.DataPoints, 1, metric.Name)
+			assert.Equal(t, attribute.NewSet(receiver), data.DataPoints[0].Attributes)
+			values[metric.Name] = data.DataPoints[0].Value
+		case metricdata.Histogram:
+			require.Len(t, data.DataPoints, 1, metric.Name)
+			assert.Equal(t, 5.0, data.DataPoints[0].Sum)
+			values[metric.Name] = int64(data.DataPoints[0].Count)
+		default:
+			assert.Failf(t, "unexpected metric type", "%s: %T", metric.Name, metric.Data)
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		"receiver/solace/failed_reconnections":             1,
+		"receiver/solace/recoverable_unmarshalling_errors": 1,
+		"receiver/solace/fatal_unmarshalling_errors":       1,
+		"receiver/solace/dropped_span_messages":            1,
+		"receiver/solace/received_span_messages":           2,
+		"receiver/solace/reported_spans":                   1,
+		"receiver/solace/dead_messages":                    1,
+		"receiver/solace/failed_dead_messages":             1,
+		"receiver/solace/queue_depth":                      1,
+		"receiver/solace/ack_latency":                      1,
+		"receiver/solace/receiver_status":                  int64(receiverStateConnected),
+		"receiver/solace/need_upgrade":                     1,
+		"receiver/solace/connection_state":                 1,
+	}, values)
+
+	// the views aren't registered when the metrics are reported with OpenTelemetry
+	assert.Nil(t, view.Find("receiver/solace/solacereceiver/otel/failed_reconnections"))
+}
+
 func validateMetric(t *testing.T, v *view.View, expected interface{}) {
 	// hack to reset stats to 0
 	defer func() {
@@ -85,14 +189,14 @@ func TestRegisterViewsExpectingFailure(t *testing.T) {
 		Aggregation: view.Sum(),
 	})
 	require.NoError(t, err)
-	metrics, err := newOpenCensusMetrics(t.Name())
+	metrics, err := newReceiverMetrics(component.NewIDWithName(componentType, t.Name()), componenttest.NewNopTelemetrySettings(), false)
 	assert.Error(t, err)
 	assert.Nil(t, metrics)
 }
 
 // newTestMetrics builds a new metrics that will cleanup when testing.T completes
-func newTestMetrics(t *testing.T) *opencensusMetrics {
-	m, err := newOpenCensusMetrics(t.Name())
+func newTestMetrics(t *testing.T) *receiverMetrics {
+	m, err := newReceiverMetrics(component.NewIDWithName(componentType, t.Name()), componenttest.NewNopTelemetrySettings(), false)
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		unregisterMetrics(m)
@@ -101,7 +205,7 @@ func newTestMetrics(t *testing.T) *opencensusMetrics {
 }
 
 // unregisterMetrics is used to unregister the metrics for testing purposes
-func unregisterMetrics(metrics *opencensusMetrics) {
+func unregisterMetrics(metrics *receiverMetrics) {
 	view.Unregister(
 		metrics.views.failedReconnections,
 		metrics.views.recoverableUnmarshallingErrors,
@@ -111,5 +215,10 @@ func unregisterMetrics(metrics *opencensusMetrics) {
 		metrics.views.reportedSpans,
 		metrics.views.receiverStatus,
 		metrics.views.needUpgrade,
+		metrics.views.deadMessages,
+		metrics.views.failedDeadMessages,
+		metrics.views.queueDepth,
+		metrics.views.ackLatency,
+		metrics.views.connectionState,
 	)
 }
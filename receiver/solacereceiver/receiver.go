@@ -23,6 +23,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/featuregate"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
@@ -35,7 +36,7 @@ type solaceTracesReceiver struct {
 
 	nextConsumer consumer.Traces
 	settings     component.ReceiverCreateSettings
-	metrics      *opencensusMetrics
+	metrics      *receiverMetrics
 	unmarshaller tracesUnmarshaller
 	// cancel is the function that will cancel the context associated with the main worker loop
 	cancel            context.CancelFunc
@@ -66,7 +67,8 @@ func newTracesReceiver(config *Config, receiverCreateSettings component.Receiver
 		return nil, err
 	}
 
-	metrics, err := newOpenCensusMetrics(config.ID().Name())
+	metrics, err := newReceiverMetrics(config.ID(), receiverCreateSettings.TelemetrySettings,
+		featuregate.GetRegistry().IsEnabled(useOtelForInternalMetricsGateID))
 	if err != nil {
 		receiverCreateSettings.Logger.Warn("Error registering metrics", zap.Any("error", err))
 		return nil, err
@@ -123,13 +125,12 @@ func (s *solaceTracesReceiver) connectAndReceive(ctx context.Context) {
 	}()
 
 	s.settings.Logger.Info("Starting reconnection and consume loop")
-	disable := false
 
 	// indicate we are in connecting state at the start
 	s.metrics.recordReceiverStatus(receiverStateConnecting)
 
 reconnectionLoop:
-	for !disable {
+	for {
 		// check that we are not shutting down prior to the dial attempt
 		select {
 		case <-ctx.Done():
@@ -139,14 +140,7 @@ reconnectionLoop:
 		}
 		// create a new connection within the closure to defer the service.close
 		func() {
-			defer func() {
-				// if the receiver is disabled, record the idle state, otherwise record the connecting state
-				if disable {
-					s.recordConnectionState(receiverStateIdle)
-				} else {
-					s.recordConnectionState(receiverStateConnecting)
-				}
-			}()
+			defer s.recordConnectionState(receiverStateConnecting)
 			service := s.factory()
 			defer service.close(ctx)
 
@@ -160,11 +154,6 @@ reconnectionLoop:
 
 			if err := s.receiveMessages(ctx, service); err != nil {
 				s.settings.Logger.Debug("Encountered error while receiving messages", zap.Error(err))
-				if errors.Is(err, errUnknownTraceMessgeVersion) {
-					s.metrics.recordNeedUpgrade()
-					disable = true
-					return
-				}
 			}
 		}()
 		// sleep will be interrupted if ctx.Done() is closed
@@ -184,6 +173,9 @@ func (s *solaceTracesReceiver) recordConnectionState(state receiverState) {
 
 // receiveMessages will continuously receive, unmarshal and propagate messages
 func (s *solaceTracesReceiver) receiveMessages(ctx context.Context, service messagingService) error {
+	if s.config.Workers.Count > 1 {
+		return s.receiveMessagesWithWorkers(ctx, service)
+	}
 	for {
 		select { // ctx.Done will be closed when we should terminate
 		case <-ctx.Done():
@@ -200,18 +192,26 @@ func (s *solaceTracesReceiver) receiveMessages(ctx context.Context, service mess
 
 // receiveMessage is the heart of the receiver's control flow. It will receive messages, unmarshal the message and forward the trace.
 // Will return an error if a fatal error occurs. It is expected that any error returned will cause a connection close.
-func (s *solaceTracesReceiver) receiveMessage(ctx context.Context, service messagingService) (err error) {
+func (s *solaceTracesReceiver) receiveMessage(ctx context.Context, service messagingService) error {
 	msg, err := service.receiveMessage(ctx)
 	if err != nil {
 		s.settings.Logger.Warn("Failed to receive message from messaging service", zap.Error(err))
 		return err // propagate any receive message error up to caller
 	}
+	return s.processMessage(ctx, service, msg)
+}
+
+// processMessage unmarshals a received message, forwards the trace and settles the message.
+// Will return an error if the message can't be settled, which is fatal to the connection.
+func (s *solaceTracesReceiver) processMessage(ctx context.Context, service messagingService, msg *inboundMessage) (err error) {
 	// only set the disposition action after we have received a message successfully
 	disposition := service.accept
+	start := time.Now()
 	defer func() { // on return of receiveMessage, we want to either ack or nack the message
 		if actionErr := disposition(ctx, msg); err == nil && actionErr != nil {
 			err = actionErr
 		}
+		s.metrics.recordAckLatency(time.Since(start))
 	}()
 	// message received successfully
 	s.metrics.recordReceivedSpanMessages()
@@ -221,8 +221,33 @@ func (s *solaceTracesReceiver) receiveMessage(ctx context.Context, service messa
 		s.settings.Logger.Error("Encountered error while unmarshalling message", zap.Error(unmarshalErr))
 		s.metrics.recordFatalUnmarshallingError()
 		if errors.Is(unmarshalErr, errUnknownTraceMessgeVersion) {
-			disposition = service.failed // if we don't know the version, reject the trace message since we will disable the receiver
-			return unmarshalErr
+			// the message can't be decoded by this version of the receiver, reject it and keep receiving the
+			// other messages rather than redelivering it forever
+			s.settings.Logger.Warn("Received trace message of an unsupported version, rejecting the message, upgrade the receiver to process it")
+			s.metrics.recordNeedUpgrade()
+			s.metrics.recordDroppedSpanMessages()
+			disposition = service.reject
+			return nil
+		}
+		if s.config.DeadMessageQueue != "" {
+			// republish the message to the dead message queue rather than dropping it
+			reason := deadMessageReason(unmarshalErr)
+			if dmqErr := service.deadMessage(ctx, msg, reason, unmarshalErr); dmqErr != nil {
+				s.metrics.recordFailedDeadMessage(reason)
+				if deliveryAttempts(msg) >= maxDeadMessageAttempts {
+					// stop the redeliveries, the message would otherwise be redelivered forever if the
+					// dead message queue stays unavailable
+					s.settings.Logger.Warn("Failed to republish message to the dead message queue, rejecting the message", zap.Error(dmqErr))
+					s.metrics.recordDroppedSpanMessages()
+					disposition = service.reject
+					return nil
+				}
+				s.settings.Logger.Warn("Failed to republish message to the dead message queue, will allow redelivery", zap.Error(dmqErr))
+				disposition = service.failed
+				return nil
+			}
+			s.metrics.recordDeadMessage(reason)
+			return nil
 		}
 		s.metrics.recordDroppedSpanMessages() // if the error is some other unmarshalling error, we will ack the message and drop the content
 		return nil                            // don't propagate error, but don't continue forwarding traces
@@ -244,6 +269,36 @@ func (s *solaceTracesReceiver) receiveMessage(ctx context.Context, service messa
 	return nil
 }
 
+// maxDeadMessageAttempts is the number of deliveries of a message after which the receiver stops trying
+// to republish it to the dead message queue and rejects it.
+const maxDeadMessageAttempts = 3
+
+// deliveryAttempts returns the number of times the message has been delivered, including this delivery.
+func deliveryAttempts(msg *inboundMessage) uint32 {
+	if msg.Header == nil {
+		return 1
+	}
+	return msg.Header.DeliveryCount + 1
+}
+
+const (
+	deadMessageReasonBadMessage    = "bad_message"
+	deadMessageReasonEmptyPayload  = "empty_payload"
+	deadMessageReasonDecodingError = "decoding_error"
+)
+
+// deadMessageReason returns the reason recorded for a message that failed unmarshalling permanently
+func deadMessageReason(err error) string {
+	switch {
+	case errors.Is(err, errUnknownTraceMessgeType):
+		return deadMessageReasonBadMessage
+	case errors.Is(err, errEmptyPayload):
+		return deadMessageReasonEmptyPayload
+	default:
+		return deadMessageReasonDecodingError
+	}
+}
+
 func sleep(ctx context.Context, d time.Duration) {
 	timer := time.NewTimer(d)
 	select {
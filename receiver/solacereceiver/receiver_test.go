@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Azure/go-amqp"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -70,13 +71,6 @@ func TestReceiveMessage(t *testing.T) {
 			unmarshalErr: errUnknownTraceMessgeType,
 			validation:   validateMetrics(1, 1, 1, nil),
 		},
-		{ // unmarshal error with wrong version expecting error to be propagated, message to be rejected
-			name:         "Unmarshal Version Error",
-			unmarshalErr: errUnknownTraceMessgeVersion,
-			expectedErr:  errUnknownTraceMessgeVersion,
-			expectNack:   true,
-			validation:   validateMetrics(1, nil, 1, nil),
-		},
 		{ // expect forward to error and message to be swallowed with ack, no error returned
 			name:         "Forward Permanent Error",
 			nextConsumer: consumertest.NewErr(consumererror.NewPermanent(errors.New("a permanent error"))),
@@ -169,6 +163,111 @@ func TestReceiveMessage(t *testing.T) {
 	}
 }
 
+func TestReceiveMessageDeadMessageQueue(t *testing.T) {
+	someError := errors.New("some error")
+	cases := []struct {
+		name           string
+		unmarshalErr   error
+		deadMessageErr error
+		expectedReason string
+		expectNack     bool
+	}{
+		{
+			name:           "Bad Message",
+			unmarshalErr:   errUnknownTraceMessgeType,
+			expectedReason: deadMessageReasonBadMessage,
+		},
+		{
+			name:           "Decoding Error",
+			unmarshalErr:   someError,
+			expectedReason: deadMessageReasonDecodingError,
+		},
+		{
+			name:           "Dead Message Error",
+			unmarshalErr:   errEmptyPayload,
+			deadMessageErr: someError,
+			expectedReason: deadMessageReasonEmptyPayload,
+			expectNack:     true,
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			receiver, messagingService, unmarshaller := newReceiver(t)
+			receiver.config.DeadMessageQueue = "queue://#dmq"
+
+			msg := &inboundMessage{}
+			var ackCalled, nackCalled, deadMessageCalled bool
+			messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+				return msg, nil
+			}
+			messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+				ackCalled = true
+				return nil
+			}
+			messagingService.nackFunc = func(ctx context.Context, msg *inboundMessage) error {
+				nackCalled = true
+				return nil
+			}
+			messagingService.deadMessageFunc = func(ctx context.Context, deadMsg *inboundMessage, reason string, cause error) error {
+				assert.False(t, deadMessageCalled)
+				deadMessageCalled = true
+				assert.Equal(t, msg, deadMsg)
+				assert.Equal(t, testCase.expectedReason, reason)
+				assert.Equal(t, testCase.unmarshalErr, cause)
+				return testCase.deadMessageErr
+			}
+			unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+				return ptrace.Traces{}, testCase.unmarshalErr
+			}
+
+			err := receiver.receiveMessage(context.Background(), messagingService)
+			assert.NoError(t, err)
+			assert.True(t, deadMessageCalled)
+			assert.Equal(t, testCase.expectNack, nackCalled)
+			assert.Equal(t, !testCase.expectNack, ackCalled)
+			// the message is not dropped since it is either dead lettered or redelivered
+			validateReceiverMetrics(t, receiver, 1, nil, 1, nil)
+			if testCase.deadMessageErr != nil {
+				validateMetric(t, receiver.metrics.views.deadMessages, nil)
+				validateMetric(t, receiver.metrics.views.failedDeadMessages, 1)
+			} else {
+				validateMetric(t, receiver.metrics.views.deadMessages, 1)
+				validateMetric(t, receiver.metrics.views.failedDeadMessages, nil)
+			}
+		})
+	}
+}
+
+func TestReceiveMessageDeadMessageQueueAttemptsExhausted(t *testing.T) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.DeadMessageQueue = "queue://#dmq"
+
+	msg := &inboundMessage{Header: &amqp.MessageHeader{DeliveryCount: maxDeadMessageAttempts - 1}}
+	var rejectCalled bool
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return msg, nil
+	}
+	messagingService.rejectFunc = func(ctx context.Context, rejectedMsg *inboundMessage) error {
+		rejectCalled = true
+		assert.Equal(t, msg, rejectedMsg)
+		return nil
+	}
+	messagingService.deadMessageFunc = func(ctx context.Context, deadMsg *inboundMessage, reason string, cause error) error {
+		return errors.New("some error")
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return ptrace.Traces{}, errEmptyPayload
+	}
+
+	err := receiver.receiveMessage(context.Background(), messagingService)
+	assert.NoError(t, err)
+	assert.True(t, rejectCalled)
+	// the message is dropped since it won't be redelivered
+	validateReceiverMetrics(t, receiver, 1, 1, 1, nil)
+	validateMetric(t, receiver.metrics.views.deadMessages, nil)
+	validateMetric(t, receiver.metrics.views.failedDeadMessages, 1)
+}
+
 // receiveMessages ctx done return
 func TestReceiveMessagesTerminateWithCtxDone(t *testing.T) {
 	receiver, messagingService, unmarshaller := newReceiver(t)
@@ -288,52 +387,59 @@ func TestReceiverDialFailureContinue(t *testing.T) {
 	validateReceiverMetrics(t, receiver, nil, nil, nil, nil)
 }
 
-func TestReceiverUnmarshalVersionFailureExpectingDisable(t *testing.T) {
+func TestReceiverUnmarshalVersionFailureExpectingReject(t *testing.T) {
 	receiver, msgService, unmarshaller := newReceiver(t)
-	dialDone := make(chan struct{})
-	nackCalled := make(chan struct{})
-	closeDone := make(chan struct{})
+	unsupported, supported := &inboundMessage{}, &inboundMessage{}
 	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
-		return ptrace.Traces{}, errUnknownTraceMessgeVersion
+		if msg == unsupported {
+			return ptrace.Traces{}, errUnknownTraceMessgeVersion
+		}
+		return ptrace.NewTraces(), nil
 	}
 	msgService.dialFunc = func() error {
-		// after we receive an unmarshalling version error, we should not call dial again
+		// the receiver keeps the connection after an unsupported version, we should not call dial again
 		msgService.dialFunc = func() error {
 			t.Error("did not expect dial to be called again")
 			return nil
 		}
-		close(dialDone)
 		return nil
 	}
+	received := 0
 	msgService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
-		// we only expect a single receiveMessage call when unmarshal returns unknown version
-		msgService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
-			t.Error("did not expect receiveMessage to be called again")
-			return nil, nil
+		received++
+		switch received {
+		case 1:
+			return unsupported, nil
+		case 2:
+			return supported, nil
 		}
-		return nil, nil
+		<-ctx.Done()
+		return nil, ctx.Err()
 	}
-	msgService.nackFunc = func(ctx context.Context, msg *inboundMessage) error {
-		close(nackCalled)
+	rejectCalled := make(chan struct{})
+	msgService.rejectFunc = func(ctx context.Context, msg *inboundMessage) error {
+		assert.Equal(t, unsupported, msg)
+		close(rejectCalled)
 		return nil
 	}
-	msgService.closeFunc = func(ctx context.Context) {
-		close(closeDone)
+	ackCalled := make(chan struct{})
+	msgService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		assert.Equal(t, supported, msg)
+		close(ackCalled)
+		return nil
 	}
+	msgService.closeFunc = func(ctx context.Context) {}
 	// start the receiver
 	err := receiver.Start(context.Background(), nil)
 	assert.NoError(t, err)
 
-	// expect dial to be called twice
-	assertChannelClosed(t, dialDone)
-	// expect nack to be called
-	assertChannelClosed(t, nackCalled)
-	// expect close to be called twice
-	assertChannelClosed(t, closeDone)
-	// we receive 1 message, encounter a fatal unmarshalling error and we nack the message so it is not actually dropped
-	validateReceiverMetrics(t, receiver, 1, nil, 1, nil)
-	// assert idle state
-	validateMetric(t, receiver.metrics.views.receiverStatus, receiverStateIdle)
+	// expect the unsupported message to be rejected and the next message to be processed
+	assertChannelClosed(t, rejectCalled)
+	assertChannelClosed(t, ackCalled)
+	// we receive 2 messages, the unsupported one is dropped and the other one is reported
+	validateReceiverMetrics(t, receiver, 2, 1, 1, 1)
+	validateMetric(t, receiver.metrics.views.needUpgrade, 1)
+	validateMetric(t, receiver.metrics.views.receiverStatus, receiverStateConnected)
 
 	err = receiver.Shutdown(context.Background())
 	assert.NoError(t, err)
@@ -375,6 +481,8 @@ type mockMessagingService struct {
 	receiveMessageFunc func(ctx context.Context) (*inboundMessage, error)
 	ackFunc            func(ctx context.Context, msg *inboundMessage) error
 	nackFunc           func(ctx context.Context, msg *inboundMessage) error
+	rejectFunc         func(ctx context.Context, msg *inboundMessage) error
+	deadMessageFunc    func(ctx context.Context, msg *inboundMessage, reason string, cause error) error
 }
 
 func (m *mockMessagingService) dial() error {
@@ -413,6 +521,20 @@ func (m *mockMessagingService) failed(ctx context.Context, msg *inboundMessage)
 	panic("did not expect nack to be called")
 }
 
+func (m *mockMessagingService) reject(ctx context.Context, msg *inboundMessage) error {
+	if m.rejectFunc != nil {
+		return m.rejectFunc(ctx, msg)
+	}
+	panic("did not expect reject to be called")
+}
+
+func (m *mockMessagingService) deadMessage(ctx context.Context, msg *inboundMessage, reason string, cause error) error {
+	if m.deadMessageFunc != nil {
+		return m.deadMessageFunc(ctx, msg, reason, cause)
+	}
+	panic("did not expect deadMessage to be called")
+}
+
 type mockUnmarshaller struct {
 	unmarshalFunc func(msg *inboundMessage) (ptrace.Traces, error)
 }
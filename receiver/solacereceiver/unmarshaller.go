@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -27,6 +28,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	model_v1 "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/solacereceiver/model/v1"
+	model_v2 "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/solacereceiver/model/v2"
 )
 
 // tracesUnmarshaller deserializes the message body.
@@ -37,23 +39,34 @@ type tracesUnmarshaller interface {
 }
 
 // newUnmarshalleer returns a new unmarshaller ready for message unmarshalling
-func newTracesUnmarshaller(logger *zap.Logger, metrics *opencensusMetrics) tracesUnmarshaller {
+func newTracesUnmarshaller(logger *zap.Logger, metrics *receiverMetrics) tracesUnmarshaller {
 	return &solaceTracesUnmarshaller{
 		logger:  logger,
 		metrics: metrics,
-		// v1 unmarshaller is implemented by solaceMessageUnmarshallerV1
-		v1: &solaceMessageUnmarshallerV1{
-			logger:  logger,
-			metrics: metrics,
+		// the decoder of each supported major version of the trace message format.
+		// A new format version is supported by registering its decoder here.
+		decoders: map[int]tracesUnmarshaller{
+			// v1 unmarshaller is implemented by solaceMessageUnmarshallerV1
+			1: &solaceMessageUnmarshallerV1{
+				logger:  logger,
+				metrics: metrics,
+			},
+			// v2 unmarshaller is implemented by solaceMessageUnmarshallerV2
+			2: &solaceMessageUnmarshallerV2{
+				v1: &solaceMessageUnmarshallerV1{
+					logger:  logger,
+					metrics: metrics,
+				},
+			},
 		},
 	}
 }
 
 // solaceTracesUnmarshaller implements tracesUnmarshaller.
 type solaceTracesUnmarshaller struct {
-	logger  *zap.Logger
-	metrics *opencensusMetrics
-	v1      tracesUnmarshaller
+	logger   *zap.Logger
+	metrics  *receiverMetrics
+	decoders map[int]tracesUnmarshaller
 }
 
 var (
@@ -62,35 +75,61 @@ var (
 	errEmptyPayload              = errors.New("no binary attachment")
 )
 
+// traceTopicPrefix is the prefix of the topic of the trace messages, followed by the
+// major version of the message format: _telemetry/broker/trace/receive/v<major>[/<additional/topic/levels>]
+const traceTopicPrefix = "_telemetry/broker/trace/receive/v"
+
 // unmarshal will unmarshal an *solaceMessage into ptrace.Traces.
-// It will make a decision based on the version of the message which unmarshalling strategy to use.
-// For now, only v1 messages are used.
+// The version of the message format is negotiated for each message from its topic,
+// so that brokers publishing different versions can share the same queue.
 func (u *solaceTracesUnmarshaller) unmarshal(message *inboundMessage) (ptrace.Traces, error) {
-	const (
-		topicPrefix   = "_telemetry/broker/trace/receive/v"
-		topicPrefixV1 = topicPrefix + "1"
-	)
-	if message.Properties != nil && message.Properties.To != nil {
-		if strings.HasPrefix(*message.Properties.To, topicPrefixV1) {
-			return u.v1.unmarshal(message)
-		}
-		if strings.HasPrefix(*message.Properties.To, topicPrefix) {
-			// unknown version
-			u.logger.Error("Received message with unsupported version topic", zap.String("topic", *message.Properties.To))
-			return ptrace.Traces{}, errUnknownTraceMessgeVersion
-		}
+	if message.Properties == nil || message.Properties.To == nil {
+		// no topic
+		u.logger.Error("Received message with no topic")
+		return ptrace.Traces{}, errUnknownTraceMessgeType
+	}
+	topic := *message.Properties.To
+	if !strings.HasPrefix(topic, traceTopicPrefix) {
 		// unknown topic
-		u.logger.Error("Received message with unknown topic", zap.String("topic", *message.Properties.To))
+		u.logger.Error("Received message with unknown topic", zap.String("topic", topic))
 		return ptrace.Traces{}, errUnknownTraceMessgeType
 	}
-	// no topic
-	u.logger.Error("Received message with no topic")
-	return ptrace.Traces{}, errUnknownTraceMessgeType
+	version, ok := traceMessageVersion(topic)
+	if !ok {
+		// the version is not a number, it can't be one of the supported versions
+		u.logger.Error("Received message with unsupported version topic", zap.String("topic", topic))
+		return ptrace.Traces{}, errUnknownTraceMessgeVersion
+	}
+	decoder, ok := u.decoders[version]
+	if !ok {
+		// unknown version
+		u.logger.Error("Received message with unsupported version topic", zap.String("topic", topic), zap.Int("version", version))
+		return ptrace.Traces{}, errUnknownTraceMessgeVersion
+	}
+	return decoder.unmarshal(message)
+}
+
+// traceMessageVersion returns the major version of the message format from the topic of a trace message.
+// The version level may be followed by additional topic levels, and by a minor version which is ignored
+// since all the minor versions of a major version are compatible with each other.
+func traceMessageVersion(topic string) (int, bool) {
+	level := strings.TrimPrefix(topic, traceTopicPrefix)
+	if i := strings.IndexByte(level, '/'); i >= 0 {
+		level = level[:i]
+	}
+	if i := strings.IndexByte(level, '.'); i >= 0 {
+		level = level[:i]
+	}
+	version, err := strconv.Atoi(level)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
 }
 
 type solaceMessageUnmarshallerV1 struct {
 	logger  *zap.Logger
-	metrics *opencensusMetrics
+	metrics *receiverMetrics
 }
 
 // unmarshal implements tracesUnmarshaller.unmarshal
@@ -480,3 +519,93 @@ func (u *solaceMessageUnmarshallerV1) insertUserProperty(toMap pcommon.Map, key
 		u.metrics.recordRecoverableUnmarshallingError()
 	}
 }
+
+// solaceMessageUnmarshallerV2 unmarshals the v2 trace messages. The v2 format describes the same receive span
+// as the v1 format, except for the destination of the received message which is either a topic or a queue.
+// The span data is mapped by the v1 unmarshaller, then the destination is mapped.
+type solaceMessageUnmarshallerV2 struct {
+	v1 *solaceMessageUnmarshallerV1
+}
+
+// unmarshal implements tracesUnmarshaller.unmarshal
+func (u *solaceMessageUnmarshallerV2) unmarshal(message *inboundMessage) (ptrace.Traces, error) {
+	var data = message.GetData()
+	if len(data) == 0 {
+		return ptrace.Traces{}, errEmptyPayload
+	}
+	var spanData model_v2.SpanData
+	if err := proto.Unmarshal(data, &spanData); err != nil {
+		return ptrace.Traces{}, err
+	}
+	traces := ptrace.NewTraces()
+	u.v1.populateTraces(spanDataToV1(&spanData), traces)
+	u.mapDestination(&spanData, traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0))
+	return traces, nil
+}
+
+// mapDestination maps the destination of the received message, the span name and the destination
+// attributes depend on whether the message was published to a topic or to a queue.
+func (u *solaceMessageUnmarshallerV2) mapDestination(spanData *model_v2.SpanData, clientSpan ptrace.Span) {
+	const (
+		queueClientSpanName    = "(queue) receive"
+		destinationAttrKey     = "messaging.destination"
+		destinationKindAttrKey = "messaging.destination_kind"
+		topicKind              = "topic"
+		queueKind              = "queue"
+	)
+	switch destination := spanData.Destination.(type) {
+	case *model_v2.SpanData_Topic:
+		clientSpan.Attributes().PutStr(destinationKindAttrKey, topicKind)
+	case *model_v2.SpanData_QueueName:
+		clientSpan.SetName(queueClientSpanName)
+		clientSpan.Attributes().PutStr(destinationAttrKey, destination.QueueName)
+		clientSpan.Attributes().PutStr(destinationKindAttrKey, queueKind)
+	default:
+		u.v1.logger.Warn(fmt.Sprintf("Unknown destination type %T", destination))
+		u.v1.metrics.recordRecoverableUnmarshallingError()
+	}
+}
+
+// spanDataToV1 converts the v2 span data to the v1 span data, the topic of the v1 span data is only set
+// when the message was published to a topic. The nested messages are shared by both versions.
+func spanDataToV1(spanData *model_v2.SpanData) *model_v1.SpanData {
+	return &model_v1.SpanData{
+		TraceId:                             spanData.TraceId,
+		SpanId:                              spanData.SpanId,
+		ParentSpanId:                        spanData.ParentSpanId,
+		TraceState:                          spanData.TraceState,
+		StartTimeUnixNano:                   spanData.StartTimeUnixNano,
+		EndTimeUnixNano:                     spanData.EndTimeUnixNano,
+		BrokerReceiveTimeUnixNano:           spanData.BrokerReceiveTimeUnixNano,
+		Topic:                               spanData.GetTopic(),
+		ReplyToTopic:                        spanData.ReplyToTopic,
+		DeliveryMode:                        spanData.DeliveryMode,
+		RouterName:                          spanData.RouterName,
+		MessageVpnName:                      spanData.MessageVpnName,
+		SolosVersion:                        spanData.SolosVersion,
+		ClientName:                          spanData.ClientName,
+		ClientUsername:                      spanData.ClientUsername,
+		HostIp:                              spanData.HostIp,
+		HostPort:                            spanData.HostPort,
+		PeerIp:                              spanData.PeerIp,
+		PeerPort:                            spanData.PeerPort,
+		ReplicationGroupMessageId:           spanData.ReplicationGroupMessageId,
+		Protocol:                            spanData.Protocol,
+		ProtocolVersion:                     spanData.ProtocolVersion,
+		DmqEligible:                         spanData.DmqEligible,
+		Priority:                            spanData.Priority,
+		Ttl:                                 spanData.Ttl,
+		BinaryAttachmentSize:                spanData.BinaryAttachmentSize,
+		XmlAttachmentSize:                   spanData.XmlAttachmentSize,
+		MetadataSize:                        spanData.MetadataSize,
+		ApplicationMessageId:                spanData.ApplicationMessageId,
+		CorrelationId:                       spanData.CorrelationId,
+		UserProperties:                      spanData.UserProperties,
+		DroppedApplicationMessageProperties: spanData.DroppedApplicationMessageProperties,
+		ErrorDescription:                    spanData.ErrorDescription,
+		TransactionEvent:                    spanData.TransactionEvent,
+		EnqueueEvents:                       spanData.EnqueueEvents,
+		DroppedEnqueueEventsSuccess:         spanData.DroppedEnqueueEventsSuccess,
+		DroppedEnqueueEventsFailed:          spanData.DroppedEnqueueEventsFailed,
+	}
+}
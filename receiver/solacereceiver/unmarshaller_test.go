@@ -28,12 +28,13 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	model_v1 "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/solacereceiver/model/v1"
+	model_v2 "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/solacereceiver/model/v2"
 )
 
 // Validate entire unmarshal flow
 func TestSolaceMessageUnmarshallerUnmarshal(t *testing.T) {
 	validTopicVersion := "_telemetry/broker/trace/receive/v1"
-	invalidTopicVersion := "_telemetry/broker/trace/receive/v2"
+	invalidTopicVersion := "_telemetry/broker/trace/receive/v3"
 	invalidTopicString := "some unknown topic string that won't be valid"
 
 	tests := []struct {
@@ -277,6 +278,145 @@ func TestSolaceMessageUnmarshallerUnmarshal(t *testing.T) {
 	}
 }
 
+func TestSolaceMessageUnmarshallerVersionNegotiation(t *testing.T) {
+	u := newTracesUnmarshaller(zap.NewNop(), newTestMetrics(t))
+	v2Data, err := proto.Marshal(&model_v2.SpanData{
+		Destination: &model_v2.SpanData_Topic{Topic: "someTopic"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		topic string
+		data  []byte
+		err   error
+	}{
+		{topic: "_telemetry/broker/trace/receive/v2", data: v2Data},
+		{topic: "_telemetry/broker/trace/receive/v2.1/some/level", data: v2Data},
+		{topic: "_telemetry/broker/trace/receive/v2", err: errEmptyPayload},
+		{topic: "_telemetry/broker/trace/receive/v1/some/level", err: errEmptyPayload},
+		{topic: "_telemetry/broker/trace/receive/v3", err: errUnknownTraceMessgeVersion},
+		{topic: "_telemetry/broker/trace/receive/v10", err: errUnknownTraceMessgeVersion},
+		{topic: "_telemetry/broker/trace/receive/vx", err: errUnknownTraceMessgeVersion},
+		{topic: "_telemetry/broker/trace/receive/v", err: errUnknownTraceMessgeVersion},
+		{topic: "_telemetry/broker/trace/receive", err: errUnknownTraceMessgeType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			topic := tt.topic
+			traces, err := u.unmarshal(&inboundMessage{
+				Data:       [][]byte{tt.data},
+				Properties: &amqp.MessageProperties{To: &topic},
+			})
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 1, traces.SpanCount())
+			}
+		})
+	}
+}
+
+func TestSolaceMessageUnmarshallerV2Unmarshal(t *testing.T) {
+	topic := "_telemetry/broker/trace/receive/v2"
+	vpnName := "someVpnName"
+	tests := []struct {
+		name        string
+		destination interface{}
+		wantName    string
+		wantAttrs   map[string]interface{}
+	}{
+		{
+			name:        "Topic Destination",
+			destination: &model_v2.SpanData_Topic{Topic: "someTopic"},
+			wantName:    "(topic) receive",
+			wantAttrs: map[string]interface{}{
+				"messaging.destination":      "someTopic",
+				"messaging.destination_kind": "topic",
+			},
+		},
+		{
+			name:        "Queue Destination",
+			destination: &model_v2.SpanData_QueueName{QueueName: "someQueue"},
+			wantName:    "(queue) receive",
+			wantAttrs: map[string]interface{}{
+				"messaging.destination":      "someQueue",
+				"messaging.destination_kind": "queue",
+			},
+		},
+		{
+			name:     "No Destination",
+			wantName: "(topic) receive",
+			wantAttrs: map[string]interface{}{
+				"messaging.destination": "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spanData := &model_v2.SpanData{
+				TraceId:        []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+				SpanId:         []byte{7, 6, 5, 4, 3, 2, 1, 0},
+				RouterName:     "someRouterName",
+				MessageVpnName: &vpnName,
+				SolosVersion:   "10.0.0",
+				ClientName:     "someClient1234",
+				DeliveryMode:   model_v1.SpanData_PERSISTENT,
+				HostIp:         []byte{1, 2, 3, 4},
+				PeerIp:         []byte{35, 69, 4, 37, 44, 161, 0, 0, 0, 0, 5, 103, 86, 115, 35, 181},
+				EnqueueEvents: []*model_v1.SpanData_EnqueueEvent{
+					{
+						Dest:         &model_v1.SpanData_EnqueueEvent_QueueName{QueueName: "someQueue"},
+						TimeUnixNano: 123456789,
+					},
+				},
+			}
+			switch destination := tt.destination.(type) {
+			case *model_v2.SpanData_Topic:
+				spanData.Destination = destination
+			case *model_v2.SpanData_QueueName:
+				spanData.Destination = destination
+			}
+			data, err := proto.Marshal(spanData)
+			require.NoError(t, err)
+
+			metrics := newTestMetrics(t)
+			u := newTracesUnmarshaller(zap.NewNop(), metrics)
+			traces, err := u.unmarshal(&inboundMessage{
+				Data:       [][]byte{data},
+				Properties: &amqp.MessageProperties{To: &topic},
+			})
+			require.NoError(t, err)
+			require.Equal(t, 1, traces.SpanCount())
+
+			resource := traces.ResourceSpans().At(0).Resource()
+			assert.Equal(t, map[string]interface{}{
+				"service.name":        "someRouterName",
+				"service.instance.id": vpnName,
+				"service.version":     "10.0.0",
+			}, resource.Attributes().AsRaw())
+			span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			assert.Equal(t, tt.wantName, span.Name())
+			assert.Equal(t, pcommon.TraceID([16]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}), span.TraceID())
+			assert.Equal(t, pcommon.SpanID([8]byte{7, 6, 5, 4, 3, 2, 1, 0}), span.SpanID())
+			attrs := span.Attributes().AsRaw()
+			assert.Equal(t, "someClient1234", attrs["messaging.solace.client_name"])
+			assert.Equal(t, "persistent", attrs["messaging.solace.delivery_mode"])
+			for key, value := range tt.wantAttrs {
+				assert.Equal(t, value, attrs[key], key)
+			}
+			if _, ok := tt.wantAttrs["messaging.destination_kind"]; !ok {
+				assert.NotContains(t, attrs, "messaging.destination_kind")
+				validateMetric(t, metrics.views.recoverableUnmarshallingErrors, 1)
+			} else {
+				validateMetric(t, metrics.views.recoverableUnmarshallingErrors, nil)
+			}
+			require.Equal(t, 1, span.Events().Len())
+			assert.Equal(t, "someQueue enqueue", span.Events().At(0).Name())
+		})
+	}
+}
+
 func TestUnmarshallerMapResourceSpan(t *testing.T) {
 	var (
 		routerName = "someRouterName"
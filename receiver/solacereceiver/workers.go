@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solacereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/solacereceiver"
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// receiveMessagesWithWorkers continuously receives messages and hands them over to the workers, which
+// unmarshal the messages, forward the traces and settle the messages concurrently. Any error encountered
+// while receiving or settling a message is returned once the workers processed the messages already
+// handed over to them.
+func (s *solaceTracesReceiver) receiveMessagesWithWorkers(ctx context.Context, service messagingService) error {
+	workers := s.config.Workers
+	// the workers share a single queue, unless the messages of a topic must be processed in order
+	queues := make([]chan *inboundMessage, 1)
+	if workers.Ordering == orderingTopic {
+		queues = make([]chan *inboundMessage, workers.Count)
+	}
+	for i := range queues {
+		queues[i] = make(chan *inboundMessage, workers.QueueSize)
+	}
+
+	// the receive loop is interrupted as soon as a worker fails to settle a message
+	receiveCtx, cancelReceive := context.WithCancel(ctx)
+	defer cancelReceive()
+	var (
+		wg        sync.WaitGroup
+		errOnce   sync.Once
+		workerErr error
+	)
+	wg.Add(workers.Count)
+	for i := 0; i < workers.Count; i++ {
+		go func(queue <-chan *inboundMessage) {
+			defer wg.Done()
+			for msg := range queue {
+				s.metrics.recordQueueDepth(-1)
+				if err := s.processMessage(ctx, service, msg); err != nil {
+					errOnce.Do(func() {
+						workerErr = err
+						cancelReceive()
+					})
+				}
+			}
+		}(queues[i%len(queues)])
+	}
+
+	receiveErr := s.dispatchMessages(receiveCtx, service, queues)
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+	if receiveErr != nil {
+		return receiveErr
+	}
+	return workerErr
+}
+
+// dispatchMessages receives the messages and queues them for the workers until ctx is done or
+// a message can't be received. A message received while ctx is done isn't settled, and is
+// redelivered once the connection is closed.
+func (s *solaceTracesReceiver) dispatchMessages(ctx context.Context, service messagingService, queues []chan *inboundMessage) error {
+	for {
+		select { // ctx.Done will be closed when we should terminate
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		msg, err := service.receiveMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			s.settings.Logger.Warn("Failed to receive message from messaging service", zap.Error(err))
+			return err
+		}
+		// the message is counted before it is queued for the count to never be negative
+		s.metrics.recordQueueDepth(1)
+		select {
+		case queues[queueIndex(msg, len(queues))] <- msg:
+		case <-ctx.Done():
+			s.metrics.recordQueueDepth(-1)
+			return nil
+		}
+	}
+}
+
+// queueIndex returns the index of the queue the message is handed over to, the messages of
+// a topic being always handed over to the same queue.
+func queueIndex(msg *inboundMessage, queues int) int {
+	if queues == 1 || msg.Properties == nil || msg.Properties.To == nil {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(*msg.Properties.To))
+	return int(h.Sum32() % uint32(queues))
+}
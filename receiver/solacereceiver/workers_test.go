@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solacereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/solacereceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// newWorkersReceiver returns a receiver with the given workers, whose messaging service delivers
+// the messages then blocks until the context is done.
+func newWorkersReceiver(t *testing.T, workers WorkersConfig, messages []*inboundMessage) (*solaceTracesReceiver, *mockMessagingService, *mockUnmarshaller) {
+	receiver, messagingService, unmarshaller := newReceiver(t)
+	receiver.config.Workers = workers
+	var mu sync.Mutex
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		mu.Lock()
+		if len(messages) > 0 {
+			msg := messages[0]
+			messages = messages[1:]
+			mu.Unlock()
+			return msg, nil
+		}
+		mu.Unlock()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		return ptrace.NewTraces(), nil
+	}
+	return receiver, messagingService, unmarshaller
+}
+
+func newTopicMessage(topic string, seq int) *inboundMessage {
+	return &inboundMessage{
+		Properties:            &amqp.MessageProperties{To: &topic},
+		ApplicationProperties: map[string]interface{}{"seq": seq},
+	}
+}
+
+func TestReceiveMessagesWithWorkersTopicOrdering(t *testing.T) {
+	var messages []*inboundMessage
+	for i := 0; i < 60; i++ {
+		messages = append(messages, newTopicMessage(fmt.Sprintf("%sv1/router%d", traceTopicPrefix, i%3), i))
+	}
+	receiver, messagingService, _ := newWorkersReceiver(t, WorkersConfig{Count: 4, QueueSize: 5, Ordering: orderingTopic}, messages)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	acked := map[string][]int{}
+	total := 0
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+		topic := *msg.Properties.To
+		acked[topic] = append(acked[topic], msg.ApplicationProperties["seq"].(int))
+		if total++; total == len(messages) {
+			cancel()
+		}
+		return nil
+	}
+
+	assert.NoError(t, receiver.receiveMessages(ctx, messagingService))
+	require.Len(t, acked, 3)
+	for topic, seqs := range acked {
+		assert.Len(t, seqs, 20, topic)
+		assert.IsIncreasing(t, seqs, "the messages of %s were processed out of order", topic)
+	}
+	validateReceiverMetrics(t, receiver, 60, nil, nil, 60)
+}
+
+func TestReceiveMessagesWithWorkersConcurrently(t *testing.T) {
+	messages := []*inboundMessage{newTopicMessage("a", 0), newTopicMessage("a", 1)}
+	receiver, messagingService, unmarshaller := newWorkersReceiver(t, WorkersConfig{Count: 2, Ordering: orderingNone}, messages)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	unmarshaller.unmarshalFunc = func(msg *inboundMessage) (ptrace.Traces, error) {
+		started <- struct{}{}
+		<-release
+		return ptrace.NewTraces(), nil
+	}
+	var acks sync.WaitGroup
+	acks.Add(2)
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		acks.Done()
+		return nil
+	}
+
+	done := make(chan error)
+	go func() { done <- receiver.receiveMessages(ctx, messagingService) }()
+	// both messages are unmarshalled at the same time
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the messages were not processed concurrently")
+		}
+	}
+	close(release)
+	acks.Wait()
+	cancel()
+	assert.NoError(t, <-done)
+	validateReceiverMetrics(t, receiver, 2, nil, nil, 2)
+}
+
+func TestReceiveMessagesWithWorkersSettleError(t *testing.T) {
+	someError := errors.New("some error")
+	messages := []*inboundMessage{newTopicMessage("a", 0)}
+	receiver, messagingService, _ := newWorkersReceiver(t, WorkersConfig{Count: 2, Ordering: orderingNone}, messages)
+	messagingService.ackFunc = func(ctx context.Context, msg *inboundMessage) error {
+		return someError
+	}
+
+	// the receive loop is interrupted by the worker failing to settle the message
+	assert.Equal(t, someError, receiver.receiveMessages(context.Background(), messagingService))
+}
+
+func TestReceiveMessagesWithWorkersReceiveError(t *testing.T) {
+	someError := errors.New("some error")
+	receiver, messagingService, _ := newWorkersReceiver(t, WorkersConfig{Count: 2, Ordering: orderingNone}, nil)
+	messagingService.receiveMessageFunc = func(ctx context.Context) (*inboundMessage, error) {
+		return nil, someError
+	}
+
+	assert.Equal(t, someError, receiver.receiveMessages(context.Background(), messagingService))
+	validateReceiverMetrics(t, receiver, nil, nil, nil, nil)
+}
+
+func TestQueueIndex(t *testing.T) {
+	assert.Equal(t, 0, queueIndex(newTopicMessage("a", 0), 1))
+	assert.Equal(t, 0, queueIndex(&inboundMessage{}, 4))
+	for _, topic := range []string{"a", "b", "c"} {
+		index := queueIndex(newTopicMessage(topic, 0), 4)
+		assert.True(t, index >= 0 && index < 4)
+		assert.Equal(t, index, queueIndex(newTopicMessage(topic, 1), 4))
+	}
+}
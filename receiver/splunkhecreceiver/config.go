@@ -15,6 +15,9 @@
 package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
 
 import (
+	"errors"
+	"time"
+
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/confighttp"
 
@@ -33,4 +36,61 @@ type Config struct {
 	RawPath string `mapstructure:"raw_path"`
 	// HecToOtelAttrs creates a mapping from HEC metadata to attributes.
 	HecToOtelAttrs splunk.HecToOtelAttrs `mapstructure:"hec_metadata_to_otel_attrs"`
+	// ResourceAttributeMappings translate the HEC metadata of the events into resource attributes.
+	ResourceAttributeMappings []ResourceAttributeMapping `mapstructure:"resource_attribute_mappings"`
+	// MaxContentLength is the maximum size in bytes of a request body, 0 means no limit.
+	MaxContentLength int64 `mapstructure:"max_content_length"`
+	// MaxConcurrentRequests is the maximum number of requests handled at the same time, 0 means no limit.
+	// Requests above the limit are rejected with a 503 status code.
+	MaxConcurrentRequests int `mapstructure:"max_concurrent_requests"`
+	// Backpressure configures how the receiver responds when the pipeline can't accept more data.
+	Backpressure BackpressureConfig `mapstructure:"backpressure"`
+	// ClientAuth restricts the clients allowed to send data, their certificates being verified
+	// against the tls.client_ca_file.
+	ClientAuth ClientAuthConfig `mapstructure:"client_auth"`
+	// TimestampFixup handles the events with a missing, zero or out of the clock skew window timestamp, which
+	// would otherwise break the retention policies downstream.
+	TimestampFixup TimestampFixupConfig `mapstructure:"timestamp_fixup"`
+}
+
+// ClientAuthConfig defines the client certificates accepted by the receiver.
+type ClientAuthConfig struct {
+	// AllowedNames lists the subject common names and subject alternative names (DNS names, email
+	// addresses, IP addresses and URIs) of the allowed client certificates. A certificate matching
+	// any of them is allowed. Empty allows any certificate verified against the client CA.
+	AllowedNames []string `mapstructure:"allowed_names"`
+}
+
+// BackpressureConfig defines how the pipeline backpressure is reported to the clients.
+type BackpressureConfig struct {
+	// Enabled returns a 503 status code, instead of a 500, when the next consumer fails with a
+	// retryable error, so that the clients throttle and retry the request.
+	Enabled bool `mapstructure:"enabled"`
+	// RetryAfter is the delay advertised in the Retry-After header of the 503 responses.
+	RetryAfter time.Duration `mapstructure:"retry_after"`
+}
+
+// Validate checks the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.MaxContentLength < 0 {
+		return errors.New("max_content_length must not be negative")
+	}
+	if cfg.MaxConcurrentRequests < 0 {
+		return errors.New("max_concurrent_requests must not be negative")
+	}
+	if cfg.Backpressure.RetryAfter < 0 {
+		return errors.New("backpressure.retry_after must not be negative")
+	}
+	if len(cfg.ClientAuth.AllowedNames) > 0 && (cfg.TLSSetting == nil || cfg.TLSSetting.ClientCAFile == "") {
+		return errors.New("client_auth.allowed_names requires tls.client_ca_file to be set")
+	}
+	if err := cfg.TimestampFixup.validate(); err != nil {
+		return err
+	}
+	for i := range cfg.ResourceAttributeMappings {
+		if err := cfg.ResourceAttributeMappings[i].validate(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -17,6 +17,7 @@ package splunkhecreceiver
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -60,6 +61,30 @@ func TestLoadConfig(t *testing.T) {
 					Index:      "myindex",
 					Host:       "myhostfield",
 				},
+				MaxContentLength:      1048576,
+				MaxConcurrentRequests: 10,
+				Backpressure: BackpressureConfig{
+					Enabled:    true,
+					RetryAfter: 10 * time.Second,
+				},
+				ResourceAttributeMappings: []ResourceAttributeMapping{
+					{
+						Field:     "index",
+						Attribute: "deployment.environment",
+						Lookup:    map[string]string{"main_prod": "production", "main_dev": "development"},
+					},
+					{
+						Field:       "source",
+						Attribute:   "service.name",
+						Regex:       `^/var/log/(\w+)/`,
+						Replacement: "$1",
+					},
+				},
+				TimestampFixup: TimestampFixupConfig{
+					Action:        replaceTimestampAction,
+					MaxFutureSkew: 5 * time.Minute,
+					MaxPastSkew:   168 * time.Hour,
+				},
 			},
 		},
 		{
@@ -73,6 +98,7 @@ func TestLoadConfig(t *testing.T) {
 							CertFile: "/test.crt",
 							KeyFile:  "/test.key",
 						},
+						ClientCAFile: "/ca.crt",
 					},
 				},
 				AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
@@ -85,6 +111,12 @@ func TestLoadConfig(t *testing.T) {
 					Index:      "com.splunk.index",
 					Host:       "host.name",
 				},
+				Backpressure: BackpressureConfig{
+					RetryAfter: defaultRetryAfter,
+				},
+				ClientAuth: ClientAuthConfig{
+					AllowedNames: []string{"collector.example.com", "spiffe://example.com/agent"},
+				},
 			},
 		},
 	}
@@ -103,3 +135,75 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "negative max_content_length",
+			modify: func(cfg *Config) { cfg.MaxContentLength = -1 },
+			err:    "max_content_length must not be negative",
+		},
+		{
+			name:   "negative max_concurrent_requests",
+			modify: func(cfg *Config) { cfg.MaxConcurrentRequests = -1 },
+			err:    "max_concurrent_requests must not be negative",
+		},
+		{
+			name:   "negative retry_after",
+			modify: func(cfg *Config) { cfg.Backpressure.RetryAfter = -time.Second },
+			err:    "backpressure.retry_after must not be negative",
+		},
+		{
+			name: "unsupported mapping field",
+			modify: func(cfg *Config) {
+				cfg.ResourceAttributeMappings = []ResourceAttributeMapping{{Field: "time", Attribute: "service.name", Regex: ".*"}}
+			},
+			err: `unsupported field "time", must be one of host, source, sourcetype or index`,
+		},
+		{
+			name: "mapping without lookup or regex",
+			modify: func(cfg *Config) {
+				cfg.ResourceAttributeMappings = []ResourceAttributeMapping{{Field: "index", Attribute: "deployment.environment"}}
+			},
+			err: "the index mapping to deployment.environment requires a lookup table or a regex",
+		},
+		{
+			name: "invalid mapping regex",
+			modify: func(cfg *Config) {
+				cfg.ResourceAttributeMappings = []ResourceAttributeMapping{{Field: "source", Attribute: "service.name", Regex: "("}}
+			},
+			err: "invalid regex of the source mapping to service.name: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:   "unsupported timestamp action",
+			modify: func(cfg *Config) { cfg.TimestampFixup.Action = "fix" },
+			err:    `unsupported timestamp_fixup.action "fix", must be one of keep, replace, drop or annotate`,
+		},
+		{
+			name:   "negative max_future_skew",
+			modify: func(cfg *Config) { cfg.TimestampFixup.MaxFutureSkew = -time.Second },
+			err:    "timestamp_fixup.max_future_skew must not be negative",
+		},
+		{
+			name:   "negative max_past_skew",
+			modify: func(cfg *Config) { cfg.TimestampFixup.MaxPastSkew = -time.Second },
+			err:    "timestamp_fixup.max_past_skew must not be negative",
+		},
+		{
+			name:   "allowed names without client CA",
+			modify: func(cfg *Config) { cfg.ClientAuth.AllowedNames = []string{"collector.example.com"} },
+			err:    "client_auth.allowed_names requires tls.client_ca_file to be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
@@ -16,6 +16,7 @@ package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-col
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -37,6 +38,9 @@ const (
 
 	// Default endpoints to bind to.
 	defaultEndpoint = ":8088"
+
+	// Default delay advertised to the clients when the receiver applies backpressure.
+	defaultRetryAfter = 30 * time.Second
 )
 
 // NewFactory creates a factory for Splunk HEC receiver.
@@ -63,6 +67,9 @@ func createDefaultConfig() component.ReceiverConfig {
 			Host:       conventions.AttributeHostName,
 		},
 		RawPath: splunk.DefaultRawPath,
+		Backpressure: BackpressureConfig{
+			RetryAfter: defaultRetryAfter,
+		},
 	}
 }
 
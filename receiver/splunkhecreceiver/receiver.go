@@ -18,11 +18,14 @@ import (
 	"bufio"
 	"compress/gzip"
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +34,7 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -50,10 +54,14 @@ const (
 	responseErrInternalServerError    = "Internal Server Error"
 	responseErrUnsupportedMetricEvent = "Unsupported metric event"
 	responseErrUnsupportedLogEvent    = "Unsupported log event"
+	responseErrContentTooLarge        = "Content length is too large"
+	responseErrServerBusy             = "Server is busy"
+	responseErrClientNotAllowed       = "Client certificate is not allowed"
 
 	// Centralizing some HTTP and related string constants.
 	gzipEncoding              = "gzip"
 	httpContentEncodingHeader = "Content-Encoding"
+	httpRetryAfterHeader      = "Retry-After"
 )
 
 var (
@@ -62,6 +70,9 @@ var (
 	errEmptyEndpoint          = errors.New("empty endpoint")
 	errInvalidMethod          = errors.New("invalid http method")
 	errInvalidEncoding        = errors.New("invalid encoding")
+	errContentTooLarge        = errors.New("content length too large")
+	errServerBusy             = errors.New("too many concurrent requests")
+	errClientNotAllowed       = errors.New("client certificate not allowed")
 
 	okRespBody                = initJSONResponse(responseOK)
 	invalidMethodRespBody     = initJSONResponse(responseInvalidMethod)
@@ -71,6 +82,9 @@ var (
 	errInternalServerError    = initJSONResponse(responseErrInternalServerError)
 	errUnsupportedMetricEvent = initJSONResponse(responseErrUnsupportedMetricEvent)
 	errUnsupportedLogEvent    = initJSONResponse(responseErrUnsupportedLogEvent)
+	errTooLargeRespBody       = initJSONResponse(responseErrContentTooLarge)
+	errServerBusyRespBody     = initJSONResponse(responseErrServerBusy)
+	errClientNotAllowedBody   = initJSONResponse(responseErrClientNotAllowed)
 )
 
 // splunkReceiver implements the component.MetricsReceiver for Splunk HEC metric protocol.
@@ -83,6 +97,12 @@ type splunkReceiver struct {
 	shutdownWG      sync.WaitGroup
 	obsrecv         *obsreport.Receiver
 	gzipReaderPool  *sync.Pool
+	// inflight limits the number of requests handled concurrently, nil if there is no limit.
+	inflight chan struct{}
+	// resourceMapper maps the HEC metadata to resource attributes, nil if there is no mapping.
+	resourceMapper *resourceMapper
+	// allowedClientNames are the names of the allowed client certificates, nil if any client is allowed.
+	allowedClientNames map[string]struct{}
 }
 
 var _ component.MetricsReceiver = (*splunkReceiver)(nil)
@@ -106,6 +126,11 @@ func newMetricsReceiver(
 		transport = "https"
 	}
 
+	resourceMapper, err := newResourceMapper(config.ResourceAttributeMappings)
+	if err != nil {
+		return nil, err
+	}
+
 	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
 		ReceiverID:             config.ID(),
 		Transport:              transport,
@@ -125,8 +150,11 @@ func newMetricsReceiver(
 			ReadHeaderTimeout: defaultServerTimeout,
 			WriteTimeout:      defaultServerTimeout,
 		},
-		obsrecv:        obsrecv,
-		gzipReaderPool: &sync.Pool{New: func() interface{} { return new(gzip.Reader) }},
+		obsrecv:            obsrecv,
+		gzipReaderPool:     &sync.Pool{New: func() interface{} { return new(gzip.Reader) }},
+		inflight:           newInflightLimiter(config.MaxConcurrentRequests),
+		resourceMapper:     resourceMapper,
+		allowedClientNames: newAllowedClientNames(config.ClientAuth.AllowedNames),
 	}
 
 	return r, nil
@@ -150,6 +178,11 @@ func newLogsReceiver(
 		transport = "https"
 	}
 
+	resourceMapper, err := newResourceMapper(config.ResourceAttributeMappings)
+	if err != nil {
+		return nil, err
+	}
+
 	obsrecv, err := obsreport.NewReceiver(obsreport.ReceiverSettings{
 		ReceiverID:             config.ID(),
 		Transport:              transport,
@@ -170,8 +203,11 @@ func newLogsReceiver(
 			ReadHeaderTimeout: defaultServerTimeout,
 			WriteTimeout:      defaultServerTimeout,
 		},
-		gzipReaderPool: &sync.Pool{New: func() interface{} { return new(gzip.Reader) }},
-		obsrecv:        obsrecv,
+		gzipReaderPool:     &sync.Pool{New: func() interface{} { return new(gzip.Reader) }},
+		obsrecv:            obsrecv,
+		inflight:           newInflightLimiter(config.MaxConcurrentRequests),
+		resourceMapper:     resourceMapper,
+		allowedClientNames: newAllowedClientNames(config.ClientAuth.AllowedNames),
 	}
 
 	return r, nil
@@ -199,7 +235,7 @@ func (r *splunkReceiver) Start(_ context.Context, host component.Host) error {
 	}
 	mx.NewRoute().HandlerFunc(r.handleReq)
 
-	r.server, err = r.config.HTTPServerSettings.ToServer(host, r.settings.TelemetrySettings, mx)
+	r.server, err = r.config.HTTPServerSettings.ToServer(host, r.settings.TelemetrySettings, r.authorizeClient(r.limitConcurrency(mx)))
 	if err != nil {
 		return err
 	}
@@ -248,10 +284,16 @@ func (r *splunkReceiver) handleRawReq(resp http.ResponseWriter, req *http.Reques
 		return
 	}
 
-	bodyReader := req.Body
+	if r.contentTooLarge(req) {
+		r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, errTooLargeRespBody, 0, errContentTooLarge)
+		return
+	}
+	limitedBody := r.limitContentLength(req.Body)
+
+	bodyReader := limitedBody
 	if encoding == gzipEncoding {
 		reader := r.gzipReaderPool.Get().(*gzip.Reader)
-		err := reader.Reset(bodyReader)
+		err := reader.Reset(limitedBody)
 
 		if err != nil {
 			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, 0, err)
@@ -259,7 +301,8 @@ func (r *splunkReceiver) handleRawReq(resp http.ResponseWriter, req *http.Reques
 			_ = req.Body.Close()
 			return
 		}
-		bodyReader = reader
+		// the decompressed body is limited as well, a small compressed body can inflate a lot
+		bodyReader = r.limitContentLength(reader)
 		defer r.gzipReaderPool.Put(reader)
 	}
 
@@ -278,12 +321,17 @@ func (r *splunkReceiver) handleRawReq(resp http.ResponseWriter, req *http.Reques
 		logLine := sc.Text()
 		logRecord.Body().SetStr(logLine)
 	}
+	if limitedBody.exceeded || bodyReader.exceeded {
+		_ = bodyReader.Close()
+		r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, errTooLargeRespBody, 0, errContentTooLarge)
+		return
+	}
 	consumerErr := r.logsConsumer.ConsumeLogs(ctx, ld)
 
 	_ = bodyReader.Close()
 
 	if consumerErr != nil {
-		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, sl.LogRecords().Len(), consumerErr)
+		r.failConsumerRequest(ctx, resp, sl.LogRecords().Len(), consumerErr)
 	} else {
 		resp.WriteHeader(http.StatusOK)
 		r.obsrecv.EndLogsOp(ctx, typeStr, sl.LogRecords().Len(), nil)
@@ -309,15 +357,22 @@ func (r *splunkReceiver) handleReq(resp http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	bodyReader := req.Body
+	if r.contentTooLarge(req) {
+		r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, errTooLargeRespBody, 0, errContentTooLarge)
+		return
+	}
+	limitedBody := r.limitContentLength(req.Body)
+
+	bodyReader := limitedBody
 	if encoding == gzipEncoding {
 		reader := r.gzipReaderPool.Get().(*gzip.Reader)
-		err := reader.Reset(bodyReader)
+		err := reader.Reset(limitedBody)
 		if err != nil {
 			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, 0, err)
 			return
 		}
-		bodyReader = reader
+		// the decompressed body is limited as well, a small compressed body can inflate a lot
+		bodyReader = r.limitContentLength(reader)
 		defer r.gzipReaderPool.Put(reader)
 	}
 
@@ -336,6 +391,10 @@ func (r *splunkReceiver) handleReq(resp http.ResponseWriter, req *http.Request)
 		var msg splunk.Event
 		err := dec.Decode(&msg)
 		if err != nil {
+			if limitedBody.exceeded || bodyReader.exceeded {
+				r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, errTooLargeRespBody, len(events), errContentTooLarge)
+				return
+			}
 			r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, len(events), err)
 			return
 		}
@@ -351,6 +410,26 @@ func (r *splunkReceiver) handleReq(resp http.ResponseWriter, req *http.Request)
 
 		events = append(events, &msg)
 	}
+	// dec.More reports no more events when the limit is reached between two events
+	if limitedBody.exceeded || bodyReader.exceeded {
+		r.failRequest(ctx, resp, http.StatusRequestEntityTooLarge, errTooLargeRespBody, len(events), errContentTooLarge)
+		return
+	}
+	events, dropped := r.config.TimestampFixup.fixTimestamps(events, time.Now())
+	if dropped > 0 {
+		r.settings.Logger.Debug("Dropped the events with an invalid timestamp", zap.Int("dropped", dropped))
+	}
+	if len(events) == 0 {
+		if r.logsConsumer == nil {
+			r.obsrecv.EndMetricsOp(ctx, typeStr, 0, nil)
+		} else {
+			r.obsrecv.EndLogsOp(ctx, typeStr, 0, nil)
+		}
+		if _, err := resp.Write(okRespBody); err != nil {
+			r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, 0, err)
+		}
+		return
+	}
 	if r.logsConsumer != nil {
 		r.consumeLogs(ctx, events, resp, req)
 	} else {
@@ -360,13 +439,13 @@ func (r *splunkReceiver) handleReq(resp http.ResponseWriter, req *http.Request)
 
 func (r *splunkReceiver) consumeMetrics(ctx context.Context, events []*splunk.Event, resp http.ResponseWriter, req *http.Request) {
 	resourceCustomizer := r.createResourceCustomizer(req)
-	md, _ := splunkHecToMetricsData(r.settings.Logger, events, resourceCustomizer, r.config)
+	md, _ := splunkHecToMetricsData(r.settings.Logger, events, resourceCustomizer, r.resourceMapper, r.config)
 
 	decodeErr := r.metricsConsumer.ConsumeMetrics(ctx, md)
 	r.obsrecv.EndMetricsOp(ctx, typeStr, len(events), decodeErr)
 
 	if decodeErr != nil {
-		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, len(events), decodeErr)
+		r.failConsumerRequest(ctx, resp, len(events), decodeErr)
 	} else {
 		resp.WriteHeader(http.StatusOK)
 		_, err := resp.Write(okRespBody)
@@ -378,7 +457,7 @@ func (r *splunkReceiver) consumeMetrics(ctx context.Context, events []*splunk.Ev
 
 func (r *splunkReceiver) consumeLogs(ctx context.Context, events []*splunk.Event, resp http.ResponseWriter, req *http.Request) {
 	resourceCustomizer := r.createResourceCustomizer(req)
-	ld, err := splunkHecToLogData(r.settings.Logger, events, resourceCustomizer, r.config)
+	ld, err := splunkHecToLogData(r.settings.Logger, events, resourceCustomizer, r.resourceMapper, r.config)
 	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, len(events), err)
 		return
@@ -387,7 +466,7 @@ func (r *splunkReceiver) consumeLogs(ctx context.Context, events []*splunk.Event
 	decodeErr := r.logsConsumer.ConsumeLogs(ctx, ld)
 	r.obsrecv.EndLogsOp(ctx, typeStr, len(events), decodeErr)
 	if decodeErr != nil {
-		r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, len(events), decodeErr)
+		r.failConsumerRequest(ctx, resp, len(events), decodeErr)
 	} else {
 		resp.WriteHeader(http.StatusOK)
 		if _, err := resp.Write(okRespBody); err != nil {
@@ -409,6 +488,143 @@ func (r *splunkReceiver) createResourceCustomizer(req *http.Request) func(resour
 	return nil
 }
 
+// failConsumerRequest fails a request the next consumer returned an error for. If backpressure is enabled
+// and the error is retryable, the client is asked to retry later rather than being sent an internal error.
+func (r *splunkReceiver) failConsumerRequest(ctx context.Context, resp http.ResponseWriter, numRecordsReceived int, err error) {
+	if r.config.Backpressure.Enabled && !consumererror.IsPermanent(err) {
+		r.setRetryAfter(resp)
+		r.failRequest(ctx, resp, http.StatusServiceUnavailable, errServerBusyRespBody, numRecordsReceived, err)
+		return
+	}
+	r.failRequest(ctx, resp, http.StatusInternalServerError, errInternalServerError, numRecordsReceived, err)
+}
+
+// setRetryAfter sets the Retry-After header to the configured delay, it must be called before writing the status code.
+func (r *splunkReceiver) setRetryAfter(resp http.ResponseWriter) {
+	if retryAfter := r.config.Backpressure.RetryAfter; retryAfter > 0 {
+		resp.Header().Set(httpRetryAfterHeader, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+}
+
+// limitConcurrency rejects the requests received while MaxConcurrentRequests requests are already being handled.
+func (r *splunkReceiver) limitConcurrency(next http.Handler) http.Handler {
+	if r.inflight == nil {
+		return next
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		select {
+		case r.inflight <- struct{}{}:
+			defer func() { <-r.inflight }()
+			next.ServeHTTP(resp, req)
+		default:
+			r.setRetryAfter(resp)
+			resp.Header().Set("Content-Type", "application/json")
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			if _, err := resp.Write(errServerBusyRespBody); err != nil {
+				r.settings.Logger.Warn("Error writing HTTP response message", zap.Error(err))
+			}
+			r.settings.Logger.Debug("Splunk HEC receiver request rejected", zap.Error(errServerBusy))
+		}
+	})
+}
+
+// authorizeClient rejects the requests whose client certificate has none of the allowed names.
+func (r *splunkReceiver) authorizeClient(next http.Handler) http.Handler {
+	if r.allowedClientNames == nil {
+		return next
+	}
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 && r.clientAllowed(req.TLS.PeerCertificates[0]) {
+			next.ServeHTTP(resp, req)
+			return
+		}
+		resp.Header().Set("Content-Type", "application/json")
+		resp.WriteHeader(http.StatusForbidden)
+		if _, err := resp.Write(errClientNotAllowedBody); err != nil {
+			r.settings.Logger.Warn("Error writing HTTP response message", zap.Error(err))
+		}
+		r.settings.Logger.Debug("Splunk HEC receiver request rejected", zap.String("remote_addr", req.RemoteAddr), zap.Error(errClientNotAllowed))
+	})
+}
+
+// clientAllowed returns true if the subject common name or any subject alternative name of the
+// certificate is allowed.
+func (r *splunkReceiver) clientAllowed(cert *x509.Certificate) bool {
+	names := []string{cert.Subject.CommonName}
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	for _, name := range names {
+		if _, ok := r.allowedClientNames[name]; ok && name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// contentTooLarge returns true if the request announces a body larger than MaxContentLength.
+func (r *splunkReceiver) contentTooLarge(req *http.Request) bool {
+	return r.config.MaxContentLength > 0 && req.ContentLength > r.config.MaxContentLength
+}
+
+// limitContentLength wraps the request body so that it can't be read past MaxContentLength,
+// which is needed for requests without a Content-Length header and for compressed bodies.
+func (r *splunkReceiver) limitContentLength(body io.ReadCloser) *limitedReader {
+	return &limitedReader{ReadCloser: body, remaining: r.config.MaxContentLength, limited: r.config.MaxContentLength > 0}
+}
+
+// limitedReader fails reads past its limit and records that the limit was exceeded.
+type limitedReader struct {
+	io.ReadCloser
+	remaining int64
+	limited   bool
+	exceeded  bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if !l.limited {
+		return l.ReadCloser.Read(p)
+	}
+	if l.remaining <= 0 {
+		// check whether the body ends right at the limit
+		var b [1]byte
+		if n, err := l.ReadCloser.Read(b[:]); n == 0 {
+			return 0, err
+		}
+		l.exceeded = true
+		return 0, errContentTooLarge
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.ReadCloser.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+func newInflightLimiter(maxConcurrentRequests int) chan struct{} {
+	if maxConcurrentRequests <= 0 {
+		return nil
+	}
+	return make(chan struct{}, maxConcurrentRequests)
+}
+
+func newAllowedClientNames(names []string) map[string]struct{} {
+	if len(names) == 0 {
+		return nil
+	}
+	allowed := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		allowed[name] = struct{}{}
+	}
+	return allowed
+}
+
 func (r *splunkReceiver) failRequest(
 	ctx context.Context,
 	resp http.ResponseWriter,
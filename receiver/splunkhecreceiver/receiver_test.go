@@ -35,6 +35,7 @@ import (
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -371,6 +372,186 @@ func Test_consumer_err_metrics(t *testing.T) {
 	assert.Equal(t, "Internal Server Error", bodyStr)
 }
 
+func Test_consumer_err_backpressure(t *testing.T) {
+	currentTime := float64(time.Now().UnixNano()) / 1e6
+	splunkMsg := buildSplunkHecMsg(currentTime, 3)
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.Backpressure.Enabled = true
+	config.Backpressure.RetryAfter = 1500 * time.Millisecond
+
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatus     int
+		expectedBody       string
+		expectedRetryAfter string
+	}{
+		{
+			name:               "retryable_error",
+			err:                errors.New("queue is full"),
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedBody:       responseErrServerBusy,
+			expectedRetryAfter: "2",
+		},
+		{
+			name:           "permanent_error",
+			err:            consumererror.NewPermanent(errors.New("bad data")),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   responseErrInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rcv, err := newLogsReceiver(componenttest.NewNopReceiverCreateSettings(), *config, consumertest.NewErr(tt.err))
+			assert.NoError(t, err)
+
+			r := rcv.(*splunkReceiver)
+			w := httptest.NewRecorder()
+			msgBytes, err := json.Marshal(splunkMsg)
+			require.NoError(t, err)
+			req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(msgBytes))
+			r.handleReq(w, req)
+
+			resp := w.Result()
+			respBytes, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedBody, bodyStr)
+			assert.Equal(t, tt.expectedRetryAfter, resp.Header.Get(httpRetryAfterHeader))
+		})
+	}
+}
+
+func Test_splunkhecReceiver_MaxContentLength(t *testing.T) {
+	currentTime := float64(time.Now().UnixNano()) / 1e6
+	msgBytes, err := json.Marshal(buildSplunkHecMsg(currentTime, 3))
+	require.NoError(t, err)
+
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.MaxContentLength = int64(len(msgBytes)) - 1
+
+	var gzipped bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipped)
+	_, err = gzipWriter.Write(msgBytes)
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+	require.Less(t, gzipped.Len(), len(msgBytes)-1)
+
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{
+			name: "content_length",
+			req:  httptest.NewRequest("POST", "http://localhost", bytes.NewReader(msgBytes)),
+		},
+		{
+			name: "chunked",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(msgBytes))
+				req.ContentLength = -1
+				return req
+			}(),
+		},
+		{
+			name: "decompressed",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(gzipped.Bytes()))
+				req.Header.Set("Content-Encoding", "gzip")
+				return req
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.LogsSink)
+			rcv, err := newLogsReceiver(componenttest.NewNopReceiverCreateSettings(), *config, sink)
+			assert.NoError(t, err)
+
+			r := rcv.(*splunkReceiver)
+			w := httptest.NewRecorder()
+			r.handleReq(w, tt.req)
+
+			resp := w.Result()
+			respBytes, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+			assert.Equal(t, responseErrContentTooLarge, bodyStr)
+			assert.Equal(t, 0, sink.LogRecordCount())
+		})
+	}
+}
+
+func Test_splunkhecReceiver_MaxContentLengthBetweenEvents(t *testing.T) {
+	msgBytes, err := json.Marshal(buildSplunkHecMsg(float64(time.Now().UnixNano())/1e6, 1))
+	require.NoError(t, err)
+
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	// the limit is reached right after the first event
+	config.MaxContentLength = int64(len(msgBytes))
+
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(componenttest.NewNopReceiverCreateSettings(), *config, sink)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(append(msgBytes, msgBytes...)))
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	rcv.(*splunkReceiver).handleReq(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Result().StatusCode)
+	assert.Equal(t, 0, sink.LogRecordCount())
+}
+
+func Test_splunkhecReceiver_MaxConcurrentRequests(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.MaxConcurrentRequests = 1
+
+	rcv, err := newLogsReceiver(componenttest.NewNopReceiverCreateSettings(), *config, consumertest.NewNop())
+	assert.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	blocked := make(chan struct{}, 1)
+	release := make(chan struct{})
+	handler := r.limitConcurrency(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		blocked <- struct{}{}
+		<-release
+		resp.WriteHeader(http.StatusOK)
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "http://localhost", nil))
+	}()
+	<-blocked
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "http://localhost", nil))
+	resp := w.Result()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "30", resp.Header.Get(httpRetryAfterHeader))
+
+	close(release)
+	<-done
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "http://localhost", nil))
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+}
+
 func Test_splunkhecReceiver_TLS(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	cfg := createDefaultConfig().(*Config)
@@ -447,6 +628,114 @@ func Test_splunkhecReceiver_TLS(t *testing.T) {
 	assert.Equal(t, want, got[0])
 }
 
+func Test_splunkhecReceiver_ClientAuth(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowedNames []string
+		wantStatus   int
+	}{
+		{
+			name:         "allowed common name",
+			allowedNames: []string{"MyCommonName"},
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "allowed subject alternative name",
+			allowedNames: []string{"collector.example.com", "localhost"},
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "not allowed",
+			allowedNames: []string{"collector.example.com"},
+			wantStatus:   http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := testutil.GetAvailableLocalAddress(t)
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = addr
+			cfg.TLSSetting = &configtls.TLSServerSetting{
+				TLSSetting: configtls.TLSSetting{
+					CertFile: "./testdata/server.crt",
+					KeyFile:  "./testdata/server.key",
+				},
+				// the client certificate is self-signed
+				ClientCAFile: "./testdata/client.crt",
+			}
+			cfg.ClientAuth.AllowedNames = tt.allowedNames
+			require.NoError(t, cfg.Validate())
+			sink := new(consumertest.LogsSink)
+			r, err := newLogsReceiver(componenttest.NewNopReceiverCreateSettings(), *cfg, sink)
+			require.NoError(t, err)
+			require.NoError(t, r.Start(context.Background(), newAssertNoErrorHost(t)))
+			defer func() {
+				require.NoError(t, r.Shutdown(context.Background()))
+			}()
+
+			body, err := json.Marshal(buildSplunkHecMsg(1, 0))
+			require.NoError(t, err)
+			req, err := http.NewRequest("POST", fmt.Sprintf("https://%s", addr), bytes.NewReader(body))
+			require.NoError(t, err)
+
+			tlscs := configtls.TLSClientSetting{
+				TLSSetting: configtls.TLSSetting{
+					CAFile:   "./testdata/ca.crt",
+					CertFile: "./testdata/client.crt",
+					KeyFile:  "./testdata/client.key",
+				},
+				ServerName: "localhost",
+			}
+			tls, err := tlscs.LoadTLSConfig()
+			require.NoError(t, err)
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: tls}}
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, 1, sink.LogRecordCount())
+				return
+			}
+			respBytes, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			var bodyStr string
+			require.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+			assert.Equal(t, responseErrClientNotAllowed, bodyStr)
+			assert.Equal(t, 0, sink.LogRecordCount())
+		})
+	}
+
+	// the clients without certificate are rejected during the handshake
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = addr
+	cfg.TLSSetting = &configtls.TLSServerSetting{
+		TLSSetting: configtls.TLSSetting{
+			CertFile: "./testdata/server.crt",
+			KeyFile:  "./testdata/server.key",
+		},
+		ClientCAFile: "./testdata/client.crt",
+	}
+	cfg.ClientAuth.AllowedNames = []string{"MyCommonName"}
+	r, err := newLogsReceiver(componenttest.NewNopReceiverCreateSettings(), *cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), newAssertNoErrorHost(t)))
+	defer func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	}()
+	tlscs := configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CAFile: "./testdata/ca.crt"},
+		ServerName: "localhost",
+	}
+	tls, err := tlscs.LoadTLSConfig()
+	require.NoError(t, err)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tls}}
+	_, err = client.Post(fmt.Sprintf("https://%s", addr), "application/json", bytes.NewReader([]byte("{}")))
+	assert.Error(t, err)
+}
+
 func Test_splunkhecReceiver_AccessTokenPassthrough(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+// HEC metadata fields a resource attribute can be mapped from.
+const (
+	hecFieldHost       = "host"
+	hecFieldSource     = "source"
+	hecFieldSourceType = "sourcetype"
+	hecFieldIndex      = "index"
+)
+
+// ResourceAttributeMapping translates a HEC metadata field of the events into a resource attribute.
+type ResourceAttributeMapping struct {
+	// Field is the HEC metadata field the attribute is mapped from, one of host, source, sourcetype or index.
+	Field string `mapstructure:"field"`
+	// Attribute is the name of the resource attribute, e.g. service.name.
+	Attribute string `mapstructure:"attribute"`
+	// Lookup maps the values of the field to the values of the attribute.
+	Lookup map[string]string `mapstructure:"lookup"`
+	// Regex is matched against the values of the field missing from the lookup table.
+	Regex string `mapstructure:"regex"`
+	// Replacement is the value of the attribute when the regex matches, where $1 and ${name}
+	// are replaced by the submatches. The whole match is used if empty.
+	Replacement string `mapstructure:"replacement"`
+}
+
+func (m *ResourceAttributeMapping) validate() error {
+	switch m.Field {
+	case hecFieldHost, hecFieldSource, hecFieldSourceType, hecFieldIndex:
+	default:
+		return fmt.Errorf("unsupported field %q, must be one of host, source, sourcetype or index", m.Field)
+	}
+	if m.Attribute == "" {
+		return fmt.Errorf("attribute of the %s mapping must not be empty", m.Field)
+	}
+	if len(m.Lookup) == 0 && m.Regex == "" {
+		return fmt.Errorf("the %s mapping to %s requires a lookup table or a regex", m.Field, m.Attribute)
+	}
+	if _, err := regexp.Compile(m.Regex); err != nil {
+		return fmt.Errorf("invalid regex of the %s mapping to %s: %w", m.Field, m.Attribute, err)
+	}
+	return nil
+}
+
+// resourceMapper sets the resource attributes mapped from the HEC metadata of the events.
+type resourceMapper struct {
+	mappings []resourceMapping
+}
+
+type resourceMapping struct {
+	ResourceAttributeMapping
+	regex *regexp.Regexp
+}
+
+// newResourceMapper returns the mapper of the given mappings, nil if there is none.
+func newResourceMapper(mappings []ResourceAttributeMapping) (*resourceMapper, error) {
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+	mapper := &resourceMapper{mappings: make([]resourceMapping, 0, len(mappings))}
+	for _, m := range mappings {
+		mapping := resourceMapping{ResourceAttributeMapping: m}
+		if m.Regex != "" {
+			regex, err := regexp.Compile(m.Regex)
+			if err != nil {
+				return nil, err
+			}
+			mapping.regex = regex
+		}
+		mapper.mappings = append(mapper.mappings, mapping)
+	}
+	return mapper, nil
+}
+
+// mapEvent puts the resource attributes mapped from the metadata of the event into attrs.
+// The mappings are applied in order, so a later mapping of an attribute overrides an earlier one.
+func (m *resourceMapper) mapEvent(event *splunk.Event, attrs pcommon.Map) {
+	if m == nil {
+		return
+	}
+	for _, mapping := range m.mappings {
+		if value, ok := mapping.apply(event); ok {
+			attrs.PutStr(mapping.Attribute, value)
+		}
+	}
+}
+
+func (m *resourceMapping) apply(event *splunk.Event) (string, bool) {
+	var field string
+	switch m.Field {
+	case hecFieldHost:
+		field = event.Host
+	case hecFieldSource:
+		field = event.Source
+	case hecFieldSourceType:
+		field = event.SourceType
+	case hecFieldIndex:
+		field = event.Index
+	}
+	if field == "" {
+		return "", false
+	}
+	if value, ok := m.Lookup[field]; ok {
+		return value, true
+	}
+	if m.regex == nil {
+		return "", false
+	}
+	match := m.regex.FindStringSubmatchIndex(field)
+	if match == nil {
+		return "", false
+	}
+	if m.Replacement == "" {
+		return field[match[0]:match[1]], true
+	}
+	return string(m.regex.ExpandString(nil, m.Replacement, field, match)), true
+}
+
+// resourceKey returns a key identifying the given attributes, to group the events by resource.
+func resourceKey(attrs pcommon.Map) string {
+	var key strings.Builder
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		key.WriteString(k)
+		key.WriteByte(0)
+		key.WriteString(v.Str())
+		key.WriteByte(0)
+		return true
+	})
+	return key.String()
+}
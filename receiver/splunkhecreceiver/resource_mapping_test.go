@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+func TestResourceMapperMapEvent(t *testing.T) {
+	mapper, err := newResourceMapper([]ResourceAttributeMapping{
+		{
+			Field:     "index",
+			Attribute: "deployment.environment",
+			Lookup:    map[string]string{"main_prod": "production"},
+			Regex:     `^main_(\w+)$`,
+		},
+		{
+			Field:       "source",
+			Attribute:   "service.name",
+			Regex:       `^/var/log/(?P<service>\w+)/`,
+			Replacement: "${service}",
+		},
+		{
+			Field:     "sourcetype",
+			Attribute: "service.name",
+			Lookup:    map[string]string{"legacy:checkout": "checkout"},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		event    splunk.Event
+		expected map[string]interface{}
+	}{
+		{
+			name:     "lookup",
+			event:    splunk.Event{Index: "main_prod", Source: "/var/log/cart/out.log"},
+			expected: map[string]interface{}{"deployment.environment": "production", "service.name": "cart"},
+		},
+		{
+			name:     "regex without replacement",
+			event:    splunk.Event{Index: "main_staging"},
+			expected: map[string]interface{}{"deployment.environment": "main_staging"},
+		},
+		{
+			name:     "later mapping overrides",
+			event:    splunk.Event{Source: "/var/log/cart/out.log", SourceType: "legacy:checkout"},
+			expected: map[string]interface{}{"service.name": "checkout"},
+		},
+		{
+			name:     "no match",
+			event:    splunk.Event{Index: "summary", Source: "tcp:9997"},
+			expected: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := pcommon.NewMap()
+			mapper.mapEvent(&tt.event, attrs)
+			assert.Equal(t, tt.expected, attrs.AsRaw())
+		})
+	}
+}
+
+func TestSplunkHecToLogDataResourceMapping(t *testing.T) {
+	mapper, err := newResourceMapper([]ResourceAttributeMapping{
+		{
+			Field:     "index",
+			Attribute: "deployment.environment",
+			Lookup:    map[string]string{"main_prod": "production", "main_dev": "development"},
+		},
+	})
+	require.NoError(t, err)
+
+	events := []*splunk.Event{
+		{Index: "main_prod", Event: "first"},
+		{Index: "main_dev", Event: "second"},
+		{Index: "main_prod", Event: "third"},
+	}
+	ld, err := splunkHecToLogData(zap.NewNop(), events, func(resource pcommon.Resource) {
+		resource.Attributes().PutStr("com.splunk.hec.access_token", "token")
+	}, mapper, defaultTestingHecConfig)
+	require.NoError(t, err)
+
+	// the unmapped resource is dropped since all the events were mapped
+	require.Equal(t, 2, ld.ResourceLogs().Len())
+	prod := ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		"com.splunk.hec.access_token": "token",
+		"deployment.environment":      "production",
+	}, prod.Resource().Attributes().AsRaw())
+	require.Equal(t, 2, prod.ScopeLogs().At(0).LogRecords().Len())
+	assert.Equal(t, "first", prod.ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+	assert.Equal(t, "third", prod.ScopeLogs().At(0).LogRecords().At(1).Body().Str())
+	dev := ld.ResourceLogs().At(1)
+	assert.Equal(t, "development", dev.Resource().Attributes().AsRaw()["deployment.environment"])
+	assert.Equal(t, 1, dev.ScopeLogs().At(0).LogRecords().Len())
+
+	// the events without mapped attributes stay in the default resource
+	ld, err = splunkHecToLogData(zap.NewNop(), []*splunk.Event{{Index: "summary", Event: "fourth"}, events[0]}, nil, mapper, defaultTestingHecConfig)
+	require.NoError(t, err)
+	require.Equal(t, 2, ld.ResourceLogs().Len())
+	assert.Equal(t, 0, ld.ResourceLogs().At(0).Resource().Attributes().Len())
+	assert.Equal(t, "fourth", ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str())
+}
@@ -30,13 +30,26 @@ var (
 )
 
 // splunkHecToLogData transforms splunk events into logs
-func splunkHecToLogData(logger *zap.Logger, events []*splunk.Event, resourceCustomizer func(pcommon.Resource), config *Config) (plog.Logs, error) {
+func splunkHecToLogData(logger *zap.Logger, events []*splunk.Event, resourceCustomizer func(pcommon.Resource), mapper *resourceMapper, config *Config) (plog.Logs, error) {
 	ld := plog.NewLogs()
 	rl := ld.ResourceLogs().AppendEmpty()
 	sl := rl.ScopeLogs().AppendEmpty()
+	// the events whose metadata are mapped to resource attributes are grouped by resource
+	var mapped map[string]plog.ScopeLogs
+	if mapper != nil {
+		mapped = map[string]plog.ScopeLogs{}
+	}
 	for _, event := range events {
+		eventScopeLogs := sl
+		if mapper != nil {
+			attrs := pcommon.NewMap()
+			mapper.mapEvent(event, attrs)
+			if attrs.Len() > 0 {
+				eventScopeLogs = scopeLogsOf(ld, mapped, attrs, resourceCustomizer)
+			}
+		}
 		// The SourceType field is the most logical "name" of the event.
-		logRecord := sl.LogRecords().AppendEmpty()
+		logRecord := eventScopeLogs.LogRecords().AppendEmpty()
 		if err := convertToValue(logger, event.Event, logRecord.Body()); err != nil {
 			return ld, err
 		}
@@ -77,10 +90,36 @@ func splunkHecToLogData(logger *zap.Logger, events []*splunk.Event, resourceCust
 			resourceCustomizer(rl.Resource())
 		}
 	}
+	if len(mapped) > 0 && sl.LogRecords().Len() == 0 {
+		// all the events were moved to the resources of their mapped attributes
+		ld.ResourceLogs().RemoveIf(func(resourceLogs plog.ResourceLogs) bool {
+			return resourceLogs.ScopeLogs().At(0).LogRecords().Len() == 0
+		})
+	}
 
 	return ld, nil
 }
 
+// scopeLogsOf returns the scope logs of the resource with the given mapped attributes, adding it if needed.
+func scopeLogsOf(ld plog.Logs, mapped map[string]plog.ScopeLogs, attrs pcommon.Map, resourceCustomizer func(pcommon.Resource)) plog.ScopeLogs {
+	key := resourceKey(attrs)
+	if sl, ok := mapped[key]; ok {
+		return sl
+	}
+	rl := ld.ResourceLogs().AppendEmpty()
+	if resourceCustomizer != nil {
+		resourceCustomizer(rl.Resource())
+	}
+	// the mapped attributes are added to the ones set by the customizer, CopyTo would replace them
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		v.CopyTo(rl.Resource().Attributes().PutEmpty(k))
+		return true
+	})
+	sl := rl.ScopeLogs().AppendEmpty()
+	mapped[key] = sl
+	return sl
+}
+
 func convertToValue(logger *zap.Logger, src interface{}, dest pcommon.Value) error {
 	switch value := src.(type) {
 	case nil:
@@ -29,7 +29,7 @@ import (
 // splunkHecToMetricsData converts Splunk HEC metric points to
 // pmetric.Metrics. Returning the converted data and the number of
 // dropped time series.
-func splunkHecToMetricsData(logger *zap.Logger, events []*splunk.Event, resourceCustomizer func(pcommon.Resource), config *Config) (pmetric.Metrics, int) {
+func splunkHecToMetricsData(logger *zap.Logger, events []*splunk.Event, resourceCustomizer func(pcommon.Resource), mapper *resourceMapper, config *Config) (pmetric.Metrics, int) {
 	numDroppedTimeSeries := 0
 	md := pmetric.NewMetrics()
 
@@ -51,6 +51,7 @@ func splunkHecToMetricsData(logger *zap.Logger, events []*splunk.Event, resource
 		if event.Index != "" {
 			attrs.PutStr(config.HecToOtelAttrs.Index, event.Index)
 		}
+		mapper.mapEvent(event, attrs)
 
 		values := event.GetMetricValues()
 
@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+const (
+	// keepTimestampAction passes the events with an invalid timestamp through unchanged, it is the default.
+	keepTimestampAction = "keep"
+	// replaceTimestampAction sets the timestamp of the events with an invalid timestamp to the time they are received.
+	replaceTimestampAction = "replace"
+	// dropTimestampAction drops the events with an invalid timestamp.
+	dropTimestampAction = "drop"
+	// annotateTimestampAction keeps the events with an invalid timestamp, adding the issue to their fields.
+	annotateTimestampAction = "annotate"
+
+	// timestampIssueField is the field added to the events with an invalid timestamp by the annotate action.
+	timestampIssueField = "com.splunk.timestamp.issue"
+
+	missingTimestamp = "missing"
+	zeroTimestamp    = "zero"
+	futureTimestamp  = "future"
+	pastTimestamp    = "past"
+)
+
+// TimestampFixupConfig defines how the events with a missing, zero or out of the clock skew window timestamp are handled.
+type TimestampFixupConfig struct {
+	// Action is applied to the events with an invalid timestamp: "keep" passes them through unchanged, "replace" sets their
+	// timestamp to the time they are received, "drop" drops them and "annotate" adds the issue, "missing", "zero", "future"
+	// or "past", to their com.splunk.timestamp.issue field. It defaults to "keep".
+	Action string `mapstructure:"action"`
+	// MaxFutureSkew is how far ahead of the time they are received the timestamps of the events can be, e.g. to
+	// tolerate the clock skew of the clients. 0 accepts any timestamp in the future.
+	MaxFutureSkew time.Duration `mapstructure:"max_future_skew"`
+	// MaxPastSkew is how far behind the time they are received the timestamps of the events can be. 0 accepts any
+	// timestamp in the past.
+	MaxPastSkew time.Duration `mapstructure:"max_past_skew"`
+}
+
+func (cfg *TimestampFixupConfig) validate() error {
+	switch cfg.Action {
+	case "", keepTimestampAction, replaceTimestampAction, dropTimestampAction, annotateTimestampAction:
+	default:
+		return fmt.Errorf("unsupported timestamp_fixup.action %q, must be one of %s, %s, %s or %s",
+			cfg.Action, keepTimestampAction, replaceTimestampAction, dropTimestampAction, annotateTimestampAction)
+	}
+	if cfg.MaxFutureSkew < 0 {
+		return fmt.Errorf("timestamp_fixup.max_future_skew must not be negative")
+	}
+	if cfg.MaxPastSkew < 0 {
+		return fmt.Errorf("timestamp_fixup.max_past_skew must not be negative")
+	}
+	return nil
+}
+
+// timestampIssue returns the issue of the timestamp of the event received at the given time, or an empty string
+// if its timestamp is valid.
+func (cfg *TimestampFixupConfig) timestampIssue(event *splunk.Event, received time.Time) string {
+	if event.Time == nil {
+		return missingTimestamp
+	}
+	if *event.Time == 0 {
+		return zeroTimestamp
+	}
+	// Splunk timestamps are in seconds, with the sub-second precision in the fraction
+	timestamp := time.Unix(0, int64(*event.Time*1e9))
+	if cfg.MaxFutureSkew > 0 && timestamp.After(received.Add(cfg.MaxFutureSkew)) {
+		return futureTimestamp
+	}
+	if cfg.MaxPastSkew > 0 && timestamp.Before(received.Add(-cfg.MaxPastSkew)) {
+		return pastTimestamp
+	}
+	return ""
+}
+
+// fixTimestamps applies the configured action to the events with an invalid timestamp, it returns the events to
+// consume and the number of dropped ones.
+func (cfg *TimestampFixupConfig) fixTimestamps(events []*splunk.Event, received time.Time) ([]*splunk.Event, int) {
+	if cfg.Action == "" || cfg.Action == keepTimestampAction {
+		return events, 0
+	}
+	kept := events[:0]
+	for _, event := range events {
+		issue := cfg.timestampIssue(event, received)
+		switch {
+		case issue == "":
+		case cfg.Action == dropTimestampAction:
+			continue
+		case cfg.Action == replaceTimestampAction:
+			t := float64(received.UnixNano()) / 1e9
+			event.Time = &t
+		case cfg.Action == annotateTimestampAction:
+			if event.Fields == nil {
+				event.Fields = map[string]interface{}{}
+			}
+			event.Fields[timestampIssueField] = issue
+		}
+		kept = append(kept, event)
+	}
+	return kept, len(events) - len(kept)
+}
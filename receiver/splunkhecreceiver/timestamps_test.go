@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/splunk"
+)
+
+func TestFixTimestamps(t *testing.T) {
+	received := time.Unix(1_600_000_000, 0)
+	seconds := func(t time.Time) *float64 {
+		s := float64(t.UnixNano()) / 1e9
+		return &s
+	}
+	newEvents := func() []*splunk.Event {
+		return []*splunk.Event{
+			{Event: "valid", Time: seconds(received.Add(-time.Minute))},
+			{Event: "missing"},
+			{Event: "zero", Time: seconds(time.Unix(0, 0))},
+			{Event: "future", Time: seconds(received.Add(time.Hour))},
+			{Event: "past", Time: seconds(received.Add(-48 * time.Hour))},
+			{Event: "skewed", Time: seconds(received.Add(time.Second))},
+		}
+	}
+
+	tests := []struct {
+		action   string
+		expected func(t *testing.T, events []*splunk.Event)
+		dropped  int
+	}{
+		{
+			action: keepTimestampAction,
+			expected: func(t *testing.T, events []*splunk.Event) {
+				assert.Equal(t, newEvents(), events)
+			},
+		},
+		{
+			action:  dropTimestampAction,
+			dropped: 4,
+			expected: func(t *testing.T, events []*splunk.Event) {
+				require.Len(t, events, 2)
+				assert.Equal(t, "valid", events[0].Event)
+				assert.Equal(t, "skewed", events[1].Event)
+			},
+		},
+		{
+			action: replaceTimestampAction,
+			expected: func(t *testing.T, events []*splunk.Event) {
+				require.Len(t, events, 6)
+				assert.Equal(t, seconds(received.Add(-time.Minute)), events[0].Time)
+				for _, event := range events[1:5] {
+					assert.Equal(t, seconds(received), event.Time, event.Event)
+				}
+				assert.Equal(t, seconds(received.Add(time.Second)), events[5].Time)
+			},
+		},
+		{
+			action: annotateTimestampAction,
+			expected: func(t *testing.T, events []*splunk.Event) {
+				require.Len(t, events, 6)
+				issues := map[interface{}]interface{}{}
+				for _, event := range events {
+					issues[event.Event] = event.Fields[timestampIssueField]
+				}
+				assert.Equal(t, map[interface{}]interface{}{
+					"valid":   nil,
+					"missing": missingTimestamp,
+					"zero":    zeroTimestamp,
+					"future":  futureTimestamp,
+					"past":    pastTimestamp,
+					"skewed":  nil,
+				}, issues)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			cfg := TimestampFixupConfig{Action: tt.action, MaxFutureSkew: time.Minute, MaxPastSkew: 24 * time.Hour}
+			events, dropped := cfg.fixTimestamps(newEvents(), received)
+			assert.Equal(t, tt.dropped, dropped)
+			tt.expected(t, events)
+		})
+	}
+}
+
+func TestTimestampIssueWithoutSkewWindow(t *testing.T) {
+	cfg := TimestampFixupConfig{Action: dropTimestampAction}
+	future := float64(time.Now().Add(24 * time.Hour).Unix())
+	past := float64(1)
+	assert.Equal(t, "", cfg.timestampIssue(&splunk.Event{Time: &future}, time.Now()))
+	assert.Equal(t, "", cfg.timestampIssue(&splunk.Event{Time: &past}, time.Now()))
+}
+
+func Test_splunkhecReceiver_TimestampFixup(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.TimestampFixup = TimestampFixupConfig{Action: dropTimestampAction, MaxFutureSkew: time.Minute}
+
+	sink := new(consumertest.LogsSink)
+	rcv, err := newLogsReceiver(componenttest.NewNopReceiverCreateSettings(), *config, sink)
+	require.NoError(t, err)
+	r := rcv.(*splunkReceiver)
+
+	valid, err := json.Marshal(buildSplunkHecMsg(float64(time.Now().Unix()), 1))
+	require.NoError(t, err)
+	future, err := json.Marshal(buildSplunkHecMsg(float64(time.Now().Add(time.Hour).Unix()), 1))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost", bytes.NewReader(append(valid, future...))))
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	assert.Equal(t, 1, sink.LogRecordCount())
+
+	// a request whose events are all dropped is accepted without consuming them
+	w = httptest.NewRecorder()
+	r.handleReq(w, httptest.NewRequest("POST", "http://localhost", bytes.NewReader(future)))
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	assert.Len(t, sink.AllLogs(), 1)
+}